@@ -3,11 +3,18 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"log"
+	"net"
 	"net/url"
+	"os"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/blang/semver"
@@ -37,11 +44,96 @@ const (
 	featurePublishViaRoot
 	featurePubTruncate
 	featurePublication
+	featureSubscription
 	featurePubWithoutTruncate
 	featureFunction
 	featureServer
+	featureMaterializedView
+	featureRefreshMaterializedViewConcurrently
+	featureIndexInclude
+	featureIndexNullsNotDistinct
+	featureDropIndexConcurrently
+	featureSequenceDataType
+	featureEnumAddValueInTransaction
+	featurePublicationTableFilters
+	featureDeclarativePartitioning
+	featureDefaultPartition
+	featureDetachPartitionConcurrently
+	featurePublicationSchemas
+	featureSubscriptionStreaming
+	featureSubscriptionParallelStreaming
+	featureSubscriptionTwoPhase
+	featureSubscriptionOrigin
+	featureReplicationSlot
+	featureReplicationSlotTwoPhase
+	featureReplicationSlotFailover
+	featureWALFunctions
+	featureCollationProvider
+	featureCollationDeterministic
+	featureCollationICULocale
+	featureCollationLocale
+	featureCollationRules
+	featureStatistics
+	featureStatisticsMCV
+	featureStatisticsTarget
+	featureStatisticsExpressions
+	featureAlterSystem
+	featurePasswordEncryption
+	featureBlockingPids
+	featureIdentityColumns
+	featureGeneratedColumns
+	featureBackendType
+	featureParameterPrivileges
 )
 
+// serverFlavor is the kind of server the provider is connected to, the wire-compatible servers
+// reporting a Postgres version but not supporting all of its features.
+type serverFlavor string
+
+const (
+	flavorPostgreSQL  serverFlavor = "postgresql"
+	flavorAurora      serverFlavor = "aurora"
+	flavorCockroachDB serverFlavor = "cockroachdb"
+	flavorRedshift    serverFlavor = "redshift"
+)
+
+// flavorNames are the names of the flavors in the error messages.
+var flavorNames = map[serverFlavor]string{
+	flavorPostgreSQL:  "PostgreSQL",
+	flavorAurora:      "Aurora PostgreSQL",
+	flavorCockroachDB: "CockroachDB",
+	flavorRedshift:    "Redshift",
+}
+
+// flavorUnsupportedFeatures are the features a flavor does not support whatever the Postgres version it reports.
+var flavorUnsupportedFeatures = map[serverFlavor][]featureName{
+	// Aurora does not allow to change the configuration files, the parameters are set in parameter groups
+	flavorAurora: {
+		featureAlterSystem,
+	},
+	flavorCockroachDB: {
+		featureRLS,
+		featurePublication,
+		featureSubscription,
+		featureReplicationSlot,
+		featureReplicationSlotTwoPhase,
+		featureReplicationSlotFailover,
+		featureWALFunctions,
+		featureAlterSystem,
+		featureStatistics,
+		featureServer,
+		featureBlockingPids,
+		featureForceDropDatabase,
+		featureDeclarativePartitioning,
+		featureDefaultPartition,
+		featureDetachPartitionConcurrently,
+		featureParameterPrivileges,
+	},
+}
+
+// defaultConnMaxIdleTime is how long a pooled connection can stay idle before being closed
+const defaultConnMaxIdleTime = 5 * time.Minute
+
 var (
 	dbRegistryLock sync.Mutex
 	dbRegistry     map[string]*DBConnection = make(map[string]*DBConnection, 1)
@@ -105,10 +197,182 @@ var (
 		// publication is Supported
 		featurePublication: semver.MustParseRange(">=10.0.0"),
 
+		// CREATE SUBSCRIPTION support (logical replication)
+		featureSubscription: semver.MustParseRange(">=10.0.0"),
+
 		// We do not support CREATE FUNCTION for Postgresql < 8.4
 		featureFunction: semver.MustParseRange(">=8.4.0"),
 		// CREATE SERVER support
 		featureServer: semver.MustParseRange(">=10.0.0"),
+
+		// CREATE MATERIALIZED VIEW support
+		featureMaterializedView: semver.MustParseRange(">=9.3.0"),
+
+		// REFRESH MATERIALIZED VIEW CONCURRENTLY support
+		featureRefreshMaterializedViewConcurrently: semver.MustParseRange(">=9.4.0"),
+
+		// CREATE INDEX ... INCLUDE support
+		featureIndexInclude: semver.MustParseRange(">=11.0.0"),
+
+		// CREATE UNIQUE INDEX ... NULLS NOT DISTINCT support
+		featureIndexNullsNotDistinct: semver.MustParseRange(">=15.0.0"),
+
+		// DROP INDEX CONCURRENTLY support
+		featureDropIndexConcurrently: semver.MustParseRange(">=9.2.0"),
+
+		// CREATE SEQUENCE ... AS data_type and pg_sequences view support
+		featureSequenceDataType: semver.MustParseRange(">=10.0.0"),
+
+		// ALTER TYPE ... ADD VALUE can be executed inside a transaction block
+		featureEnumAddValueInTransaction: semver.MustParseRange(">=12.0.0"),
+
+		// Publication column lists and row filters (WHERE) support
+		featurePublicationTableFilters: semver.MustParseRange(">=15.0.0"),
+
+		// CREATE TABLE ... PARTITION OF / ATTACH PARTITION support
+		featureDeclarativePartitioning: semver.MustParseRange(">=10.0.0"),
+
+		// DEFAULT partitions support
+		featureDefaultPartition: semver.MustParseRange(">=11.0.0"),
+
+		// ALTER TABLE ... DETACH PARTITION ... CONCURRENTLY support
+		featureDetachPartitionConcurrently: semver.MustParseRange(">=14.0.0"),
+
+		// CREATE/ALTER PUBLICATION ... TABLES IN SCHEMA support
+		featurePublicationSchemas: semver.MustParseRange(">=15.0.0"),
+
+		// CREATE/ALTER SUBSCRIPTION ... WITH (streaming) support
+		featureSubscriptionStreaming: semver.MustParseRange(">=14.0.0"),
+
+		// CREATE/ALTER SUBSCRIPTION ... WITH (streaming = parallel) support
+		featureSubscriptionParallelStreaming: semver.MustParseRange(">=16.0.0"),
+
+		// CREATE SUBSCRIPTION ... WITH (two_phase) support
+		featureSubscriptionTwoPhase: semver.MustParseRange(">=15.0.0"),
+
+		// CREATE/ALTER SUBSCRIPTION ... WITH (origin) support
+		featureSubscriptionOrigin: semver.MustParseRange(">=16.0.0"),
+
+		// pg_create_logical_replication_slot and pg_create_physical_replication_slot support
+		featureReplicationSlot: semver.MustParseRange(">=9.4.0"),
+
+		// pg_create_logical_replication_slot(..., twophase) support
+		featureReplicationSlotTwoPhase: semver.MustParseRange(">=14.0.0"),
+
+		// pg_create_logical_replication_slot(..., failover) support
+		featureReplicationSlotFailover: semver.MustParseRange(">=17.0.0"),
+
+		// pg_current_wal_lsn/pg_wal_lsn_diff, previously named pg_current_xlog_location/pg_xlog_location_diff
+		featureWALFunctions: semver.MustParseRange(">=10.0.0"),
+
+		// CREATE COLLATION has PROVIDER support (ICU collations), with collversion and pg_collation_actual_version
+		featureCollationProvider: semver.MustParseRange(">=10.0.0"),
+
+		// Nondeterministic collations
+		featureCollationDeterministic: semver.MustParseRange(">=12.0.0"),
+
+		// The ICU locale is stored in pg_collation.colliculocale
+		featureCollationICULocale: semver.MustParseRange(">=15.0.0"),
+
+		// pg_collation.colliculocale has been renamed colllocale
+		featureCollationLocale: semver.MustParseRange(">=17.0.0"),
+
+		// Custom ICU collation rules
+		featureCollationRules: semver.MustParseRange(">=16.0.0"),
+
+		// CREATE STATISTICS support (extended statistics)
+		featureStatistics: semver.MustParseRange(">=10.0.0"),
+
+		// Most common values lists in extended statistics
+		featureStatisticsMCV: semver.MustParseRange(">=12.0.0"),
+
+		// ALTER STATISTICS ... SET STATISTICS support
+		featureStatisticsTarget: semver.MustParseRange(">=13.0.0"),
+
+		// Extended statistics on expressions
+		featureStatisticsExpressions: semver.MustParseRange(">=14.0.0"),
+
+		// ALTER SYSTEM support, with pg_file_settings and pg_settings.pending_restart
+		featureAlterSystem: semver.MustParseRange(">=9.5.0"),
+
+		// password_encryption accepts md5 and scram-sha-256 (it was a boolean before)
+		featurePasswordEncryption: semver.MustParseRange(">=10.0.0"),
+
+		// pg_blocking_pids support
+		featureBlockingPids: semver.MustParseRange(">=9.6.0"),
+
+		// GENERATED ... AS IDENTITY columns
+		featureIdentityColumns: semver.MustParseRange(">=10.0.0"),
+
+		// GENERATED ALWAYS AS (...) STORED columns
+		featureGeneratedColumns: semver.MustParseRange(">=12.0.0"),
+
+		// pg_stat_activity.backend_type
+		featureBackendType: semver.MustParseRange(">=10.0.0"),
+
+		// GRANT SET, ALTER SYSTEM ON PARAMETER support, with pg_parameter_acl
+		featureParameterPrivileges: semver.MustParseRange(">=15.0.0"),
+	}
+
+	// featureNames are the names of the feature flags exposed by the postgresql_server_version data source
+	featureNames = map[featureName]string{
+		featureCreateRoleWith:                      "create_role_with",
+		featureDBAllowConnections:                  "db_allow_connections",
+		featureDBIsTemplate:                        "db_is_template",
+		featureFallbackApplicationName:             "fallback_application_name",
+		featureRLS:                                 "rls",
+		featureSchemaCreateIfNotExist:              "schema_create_if_not_exist",
+		featureReplication:                         "replication",
+		featureExtension:                           "extension",
+		featurePrivileges:                          "privileges",
+		featureProcedure:                           "procedure",
+		featureRoutine:                             "routine",
+		featurePrivilegesOnSchemas:                 "privileges_on_schemas",
+		featureForceDropDatabase:                   "force_drop_database",
+		featurePid:                                 "pid",
+		featurePublishViaRoot:                      "publish_via_root",
+		featurePubTruncate:                         "pub_truncate",
+		featurePublication:                         "publication",
+		featureSubscription:                        "subscription",
+		featurePubWithoutTruncate:                  "pub_without_truncate",
+		featureFunction:                            "function",
+		featureServer:                              "server",
+		featureMaterializedView:                    "materialized_view",
+		featureRefreshMaterializedViewConcurrently: "refresh_materialized_view_concurrently",
+		featureIndexInclude:                        "index_include",
+		featureIndexNullsNotDistinct:               "index_nulls_not_distinct",
+		featureDropIndexConcurrently:               "drop_index_concurrently",
+		featureSequenceDataType:                    "sequence_data_type",
+		featureEnumAddValueInTransaction:           "enum_add_value_in_transaction",
+		featurePublicationTableFilters:             "publication_table_filters",
+		featureDeclarativePartitioning:             "declarative_partitioning",
+		featureDefaultPartition:                    "default_partition",
+		featureDetachPartitionConcurrently:         "detach_partition_concurrently",
+		featurePublicationSchemas:                  "publication_schemas",
+		featureSubscriptionStreaming:               "subscription_streaming",
+		featureSubscriptionParallelStreaming:       "subscription_parallel_streaming",
+		featureSubscriptionTwoPhase:                "subscription_two_phase",
+		featureSubscriptionOrigin:                  "subscription_origin",
+		featureReplicationSlot:                     "replication_slot",
+		featureReplicationSlotTwoPhase:             "replication_slot_two_phase",
+		featureReplicationSlotFailover:             "replication_slot_failover",
+		featureWALFunctions:                        "wal_functions",
+		featureCollationProvider:                   "collation_provider",
+		featureCollationDeterministic:              "collation_deterministic",
+		featureCollationICULocale:                  "collation_icu_locale",
+		featureCollationLocale:                     "collation_locale",
+		featureCollationRules:                      "collation_rules",
+		featureStatistics:                          "statistics",
+		featureStatisticsMCV:                       "statistics_mcv",
+		featureStatisticsTarget:                    "statistics_target",
+		featureStatisticsExpressions:               "statistics_expressions",
+		featureAlterSystem:                         "alter_system",
+		featurePasswordEncryption:                  "password_encryption",
+		featureBlockingPids:                        "blocking_pids",
+		featureIdentityColumns:                     "identity_columns",
+		featureGeneratedColumns:                    "generated_columns",
+		featureBackendType:                         "backend_type",
+		featureParameterPrivileges:                 "parameter_privileges",
 	}
 )
 
@@ -120,6 +384,9 @@ type DBConnection struct {
 	// version is the version number of the database as determined by parsing the
 	// output of `SELECT VERSION()`.x
 	version semver.Version
+
+	// serverFlavor is the kind of server, detected with the version.
+	serverFlavor serverFlavor
 }
 
 // featureSupported returns true if a given feature is supported or not. This is
@@ -132,9 +399,68 @@ func (db *DBConnection) featureSupported(name featureName) bool {
 		panic(fmt.Sprintf("unknown feature flag %v", name))
 	}
 
+	for _, unsupported := range flavorUnsupportedFeatures[db.serverFlavor] {
+		if unsupported == name {
+			return false
+		}
+	}
+
 	return fn(db.version)
 }
 
+// unsupportedServer describes the server in the errors of the unsupported features: its version
+// for PostgreSQL, or its flavor for the servers which are only wire-compatible.
+func (db *DBConnection) unsupportedServer() string {
+	name, ok := flavorNames[db.serverFlavor]
+	if !ok || db.serverFlavor == flavorPostgreSQL {
+		return fmt.Sprintf("for this Postgres version (%s)", db.version)
+	}
+	return fmt.Sprintf("on %s (Postgres %s compatible)", name, db.version)
+}
+
+// Exec, Query and QueryRow run the statements with the operation context so they are
+// cancelled once the deadline of the resource timeouts has been exceeded. A query failing
+// because its connection was broken is retried once on a working connection, a statement
+// only if it has not been run as it may not be idempotent.
+func (db *DBConnection) Exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx := db.client.context()
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	if isStatementNotRunError(err) {
+		if retry, reconnectErr := db.reconnect(err); reconnectErr == nil {
+			result, err = retry.DB.ExecContext(ctx, query, args...)
+		}
+	}
+	return result, cancelledStatementError(ctx, query, err)
+}
+
+func (db *DBConnection) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	ctx := db.client.context()
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if isBadConnectionError(err) {
+		if retry, reconnectErr := db.reconnect(err); reconnectErr == nil {
+			rows, err = retry.DB.QueryContext(ctx, query, args...)
+		}
+	}
+	return rows, cancelledStatementError(ctx, query, err)
+}
+
+func (db *DBConnection) QueryRow(query string, args ...interface{}) *sql.Row {
+	ctx := db.client.context()
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); isBadConnectionError(err) {
+		if retry, reconnectErr := db.reconnect(err); reconnectErr == nil {
+			row = retry.DB.QueryRowContext(ctx, query, args...)
+		}
+	}
+	return row
+}
+
+// withoutDeadline returns a copy of the connection whose statements are not bound to the operation
+// context, to clean up what the operation changed (e.g.: a temporary role membership) once it timed out.
+func (db *DBConnection) withoutDeadline() *DBConnection {
+	return &DBConnection{db.DB, db.client.withContext(context.Background()), db.version, db.serverFlavor}
+}
+
 // isSuperuser returns true if connected user is a Postgres SUPERUSER
 func (db *DBConnection) isSuperuser() (bool, error) {
 	var superuser bool
@@ -168,6 +494,32 @@ type Config struct {
 	ExpectedVersion   semver.Version
 	SSLClientCert     *ClientCertificateConfig
 	SSLRootCertPath   string
+	SSLRootCertPool   string
+	KrbSrvname        string
+	KrbSpn            string
+	Options           string
+	IsolationLevel    string
+
+	// Hosts are the host or host:port of the servers of a multi-host connection string, tried in
+	// order until one matches TargetSessionAttrs. Host is used when they are not set.
+	Hosts              []string
+	TargetSessionAttrs string
+
+	// LogStatements logs every executed statement at debug level through logContext
+	LogStatements bool
+	logContext    context.Context
+
+	// PreferSimpleProtocol interpolates the parameters of the queries to run them with the simple
+	// query protocol, for the poolers and proxies not supporting the extended protocol
+	PreferSimpleProtocol bool
+
+	// StrictCatalogReads fails the reads of the roles, grants and comments when the connected role
+	// cannot read a system catalog, instead of keeping their state with a warning
+	StrictCatalogReads bool
+
+	// operationContext is the context of the resource operation using the configuration.
+	// Its deadline, set from the resource timeouts, bounds the executed statements.
+	operationContext context.Context
 }
 
 // Client struct holding connection string
@@ -176,6 +528,10 @@ type Client struct {
 	config Config
 
 	databaseName string
+
+	// resourceType is the type of the resource or data source the client is used for,
+	// logged with the statements
+	resourceType string
 }
 
 // NewClient returns client config for the specified database.
@@ -186,6 +542,30 @@ func (c *Config) NewClient(database string) *Client {
 	}
 }
 
+// withDatabase returns a client for the specified database with the configuration of the client,
+// its statements being logged with the same resource type.
+func (c *Client) withDatabase(database string) *Client {
+	client := c.config.NewClient(database)
+	client.resourceType = c.resourceType
+	return client
+}
+
+// withContext returns a copy of the client whose connections and transactions
+// are bound to the given context.
+func (c *Client) withContext(ctx context.Context) *Client {
+	client := *c
+	client.config.operationContext = ctx
+	return &client
+}
+
+// context returns the context of the operation the client is used for.
+func (c *Client) context() context.Context {
+	if c.config.operationContext == nil {
+		return context.Background()
+	}
+	return c.config.operationContext
+}
+
 // featureSupported returns true if a given feature is supported or not.  This
 // is slightly different from Client's featureSupported in that here we're
 // evaluating against the expected version, not the fingerprinted version.
@@ -202,11 +582,15 @@ func (c *Config) featureSupported(name featureName) bool {
 func (c *Config) connParams() []string {
 	params := map[string]string{}
 
-	// sslmode and connect_timeout are not allowed with gocloud
+	// sslmode, connect_timeout and target_session_attrs are not allowed with gocloud
 	// (TLS is provided by gocloud directly)
 	if c.Scheme == "postgres" {
 		params["sslmode"] = c.SSLMode
 		params["connect_timeout"] = strconv.Itoa(c.ConnectTimeoutSec)
+		// Handled by the provider driver as lib/pq does not support it
+		if c.TargetSessionAttrs != "" {
+			params["target_session_attrs"] = c.TargetSessionAttrs
+		}
 	}
 
 	if c.featureSupported(featureFallbackApplicationName) {
@@ -220,31 +604,68 @@ func (c *Config) connParams() []string {
 	if c.SSLRootCertPath != "" {
 		params["sslrootcert"] = c.SSLRootCertPath
 	}
+	if c.SSLRootCertPool != "" {
+		params[sslRootCertPoolParam] = c.SSLRootCertPool
+	}
+
+	if c.KrbSrvname != "" {
+		params["krbsrvname"] = c.KrbSrvname
+	}
+	if c.KrbSpn != "" {
+		params["krbspn"] = c.KrbSpn
+	}
+	if c.Options != "" {
+		params["options"] = c.Options
+	}
 
 	paramsArray := []string{}
 	for key, value := range params {
 		paramsArray = append(paramsArray, fmt.Sprintf("%s=%s", key, url.QueryEscape(value)))
 	}
+	// The connection string is the key of the connection pools, it has to be stable
+	sort.Strings(paramsArray)
 
 	return paramsArray
 }
 
+// address returns the host:port of the server, or the comma separated host:port of the servers
+// of a multi-host connection string (the hosts without port use the port of the configuration).
+func (c *Config) address() string {
+	hosts := c.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{c.Host}
+		// As with libpq, PGHOST can be a comma separated list of hosts
+		if c.Scheme == "postgres" {
+			hosts = strings.Split(c.Host, ",")
+		}
+	}
+
+	addresses := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if _, _, err := net.SplitHostPort(host); err == nil {
+			addresses = append(addresses, host)
+			continue
+		}
+		addresses = append(addresses, fmt.Sprintf("%s:%d", host, c.Port))
+	}
+	return strings.Join(addresses, ",")
+}
+
 func (c *Config) connStr(database string) string {
-	host := c.Host
+	address := c.address()
 
 	// For GCP, support both project/region/instance and project:region:instance
 	// (The second one allows to use the output of google_sql_database_instance as host
 	if c.Scheme == "gcppostgres" {
-		host = strings.ReplaceAll(host, ":", "/")
+		address = fmt.Sprintf("%s:%d", strings.ReplaceAll(c.Host, ":", "/"), c.Port)
 	}
 
 	connStr := fmt.Sprintf(
-		"%s://%s:%s@%s:%d/%s?%s",
+		"%s://%s:%s@%s/%s?%s",
 		c.Scheme,
 		url.QueryEscape(c.Username),
 		url.QueryEscape(c.Password),
-		host,
-		c.Port,
+		address,
 		database,
 		strings.Join(c.connParams(), "&"),
 	)
@@ -272,14 +693,23 @@ func (c *Client) Connect() (*DBConnection, error) {
 
 		var db *sql.DB
 		var err error
-		if c.config.Scheme == "postgres" {
-			db, err = sql.Open(proxyDriverName, dsn)
+		var pgDriver driver.Driver = proxyDriver{}
+		driverName := proxyDriverName
+		if c.config.PreferSimpleProtocol {
+			pgDriver = simpleProtocolDriver{pgDriver}
+			driverName = simpleProtocolDriverName
+		}
+
+		if c.config.Scheme == "postgres" && c.config.LogStatements {
+			db = sql.OpenDB(loggingConnector{dsn: dsn, database: c.databaseName, driver: pgDriver, ctx: c.config.logContext})
+		} else if c.config.Scheme == "postgres" {
+			db, err = sql.Open(driverName, dsn)
 		} else {
 			db, err = postgres.Open(context.Background(), dsn)
 		}
 		if err != nil {
 			errString := strings.Replace(err.Error(), c.config.Password, "XXXX", 2)
-			return nil, fmt.Errorf("Error connecting to PostgreSQL server %s (scheme: %s): %s", c.config.Host, c.config.Scheme, errString)
+			return nil, fmt.Errorf("Error connecting to PostgreSQL server %s (scheme: %s): %s", c.config.address(), c.config.Scheme, errString)
 		}
 
 		// We don't want to retain connection
@@ -287,12 +717,16 @@ func (c *Client) Connect() (*DBConnection, error) {
 		// we don't keep opened connection in case of the db has to be dopped in the plan.
 		db.SetMaxIdleConns(0)
 		db.SetMaxOpenConns(c.config.MaxConns)
+		// The connections idle for a while may have been closed by the server or a proxy,
+		// they are closed before being reused if idle connections are ever retained.
+		db.SetConnMaxIdleTime(defaultConnMaxIdleTime)
 
 		defaultVersion, _ := semver.Parse(defaultExpectedPostgreSQLVersion)
 		version := &c.config.ExpectedVersion
+		flavor := flavorPostgreSQL
 		if defaultVersion.Equals(c.config.ExpectedVersion) {
 			// Version hint not set by user, need to fingerprint
-			version, err = fingerprintCapabilities(db)
+			flavor, version, err = fingerprintCapabilities(db)
 			if err != nil {
 				db.Close()
 				return nil, fmt.Errorf("error detecting capabilities: %w", err)
@@ -303,35 +737,205 @@ func (c *Client) Connect() (*DBConnection, error) {
 			db,
 			c,
 			*version,
+			flavor,
 		}
 		dbRegistry[dsn] = conn
 	}
 
-	return conn, nil
+	// The *sql.DB is shared by every client using the same DSN but the returned
+	// connection is bound to this client, and so to its operation context.
+	return &DBConnection{conn.DB, c, conn.version, conn.serverFlavor}, nil
+}
+
+// evict removes the pool of the client from the connection cache and closes it, the next
+// Connect dialing a new pool. The connections in use are closed once they are released.
+func (c *Client) evict(db *sql.DB) {
+	dbRegistryLock.Lock()
+	defer dbRegistryLock.Unlock()
+
+	dsn := c.config.connStr(c.databaseName)
+	if conn, found := dbRegistry[dsn]; found && conn.DB == db {
+		delete(dbRegistry, dsn)
+	}
+	db.Close()
+}
+
+// reconnect returns a connection to retry a statement which failed because its connection
+// was broken (e.g.: the backend was terminated or the server failed over). The pool is kept
+// if it can still reach the server, it is evicted and dialed again otherwise.
+func (db *DBConnection) reconnect(cause error) (*DBConnection, error) {
+	log.Printf("[WARN] connection to database %s lost, retrying once: %v", db.client.databaseName, cause)
+	if err := db.DB.PingContext(db.client.context()); err == nil {
+		return db, nil
+	}
+
+	db.client.evict(db.DB)
+	return db.client.Connect()
+}
+
+// waitForReady tries to connect to the database until the server accepts
+// connections or the timeout expires. A zero timeout means to wait for defaultReadyTimeout.
+func (c *Client) waitForReady(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultReadyTimeout
+	}
+	return retryUntilReady(timeout, defaultReadyRetryInterval, func() error {
+		db, err := c.Connect()
+		if err != nil {
+			return err
+		}
+		return db.Ping()
+	})
+}
+
+// retryUntilReady calls ping every interval until it succeeds or the timeout
+// expires, in which case the last error is returned. The errors which will not
+// go away by waiting (e.g.: a wrong password) are returned at once.
+func retryUntilReady(timeout, interval time.Duration, ping func() error) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		err := ping()
+		if err == nil {
+			return nil
+		}
+
+		if isPermanentConnectionError(err) {
+			return err
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("PostgreSQL server is still not ready after %s: %w", timeout, err)
+		}
+
+		log.Printf("[DEBUG] PostgreSQL server is not ready yet, retrying in %s: %v", interval, err)
+		time.Sleep(interval)
+	}
+}
+
+// kerberosCredentialCachePath returns the path of the Kerberos credential cache
+// as resolved by the Kerberos libraries (KRB5CCNAME or the default file cache).
+// It returns an empty string if the cache is not stored in a file (e.g. KEYRING or KCM).
+func kerberosCredentialCachePath() string {
+	ccName := os.Getenv("KRB5CCNAME")
+	if ccName == "" {
+		return fmt.Sprintf("/tmp/krb5cc_%d", os.Getuid())
+	}
+
+	if strings.HasPrefix(ccName, "FILE:") {
+		return strings.TrimPrefix(ccName, "FILE:")
+	}
+
+	if strings.Contains(ccName, ":") {
+		return ""
+	}
+
+	return ccName
+}
+
+// checkKerberosCredentialCache ensures a Kerberos credential cache is available
+// before trying to authenticate with GSSAPI, as the driver error is not explicit.
+func checkKerberosCredentialCache() error {
+	// Windows uses the LSA credential cache, which cannot be checked here.
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	path := kerberosCredentialCachePath()
+	if path == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("GSSAPI authentication is enabled but no Kerberos credential cache is available (%s), run kinit or set KRB5CCNAME: %w", path, err)
+	}
+
+	return nil
 }
 
 // fingerprintCapabilities queries PostgreSQL to populate a local catalog of
 // capabilities.  This is only run once per Client.
-func fingerprintCapabilities(db *sql.DB) (*semver.Version, error) {
+func fingerprintCapabilities(db *sql.DB) (serverFlavor, *semver.Version, error) {
 	var pgVersion string
 	err := db.QueryRow(`SELECT VERSION()`).Scan(&pgVersion)
 	if err != nil {
-		return nil, fmt.Errorf("error PostgreSQL version: %w", err)
+		return "", nil, fmt.Errorf("error PostgreSQL version: %w", err)
 	}
 
-	// PostgreSQL 9.2.21 on x86_64-apple-darwin16.5.0, compiled by Apple LLVM version 8.1.0 (clang-802.0.42), 64-bit
-	// PostgreSQL 9.6.7, compiled by Visual C++ build 1800, 64-bit
+	flavor, version, err := parseVersionString(pgVersion)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch flavor {
+	case flavorCockroachDB:
+		// The features depend on the Postgres version CockroachDB is compatible with, not on its own version
+		var serverVersion string
+		if err := db.QueryRow(`SELECT current_setting('server_version')`).Scan(&serverVersion); err != nil {
+			return "", nil, fmt.Errorf("error reading the Postgres version of CockroachDB: %w", err)
+		}
+		if version, err = parsePostgresVersion(serverVersion); err != nil {
+			return "", nil, err
+		}
+	case flavorPostgreSQL:
+		// Aurora reports the same version string as PostgreSQL but has its own version function
+		var aurora bool
+		if err := db.QueryRow(
+			`SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_proc WHERE proname = 'aurora_version')`,
+		).Scan(&aurora); err != nil {
+			return "", nil, fmt.Errorf("error detecting Aurora: %w", err)
+		}
+		if aurora {
+			flavor = flavorAurora
+		}
+	}
+
+	log.Printf("[DEBUG] Detected server %s version %s from %q", flavor, version, pgVersion)
+	return flavor, &version, nil
+}
+
+// parseVersionString returns the flavor and the version of the server from the output of version(), e.g.:
+//
+//	PostgreSQL 9.2.21 on x86_64-apple-darwin16.5.0, compiled by Apple LLVM version 8.1.0 (clang-802.0.42), 64-bit
+//	PostgreSQL 9.6.7, compiled by Visual C++ build 1800, 64-bit
+//	PostgreSQL 8.0.2 on i686-pc-linux-gnu, compiled by GCC gcc (GCC) 3.4.2 20041017 (Red Hat 3.4.2-6.fc3), Redshift 1.0.12103
+//	CockroachDB CCL v23.1.11 (x86_64-pc-linux-gnu, built 2023/09/27 01:53:43, go1.19.10)
+//
+// The version of CockroachDB is its own version, not the Postgres version it is compatible with.
+func parseVersionString(pgVersion string) (serverFlavor, semver.Version, error) {
 	fields := strings.FieldsFunc(pgVersion, func(c rune) bool {
 		return unicode.IsSpace(c) || c == ','
 	})
-	if len(fields) < 2 {
-		return nil, fmt.Errorf("error determining the server version: %q", pgVersion)
+
+	switch {
+	case len(fields) >= 3 && fields[0] == "CockroachDB":
+		version, err := parsePostgresVersion(strings.TrimPrefix(fields[2], "v"))
+		return flavorCockroachDB, version, err
+	case len(fields) >= 2:
+		// The forks of PostgreSQL keep its version string
+		version, err := parsePostgresVersion(fields[1])
+		if strings.Contains(pgVersion, "Redshift") {
+			return flavorRedshift, version, err
+		}
+		return flavorPostgreSQL, version, err
 	}
 
-	version, err := semver.ParseTolerant(fields[1])
-	if err != nil {
-		return nil, fmt.Errorf("error parsing version: %w", err)
+	return "", semver.Version{}, fmt.Errorf("error determining the server version: %q", pgVersion)
+}
+
+// parsePostgresVersion parses a version like 16.2, 9.6.7, 17beta1 or 15.5 (Debian 15.5-1.pgdg120+1),
+// ignoring what follows the version numbers.
+func parsePostgresVersion(version string) (semver.Version, error) {
+	end := strings.IndexFunc(version, func(c rune) bool {
+		return !unicode.IsDigit(c) && c != '.'
+	})
+	if end >= 0 {
+		version = version[:end]
 	}
 
-	return &version, nil
+	parsed, err := semver.ParseTolerant(strings.TrimSuffix(version, "."))
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("error parsing version: %w", err)
+	}
+	return parsed, nil
 }
@@ -3,8 +3,6 @@ package postgresql
 import (
 	"fmt"
 	"strings"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -17,7 +15,12 @@ const (
 	regexPatternQuery       = "~"
 )
 
-func applyPatternMatchingToQuery(patternMatchingTarget string, d *schema.ResourceData) []string {
+// attributeGetter reads the attributes of a schema.ResourceData or of a schema.ResourceDiff.
+type attributeGetter interface {
+	Get(key string) interface{}
+}
+
+func applyPatternMatchingToQuery(patternMatchingTarget string, d attributeGetter) []string {
 	likeAnyPatterns := d.Get("like_any_patterns").([]interface{})
 	likeAllPatterns := d.Get("like_all_patterns").([]interface{})
 	notLikeAllPatterns := d.Get("not_like_all_patterns").([]interface{})
@@ -0,0 +1,158 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePostgreSQLActiveConnections() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLActiveConnectionsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the connections to this database",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the connections of this user",
+			},
+			"include_query": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to return the last query of the connections, which may contain sensitive data",
+			},
+			"connections": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"pid": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"database": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"application_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"client_addr": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"query_start": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"query": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+					},
+				},
+				Description: "The client connections retrieved by this data source, ordered by pid",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLActiveConnectionsRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featurePid) {
+		return fmt.Errorf("postgresql_active_connections data source is not supported %s", db.unsupportedServer())
+	}
+
+	database := d.Get("database").(string)
+	username := d.Get("username").(string)
+
+	query := "''"
+	if d.Get("include_query").(bool) {
+		query = "COALESCE(query, '')"
+	}
+
+	// The connection of the provider is excluded, as well as the background processes.
+	filters := []string{"pid <> pg_catalog.pg_backend_pid()"}
+	if db.featureSupported(featureBackendType) {
+		filters = append(filters, "backend_type = 'client backend'")
+	} else {
+		filters = append(filters, "usename IS NOT NULL")
+	}
+	args := []interface{}{}
+	if database != "" {
+		args = append(args, database)
+		filters = append(filters, fmt.Sprintf("datname = $%d", len(args)))
+	}
+	if username != "" {
+		args = append(args, username)
+		filters = append(filters, fmt.Sprintf("usename = $%d", len(args)))
+	}
+
+	// The client address and the query of the connections of other users are only visible
+	// to superusers and members of pg_read_all_stats.
+	rows, err := db.Query(
+		fmt.Sprintf(
+			"SELECT pid, COALESCE(datname, ''), COALESCE(usename, ''), COALESCE(application_name, ''), "+
+				"COALESCE(host(client_addr), ''), COALESCE(state, ''), query_start, %s "+
+				"FROM pg_catalog.pg_stat_activity WHERE %s ORDER BY pid",
+			query, strings.Join(filters, " AND "),
+		),
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("could not read active connections: %w", err)
+	}
+	defer rows.Close()
+
+	connections := make([]interface{}, 0)
+	for rows.Next() {
+		var pid int
+		var datname, usename, applicationName, clientAddr, state, queryText string
+		var queryStart sql.NullTime
+
+		if err = rows.Scan(&pid, &datname, &usename, &applicationName, &clientAddr, &state, &queryStart, &queryText); err != nil {
+			return fmt.Errorf("could not scan active connection output: %w", err)
+		}
+
+		var start string
+		if queryStart.Valid {
+			start = queryStart.Time.Format(time.RFC3339)
+		}
+
+		connections = append(connections, map[string]interface{}{
+			"pid":              pid,
+			"database":         datname,
+			"username":         usename,
+			"application_name": applicationName,
+			"client_addr":      clientAddr,
+			"state":            state,
+			"query_start":      start,
+			"query":            queryText,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("connections", connections)
+	d.SetId(strings.Join([]string{"active_connections", database, username}, "_"))
+
+	return nil
+}
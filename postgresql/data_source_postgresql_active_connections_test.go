@@ -0,0 +1,79 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceActiveConnections(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+
+	// An application keeps a connection opened to the database
+	db, err := sql.Open("postgres", testConfig.connStr(dbName)+"&application_name=test_app")
+	if err != nil {
+		t.Fatalf("could not open SQL connection: %v", err)
+	}
+	defer db.Close()
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("could not connect: %v", err)
+	}
+	defer conn.Close()
+
+	var pid int
+	if err := conn.QueryRowContext(context.Background(), "SELECT pg_backend_pid()").Scan(&pid); err != nil {
+		t.Fatalf("could not read backend pid: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePid)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				data "postgresql_active_connections" "test" {
+					database = "%[1]s"
+				}
+
+				data "postgresql_active_connections" "with_query" {
+					database      = "%[1]s"
+					username      = "%[2]s"
+					include_query = true
+				}
+
+				data "postgresql_active_connections" "other_user" {
+					database = "%[1]s"
+					username = "unknown_user"
+				}
+				`, dbName, testConfig.Username),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.test", "connections.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.test", "connections.0.pid", fmt.Sprint(pid)),
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.test", "connections.0.database", dbName),
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.test", "connections.0.username", testConfig.Username),
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.test", "connections.0.application_name", "test_app"),
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.test", "connections.0.state", "idle"),
+					resource.TestCheckResourceAttrSet("data.postgresql_active_connections.test", "connections.0.query_start"),
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.test", "connections.0.query", ""),
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.with_query", "connections.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.with_query", "connections.0.query", "SELECT pg_backend_pid()"),
+					resource.TestCheckResourceAttr("data.postgresql_active_connections.other_user", "connections.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,182 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePostgreSQLColumns() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLColumnsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The PostgreSQL database which will be queried for the columns",
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "public",
+				Description: "The schema of the table",
+			},
+			"table": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The table, view or foreign table whose columns are retrieved",
+			},
+			"columns": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"position": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"data_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"nullable": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"default": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"identity": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"generated_expression": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"collation": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"comment": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The columns of the table, ordered by position",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLColumnsRead(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get("database").(string)
+	schemaName := d.Get("schema").(string)
+	tableName := d.Get("table").(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var tableOID int
+	err = txn.QueryRow(
+		"SELECT c.oid FROM pg_catalog.pg_class c "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace "+
+			"WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('r', 'p', 'v', 'm', 'f')",
+		schemaName, tableName,
+	).Scan(&tableOID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("table %s.%s not found in database %s", schemaName, tableName, database)
+	case err != nil:
+		return fmt.Errorf("could not read table %s.%s: %w", schemaName, tableName, err)
+	}
+
+	// attidentity and attgenerated only exist in the versions supporting these columns
+	identityColumn := "''"
+	if db.featureSupported(featureIdentityColumns) {
+		identityColumn = "a.attidentity::text"
+	}
+	generatedColumn := "''"
+	if db.featureSupported(featureGeneratedColumns) {
+		generatedColumn = "a.attgenerated::text"
+	}
+
+	// The collation is only reported when it differs from the default one of the column type
+	query := fmt.Sprintf(
+		"SELECT a.attname, a.attnum, pg_catalog.format_type(a.atttypid, a.atttypmod), NOT a.attnotnull, "+
+			"COALESCE(pg_catalog.pg_get_expr(ad.adbin, ad.adrelid), ''), %s, %s, "+
+			"CASE WHEN a.attcollation <> t.typcollation THEN COALESCE(co.collname, '') ELSE '' END, "+
+			"COALESCE(pg_catalog.col_description(a.attrelid, a.attnum), '') "+
+			"FROM pg_catalog.pg_attribute a "+
+			"JOIN pg_catalog.pg_type t ON t.oid = a.atttypid "+
+			"LEFT JOIN pg_catalog.pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum "+
+			"LEFT JOIN pg_catalog.pg_collation co ON co.oid = a.attcollation "+
+			"WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped "+
+			"ORDER BY a.attnum",
+		identityColumn, generatedColumn,
+	)
+
+	rows, err := txn.Query(query, tableOID)
+	if err != nil {
+		return fmt.Errorf("could not read columns of table %s.%s: %w", schemaName, tableName, err)
+	}
+	defer rows.Close()
+
+	columns := make([]interface{}, 0)
+	for rows.Next() {
+		var name, dataType, expression, identity, generated, collation, comment string
+		var position int
+		var nullable bool
+
+		if err = rows.Scan(&name, &position, &dataType, &nullable, &expression, &identity, &generated, &collation, &comment); err != nil {
+			return fmt.Errorf("could not scan column output: %w", err)
+		}
+
+		column := map[string]interface{}{
+			"name":      name,
+			"position":  position,
+			"data_type": dataType,
+			"nullable":  nullable,
+			"identity":  columnIdentity(identity),
+			"collation": collation,
+			"comment":   comment,
+		}
+		// The generation expression of a generated column is stored as its default
+		if generated == "s" {
+			column["generated_expression"] = expression
+		} else {
+			column["default"] = expression
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("columns", columns)
+	d.SetId(fmt.Sprintf("%s.%s.%s", database, schemaName, tableName))
+
+	return nil
+}
+
+// columnIdentity converts the attidentity code of a column to its SQL keywords.
+func columnIdentity(code string) string {
+	switch code {
+	case "a":
+		return "ALWAYS"
+	case "d":
+		return "BY DEFAULT"
+	}
+	return ""
+}
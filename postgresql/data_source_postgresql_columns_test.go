@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceColumns(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), `CREATE TABLE test_schema.test_columns (
+		id integer GENERATED BY DEFAULT AS IDENTITY,
+		dropped text,
+		name varchar(64) COLLATE "C" NOT NULL,
+		created_at timestamp with time zone DEFAULT now()
+	)`)
+	dbExecute(t, testConfig.connStr(dbName), "ALTER TABLE test_schema.test_columns DROP COLUMN dropped")
+	dbExecute(t, testConfig.connStr(dbName), "COMMENT ON COLUMN test_schema.test_columns.name IS 'The name'")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureIdentityColumns)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				data "postgresql_columns" "test" {
+					database = "%s"
+					schema   = "test_schema"
+					table    = "test_columns"
+				}
+				`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.#", "3"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.0.name", "id"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.0.position", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.0.data_type", "integer"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.0.nullable", "false"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.0.identity", "BY DEFAULT"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.1.name", "name"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.1.position", "3"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.1.data_type", "character varying(64)"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.1.collation", "C"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.1.comment", "The name"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.2.name", "created_at"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.2.data_type", "timestamp with time zone"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.2.nullable", "true"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.2.default", "now()"),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.2.collation", ""),
+					resource.TestCheckResourceAttr("data.postgresql_columns.test", "columns.2.comment", ""),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+				data "postgresql_columns" "missing" {
+					database = "%s"
+					table    = "test_columns"
+				}
+				`, dbName),
+				ExpectError: regexp.MustCompile("table public.test_columns not found"),
+			},
+		},
+	})
+}
@@ -0,0 +1,137 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	extensionObjectQuery = `
+	SELECT o.type, COALESCE(o.schema, ''), o.identity, pg_catalog.pg_describe_object(d.classid, d.objid, d.objsubid)
+	FROM pg_catalog.pg_depend d
+	JOIN pg_catalog.pg_extension e ON e.oid = d.refobjid
+	CROSS JOIN LATERAL pg_catalog.pg_identify_object(d.classid, d.objid, d.objsubid) o
+	WHERE d.refclassid = 'pg_catalog.pg_extension'::regclass AND d.deptype = 'e' AND e.extname = $1
+	`
+	extensionObjectTypeKeyword = "o.type"
+)
+
+func dataSourcePostgreSQLExtensionObjects() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLExtensionObjectsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The PostgreSQL database in which the extension is installed",
+			},
+			"extension": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the extension whose objects are retrieved",
+			},
+			"types": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "The types of the objects to retrieve (e.g.: function, table). Retrieves all types by default",
+			},
+			"objects": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"schema": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"identity": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The objects which are members of the extension, ordered by type and identity",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLExtensionObjectsRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureExtension) {
+		return fmt.Errorf(
+			"postgresql_extension_objects data source is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := d.Get("database").(string)
+	extName := d.Get("extension").(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	_, extVersion, err := readDatabaseExtension(txn, extName)
+	if err != nil {
+		return err
+	}
+	if extVersion == "" {
+		return fmt.Errorf("extension %s not found in database %s", extName, database)
+	}
+
+	filters := []string{}
+	typesFilter := applyTypeMatchingToQuery(extensionObjectTypeKeyword, d.Get("types").([]interface{}))
+	if len(typesFilter) > 0 {
+		filters = append(filters, typesFilter)
+	}
+	query := finalizeQueryWithFilters(extensionObjectQuery, queryConcatKeywordAnd, filters)
+	query += " ORDER BY o.type, o.identity"
+
+	rows, err := txn.Query(query, extName)
+	if err != nil {
+		return fmt.Errorf("could not read objects of extension %s: %w", extName, err)
+	}
+	defer rows.Close()
+
+	objects := make([]interface{}, 0)
+	for rows.Next() {
+		var objectType, objectSchema, identity, description string
+
+		if err = rows.Scan(&objectType, &objectSchema, &identity, &description); err != nil {
+			return fmt.Errorf("could not scan extension object output: %w", err)
+		}
+
+		objects = append(objects, map[string]interface{}{
+			"type":        objectType,
+			"schema":      objectSchema,
+			"identity":    identity,
+			"description": description,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("objects", objects)
+	d.SetId(strings.Join([]string{
+		database,
+		extName,
+		generatePatternArrayString(d.Get("types").([]interface{}), queryArrayKeywordAny),
+	}, "_"))
+
+	return nil
+}
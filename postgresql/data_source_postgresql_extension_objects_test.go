@@ -0,0 +1,64 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceExtensionObjects(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE EXTENSION pg_trgm")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureExtension)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				data "postgresql_extension_objects" "pg_trgm" {
+					database  = "%[1]s"
+					extension = "pg_trgm"
+				}
+
+				data "postgresql_extension_objects" "pg_trgm_types" {
+					database  = "%[1]s"
+					extension = "pg_trgm"
+					types     = ["type"]
+				}
+				`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.postgresql_extension_objects.pg_trgm", "objects.#"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_extension_objects.pg_trgm", "objects.*", map[string]string{
+						"type":        "function",
+						"schema":      "public",
+						"identity":    "public.similarity(text,text)",
+						"description": "function similarity(text,text)",
+					}),
+					resource.TestCheckResourceAttr("data.postgresql_extension_objects.pg_trgm_types", "objects.0.type", "type"),
+					resource.TestCheckResourceAttr("data.postgresql_extension_objects.pg_trgm_types", "objects.0.identity", "public.gtrgm"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+				data "postgresql_extension_objects" "missing" {
+					database  = "%s"
+					extension = "hstore"
+				}
+				`, dbName),
+				ExpectError: regexp.MustCompile("extension hstore not found in database " + dbName),
+			},
+		},
+	})
+}
@@ -0,0 +1,152 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	indexQuery = `
+	SELECT c.relname, t.relname, am.amname, i.indisunique, i.indisvalid,
+		pg_catalog.pg_get_indexdef(i.indexrelid), pg_catalog.pg_relation_size(i.indexrelid)
+	FROM pg_catalog.pg_index i
+	JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid
+	JOIN pg_catalog.pg_class t ON t.oid = i.indrelid
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_catalog.pg_am am ON am.oid = c.relam
+	WHERE n.nspname = $1
+	`
+	// The foreign keys also reference the index backing the referenced key, so only
+	// the constraints owning their index are considered.
+	indexConstraintFilter = `NOT EXISTS (
+		SELECT 1 FROM pg_catalog.pg_constraint con
+		WHERE con.conindid = i.indexrelid AND con.conrelid = i.indrelid AND con.contype IN ('p', 'u', 'x')
+	)`
+)
+
+func dataSourcePostgreSQLIndexes() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLIndexesRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The PostgreSQL database which will be queried for the indexes",
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "public",
+				Description: "The schema of the indexes",
+			},
+			"table": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the indexes of this table",
+			},
+			"exclude_constraint_indexes": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to exclude the indexes backing primary key, unique and exclusion constraints",
+			},
+			"indexes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"table": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"method": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"unique": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"valid": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"definition": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The indexes retrieved by this data source, ordered by table and name",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLIndexesRead(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get("database").(string)
+	schemaName := d.Get("schema").(string)
+	tableName := d.Get("table").(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := indexQuery
+	args := []interface{}{schemaName}
+	if tableName != "" {
+		args = append(args, tableName)
+		query += fmt.Sprintf(" AND t.relname = $%d", len(args))
+	}
+	if d.Get("exclude_constraint_indexes").(bool) {
+		query += " AND " + indexConstraintFilter
+	}
+	query += " ORDER BY t.relname, c.relname"
+
+	rows, err := txn.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("could not read indexes of schema %s: %w", schemaName, err)
+	}
+	defer rows.Close()
+
+	indexes := make([]interface{}, 0)
+	for rows.Next() {
+		var name, table, method, definition string
+		var unique, valid bool
+		var size int64
+
+		if err = rows.Scan(&name, &table, &method, &unique, &valid, &definition, &size); err != nil {
+			return fmt.Errorf("could not scan index output: %w", err)
+		}
+
+		indexes = append(indexes, map[string]interface{}{
+			"name":       name,
+			"table":      table,
+			"method":     method,
+			"unique":     unique,
+			"valid":      valid,
+			"definition": definition,
+			"size":       size,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("indexes", indexes)
+	d.SetId(strings.Join([]string{database, schemaName, tableName}, "."))
+
+	return nil
+}
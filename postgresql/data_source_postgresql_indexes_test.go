@@ -0,0 +1,75 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceIndexes(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dsn := testConfig.connStr(dbName)
+	dbExecute(t, dsn, "CREATE TABLE test_schema.orders (id integer PRIMARY KEY, reference text UNIQUE, amount integer)")
+	dbExecute(t, dsn, "CREATE INDEX orders_amount_idx ON test_schema.orders USING brin (amount)")
+	dbExecute(t, dsn, "CREATE TABLE test_schema.customers (id integer, name text)")
+	dbExecute(t, dsn, "INSERT INTO test_schema.customers VALUES (1, 'duplicate'), (2, 'duplicate')")
+
+	// A failed concurrent build leaves an invalid index behind
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("could not create connection pool: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE UNIQUE INDEX CONCURRENTLY customers_name_idx ON test_schema.customers (name)"); err == nil {
+		t.Fatalf("expected the concurrent build of a unique index on duplicate values to fail")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				data "postgresql_indexes" "test_schema" {
+					database = "%[1]s"
+					schema   = "test_schema"
+				}
+
+				data "postgresql_indexes" "orders" {
+					database                   = "%[1]s"
+					schema                     = "test_schema"
+					table                      = "orders"
+					exclude_constraint_indexes = true
+				}
+				`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.#", "4"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.0.name", "customers_name_idx"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.0.table", "customers"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.0.unique", "true"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.0.valid", "false"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.1.name", "orders_amount_idx"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.1.method", "brin"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.1.valid", "true"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.1.definition", "CREATE INDEX orders_amount_idx ON test_schema.orders USING brin (amount)"),
+					resource.TestCheckResourceAttrSet("data.postgresql_indexes.test_schema", "indexes.1.size"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.2.name", "orders_pkey"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.2.method", "btree"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.test_schema", "indexes.3.name", "orders_reference_key"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.orders", "indexes.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_indexes.orders", "indexes.0.name", "orders_amount_idx"),
+				),
+			},
+		},
+	})
+}
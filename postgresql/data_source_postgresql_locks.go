@@ -0,0 +1,122 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePostgreSQLLocks() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLLocksRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The PostgreSQL database in which the blocked sessions are looked for",
+			},
+			"locks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"blocked_pid": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"blocking_pid": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"lock_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"relation": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mode": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"blocked_query": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"blocking_query": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"blocking_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The pairs of blocked and blocking sessions retrieved by this data source",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLLocksRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureBlockingPids) {
+		return fmt.Errorf("postgresql_locks data source is not supported %s", db.unsupportedServer())
+	}
+
+	database := d.Get("database").(string)
+
+	// The relation names are resolved in the queried database
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// A blocked session waits for a single lock, which is the one not granted.
+	// The queries are only visible for the sessions of the roles the connected user is member of (or with pg_read_all_stats).
+	query := `SELECT a.pid, b.pid, l.locktype, COALESCE(l.relation::regclass::text, ''), l.mode, ` +
+		`COALESCE(a.query, ''), COALESCE(ba.query, ''), COALESCE(ba.state, '') ` +
+		`FROM pg_catalog.pg_stat_activity a ` +
+		`CROSS JOIN LATERAL unnest(pg_catalog.pg_blocking_pids(a.pid)) AS b(pid) ` +
+		`JOIN pg_catalog.pg_locks l ON l.pid = a.pid AND NOT l.granted ` +
+		`LEFT JOIN pg_catalog.pg_stat_activity ba ON ba.pid = b.pid ` +
+		`WHERE a.datname = $1 ` +
+		`ORDER BY a.pid, b.pid`
+
+	rows, err := txn.Query(query, database)
+	if err != nil {
+		return fmt.Errorf("could not read locks: %w", err)
+	}
+	defer rows.Close()
+
+	locks := make([]interface{}, 0)
+	for rows.Next() {
+		var blockedPid, blockingPid int
+		var lockType, relation, mode, blockedQuery, blockingQuery, blockingState string
+
+		if err = rows.Scan(&blockedPid, &blockingPid, &lockType, &relation, &mode, &blockedQuery, &blockingQuery, &blockingState); err != nil {
+			return fmt.Errorf("could not scan lock output: %w", err)
+		}
+
+		locks = append(locks, map[string]interface{}{
+			"blocked_pid":    blockedPid,
+			"blocking_pid":   blockingPid,
+			"lock_type":      lockType,
+			"relation":       relation,
+			"mode":           mode,
+			"blocked_query":  blockedQuery,
+			"blocking_query": blockingQuery,
+			"blocking_state": blockingState,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("locks", locks)
+	d.SetId(database)
+
+	return nil
+}
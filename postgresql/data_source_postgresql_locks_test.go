@@ -0,0 +1,95 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceLocks(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_locked (id integer)")
+
+	db, err := sql.Open("postgres", testConfig.connStr(dbName))
+	if err != nil {
+		t.Fatalf("could not open SQL connection: %v", err)
+	}
+	defer db.Close()
+
+	// A first session locks the table...
+	blocking, err := db.Begin()
+	if err != nil {
+		t.Fatalf("could not start transaction: %v", err)
+	}
+	defer blocking.Rollback()
+
+	var blockingPid int
+	if err := blocking.QueryRow("SELECT pg_backend_pid()").Scan(&blockingPid); err != nil {
+		t.Fatalf("could not read backend pid: %v", err)
+	}
+	if _, err := blocking.Exec("LOCK TABLE test_locked IN ACCESS EXCLUSIVE MODE"); err != nil {
+		t.Fatalf("could not lock table: %v", err)
+	}
+
+	// ... and a second one waits for it until the first one is rolled back.
+	blocked := make(chan error, 1)
+	go func() {
+		_, err := db.Exec("SELECT * FROM test_locked")
+		blocked <- err
+	}()
+	defer func() {
+		blocking.Rollback()
+		if err := <-blocked; err != nil {
+			t.Errorf("blocked query failed: %v", err)
+		}
+	}()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureBlockingPids)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					// Wait for the second session to be blocked
+					var waiting bool
+					for !waiting {
+						if err := db.QueryRow(
+							"SELECT count(*) > 0 FROM pg_locks WHERE relation = 'test_locked'::regclass AND NOT granted",
+						).Scan(&waiting); err != nil {
+							t.Fatalf("could not check locks: %v", err)
+						}
+						time.Sleep(100 * time.Millisecond)
+					}
+				},
+				Config: fmt.Sprintf(`
+				data "postgresql_locks" "test" {
+					database = "%s"
+				}
+				`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_locks.test", "locks.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_locks.test", "locks.0.blocking_pid", strconv.Itoa(blockingPid)),
+					resource.TestCheckResourceAttrSet("data.postgresql_locks.test", "locks.0.blocked_pid"),
+					resource.TestCheckResourceAttr("data.postgresql_locks.test", "locks.0.lock_type", "relation"),
+					resource.TestCheckResourceAttr("data.postgresql_locks.test", "locks.0.relation", "test_locked"),
+					resource.TestCheckResourceAttr("data.postgresql_locks.test", "locks.0.mode", "AccessShareLock"),
+					resource.TestCheckResourceAttr("data.postgresql_locks.test", "locks.0.blocked_query", "SELECT * FROM test_locked"),
+					resource.TestCheckResourceAttr("data.postgresql_locks.test", "locks.0.blocking_state", "idle in transaction"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,232 @@
+package postgresql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	objectPrivilegesDatabaseAttr   = "database"
+	objectPrivilegesObjectTypeAttr = "object_type"
+	objectPrivilegesSchemaAttr     = "schema"
+	objectPrivilegesObjectNameAttr = "object_name"
+	objectPrivilegesPrivilegesAttr = "privileges"
+)
+
+// objectPrivilegesCatalog describes where the ACL of an object type is stored.
+// When the ACL is NULL, the object has its default privileges (acldefault).
+type objectPrivilegesCatalog struct {
+	// Catalog of the object (aliased as o) and condition selecting it,
+	// $1 is the object name and $2 the schema name if needed
+	from  string
+	where string
+	// ACL and owner columns of the catalog
+	aclColumn   string
+	ownerColumn string
+	// Object type code of acldefault
+	aclDefaultType string
+	needsSchema    bool
+}
+
+var objectPrivilegesCatalogs = map[string]objectPrivilegesCatalog{
+	"database": {
+		from:           "pg_catalog.pg_database o",
+		where:          "o.datname = $1",
+		aclColumn:      "datacl",
+		ownerColumn:    "datdba",
+		aclDefaultType: "d",
+	},
+	"schema": {
+		from:           "pg_catalog.pg_namespace o",
+		where:          "o.nspname = $1",
+		aclColumn:      "nspacl",
+		ownerColumn:    "nspowner",
+		aclDefaultType: "n",
+	},
+	"table": {
+		from:           "pg_catalog.pg_class o JOIN pg_catalog.pg_namespace n ON n.oid = o.relnamespace",
+		where:          "o.relname = $1 AND n.nspname = $2 AND o.relkind IN ('r', 'v', 'm', 'f', 'p')",
+		aclColumn:      "relacl",
+		ownerColumn:    "relowner",
+		aclDefaultType: "r",
+		needsSchema:    true,
+	},
+	"sequence": {
+		from:           "pg_catalog.pg_class o JOIN pg_catalog.pg_namespace n ON n.oid = o.relnamespace",
+		where:          "o.relname = $1 AND n.nspname = $2 AND o.relkind = 'S'",
+		aclColumn:      "relacl",
+		ownerColumn:    "relowner",
+		aclDefaultType: "s",
+		needsSchema:    true,
+	},
+	"function": {
+		from:           "pg_catalog.pg_proc o JOIN pg_catalog.pg_namespace n ON n.oid = o.pronamespace",
+		where:          "o.proname = $1 AND n.nspname = $2",
+		aclColumn:      "proacl",
+		ownerColumn:    "proowner",
+		aclDefaultType: "f",
+		needsSchema:    true,
+	},
+	"type": {
+		from:           "pg_catalog.pg_type o JOIN pg_catalog.pg_namespace n ON n.oid = o.typnamespace",
+		where:          "o.typname = $1 AND n.nspname = $2",
+		aclColumn:      "typacl",
+		ownerColumn:    "typowner",
+		aclDefaultType: "T",
+		needsSchema:    true,
+	},
+	"foreign_data_wrapper": {
+		from:           "pg_catalog.pg_foreign_data_wrapper o",
+		where:          "o.fdwname = $1",
+		aclColumn:      "fdwacl",
+		ownerColumn:    "fdwowner",
+		aclDefaultType: "F",
+	},
+	"foreign_server": {
+		from:           "pg_catalog.pg_foreign_server o",
+		where:          "o.srvname = $1",
+		aclColumn:      "srvacl",
+		ownerColumn:    "srvowner",
+		aclDefaultType: "S",
+	},
+	"language": {
+		from:           "pg_catalog.pg_language o",
+		where:          "o.lanname = $1",
+		aclColumn:      "lanacl",
+		ownerColumn:    "lanowner",
+		aclDefaultType: "l",
+	},
+}
+
+func dataSourcePostgreSQLObjectPrivileges() *schema.Resource {
+	objectTypeNames := make([]string, 0, len(objectPrivilegesCatalogs))
+	for objectType := range objectPrivilegesCatalogs {
+		objectTypeNames = append(objectTypeNames, objectType)
+	}
+	sort.Strings(objectTypeNames)
+
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLObjectPrivilegesRead),
+		Schema: map[string]*schema.Schema{
+			objectPrivilegesDatabaseAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The database of the object",
+			},
+			objectPrivilegesObjectTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(objectTypeNames, false),
+				Description:  "The PostgreSQL object type to read the privileges of (one of: " + strings.Join(objectTypeNames, ", ") + ")",
+			},
+			objectPrivilegesSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The schema of the object, required for tables, sequences, functions and types",
+			},
+			objectPrivilegesObjectNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the object",
+			},
+			objectPrivilegesPrivilegesAttr: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"grantee": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"privilege": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_grantable": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The privileges granted on the object, including the implicit privileges of the owner and of PUBLIC",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLObjectPrivilegesRead(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get(objectPrivilegesDatabaseAttr).(string)
+	objectType := d.Get(objectPrivilegesObjectTypeAttr).(string)
+	schemaName := d.Get(objectPrivilegesSchemaAttr).(string)
+	objectName := d.Get(objectPrivilegesObjectNameAttr).(string)
+
+	catalog := objectPrivilegesCatalogs[objectType]
+	if catalog.needsSchema && schemaName == "" {
+		return fmt.Errorf("parameter `%s` is required for object type %s", objectPrivilegesSchemaAttr, objectType)
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	args := []interface{}{objectName}
+	if catalog.needsSchema {
+		args = append(args, schemaName)
+	}
+
+	var count int
+	if err := txn.QueryRow(fmt.Sprintf("SELECT count(*) FROM %s WHERE %s", catalog.from, catalog.where), args...).Scan(&count); err != nil {
+		return fmt.Errorf("could not find %s %s: %w", objectType, objectName, err)
+	}
+	switch {
+	case count == 0:
+		return fmt.Errorf("%s %s not found", objectType, objectName)
+	case count > 1:
+		// e.g.: overloaded functions
+		return fmt.Errorf("%s %s is ambiguous, %d objects have this name", objectType, objectName, count)
+	}
+
+	query := fmt.Sprintf(
+		`SELECT CASE WHEN a.grantee = 0 THEN '%s' ELSE pg_catalog.pg_get_userbyid(a.grantee) END AS grantee, `+
+			`a.privilege_type, a.is_grantable `+
+			`FROM %s, pg_catalog.aclexplode(COALESCE(o.%s, pg_catalog.acldefault('%s', o.%s))) a `+
+			`WHERE %s ORDER BY 1, 2`,
+		publicRole, catalog.from, catalog.aclColumn, catalog.aclDefaultType, catalog.ownerColumn, catalog.where,
+	)
+
+	rows, err := txn.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("could not read privileges of %s %s: %w", objectType, objectName, err)
+	}
+	defer rows.Close()
+
+	privileges := make([]interface{}, 0)
+	for rows.Next() {
+		var grantee, privilege string
+		var isGrantable bool
+
+		if err = rows.Scan(&grantee, &privilege, &isGrantable); err != nil {
+			return fmt.Errorf("could not scan privileges of %s %s: %w", objectType, objectName, err)
+		}
+
+		privileges = append(privileges, map[string]interface{}{
+			"grantee":      grantee,
+			"privilege":    privilege,
+			"is_grantable": isGrantable,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set(objectPrivilegesPrivilegesAttr, privileges)
+	d.SetId(strings.Join([]string{database, objectType, schemaName, objectName}, "_"))
+
+	return nil
+}
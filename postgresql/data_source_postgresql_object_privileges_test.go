@@ -0,0 +1,120 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceObjectPrivileges(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	config := getTestConfig(t)
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	dbExecute(t, config.connStr(dbName), "CREATE TABLE test_schema.audited (id int)")
+	dbExecute(t, config.connStr(dbName), fmt.Sprintf("GRANT SELECT ON test_schema.audited TO %s", roleName))
+	dbExecute(t, config.connStr(dbName), fmt.Sprintf("GRANT INSERT ON test_schema.audited TO %s WITH GRANT OPTION", roleName))
+	dbExecute(t, config.connStr(dbName), "CREATE SEQUENCE test_schema.audited_seq")
+
+	tfConfig := fmt.Sprintf(`
+data "postgresql_object_privileges" "table" {
+  database    = "%[1]s"
+  object_type = "table"
+  schema      = "test_schema"
+  object_name = "audited"
+}
+
+data "postgresql_object_privileges" "sequence" {
+  database    = "%[1]s"
+  object_type = "sequence"
+  schema      = "test_schema"
+  object_name = "audited_seq"
+}
+
+data "postgresql_object_privileges" "schema" {
+  database    = "%[1]s"
+  object_type = "schema"
+  object_name = "dev_schema"
+}
+
+data "postgresql_object_privileges" "database" {
+  database    = "%[1]s"
+  object_type = "database"
+  object_name = "%[1]s"
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: tfConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_object_privileges.table", "privileges.*", map[string]string{
+						"grantee":      roleName,
+						"privilege":    "SELECT",
+						"is_grantable": "false",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_object_privileges.table", "privileges.*", map[string]string{
+						"grantee":      roleName,
+						"privilege":    "INSERT",
+						"is_grantable": "true",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_object_privileges.table", "privileges.*", map[string]string{
+						"grantee":   config.Username,
+						"privilege": "TRUNCATE",
+					}),
+					// The sequence has no ACL, only the default privileges of its owner are returned
+					resource.TestCheckResourceAttr("data.postgresql_object_privileges.sequence", "privileges.#", "3"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_object_privileges.sequence", "privileges.*", map[string]string{
+						"grantee":   config.Username,
+						"privilege": "USAGE",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_object_privileges.schema", "privileges.*", map[string]string{
+						"grantee":   roleName,
+						"privilege": "USAGE",
+					}),
+					// PUBLIC can connect to a database by default
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_object_privileges.database", "privileges.*", map[string]string{
+						"grantee":   "public",
+						"privilege": "CONNECT",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlDataSourceObjectPrivileges_NotFound(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "postgresql_object_privileges" "table" {
+  database    = "%s"
+  object_type = "table"
+  schema      = "test_schema"
+  object_name = "does_not_exist"
+}
+`, dbName),
+				ExpectError: regexp.MustCompile("table does_not_exist not found"),
+			},
+		},
+	})
+}
@@ -0,0 +1,171 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	publicationPatternMatchingTarget = "p.pubname"
+)
+
+func dataSourcePostgreSQLPublications() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLPublicationsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The PostgreSQL database which will be queried for publications",
+			},
+			"like_any_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against publication names in the query using the PostgreSQL LIKE ANY operator",
+			},
+			"like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against publication names in the query using the PostgreSQL LIKE ALL operator",
+			},
+			"not_like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against publication names in the query using the PostgreSQL NOT LIKE ALL operator",
+			},
+			"regex_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Expression which will be pattern matched against publication names in the query using the PostgreSQL ~ (regular expression match) operator",
+			},
+			"publications": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owner": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"all_tables": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"table_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"publish": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"publish_via_partition_root": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The list of PostgreSQL publications retrieved by this data source",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLPublicationsRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featurePublication) {
+		return fmt.Errorf(
+			"postgresql_publications data source is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := d.Get("database").(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	publish := []string{
+		"CASE WHEN p.pubinsert THEN 'insert' END",
+		"CASE WHEN p.pubupdate THEN 'update' END",
+		"CASE WHEN p.pubdelete THEN 'delete' END",
+	}
+	if db.featureSupported(featurePubTruncate) {
+		publish = append(publish, "CASE WHEN p.pubtruncate THEN 'truncate' END")
+	}
+	viaRoot := "false"
+	if db.featureSupported(featurePublishViaRoot) {
+		viaRoot = "p.pubviaroot"
+	}
+
+	query := fmt.Sprintf(
+		`SELECT p.pubname, pg_catalog.pg_get_userbyid(p.pubowner), p.puballtables, `+
+			`(SELECT count(*) FROM pg_catalog.pg_publication_tables pt WHERE pt.pubname = p.pubname), `+
+			`array_remove(ARRAY[%s], NULL), %s `+
+			`FROM pg_catalog.pg_publication p`,
+		strings.Join(publish, ", "), viaRoot,
+	)
+	query = finalizeQueryWithFilters(query, queryConcatKeywordWhere, applyPatternMatchingToQuery(publicationPatternMatchingTarget, d))
+	query += " ORDER BY p.pubname"
+
+	rows, err := txn.Query(query)
+	if err != nil {
+		return fmt.Errorf("could not read publications of database %s: %w", database, err)
+	}
+	defer rows.Close()
+
+	publications := make([]interface{}, 0)
+	for rows.Next() {
+		var name, owner string
+		var allTables, viaPartitionRoot bool
+		var tableCount int
+		var publishOperations []string
+
+		if err = rows.Scan(&name, &owner, &allTables, &tableCount, pq.Array(&publishOperations), &viaPartitionRoot); err != nil {
+			return fmt.Errorf("could not scan publication output for database: %w", err)
+		}
+
+		publications = append(publications, map[string]interface{}{
+			"name":                       name,
+			"owner":                      owner,
+			"all_tables":                 allTables,
+			"table_count":                tableCount,
+			"publish":                    publishOperations,
+			"publish_via_partition_root": viaPartitionRoot,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("publications", publications)
+	d.SetId(generateDataSourcePublicationsID(d, database))
+
+	return nil
+}
+
+func generateDataSourcePublicationsID(d *schema.ResourceData, databaseName string) string {
+	return strings.Join([]string{
+		databaseName,
+		generatePatternArrayString(d.Get("like_any_patterns").([]interface{}), queryArrayKeywordAny),
+		generatePatternArrayString(d.Get("like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		generatePatternArrayString(d.Get("not_like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		d.Get("regex_pattern").(string),
+	}, "_")
+}
@@ -0,0 +1,58 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourcePublications(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table_1", "test_schema.test_table_2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	config := getTestConfig(t)
+	dbExecute(t, config.connStr(dbName), "CREATE PUBLICATION test_pub_tables FOR TABLE test_schema.test_table_1, test_schema.test_table_2 WITH (publish = 'insert, update')")
+	dbExecute(t, config.connStr(dbName), "CREATE PUBLICATION test_pub_all FOR ALL TABLES")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePublication)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				data "postgresql_publications" "all" {
+					database = "%[1]s"
+				}
+
+				data "postgresql_publications" "tables" {
+					database          = "%[1]s"
+					like_any_patterns = ["%%tables"]
+				}
+				`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_publications.all", "publications.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_publications.all", "publications.0.name", "test_pub_all"),
+					resource.TestCheckResourceAttr("data.postgresql_publications.all", "publications.0.all_tables", "true"),
+					resource.TestCheckResourceAttr("data.postgresql_publications.tables", "publications.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_publications.tables", "publications.0.name", "test_pub_tables"),
+					resource.TestCheckResourceAttr("data.postgresql_publications.tables", "publications.0.all_tables", "false"),
+					resource.TestCheckResourceAttr("data.postgresql_publications.tables", "publications.0.table_count", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_publications.tables", "publications.0.publish.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_publications.tables", "publications.0.publish.0", "insert"),
+					resource.TestCheckResourceAttr("data.postgresql_publications.tables", "publications.0.publish.1", "update"),
+					resource.TestCheckResourceAttrSet("data.postgresql_publications.tables", "publications.0.owner"),
+				),
+			},
+		},
+	})
+}
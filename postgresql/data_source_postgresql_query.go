@@ -0,0 +1,204 @@
+package postgresql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourcePostgreSQLQuery() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLQueryRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The database in which the query is run. Defaults to the database of the provider",
+			},
+			"query": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "The query to run, in a read only transaction",
+			},
+			"args": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The values of the parameters of the query ($1, $2, ...)",
+			},
+			"columns": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The columns of the result, in the order of the query",
+			},
+			"rows": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{Type: schema.TypeString},
+				},
+				Description: "The rows of the result, as maps of the column names to their values formatted as strings, the NULL values being omitted",
+			},
+			"result_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The rows of the result as a JSON array of objects, the numbers, booleans, nulls and JSON values keeping their type",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLQueryRead(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	query := d.Get("query").(string)
+	args := d.Get("args").([]interface{})
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// A data source must not modify anything, the transaction is always rolled back anyway
+	if _, err := txn.Exec("SET TRANSACTION READ ONLY"); err != nil {
+		return fmt.Errorf("could not set transaction read only: %w", err)
+	}
+
+	rows, err := txn.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("could not run query: %w", err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return fmt.Errorf("could not read columns of query result: %w", err)
+	}
+
+	columns := make([]interface{}, len(columnTypes))
+	for i, column := range columnTypes {
+		columns[i] = map[string]interface{}{
+			"name": column.Name(),
+			"type": strings.ToLower(column.DatabaseTypeName()),
+		}
+	}
+
+	stringRows := make([]interface{}, 0)
+	jsonRows := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columnTypes))
+		pointers := make([]interface{}, len(columnTypes))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return fmt.Errorf("could not scan query result: %w", err)
+		}
+
+		stringRow := map[string]interface{}{}
+		jsonRow := map[string]interface{}{}
+		for i, column := range columnTypes {
+			jsonRow[column.Name()] = queryValueToJSON(column, values[i])
+			if values[i] != nil {
+				stringRow[column.Name()] = queryValueToString(values[i])
+			}
+		}
+		stringRows = append(stringRows, stringRow)
+		jsonRows = append(jsonRows, jsonRow)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	resultJSON, err := json.Marshal(jsonRows)
+	if err != nil {
+		return fmt.Errorf("could not encode query result to JSON: %w", err)
+	}
+
+	d.Set("database", database)
+	d.Set("columns", columns)
+	d.Set("rows", stringRows)
+	d.Set("result_json", string(resultJSON))
+	d.SetId(generateDataSourceQueryID(database, query, args))
+
+	return nil
+}
+
+// queryColumnType is the part of *sql.ColumnType used to convert the values to JSON.
+type queryColumnType interface {
+	DatabaseTypeName() string
+}
+
+// queryValueToJSON converts a value scanned by the driver to the value encoded in the JSON result.
+// The driver returns the integers, floats, booleans and timestamps with their Go type but the other
+// types as their text representation, so the numerics and the JSON values are kept as is.
+func queryValueToJSON(column queryColumnType, value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		switch column.DatabaseTypeName() {
+		case "NUMERIC":
+			// NaN and infinity are not valid JSON numbers
+			if number := json.Number(v); isJSONNumber(number) {
+				return number
+			}
+			return string(v)
+		case "JSON", "JSONB":
+			if json.Valid(v) {
+				return json.RawMessage(v)
+			}
+			return string(v)
+		case "BYTEA":
+			return `\x` + hex.EncodeToString(v)
+		}
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return value
+}
+
+// queryValueToString formats a value scanned by the driver as a string.
+func queryValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+func isJSONNumber(number json.Number) bool {
+	var f float64
+	return json.Unmarshal([]byte(number), &f) == nil
+}
+
+func generateDataSourceQueryID(database, query string, args []interface{}) string {
+	hash := sha256.New()
+	hash.Write([]byte(query))
+	for _, arg := range args {
+		hash.Write([]byte{0})
+		hash.Write([]byte(fmt.Sprint(arg)))
+	}
+	return fmt.Sprintf("%s_%x", database, hash.Sum(nil)[:8])
+}
@@ -0,0 +1,80 @@
+package postgresql
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+type testQueryColumnType string
+
+func (c testQueryColumnType) DatabaseTypeName() string {
+	return string(c)
+}
+
+func TestQueryValueToJSON(t *testing.T) {
+	cases := map[string]struct {
+		typeName string
+		value    interface{}
+		expected string
+	}{
+		"null":          {"INT4", nil, `null`},
+		"integer":       {"INT8", int64(42), `42`},
+		"float":         {"FLOAT8", float64(1.5), `1.5`},
+		"boolean":       {"BOOL", true, `true`},
+		"numeric":       {"NUMERIC", []byte("12345678901234567890.123"), `12345678901234567890.123`},
+		"numeric NaN":   {"NUMERIC", []byte("NaN"), `"NaN"`},
+		"jsonb":         {"JSONB", []byte(`{"a": [1, null]}`), `{"a":[1,null]}`},
+		"bytea":         {"BYTEA", []byte{0xde, 0xad}, `"\\xdead"`},
+		"text":          {"TEXT", "foo", `"foo"`},
+		"uuid as bytes": {"UUID", []byte("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"), `"a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"`},
+		"timestamp":     {"TIMESTAMPTZ", time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC), `"2022-03-04T05:06:07Z"`},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := json.Marshal(queryValueToJSON(testQueryColumnType(c.typeName), c.value))
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, string(encoded))
+		})
+	}
+}
+
+func TestAccPostgresqlDataSourceQuery(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "postgresql_query" "test" {
+  database = "%s"
+  query    = "SELECT 1::int AS i, true AS b, NULL::text AS n, $1::text AS s"
+  args     = ["foo"]
+}
+`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_query.test", "result_json", `[{"b":true,"i":1,"n":null,"s":"foo"}]`),
+					resource.TestCheckResourceAttr("data.postgresql_query.test", "columns.#", "4"),
+					resource.TestCheckResourceAttr("data.postgresql_query.test", "columns.0.name", "i"),
+					resource.TestCheckResourceAttr("data.postgresql_query.test", "columns.0.type", "int4"),
+					resource.TestCheckResourceAttr("data.postgresql_query.test", "rows.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_query.test", "rows.0.i", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_query.test", "rows.0.b", "true"),
+					resource.TestCheckNoResourceAttr("data.postgresql_query.test", "rows.0.n"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,158 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	replicationSlotPatternMatchingTarget = "s.slot_name"
+)
+
+func dataSourcePostgreSQLReplicationSlots() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLReplicationSlotsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the logical replication slots of this database. Returns the slots of all databases and the physical slots by default",
+			},
+			"like_any_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against replication slot names in the query using the PostgreSQL LIKE ANY operator",
+			},
+			"like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against replication slot names in the query using the PostgreSQL LIKE ALL operator",
+			},
+			"not_like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against replication slot names in the query using the PostgreSQL NOT LIKE ALL operator",
+			},
+			"regex_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Expression which will be pattern matched against replication slot names in the query using the PostgreSQL ~ (regular expression match) operator",
+			},
+			"replication_slots": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"plugin": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"slot_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"database": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"active": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"restart_lsn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"wal_retained_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The list of PostgreSQL replication slots retrieved by this data source",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLReplicationSlotsRead(db *DBConnection, d *schema.ResourceData) error {
+	// The current WAL location is the last received one on a standby
+	currentLSN := "CASE WHEN pg_catalog.pg_is_in_recovery() THEN pg_catalog.pg_last_wal_receive_lsn() ELSE pg_catalog.pg_current_wal_lsn() END"
+	lsnDiff := "pg_catalog.pg_wal_lsn_diff"
+	if !db.featureSupported(featureWALFunctions) {
+		currentLSN = "CASE WHEN pg_catalog.pg_is_in_recovery() THEN pg_catalog.pg_last_xlog_receive_location() ELSE pg_catalog.pg_current_xlog_location() END"
+		lsnDiff = "pg_catalog.pg_xlog_location_diff"
+	}
+
+	query := fmt.Sprintf(
+		`SELECT s.slot_name, COALESCE(s.plugin, ''), s.slot_type, COALESCE(s.database, ''), s.active, `+
+			`COALESCE(s.restart_lsn::text, ''), COALESCE(%s(%s, s.restart_lsn), 0)::bigint `+
+			`FROM pg_catalog.pg_replication_slots s`,
+		lsnDiff, currentLSN,
+	)
+
+	filters := applyPatternMatchingToQuery(replicationSlotPatternMatchingTarget, d)
+	if database := d.Get("database").(string); database != "" {
+		filters = append(filters, fmt.Sprintf("s.database = %s", pq.QuoteLiteral(database)))
+	}
+	query = finalizeQueryWithFilters(query, queryConcatKeywordWhere, filters)
+	query += " ORDER BY s.slot_name"
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return fmt.Errorf("could not read replication slots: %w", err)
+	}
+	defer rows.Close()
+
+	slots := make([]interface{}, 0)
+	for rows.Next() {
+		var name, plugin, slotType, database, restartLSN string
+		var active bool
+		var walRetainedBytes int64
+
+		if err = rows.Scan(&name, &plugin, &slotType, &database, &active, &restartLSN, &walRetainedBytes); err != nil {
+			return fmt.Errorf("could not scan replication slot output: %w", err)
+		}
+
+		slots = append(slots, map[string]interface{}{
+			"name":               name,
+			"plugin":             plugin,
+			"slot_type":          slotType,
+			"database":           database,
+			"active":             active,
+			"restart_lsn":        restartLSN,
+			"wal_retained_bytes": walRetainedBytes,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("replication_slots", slots)
+	d.SetId(generateDataSourceReplicationSlotsID(d))
+
+	return nil
+}
+
+func generateDataSourceReplicationSlotsID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get("database").(string),
+		generatePatternArrayString(d.Get("like_any_patterns").([]interface{}), queryArrayKeywordAny),
+		generatePatternArrayString(d.Get("like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		generatePatternArrayString(d.Get("not_like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		d.Get("regex_pattern").(string),
+	}, "_")
+}
@@ -0,0 +1,69 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceReplicationSlots(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+			testCheckCompatibleVersion(t, featureWALFunctions)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "postgresql_replication_slot" "logical" {
+					name     = "ds_logical_slot"
+					plugin   = "test_decoding"
+					database = "%[1]s"
+				}
+
+				resource "postgresql_replication_slot" "physical" {
+					name                = "ds_physical_slot"
+					physical            = true
+					immediately_reserve = true
+				}
+
+				data "postgresql_replication_slots" "database" {
+					database = "%[1]s"
+
+					depends_on = [postgresql_replication_slot.logical, postgresql_replication_slot.physical]
+				}
+
+				data "postgresql_replication_slots" "pattern" {
+					regex_pattern = "^ds_.*_slot$"
+
+					depends_on = [postgresql_replication_slot.logical, postgresql_replication_slot.physical]
+				}
+				`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.database", "replication_slots.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.database", "replication_slots.0.name", "ds_logical_slot"),
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.database", "replication_slots.0.plugin", "test_decoding"),
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.database", "replication_slots.0.slot_type", "logical"),
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.database", "replication_slots.0.database", dbName),
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.database", "replication_slots.0.active", "false"),
+					resource.TestCheckResourceAttrSet("data.postgresql_replication_slots.database", "replication_slots.0.restart_lsn"),
+					resource.TestCheckResourceAttrSet("data.postgresql_replication_slots.database", "replication_slots.0.wal_retained_bytes"),
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.pattern", "replication_slots.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.pattern", "replication_slots.1.name", "ds_physical_slot"),
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.pattern", "replication_slots.1.slot_type", "physical"),
+					resource.TestCheckResourceAttr("data.postgresql_replication_slots.pattern", "replication_slots.1.database", ""),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,105 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const roleSettingQuery = `
+	SELECT COALESCE(r.rolname, ''), COALESCE(d.datname, ''), s.setconfig
+	FROM pg_catalog.pg_db_role_setting s
+	LEFT JOIN pg_catalog.pg_roles r ON r.oid = s.setrole
+	LEFT JOIN pg_catalog.pg_database d ON d.oid = s.setdatabase
+	`
+
+func dataSourcePostgreSQLRoleSettings() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLRoleSettingsRead),
+		Schema: map[string]*schema.Schema{
+			"role": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the settings of this role",
+			},
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the settings in this database",
+			},
+			"settings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"database": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"values": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+				Description: "The settings set with ALTER ROLE and ALTER DATABASE, ordered by role and database",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLRoleSettingsRead(db *DBConnection, d *schema.ResourceData) error {
+	roleName := d.Get("role").(string)
+	database := d.Get("database").(string)
+
+	filters := []string{}
+	args := []interface{}{}
+	if roleName != "" {
+		args = append(args, roleName)
+		filters = append(filters, fmt.Sprintf("r.rolname = $%d", len(args)))
+	}
+	if database != "" {
+		args = append(args, database)
+		filters = append(filters, fmt.Sprintf("d.datname = $%d", len(args)))
+	}
+	query := finalizeQueryWithFilters(roleSettingQuery, queryConcatKeywordWhere, filters)
+	query += " ORDER BY 1, 2"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("could not read role settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := make([]interface{}, 0)
+	for rows.Next() {
+		var settingRole, settingDatabase string
+		var setconfig []string
+
+		if err = rows.Scan(&settingRole, &settingDatabase, pq.Array(&setconfig)); err != nil {
+			return fmt.Errorf("could not scan role setting output: %w", err)
+		}
+
+		// The entries of setconfig have the same name=value format as the reloptions
+		settings = append(settings, map[string]interface{}{
+			"role":     settingRole,
+			"database": settingDatabase,
+			"values":   relOptionsToMap(setconfig),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("settings", settings)
+	d.SetId(strings.Join([]string{"role_settings", roleName, database}, "_"))
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/lib/pq"
+)
+
+func TestRoleSettingsParsing(t *testing.T) {
+	var setconfig []string
+	// setconfig as returned by Postgres, the values can contain commas, quotes and equal signs
+	src := `{"search_path=\"$user\", public",application_name=a=b,"work_mem=64MB"}`
+	if err := pq.Array(&setconfig).Scan([]byte(src)); err != nil {
+		t.Fatalf("could not scan setconfig: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"search_path":      `"$user", public`,
+		"application_name": "a=b",
+		"work_mem":         "64MB",
+	}
+	if out := relOptionsToMap(setconfig); !reflect.DeepEqual(out, expected) {
+		t.Fatalf("expected %v, got %v", expected, out)
+	}
+}
+
+func TestAccPostgresqlDataSourceRoleSettings(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dsn := testConfig.connStr("postgres")
+	dbExecute(t, dsn, fmt.Sprintf(`ALTER ROLE %s IN DATABASE %s SET search_path = "$user", test_schema`, roleName, dbName))
+	dbExecute(t, dsn, fmt.Sprintf("ALTER ROLE %s SET statement_timeout = '5min'", roleName))
+	dbExecute(t, dsn, fmt.Sprintf("ALTER DATABASE %s SET application_name = 'app=test,v1'", dbName))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				data "postgresql_role_settings" "role" {
+					role = "%s"
+				}
+
+				data "postgresql_role_settings" "database" {
+					database = "%s"
+				}
+				`, roleName, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.role", "settings.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.role", "settings.0.role", roleName),
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.role", "settings.0.database", ""),
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.role", "settings.0.values.statement_timeout", "5min"),
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.role", "settings.1.database", dbName),
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.role", "settings.1.values.search_path", `"$user", test_schema`),
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.database", "settings.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.database", "settings.0.role", ""),
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.database", "settings.0.values.application_name", "app=test,v1"),
+					resource.TestCheckResourceAttr("data.postgresql_role_settings.database", "settings.1.role", roleName),
+				),
+			},
+		},
+	})
+}
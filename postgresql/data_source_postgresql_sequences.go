@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -8,12 +9,22 @@ import (
 )
 
 const (
+	// The sequences are read from pg_class rather than information_schema.sequences,
+	// which hides the sequences the connected user has no privilege on.
 	sequenceQuery = `
-	SELECT sequence_name, sequence_schema, data_type
-	FROM information_schema.sequences
+	SELECT c.relname, n.nspname, %s, pg_catalog.pg_get_userbyid(c.relowner), %s, %s,
+		COALESCE(tn.nspname, ''), COALESCE(t.relname, ''), COALESCE(ta.attname, '')
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_catalog.pg_depend dep ON dep.classid = 'pg_catalog.pg_class'::regclass AND dep.objid = c.oid
+		AND dep.refclassid = 'pg_catalog.pg_class'::regclass AND dep.refobjsubid > 0 AND dep.deptype IN ('a', 'i')
+	LEFT JOIN pg_catalog.pg_class t ON t.oid = dep.refobjid
+	LEFT JOIN pg_catalog.pg_namespace tn ON tn.oid = t.relnamespace
+	LEFT JOIN pg_catalog.pg_attribute ta ON ta.attrelid = dep.refobjid AND ta.attnum = dep.refobjsubid
+	WHERE c.relkind = 'S' AND NOT pg_catalog.pg_is_other_temp_schema(n.oid)
 	`
-	sequencePatternMatchingTarget = "sequence_name"
-	sequenceSchemaKeyword         = "sequence_schema"
+	sequencePatternMatchingTarget = "c.relname"
+	sequenceSchemaKeyword         = "n.nspname"
 )
 
 func dataSourcePostgreSQLDatabaseSequences() *schema.Resource {
@@ -76,6 +87,30 @@ func dataSourcePostgreSQLDatabaseSequences() *schema.Resource {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+						"owner": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_value": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"readable": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"owned_by_schema": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owned_by_table": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owned_by_column": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
 					},
 				},
 				Description: "The list of PostgreSQL sequence names retrieved by this data source. Note that this returns a set, so duplicate table names across different schemas will be consolidated.",
@@ -93,10 +128,21 @@ func dataSourcePostgreSQLSequencesRead(db *DBConnection, d *schema.ResourceData)
 	}
 	defer deferredRollback(txn)
 
-	query := sequenceQuery
-	queryConcatKeyword := queryConcatKeywordWhere
+	// The last value can only be read with the SELECT or USAGE privilege on the sequence,
+	// it is NULL when the sequence has not been used yet.
+	dataType := "'bigint'"
+	readable := "false"
+	lastValue := "NULL::bigint"
+	if db.featureSupported(featureSequenceDataType) {
+		dataType = "(SELECT pg_catalog.format_type(s.seqtypid, NULL) FROM pg_catalog.pg_sequence s WHERE s.seqrelid = c.oid)"
+		readable = "pg_catalog.has_sequence_privilege(c.oid, 'SELECT,USAGE')"
+		lastValue = fmt.Sprintf("CASE WHEN %s THEN pg_catalog.pg_sequence_last_value(c.oid) END", readable)
+	}
+	query := fmt.Sprintf(sequenceQuery, dataType, readable, lastValue)
+	queryConcatKeyword := queryConcatKeywordAnd
 
 	query = applySequenceDataSourceQueryFilters(query, queryConcatKeyword, d)
+	query += " ORDER BY n.nspname, c.relname"
 
 	rows, err := txn.Query(query)
 	if err != nil {
@@ -109,8 +155,13 @@ func dataSourcePostgreSQLSequencesRead(db *DBConnection, d *schema.ResourceData)
 		var object_name string
 		var schema_name string
 		var data_type string
+		var owner, ownedBySchema, ownedByTable, ownedByColumn string
+		var readable bool
+		var lastValue sql.NullInt64
 
-		if err = rows.Scan(&object_name, &schema_name, &data_type); err != nil {
+		if err = rows.Scan(
+			&object_name, &schema_name, &data_type, &owner, &readable, &lastValue, &ownedBySchema, &ownedByTable, &ownedByColumn,
+		); err != nil {
 			return fmt.Errorf("could not scan sequence output for database: %w", err)
 		}
 
@@ -118,8 +169,17 @@ func dataSourcePostgreSQLSequencesRead(db *DBConnection, d *schema.ResourceData)
 		result["object_name"] = object_name
 		result["schema_name"] = schema_name
 		result["data_type"] = data_type
+		result["owner"] = owner
+		result["readable"] = readable
+		result["last_value"] = lastValue.Int64
+		result["owned_by_schema"] = ownedBySchema
+		result["owned_by_table"] = ownedByTable
+		result["owned_by_column"] = ownedByColumn
 		sequences = append(sequences, result)
 	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
 
 	d.Set("sequences", sequences)
 	d.SetId(generateDataSourceSequencesID(d, database))
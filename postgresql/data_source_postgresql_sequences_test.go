@@ -51,6 +51,81 @@ func TestAccPostgresqlDataSourceSequences(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlDataSourceSequences_Details(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureSequenceDataType)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.orders (id serial, ref text)")
+					dbExecute(t, testConfig.connStr(dbName), "INSERT INTO test_schema.orders (ref) VALUES ('a'), ('b'), ('c')")
+					dbExecute(t, testConfig.connStr(dbName), "CREATE SEQUENCE test_schema.unused_seq")
+				},
+				Config: fmt.Sprintf(`
+				data "postgresql_sequences" "test" {
+					database = "%s"
+					schemas  = ["test_schema"]
+				}
+				`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.object_name", "orders_id_seq"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.data_type", "integer"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.owner", testConfig.Username),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.last_value", "3"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.readable", "true"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.owned_by_schema", "test_schema"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.owned_by_table", "orders"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.owned_by_column", "id"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.1.object_name", "unused_seq"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.1.last_value", "0"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.1.owned_by_table", ""),
+				),
+			},
+			{
+				// The sequences the connected role cannot read are returned without their last value
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), fmt.Sprintf("GRANT SELECT ON test_schema.orders_id_seq TO %s", roleName))
+				},
+				Config: fmt.Sprintf(`
+				provider "postgresql" {
+					username  = "%s"
+					password  = "%s"
+					superuser = false
+				}
+
+				data "postgresql_sequences" "test" {
+					database = "%s"
+					schemas  = ["test_schema"]
+				}
+				`, roleName, testRolePassword, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.object_name", "orders_id_seq"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.readable", "true"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.0.last_value", "3"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.1.object_name", "unused_seq"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.1.readable", "false"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.1.last_value", "0"),
+					resource.TestCheckResourceAttr("data.postgresql_sequences.test", "sequences.1.data_type", "bigint"),
+				),
+			},
+		},
+	})
+}
+
 func generateDataSourceSequencesConfig(dbName string) string {
 	return fmt.Sprintf(`	
 	data "postgresql_sequences" "test_schemas1and2" {
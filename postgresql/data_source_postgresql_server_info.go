@@ -0,0 +1,146 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+// defaultServerInfoSettings are the settings returned when setting_names is not set
+var defaultServerInfoSettings = []string{
+	"max_connections",
+	"server_encoding",
+	"shared_preload_libraries",
+	"timezone",
+	"wal_level",
+}
+
+func dataSourcePostgreSQLServerInfo() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLServerInfoRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The database to connect to, defaults to the database of the provider",
+			},
+			"setting_names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the settings to return in settings, a set of commonly used settings if not set",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the server (server_version)",
+			},
+			"version_num": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version of the server as an integer (server_version_num, e.g.: 160002)",
+			},
+			"is_in_recovery": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the server is a standby in recovery",
+			},
+			"current_database": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The database of the connection",
+			},
+			"current_user": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The user of the connection",
+			},
+			"data_directory": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The data directory of the server, empty if the user is not allowed to read it",
+			},
+			"settings": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The values of the requested settings readable by the user",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLServerInfoRead(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var version, currentDatabase, currentUser string
+	var versionNum int
+	var isInRecovery bool
+	if err := txn.QueryRow(
+		"SELECT pg_catalog.current_setting('server_version'), pg_catalog.current_setting('server_version_num')::int, "+
+			"pg_catalog.pg_is_in_recovery(), pg_catalog.current_database(), current_user",
+	).Scan(&version, &versionNum, &isInRecovery, &currentDatabase, &currentUser); err != nil {
+		return fmt.Errorf("could not read server information: %w", err)
+	}
+
+	settingNames := interfaceSliceToStrings(d.Get("setting_names").([]interface{}))
+	if len(settingNames) == 0 {
+		settingNames = defaultServerInfoSettings
+	}
+	// Setting names are case insensitive (e.g.: TimeZone)
+	names := []string{"data_directory"}
+	for _, name := range settingNames {
+		names = append(names, strings.ToLower(name))
+	}
+
+	// pg_settings only shows the settings the user is allowed to read (e.g.: data_directory
+	// requires pg_read_all_settings), so current_setting cannot fail on permissions.
+	rows, err := txn.Query(
+		"SELECT lower(name), pg_catalog.current_setting(name) FROM pg_catalog.pg_settings WHERE lower(name) = ANY($1)",
+		pq.Array(names),
+	)
+	if err != nil {
+		return fmt.Errorf("could not read server settings: %w", err)
+	}
+	defer rows.Close()
+
+	values := map[string]string{}
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return fmt.Errorf("could not scan server setting: %w", err)
+		}
+		values[name] = value
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	settings := map[string]interface{}{}
+	for _, name := range settingNames {
+		if value, ok := values[strings.ToLower(name)]; ok {
+			settings[name] = value
+		}
+	}
+
+	d.Set("database", database)
+	d.Set("version", version)
+	d.Set("version_num", versionNum)
+	d.Set("is_in_recovery", isInRecovery)
+	d.Set("current_database", currentDatabase)
+	d.Set("current_user", currentUser)
+	d.Set("data_directory", values["data_directory"])
+	d.Set("settings", settings)
+	d.SetId(database)
+
+	return nil
+}
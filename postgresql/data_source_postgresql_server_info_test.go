@@ -0,0 +1,40 @@
+package postgresql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceServerInfo(t *testing.T) {
+	skipIfNotAcc(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "postgresql_server_info" "default" {}
+
+				data "postgresql_server_info" "settings" {
+					database      = "postgres"
+					setting_names = ["TimeZone", "max_connections", "unknown.setting"]
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.postgresql_server_info.default", "version_num", regexp.MustCompile(`^[1-9][0-9]*$`)),
+					resource.TestMatchResourceAttr("data.postgresql_server_info.default", "version", regexp.MustCompile(`^[0-9]+`)),
+					resource.TestCheckResourceAttr("data.postgresql_server_info.default", "is_in_recovery", "false"),
+					resource.TestCheckResourceAttr("data.postgresql_server_info.default", "current_database", "postgres"),
+					resource.TestCheckResourceAttrSet("data.postgresql_server_info.default", "current_user"),
+					resource.TestCheckResourceAttrSet("data.postgresql_server_info.default", "settings.wal_level"),
+					resource.TestCheckResourceAttr("data.postgresql_server_info.settings", "settings.%", "2"),
+					resource.TestCheckResourceAttrSet("data.postgresql_server_info.settings", "settings.TimeZone"),
+					resource.TestMatchResourceAttr("data.postgresql_server_info.settings", "settings.max_connections", regexp.MustCompile(`^[0-9]+$`)),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,73 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePostgreSQLServerVersion() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLServerVersionRead),
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version used by the provider, detected from the server or set by expected_version (e.g.: 16.2.0)",
+			},
+			"version_num": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version used by the provider as an integer, in the format of server_version_num (e.g.: 160002)",
+			},
+			"server_flavor": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The kind of server detected from the version string: postgresql, aurora, cockroachdb or redshift",
+			},
+			"version_string": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The full version string of the server, as returned by version()",
+			},
+			"features": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeBool},
+				Description: "Whether each feature of the provider is supported by the version, indexed by feature name",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLServerVersionRead(db *DBConnection, d *schema.ResourceData) error {
+	// version() does not require any privilege
+	var versionString string
+	if err := db.QueryRow("SELECT pg_catalog.version()").Scan(&versionString); err != nil {
+		return fmt.Errorf("could not read server version: %w", err)
+	}
+
+	features := make(map[string]interface{}, len(featureNames))
+	for feature, name := range featureNames {
+		features[name] = db.featureSupported(feature)
+	}
+
+	d.Set("version", db.version.String())
+	d.Set("version_num", versionNum(db.version))
+	d.Set("server_flavor", string(db.serverFlavor))
+	d.Set("version_string", versionString)
+	d.Set("features", features)
+	d.SetId(db.version.String())
+
+	return nil
+}
+
+// versionNum returns the version in the format of server_version_num: the major version
+// and the minor version on 4 digits since PostgreSQL 10, or on 2 digits each before.
+func versionNum(version semver.Version) int {
+	if version.Major >= 10 {
+		return int(version.Major*10000 + version.Minor)
+	}
+	return int(version.Major*10000 + version.Minor*100 + version.Patch)
+}
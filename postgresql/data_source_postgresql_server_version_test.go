@@ -0,0 +1,58 @@
+package postgresql
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestVersionNum(t *testing.T) {
+	var cases = []struct {
+		version  string
+		expected int
+	}{
+		{"16.2.0", 160002},
+		{"10.0.0", 100000},
+		{"17.10.0", 170010},
+		{"9.6.7", 90607},
+		{"9.10.12", 91012},
+	}
+
+	for _, c := range cases {
+		if num := versionNum(semver.MustParse(c.version)); num != c.expected {
+			t.Errorf("versionNum(%s): expected %d, got %d", c.version, c.expected, num)
+		}
+	}
+}
+
+func TestAccPostgresqlDataSourceServerVersion(t *testing.T) {
+	skipIfNotAcc(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "postgresql_server_version" "test" {}
+
+				data "postgresql_server_info" "test" {}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.postgresql_server_version.test", "version"),
+					resource.TestMatchResourceAttr("data.postgresql_server_version.test", "version_string", regexp.MustCompile("^PostgreSQL ")),
+					resource.TestCheckResourceAttrPair(
+						"data.postgresql_server_version.test", "version_num",
+						"data.postgresql_server_info.test", "version_num",
+					),
+					resource.TestCheckResourceAttr("data.postgresql_server_version.test", "features.%", strconv.Itoa(len(featureNames))),
+					resource.TestCheckResourceAttr("data.postgresql_server_version.test", "features.create_role_with", "true"),
+					resource.TestCheckResourceAttrSet("data.postgresql_server_version.test", "features.identity_columns"),
+				),
+			},
+		},
+	})
+}
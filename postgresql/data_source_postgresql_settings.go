@@ -0,0 +1,232 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	settingQuery = `
+	SELECT name, setting, COALESCE(unit, ''), vartype, source, context, %s
+	FROM pg_catalog.pg_settings
+	`
+	settingPatternMatchingTarget = "name"
+	settingNameKeyword           = "name"
+)
+
+// settingUnitRegexp matches the units of pg_settings, which can be prefixed by a multiple (e.g.: 8kB)
+var settingUnitRegexp = regexp.MustCompile(`^(\d*)(B|kB|MB|GB|TB|us|ms|s|min|h|d)$`)
+
+// settingUnitFactors are the factors to the base unit of the settings: bytes for memory and milliseconds for time.
+var settingUnitFactors = map[string]float64{
+	"B":   1,
+	"kB":  1 << 10,
+	"MB":  1 << 20,
+	"GB":  1 << 30,
+	"TB":  1 << 40,
+	"us":  0.001,
+	"ms":  1,
+	"s":   1000,
+	"min": 60 * 1000,
+	"h":   60 * 60 * 1000,
+	"d":   24 * 60 * 60 * 1000,
+}
+
+func dataSourcePostgreSQLSettings() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLSettingsRead),
+		Schema: map[string]*schema.Schema{
+			"names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "The names of the settings to retrieve. Retrieves all settings by default",
+			},
+			"like_any_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against setting names in the query using the PostgreSQL LIKE ANY operator",
+			},
+			"like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against setting names in the query using the PostgreSQL LIKE ALL operator",
+			},
+			"not_like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against setting names in the query using the PostgreSQL NOT LIKE ALL operator",
+			},
+			"regex_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Expression which will be pattern matched against setting names in the query using the PostgreSQL ~ (regular expression match) operator",
+			},
+			"settings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"setting": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"unit": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"normalized_setting": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"normalized_unit": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vartype": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"context": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"pending_restart": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The list of settings retrieved by this data source, ordered by name",
+			},
+			"values": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The raw values of the retrieved settings, indexed by name",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLSettingsRead(db *DBConnection, d *schema.ResourceData) error {
+	pendingRestart := "false"
+	if db.featureSupported(featureAlterSystem) {
+		pendingRestart = "pending_restart"
+	}
+
+	// pg_settings is readable by every role, it only hides the values of a few settings
+	// (e.g.: data_directory) to the roles which are not members of pg_read_all_settings.
+	query := applySettingDataSourceQueryFilters(fmt.Sprintf(settingQuery, pendingRestart), queryConcatKeywordWhere, d)
+	query += " ORDER BY name"
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return fmt.Errorf("could not read settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := make([]interface{}, 0)
+	values := make(map[string]interface{})
+	for rows.Next() {
+		var name, setting, unit, vartype, source, context string
+		var pendingRestart bool
+
+		if err = rows.Scan(&name, &setting, &unit, &vartype, &source, &context, &pendingRestart); err != nil {
+			return fmt.Errorf("could not scan setting output: %w", err)
+		}
+
+		normalizedSetting, normalizedUnit := normalizeSettingValue(setting, unit)
+		settings = append(settings, map[string]interface{}{
+			"name":               name,
+			"setting":            setting,
+			"unit":               unit,
+			"normalized_setting": normalizedSetting,
+			"normalized_unit":    normalizedUnit,
+			"vartype":            vartype,
+			"source":             source,
+			"context":            context,
+			"pending_restart":    pendingRestart,
+		})
+		values[name] = setting
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("settings", settings)
+	d.Set("values", values)
+	d.SetId(generateDataSourceSettingsID(d))
+
+	return nil
+}
+
+// normalizeSettingValue converts the value of a setting in the unit of pg_settings to its base unit,
+// bytes for memory and milliseconds for time. Settings without unit are returned as is.
+func normalizeSettingValue(setting, unit string) (string, string) {
+	matches := settingUnitRegexp.FindStringSubmatch(unit)
+	if matches == nil {
+		return setting, unit
+	}
+
+	value, err := strconv.ParseFloat(setting, 64)
+	if err != nil {
+		return setting, unit
+	}
+
+	// -1 and 0 are special values (e.g.: disabled, or default) in most settings with a unit
+	if value > 0 {
+		multiple := 1.0
+		if matches[1] != "" {
+			multiple, _ = strconv.ParseFloat(matches[1], 64)
+		}
+		value *= multiple * settingUnitFactors[matches[2]]
+	}
+
+	baseUnit := "ms"
+	if strings.HasSuffix(matches[2], "B") {
+		baseUnit = "B"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64), baseUnit
+}
+
+func generateDataSourceSettingsID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		"settings",
+		generatePatternArrayString(d.Get("names").([]interface{}), queryArrayKeywordAny),
+		generatePatternArrayString(d.Get("like_any_patterns").([]interface{}), queryArrayKeywordAny),
+		generatePatternArrayString(d.Get("like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		generatePatternArrayString(d.Get("not_like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		d.Get("regex_pattern").(string),
+	}, "_")
+}
+
+func applySettingDataSourceQueryFilters(query string, queryConcatKeyword string, d *schema.ResourceData) string {
+	filters := []string{}
+	namesFilter := applyTypeMatchingToQuery(settingNameKeyword, d.Get("names").([]interface{}))
+	if len(namesFilter) > 0 {
+		filters = append(filters, namesFilter)
+	}
+	filters = append(filters, applyPatternMatchingToQuery(settingPatternMatchingTarget, d)...)
+
+	return finalizeQueryWithFilters(query, queryConcatKeyword, filters)
+}
@@ -0,0 +1,76 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestNormalizeSettingValue(t *testing.T) {
+	var cases = []struct {
+		setting, unit                 string
+		expectedSetting, expectedUnit string
+	}{
+		{"16384", "8kB", "134217728", "B"},
+		{"4096", "kB", "4194304", "B"},
+		{"64", "MB", "67108864", "B"},
+		{"1024", "B", "1024", "B"},
+		{"30", "s", "30000", "ms"},
+		{"60", "min", "3600000", "ms"},
+		{"2", "ms", "2", "ms"},
+		{"0.5", "ms", "0.5", "ms"},
+		{"500", "us", "0.5", "ms"},
+		{"-1", "ms", "-1", "ms"},
+		{"0", "kB", "0", "B"},
+		{"logical", "", "logical", ""},
+		{"100", "", "100", ""},
+	}
+
+	for _, c := range cases {
+		setting, unit := normalizeSettingValue(c.setting, c.unit)
+		if setting != c.expectedSetting || unit != c.expectedUnit {
+			t.Errorf("normalizeSettingValue(%q, %q): expected %q %q, got %q %q", c.setting, c.unit, c.expectedSetting, c.expectedUnit, setting, unit)
+		}
+	}
+}
+
+func TestAccPostgresqlDataSourceSettings(t *testing.T) {
+	skipIfNotAcc(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "postgresql_settings" "names" {
+					names = ["max_connections", "shared_buffers", "statement_timeout"]
+				}
+
+				data "postgresql_settings" "patterns" {
+					like_any_patterns = ["autovacuum%"]
+					regex_pattern     = "^autovacuum_(vacuum|analyze)_threshold$"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.#", "3"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.0.name", "max_connections"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.0.unit", ""),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.0.vartype", "integer"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.0.context", "postmaster"),
+					resource.TestCheckResourceAttrSet("data.postgresql_settings.names", "settings.0.setting"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.1.name", "shared_buffers"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.1.unit", "8kB"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.1.normalized_unit", "B"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.2.name", "statement_timeout"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.2.normalized_unit", "ms"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.names", "settings.2.pending_restart", "false"),
+					resource.TestCheckResourceAttrSet("data.postgresql_settings.names", "values.max_connections"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.patterns", "settings.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.patterns", "settings.0.name", "autovacuum_analyze_threshold"),
+					resource.TestCheckResourceAttr("data.postgresql_settings.patterns", "settings.1.name", "autovacuum_vacuum_threshold"),
+				),
+			},
+		},
+	})
+}
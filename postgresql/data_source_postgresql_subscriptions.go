@@ -0,0 +1,151 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	subscriptionPatternMatchingTarget = "s.subname"
+)
+
+func dataSourcePostgreSQLSubscriptions() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLSubscriptionsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The PostgreSQL database which will be queried for subscriptions",
+			},
+			"like_any_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against subscription names in the query using the PostgreSQL LIKE ANY operator",
+			},
+			"like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against subscription names in the query using the PostgreSQL LIKE ALL operator",
+			},
+			"not_like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against subscription names in the query using the PostgreSQL NOT LIKE ALL operator",
+			},
+			"regex_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Expression which will be pattern matched against subscription names in the query using the PostgreSQL ~ (regular expression match) operator",
+			},
+			"subscriptions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owner": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"publications": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"worker_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The list of PostgreSQL subscriptions retrieved by this data source",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLSubscriptionsRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureSubscription) {
+		return fmt.Errorf(
+			"postgresql_subscriptions data source is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := d.Get("database").(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// pg_subscription is shared across the cluster
+	query := `SELECT s.subname, pg_catalog.pg_get_userbyid(s.subowner), s.subenabled, s.subpublications, ` +
+		`(SELECT count(*) FROM pg_catalog.pg_stat_subscription ss WHERE ss.subid = s.oid AND ss.pid IS NOT NULL) ` +
+		`FROM pg_catalog.pg_subscription s ` +
+		`WHERE s.subdbid = (SELECT oid FROM pg_catalog.pg_database WHERE datname = pg_catalog.current_database())`
+	query = finalizeQueryWithFilters(query, queryConcatKeywordAnd, applyPatternMatchingToQuery(subscriptionPatternMatchingTarget, d))
+	query += " ORDER BY s.subname"
+
+	rows, err := txn.Query(query)
+	if err != nil {
+		return fmt.Errorf("could not read subscriptions of database %s: %w", database, err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]interface{}, 0)
+	for rows.Next() {
+		var name, owner string
+		var enabled bool
+		var publications []string
+		var workerCount int
+
+		if err = rows.Scan(&name, &owner, &enabled, pq.Array(&publications), &workerCount); err != nil {
+			return fmt.Errorf("could not scan subscription output for database: %w", err)
+		}
+
+		subscriptions = append(subscriptions, map[string]interface{}{
+			"name":         name,
+			"owner":        owner,
+			"enabled":      enabled,
+			"publications": publications,
+			"worker_count": workerCount,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("subscriptions", subscriptions)
+	d.SetId(generateDataSourceSubscriptionsID(d, database))
+
+	return nil
+}
+
+func generateDataSourceSubscriptionsID(d *schema.ResourceData, databaseName string) string {
+	return strings.Join([]string{
+		databaseName,
+		generatePatternArrayString(d.Get("like_any_patterns").([]interface{}), queryArrayKeywordAny),
+		generatePatternArrayString(d.Get("like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		generatePatternArrayString(d.Get("not_like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		d.Get("regex_pattern").(string),
+	}, "_")
+}
@@ -0,0 +1,84 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceSubscriptions(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffixPub, teardownPub := setupTestDatabase(t, true, true)
+	dbSuffixSub, teardownSub := setupTestDatabase(t, true, true)
+	defer teardownPub()
+	defer teardownSub()
+
+	testTables := []string{"test_schema.test_table_1"}
+	createTestTables(t, dbSuffixPub, testTables, "")
+	createTestTables(t, dbSuffixSub, testTables, "")
+
+	dbNamePub, _ := getTestDBNames(dbSuffixPub)
+	dbNameSub, _ := getTestDBNames(dbSuffixSub)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+			testCheckCompatibleVersion(t, featurePublication)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "postgresql_publication" "test_pub" {
+					name     = "ds_publication"
+					database = "%[1]s"
+					tables   = ["test_schema.test_table_1"]
+				}
+
+				resource "postgresql_subscription" "enabled" {
+					name         = "ds_subscription_enabled"
+					database     = "%[2]s"
+					conninfo     = "%[3]s"
+					publications = [postgresql_publication.test_pub.name]
+				}
+
+				resource "postgresql_subscription" "disabled" {
+					name         = "ds_subscription_disabled"
+					database     = "%[2]s"
+					conninfo     = "%[3]s"
+					publications = [postgresql_publication.test_pub.name]
+					enabled      = false
+				}
+
+				data "postgresql_subscriptions" "all" {
+					database = "%[2]s"
+
+					depends_on = [postgresql_subscription.enabled, postgresql_subscription.disabled]
+				}
+
+				data "postgresql_subscriptions" "disabled" {
+					database          = "%[2]s"
+					like_all_patterns = ["%%disabled"]
+
+					depends_on = [postgresql_subscription.enabled, postgresql_subscription.disabled]
+				}
+				`, dbNamePub, dbNameSub, getConnInfo(t, dbNamePub)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_subscriptions.all", "subscriptions.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_subscriptions.all", "subscriptions.1.name", "ds_subscription_enabled"),
+					resource.TestCheckResourceAttr("data.postgresql_subscriptions.all", "subscriptions.1.enabled", "true"),
+					resource.TestCheckResourceAttr("data.postgresql_subscriptions.all", "subscriptions.1.publications.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_subscriptions.all", "subscriptions.1.publications.0", "ds_publication"),
+					resource.TestCheckResourceAttr("data.postgresql_subscriptions.disabled", "subscriptions.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_subscriptions.disabled", "subscriptions.0.name", "ds_subscription_disabled"),
+					resource.TestCheckResourceAttr("data.postgresql_subscriptions.disabled", "subscriptions.0.enabled", "false"),
+					resource.TestCheckResourceAttr("data.postgresql_subscriptions.disabled", "subscriptions.0.worker_count", "0"),
+				),
+			},
+		},
+	})
+	coolDown()
+}
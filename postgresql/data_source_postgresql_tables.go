@@ -5,16 +5,22 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
 )
 
 const (
 	tableQuery = `
-	SELECT table_name, table_schema, table_type
+	SELECT table_name, table_schema, table_type, c.oid, pg_catalog.pg_get_userbyid(c.relowner), c.relkind::text,
+		c.reltuples::bigint, pg_catalog.pg_total_relation_size(c.oid), pg_catalog.pg_table_size(c.oid),
+		pg_catalog.pg_indexes_size(c.oid), %s
 	FROM information_schema.tables
+	JOIN pg_catalog.pg_namespace n ON n.nspname = table_schema
+	JOIN pg_catalog.pg_class c ON c.relnamespace = n.oid AND c.relname = table_name
 	`
 	tablePatternMatchingTarget = "table_name"
 	tableSchemaKeyword         = "table_schema"
 	tableTypeKeyword           = "table_type"
+	tableRelkindKeyword        = "c.relkind::text"
 )
 
 func dataSourcePostgreSQLDatabaseTables() *schema.Resource {
@@ -41,6 +47,13 @@ func dataSourcePostgreSQLDatabaseTables() *schema.Resource {
 				MinItems:    0,
 				Description: "The PostgreSQL table types which will be queried for table names. Includes all table types by default. Use 'BASE TABLE' for normal tables only",
 			},
+			"relkinds": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "The PostgreSQL relation kinds (pg_class.relkind) which will be queried for table names. Includes all relation kinds by default. Use 'r' and 'p' for normal and partitioned tables",
+			},
 			"like_any_patterns": {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -84,6 +97,54 @@ func dataSourcePostgreSQLDatabaseTables() *schema.Resource {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+						"owner": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"relkind": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"row_estimate": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"total_size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"table_size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"indexes_size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"rls_enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"partitions": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"object_name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"schema_name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"bound": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
 					},
 				},
 				Description: "The list of PostgreSQL tables retrieved by this data source. Note that this returns a set, so duplicate table names across different schemas will be consolidated.",
@@ -101,10 +162,15 @@ func dataSourcePostgreSQLTablesRead(db *DBConnection, d *schema.ResourceData) er
 	}
 	defer deferredRollback(txn)
 
-	query := tableQuery
+	rowSecurity := "false"
+	if db.featureSupported(featureRLS) {
+		rowSecurity = "c.relrowsecurity"
+	}
+	query := fmt.Sprintf(tableQuery, rowSecurity)
 	queryConcatKeyword := queryConcatKeywordWhere
 
 	query = applyTableDataSourceQueryFilters(query, queryConcatKeyword, d)
+	query += " ORDER BY table_schema, table_name"
 
 	rows, err := txn.Query(query)
 	if err != nil {
@@ -113,12 +179,20 @@ func dataSourcePostgreSQLTablesRead(db *DBConnection, d *schema.ResourceData) er
 	defer rows.Close()
 
 	tables := make([]interface{}, 0)
+	partitionedTables := map[int]map[string]interface{}{}
 	for rows.Next() {
 		var object_name string
 		var schema_name string
 		var table_type string
+		var oid int
+		var owner, relkind string
+		var rowEstimate, totalSize, tableSize, indexesSize int64
+		var rlsEnabled bool
 
-		if err = rows.Scan(&object_name, &schema_name, &table_type); err != nil {
+		if err = rows.Scan(
+			&object_name, &schema_name, &table_type, &oid, &owner, &relkind,
+			&rowEstimate, &totalSize, &tableSize, &indexesSize, &rlsEnabled,
+		); err != nil {
 			return fmt.Errorf("could not scan table output for database: %w", err)
 		}
 
@@ -126,7 +200,26 @@ func dataSourcePostgreSQLTablesRead(db *DBConnection, d *schema.ResourceData) er
 		result["object_name"] = object_name
 		result["schema_name"] = schema_name
 		result["table_type"] = table_type
+		result["owner"] = owner
+		result["relkind"] = relkind
+		result["row_estimate"] = rowEstimate
+		result["total_size"] = totalSize
+		result["table_size"] = tableSize
+		result["indexes_size"] = indexesSize
+		result["rls_enabled"] = rlsEnabled
+		result["partitions"] = []interface{}{}
 		tables = append(tables, result)
+
+		if relkind == "p" {
+			partitionedTables[oid] = result
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := readTablePartitions(txn, partitionedTables); err != nil {
+		return err
 	}
 
 	d.Set("tables", tables)
@@ -135,11 +228,54 @@ func dataSourcePostgreSQLTablesRead(db *DBConnection, d *schema.ResourceData) er
 	return nil
 }
 
+// readTablePartitions sets the partitions, with their bound, of the partitioned tables indexed by oid.
+func readTablePartitions(txn QueryAble, partitionedTables map[int]map[string]interface{}) error {
+	if len(partitionedTables) == 0 {
+		return nil
+	}
+
+	oids := make([]int64, 0, len(partitionedTables))
+	for oid := range partitionedTables {
+		oids = append(oids, int64(oid))
+	}
+
+	rows, err := txn.Query(
+		`SELECT i.inhparent, n.nspname, c.relname, COALESCE(pg_catalog.pg_get_expr(c.relpartbound, c.oid), '') `+
+			`FROM pg_catalog.pg_inherits i `+
+			`JOIN pg_catalog.pg_class c ON c.oid = i.inhrelid `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+			`WHERE i.inhparent = ANY($1) `+
+			`ORDER BY n.nspname, c.relname`,
+		pq.Array(oids),
+	)
+	if err != nil {
+		return fmt.Errorf("could not read table partitions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parent int
+		var schemaName, name, bound string
+		if err := rows.Scan(&parent, &schemaName, &name, &bound); err != nil {
+			return fmt.Errorf("could not scan table partition: %w", err)
+		}
+		table := partitionedTables[parent]
+		table["partitions"] = append(table["partitions"].([]interface{}), map[string]interface{}{
+			"object_name": name,
+			"schema_name": schemaName,
+			"bound":       bound,
+		})
+	}
+
+	return rows.Err()
+}
+
 func generateDataSourceTablesID(d *schema.ResourceData, databaseName string) string {
 	return strings.Join([]string{
 		databaseName,
 		generatePatternArrayString(d.Get("schemas").([]interface{}), queryArrayKeywordAny),
 		generatePatternArrayString(d.Get("table_types").([]interface{}), queryArrayKeywordAny),
+		generatePatternArrayString(d.Get("relkinds").([]interface{}), queryArrayKeywordAny),
 		generatePatternArrayString(d.Get("like_any_patterns").([]interface{}), queryArrayKeywordAny),
 		generatePatternArrayString(d.Get("like_all_patterns").([]interface{}), queryArrayKeywordAll),
 		generatePatternArrayString(d.Get("not_like_all_patterns").([]interface{}), queryArrayKeywordAll),
@@ -157,6 +293,10 @@ func applyTableDataSourceQueryFilters(query string, queryConcatKeyword string, d
 	if len(tableTypeFilter) > 0 {
 		filters = append(filters, tableTypeFilter)
 	}
+	relkindFilter := applyTypeMatchingToQuery(tableRelkindKeyword, d.Get("relkinds").([]interface{}))
+	if len(relkindFilter) > 0 {
+		filters = append(filters, relkindFilter)
+	}
 	filters = append(filters, applyPatternMatchingToQuery(tablePatternMatchingTarget, d)...)
 
 	return finalizeQueryWithFilters(query, queryConcatKeyword, filters)
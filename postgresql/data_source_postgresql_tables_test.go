@@ -130,3 +130,68 @@ func generateDataSourceTablesConfig(dbName string) string {
 
 	`, dbName)
 }
+
+func TestAccPostgresqlDataSourceTables_Details(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureDeclarativePartitioning)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.measures (id integer PRIMARY KEY, at date) PARTITION BY RANGE (id)")
+					dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.measures_1 PARTITION OF test_schema.measures FOR VALUES FROM (0) TO (100)")
+					dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.measures_2 PARTITION OF test_schema.measures FOR VALUES FROM (100) TO (200)")
+					dbExecute(t, testConfig.connStr(dbName), "INSERT INTO test_schema.measures SELECT generate_series(0, 199), now()")
+					dbExecute(t, testConfig.connStr(dbName), "ALTER TABLE test_schema.measures_1 ENABLE ROW LEVEL SECURITY")
+					dbExecute(t, testConfig.connStr(dbName), "ANALYZE test_schema.measures_1")
+				},
+				Config: fmt.Sprintf(`
+				data "postgresql_tables" "partitioned" {
+					database = "%[1]s"
+					schemas  = ["test_schema"]
+					relkinds = ["p"]
+				}
+
+				data "postgresql_tables" "partitions" {
+					database          = "%[1]s"
+					schemas           = ["test_schema"]
+					relkinds          = ["r"]
+					like_any_patterns = ["measures_%%"]
+				}
+				`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitioned", "tables.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitioned", "tables.0.object_name", "measures"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitioned", "tables.0.relkind", "p"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitioned", "tables.0.owner", testConfig.Username),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitioned", "tables.0.partitions.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitioned", "tables.0.partitions.0.object_name", "measures_1"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitioned", "tables.0.partitions.0.schema_name", "test_schema"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitioned", "tables.0.partitions.0.bound", "FOR VALUES FROM (0) TO (100)"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitioned", "tables.0.partitions.1.object_name", "measures_2"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitions", "tables.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitions", "tables.0.object_name", "measures_1"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitions", "tables.0.relkind", "r"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitions", "tables.0.rls_enabled", "true"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitions", "tables.0.row_estimate", "100"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitions", "tables.0.partitions.#", "0"),
+					resource.TestCheckResourceAttrSet("data.postgresql_tables.partitions", "tables.0.total_size"),
+					resource.TestCheckResourceAttrSet("data.postgresql_tables.partitions", "tables.0.table_size"),
+					resource.TestCheckResourceAttrSet("data.postgresql_tables.partitions", "tables.0.indexes_size"),
+					resource.TestCheckResourceAttr("data.postgresql_tables.partitions", "tables.1.rls_enabled", "false"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,163 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	viewQuery = `
+	SELECT c.relname, n.nspname, pg_catalog.pg_get_userbyid(c.relowner),
+		pg_catalog.md5(COALESCE(pg_catalog.pg_get_viewdef(c.oid), '')), c.relkind = 'm'
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE c.relkind IN ('v', 'm')
+	`
+	viewPatternMatchingTarget = "c.relname"
+	viewSchemaKeyword         = "n.nspname"
+)
+
+func dataSourcePostgreSQLDatabaseViews() *schema.Resource {
+	return &schema.Resource{
+		Read: PGResourceFunc(dataSourcePostgreSQLViewsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The PostgreSQL database which will be queried for view names",
+			},
+			"schemas": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "The PostgreSQL schema(s) which will be queried for view names. Queries all schemas in the database by default",
+			},
+			"like_any_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against view names in the query using the PostgreSQL LIKE ANY operator",
+			},
+			"like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against view names in the query using the PostgreSQL LIKE ALL operator",
+			},
+			"not_like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    0,
+				Description: "Expression(s) which will be pattern matched against view names in the query using the PostgreSQL NOT LIKE ALL operator",
+			},
+			"regex_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Expression which will be pattern matched against view names in the query using the PostgreSQL ~ (regular expression match) operator",
+			},
+			"views": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"object_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"schema_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owner": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"definition_hash": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_materialized": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The list of PostgreSQL views and materialized views retrieved by this data source",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLViewsRead(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get("database").(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := applyViewDataSourceQueryFilters(viewQuery, queryConcatKeywordAnd, d)
+	query += " ORDER BY n.nspname, c.relname"
+
+	rows, err := txn.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	views := make([]interface{}, 0)
+	for rows.Next() {
+		var objectName, schemaName, owner, definitionHash string
+		var isMaterialized bool
+
+		if err = rows.Scan(&objectName, &schemaName, &owner, &definitionHash, &isMaterialized); err != nil {
+			return fmt.Errorf("could not scan view output for database: %w", err)
+		}
+
+		views = append(views, map[string]interface{}{
+			"object_name":     objectName,
+			"schema_name":     schemaName,
+			"owner":           owner,
+			"definition_hash": definitionHash,
+			"is_materialized": isMaterialized,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("views", views)
+	d.SetId(generateDataSourceViewsID(d, database))
+
+	return nil
+}
+
+func generateDataSourceViewsID(d *schema.ResourceData, databaseName string) string {
+	return strings.Join([]string{
+		databaseName,
+		generatePatternArrayString(d.Get("schemas").([]interface{}), queryArrayKeywordAny),
+		generatePatternArrayString(d.Get("like_any_patterns").([]interface{}), queryArrayKeywordAny),
+		generatePatternArrayString(d.Get("like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		generatePatternArrayString(d.Get("not_like_all_patterns").([]interface{}), queryArrayKeywordAll),
+		d.Get("regex_pattern").(string),
+	}, "_")
+}
+
+func applyViewDataSourceQueryFilters(query string, queryConcatKeyword string, d *schema.ResourceData) string {
+	filters := []string{}
+	schemasTypeFilter := applyTypeMatchingToQuery(viewSchemaKeyword, d.Get("schemas").([]interface{}))
+	if len(schemasTypeFilter) > 0 {
+		filters = append(filters, schemasTypeFilter)
+	}
+	filters = append(filters, applyPatternMatchingToQuery(viewPatternMatchingTarget, d)...)
+
+	return finalizeQueryWithFilters(query, queryConcatKeyword, filters)
+}
@@ -0,0 +1,60 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceViews(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.orders (id integer, amount integer)")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE VIEW test_schema.big_orders AS SELECT * FROM test_schema.orders WHERE amount > 1000")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE VIEW test_schema.small_orders AS SELECT * FROM test_schema.orders WHERE amount < 10")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE MATERIALIZED VIEW test_schema.order_totals AS SELECT sum(amount) FROM test_schema.orders")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureMaterializedView)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				data "postgresql_views" "test_schema" {
+					database = "%[1]s"
+					schemas  = ["test_schema"]
+				}
+
+				data "postgresql_views" "orders" {
+					database          = "%[1]s"
+					schemas           = ["test_schema"]
+					like_any_patterns = ["%%_orders"]
+					regex_pattern     = "^big"
+				}
+				`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_views.test_schema", "views.#", "3"),
+					resource.TestCheckResourceAttr("data.postgresql_views.test_schema", "views.0.object_name", "big_orders"),
+					resource.TestCheckResourceAttr("data.postgresql_views.test_schema", "views.0.schema_name", "test_schema"),
+					resource.TestCheckResourceAttr("data.postgresql_views.test_schema", "views.0.owner", testConfig.Username),
+					resource.TestCheckResourceAttr("data.postgresql_views.test_schema", "views.0.is_materialized", "false"),
+					resource.TestCheckResourceAttrSet("data.postgresql_views.test_schema", "views.0.definition_hash"),
+					resource.TestCheckResourceAttr("data.postgresql_views.test_schema", "views.1.object_name", "order_totals"),
+					resource.TestCheckResourceAttr("data.postgresql_views.test_schema", "views.1.is_materialized", "true"),
+					resource.TestCheckResourceAttr("data.postgresql_views.test_schema", "views.2.object_name", "small_orders"),
+					resource.TestCheckResourceAttr("data.postgresql_views.orders", "views.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_views.orders", "views.0.object_name", "big_orders"),
+				),
+			},
+		},
+	})
+}
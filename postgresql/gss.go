@@ -0,0 +1,138 @@
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	"github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/lib/pq"
+)
+
+// gssEncModes are the values of gss_enc_mode. lib/pq cannot negotiate a GSSAPI encrypted connection,
+// so require is rejected and prefer falls back to the connection without GSSAPI encryption, as libpq
+// does when the server does not support it.
+var gssEncModes = []string{"disable", "prefer", "require"}
+
+// checkGSSEncMode returns an explicit error for the gss_enc_mode lib/pq cannot honour.
+func checkGSSEncMode(gssEncMode string) error {
+	if gssEncMode == "require" {
+		return errors.New(`gss_enc_mode "require" is not supported: lib/pq cannot negotiate a GSSAPI encrypted connection, ` +
+			`set gss_enc_mode to "disable" or "prefer" and use sslmode to encrypt the connection`)
+	}
+	return nil
+}
+
+// configureGSS registers the Kerberos GSSAPI provider in lib/pq, which has none by default: without it,
+// the servers asking for a GSSAPI authentication fail with "no GSSAPI provider registered".
+func configureGSS() error {
+	if err := checkKerberosCredentialCache(); err != nil {
+		return err
+	}
+
+	pq.RegisterGSSProvider(newKerberosGSS)
+	return nil
+}
+
+// kerberosGSS authenticates with Kerberos tickets of the credential cache,
+// as the provider of github.com/lib/pq/auth/kerberos.
+type kerberosGSS struct {
+	client *client.Client
+}
+
+// newKerberosGSS returns a GSSAPI provider using the Kerberos configuration (KRB5_CONFIG or /etc/krb5.conf)
+// and the credential cache (KRB5CCNAME or the default file cache).
+func newKerberosGSS() (pq.GSS, error) {
+	configPath := os.Getenv("KRB5_CONFIG")
+	if configPath == "" {
+		configPath = "/etc/krb5.conf"
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the Kerberos configuration %s: %w", configPath, err)
+	}
+
+	ccachePath := kerberosCredentialCachePath()
+	if ccachePath == "" {
+		return nil, fmt.Errorf("the Kerberos credential cache %s is not supported, only file caches are", os.Getenv("KRB5CCNAME"))
+	}
+	ccache, err := credentials.LoadCCache(ccachePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the Kerberos credential cache %s: %w", ccachePath, err)
+	}
+
+	cl, err := client.NewFromCCache(ccache, cfg, client.DisablePAFXFAST(true))
+	if err != nil {
+		return nil, fmt.Errorf("could not create the Kerberos client: %w", err)
+	}
+
+	return &kerberosGSS{client: cl}, nil
+}
+
+// GetInitToken returns the initial token of the authentication to the service of the host,
+// e.g.: postgres/db.example.com.
+func (g *kerberosGSS) GetInitToken(host string, service string) ([]byte, error) {
+	if g.client.Config.LibDefaults.DNSCanonicalizeHostname {
+		canonical, err := canonicalizeHostname(host)
+		if err != nil {
+			return nil, err
+		}
+		host = canonical
+	}
+
+	return g.GetInitTokenFromSpn(service + "/" + host)
+}
+
+// GetInitTokenFromSpn returns the initial token of the authentication to the service principal name.
+func (g *kerberosGSS) GetInitTokenFromSpn(spn string) ([]byte, error) {
+	token, err := spnego.SPNEGOClient(g.client, spn).InitSecContext()
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize the Kerberos security context for %s: %w", spn, err)
+	}
+
+	b, err := token.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal the Kerberos token for %s: %w", spn, err)
+	}
+	return b, nil
+}
+
+// Continue checks the response of the server, the authentication is complete after the initial token.
+func (g *kerberosGSS) Continue(inToken []byte) (bool, []byte, error) {
+	var token spnego.SPNEGOToken
+	if err := token.Unmarshal(inToken); err != nil {
+		return true, nil, fmt.Errorf("could not unmarshal the Kerberos token of the server: %w", err)
+	}
+	if !token.Resp {
+		return true, nil, errors.New("the Kerberos token of the server is not a response")
+	}
+	if state := token.NegTokenResp.State(); state != spnego.NegStateAcceptCompleted {
+		return true, nil, fmt.Errorf("the Kerberos authentication has not been accepted by the server (state %d)", state)
+	}
+
+	return true, nil, nil
+}
+
+// canonicalizeHostname returns the canonical name of the host, as the Kerberos libraries
+// do to build the service principal name when dns_canonicalize_hostname is set.
+func canonicalizeHostname(host string) (string, error) {
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return host, nil
+	}
+
+	names, err := net.LookupAddr(addrs[0])
+	if err != nil || len(names) == 0 {
+		// Without reverse DNS, the name given is used
+		return host, nil
+	}
+	return strings.TrimSuffix(names[0], "."), nil
+}
@@ -1,39 +1,219 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/lib/pq"
 )
 
 func PGResourceFunc(fn func(*DBConnection, *schema.ResourceData) error) func(*schema.ResourceData, interface{}) error {
 	return func(d *schema.ResourceData, meta interface{}) error {
-		client := meta.(*Client)
+		client := meta.(*Client).withStatementLogging(context.Background(), d)
 
 		db, err := client.Connect()
 		if err != nil {
 			return err
 		}
 
-		return fn(db, d)
+		return withResourceID(fn(db, d), d)
 	}
 }
 
+// PGResourceContextFunc is the context aware version of PGResourceFunc, used by the
+// resources supporting the timeouts block: the deadline set by Terraform from the
+// resource timeouts is applied to every statement run by fn.
+func PGResourceContextFunc(fn func(*DBConnection, *schema.ResourceData) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		client := meta.(*Client).withStatementLogging(ctx, d)
+
+		db, err := client.Connect()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := withResourceID(fn(db, d), d); err != nil {
+			var catalogErr *catalogReadError
+			if errors.As(err, &catalogErr) && !client.config.StrictCatalogReads {
+				return diag.Diagnostics{{
+					Severity: diag.Warning,
+					Summary:  "Could not read a system catalog, the state is kept",
+					Detail: fmt.Sprintf(
+						"%v. The values of the state have not been refreshed, grant the connected role the SELECT privilege on the catalog "+
+							"or set strict_catalog_reads in the provider configuration to fail instead.", err,
+					),
+				}}
+			}
+			if isTimeoutError(ctx, err) {
+				return diag.Errorf("%v: the operation exceeded its timeout, it can be increased with the timeouts block of the resource", err)
+			}
+			return diag.FromErr(err)
+		}
+		return nil
+	}
+}
+
+// isTimeoutError returns true if err has been caused by the deadline of ctx.
+func isTimeoutError(ctx context.Context, err error) bool {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pqErr *pq.Error
+	// query_canceled is returned when the statement_timeout is exceeded
+	return errors.As(err, &pqErr) && pqErr.Code == "57014" && strings.Contains(pqErr.Message, "statement timeout")
+}
+
+// isBadConnectionError returns true if err has been caused by a broken connection: the backend has
+// been terminated (e.g.: with pg_terminate_backend or by a failover), or the connection has been closed
+// by the server or a proxy. The statement may have been run before the connection was broken, only
+// the statements which can be run again are retried on these errors.
+func isBadConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isStatementNotRunError(err) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var pqErr *pq.Error
+	// connection_exception
+	return errors.As(err, &pqErr) && pqErr.Code.Class() == "08"
+}
+
+// isStatementNotRunError returns true if err tells the connection was broken before the statement
+// has had any effect: the driver did not send it, or the backend was terminated, rolling it back.
+func isStatementNotRunError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code {
+	// admin_shutdown, crash_shutdown, cannot_connect_now
+	case "57P01", "57P02", "57P03":
+		return true
+	}
+	return false
+}
+
+// isPermanentConnectionError returns true if the server rejected the connection because of the
+// configuration of the provider, e.g.: a wrong password or a database which does not exist.
+func isPermanentConnectionError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	// invalid_authorization_specification, invalid_password
+	if pqErr.Code.Class() == "28" {
+		return true
+	}
+	switch pqErr.Code {
+	// invalid_catalog_name, insufficient_privilege (e.g.: no CONNECT privilege on the database)
+	case "3D000", "42501":
+		return true
+	}
+	return false
+}
+
+// cancelledStatementError names the statement in err if it has been cancelled because the
+// deadline of ctx has been exceeded, to tell the user which statement the timeout interrupted.
+func cancelledStatementError(ctx context.Context, statement string, err error) error {
+	if err == nil || !isTimeoutError(ctx, err) {
+		return err
+	}
+	return fmt.Errorf("statement `%s` cancelled: %w", sqlStatementSummary(statement), err)
+}
+
+// catalogReadError is returned by the reads which could not query a system catalog the connected
+// role is not allowed to read, as on the hardened clusters revoking the access to some catalogs.
+// Unless strict_catalog_reads is set, the read keeps the state and reports a warning.
+type catalogReadError struct {
+	catalog string
+	err     error
+}
+
+func (e *catalogReadError) Error() string {
+	if e.catalog == "" {
+		return fmt.Sprintf("the connected role cannot read a system catalog: %v", e.err)
+	}
+	return fmt.Sprintf("the connected role cannot read the catalog %s: %v", e.catalog, e.err)
+}
+
+func (e *catalogReadError) Unwrap() error {
+	return e.err
+}
+
+var permissionDeniedRelation = regexp.MustCompile(`permission denied for (?:table|relation|view) (\S+)`)
+
+// asCatalogReadError returns err as a catalogReadError if it is a permission denied (insufficient_privilege) error.
+func asCatalogReadError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "42501" {
+		return err
+	}
+	var catalog string
+	if match := permissionDeniedRelation.FindStringSubmatch(pqErr.Message); match != nil {
+		catalog = match[1]
+	}
+	return &catalogReadError{catalog: catalog, err: err}
+}
+
+// isObjectNotFoundError returns true if err reports that the object, or the database
+// or schema containing it, does not exist. Any other error (e.g.: the server cannot be
+// reached) does not tell anything about the object and must be returned to the user.
+func isObjectNotFoundError(err error) bool {
+	var notFound *databaseNotFoundError
+	if errors.As(err, &notFound) {
+		return true
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code {
+	// invalid_catalog_name, invalid_schema_name, undefined_table, undefined_object, undefined_function
+	case "3D000", "3F000", "42P01", "42704", "42883":
+		return true
+	}
+	return false
+}
+
 func PGResourceExistsFunc(fn func(*DBConnection, *schema.ResourceData) (bool, error)) func(*schema.ResourceData, interface{}) (bool, error) {
 	return func(d *schema.ResourceData, meta interface{}) (bool, error) {
-		client := meta.(*Client)
+		client := meta.(*Client).withStatementLogging(context.Background(), d)
 
 		db, err := client.Connect()
 		if err != nil {
 			return false, err
 		}
 
-		return fn(db, d)
+		exists, err := fn(db, d)
+		err = withResourceID(err, d)
+		var catalogErr *catalogReadError
+		if errors.As(err, &catalogErr) && !client.config.StrictCatalogReads {
+			// The read reports the warning, the state is kept
+			log.Printf("[WARN] could not check whether %s exists: %v", d.Id(), err)
+			return true, nil
+		}
+		if err != nil && isObjectNotFoundError(err) {
+			log.Printf("[WARN] PostgreSQL object %s not found: %v", d.Id(), err)
+			return false, nil
+		}
+		return exists, err
 	}
 }
 
@@ -122,6 +302,21 @@ func revokeRoleMembership(db QueryAble, role, member string) (bool, error) {
 	return true, nil
 }
 
+// ownerMembershipError explains the errors caused by a missing privilege (42501) of the connected user
+// on the owner role, requirement describing what the connected user needs on it.
+func ownerMembershipError(err error, owner, currentUser, requirement string) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "42501" {
+		return err
+	}
+	return fmt.Errorf(
+		"the connected user %[2]s %[3]s. "+
+			"When the connected user is not a superuser (e.g. on AWS RDS), run `GRANT %[1]s TO %[2]s` "+
+			"or create the role %[1]s with the connected user: %[4]w",
+		pq.QuoteIdentifier(owner), pq.QuoteIdentifier(currentUser), requirement, err,
+	)
+}
+
 // withRolesGranted temporarily grants, if needed, the roles specified to connected user
 // (i.e.: the admin configure in the provider) and revoke them as soon as the
 // callback func has finished.
@@ -233,6 +428,14 @@ func sliceContainsStr(haystack []string, needle string) bool {
 	return false
 }
 
+func interfaceSliceToStrings(values []interface{}) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, value.(string))
+	}
+	return strs
+}
+
 // allowedPrivileges is the list of privileges allowed per object types in Postgres.
 // see: https://www.postgresql.org/docs/current/sql-grant.html
 var allowedPrivileges = map[string][]string{
@@ -246,25 +449,30 @@ var allowedPrivileges = map[string][]string{
 	"type":                 {"ALL", "USAGE"},
 	"foreign_data_wrapper": {"ALL", "USAGE"},
 	"foreign_server":       {"ALL", "USAGE"},
+	"language":             {"ALL", "USAGE"},
 	"column":               {"ALL", "SELECT", "INSERT", "UPDATE", "REFERENCES"},
 }
 
 // validatePrivileges checks that privileges to apply are allowed for this object type.
 func validatePrivileges(d *schema.ResourceData) error {
-	objectType := d.Get("object_type").(string)
-	privileges := d.Get("privileges").(*schema.Set).List()
+	return checkPrivileges(d.Get("object_type").(string), d.Get("privileges").(*schema.Set).List())
+}
 
-	allowed, ok := allowedPrivileges[objectType]
-	if !ok {
-		return fmt.Errorf("unknown object type %s", objectType)
+// normalizeAllPrivileges returns the configured privileges if they contain ALL and the privileges
+// read from the catalog cover every privilege of the object type, as Postgres stores ALL as the
+// enumerated privileges. Otherwise the privileges read are returned.
+func normalizeAllPrivileges(objectType string, configured, read *schema.Set) *schema.Set {
+	if !configured.Contains("ALL") {
+		return read
 	}
 
-	for _, priv := range privileges {
-		if !sliceContainsStr(allowed, priv.(string)) {
-			return fmt.Errorf("%s is not an allowed privilege for object type %s", priv, objectType)
+	// The privileges read can contain privileges added in newer Postgres versions (e.g.: MAINTAIN on tables)
+	for _, privilege := range allowedPrivileges[objectType] {
+		if privilege != "ALL" && !read.Contains(privilege) {
+			return read
 		}
 	}
-	return nil
+	return configured
 }
 
 func pgArrayToSet(arr pq.ByteaArray) *schema.Set {
@@ -303,53 +511,421 @@ func quoteIdentifyIdent(ident string) string {
 	return fmt.Sprintf("%s%s", pq.QuoteIdentifier(s[0]), functionArgTypes)
 }
 
+// sortedSetStrings returns the elements of a set of strings sorted, so the statements generated
+// from a set and their debug logs are the same between runs.
+func sortedSetStrings(set *schema.Set) []string {
+	values := make([]string, set.Len())
+	for i, value := range set.List() {
+		values[i] = value.(string)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// listInStateOrder returns the values of the list attribute in the order of the state when they
+// are the same elements as the values read, in the order of the catalog, so a list whose order
+// is not significant is not reported as changed.
+func listInStateOrder(d *schema.ResourceData, attr string, values []string) []string {
+	state, ok := d.Get(attr).([]interface{})
+	if !ok || len(state) != len(values) {
+		return values
+	}
+
+	counts := make(map[string]int, len(values))
+	for _, value := range values {
+		counts[value]++
+	}
+	ordered := make([]string, len(state))
+	for i, value := range state {
+		v, _ := value.(string)
+		if counts[v] == 0 {
+			return values
+		}
+		counts[v]--
+		ordered[i] = v
+	}
+	return ordered
+}
+
 func setToPgIdentList(schema string, idents *schema.Set) string {
-	quotedIdents := make([]string, idents.Len())
-	for i, ident := range idents.List() {
+	quotedIdents := sortedSetStrings(idents)
+	for i, ident := range quotedIdents {
 		quotedIdents[i] = fmt.Sprintf(
 			"%s.%s",
-			pq.QuoteIdentifier(schema), quoteIdentifyIdent(ident.(string)),
+			pq.QuoteIdentifier(schema), quoteIdentifyIdent(ident),
 		)
 	}
 	return strings.Join(quotedIdents, ",")
 }
 
 func setToPgIdentListWithoutSchema(idents *schema.Set) string {
-	quotedIdents := make([]string, idents.Len())
-	for i, ident := range idents.List() {
-		quotedIdents[i] = pq.QuoteIdentifier(ident.(string))
+	quotedIdents := sortedSetStrings(idents)
+	for i, ident := range quotedIdents {
+		quotedIdents[i] = pq.QuoteIdentifier(ident)
 	}
 	return strings.Join(quotedIdents, ",")
 }
 
 func setToPgIdentSimpleList(idents *schema.Set) string {
-	quotedIdents := make([]string, idents.Len())
-	for i, ident := range idents.List() {
-		quotedIdents[i] = ident.(string)
+	return strings.Join(sortedSetStrings(idents), ",")
+}
+
+var (
+	// existingDatabasesLock protects existingDatabases, the connection strings of the
+	// databases known to exist. A database missing at some point of an apply may be
+	// created later by postgresql_database, so only the existing ones are cached.
+	existingDatabasesLock sync.Mutex
+	existingDatabases     = map[string]bool{}
+)
+
+// databaseNotFoundError is returned when connecting to a database which does not exist.
+type databaseNotFoundError struct {
+	database string
+	// referencedBy is the ID of the resource using the database, if known
+	referencedBy string
+}
+
+func (e *databaseNotFoundError) Error() string {
+	hint := "check the database attribute, or if the database is created with postgresql_database, " +
+		"reference it (or use depends_on) so it is created first"
+	if e.referencedBy != "" {
+		return fmt.Sprintf("database %s referenced by %s does not exist; %s", e.database, e.referencedBy, hint)
 	}
-	return strings.Join(quotedIdents, ",")
+	return fmt.Sprintf("database %s does not exist; %s", e.database, hint)
 }
 
-// startTransaction starts a new DB transaction on the specified database.
+// asDatabaseNotFoundError returns a databaseNotFoundError if err is the error of a connection of client
+// to a database which does not exist (e.g.: dropped since its existence has been checked), err otherwise.
+func asDatabaseNotFoundError(client *Client, err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "3D000" {
+		return err
+	}
+
+	existingDatabasesLock.Lock()
+	delete(existingDatabases, client.config.connStr(client.databaseName))
+	existingDatabasesLock.Unlock()
+
+	return &databaseNotFoundError{database: client.databaseName}
+}
+
+// withResourceID replaces an error caused by a databaseNotFoundError by the same error
+// naming the resource which has tried to connect to the database.
+func withResourceID(err error, d *schema.ResourceData) error {
+	var notFound *databaseNotFoundError
+	if d.Id() != "" && errors.As(err, &notFound) {
+		return &databaseNotFoundError{database: notFound.database, referencedBy: d.Id()}
+	}
+	return err
+}
+
+// ensureDatabaseExists returns a databaseNotFoundError if database does not exist. It is checked
+// with the connection of client, as the error of a connection to a missing database doesn't
+// tell which database was missing.
+func ensureDatabaseExists(client *Client, database string) error {
+	key := client.config.connStr(database)
+
+	existingDatabasesLock.Lock()
+	known := existingDatabases[key]
+	existingDatabasesLock.Unlock()
+	if known {
+		return nil
+	}
+
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &databaseNotFoundError{database: database}
+	}
+
+	existingDatabasesLock.Lock()
+	existingDatabases[key] = true
+	existingDatabasesLock.Unlock()
+
+	return nil
+}
+
+// connectDatabase returns a connection to the specified database.
 // If the database is specified and different from the one configured in the provider,
 // it will create a new connection pool if needed.
-func startTransaction(client *Client, database string) (*sql.Tx, error) {
+func connectDatabase(client *Client, database string) (*DBConnection, error) {
 	if database != "" && database != client.databaseName {
-		client = client.config.NewClient(database)
+		if err := ensureDatabaseExists(client, database); err != nil {
+			return nil, err
+		}
+		client = client.withDatabase(database)
 	}
+
 	db, err := client.Connect()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("could not connect to database %s: %w", client.databaseName, asDatabaseNotFoundError(client, err))
 	}
+	return db, nil
+}
 
-	txn, err := db.Begin()
+// startTransaction starts a new DB transaction on the specified database.
+// If the database is specified and different from the one configured in the provider,
+// it will create a new connection pool if needed.
+func startTransaction(client *Client, database string) (*sql.Tx, error) {
+	db, err := connectDatabase(client, database)
 	if err != nil {
-		return nil, fmt.Errorf("could not start transaction: %w", err)
+		return nil, err
+	}
+	client = db.client
+
+	ctx := client.context()
+	txn, err := db.BeginTx(ctx, nil)
+	if isBadConnectionError(err) {
+		// Nothing has been run yet, the transaction can be started on a new connection. A
+		// connection broken later in the transaction fails the operation as its statements
+		// have been rolled back.
+		if db, err = db.reconnect(err); err == nil {
+			txn, err = db.BeginTx(ctx, nil)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction on database %s: %w", client.databaseName, asDatabaseNotFoundError(client, err))
+	}
+
+	// SET TRANSACTION must be executed before any query of the transaction
+	if level := client.config.IsolationLevel; level != "" {
+		if _, err := txn.Exec(fmt.Sprintf("SET TRANSACTION ISOLATION LEVEL %s", level)); err != nil {
+			deferredRollback(txn)
+			return nil, fmt.Errorf("could not set transaction isolation level: %w", err)
+		}
+	}
+
+	// The transaction context is only checked between statements, the statement timeout
+	// makes the server cancel a statement still running when the deadline is exceeded.
+	if timeout := statementTimeout(ctx); timeout > 0 {
+		if _, err := txn.Exec(fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout)); err != nil {
+			deferredRollback(txn)
+			return nil, fmt.Errorf("could not set statement timeout: %w", err)
+		}
 	}
 
 	return txn, nil
 }
 
+// setLocalStatementTimeout overrides the statement timeout of the transaction for a resource
+// with statements longer than the usual ones, without exceeding the deadline of its operation.
+func setLocalStatementTimeout(client *Client, txn *sql.Tx, timeout int64) error {
+	if timeout <= 0 {
+		return nil
+	}
+	if left := statementTimeout(client.context()); left > 0 && left < timeout {
+		timeout = left
+	}
+
+	if _, err := txn.Exec(fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout)); err != nil {
+		return fmt.Errorf("could not set statement timeout: %w", err)
+	}
+	return nil
+}
+
+// statementTimeout returns the time left, in milliseconds, before the deadline of the
+// context or 0 if it has no deadline.
+func statementTimeout(ctx context.Context) int64 {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+	if timeout := time.Until(deadline).Milliseconds(); timeout > 0 {
+		return timeout
+	}
+	// 0 would disable the timeout
+	return 1
+}
+
+// statementErrorAction tells execStatements what to do when a statement fails.
+type statementErrorAction int
+
+const (
+	// abortStatements returns the error, the caller rolling back the whole transaction.
+	abortStatements statementErrorAction = iota
+	// skipStatement rolls back the failed statement only and executes the next statements.
+	skipStatement
+)
+
+// statementErrorClassifier classifies the error of a statement run by execStatements.
+type statementErrorClassifier func(statement string, err error) statementErrorAction
+
+// skipMissingObjects skips the statements failing because an object they reference does not exist.
+func skipMissingObjects(statement string, err error) statementErrorAction {
+	if isObjectNotFoundError(err) {
+		return skipStatement
+	}
+	return abortStatements
+}
+
+// skipExistingObjects skips the statements failing because the object they create already exists.
+func skipExistingObjects(statement string, err error) statementErrorAction {
+	if isObjectExistsError(err) {
+		return skipStatement
+	}
+	return abortStatements
+}
+
+// isObjectExistsError returns true if err reports that the object created already exists.
+func isObjectExistsError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code {
+	// duplicate_database, duplicate_function, duplicate_schema, duplicate_table, duplicate_object
+	case "42P04", "42723", "42P06", "42P07", "42710":
+		return true
+	}
+	return false
+}
+
+// adoptExistingAttr is the attribute of the resources creating objects telling whether an object
+// which already exists is managed as if it had been created, or is an error.
+const adoptExistingAttr = "adopt_existing"
+
+// adoptExistingSchema returns the schema of the adoptExistingAttr attribute of a resource creating objects.
+// It has no default as the resources which used the existing objects keep doing so when it is not set.
+func adoptExistingSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "When true, an existing object is managed and updated to match the configuration, otherwise the creation fails",
+	}
+}
+
+// adoptExisting returns the adoptExistingAttr attribute of the resource, or defaultValue when it is not set.
+func adoptExisting(d *schema.ResourceData, defaultValue bool) bool {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() {
+		// Without the raw configuration (e.g.: in the unit tests), the attribute is set if it has a value
+		if v, ok := d.GetOkExists(adoptExistingAttr); ok {
+			return v.(bool)
+		}
+		return defaultValue
+	}
+	if v := rawConfig.GetAttr(adoptExistingAttr); v.IsKnown() && !v.IsNull() {
+		return v.True()
+	}
+	return defaultValue
+}
+
+// getConfiguredInt returns the value of an integer attribute and whether it is set in the configuration,
+// as GetOk does not tell a zero set explicitly from an attribute which is not set.
+func getConfiguredInt(d *schema.ResourceData, key string) (int, bool) {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() {
+		// Without the raw configuration (e.g.: in the unit tests), the attribute is set if it has a value
+		v, ok := d.GetOkExists(key)
+		if !ok {
+			return 0, false
+		}
+		return v.(int), true
+	}
+	if v := rawConfig.GetAttr(key); v.IsNull() {
+		return 0, false
+	}
+	return d.Get(key).(int), true
+}
+
+// objectExistsError is returned when creating an object which already exists without adopting it.
+type objectExistsError struct {
+	err error
+	// resourceType and importID tell how to import the existing object
+	resourceType string
+	importID     string
+}
+
+func (e *objectExistsError) Error() string {
+	return fmt.Sprintf(
+		"%v; set %s = true to manage the existing object, or import it: terraform import %s.<name> %s",
+		e.err, adoptExistingAttr, e.resourceType, e.importID,
+	)
+}
+
+func (e *objectExistsError) Unwrap() error {
+	return e.err
+}
+
+// existingObject returns nil if the existing object reported by err is adopted,
+// an objectExistsError suggesting to import it otherwise.
+func existingObject(adopt bool, resourceType, importID string, err error) error {
+	if !adopt {
+		return &objectExistsError{err: err, resourceType: resourceType, importID: importID}
+	}
+	log.Printf("[INFO] Adopting the existing object of %s %s: %v", resourceType, importID, err)
+	return nil
+}
+
+// createOrAdopt executes the statement creating the object of a resource in a savepoint of txn.
+// If the object already exists, it returns true when adopt is set, for the caller to reconcile the
+// existing object with the configuration, or an objectExistsError suggesting to import it.
+func createOrAdopt(txn *sql.Tx, adopt bool, resourceType, importID, statement string) (bool, error) {
+	var existsErr error
+	skipped, err := execStatements(txn, []string{statement}, func(statement string, err error) statementErrorAction {
+		action := skipExistingObjects(statement, err)
+		if action == skipStatement {
+			existsErr = err
+		}
+		return action
+	})
+	if err != nil || len(skipped) == 0 {
+		return false, err
+	}
+
+	if err := existingObject(adopt, resourceType, importID, existsErr); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// statementSavepoint is the savepoint set before each statement by execStatements, it can be
+// reused as a savepoint released or rolled back to is not visible to the next statements.
+const statementSavepoint = "terraform_statement"
+
+// execStatements executes the statements in order in txn. Without classify, the first error is returned
+// and the caller has to roll back the transaction. Otherwise each statement is executed in a savepoint so
+// the statements whose error is classified as skipStatement are rolled back alone, and the next statements
+// are executed. It returns the statements skipped.
+func execStatements(txn *sql.Tx, statements []string, classify statementErrorClassifier) ([]string, error) {
+	var skipped []string
+	for _, statement := range statements {
+		if classify == nil {
+			if _, err := txn.Exec(statement); err != nil {
+				return skipped, fmt.Errorf("could not execute `%s`: %w", sqlStatementSummary(statement), err)
+			}
+			continue
+		}
+
+		if _, err := txn.Exec("SAVEPOINT " + statementSavepoint); err != nil {
+			return skipped, fmt.Errorf("could not create savepoint: %w", err)
+		}
+
+		_, err := txn.Exec(statement)
+		if err != nil && classify(statement, err) != skipStatement {
+			return skipped, fmt.Errorf("could not execute `%s`: %w", sqlStatementSummary(statement), err)
+		}
+		if err != nil {
+			log.Printf("[WARN] Skipping statement `%s`: %v", sqlStatementSummary(statement), err)
+			if _, err := txn.Exec("ROLLBACK TO SAVEPOINT " + statementSavepoint); err != nil {
+				return skipped, fmt.Errorf("could not roll back to savepoint: %w", err)
+			}
+			skipped = append(skipped, statement)
+		}
+
+		if _, err := txn.Exec("RELEASE SAVEPOINT " + statementSavepoint); err != nil {
+			return skipped, fmt.Errorf("could not release savepoint: %w", err)
+		}
+	}
+	return skipped, nil
+}
+
 func dbExists(db QueryAble, dbname string) (bool, error) {
 	err := db.QueryRow("SELECT datname FROM pg_database WHERE datname=$1", dbname).Scan(&dbname)
 	switch {
@@ -479,6 +1055,29 @@ func getTablesOwner(db QueryAble, schemaName string) ([]string, error) {
 	return owners, nil
 }
 
+func getTypesOwner(db QueryAble, schemaName string) ([]string, error) {
+	rows, err := db.Query(
+		"SELECT DISTINCT pg_catalog.pg_get_userbyid(t.typowner) FROM pg_catalog.pg_type t "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace WHERE n.nspname = $1",
+		schemaName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error while looking for owners of types in schema '%s': %w", schemaName, err)
+	}
+	defer rows.Close()
+
+	var owners []string
+	for rows.Next() {
+		var owner string
+		if err := rows.Scan(&owner); err != nil {
+			return nil, fmt.Errorf("could not scan types owner: %w", err)
+		}
+		owners = append(owners, owner)
+	}
+
+	return owners, rows.Err()
+}
+
 func isSuperuser(db QueryAble, role string) (bool, error) {
 	var superuser bool
 
@@ -491,6 +1090,24 @@ func isSuperuser(db QueryAble, role string) (bool, error) {
 
 const publicRole = "public"
 
+// quoteRoleName quotes the name of a role to be granted privileges, PUBLIC being a keyword.
+func quoteRoleName(role string) string {
+	if role == publicRole {
+		return "PUBLIC"
+	}
+	return pq.QuoteIdentifier(role)
+}
+
+// aclWithDefault returns the ACL expression to read the privileges of a role from. A NULL ACL means
+// the object has its default privileges, some of them being granted to PUBLIC (e.g.: CONNECT on
+// databases, EXECUTE on functions), so they are read from acldefault when the role is PUBLIC.
+func aclWithDefault(roleOID int, aclColumn, aclDefaultType, ownerColumn string) string {
+	if roleOID != 0 {
+		return aclColumn
+	}
+	return fmt.Sprintf("COALESCE(%s, pg_catalog.acldefault('%s', %s))", aclColumn, aclDefaultType, ownerColumn)
+}
+
 func getRoleOID(db QueryAble, role string) (int, error) {
 	if role == publicRole {
 		return 0, nil
@@ -578,6 +1195,480 @@ func findStringSubmatchMap(expression string, text string) map[string]string {
 	return paramsMap
 }
 
+// storageParametersToSQL formats storage parameters to be used in a
+// WITH (...) or SET (...) clause (e.g.: fillfactor = '70', autovacuum_enabled = 'false')
+func storageParametersToSQL(params map[string]interface{}) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s = %s", k, pq.QuoteLiteral(params[k].(string)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// relOptionsToMap parses the reloptions array of pg_class (e.g.: {fillfactor=70})
+func relOptionsToMap(relOptions []string) map[string]interface{} {
+	params := make(map[string]interface{}, len(relOptions))
+	for _, option := range relOptions {
+		pair := strings.SplitN(option, "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+		params[pair[0]] = pair[1]
+	}
+	return params
+}
+
+// foreignOptionsToSQL formats the options of a foreign-data wrapper, server or user mapping
+// to be used in an OPTIONS (...) clause at creation (e.g.: "host" 'foo', "port" '5432')
+func foreignOptionsToSQL(options map[string]interface{}) string {
+	keys := make([]string, 0, len(options))
+	for k := range options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s %s", pq.QuoteIdentifier(k), pq.QuoteLiteral(options[k].(string)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// alterForeignOptionsToSQL returns the actions of an OPTIONS (...) clause to go from the old options
+// to the new ones (e.g.: SET "host" 'bar', ADD "port" '5432', DROP "dbname").
+// Unchanged options are omitted, so it returns an empty string if there is nothing to change.
+func alterForeignOptionsToSQL(oldOptions, newOptions map[string]interface{}) string {
+	keys := make([]string, 0, len(oldOptions)+len(newOptions))
+	for k := range newOptions {
+		keys = append(keys, k)
+	}
+	for k := range oldOptions {
+		if _, ok := newOptions[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := []string{}
+	for _, k := range keys {
+		oldValue, exists := oldOptions[k]
+		newValue, kept := newOptions[k]
+		switch {
+		case !kept:
+			parts = append(parts, fmt.Sprintf("DROP %s", pq.QuoteIdentifier(k)))
+		case !exists:
+			parts = append(parts, fmt.Sprintf("ADD %s %s", pq.QuoteIdentifier(k), pq.QuoteLiteral(newValue.(string))))
+		case oldValue != newValue:
+			parts = append(parts, fmt.Sprintf("SET %s %s", pq.QuoteIdentifier(k), pq.QuoteLiteral(newValue.(string))))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseForeignOptions parses the options array of the foreign-data catalogs (e.g.: {host=foo,port=5432}),
+// values can contain an equal sign.
+func parseForeignOptions(options []string) map[string]interface{} {
+	mappedOptions := make(map[string]interface{}, len(options))
+	for _, option := range options {
+		pair := strings.SplitN(option, "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+		mappedOptions[pair[0]] = pair[1]
+	}
+	return mappedOptions
+}
+
+// typeNameAliases maps the type aliases accepted by Postgres to the name
+// returned by format_type.
+var typeNameAliases = map[string]string{
+	"int":         "integer",
+	"int4":        "integer",
+	"int8":        "bigint",
+	"int2":        "smallint",
+	"varchar":     "character varying",
+	"char":        "character",
+	"bool":        "boolean",
+	"float8":      "double precision",
+	"float4":      "real",
+	"decimal":     "numeric",
+	"timestamptz": "timestamp with time zone",
+	"timestamp":   "timestamp without time zone",
+	"timetz":      "time with time zone",
+	"time":        "time without time zone",
+}
+
+// normalizeTypeName returns the name of a type as returned by format_type
+// (e.g.: VARCHAR(10)[] => character varying(10)[]) so it can be compared with the catalog.
+func normalizeTypeName(typeName string) string {
+	typeName = strings.ToLower(strings.Join(strings.Fields(typeName), " "))
+
+	// Split the base name from its modifiers and array suffix
+	base, suffix := typeName, ""
+	if i := strings.IndexAny(typeName, "(["); i >= 0 {
+		base, suffix = strings.TrimSpace(typeName[:i]), typeName[i:]
+	}
+
+	alias, ok := typeNameAliases[base]
+	if !ok {
+		return base + suffix
+	}
+
+	// The time zone qualifier of time types is placed after the precision (e.g.: timestamp(3) with time zone)
+	if i := strings.Index(alias, " with"); i >= 0 && strings.HasPrefix(suffix, "(") {
+		if end := strings.Index(suffix, ")"); end >= 0 {
+			return alias[:i] + suffix[:end+1] + alias[i:] + suffix[end+1:]
+		}
+	}
+
+	return alias + suffix
+}
+
+func typeDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeTypeName(old) == normalizeTypeName(new)
+}
+
+// normalizeFunctionSignature returns a function name or signature (e.g.: Public.To_Point(public.geo, int4)) as
+// displayed by regproc/regprocedure when the public schema is in the search path (e.g.: to_point(geo, integer)),
+// so it can be compared with the catalog.
+func normalizeFunctionSignature(signature string) string {
+	name, args := strings.TrimSpace(signature), ""
+	i := strings.Index(name, "(")
+	if i >= 0 {
+		name, args = strings.TrimSpace(name[:i]), strings.TrimSuffix(strings.TrimSpace(name[i+1:]), ")")
+	}
+	name = strings.TrimPrefix(strings.ToLower(name), "public.")
+	if i < 0 {
+		return name
+	}
+
+	types := []string{}
+	for _, arg := range strings.Split(args, ",") {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			types = append(types, strings.TrimPrefix(normalizeTypeName(arg), "public."))
+		}
+	}
+	return name + "(" + strings.Join(types, ", ") + ")"
+}
+
+func functionSignatureDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeFunctionSignature(old) == normalizeFunctionSignature(new)
+}
+
+// sqlExpressionTokens splits a SQL expression into tokens, unquoted words being lower-cased
+// and string literals, quoted identifiers and operators being kept as is.
+func sqlExpressionTokens(expression string) []string {
+	const operatorChars = "+-*/<>=~!@#%^&|`?:"
+
+	tokens := []string{}
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '\'' || r == '"':
+			// Quoted literal or identifier, a doubled quote is an escaped quote
+			for i++; i < len(runes); i++ {
+				if runes[i] == r {
+					if i+1 < len(runes) && runes[i+1] == r {
+						i++
+						continue
+					}
+					i++
+					break
+				}
+			}
+			tokens = append(tokens, string(runes[start:i]))
+			continue
+		case r == '_' || r == '$' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '$' || runes[i] == '.' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, strings.ToLower(string(runes[start:i])))
+			continue
+		case strings.ContainsRune(operatorChars, r):
+			for i < len(runes) && strings.ContainsRune(operatorChars, runes[i]) {
+				i++
+			}
+		default:
+			i++
+		}
+		tokens = append(tokens, string(runes[start:i]))
+	}
+
+	return tokens
+}
+
+// matchingParenthesis returns the index of the token closing the parenthesis opened at tokens[open], or -1.
+func matchingParenthesis(tokens []string, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// normalizeSQLExpression returns a canonical form of a SQL expression, so an expression written by the user
+// can be compared with the one rewritten by Postgres (e.g.: a=1 and (a = 1) are both normalized to a = 1).
+// Whitespaces, the case of unquoted words, a trailing semicolon and redundant parentheses (surrounding the whole
+// expression or doubled) are ignored.
+func normalizeSQLExpression(expression string) string {
+	tokens := sqlExpressionTokens(expression)
+	for len(tokens) > 0 && tokens[len(tokens)-1] == ";" {
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	// Remove the parentheses surrounding the whole expression
+	for len(tokens) > 1 && tokens[0] == "(" && matchingParenthesis(tokens, 0) == len(tokens)-1 {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+
+	// Remove doubled parentheses, e.g.: ((a = 1)) => (a = 1)
+	removed := make([]bool, len(tokens))
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i] != "(" || tokens[i+1] != "(" {
+			continue
+		}
+		outer, inner := matchingParenthesis(tokens, i), matchingParenthesis(tokens, i+1)
+		if outer != -1 && inner == outer-1 {
+			removed[i], removed[outer] = true, true
+		}
+	}
+
+	normalized := make([]string, 0, len(tokens))
+	for i, token := range tokens {
+		if !removed[i] {
+			normalized = append(normalized, token)
+		}
+	}
+
+	return strings.Join(normalized, " ")
+}
+
+// sqlExpressionDiffSuppressFunc suppresses the diff between SQL expressions which only differ by their formatting,
+// as Postgres rewrites the stored expressions (queries, predicates, check constraints, defaults...).
+func sqlExpressionDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeSQLExpression(old) == normalizeSQLExpression(new)
+}
+
+// scratchSavepoint is the savepoint the scratch objects used to deparse the expressions are created in.
+const scratchSavepoint = "terraform_scratch"
+
+// scratchQuery executes the statements creating scratch objects in a savepoint of txn, returns the result
+// of query and rolls back to the savepoint, so nothing is kept from the statements.
+func scratchQuery(txn *sql.Tx, statements []string, query string) (string, error) {
+	if _, err := txn.Exec("SAVEPOINT " + scratchSavepoint); err != nil {
+		return "", fmt.Errorf("could not create savepoint: %w", err)
+	}
+
+	var result string
+	var err error
+	for _, statement := range statements {
+		if _, err = txn.Exec(statement); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = txn.QueryRow(query).Scan(&result)
+	}
+
+	if _, rollbackErr := txn.Exec("ROLLBACK TO SAVEPOINT " + scratchSavepoint); rollbackErr != nil {
+		return "", fmt.Errorf("could not roll back to savepoint: %w", rollbackErr)
+	}
+	if _, releaseErr := txn.Exec("RELEASE SAVEPOINT " + scratchSavepoint); releaseErr != nil {
+		return "", fmt.Errorf("could not release savepoint: %w", releaseErr)
+	}
+
+	return result, err
+}
+
+// deparseSQLExpression returns the expression as Postgres stores it (with its parentheses and casts, e.g.:
+// name = 'foo' becomes name = 'foo'::text), by deparsing a temporary view selecting it from the relations of
+// from (e.g.: `"public"."t"`, empty for none).
+func deparseSQLExpression(txn *sql.Tx, from, expression string) (string, error) {
+	view := "CREATE TEMPORARY VIEW terraform_scratch AS SELECT (" + expression + ") AS expression"
+	if from != "" {
+		view += " FROM " + from
+	}
+	return scratchQuery(txn, []string{view}, "SELECT pg_catalog.pg_get_viewdef('pg_temp.terraform_scratch'::regclass, true)")
+}
+
+// equivalentSQLExpressions returns whether two expressions are the same once deparsed by Postgres,
+// as sqlExpressionDiffSuppressFunc only ignores their formatting.
+// The expressions which cannot be deparsed are considered different.
+func equivalentSQLExpressions(txn *sql.Tx, from, a, b string) (bool, error) {
+	if normalizeSQLExpression(a) == normalizeSQLExpression(b) {
+		return true, nil
+	}
+
+	deparsed := make([]string, 0, 2)
+	for _, expression := range []string{a, b} {
+		result, err := deparseSQLExpression(txn, from, expression)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			log.Printf("[WARN] could not deparse expression `%s`: %v", expression, err)
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		deparsed = append(deparsed, result)
+	}
+
+	return deparsed[0] == deparsed[1], nil
+}
+
+// configuredSQLExpression returns the expression to set in the state: the configured one if Postgres
+// stores it as the catalog one with other parentheses or casts, so it does not show a diff, otherwise
+// the catalog one (sqlExpressionDiffSuppressFunc ignores the formatting).
+func configuredSQLExpression(txn *sql.Tx, from, configured, catalog string) (string, error) {
+	if configured == "" || normalizeSQLExpression(configured) == normalizeSQLExpression(catalog) {
+		return catalog, nil
+	}
+	equivalent, err := equivalentSQLExpressions(txn, from, configured, catalog)
+	if err != nil || !equivalent {
+		return catalog, err
+	}
+	return configured, nil
+}
+
+// configuredSQLExpressions is configuredSQLExpression for a list of expressions, compared by position.
+func configuredSQLExpressions(txn *sql.Tx, from string, configured []interface{}, catalog []string) ([]string, error) {
+	if len(configured) != len(catalog) {
+		return catalog, nil
+	}
+	expressions := make([]string, len(catalog))
+	for i := range catalog {
+		expression, err := configuredSQLExpression(txn, from, configured[i].(string), catalog[i])
+		if err != nil {
+			return nil, err
+		}
+		expressions[i] = expression
+	}
+	return expressions, nil
+}
+
+// parseSettingList splits the value of a list-valued setting as stored by Postgres
+// (e.g.: `auto_explain, "$libdir/plugins/my lib"`), the elements may be double-quoted.
+func parseSettingList(value string) []string {
+	values := []string{}
+	var current strings.Builder
+	inQuotes := false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' && inQuotes && i+1 < len(value) && value[i+1] == '"':
+			current.WriteByte('"')
+			i++
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ',' && !inQuotes:
+			values = append(values, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if last := strings.TrimSpace(current.String()); last != "" || len(values) > 0 {
+		values = append(values, last)
+	}
+	return values
+}
+
 func defaultDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
 	return old == new
 }
+
+// resourceIDSeparator separates the parts of the composite resource IDs built by joinResourceID.
+const resourceIDSeparator = '.'
+
+// joinResourceID joins the parts of a composite resource ID (e.g.: database, schema and name) with
+// dots. The dots and backslashes of the parts are escaped with a backslash, so names containing dots
+// are split back unchanged by splitResourceID.
+func joinResourceID(parts ...string) string {
+	escaped := make([]string, len(parts))
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, `\`, `\\`)
+		escaped[i] = strings.ReplaceAll(part, string(resourceIDSeparator), `\`+string(resourceIDSeparator))
+	}
+	return strings.Join(escaped, string(resourceIDSeparator))
+}
+
+// splitResourceID splits a resource ID built by joinResourceID into its parts.
+func splitResourceID(id string) []string {
+	return splitResourceIDN(id, -1)
+}
+
+// splitResourceIDN splits a resource ID built by joinResourceID into n parts at most, as strings.SplitN:
+// the dots of the last part don't need to be escaped, e.g. in the arguments of a function signature.
+func splitResourceIDN(id string, n int) []string {
+	var parts []string
+	var current strings.Builder
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case c == '\\' && i+1 < len(id):
+			i++
+			current.WriteByte(id[i])
+		case c == resourceIDSeparator && len(parts) != n-1:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	return append(parts, current.String())
+}
+
+// resourceIDStateUpgrader returns the state upgrader of a resource whose ID is now built by
+// joinResourceID, the ID of the previous version being joined without escaping. The ID is
+// rebuilt by generateID from the attributes of the state, which are left unchanged.
+func resourceIDStateUpgrader(resource *schema.Resource, version int, generateID func(map[string]interface{}) string) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: version,
+		Type:    resource.CoreConfigSchema().ImpliedType(),
+		Upgrade: func(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+			if rawState == nil {
+				return rawState, nil
+			}
+			if id := generateID(rawState); id != "" {
+				log.Printf("[DEBUG] Upgrading resource ID %v to %s", rawState["id"], id)
+				rawState["id"] = id
+			}
+			return rawState, nil
+		},
+	}
+}
+
+// databaseObjectIDFromRawState returns the ID of an object of a database (e.g.: a schema or an extension)
+// from the database and name attributes of a raw state, or an empty string if they are not set.
+func databaseObjectIDFromRawState(rawState map[string]interface{}, databaseAttr, nameAttr string) string {
+	database := rawStateString(rawState, databaseAttr)
+	name := rawStateString(rawState, nameAttr)
+	if database == "" || name == "" {
+		return ""
+	}
+	return joinResourceID(database, name)
+}
+
+// rawStateString returns the string attribute of a raw state, or an empty string if it is not set.
+func rawStateString(rawState map[string]interface{}, key string) string {
+	value, _ := rawState[key].(string)
+	return value
+}
@@ -1,8 +1,20 @@
 package postgresql
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/blang/semver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,3 +29,638 @@ func TestFindStringSubmatchMap(t *testing.T) {
 		},
 	)
 }
+
+func TestNormalizeTypeName(t *testing.T) {
+	tests := map[string]string{
+		"integer":                     "integer",
+		"INT":                         "integer",
+		"int8":                        "bigint",
+		"varchar(10)":                 "character varying(10)",
+		"VARCHAR (10)[]":              "character varying(10)[]",
+		"text[]":                      "text[]",
+		"bool":                        "boolean",
+		"numeric(10,2)":               "numeric(10,2)",
+		"decimal(10,2)":               "numeric(10,2)",
+		"timestamptz":                 "timestamp with time zone",
+		"timestamp(3)":                "timestamp(3) without time zone",
+		"timestamptz(3)[]":            "timestamp(3) with time zone[]",
+		"my_schema.my_type":           "my_schema.my_type",
+		"double   precision":          "double precision",
+		"timestamp without time zone": "timestamp without time zone",
+	}
+
+	for typeName, expected := range tests {
+		assert.Equal(t, expected, normalizeTypeName(typeName), typeName)
+	}
+}
+
+func TestNormalizeSQLExpression(t *testing.T) {
+	tests := []struct {
+		old, new string
+		equal    bool
+	}{
+		{"a=1", "(a = 1)", true},
+		{"a=1", "((a = 1))", true},
+		{"A = 1 AND b > 2", "(a = 1) and (b > 2)", false},
+		{"(a = 1) AND (b > 2)", "((A = 1)) AND (b > 2)", true},
+		{"SELECT 1 AS id;", "SELECT 1 AS id", true},
+		{"SELECT id\n  FROM t", " select id from t", true},
+		{"name = 'Foo'", "name = 'foo'", false},
+		{`"Name" = 1`, `"name" = 1`, false},
+		{"name = 'it''s'", "(name = 'it''s')", true},
+		{"(a + b) * c", "a + b * c", false},
+		{"(a = 1) OR (b = 2)", "a = 1) OR (b = 2", false},
+		{"lower(name)", "lower((name))", true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.equal, normalizeSQLExpression(test.old) == normalizeSQLExpression(test.new), "%s / %s", test.old, test.new)
+	}
+}
+
+func TestAccEquivalentSQLExpressions(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_expressions (a integer, b integer, name text)")
+
+	db, err := sql.Open("postgres", testConfig.connStr(dbName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	txn, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deferredRollback(txn)
+
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"A = 1 AND b > 2", "(a = 1) and (b > 2)", true},
+		{"name = 'foo'", "name = 'foo'::text", true},
+		{"(a + b) * 2 > 10", "((a + b) * 2) > 10", true},
+		{"a = 1", "a = 2", false},
+		{"(a + b) * 2", "a + b * 2", false},
+		// Expressions which cannot be deparsed are different, the transaction can still be used
+		{"unknown_column = 1", "a = 1", false},
+	}
+	for _, test := range tests {
+		equal, err := equivalentSQLExpressions(txn, "public.test_expressions", test.a, test.b)
+		assert.NoError(t, err, "%s / %s", test.a, test.b)
+		assert.Equal(t, test.equal, equal, "%s / %s", test.a, test.b)
+	}
+
+	expression, err := configuredSQLExpression(txn, "public.test_expressions", "name = 'foo'", "name = 'foo'::text")
+	assert.NoError(t, err)
+	assert.Equal(t, "name = 'foo'", expression)
+	expression, err = configuredSQLExpression(txn, "public.test_expressions", "name = 'bar'", "name = 'foo'::text")
+	assert.NoError(t, err)
+	assert.Equal(t, "name = 'foo'::text", expression)
+}
+
+func TestAlterForeignOptionsToSQL(t *testing.T) {
+	oldOptions := map[string]interface{}{"host": "foo", "port": "5432", "dbname": "foodb"}
+
+	assert.Equal(t, "", alterForeignOptionsToSQL(oldOptions, oldOptions))
+	assert.Equal(t,
+		`DROP "dbname", SET "host" 'bar', ADD "user" 'it''s'`,
+		alterForeignOptionsToSQL(oldOptions, map[string]interface{}{"host": "bar", "port": "5432", "user": "it's"}),
+	)
+	assert.Equal(t,
+		`DROP "dbname", DROP "host", DROP "port"`,
+		alterForeignOptionsToSQL(oldOptions, map[string]interface{}{}),
+	)
+}
+
+func TestParseForeignOptions(t *testing.T) {
+	assert.Equal(t,
+		map[string]interface{}{"user": "admin", "password": "pass=$*'"},
+		parseForeignOptions([]string{"user=admin", "password=pass=$*'"}),
+	)
+}
+
+func TestParseSettingList(t *testing.T) {
+	assert.Equal(t, []string{}, parseSettingList(""))
+	assert.Equal(t, []string{"auto_explain"}, parseSettingList("auto_explain"))
+	assert.Equal(t, []string{"auto_explain", "pg_stat_statements"}, parseSettingList("auto_explain, pg_stat_statements"))
+	assert.Equal(t,
+		[]string{"$libdir/plugins/my lib", `with "quotes", and comma`, "auto_explain"},
+		parseSettingList(`"$libdir/plugins/my lib", "with ""quotes"", and comma", auto_explain`),
+	)
+}
+
+func TestStatementTimeout(t *testing.T) {
+	assert.Equal(t, int64(0), statementTimeout(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	timeout := statementTimeout(ctx)
+	assert.Greater(t, timeout, int64(59000))
+	assert.LessOrEqual(t, timeout, int64(60000))
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	assert.Equal(t, int64(1), statementTimeout(expired))
+}
+
+func TestIsTimeoutError(t *testing.T) {
+	ctx := context.Background()
+	assert.True(t, isTimeoutError(ctx, &pq.Error{Code: "57014", Message: "canceling statement due to statement timeout"}))
+	assert.False(t, isTimeoutError(ctx, &pq.Error{Code: "57014", Message: "canceling statement due to user request"}))
+	assert.False(t, isTimeoutError(ctx, &pq.Error{Code: "42P01", Message: "relation does not exist"}))
+
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	assert.True(t, isTimeoutError(expired, context.DeadlineExceeded))
+}
+
+func TestIsBadConnectionError(t *testing.T) {
+	assert.False(t, isBadConnectionError(nil))
+	assert.True(t, isBadConnectionError(driver.ErrBadConn))
+	assert.True(t, isBadConnectionError(&pq.Error{Code: "57P01", Message: "terminating connection due to administrator command"}))
+	assert.True(t, isBadConnectionError(fmt.Errorf("could not create role: %w", &pq.Error{Code: "08006", Message: "connection failure"})))
+	assert.True(t, isBadConnectionError(&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}))
+	assert.False(t, isBadConnectionError(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}))
+	assert.False(t, isBadConnectionError(&pq.Error{Code: "57014", Message: "canceling statement due to statement timeout"}))
+	assert.False(t, isBadConnectionError(&pq.Error{Code: "42P01", Message: "relation does not exist"}))
+}
+
+func TestIsStatementNotRunError(t *testing.T) {
+	assert.True(t, isStatementNotRunError(driver.ErrBadConn))
+	assert.True(t, isStatementNotRunError(&pq.Error{Code: "57P01", Message: "terminating connection due to administrator command"}))
+	assert.False(t, isStatementNotRunError(&pq.Error{Code: "08006", Message: "connection failure"}))
+	assert.False(t, isStatementNotRunError(&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}))
+	assert.False(t, isStatementNotRunError(syscall.EPIPE))
+	assert.False(t, isStatementNotRunError(nil))
+}
+
+func TestCancelledStatementError(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, cancelledStatementError(ctx, "CREATE DATABASE db", nil))
+
+	notFound := &pq.Error{Code: "42P01", Message: "relation does not exist"}
+	assert.Equal(t, notFound, cancelledStatementError(ctx, "SELECT 1", notFound))
+
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	err := cancelledStatementError(expired, "CREATE DATABASE \"db\" TEMPLATE \"big\"\nOWNER app", context.DeadlineExceeded)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "statement `CREATE DATABASE \"db\" TEMPLATE \"big\" ...` cancelled: context deadline exceeded", err.Error())
+}
+
+func TestIsObjectNotFoundError(t *testing.T) {
+	assert.True(t, isObjectNotFoundError(&pq.Error{Code: "3D000", Message: `database "mydb" does not exist`}))
+	assert.True(t, isObjectNotFoundError(fmt.Errorf("could not start transaction: %w", &pq.Error{Code: "3D000"})))
+	assert.True(t, isObjectNotFoundError(&pq.Error{Code: "42704", Message: `type "mytype" does not exist`}))
+	assert.False(t, isObjectNotFoundError(&pq.Error{Code: "57P01", Message: "terminating connection due to administrator command"}))
+	assert.False(t, isObjectNotFoundError(driver.ErrBadConn))
+	assert.False(t, isObjectNotFoundError(sql.ErrNoRows))
+	assert.False(t, isObjectNotFoundError(nil))
+}
+
+// connectionErrors are errors which do not tell if an object exists or not.
+var connectionErrors = []error{
+	driver.ErrBadConn,
+	&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+	&pq.Error{Code: "57P01", Message: "terminating connection due to administrator command"},
+}
+
+type failingConnector struct {
+	err error
+}
+
+func (c failingConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, c.err
+}
+
+func (c failingConnector) Driver() driver.Driver {
+	return failingDriver(c)
+}
+
+type failingDriver struct {
+	err error
+}
+
+func (d failingDriver) Open(string) (driver.Conn, error) {
+	return nil, d.err
+}
+
+// newFailingClient returns a client for database whose connections fail with err.
+func newFailingClient(t *testing.T, database string, err error) *Client {
+	config := Config{
+		Scheme:          "postgres",
+		Host:            "localhost",
+		Port:            5432,
+		Username:        "postgres",
+		SSLMode:         "disable",
+		ExpectedVersion: semver.MustParse("15.0.0"),
+	}
+	client := config.NewClient(database)
+	dsn := config.connStr(database)
+
+	db := sql.OpenDB(failingConnector{err: err})
+	dbRegistryLock.Lock()
+	dbRegistry[dsn] = &DBConnection{db, client, config.ExpectedVersion, flavorPostgreSQL}
+	dbRegistryLock.Unlock()
+
+	t.Cleanup(func() {
+		dbRegistryLock.Lock()
+		delete(dbRegistry, dsn)
+		dbRegistryLock.Unlock()
+		db.Close()
+	})
+
+	return client
+}
+
+func TestDatabaseNotFoundError(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLComment().Schema, map[string]interface{}{})
+
+	err := withResourceID(fmt.Errorf("could not read comment: %w", &databaseNotFoundError{database: "my_database"}), d)
+	assert.EqualError(t, err, "could not read comment: database my_database does not exist; check the database attribute, or if the database is created with postgresql_database, reference it (or use depends_on) so it is created first")
+
+	d.SetId("my_database.table.public.my_table")
+	err = withResourceID(fmt.Errorf("could not read comment: %w", &databaseNotFoundError{database: "my_database"}), d)
+	assert.EqualError(t, err, "database my_database referenced by my_database.table.public.my_table does not exist; check the database attribute, or if the database is created with postgresql_database, reference it (or use depends_on) so it is created first")
+	assert.True(t, isObjectNotFoundError(err))
+
+	assert.Nil(t, withResourceID(nil, d))
+}
+
+func TestConnectDatabaseErrors(t *testing.T) {
+	// The existence of the database cannot be checked if the server cannot be reached
+	client := newFailingClient(t, "postgres", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+	_, err := startTransaction(client, "my_database")
+	assert.Contains(t, err.Error(), "could not check if database exists")
+	assert.False(t, isObjectNotFoundError(err))
+
+	// The error of a connection to an existing database names the database
+	newFailingClient(t, "my_database", &pq.Error{Code: "42501", Message: `permission denied for database "my_database"`})
+	key := client.config.connStr("my_database")
+	existingDatabasesLock.Lock()
+	existingDatabases[key] = true
+	existingDatabasesLock.Unlock()
+	t.Cleanup(func() {
+		existingDatabasesLock.Lock()
+		delete(existingDatabases, key)
+		existingDatabasesLock.Unlock()
+	})
+
+	_, err = startTransaction(client, "my_database")
+	assert.EqualError(t, err, `could not start transaction on database my_database: pq: permission denied for database "my_database"`)
+}
+
+func TestStartTransactionOnDroppedDatabase(t *testing.T) {
+	// The database has been dropped since its existence has been checked
+	client := newFailingClient(t, "postgres", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+	newFailingClient(t, "my_database", &pq.Error{Severity: "FATAL", Code: "3D000", Message: `database "my_database" does not exist`})
+	key := client.config.connStr("my_database")
+	existingDatabasesLock.Lock()
+	existingDatabases[key] = true
+	existingDatabasesLock.Unlock()
+
+	_, err := startTransaction(client, "my_database")
+	assert.EqualError(t, err, "could not start transaction on database my_database: database my_database does not exist; check the database attribute, or if the database is created with postgresql_database, reference it (or use depends_on) so it is created first")
+	assert.True(t, isObjectNotFoundError(err))
+
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLExtension().Schema, map[string]interface{}{})
+	d.SetId("my_database.pg_trgm")
+	assert.EqualError(t, withResourceID(err, d), "database my_database referenced by my_database.pg_trgm does not exist; check the database attribute, or if the database is created with postgresql_database, reference it (or use depends_on) so it is created first")
+
+	// The database is checked again by the next connection
+	existingDatabasesLock.Lock()
+	_, known := existingDatabases[key]
+	existingDatabasesLock.Unlock()
+	assert.False(t, known)
+}
+
+func TestAsCatalogReadError(t *testing.T) {
+	err := asCatalogReadError(fmt.Errorf("Error reading ROLE: %w", &pq.Error{Code: "42501", Message: "permission denied for table pg_authid"}))
+	var catalogErr *catalogReadError
+	assert.True(t, errors.As(err, &catalogErr))
+	assert.Equal(t, "pg_authid", catalogErr.catalog)
+	assert.EqualError(t, err, "the connected role cannot read the catalog pg_authid: Error reading ROLE: pq: permission denied for table pg_authid")
+
+	// Before Postgres 11 the catalogs are relations
+	err = asCatalogReadError(&pq.Error{Code: "42501", Message: "permission denied for relation pg_shdescription"})
+	assert.True(t, errors.As(err, &catalogErr))
+	assert.Equal(t, "pg_shdescription", catalogErr.catalog)
+
+	err = asCatalogReadError(&pq.Error{Code: "42501", Message: "permission denied for function pg_read_file"})
+	assert.EqualError(t, err, "the connected role cannot read a system catalog: pq: permission denied for function pg_read_file")
+
+	// The other errors are returned unchanged
+	other := &pq.Error{Code: "42P01", Message: `relation "t" does not exist`}
+	assert.Equal(t, other, asCatalogReadError(other))
+	assert.Nil(t, asCatalogReadError(nil))
+}
+
+func TestCatalogReadErrorKeepsState(t *testing.T) {
+	client := newFailingClient(t, "postgres", fmt.Errorf("not connected"))
+	read := PGResourceContextFunc(func(db *DBConnection, d *schema.ResourceData) error {
+		return asCatalogReadError(&pq.Error{Code: "42501", Message: "permission denied for table pg_description"})
+	})
+
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLComment().Schema, map[string]interface{}{})
+	d.SetId("app.schema.app")
+
+	diags := read(context.Background(), d, client)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Contains(t, diags[0].Detail, "pg_description")
+	assert.Equal(t, "app.schema.app", d.Id())
+
+	client.config.StrictCatalogReads = true
+	diags = read(context.Background(), d, client)
+	assert.True(t, diags.HasError())
+}
+
+func TestACLWithDefault(t *testing.T) {
+	assert.Equal(t, "datacl", aclWithDefault(16384, "datacl", "d", "datdba"))
+	assert.Equal(t, "COALESCE(datacl, pg_catalog.acldefault('d', datdba))", aclWithDefault(0, "datacl", "d", "datdba"))
+	assert.Equal(t, "PUBLIC", quoteRoleName(publicRole))
+	assert.Equal(t, `"Public"`, quoteRoleName("Public"))
+}
+
+func TestResourceIDCodec(t *testing.T) {
+	var cases = []struct {
+		parts []string
+		id    string
+	}{
+		{[]string{"mydb", "public", "events"}, "mydb.public.events"},
+		{[]string{"mydb", "My.Schema", "Events/2024"}, `mydb.My\.Schema.Events/2024`},
+		{[]string{"mydb", "public", `back\slash.`}, `mydb.public.back\\slash\.`},
+		{[]string{"mydb", "", "events"}, "mydb..events"},
+	}
+
+	for _, c := range cases {
+		id := joinResourceID(c.parts...)
+		assert.Equal(t, c.id, id)
+		assert.Equal(t, c.parts, splitResourceID(id))
+	}
+
+	// The last part of the IDs split in a fixed number of parts keeps its unescaped dots
+	assert.Equal(t, []string{"mydb", "app", "function", "to_point(app.geo, integer)"}, splitResourceIDN("mydb.app.function.to_point(app.geo, integer)", 4))
+	assert.Equal(t, []string{"mydb", "My.Schema", "a.b"}, splitResourceIDN(`mydb.My\.Schema.a\.b`, 3))
+}
+
+func TestDatabaseObjectStateUpgradeV0(t *testing.T) {
+	resources := map[string]*schema.Resource{
+		"postgresql_extension":        resourcePostgreSQLExtension(),
+		"postgresql_schema":           resourcePostgreSQLSchema(),
+		"postgresql_publication":      resourcePostgreSQLPublication(),
+		"postgresql_subscription":     resourcePostgreSQLSubscription(),
+		"postgresql_replication_slot": resourcePostgreSQLReplicationSlot(),
+	}
+
+	for name, r := range resources {
+		assert.Equal(t, 1, r.SchemaVersion, name)
+
+		rawState := map[string]interface{}{"id": "my.db.app.v2", "database": "my.db", "name": "app.v2"}
+		upgraded, err := r.StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+		assert.NoError(t, err, name)
+		assert.Equal(t, `my\.db.app\.v2`, upgraded["id"], name)
+
+		// The ID of a state without the names is kept
+		rawState = map[string]interface{}{"id": "mydb.app"}
+		upgraded, err = r.StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+		assert.NoError(t, err, name)
+		assert.Equal(t, "mydb.app", upgraded["id"], name)
+	}
+}
+
+func TestNormalizeFunctionSignature(t *testing.T) {
+	var tests = []struct {
+		input string
+		want  string
+	}{
+		{"eqsel", "eqsel"},
+		{"public.EqSel", "eqsel"},
+		{"my_schema.to_point(my_schema.geo)", "my_schema.to_point(my_schema.geo)"},
+		{"public.to_point( public.geo , int4,bool)", "to_point(geo, integer, boolean)"},
+		{"to_point()", "to_point()"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, normalizeFunctionSignature(test.input), "normalizeFunctionSignature(%q)", test.input)
+	}
+}
+
+func TestNormalizeAllPrivileges(t *testing.T) {
+	all := stringSliceToSet([]string{"ALL"})
+	enumerated := stringSliceToSet([]string{"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER"})
+	withMaintain := stringSliceToSet([]string{"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER", "MAINTAIN"})
+	partial := stringSliceToSet([]string{"SELECT", "INSERT"})
+
+	assert.True(t, all.Equal(normalizeAllPrivileges("table", all, enumerated)))
+	assert.True(t, all.Equal(normalizeAllPrivileges("table", all, withMaintain)))
+	assert.True(t, partial.Equal(normalizeAllPrivileges("table", all, partial)))
+	assert.True(t, enumerated.Equal(normalizeAllPrivileges("table", enumerated, enumerated)))
+	assert.True(t, all.Equal(normalizeAllPrivileges("database", all, stringSliceToSet([]string{"CREATE", "CONNECT", "TEMPORARY"}))))
+	assert.True(t, all.Equal(normalizeAllPrivileges("function", all, stringSliceToSet([]string{"EXECUTE"}))))
+}
+
+// recordingConn is a connection recording the statements executed, the statements of
+// failures failing with the associated error.
+type recordingConn struct {
+	statements []string
+	failures   map[string]error
+}
+
+func (c *recordingConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *recordingConn) Driver() driver.Driver                        { return nil }
+func (c *recordingConn) Prepare(string) (driver.Stmt, error)          { return nil, driver.ErrSkip }
+func (c *recordingConn) Close() error                                 { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error)                    { return c, nil }
+func (c *recordingConn) Commit() error                                { return nil }
+func (c *recordingConn) Rollback() error                              { return nil }
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.statements = append(c.statements, query)
+	if err, ok := c.failures[query]; ok {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func TestExecStatements(t *testing.T) {
+	missingTable := &pq.Error{Code: "42P01", Message: `relation "t2" does not exist`}
+	failures := map[string]error{
+		"GRANT SELECT ON t2 TO r": missingTable,
+		"GRANT SELECT ON t3 TO r": &pq.Error{Code: "42501", Message: "permission denied"},
+	}
+
+	run := func(statements []string, classify statementErrorClassifier) (*recordingConn, []string, error) {
+		conn := &recordingConn{failures: failures}
+		db := sql.OpenDB(conn)
+		defer db.Close()
+
+		txn, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer deferredRollback(txn)
+
+		skipped, err := execStatements(txn, statements, classify)
+		return conn, skipped, err
+	}
+
+	t.Run("abort without classifier", func(t *testing.T) {
+		conn, skipped, err := run([]string{"GRANT SELECT ON t1 TO r", "GRANT SELECT ON t2 TO r", "GRANT SELECT ON t4 TO r"}, nil)
+		assert.ErrorIs(t, err, missingTable)
+		assert.Equal(t, "could not execute `GRANT SELECT ON t2 TO r`: pq: relation \"t2\" does not exist", err.Error())
+		assert.Empty(t, skipped)
+		assert.Equal(t, []string{"GRANT SELECT ON t1 TO r", "GRANT SELECT ON t2 TO r"}, conn.statements)
+	})
+
+	t.Run("skip missing objects", func(t *testing.T) {
+		conn, skipped, err := run([]string{"GRANT SELECT ON t1 TO r", "GRANT SELECT ON t2 TO r", "GRANT SELECT ON t4 TO r"}, skipMissingObjects)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"GRANT SELECT ON t2 TO r"}, skipped)
+		assert.Equal(t, []string{
+			"SAVEPOINT terraform_statement",
+			"GRANT SELECT ON t1 TO r",
+			"RELEASE SAVEPOINT terraform_statement",
+			"SAVEPOINT terraform_statement",
+			"GRANT SELECT ON t2 TO r",
+			"ROLLBACK TO SAVEPOINT terraform_statement",
+			"RELEASE SAVEPOINT terraform_statement",
+			"SAVEPOINT terraform_statement",
+			"GRANT SELECT ON t4 TO r",
+			"RELEASE SAVEPOINT terraform_statement",
+		}, conn.statements)
+	})
+
+	t.Run("abort on errors not skipped", func(t *testing.T) {
+		conn, skipped, err := run([]string{"GRANT SELECT ON t2 TO r", "GRANT SELECT ON t3 TO r", "GRANT SELECT ON t4 TO r"}, skipMissingObjects)
+		assert.Error(t, err)
+		assert.Equal(t, []string{"GRANT SELECT ON t2 TO r"}, skipped)
+		assert.Equal(t, "GRANT SELECT ON t3 TO r", conn.statements[len(conn.statements)-1])
+	})
+}
+
+func TestIsObjectExistsError(t *testing.T) {
+	assert.True(t, isObjectExistsError(&pq.Error{Code: "42710", Message: `role "r" already exists`}))
+	assert.True(t, isObjectExistsError(fmt.Errorf("could not create schema: %w", &pq.Error{Code: "42P06"})))
+	assert.False(t, isObjectExistsError(&pq.Error{Code: "42P01", Message: "relation does not exist"}))
+	assert.False(t, isObjectExistsError(nil))
+	assert.Equal(t, skipStatement, skipExistingObjects("CREATE SCHEMA s", &pq.Error{Code: "42P06"}))
+	assert.Equal(t, abortStatements, skipExistingObjects("CREATE SCHEMA s", &pq.Error{Code: "42501"}))
+}
+
+func TestCreateOrAdopt(t *testing.T) {
+	schemaExists := &pq.Error{Code: "42P06", Message: `schema "s" already exists`}
+
+	run := func(statement string, adopt bool) (*recordingConn, bool, error) {
+		conn := &recordingConn{failures: map[string]error{
+			"CREATE SCHEMA s": schemaExists,
+			"CREATE SCHEMA p": &pq.Error{Code: "42501", Message: "permission denied"},
+		}}
+		db := sql.OpenDB(conn)
+		defer db.Close()
+
+		txn, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer deferredRollback(txn)
+
+		adopted, err := createOrAdopt(txn, adopt, "postgresql_schema", "db.s", statement)
+		return conn, adopted, err
+	}
+
+	t.Run("created", func(t *testing.T) {
+		_, adopted, err := run("CREATE SCHEMA n", false)
+		assert.NoError(t, err)
+		assert.False(t, adopted)
+	})
+
+	t.Run("adopted", func(t *testing.T) {
+		conn, adopted, err := run("CREATE SCHEMA s", true)
+		assert.NoError(t, err)
+		assert.True(t, adopted)
+		// The transaction can be used to reconcile the existing object
+		assert.Equal(t, []string{
+			"SAVEPOINT terraform_statement",
+			"CREATE SCHEMA s",
+			"ROLLBACK TO SAVEPOINT terraform_statement",
+			"RELEASE SAVEPOINT terraform_statement",
+		}, conn.statements)
+	})
+
+	t.Run("import suggested", func(t *testing.T) {
+		_, adopted, err := run("CREATE SCHEMA s", false)
+		assert.False(t, adopted)
+		var existsErr *objectExistsError
+		assert.ErrorAs(t, err, &existsErr)
+		assert.ErrorIs(t, err, schemaExists)
+		assert.True(t, isObjectExistsError(err))
+		assert.Equal(t,
+			`pq: schema "s" already exists; set adopt_existing = true to manage the existing object, `+
+				`or import it: terraform import postgresql_schema.<name> db.s`,
+			err.Error(),
+		)
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		_, adopted, err := run("CREATE SCHEMA p", true)
+		assert.False(t, adopted)
+		assert.EqualError(t, err, "could not execute `CREATE SCHEMA p`: pq: permission denied")
+	})
+}
+
+func TestAdoptExisting(t *testing.T) {
+	resourceSchema := resourcePostgreSQLSchema().Schema
+
+	// Without adopt_existing, the schema resource uses if_not_exists as before
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{schemaNameAttr: "public"})
+	assert.True(t, adoptExisting(d, d.Get(schemaIfNotExists).(bool)))
+	d = schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{schemaNameAttr: "public", schemaIfNotExists: false})
+	assert.False(t, adoptExisting(d, d.Get(schemaIfNotExists).(bool)))
+
+	// An explicit value is used, including false
+	d = schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{schemaNameAttr: "public", adoptExistingAttr: false})
+	assert.False(t, adoptExisting(d, true))
+	d = schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{schemaNameAttr: "public", adoptExistingAttr: true})
+	assert.True(t, adoptExisting(d, false))
+}
+
+func TestGetConfiguredInt(t *testing.T) {
+	resourceSchema := resourcePostgreSQLSequence().Schema
+
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{seqNameAttr: "seq", seqMinValueAttr: 0})
+	v, ok := getConfiguredInt(d, seqMinValueAttr)
+	assert.True(t, ok)
+	assert.Equal(t, 0, v)
+
+	_, ok = getConfiguredInt(d, seqMaxValueAttr)
+	assert.False(t, ok)
+}
+
+func TestSortedSetStrings(t *testing.T) {
+	privileges := schema.NewSet(schema.HashString, []interface{}{"UPDATE", "SELECT", "TRUNCATE", "INSERT", "DELETE"})
+	assert.Equal(t, []string{"DELETE", "INSERT", "SELECT", "TRUNCATE", "UPDATE"}, sortedSetStrings(privileges))
+	assert.Equal(t, `"s"."a","s"."b","s"."c"`, setToPgIdentList("s", schema.NewSet(schema.HashString, []interface{}{"c", "a", "b"})))
+	assert.Equal(t, "DELETE,INSERT,SELECT,TRUNCATE,UPDATE", setToPgIdentSimpleList(privileges))
+}
+
+func TestListInStateOrder(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLPublication().Schema, map[string]interface{}{
+		pubPublishAttr: []interface{}{"delete", "insert"},
+	})
+
+	// Same elements in the order of the catalog: the order of the state is kept
+	assert.Equal(t, []string{"delete", "insert"}, listInStateOrder(d, pubPublishAttr, []string{"insert", "delete"}))
+	// Different elements: the values read are returned
+	assert.Equal(t, []string{"insert", "update"}, listInStateOrder(d, pubPublishAttr, []string{"insert", "update"}))
+	assert.Equal(t, []string{"insert"}, listInStateOrder(d, pubPublishAttr, []string{"insert"}))
+	assert.Equal(t, []string{"delete", "delete"}, listInStateOrder(d, pubPublishAttr, []string{"delete", "delete"}))
+}
@@ -0,0 +1,161 @@
+package postgresql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactStatement(t *testing.T) {
+	var tests = []struct {
+		input string
+		want  string
+	}{
+		{`CREATE ROLE "foo" LOGIN PASSWORD 'secret'`, `CREATE ROLE "foo" LOGIN PASSWORD '******'`},
+		{`ALTER ROLE "foo" ENCRYPTED PASSWORD 'it''s secret' VALID UNTIL 'infinity'`, `ALTER ROLE "foo" ENCRYPTED PASSWORD '******' VALID UNTIL 'infinity'`},
+		{`ALTER ROLE "foo" PASSWORD E'back\\slash'`, `ALTER ROLE "foo" PASSWORD '******'`},
+		{`CREATE USER MAPPING FOR "foo" SERVER "srv" OPTIONS (user 'foo', password 'secret')`, `CREATE USER MAPPING FOR "foo" SERVER "srv" OPTIONS (user '******', password '******')`},
+		{`ALTER USER MAPPING FOR "foo" SERVER "srv" OPTIONS (SET sslkey 'it''s secret', ADD token E'\\x')`, `ALTER USER MAPPING FOR "foo" SERVER "srv" OPTIONS (SET sslkey '******', ADD token '******')`},
+		{`CREATE SUBSCRIPTION "sub" CONNECTION 'host=db password=secret dbname=test' PUBLICATION "pub"`, `CREATE SUBSCRIPTION "sub" CONNECTION 'host=db password=****** dbname=test' PUBLICATION "pub"`},
+		{`ALTER SUBSCRIPTION "sub" CONNECTION 'host=db password=''my secret'''`, `ALTER SUBSCRIPTION "sub" CONNECTION 'host=db password=******'`},
+		{`ALTER SUBSCRIPTION "sub" CONNECTION 'postgres://user:secret@db/test'`, `ALTER SUBSCRIPTION "sub" CONNECTION 'postgres://user:******@db/test'`},
+		{`SELECT rolname FROM pg_catalog.pg_roles WHERE rolname = $1`, `SELECT rolname FROM pg_catalog.pg_roles WHERE rolname = $1`},
+		{`ALTER ROLE "foo" PASSWORD NULL`, `ALTER ROLE "foo" PASSWORD NULL`},
+	}
+
+	for _, test := range tests {
+		if got := redactStatement(test.input); got != test.want {
+			t.Errorf("redactStatement(%q): want %q, got %q", test.input, test.want, got)
+		}
+	}
+}
+
+type fakeConn struct {
+	driver.Conn
+	executed []string
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.executed = append(c.executed, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func TestLoggingConnLogsStatements(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	inner := &fakeConn{}
+	conn := &loggingConn{Conn: inner, ctx: ctx}
+
+	statement := `CREATE ROLE "foo" LOGIN PASSWORD 'secret'`
+	if _, err := conn.ExecContext(context.Background(), statement, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(inner.executed) != 1 || inner.executed[0] != statement {
+		t.Fatalf("expected the statement to be executed unchanged, got %v", inner.executed)
+	}
+
+	logs := output.String()
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("could not decode logs: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d: %v", len(entries), entries)
+	}
+
+	entry := entries[0]
+	if entry["@level"] != "debug" {
+		t.Errorf("expected statement to be logged at debug level, got %v", entry["@level"])
+	}
+	if logged := entry["statement"]; logged != `CREATE ROLE "foo" LOGIN PASSWORD '******'` {
+		t.Errorf("unexpected logged statement: %v", logged)
+	}
+	if strings.Contains(logs, "secret") {
+		t.Errorf("password has not been masked in logs: %s", logs)
+	}
+}
+
+func TestLoggingConnLogsTransactionFields(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	conn := &loggingConn{Conn: &fakeConn{}, ctx: ctx, database: "app"}
+
+	// The statements of a transaction run without context, they are logged with the
+	// fields of the context the transaction has been started with
+	tx, err := conn.BeginTx(withStatementLogFields(context.Background(), "postgresql_grant", "bar_app_table"), driver.TxOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := conn.ExecContext(context.Background(), "GRANT SELECT ON TABLE t TO bar", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := conn.ExecContext(context.Background(), "SELECT 1", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("could not decode logs: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %d: %v", len(entries), entries)
+	}
+
+	expected := map[string]interface{}{
+		"statement":     "GRANT SELECT ON TABLE t TO bar",
+		"database":      "app",
+		"resource_type": "postgresql_grant",
+		"resource_id":   "bar_app_table",
+		"rows_affected": float64(0),
+	}
+	for key, value := range expected {
+		if entries[0][key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, entries[0][key])
+		}
+	}
+	if _, ok := entries[0]["duration_ms"]; !ok {
+		t.Errorf("expected the duration to be logged: %v", entries[0])
+	}
+
+	// The connection is back in the pool once the transaction is over
+	if _, ok := entries[1]["resource_type"]; ok {
+		t.Errorf("expected the fields of the transaction to be forgotten after it, got %v", entries[1])
+	}
+	if entries[1]["database"] != "app" {
+		t.Errorf("expected the database to be logged, got %v", entries[1])
+	}
+}
+
+func TestClientWithDatabaseKeepsResourceType(t *testing.T) {
+	config := Config{Scheme: "postgres", Host: "localhost", Port: 5432, Username: "postgres"}
+	client := config.NewClient("postgres")
+	client.resourceType = "postgresql_grant"
+
+	// The statements run on the other databases are logged with the resource type too
+	dbClient := client.withDatabase("app")
+	if dbClient.databaseName != "app" {
+		t.Errorf("expected the client of the database app, got %s", dbClient.databaseName)
+	}
+	if dbClient.resourceType != "postgresql_grant" {
+		t.Errorf("expected the resource type postgresql_grant, got %q", dbClient.resourceType)
+	}
+}
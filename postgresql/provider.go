@@ -2,10 +2,18 @@ package postgresql
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/blang/semver"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"golang.org/x/oauth2/google"
@@ -18,11 +26,14 @@ import (
 const (
 	defaultProviderMaxOpenConnections = 20
 	defaultExpectedPostgreSQLVersion  = "9.0.0"
+	defaultReadyRetryInterval         = 2 * time.Second
+	// defaultReadyTimeout caps the wait for the server when connect_timeout does not limit it
+	defaultReadyTimeout = 5 * time.Minute
 )
 
 // Provider returns a terraform.ResourceProvider.
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"scheme": {
 				Type:     schema.TypeString,
@@ -40,6 +51,19 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("PGHOST", nil),
 				Description: "Name of PostgreSQL server address to connect to",
 			},
+			"hosts": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The PostgreSQL servers of a multi-host connection (host or host:port, tried in order), used instead of host",
+			},
+			"target_session_attrs": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("PGTARGETSESSIONATTRS", nil),
+				ValidateFunc: validation.StringInSlice(targetSessionAttrs, false),
+				Description:  "The kind of server to connect to among the hosts: " + strings.Join(targetSessionAttrs, ", "),
+			},
 			"port": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -135,9 +159,50 @@ func Provider() *schema.Provider {
 				MaxItems: 1,
 			},
 			"sslrootcert": {
+				Type:          schema.TypeString,
+				Description:   "The SSL server root certificate file path. The file must contain PEM encoded data.",
+				Optional:      true,
+				ConflictsWith: []string{"sslrootcert_content"},
+			},
+			"sslrootcert_content": {
+				Type:          schema.TypeString,
+				Description:   "The PEM encoded SSL server root certificate(s), as an alternative to the sslrootcert file path.",
+				Optional:      true,
+				ConflictsWith: []string{"sslrootcert"},
+				ValidateFunc:  validateCertificateBundle,
+			},
+
+			"gssapi_auth": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Use GSSAPI (Kerberos) authentication. A Kerberos credential cache must be available (see `kinit`).",
+			},
+			"krb_srvname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PGKRBSRVNAME", nil),
+				Description: "Kerberos service name to use when authenticating with GSSAPI (defaults to `postgres`).",
+			},
+			"krb_spn": {
 				Type:        schema.TypeString,
-				Description: "The SSL server root certificate file path. The file must contain PEM encoded data.",
 				Optional:    true,
+				Description: "Kerberos service principal name to use when authenticating with GSSAPI. Takes priority over `krb_srvname`.",
+			},
+			"gss_enc_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "disable",
+				ValidateFunc: validation.StringInSlice(gssEncModes, false),
+				Description:  "Whether a GSSAPI encrypted connection should be negotiated with the server (`gssencmode`), `require` is not supported by lib/pq.",
+			},
+
+			"options": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("PGOPTIONS", nil),
+				Description:  "Command-line options sent to the server at connection start to set session parameters (e.g.: `-c search_path=myschema -c timezone=UTC`).",
+				ValidateFunc: validateConnectionOptions,
 			},
 
 			"connect_timeout": {
@@ -147,6 +212,12 @@ func Provider() *schema.Provider {
 				Description:  "Maximum wait for connection, in seconds. Zero or not specified means wait indefinitely.",
 				ValidateFunc: validation.IntAtLeast(-1),
 			},
+			"wait_for_ready": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait for the PostgreSQL server to accept connections when configuring the provider, retrying until `connect_timeout` (5 minutes if zero) expires.",
+			},
 			"max_connections": {
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -154,6 +225,30 @@ func Provider() *schema.Provider {
 				Description:  "Maximum number of connections to establish to the database. Zero means unlimited.",
 				ValidateFunc: validation.IntAtLeast(-1),
 			},
+			"isolation_level": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The isolation level of the transactions run by the provider (e.g.: `REPEATABLE READ`), defaults to the isolation level of the server.",
+				ValidateFunc: validation.StringInSlice(isolationLevels, true),
+			},
+			"log_statements": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Log every executed SQL statement at debug level with the resource running it, its duration and the number of rows affected, with passwords masked. Set it to false to turn the logging off for applies running many statements.",
+			},
+			"prefer_simple_protocol": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Run the queries with the simple query protocol, their parameters being quoted as literals, for the poolers and proxies which do not support the extended protocol.",
+			},
+			"strict_catalog_reads": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fail the refresh of the roles, grants and comments when the connected role cannot read a system catalog, instead of keeping their state with a warning.",
+			},
 			"expected_version": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -167,8 +262,11 @@ func Provider() *schema.Provider {
 			"postgresql_database":                  resourcePostgreSQLDatabase(),
 			"postgresql_default_privileges":        resourcePostgreSQLDefaultPrivileges(),
 			"postgresql_extension":                 resourcePostgreSQLExtension(),
+			"postgresql_extension_databases":       resourcePostgreSQLExtensionDatabases(),
 			"postgresql_grant":                     resourcePostgreSQLGrant(),
+			"postgresql_grant_schema_objects":      resourcePostgreSQLGrantSchemaObjects(),
 			"postgresql_grant_role":                resourcePostgreSQLGrantRole(),
+			"postgresql_grant_parameter":           resourcePostgreSQLGrantParameter(),
 			"postgresql_replication_slot":          resourcePostgreSQLReplicationSlot(),
 			"postgresql_publication":               resourcePostgreSQLPublication(),
 			"postgresql_subscription":              resourcePostgreSQLSubscription(),
@@ -176,18 +274,72 @@ func Provider() *schema.Provider {
 			"postgresql_schema":                    resourcePostgreSQLSchema(),
 			"postgresql_role":                      resourcePostgreSQLRole(),
 			"postgresql_function":                  resourcePostgreSQLFunction(),
+			"postgresql_foreign_data_wrapper":      resourcePostgreSQLForeignDataWrapper(),
 			"postgresql_server":                    resourcePostgreSQLServer(),
 			"postgresql_user_mapping":              resourcePostgreSQLUserMapping(),
+			"postgresql_foreign_table":             resourcePostgreSQLForeignTable(),
+			"postgresql_foreign_schema_import":     resourcePostgreSQLForeignSchemaImport(),
+			"postgresql_materialized_view":         resourcePostgreSQLMaterializedView(),
+			"postgresql_index":                     resourcePostgreSQLIndex(),
+			"postgresql_sequence":                  resourcePostgreSQLSequence(),
+			"postgresql_enum":                      resourcePostgreSQLEnum(),
+			"postgresql_composite_type":            resourcePostgreSQLCompositeType(),
+			"postgresql_domain":                    resourcePostgreSQLDomain(),
+			"postgresql_cast":                      resourcePostgreSQLCast(),
+			"postgresql_operator":                  resourcePostgreSQLOperator(),
+			"postgresql_operator_family":           resourcePostgreSQLOperatorFamily(),
+			"postgresql_operator_class":            resourcePostgreSQLOperatorClass(),
+			"postgresql_rule":                      resourcePostgreSQLRule(),
+			"postgresql_constraint":                resourcePostgreSQLConstraint(),
+			"postgresql_collation":                 resourcePostgreSQLCollation(),
+			"postgresql_text_search_dictionary":    resourcePostgreSQLTextSearchDictionary(),
+			"postgresql_text_search_configuration": resourcePostgreSQLTextSearchConfiguration(),
+			"postgresql_language":                  resourcePostgreSQLLanguage(),
+			"postgresql_comment":                   resourcePostgreSQLComment(),
+			"postgresql_object_ownership":          resourcePostgreSQLObjectOwnership(),
+			"postgresql_statistics":                resourcePostgreSQLStatistics(),
+			"postgresql_system_setting":            resourcePostgreSQLSystemSetting(),
+			"postgresql_cron_job":                  resourcePostgreSQLCronJob(),
+			"postgresql_sql":                       resourcePostgreSQLSQL(),
+			"postgresql_tablespace":                resourcePostgreSQLTablespace(),
+			"postgresql_table_partition":           resourcePostgreSQLTablePartition(),
+			"postgresql_table_storage_parameters":  resourcePostgreSQLTableStorageParameters(),
+			"postgresql_table_tablespace":          resourcePostgreSQLTableTablespace(),
+			"postgresql_notify":                    resourcePostgreSQLNotify(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"postgresql_schemas":   dataSourcePostgreSQLDatabaseSchemas(),
-			"postgresql_tables":    dataSourcePostgreSQLDatabaseTables(),
-			"postgresql_sequences": dataSourcePostgreSQLDatabaseSequences(),
+			"postgresql_schemas":            dataSourcePostgreSQLDatabaseSchemas(),
+			"postgresql_tables":             dataSourcePostgreSQLDatabaseTables(),
+			"postgresql_sequences":          dataSourcePostgreSQLDatabaseSequences(),
+			"postgresql_object_privileges":  dataSourcePostgreSQLObjectPrivileges(),
+			"postgresql_publications":       dataSourcePostgreSQLPublications(),
+			"postgresql_replication_slots":  dataSourcePostgreSQLReplicationSlots(),
+			"postgresql_subscriptions":      dataSourcePostgreSQLSubscriptions(),
+			"postgresql_server_info":        dataSourcePostgreSQLServerInfo(),
+			"postgresql_server_version":     dataSourcePostgreSQLServerVersion(),
+			"postgresql_locks":              dataSourcePostgreSQLLocks(),
+			"postgresql_active_connections": dataSourcePostgreSQLActiveConnections(),
+			"postgresql_columns":            dataSourcePostgreSQLColumns(),
+			"postgresql_views":              dataSourcePostgreSQLDatabaseViews(),
+			"postgresql_settings":           dataSourcePostgreSQLSettings(),
+			"postgresql_indexes":            dataSourcePostgreSQLIndexes(),
+			"postgresql_extension_objects":  dataSourcePostgreSQLExtensionObjects(),
+			"postgresql_role_settings":      dataSourcePostgreSQLRoleSettings(),
+			"postgresql_query":              dataSourcePostgreSQLQuery(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
+	}
+
+	// The statements are logged with the type of the resource or data source running them
+	for name, resource := range provider.ResourcesMap {
+		withResourceType(name, resource)
 	}
+	for name, dataSource := range provider.DataSourcesMap {
+		withResourceType(name, dataSource)
+	}
+	return provider
 }
 
 func validateExpectedVersion(v interface{}, key string) (warnings []string, errors []error) {
@@ -197,6 +349,105 @@ func validateExpectedVersion(v interface{}, key string) (warnings []string, erro
 	return
 }
 
+// isolationLevels are the transaction isolation levels supported by SET TRANSACTION
+var isolationLevels = []string{"READ UNCOMMITTED", "READ COMMITTED", "REPEATABLE READ", "SERIALIZABLE"}
+
+// settingNameRegexp matches the name of a run-time parameter, custom parameters being prefixed by their extension
+var settingNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// validateConnectionOptions checks the options are a list of run-time parameters settings
+// as accepted by the server: "-c name=value", "-cname=value" or "--name=value".
+func validateConnectionOptions(v interface{}, key string) (warnings []string, errors []error) {
+	options := splitConnectionOptions(v.(string))
+	for i := 0; i < len(options); i++ {
+		var setting string
+		switch option := options[i]; {
+		case option == "-c" && i+1 < len(options):
+			i++
+			setting = options[i]
+		case strings.HasPrefix(option, "--"):
+			setting = strings.TrimPrefix(option, "--")
+		case strings.HasPrefix(option, "-c"):
+			setting = strings.TrimPrefix(option, "-c")
+		default:
+			errors = append(errors, fmt.Errorf("%s: invalid option %q, expected -c name=value settings", key, option))
+			continue
+		}
+
+		if name := strings.SplitN(setting, "=", 2)[0]; !strings.Contains(setting, "=") || !settingNameRegexp.MatchString(name) {
+			errors = append(errors, fmt.Errorf("%s: invalid setting %q, expected name=value", key, setting))
+		}
+	}
+	return
+}
+
+// validateCertificateBundle checks the value is a list of PEM encoded certificates.
+func validateCertificateBundle(v interface{}, key string) (warnings []string, errors []error) {
+	if _, err := parseCertificateBundle(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %w", key, err))
+	}
+	return
+}
+
+// parseCertificateBundle returns the pool of the PEM encoded certificates of the bundle.
+func parseCertificateBundle(content string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	count := 0
+	rest := []byte(content)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block of type %s, expected CERTIFICATE", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse certificate: %w", err)
+		}
+		pool.AddCert(cert)
+		count++
+	}
+
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return nil, fmt.Errorf("could not decode PEM data")
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("no certificate found in PEM data")
+	}
+	return pool, nil
+}
+
+// splitConnectionOptions splits the options on whitespace, which can be escaped
+// with a backslash to be part of a value as the server does.
+func splitConnectionOptions(options string) []string {
+	var parts []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range options {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case unicode.IsSpace(r):
+			if current.Len() > 0 {
+				parts = append(parts, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
+
 func getRDSAuthToken(region string, profile string, username string, host string, port int) (string, error) {
 	endpoint := fmt.Sprintf("%s:%d", host, port)
 
@@ -245,7 +496,7 @@ func createGoogleCredsFileIfNeeded() error {
 	return os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", tmpFile.Name())
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	var sslMode string
 	if sslModeRaw, ok := d.GetOk("sslmode"); ok {
 		sslMode = sslModeRaw.(string)
@@ -262,14 +513,26 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	port := d.Get("port").(int)
 	username := d.Get("username").(string)
 
+	var hosts []string
+	for _, h := range d.Get("hosts").([]interface{}) {
+		hosts = append(hosts, h.(string))
+	}
+	if len(hosts) > 0 && d.Get("scheme").(string) != "postgres" {
+		return nil, diag.Errorf("hosts is only supported with the postgres scheme")
+	}
+
 	var password string
 	if d.Get("aws_rds_iam_auth").(bool) {
+		// The authentication token is bound to a host
+		if len(hosts) > 0 {
+			return nil, diag.Errorf("aws_rds_iam_auth cannot be used with hosts")
+		}
 		profile := d.Get("aws_rds_iam_profile").(string)
 		region := d.Get("aws_rds_iam_region").(string)
 		var err error
 		password, err = getRDSAuthToken(region, profile, username, host, port)
 		if err != nil {
-			return nil, err
+			return nil, diag.FromErr(err)
 		}
 	} else {
 		password = d.Get("password").(string)
@@ -289,6 +552,30 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		MaxConns:          d.Get("max_connections").(int),
 		ExpectedVersion:   version,
 		SSLRootCertPath:   d.Get("sslrootcert").(string),
+		KrbSrvname:        d.Get("krb_srvname").(string),
+		KrbSpn:            d.Get("krb_spn").(string),
+		Options:           d.Get("options").(string),
+		IsolationLevel:    strings.ToUpper(d.Get("isolation_level").(string)),
+		Hosts:             hosts,
+		LogStatements:     d.Get("log_statements").(bool),
+		logContext:        ctx,
+
+		PreferSimpleProtocol: d.Get("prefer_simple_protocol").(bool),
+		StrictCatalogReads:   d.Get("strict_catalog_reads").(bool),
+		TargetSessionAttrs:   d.Get("target_session_attrs").(string),
+	}
+
+	if config.LogStatements && config.Scheme != "postgres" {
+		// log_statements is enabled by default, only mention it at the level of the statements
+		tflog.Debug(ctx, "log_statements is only supported with the postgres scheme, statements will not be logged", map[string]interface{}{
+			"scheme": config.Scheme,
+		})
+	}
+
+	if config.PreferSimpleProtocol && config.Scheme != "postgres" {
+		tflog.Warn(ctx, "prefer_simple_protocol is only supported with the postgres scheme, the extended protocol will be used", map[string]interface{}{
+			"scheme": config.Scheme,
+		})
 	}
 
 	if value, ok := d.GetOk("clientcert"); ok {
@@ -300,12 +587,37 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		}
 	}
 
+	if content := d.Get("sslrootcert_content").(string); content != "" {
+		name, err := registerSSLRootCertPool(content)
+		if err != nil {
+			return nil, diag.Errorf("invalid sslrootcert_content: %v", err)
+		}
+		config.SSLRootCertPool = name
+	}
+
+	if err := checkGSSEncMode(d.Get("gss_enc_mode").(string)); err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if d.Get("gssapi_auth").(bool) {
+		if err := configureGSS(); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
 	if config.Scheme == "gcppostgres" {
 		if err := createGoogleCredsFileIfNeeded(); err != nil {
-			return nil, err
+			return nil, diag.FromErr(err)
 		}
 	}
 
 	client := config.NewClient(d.Get("database").(string))
+
+	if d.Get("wait_for_ready").(bool) {
+		timeout := time.Duration(config.ConnectTimeoutSec) * time.Second
+		if err := client.waitForReady(timeout); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
 	return client, nil
 }
@@ -2,9 +2,28 @@ package postgresql
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -29,6 +48,330 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestValidateConnectionOptions(t *testing.T) {
+	valid := []string{
+		"",
+		"-c search_path=myschema",
+		"-c search_path=myschema -c timezone=UTC",
+		"-csearch_path=myschema --timezone=UTC",
+		`-c search_path=myschema,\ public`,
+		"-c pg_stat_statements.track=all",
+		"-c search_path=",
+	}
+	for _, options := range valid {
+		if _, errs := validateConnectionOptions(options, "options"); len(errs) > 0 {
+			t.Errorf("validateConnectionOptions(%q): unexpected errors %v", options, errs)
+		}
+	}
+
+	invalid := []string{
+		"search_path=myschema",
+		"-c",
+		"-c search_path",
+		"-c search_path myschema",
+		"-B 1000",
+		"-c 1search_path=myschema",
+	}
+	for _, options := range invalid {
+		if _, errs := validateConnectionOptions(options, "options"); len(errs) == 0 {
+			t.Errorf("validateConnectionOptions(%q): expected an error", options)
+		}
+	}
+}
+
+func TestProviderConfigureGSSAPI(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential cache check is not supported on Windows")
+	}
+
+	ccache := filepath.Join(t.TempDir(), "krb5cc_test")
+	if err := os.WriteFile(ccache, []byte{}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KRB5CCNAME", ccache)
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"host":         "db.example.com",
+		"sslmode":      "disable",
+		"gssapi_auth":  true,
+		"krb_srvname":  "pgsql",
+		"krb_spn":      "pgsql/db.example.com@EXAMPLE.COM",
+		"gss_enc_mode": "prefer",
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("could not configure the provider: %v", diags)
+	}
+
+	connStr := meta.(*Client).config.connStr("postgres")
+	for _, param := range []string{"krbsrvname=pgsql", "krbspn=pgsql%2Fdb.example.com%40EXAMPLE.COM"} {
+		if !strings.Contains(connStr, param) {
+			t.Errorf("expected %s in the connection string %s", param, connStr)
+		}
+	}
+
+	// lib/pq cannot negotiate a GSSAPI encrypted connection
+	if strings.Contains(connStr, "gssencmode") {
+		t.Errorf("unexpected gssencmode in the connection string %s", connStr)
+	}
+	required := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"host":         "db.example.com",
+		"sslmode":      "disable",
+		"gssapi_auth":  true,
+		"gss_enc_mode": "require",
+	})
+	if _, diags := providerConfigure(context.Background(), required); !diags.HasError() || !strings.Contains(diags[0].Summary, `gss_enc_mode "require" is not supported`) {
+		t.Errorf("expected an error with gss_enc_mode require, got %v", diags)
+	}
+
+	// Without credential cache, the provider cannot be configured
+	t.Setenv("KRB5CCNAME", filepath.Join(t.TempDir(), "missing"))
+	if _, diags := providerConfigure(context.Background(), d); !diags.HasError() {
+		t.Error("expected an error without Kerberos credential cache")
+	}
+}
+
+func TestNewKerberosGSS(t *testing.T) {
+	krb5Config := filepath.Join(t.TempDir(), "krb5.conf")
+	if err := os.WriteFile(krb5Config, []byte("[libdefaults]\n  default_realm = EXAMPLE.COM\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ccache := filepath.Join(t.TempDir(), "krb5cc_test")
+	if err := os.WriteFile(ccache, []byte("not a credential cache"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		krb5Config string
+		ccName     string
+		wantErr    string
+	}{
+		{filepath.Join(t.TempDir(), "missing.conf"), ccache, "could not load the Kerberos configuration"},
+		{krb5Config, "KEYRING:persistent:1000", "the Kerberos credential cache KEYRING:persistent:1000 is not supported"},
+		{krb5Config, ccache, "could not load the Kerberos credential cache " + ccache},
+	}
+
+	for _, test := range tests {
+		t.Setenv("KRB5_CONFIG", test.krb5Config)
+		t.Setenv("KRB5CCNAME", test.ccName)
+
+		// The provider registered in lib/pq reports why it cannot authenticate
+		if _, err := newKerberosGSS(); err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("newKerberosGSS() with KRB5_CONFIG=%s KRB5CCNAME=%s returned %v, want error: %s", test.krb5Config, test.ccName, err, test.wantErr)
+		}
+	}
+}
+
+func TestParseCertificateBundle(t *testing.T) {
+	first, second := testGenerateCertificate(t, "first"), testGenerateCertificate(t, "second")
+
+	for _, content := range []string{first, first + second, "\n" + first + "\n\n" + second + "\n"} {
+		if _, err := parseCertificateBundle(content); err != nil {
+			t.Errorf("parseCertificateBundle(%q): unexpected error %v", content, err)
+		}
+	}
+
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")}))
+	invalidCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not a certificate")}))
+	for _, content := range []string{"", "not a certificate", first + "garbage", privateKey, invalidCertificate} {
+		if _, err := parseCertificateBundle(content); err == nil {
+			t.Errorf("parseCertificateBundle(%q): expected an error", content)
+		}
+	}
+}
+
+// TestProviderConfigureSSLRootCertContent connects with an inline CA bundle to a server negotiating SSL
+// as PostgreSQL: the error of the server is only received once its certificate has been verified.
+func TestProviderConfigureSSLRootCertContent(t *testing.T) {
+	caCert, serverCert := testGenerateServerCertificate(t)
+	host, port, err := net.SplitHostPort(testTLSServer(t, serverCert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherCert := testGenerateCertificate(t, "other")
+	var tests = []struct {
+		sslmode  string
+		rootCert string
+		wantErr  string
+	}{
+		{"verify-full", caCert, "rejected by the test server"},
+		{"verify-full", otherCert, "x509: certificate signed by unknown authority"},
+		{"verify-ca", caCert, "rejected by the test server"},
+		{"verify-ca", otherCert, "x509: certificate signed by unknown authority"},
+		{"require", otherCert, "x509: certificate signed by unknown authority"},
+	}
+
+	for _, test := range tests {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+			"host":                host,
+			"port":                portNumber,
+			"sslmode":             test.sslmode,
+			"sslrootcert_content": test.rootCert,
+		})
+		meta, diags := providerConfigure(context.Background(), d)
+		if diags.HasError() {
+			t.Fatalf("could not configure the provider: %v", diags)
+		}
+
+		db, err := sql.Open(proxyDriverName, meta.(*Client).config.connStr("postgres"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.Ping()
+		db.Close()
+		if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("expected the connection with sslmode %s to fail with %q, got %v", test.sslmode, test.wantErr, err)
+		}
+	}
+}
+
+// testTLSServer starts a server accepting the SSL requests of the PostgreSQL protocol with the certificate,
+// then rejecting the connections with an error, and returns its address.
+func testTLSServer(t *testing.T, cert tls.Certificate) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+
+				// SSLRequest: length and request code
+				if _, err := io.CopyN(io.Discard, conn, 8); err != nil {
+					return
+				}
+				if _, err := conn.Write([]byte{'S'}); err != nil {
+					return
+				}
+
+				tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}})
+				header := make([]byte, 4)
+				if _, err := io.ReadFull(tlsConn, header); err != nil {
+					return
+				}
+				if _, err := io.CopyN(io.Discard, tlsConn, int64(binary.BigEndian.Uint32(header))-4); err != nil {
+					return
+				}
+
+				// ErrorResponse with its severity, code and message
+				fields := "SFATAL\x00C28000\x00Mrejected by the test server\x00\x00"
+				message := make([]byte, 5, 5+len(fields))
+				message[0] = 'E'
+				binary.BigEndian.PutUint32(message[1:], uint32(4+len(fields)))
+				tlsConn.Write(append(message, fields...))
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// testGenerateServerCertificate returns a PEM encoded self-signed certificate for 127.0.0.1,
+// and the same certificate with its key for the server.
+func testGenerateServerCertificate(t *testing.T) (string, tls.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	serverCert, err := tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	if err != nil {
+		t.Fatalf("could not load server certificate: %v", err)
+	}
+	return string(certPEM), serverCert
+}
+
+// TestAccProvider_SSLRootCertContent connects with the CA bundle of the file set in PGSSLROOTCERT
+// given inline, the server certificate must be signed by this CA.
+func TestAccProvider_SSLRootCertContent(t *testing.T) {
+	skipIfNotAcc(t)
+
+	rootCertPath := os.Getenv("PGSSLROOTCERT")
+	if rootCertPath == "" {
+		t.Skip("PGSSLROOTCERT must be set to test the connection with an inline CA bundle")
+	}
+	content, err := os.ReadFile(rootCertPath)
+	if err != nil {
+		t.Fatalf("could not read %s: %v", rootCertPath, err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				provider "postgresql" {
+					sslmode             = "verify-ca"
+					sslrootcert_content = <<-EOT
+%sEOT
+				}
+
+				data "postgresql_server_info" "test" {}
+				`, content),
+				Check: resource.TestCheckResourceAttrSet("data.postgresql_server_info.test", "version"),
+			},
+		},
+	})
+}
+
+// testGenerateCertificate returns a PEM encoded self-signed certificate.
+func testGenerateCertificate(t *testing.T, commonName string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
 func testAccPreCheck(t *testing.T) {
 	var host string
 	if host = os.Getenv("PGHOST"); host == "" {
@@ -16,7 +16,23 @@ const proxyDriverName = "postgresql-proxy"
 type proxyDriver struct{}
 
 func (d proxyDriver) Open(name string) (driver.Conn, error) {
-	return pq.DialOpen(d, name)
+	hosts, attrs, err := splitHosts(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) == 1 && attrs == "any" {
+		return d.open(hosts[0].dsn)
+	}
+
+	return openTargetSession(d.open, hosts, attrs)
+}
+
+func (d proxyDriver) open(dsn string) (driver.Conn, error) {
+	dialer, dsn, err := sslRootCertPoolDialer(d, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return pq.DialOpen(dialer, dsn)
 }
 
 func (d proxyDriver) Dial(network, address string) (net.Conn, error) {
@@ -0,0 +1,274 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	castDatabaseAttr   = "database"
+	castSourceTypeAttr = "source_type"
+	castTargetTypeAttr = "target_type"
+	castFunctionAttr   = "function"
+	castInOutAttr      = "inout"
+	castContextAttr    = "context"
+)
+
+// castContexts maps the context of a cast to its castcontext in pg_cast
+var castContexts = map[string]string{
+	"explicit":   "e",
+	"assignment": "a",
+	"implicit":   "i",
+}
+
+func resourcePostgreSQLCast() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLCastCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLCastRead),
+		Update: PGResourceFunc(resourcePostgreSQLCastUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLCastDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			castDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the cast is created",
+			},
+			castSourceTypeAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: typeDiffSuppressFunc,
+				Description:      "The source data type of the cast",
+			},
+			castTargetTypeAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: typeDiffSuppressFunc,
+				Description:      "The target data type of the cast",
+			},
+			castFunctionAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ConflictsWith:    []string{castInOutAttr},
+				DiffSuppressFunc: functionSignatureDiffSuppressFunc,
+				Description:      "The signature of the function used to perform the cast (e.g.: my_schema.my_func(my_type)), the types are binary coercible if neither function nor inout are set",
+			},
+			castInOutAttr: {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				Default:       false,
+				ConflictsWith: []string{castFunctionAttr},
+				Description:   "Perform the cast by invoking the output function of the source type and passing the resulting string to the input function of the target type",
+			},
+			castContextAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "explicit",
+				ValidateFunc: validation.StringInSlice([]string{"explicit", "assignment", "implicit"}, false),
+				Description:  "The contexts in which the cast can be invoked implicitly (one of: explicit, assignment, implicit)",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLCastCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	sourceType := d.Get(castSourceTypeAttr).(string)
+	targetType := d.Get(castTargetTypeAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(createCastQuery(d)); err != nil {
+		return fmt.Errorf("could not create cast from %s to %s: %w", sourceType, targetType, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating cast: %w", err)
+	}
+
+	d.SetId(generateCastID(database, sourceType, targetType))
+
+	return resourcePostgreSQLCastReadImpl(db, d)
+}
+
+func createCastQuery(d *schema.ResourceData) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "CREATE CAST (%s AS %s)", d.Get(castSourceTypeAttr).(string), d.Get(castTargetTypeAttr).(string))
+
+	switch {
+	case d.Get(castFunctionAttr).(string) != "":
+		fmt.Fprint(b, " WITH FUNCTION ", d.Get(castFunctionAttr).(string))
+	case d.Get(castInOutAttr).(bool):
+		fmt.Fprint(b, " WITH INOUT")
+	default:
+		fmt.Fprint(b, " WITHOUT FUNCTION")
+	}
+
+	switch d.Get(castContextAttr).(string) {
+	case "assignment":
+		fmt.Fprint(b, " AS ASSIGNMENT")
+	case "implicit":
+		fmt.Fprint(b, " AS IMPLICIT")
+	}
+
+	return b.String()
+}
+
+func resourcePostgreSQLCastRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLCastReadImpl(db, d)
+}
+
+func resourcePostgreSQLCastReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, sourceType, targetType, err := getDBCastTypes(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var formattedSource, formattedTarget, method, castContext, function string
+	// to_regtype returns NULL if the type does not exist anymore, which is handled as a missing cast
+	err = txn.QueryRow(
+		`SELECT pg_catalog.format_type(c.castsource, NULL), pg_catalog.format_type(c.casttarget, NULL), `+
+			`c.castmethod, c.castcontext, CASE WHEN c.castmethod = 'f' THEN c.castfunc::regprocedure::text ELSE '' END `+
+			`FROM pg_catalog.pg_cast c `+
+			`WHERE c.castsource = pg_catalog.to_regtype($1) AND c.casttarget = pg_catalog.to_regtype($2)`,
+		sourceType, targetType,
+	).Scan(&formattedSource, &formattedTarget, &method, &castContext, &function)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL cast from %s to %s not found in database %s", sourceType, targetType, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading cast: %w", err)
+	}
+
+	// The types are looked up with the names of the configuration, which may be written differently
+	// from format_type (e.g.: schema qualified), so we only take them from the catalog when importing.
+	if d.Get(castSourceTypeAttr).(string) == "" {
+		d.Set(castSourceTypeAttr, formattedSource)
+		d.Set(castTargetTypeAttr, formattedTarget)
+	}
+	if normalizeFunctionSignature(d.Get(castFunctionAttr).(string)) != normalizeFunctionSignature(function) {
+		d.Set(castFunctionAttr, function)
+	}
+
+	for name, value := range castContexts {
+		if value == castContext {
+			d.Set(castContextAttr, name)
+		}
+	}
+
+	d.Set(castDatabaseAttr, database)
+	d.Set(castInOutAttr, method == "i")
+	d.SetId(generateCastID(database, sourceType, targetType))
+
+	return nil
+}
+
+// resourcePostgreSQLCastUpdate changes the context of the cast. As ALTER CAST does not exist,
+// the cast is dropped and created again in the same transaction.
+func resourcePostgreSQLCastUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(castContextAttr) {
+		return resourcePostgreSQLCastReadImpl(db, d)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	sourceType := d.Get(castSourceTypeAttr).(string)
+	targetType := d.Get(castTargetTypeAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf("DROP CAST (%s AS %s)", sourceType, targetType)); err != nil {
+		return fmt.Errorf("could not drop cast from %s to %s: %w", sourceType, targetType, err)
+	}
+	if _, err := txn.Exec(createCastQuery(d)); err != nil {
+		return fmt.Errorf("could not create cast from %s to %s: %w", sourceType, targetType, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating cast: %w", err)
+	}
+
+	return resourcePostgreSQLCastReadImpl(db, d)
+}
+
+func resourcePostgreSQLCastDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	sourceType := d.Get(castSourceTypeAttr).(string)
+	targetType := d.Get(castTargetTypeAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf("DROP CAST (%s AS %s)", sourceType, targetType)); err != nil {
+		return fmt.Errorf("could not drop cast: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting cast: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// generateCastID uses | as separator as type names can contain dots (schema qualified)
+// and spaces (e.g.: character varying).
+func generateCastID(database, sourceType, targetType string) string {
+	return strings.Join([]string{database, sourceType, targetType}, "|")
+}
+
+// getDBCastTypes returns database, source and target types of the cast. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBCastTypes(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	sourceType := d.Get(castSourceTypeAttr).(string)
+	targetType := d.Get(castTargetTypeAttr).(string)
+
+	// When importing, we have to parse the ID to find database and types.
+	if sourceType == "" {
+		parsed := strings.Split(d.Id(), "|")
+		if len(parsed) != 3 || parsed[1] == "" || parsed[2] == "" {
+			return "", "", "", fmt.Errorf("cast ID %s has not the expected format 'database|source_type|target_type': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		sourceType = parsed[1]
+		targetType = parsed[2]
+	}
+	return database, sourceType, targetType, nil
+}
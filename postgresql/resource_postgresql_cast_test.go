@@ -0,0 +1,137 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlCast_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TYPE test_schema.geo AS (x float8, y float8)")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE FUNCTION test_schema.geo_to_point(test_schema.geo) RETURNS point IMMUTABLE LANGUAGE sql AS 'SELECT point($1.x, $1.y)'")
+
+	config := `
+resource "postgresql_cast" "geo_to_point" {
+  database    = "%s"
+  source_type = "test_schema.geo"
+  target_type = "point"
+  function    = "test_schema.geo_to_point(test_schema.geo)"
+  context     = "%s"
+}
+
+resource "postgresql_cast" "geo_to_text" {
+  database    = "%s"
+  source_type = "test_schema.geo"
+  target_type = "text"
+  inout       = true
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCastDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, "explicit", dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlCastExists("postgresql_cast.geo_to_point"),
+					resource.TestCheckResourceAttr("postgresql_cast.geo_to_point", "context", "explicit"),
+					resource.TestCheckResourceAttr("postgresql_cast.geo_to_point", "inout", "false"),
+					testAccCheckPostgresqlCastExists("postgresql_cast.geo_to_text"),
+					resource.TestCheckResourceAttr("postgresql_cast.geo_to_text", "inout", "true"),
+					resource.TestCheckResourceAttr("postgresql_cast.geo_to_text", "function", ""),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, "assignment", dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlCastExists("postgresql_cast.geo_to_point"),
+					resource.TestCheckResourceAttr("postgresql_cast.geo_to_point", "context", "assignment"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_cast.geo_to_point",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s|test_schema.geo|point", dbName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlCastDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_cast" {
+			continue
+		}
+
+		exists, err := checkCastExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking cast %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Cast still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlCastExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		exists, err := checkCastExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking cast %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Cast not found")
+		}
+
+		return nil
+	}
+}
+
+func checkCastExists(rs *terraform.ResourceState) (bool, error) {
+	client := testAccProvider.Meta().(*Client)
+	txn, err := startTransaction(client, rs.Primary.Attributes[castDatabaseAttr])
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	var _rez bool
+	err = txn.QueryRow(
+		"SELECT TRUE FROM pg_catalog.pg_cast WHERE castsource = pg_catalog.to_regtype($1) AND casttarget = pg_catalog.to_regtype($2)",
+		rs.Primary.Attributes[castSourceTypeAttr], rs.Primary.Attributes[castTargetTypeAttr],
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
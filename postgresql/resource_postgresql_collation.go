@@ -0,0 +1,423 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	collationNameAttr            = "name"
+	collationSchemaAttr          = "schema"
+	collationDatabaseAttr        = "database"
+	collationProviderAttr        = "locale_provider"
+	collationLocaleAttr          = "locale"
+	collationLCCollateAttr       = "lc_collate"
+	collationLCCTypeAttr         = "lc_ctype"
+	collationDeterministicAttr   = "deterministic"
+	collationRulesAttr           = "rules"
+	collationOwnerAttr           = "owner"
+	collationVersionAttr         = "version"
+	collationActualVersionAttr   = "actual_version"
+	collationVersionMismatchAttr = "version_mismatch"
+)
+
+func resourcePostgreSQLCollation() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLCollationCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLCollationRead),
+		Update: PGResourceFunc(resourcePostgreSQLCollationUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLCollationDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			collationNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the collation",
+			},
+			collationSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the collation is created",
+			},
+			collationDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the collation is created",
+			},
+			collationProviderAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "libc",
+				ValidateFunc: validation.StringInSlice([]string{"libc", "icu"}, false),
+				Description:  "The locale provider of the collation (libc or icu)",
+			},
+			collationLocaleAttr: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{collationLCCollateAttr, collationLCCTypeAttr},
+				Description:   "The locale of the collation, sets both lc_collate and lc_ctype for the libc provider",
+			},
+			collationLCCollateAttr: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{collationLocaleAttr},
+				Description:   "The LC_COLLATE locale category of the collation",
+			},
+			collationLCCTypeAttr: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{collationLocaleAttr},
+				Description:   "The LC_CTYPE locale category of the collation",
+			},
+			collationDeterministicAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Whether the collation uses deterministic comparisons, nondeterministic collations are only supported by the icu provider",
+			},
+			collationRulesAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The custom ICU collation rules of the collation",
+			},
+			collationOwnerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The owner of the collation",
+			},
+			collationVersionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the collation recorded when it has been created or refreshed",
+			},
+			collationActualVersionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the collation currently provided by the operating system or ICU library",
+			},
+			collationVersionMismatchAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the recorded version of the collation differs from its actual version, the indexes using it may be corrupted",
+			},
+		},
+	}
+}
+
+func checkCollationFeatures(db *DBConnection, d *schema.ResourceData) error {
+	provider := d.Get(collationProviderAttr).(string)
+	if provider != "libc" && !db.featureSupported(featureCollationProvider) {
+		return fmt.Errorf("the %s collation provider is not supported %s", provider, db.unsupportedServer())
+	}
+	if !d.Get(collationDeterministicAttr).(bool) {
+		if !db.featureSupported(featureCollationDeterministic) {
+			return fmt.Errorf("nondeterministic collations are not supported %s", db.unsupportedServer())
+		}
+		if provider != "icu" {
+			return fmt.Errorf("nondeterministic collations are only supported by the icu provider")
+		}
+	}
+	if d.Get(collationRulesAttr).(string) != "" {
+		if !db.featureSupported(featureCollationRules) {
+			return fmt.Errorf("collation rules are not supported %s", db.unsupportedServer())
+		}
+		if provider != "icu" {
+			return fmt.Errorf("collation rules are only supported by the icu provider")
+		}
+	}
+	if d.Get(collationLocaleAttr).(string) == "" && (d.Get(collationLCCollateAttr).(string) == "" || d.Get(collationLCCTypeAttr).(string) == "") {
+		return fmt.Errorf("either locale or both lc_collate and lc_ctype must be set")
+	}
+	return nil
+}
+
+func createCollationQuery(d *schema.ResourceData) string {
+	options := []string{}
+	if provider := d.Get(collationProviderAttr).(string); provider != "libc" {
+		options = append(options, "PROVIDER = "+provider)
+	}
+	if v, ok := d.GetOk(collationLocaleAttr); ok {
+		options = append(options, "LOCALE = "+pq.QuoteLiteral(v.(string)))
+	} else {
+		options = append(options,
+			"LC_COLLATE = "+pq.QuoteLiteral(d.Get(collationLCCollateAttr).(string)),
+			"LC_CTYPE = "+pq.QuoteLiteral(d.Get(collationLCCTypeAttr).(string)),
+		)
+	}
+	if !d.Get(collationDeterministicAttr).(bool) {
+		options = append(options, "DETERMINISTIC = false")
+	}
+	if v, ok := d.GetOk(collationRulesAttr); ok {
+		options = append(options, "RULES = "+pq.QuoteLiteral(v.(string)))
+	}
+
+	return fmt.Sprintf("CREATE COLLATION %s.%s (%s)",
+		pq.QuoteIdentifier(d.Get(collationSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(collationNameAttr).(string)),
+		strings.Join(options, ", "),
+	)
+}
+
+func resourcePostgreSQLCollationCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := checkCollationFeatures(db, d); err != nil {
+		return err
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(collationSchemaAttr).(string)
+	collationName := d.Get(collationNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(createCollationQuery(d)); err != nil {
+		return fmt.Errorf("could not create collation %s: %w", collationName, err)
+	}
+
+	if v, ok := d.GetOk(collationOwnerAttr); ok {
+		if err := alterCollationOwner(txn, schemaName, collationName, v.(string)); err != nil {
+			return err
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating collation: %w", err)
+	}
+
+	d.SetId(generateCollationID(database, schemaName, collationName))
+
+	return resourcePostgreSQLCollationReadImpl(db, d)
+}
+
+func resourcePostgreSQLCollationRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLCollationReadImpl(db, d)
+}
+
+func resourcePostgreSQLCollationReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, collationName, err := getDBCollationName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	provider, locale, deterministic, rules, version, actualVersion := "'c'", "''", "true", "''", "''", "''"
+	if db.featureSupported(featureCollationProvider) {
+		provider = "c.collprovider"
+		version = "COALESCE(c.collversion, '')"
+		actualVersion = "COALESCE(pg_catalog.pg_collation_actual_version(c.oid), '')"
+	}
+	switch {
+	case db.featureSupported(featureCollationLocale):
+		locale = "COALESCE(c.colllocale, '')"
+	case db.featureSupported(featureCollationICULocale):
+		locale = "COALESCE(c.colliculocale, '')"
+	}
+	if db.featureSupported(featureCollationDeterministic) {
+		deterministic = "c.collisdeterministic"
+	}
+	if db.featureSupported(featureCollationRules) {
+		rules = "COALESCE(c.collicurules, '')"
+	}
+
+	// Collations are only usable with their encoding (or any encoding for -1)
+	query := fmt.Sprintf(
+		`SELECT %s, COALESCE(c.collcollate, ''), COALESCE(c.collctype, ''), %s, %s, %s, %s, %s, `+
+			`pg_catalog.pg_get_userbyid(c.collowner) `+
+			`FROM pg_catalog.pg_collation c JOIN pg_catalog.pg_namespace n ON n.oid = c.collnamespace `+
+			`WHERE n.nspname = $1 AND c.collname = $2 `+
+			`AND c.collencoding IN (-1, pg_catalog.pg_char_to_encoding(pg_catalog.getdatabaseencoding()))`,
+		provider, locale, deterministic, rules, version, actualVersion,
+	)
+
+	var providerCode, lcCollate, lcCType, iculocale, collationRules, collationVersion, collationActualVersion, owner string
+	var isDeterministic bool
+	err = txn.QueryRow(query, schemaName, collationName).Scan(
+		&providerCode, &lcCollate, &lcCType, &iculocale, &isDeterministic, &collationRules,
+		&collationVersion, &collationActualVersion, &owner,
+	)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL collation (%s.%s) not found in database %s", schemaName, collationName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading collation: %w", err)
+	}
+
+	providerName := "libc"
+	switch providerCode {
+	case "i":
+		providerName = "icu"
+		// Before Postgres 15, the ICU locale is stored in collcollate
+		if iculocale == "" {
+			iculocale = lcCollate
+		}
+	case "b":
+		providerName = "builtin"
+	}
+
+	switch {
+	case providerName != "libc":
+		// Postgres canonicalizes ICU locales (e.g.: en_US becomes en-US) so we only take it
+		// from the catalog when importing.
+		if d.Get(collationLocaleAttr).(string) == "" {
+			d.Set(collationLocaleAttr, iculocale)
+		}
+	case lcCollate == lcCType:
+		d.Set(collationLocaleAttr, lcCollate)
+	default:
+		d.Set(collationLocaleAttr, "")
+	}
+
+	versionMismatch := collationVersion != "" && collationActualVersion != "" && collationVersion != collationActualVersion
+	if versionMismatch {
+		log.Printf(
+			"[WARN] PostgreSQL collation %s.%s in database %s has version %s but the actual version is %s, "+
+				"the indexes using it should be rebuilt before running ALTER COLLATION ... REFRESH VERSION",
+			schemaName, collationName, database, collationVersion, collationActualVersion,
+		)
+	}
+
+	d.Set(collationNameAttr, collationName)
+	d.Set(collationSchemaAttr, schemaName)
+	d.Set(collationDatabaseAttr, database)
+	d.Set(collationProviderAttr, providerName)
+	d.Set(collationLCCollateAttr, lcCollate)
+	d.Set(collationLCCTypeAttr, lcCType)
+	d.Set(collationDeterministicAttr, isDeterministic)
+	d.Set(collationRulesAttr, collationRules)
+	d.Set(collationOwnerAttr, owner)
+	d.Set(collationVersionAttr, collationVersion)
+	d.Set(collationActualVersionAttr, collationActualVersion)
+	d.Set(collationVersionMismatchAttr, versionMismatch)
+	d.SetId(generateCollationID(database, schemaName, collationName))
+
+	return nil
+}
+
+func resourcePostgreSQLCollationUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(collationOwnerAttr) {
+		return resourcePostgreSQLCollationReadImpl(db, d)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	owner := d.Get(collationOwnerAttr).(string)
+	if owner == "" {
+		return fmt.Errorf("Error setting collation owner to an empty string")
+	}
+	if err := alterCollationOwner(txn, d.Get(collationSchemaAttr).(string), d.Get(collationNameAttr).(string), owner); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating collation: %w", err)
+	}
+
+	return resourcePostgreSQLCollationReadImpl(db, d)
+}
+
+func alterCollationOwner(txn *sql.Tx, schemaName, collationName, owner string) error {
+	sql := fmt.Sprintf("ALTER COLLATION %s.%s OWNER TO %s",
+		pq.QuoteIdentifier(schemaName),
+		pq.QuoteIdentifier(collationName),
+		pq.QuoteIdentifier(owner),
+	)
+
+	return withRolesGranted(txn, []string{owner}, func() error {
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating collation OWNER: %w", err)
+		}
+		return nil
+	})
+}
+
+func resourcePostgreSQLCollationDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(collationSchemaAttr).(string)
+	collationName := d.Get(collationNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	sql := fmt.Sprintf("DROP COLLATION %s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(collationName))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop collation: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting collation: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func generateCollationID(database, schemaName, collationName string) string {
+	return joinResourceID(database, schemaName, collationName)
+}
+
+// getDBCollationName returns database, schema and collation name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBCollationName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(collationSchemaAttr).(string)
+	collationName := d.Get(collationNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and collation names.
+	if collationName == "" {
+		// The name of a collation usually contains dots (e.g.: fr_FR.utf8)
+		parsed := splitResourceIDN(d.Id(), 3)
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("collation ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		collationName = parsed[2]
+	}
+	return database, schemaName, collationName, nil
+}
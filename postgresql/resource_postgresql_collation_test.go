@@ -0,0 +1,226 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCreateCollationQuery(t *testing.T) {
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			raw:      map[string]interface{}{"name": "french", "locale": "fr_FR.utf8"},
+			expected: `CREATE COLLATION "public"."french" (LOCALE = 'fr_FR.utf8')`,
+		},
+		{
+			raw:      map[string]interface{}{"name": "mixed", "schema": "test", "lc_collate": "fr_FR.utf8", "lc_ctype": "C"},
+			expected: `CREATE COLLATION "test"."mixed" (LC_COLLATE = 'fr_FR.utf8', LC_CTYPE = 'C')`,
+		},
+		{
+			raw:      map[string]interface{}{"name": "ci", "locale_provider": "icu", "locale": "und-u-ks-level2", "deterministic": false},
+			expected: `CREATE COLLATION "public"."ci" (PROVIDER = icu, LOCALE = 'und-u-ks-level2', DETERMINISTIC = false)`,
+		},
+		{
+			raw:      map[string]interface{}{"name": "custom", "locale_provider": "icu", "locale": "und", "rules": "&a < g"},
+			expected: `CREATE COLLATION "public"."custom" (PROVIDER = icu, LOCALE = 'und', RULES = '&a < g')`,
+		},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourcePostgreSQLCollation().Schema, c.raw)
+		if out := createCollationQuery(d); out != c.expected {
+			t.Errorf("createCollationQuery(%v): expected %q, got %q", c.raw, c.expected, out)
+		}
+	}
+}
+
+func TestAccPostgresqlCollation_ICU(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE ROLE test_collation_owner")
+	defer dbExecute(t, testConfig.connStr(dbName), "DROP ROLE test_collation_owner")
+
+	config := `
+resource "postgresql_collation" "case_insensitive" {
+  database        = "%s"
+  schema          = "test_schema"
+  name            = "case_insensitive"
+  locale_provider = "icu"
+  locale          = "und-u-ks-level2"
+  deterministic   = false
+  %s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureCollationDeterministic)
+			testCheckICUSupport(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCollationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlCollationExists("postgresql_collation.case_insensitive"),
+					resource.TestCheckResourceAttr("postgresql_collation.case_insensitive", "locale_provider", "icu"),
+					resource.TestCheckResourceAttr("postgresql_collation.case_insensitive", "deterministic", "false"),
+					resource.TestCheckResourceAttrSet("postgresql_collation.case_insensitive", "version"),
+					resource.TestCheckResourceAttr("postgresql_collation.case_insensitive", "version_mismatch", "false"),
+				),
+			},
+			{
+				// The owner is updated in place
+				Config: fmt.Sprintf(config, dbName, `owner = "test_collation_owner"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlCollationExists("postgresql_collation.case_insensitive"),
+					resource.TestCheckResourceAttr("postgresql_collation.case_insensitive", "owner", "test_collation_owner"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_collation.case_insensitive",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlCollation_Libc(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_collation" "posix" {
+  database   = "%s"
+  schema     = "test_schema"
+  name       = "posix.utf8"
+  lc_collate = "POSIX"
+  lc_ctype   = "C"
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCollationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlCollationExists("postgresql_collation.posix"),
+					resource.TestCheckResourceAttr("postgresql_collation.posix", "locale_provider", "libc"),
+					resource.TestCheckResourceAttr("postgresql_collation.posix", "lc_collate", "POSIX"),
+					resource.TestCheckResourceAttr("postgresql_collation.posix", "lc_ctype", "C"),
+					resource.TestCheckResourceAttr("postgresql_collation.posix", "locale", ""),
+				),
+			},
+			{
+				ResourceName:      "postgresql_collation.posix",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testCheckICUSupport(t *testing.T) {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		t.Fatalf("could connect to database: %v", err)
+	}
+
+	var icu bool
+	if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_collation WHERE collprovider = 'i')").Scan(&icu); err != nil {
+		t.Fatalf("could not check ICU support: %v", err)
+	}
+	if !icu {
+		t.Skip("Skip test: Postgres has been built without ICU support")
+	}
+}
+
+func testAccCheckPostgresqlCollationDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_collation" {
+			continue
+		}
+
+		exists, err := checkCollationExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking collation %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Collation still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlCollationExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		exists, err := checkCollationExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking collation %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Collation not found")
+		}
+
+		return nil
+	}
+}
+
+func checkCollationExists(rs *terraform.ResourceState) (bool, error) {
+	client := testAccProvider.Meta().(*Client)
+	txn, err := startTransaction(client, rs.Primary.Attributes[collationDatabaseAttr])
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	var _rez bool
+	err = txn.QueryRow(
+		"SELECT TRUE FROM pg_catalog.pg_collation c JOIN pg_catalog.pg_namespace n ON n.oid = c.collnamespace "+
+			"WHERE n.nspname = $1 AND c.collname = $2",
+		rs.Primary.Attributes[collationSchemaAttr], rs.Primary.Attributes[collationNameAttr],
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,392 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	commentDatabaseAttr   = "database"
+	commentObjectTypeAttr = "object_type"
+	commentSchemaAttr     = "schema"
+	commentObjectNameAttr = "object_name"
+	commentCommentAttr    = "comment"
+	commentIgnorePattern  = "comment_ignore_pattern"
+)
+
+// commentObjectType describes how to comment an object type and where to read its comment.
+type commentObjectType struct {
+	// keyword is the object type in the COMMENT ON statement
+	keyword string
+	// catalog is the system catalog of the objects
+	catalog string
+	// nameColumn is the column of the catalog with the name of the objects
+	nameColumn string
+	// schemaColumn is the column of the catalog with the namespace of the objects, if they belong to a schema
+	schemaColumn string
+	// shared objects (e.g.: databases) have their comments in pg_shdescription instead of pg_description
+	shared bool
+}
+
+var commentObjectTypes = map[string]commentObjectType{
+	"database":   {keyword: "DATABASE", catalog: "pg_database", nameColumn: "datname", shared: true},
+	"extension":  {keyword: "EXTENSION", catalog: "pg_extension", nameColumn: "extname"},
+	"language":   {keyword: "LANGUAGE", catalog: "pg_language", nameColumn: "lanname"},
+	"schema":     {keyword: "SCHEMA", catalog: "pg_namespace", nameColumn: "nspname"},
+	"tablespace": {keyword: "TABLESPACE", catalog: "pg_tablespace", nameColumn: "spcname", shared: true},
+	"type":       {keyword: "TYPE", catalog: "pg_type", nameColumn: "typname", schemaColumn: "typnamespace"},
+}
+
+func resourcePostgreSQLComment() *schema.Resource {
+	objectTypes := make([]string, 0, len(commentObjectTypes))
+	for objectType := range commentObjectTypes {
+		objectTypes = append(objectTypes, objectType)
+	}
+	sort.Strings(objectTypes)
+
+	resource := &schema.Resource{
+		// Version 1 escapes the dots of the names in the ID
+		SchemaVersion: 1,
+		Create:        PGResourceFunc(resourcePostgreSQLCommentCreate),
+		ReadContext:   PGResourceContextFunc(resourcePostgreSQLCommentRead),
+		Update:        PGResourceFunc(resourcePostgreSQLCommentUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLCommentDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			commentDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the object, not used for databases and tablespaces",
+			},
+			commentObjectTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(objectTypes, false),
+				Description:  "The type of the commented object",
+			},
+			commentSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The schema of the object, for objects belonging to a schema (defaults to public)",
+			},
+			commentObjectNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the commented object",
+			},
+			commentCommentAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The comment of the object",
+
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					// The comment is always set when the resource is created
+					if d.Id() == "" {
+						return false
+					}
+					return commentsMatchIgnoringPattern(old, new, d.Get(commentIgnorePattern).(string))
+				},
+			},
+			commentIgnorePattern: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Regular expression matching the volatile parts of the comment (e.g.: a timestamp), a change of these parts only does not update the comment",
+			},
+			adoptExistingAttr: adoptExistingSchema(),
+		},
+	}
+	resource.StateUpgraders = []schema.StateUpgrader{
+		resourceIDStateUpgrader(resource, 0, func(rawState map[string]interface{}) string {
+			if rawStateString(rawState, commentDatabaseAttr) == "" || rawStateString(rawState, commentObjectNameAttr) == "" {
+				return ""
+			}
+			return commentID(
+				rawStateString(rawState, commentDatabaseAttr),
+				rawStateString(rawState, commentObjectTypeAttr),
+				rawStateString(rawState, commentSchemaAttr),
+				rawStateString(rawState, commentObjectNameAttr),
+			)
+		}),
+	}
+
+	return resource
+}
+
+// commentsMatchIgnoringPattern returns whether the comments are the same once the parts matching the pattern are removed.
+func commentsMatchIgnoringPattern(old, new, pattern string) bool {
+	if pattern == "" {
+		return old == new
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	return re.ReplaceAllString(old, "") == re.ReplaceAllString(new, "")
+}
+
+// getCommentObject returns the object type and the qualified name of the commented object.
+func getCommentObject(d *schema.ResourceData) (commentObjectType, string) {
+	objectType := commentObjectTypes[d.Get(commentObjectTypeAttr).(string)]
+	name := pq.QuoteIdentifier(d.Get(commentObjectNameAttr).(string))
+	if objectType.schemaColumn != "" {
+		name = fmt.Sprintf("%s.%s", pq.QuoteIdentifier(getCommentSchema(d)), name)
+	}
+	return objectType, name
+}
+
+func getCommentSchema(d *schema.ResourceData) string {
+	if schemaName := d.Get(commentSchemaAttr).(string); schemaName != "" {
+		return schemaName
+	}
+	return "public"
+}
+
+func resourcePostgreSQLCommentCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := checkExistingComment(db, d); err != nil {
+		return err
+	}
+
+	if err := setComment(db, d, commentLiteral(d.Get(commentCommentAttr).(string))); err != nil {
+		return err
+	}
+
+	d.SetId(generateCommentID(d, db.client.databaseName))
+
+	return resourcePostgreSQLCommentReadImpl(db, d)
+}
+
+// commentLiteral returns the SQL literal of a comment. PostgreSQL does not store empty comments,
+// an empty string literal removes the comment like NULL: an empty comment is sent as NULL so the
+// statement says what it does.
+func commentLiteral(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return pq.QuoteLiteral(comment)
+}
+
+// setComment sets the comment of the object, comment being a SQL literal or NULL.
+func setComment(db *DBConnection, d *schema.ResourceData, comment string) error {
+	objectType, name := getCommentObject(d)
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf("COMMENT ON %s %s IS %s", objectType.keyword, name, comment)); err != nil {
+		return fmt.Errorf("could not comment %s %s: %w", d.Get(commentObjectTypeAttr), name, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error commenting %s %s: %w", d.Get(commentObjectTypeAttr), name, err)
+	}
+
+	return nil
+}
+
+// checkExistingComment returns an objectExistsError if the object already has another comment than
+// the configured one (e.g.: set outside of Terraform) and adopt_existing is not set.
+func checkExistingComment(db *DBConnection, d *schema.ResourceData) error {
+	objectTypeName := d.Get(commentObjectTypeAttr).(string)
+	objectType := commentObjectTypes[objectTypeName]
+	name := d.Get(commentObjectNameAttr).(string)
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var comment sql.NullString
+	err = txn.QueryRow(commentQuery(objectType), commentQueryArgs(d, objectType)...).Scan(&comment)
+	switch {
+	case err == sql.ErrNoRows:
+		// The missing object is reported when commenting it
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading comment of %s %s: %w", objectTypeName, name, err)
+	}
+
+	if !comment.Valid || comment.String == d.Get(commentCommentAttr).(string) {
+		return nil
+	}
+	return existingObject(
+		adoptExisting(d, false), "postgresql_comment", generateCommentID(d, db.client.databaseName),
+		fmt.Errorf("%s %s already has a comment", objectTypeName, name),
+	)
+}
+
+func resourcePostgreSQLCommentRead(db *DBConnection, d *schema.ResourceData) error {
+	return asCatalogReadError(resourcePostgreSQLCommentReadImpl(db, d))
+}
+
+func resourcePostgreSQLCommentReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	if d.Get(commentObjectTypeAttr).(string) == "" {
+		if err := parseCommentID(d); err != nil {
+			return err
+		}
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	objectTypeName := d.Get(commentObjectTypeAttr).(string)
+	objectType := commentObjectTypes[objectTypeName]
+	name := d.Get(commentObjectNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		if isObjectNotFoundError(err) {
+			log.Printf("[WARN] PostgreSQL database %s not found, the comment of %s %s is removed from state", database, objectTypeName, name)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// The object has no description row when it has no comment
+	var comment sql.NullString
+	err = txn.QueryRow(commentQuery(objectType), commentQueryArgs(d, objectType)...).Scan(&comment)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL %s %s not found in database %s, its comment is removed from state", objectTypeName, name, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading comment of %s %s: %w", objectTypeName, name, err)
+	}
+
+	d.Set(commentDatabaseAttr, database)
+	if objectType.schemaColumn != "" {
+		d.Set(commentSchemaAttr, getCommentSchema(d))
+	}
+	if !comment.Valid {
+		log.Printf("[DEBUG] PostgreSQL %s %s has no comment", objectTypeName, name)
+	}
+	d.Set(commentCommentAttr, comment.String)
+	d.SetId(generateCommentID(d, db.client.databaseName))
+
+	return nil
+}
+
+// commentQuery returns the query reading the comment of an object, NULL if it has none. Its
+// parameters are the catalog of the object, its name and its schema if it belongs to one.
+func commentQuery(objectType commentObjectType) string {
+	descriptionJoin := "LEFT JOIN pg_catalog.pg_description d ON d.objoid = o.oid AND d.classoid = $1::regclass AND d.objsubid = 0"
+	if objectType.shared {
+		descriptionJoin = "LEFT JOIN pg_catalog.pg_shdescription d ON d.objoid = o.oid AND d.classoid = $1::regclass"
+	}
+	query := fmt.Sprintf(
+		"SELECT d.description FROM pg_catalog.%s o %s WHERE o.%s = $2",
+		objectType.catalog, descriptionJoin, objectType.nameColumn,
+	)
+	if objectType.schemaColumn != "" {
+		query += fmt.Sprintf(" AND o.%s = (SELECT oid FROM pg_catalog.pg_namespace WHERE nspname = $3)", objectType.schemaColumn)
+	}
+	return query
+}
+
+// commentQueryArgs returns the parameters of the commentQuery of the object of d.
+func commentQueryArgs(d *schema.ResourceData, objectType commentObjectType) []interface{} {
+	args := []interface{}{"pg_catalog." + objectType.catalog, d.Get(commentObjectNameAttr).(string)}
+	if objectType.schemaColumn != "" {
+		args = append(args, getCommentSchema(d))
+	}
+	return args
+}
+
+func resourcePostgreSQLCommentUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setComment(db, d, commentLiteral(d.Get(commentCommentAttr).(string))); err != nil {
+		return err
+	}
+
+	return resourcePostgreSQLCommentReadImpl(db, d)
+}
+
+func resourcePostgreSQLCommentDelete(db *DBConnection, d *schema.ResourceData) error {
+	// The comment of an object is dropped with it
+	if err := setComment(db, d, "NULL"); err != nil {
+		if !isObjectNotFoundError(err) {
+			return err
+		}
+		log.Printf("[WARN] PostgreSQL %s %s not found, its comment has been removed with it: %v", d.Get(commentObjectTypeAttr), d.Get(commentObjectNameAttr), err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// generateCommentID returns database.object_type.name, or database.object_type.schema.name
+// for the objects belonging to a schema.
+func generateCommentID(d *schema.ResourceData, defaultDatabase string) string {
+	return commentID(
+		getDatabase(d, defaultDatabase),
+		d.Get(commentObjectTypeAttr).(string),
+		d.Get(commentSchemaAttr).(string),
+		d.Get(commentObjectNameAttr).(string),
+	)
+}
+
+// commentID returns the ID of the comment of an object, the schema being only part of
+// the ID for the objects belonging to a schema.
+func commentID(database, objectType, schemaName, name string) string {
+	parts := []string{database, objectType}
+	if commentObjectTypes[objectType].schemaColumn != "" {
+		if schemaName == "" {
+			schemaName = "public"
+		}
+		parts = append(parts, schemaName)
+	}
+	return joinResourceID(append(parts, name)...)
+}
+
+// parseCommentID sets the attributes of the commented object from the ID when importing.
+func parseCommentID(d *schema.ResourceData) error {
+	parsed := splitResourceID(d.Id())
+	if len(parsed) < 3 {
+		return fmt.Errorf("comment ID %s has not the expected format 'database.object_type.[schema.]name': %v", d.Id(), parsed)
+	}
+
+	objectType, ok := commentObjectTypes[parsed[1]]
+	if !ok {
+		return fmt.Errorf("comment ID %s has an unknown object type: %s", d.Id(), parsed[1])
+	}
+
+	expected := 3
+	if objectType.schemaColumn != "" {
+		expected = 4
+	}
+	if len(parsed) != expected {
+		return fmt.Errorf("comment ID %s has not the expected format 'database.object_type.[schema.]name': %v", d.Id(), parsed)
+	}
+
+	d.Set(commentDatabaseAttr, parsed[0])
+	d.Set(commentObjectTypeAttr, parsed[1])
+	if expected == 4 {
+		d.Set(commentSchemaAttr, parsed[2])
+	}
+	d.Set(commentObjectNameAttr, parsed[expected-1])
+
+	return nil
+}
@@ -0,0 +1,589 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
+)
+
+func TestParseCommentID(t *testing.T) {
+	var cases = []struct {
+		id       string
+		expected map[string]string
+		valid    bool
+	}{
+		{
+			id:       "mydb.extension.pg_trgm",
+			expected: map[string]string{"database": "mydb", "object_type": "extension", "schema": "", "object_name": "pg_trgm"},
+			valid:    true,
+		},
+		{
+			id:       "mydb.type.my_schema.my_type",
+			expected: map[string]string{"database": "mydb", "object_type": "type", "schema": "my_schema", "object_name": "my_type"},
+			valid:    true,
+		},
+		{
+			id:       `mydb.type.My\.Schema.Status/Type`,
+			expected: map[string]string{"database": "mydb", "object_type": "type", "schema": "My.Schema", "object_name": "Status/Type"},
+			valid:    true,
+		},
+		{id: "mydb.type.my_type"},
+		{id: "mydb.extension.my_schema.pg_trgm"},
+		{id: "mydb.table.my_table"},
+	}
+
+	for _, c := range cases {
+		d := resourcePostgreSQLComment().TestResourceData()
+		d.SetId(c.id)
+
+		err := parseCommentID(d)
+		if (err == nil) != c.valid {
+			t.Fatalf("Expected parsing of %s to be %t, got error: %v", c.id, c.valid, err)
+		}
+		for attr, value := range c.expected {
+			if out := d.Get(attr).(string); out != value {
+				t.Fatalf("Error matching %s of %s: %#v vs %#v", attr, c.id, out, value)
+			}
+		}
+		if c.valid {
+			if id := generateCommentID(d, ""); id != c.id {
+				t.Fatalf("Error matching generated ID and parsed ID: %#v vs %#v", id, c.id)
+			}
+		}
+	}
+}
+
+func TestCommentStateUpgradeV0(t *testing.T) {
+	var cases = []struct {
+		rawState map[string]interface{}
+		expected string
+	}{
+		{
+			rawState: map[string]interface{}{
+				"id": "mydb.type.My.Schema.Status/Type", "database": "mydb", "object_type": "type",
+				"schema": "My.Schema", "object_name": "Status/Type", "comment": "Status of an order",
+			},
+			expected: `mydb.type.My\.Schema.Status/Type`,
+		},
+		{
+			rawState: map[string]interface{}{
+				"id": "app.v2.database.app.v2", "database": "app.v2", "object_type": "database",
+				"schema": "", "object_name": "app.v2", "comment": "Application",
+			},
+			expected: `app\.v2.database.app\.v2`,
+		},
+		{
+			rawState: map[string]interface{}{
+				"id": "mydb.extension.pg_trgm", "database": "mydb", "object_type": "extension",
+				"schema": "", "object_name": "pg_trgm", "comment": "Trigram matching",
+			},
+			expected: "mydb.extension.pg_trgm",
+		},
+	}
+
+	upgrader := resourcePostgreSQLComment().StateUpgraders[0]
+	for _, c := range cases {
+		upgraded, err := upgrader.Upgrade(context.Background(), c.rawState, nil)
+		if err != nil {
+			t.Fatalf("unexpected error upgrading %v: %v", c.rawState["id"], err)
+		}
+		if upgraded["id"] != c.expected {
+			t.Fatalf("expected ID %s to be upgraded to %s, got %v", c.rawState["id"], c.expected, upgraded["id"])
+		}
+
+		// The upgraded ID can be parsed back, e.g. when importing the comment in another state
+		d := resourcePostgreSQLComment().TestResourceData()
+		d.SetId(c.expected)
+		if err := parseCommentID(d); err != nil {
+			t.Fatalf("could not parse upgraded ID %s: %v", c.expected, err)
+		}
+		for _, attr := range []string{"database", "object_type", "schema", "object_name"} {
+			if out := d.Get(attr).(string); out != c.rawState[attr] {
+				t.Fatalf("Error matching %s of %s: %#v vs %#v", attr, c.expected, out, c.rawState[attr])
+			}
+		}
+	}
+}
+
+func TestCommentsMatchIgnoringPattern(t *testing.T) {
+	var cases = []struct {
+		old, new, pattern string
+		expected          bool
+	}{
+		{old: "managed by terraform", new: "managed by terraform", expected: true},
+		{old: "managed by terraform", new: "managed by ansible", expected: false},
+		{old: "deployed at 2024-01-01T00:00:00Z", new: "deployed at 2024-06-30T12:34:56Z", pattern: `\d{4}-\d{2}-\d{2}T\S+`, expected: true},
+		{old: "deployed at 2024-01-01T00:00:00Z", new: "released at 2024-06-30T12:34:56Z", pattern: `\d{4}-\d{2}-\d{2}T\S+`, expected: false},
+		{old: "sha abc123", new: "sha def456", pattern: "[", expected: false},
+	}
+
+	for _, c := range cases {
+		if out := commentsMatchIgnoringPattern(c.old, c.new, c.pattern); out != c.expected {
+			t.Errorf("expected %q and %q with pattern %q to match: %t, got %t", c.old, c.new, c.pattern, c.expected, out)
+		}
+	}
+}
+
+func TestCommentReadOnConnectionError(t *testing.T) {
+	read := func(client *Client) (*schema.ResourceData, error) {
+		d := schema.TestResourceDataRaw(t, resourcePostgreSQLComment().Schema, map[string]interface{}{
+			commentDatabaseAttr:   "mydb",
+			commentObjectTypeAttr: "extension",
+			commentObjectNameAttr: "pg_trgm",
+			commentCommentAttr:    "Trigram matching",
+		})
+		d.SetId("mydb.extension.pg_trgm")
+
+		db, err := client.Connect()
+		if err != nil {
+			t.Fatalf("could not connect: %v", err)
+		}
+		return d, resourcePostgreSQLCommentReadImpl(db, d)
+	}
+
+	for _, connErr := range connectionErrors {
+		d, err := read(newFailingClient(t, "mydb", connErr))
+		if err == nil {
+			t.Errorf("expected an error to be returned for %v", connErr)
+		}
+		if d.Id() == "" {
+			t.Errorf("expected the comment to stay in state on %v", connErr)
+		}
+	}
+
+	d, err := read(newFailingClient(t, "mydb", &pq.Error{Code: "3D000", Message: `database "mydb" does not exist`}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the comment to be removed from state when its database does not exist")
+	}
+}
+
+func TestAccPostgresqlComment_Extension(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_extension" "test" {
+  database = "%[1]s"
+  name     = "pg_trgm"
+}
+
+# pg_trgm has a comment when it is created
+resource "postgresql_comment" "test" {
+  database       = "%[1]s"
+  object_type    = "extension"
+  object_name    = postgresql_extension.test.name
+  comment        = "%[2]s"
+  adopt_existing = true
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			// COMMENT ON needs no feature, the test comments an extension it creates
+			testCheckFeatures(t, featureExtension)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCommentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, "trigram matching"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_comment.test", "id", fmt.Sprintf("%s.extension.pg_trgm", dbName)),
+					testAccCheckPostgresqlComment(dbName, "SELECT obj_description(oid, 'pg_extension') FROM pg_extension WHERE extname = 'pg_trgm'", "trigram matching"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, "text similarity"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_comment.test", "comment", "text similarity"),
+					testAccCheckPostgresqlComment(dbName, "SELECT obj_description(oid, 'pg_extension') FROM pg_extension WHERE extname = 'pg_trgm'", "text similarity"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_comment.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.extension.pg_trgm", dbName),
+				ImportStateVerify: true,
+				// Not read from the database, it only tells how to create the resource
+				ImportStateVerifyIgnore: []string{adoptExistingAttr},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlComment_AdoptExisting(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	query := "SELECT COALESCE(obj_description('test_schema'::regnamespace, 'pg_namespace'), '')"
+
+	config := `
+resource "postgresql_comment" "test" {
+  database       = "%s"
+  object_type    = "schema"
+  object_name    = "test_schema"
+  comment        = "Managed by Terraform"
+  adopt_existing = %t
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testConfig := getTestConfig(t)
+			// The comment set outside of Terraform
+			dbExecute(t, testConfig.connStr(dbName), "COMMENT ON SCHEMA test_schema IS 'Set manually'")
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCommentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, false),
+				ExpectError: regexp.MustCompile(regexp.QuoteMeta(
+					fmt.Sprintf("terraform import postgresql_comment.<name> %s.schema.test_schema", dbName),
+				)),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, true),
+				Check:  testAccCheckPostgresqlComment(dbName, query, "Managed by Terraform"),
+			},
+		},
+	})
+}
+
+func TestCommentDeleteOnMissingObject(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLComment().Schema, map[string]interface{}{
+		commentDatabaseAttr:   "mydb",
+		commentObjectTypeAttr: "extension",
+		commentObjectNameAttr: "pg_trgm",
+		commentCommentAttr:    "Trigram matching",
+	})
+	d.SetId("mydb.extension.pg_trgm")
+
+	// The comment has been dropped with its database
+	db, err := newFailingClient(t, "mydb", &pq.Error{Code: "3D000", Message: `database "mydb" does not exist`}).Connect()
+	if err != nil {
+		t.Fatalf("could not connect: %v", err)
+	}
+	if err := resourcePostgreSQLCommentDelete(db, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the comment to be removed from state")
+	}
+
+	d.SetId("mydb.extension.pg_trgm")
+	for _, connErr := range connectionErrors {
+		db, err := newFailingClient(t, "mydb", connErr).Connect()
+		if err != nil {
+			t.Fatalf("could not connect: %v", err)
+		}
+		if err := resourcePostgreSQLCommentDelete(db, d); err == nil {
+			t.Errorf("expected an error to be returned for %v", connErr)
+		}
+	}
+}
+
+func TestAccPostgresqlComment_MissingDatabase(t *testing.T) {
+	skipIfNotAcc(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "postgresql_comment" "test" {
+  database    = "tf_tests_missing_database"
+  object_type = "schema"
+  object_name = "public"
+  comment     = "Public schema"
+}
+`,
+				ExpectError: regexp.MustCompile("database tf_tests_missing_database does not exist; check the database attribute"),
+			},
+		},
+	})
+}
+
+func TestCommentLiteral(t *testing.T) {
+	var cases = []struct {
+		comment, expected string
+	}{
+		// PostgreSQL removes the comment set to an empty string, it is sent as NULL
+		{comment: "", expected: "NULL"},
+		{comment: " ", expected: "' '"},
+		{comment: "It's a test", expected: "'It''s a test'"},
+	}
+
+	for _, c := range cases {
+		if out := commentLiteral(c.comment); out != c.expected {
+			t.Errorf("expected the literal of %q to be %s, got %s", c.comment, c.expected, out)
+		}
+	}
+}
+
+func TestAccPostgresqlComment_Type(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	query := "SELECT COALESCE(obj_description('test_schema.test_enum'::regtype, 'pg_type'), '')"
+
+	config := fmt.Sprintf(`
+resource "postgresql_enum" "test" {
+  database = "%[1]s"
+  schema   = "test_schema"
+  name     = "test_enum"
+  values   = ["a", "b"]
+}
+
+resource "postgresql_comment" "test" {
+  database    = "%[1]s"
+  object_type = "type"
+  schema      = postgresql_enum.test.schema
+  object_name = postgresql_enum.test.name
+  comment     = "It's a test enum"
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCommentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_comment.test", "id", fmt.Sprintf("%s.type.test_schema.test_enum", dbName)),
+					testAccCheckPostgresqlComment(dbName, query, "It's a test enum"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_comment.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.type.test_schema.test_enum", dbName),
+				ImportStateVerify: true,
+				// Not read from the database, it only tells how to create the resource
+				ImportStateVerifyIgnore: []string{adoptExistingAttr},
+			},
+			{
+				// Removing the comment resource removes the comment but keeps the type
+				Config: fmt.Sprintf(`
+resource "postgresql_enum" "test" {
+  database = "%s"
+  schema   = "test_schema"
+  name     = "test_enum"
+  values   = ["a", "b"]
+}
+`, dbName),
+				Check: testAccCheckPostgresqlComment(dbName, query, ""),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlComment_IgnorePattern(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	query := "SELECT obj_description(oid, 'pg_namespace') FROM pg_namespace WHERE nspname = 'test_schema'"
+
+	config := `
+resource "postgresql_comment" "test" {
+  database               = "%s"
+  object_type            = "schema"
+  object_name            = "test_schema"
+  comment                = "%s"
+  comment_ignore_pattern = "\\d{4}-\\d{2}-\\d{2}T\\S+"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCommentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, "Deployed at 2024-01-01T00:00:00Z"),
+				Check:  testAccCheckPostgresqlComment(dbName, query, "Deployed at 2024-01-01T00:00:00Z"),
+			},
+			{
+				// Only the timestamp changes, the comment is not updated
+				Config:   fmt.Sprintf(config, dbName, "Deployed at 2024-06-30T12:34:56Z"),
+				PlanOnly: true,
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, "Released at 2024-06-30T12:34:56Z"),
+				Check:  testAccCheckPostgresqlComment(dbName, query, "Released at 2024-06-30T12:34:56Z"),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlComment_Empty(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	// An object without comment has no description row, unlike an object with a comment
+	query := "SELECT COALESCE(obj_description(oid, 'pg_namespace'), '<no comment>') FROM pg_namespace WHERE nspname = 'test_schema'"
+
+	config := `
+resource "postgresql_comment" "test" {
+  database    = "%s"
+  object_type = "schema"
+  object_name = "test_schema"
+  comment     = "%s"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCommentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, "Application schema"),
+				Check:  testAccCheckPostgresqlComment(dbName, query, "Application schema"),
+			},
+			{
+				// An empty comment removes the comment
+				Config: fmt.Sprintf(config, dbName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_comment.test", "comment", ""),
+					testAccCheckPostgresqlComment(dbName, query, "<no comment>"),
+				),
+			},
+			{
+				// The missing comment is read as the empty comment
+				Config:   fmt.Sprintf(config, dbName, ""),
+				PlanOnly: true,
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, " "),
+				Check:  testAccCheckPostgresqlComment(dbName, query, " "),
+			},
+		},
+	})
+}
+
+// TestAccPostgresqlComment_UnreadableCatalog runs the comment resource as a minimally-privileged role
+// which cannot read pg_description, as on hardened clusters.
+func TestAccPostgresqlComment_UnreadableCatalog(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), fmt.Sprintf("CREATE SCHEMA limited AUTHORIZATION %s", roleName))
+
+	query := "SELECT obj_description(oid, 'pg_namespace') FROM pg_namespace WHERE nspname = 'limited'"
+	config := `
+provider "postgresql" {
+  username             = "%s"
+  password             = "%s"
+  superuser            = false
+  strict_catalog_reads = %t
+}
+
+resource "postgresql_comment" "test" {
+  database    = "%s"
+  object_type = "schema"
+  object_name = "limited"
+  comment     = "Managed by a limited role"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCommentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, roleName, testRolePassword, false, dbName),
+				Check:  testAccCheckPostgresqlComment(dbName, query, "Managed by a limited role"),
+			},
+			{
+				// pg_description is only hardened in the test database
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "REVOKE SELECT ON pg_catalog.pg_description FROM PUBLIC")
+				},
+				// The refresh keeps the state with a warning
+				Config:   fmt.Sprintf(config, roleName, testRolePassword, false, dbName),
+				PlanOnly: true,
+			},
+			{
+				Config:      fmt.Sprintf(config, roleName, testRolePassword, true, dbName),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("cannot read the catalog pg_description"),
+			},
+			{
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "GRANT SELECT ON pg_catalog.pg_description TO PUBLIC")
+				},
+				Config:   fmt.Sprintf(config, roleName, testRolePassword, false, dbName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// testAccCheckPostgresqlComment checks the comment returned by the query in the database.
+// testAccCheckPostgresqlCommentDestroy checks the commented objects have no comment left.
+var testAccCheckPostgresqlCommentDestroy = testAccCheckResourcesDestroyed("postgresql_comment", func(rs *terraform.ResourceState) (string, string, []interface{}) {
+	objectType := commentObjectTypes[rs.Primary.Attributes[commentObjectTypeAttr]]
+	args := []interface{}{"pg_catalog." + objectType.catalog, rs.Primary.Attributes[commentObjectNameAttr]}
+	if objectType.schemaColumn != "" {
+		args = append(args, rs.Primary.Attributes[commentSchemaAttr])
+	}
+	query := fmt.Sprintf("SELECT description IS NOT NULL FROM (%s) c", commentQuery(objectType))
+	return rs.Primary.Attributes[commentDatabaseAttr], query, args
+})
+
+func testAccCheckPostgresqlComment(dbName, query, expected string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var comment string
+		if err := txn.QueryRow(query).Scan(&comment); err != nil {
+			return fmt.Errorf("could not read comment: %w", err)
+		}
+		if comment != expected {
+			return fmt.Errorf("expected comment to be %q, got %q", expected, comment)
+		}
+		return nil
+	}
+}
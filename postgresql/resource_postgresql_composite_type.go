@@ -0,0 +1,439 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	compositeTypeNameAttr          = "name"
+	compositeTypeSchemaAttr        = "schema"
+	compositeTypeDatabaseAttr      = "database"
+	compositeTypeAttributeAttr     = "attribute"
+	compositeTypeAttrNameAttr      = "name"
+	compositeTypeAttrTypeAttr      = "type"
+	compositeTypeAttrCollationAttr = "collation"
+	compositeTypeDropCascadeAttr   = "drop_cascade"
+)
+
+func resourcePostgreSQLCompositeType() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLCompositeTypeCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLCompositeTypeRead),
+		Update: PGResourceFunc(resourcePostgreSQLCompositeTypeUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLCompositeTypeDelete),
+		Exists: PGResourceExistsFunc(resourcePostgreSQLCompositeTypeExists),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourcePostgreSQLCompositeTypeCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			compositeTypeNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the composite type",
+				ValidateFunc: validateIdentifier,
+			},
+			compositeTypeSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the composite type is created",
+			},
+			compositeTypeDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the composite type is created",
+			},
+			compositeTypeAttributeAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The ordered list of attributes of the composite type",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						compositeTypeAttrNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The name of the attribute",
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						compositeTypeAttrTypeAttr: {
+							Type:             schema.TypeString,
+							Required:         true,
+							Description:      "The data type of the attribute",
+							ValidateFunc:     validation.StringIsNotEmpty,
+							DiffSuppressFunc: typeDiffSuppressFunc,
+						},
+						compositeTypeAttrCollationAttr: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The collation of the attribute (if its data type is collatable)",
+						},
+					},
+				},
+			},
+			compositeTypeDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the composite type",
+			},
+		},
+	}
+}
+
+type compositeTypeAttribute struct {
+	name      string
+	typeName  string
+	collation string
+}
+
+func (a compositeTypeAttribute) definition() string {
+	definition := fmt.Sprintf("%s %s", pq.QuoteIdentifier(a.name), a.typeName)
+	if a.collation != "" {
+		definition += " COLLATE " + pq.QuoteIdentifier(a.collation)
+	}
+	return definition
+}
+
+func getCompositeTypeAttributes(raw []interface{}) []compositeTypeAttribute {
+	attributes := make([]compositeTypeAttribute, 0, len(raw))
+	for _, r := range raw {
+		attribute := r.(map[string]interface{})
+		attributes = append(attributes, compositeTypeAttribute{
+			name:      attribute[compositeTypeAttrNameAttr].(string),
+			typeName:  attribute[compositeTypeAttrTypeAttr].(string),
+			collation: attribute[compositeTypeAttrCollationAttr].(string),
+		})
+	}
+	return attributes
+}
+
+// resourcePostgreSQLCompositeTypeCustomizeDiff forces the recreation of the type if the new attributes
+// cannot be reached with ALTER TYPE, e.g.: if the kept attributes have been reordered.
+func resourcePostgreSQLCompositeTypeCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	names := []interface{}{}
+	for _, attribute := range getCompositeTypeAttributes(diff.Get(compositeTypeAttributeAttr).([]interface{})) {
+		names = append(names, attribute.name)
+	}
+	if name, ok := isUniqueArr(names); !ok {
+		return fmt.Errorf("composite type attribute %v is duplicated", name)
+	}
+
+	if diff.Id() == "" || !diff.HasChange(compositeTypeAttributeAttr) {
+		return nil
+	}
+
+	oraw, nraw := diff.GetChange(compositeTypeAttributeAttr)
+	if _, err := compositeTypeAlterations(
+		getCompositeTypeAttributes(oraw.([]interface{})), getCompositeTypeAttributes(nraw.([]interface{})),
+	); err != nil {
+		log.Printf("[DEBUG] composite type will be recreated: %v", err)
+		return diff.ForceNew(compositeTypeAttributeAttr)
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLCompositeTypeCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(compositeTypeSchemaAttr).(string)
+	typeName := d.Get(compositeTypeNameAttr).(string)
+
+	definitions := []string{}
+	for _, attribute := range getCompositeTypeAttributes(d.Get(compositeTypeAttributeAttr).([]interface{})) {
+		definitions = append(definitions, attribute.definition())
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	sql := fmt.Sprintf("CREATE TYPE %s.%s AS (%s)",
+		pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(typeName), strings.Join(definitions, ", "),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not create composite type %s: %w", typeName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating composite type: %w", err)
+	}
+
+	d.SetId(generateCompositeTypeID(database, schemaName, typeName))
+
+	return resourcePostgreSQLCompositeTypeReadImpl(db, d)
+}
+
+func resourcePostgreSQLCompositeTypeExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	database, schemaName, typeName, err := getDBCompositeTypeName(d, db.client)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the database exists
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	return compositeTypeExists(txn, schemaName, typeName)
+}
+
+func resourcePostgreSQLCompositeTypeRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLCompositeTypeReadImpl(db, d)
+}
+
+func resourcePostgreSQLCompositeTypeReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, typeName, err := getDBCompositeTypeName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	exists, err := compositeTypeExists(txn, schemaName, typeName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL composite type (%s.%s) not found in database %s", schemaName, typeName, database)
+		d.SetId("")
+		return nil
+	}
+
+	attributes, err := readCompositeTypeAttributes(txn, schemaName, typeName)
+	if err != nil {
+		return err
+	}
+
+	d.Set(compositeTypeNameAttr, typeName)
+	d.Set(compositeTypeSchemaAttr, schemaName)
+	d.Set(compositeTypeDatabaseAttr, database)
+	d.Set(compositeTypeAttributeAttr, attributes)
+	d.SetId(generateCompositeTypeID(database, schemaName, typeName))
+
+	return nil
+}
+
+func resourcePostgreSQLCompositeTypeUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(compositeTypeAttributeAttr) {
+		return resourcePostgreSQLCompositeTypeReadImpl(db, d)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(compositeTypeSchemaAttr).(string)
+	typeName := d.Get(compositeTypeNameAttr).(string)
+
+	oraw, nraw := d.GetChange(compositeTypeAttributeAttr)
+	alterations, err := compositeTypeAlterations(
+		getCompositeTypeAttributes(oraw.([]interface{})), getCompositeTypeAttributes(nraw.([]interface{})),
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(alterations) > 0 {
+		txn, err := startTransaction(db.client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		sql := fmt.Sprintf("ALTER TYPE %s.%s %s",
+			pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(typeName), strings.Join(alterations, ", "),
+		)
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not alter composite type %s: %w", typeName, err)
+		}
+
+		if err = txn.Commit(); err != nil {
+			return fmt.Errorf("Error updating composite type: %w", err)
+		}
+	}
+
+	return resourcePostgreSQLCompositeTypeReadImpl(db, d)
+}
+
+func resourcePostgreSQLCompositeTypeDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(compositeTypeSchemaAttr).(string)
+	typeName := d.Get(compositeTypeNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	dropMode := "RESTRICT"
+	if d.Get(compositeTypeDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	}
+
+	sql := fmt.Sprintf("DROP TYPE %s.%s %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(typeName), dropMode)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop composite type: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting composite type: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// compositeTypeAlterations returns the ALTER TYPE actions needed to go from the old attributes to the new ones.
+// As ADD ATTRIBUTE always appends the attribute, it returns an error if the kept attributes have been reordered
+// or if an attribute has been added before a kept one.
+func compositeTypeAlterations(oldAttributes, newAttributes []compositeTypeAttribute) ([]string, error) {
+	oldByName := make(map[string]compositeTypeAttribute, len(oldAttributes))
+	for _, attribute := range oldAttributes {
+		oldByName[attribute.name] = attribute
+	}
+	newByName := make(map[string]compositeTypeAttribute, len(newAttributes))
+	for _, attribute := range newAttributes {
+		newByName[attribute.name] = attribute
+	}
+
+	alterations := []string{}
+	kept := []string{}
+	for _, attribute := range oldAttributes {
+		if _, ok := newByName[attribute.name]; !ok {
+			alterations = append(alterations, "DROP ATTRIBUTE "+pq.QuoteIdentifier(attribute.name))
+			continue
+		}
+		kept = append(kept, attribute.name)
+	}
+
+	i := 0
+	for _, attribute := range newAttributes {
+		old, ok := oldByName[attribute.name]
+		if !ok {
+			if i < len(kept) {
+				return nil, fmt.Errorf("attribute %s has been added before the existing attribute %s", attribute.name, kept[i])
+			}
+			alterations = append(alterations, "ADD ATTRIBUTE "+attribute.definition())
+			continue
+		}
+
+		if kept[i] != attribute.name {
+			return nil, fmt.Errorf("attribute %s has been reordered", attribute.name)
+		}
+		i++
+
+		if normalizeTypeName(old.typeName) != normalizeTypeName(attribute.typeName) || old.collation != attribute.collation {
+			alteration := fmt.Sprintf("ALTER ATTRIBUTE %s TYPE %s", pq.QuoteIdentifier(attribute.name), attribute.typeName)
+			if attribute.collation != "" {
+				alteration += " COLLATE " + pq.QuoteIdentifier(attribute.collation)
+			}
+			alterations = append(alterations, alteration)
+		}
+	}
+
+	return alterations, nil
+}
+
+func readCompositeTypeAttributes(txn *sql.Tx, schemaName, typeName string) ([]map[string]interface{}, error) {
+	// The collation is only returned if it is not the default one of the attribute type
+	query := `SELECT a.attname, pg_catalog.format_type(a.atttypid, a.atttypmod), COALESCE(co.collname, '') ` +
+		`FROM pg_catalog.pg_type t ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace ` +
+		`JOIN pg_catalog.pg_attribute a ON a.attrelid = t.typrelid ` +
+		`JOIN pg_catalog.pg_type at ON at.oid = a.atttypid ` +
+		`LEFT JOIN pg_catalog.pg_collation co ON co.oid = a.attcollation AND a.attcollation <> at.typcollation ` +
+		`WHERE n.nspname = $1 AND t.typname = $2 AND a.attnum > 0 AND NOT a.attisdropped ` +
+		`ORDER BY a.attnum`
+	rows, err := txn.Query(query, schemaName, typeName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read attributes of composite type %s: %w", typeName, err)
+	}
+	defer rows.Close()
+
+	attributes := []map[string]interface{}{}
+	for rows.Next() {
+		var name, attributeType, collation string
+		if err := rows.Scan(&name, &attributeType, &collation); err != nil {
+			return nil, fmt.Errorf("could not scan attribute of composite type %s: %w", typeName, err)
+		}
+		attributes = append(attributes, map[string]interface{}{
+			compositeTypeAttrNameAttr:      name,
+			compositeTypeAttrTypeAttr:      attributeType,
+			compositeTypeAttrCollationAttr: collation,
+		})
+	}
+
+	return attributes, rows.Err()
+}
+
+func compositeTypeExists(txn *sql.Tx, schemaName, typeName string) (bool, error) {
+	var _rez bool
+	// Tables also have a composite row type, so we check that the type relation is a standalone composite type
+	err := txn.QueryRow(
+		`SELECT TRUE FROM pg_catalog.pg_type t `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace `+
+			`JOIN pg_catalog.pg_class c ON c.oid = t.typrelid `+
+			`WHERE n.nspname = $1 AND t.typname = $2 AND t.typtype = 'c' AND c.relkind = 'c'`,
+		schemaName, typeName,
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if composite type exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func generateCompositeTypeID(database, schemaName, typeName string) string {
+	return joinResourceID(database, schemaName, typeName)
+}
+
+// getDBCompositeTypeName returns database, schema and composite type name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBCompositeTypeName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(compositeTypeSchemaAttr).(string)
+	typeName := d.Get(compositeTypeNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and composite type names.
+	if typeName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("composite type ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		typeName = parsed[2]
+	}
+	return database, schemaName, typeName, nil
+}
@@ -0,0 +1,179 @@
+package postgresql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCompositeTypeAlterations(t *testing.T) {
+	a := compositeTypeAttribute{name: "a", typeName: "integer"}
+	b := compositeTypeAttribute{name: "b", typeName: "text"}
+	c := compositeTypeAttribute{name: "c", typeName: "varchar(10)", collation: "C"}
+
+	var tests = []struct {
+		oldAttributes []compositeTypeAttribute
+		newAttributes []compositeTypeAttribute
+		want          []string
+		wantErr       bool
+	}{
+		{[]compositeTypeAttribute{a, b}, []compositeTypeAttribute{a, b}, []string{}, false},
+		{[]compositeTypeAttribute{a, b}, []compositeTypeAttribute{{name: "a", typeName: "INT4"}, b}, []string{}, false},
+		{[]compositeTypeAttribute{a, b}, []compositeTypeAttribute{a, b, c}, []string{`ADD ATTRIBUTE "c" varchar(10) COLLATE "C"`}, false},
+		{[]compositeTypeAttribute{a, b}, []compositeTypeAttribute{b}, []string{`DROP ATTRIBUTE "a"`}, false},
+		{[]compositeTypeAttribute{a, b}, []compositeTypeAttribute{a, {name: "b", typeName: "varchar(255)"}}, []string{`ALTER ATTRIBUTE "b" TYPE varchar(255)`}, false},
+		{[]compositeTypeAttribute{a, b}, []compositeTypeAttribute{a, c}, []string{`DROP ATTRIBUTE "b"`, `ADD ATTRIBUTE "c" varchar(10) COLLATE "C"`}, false},
+		{[]compositeTypeAttribute{a, b}, []compositeTypeAttribute{c, a, b}, nil, true},
+		{[]compositeTypeAttribute{a, b}, []compositeTypeAttribute{b, a}, nil, true},
+	}
+
+	for _, test := range tests {
+		alterations, err := compositeTypeAlterations(test.oldAttributes, test.newAttributes)
+		if (err != nil) != test.wantErr {
+			t.Errorf("compositeTypeAlterations(%v, %v) returned error %v, want error: %t", test.oldAttributes, test.newAttributes, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !reflect.DeepEqual(alterations, test.want) {
+			t.Errorf("compositeTypeAlterations(%v, %v) returned %#v, want %#v", test.oldAttributes, test.newAttributes, alterations, test.want)
+		}
+	}
+}
+
+func TestAccPostgresqlCompositeType_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_composite_type" "test" {
+  database = "%s"
+  schema   = "test_schema"
+  name     = "test_composite"
+
+  attribute {
+    name = "id"
+    type = "int"
+  }
+  attribute {
+    name = "label"
+    type = "varchar(10)"
+  }
+}
+`, dbName)
+
+	configUpdated := fmt.Sprintf(`
+resource "postgresql_composite_type" "test" {
+  database = "%s"
+  schema   = "test_schema"
+  name     = "test_composite"
+
+  attribute {
+    name = "id"
+    type = "bigint"
+  }
+  attribute {
+    name      = "code"
+    type      = "text"
+    collation = "C"
+  }
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCompositeTypeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlCompositeTypeExists("postgresql_composite_type.test"),
+					resource.TestCheckResourceAttr("postgresql_composite_type.test", "attribute.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_composite_type.test", "attribute.0.type", "integer"),
+					resource.TestCheckResourceAttr("postgresql_composite_type.test", "attribute.1.type", "character varying(10)"),
+				),
+			},
+			{
+				Config: configUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlCompositeTypeExists("postgresql_composite_type.test"),
+					resource.TestCheckResourceAttr("postgresql_composite_type.test", "attribute.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_composite_type.test", "attribute.0.type", "bigint"),
+					resource.TestCheckResourceAttr("postgresql_composite_type.test", "attribute.1.name", "code"),
+					resource.TestCheckResourceAttr("postgresql_composite_type.test", "attribute.1.collation", "C"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_composite_type.test",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("%s.test_schema.test_composite", dbName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{compositeTypeDropCascadeAttr},
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlCompositeTypeDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_composite_type" {
+			continue
+		}
+
+		txn, err := startTransaction(client, rs.Primary.Attributes[compositeTypeDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := compositeTypeExists(txn, rs.Primary.Attributes[compositeTypeSchemaAttr], rs.Primary.Attributes[compositeTypeNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking composite type %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Composite type still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlCompositeTypeExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[compositeTypeDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := compositeTypeExists(txn, rs.Primary.Attributes[compositeTypeSchemaAttr], rs.Primary.Attributes[compositeTypeNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking composite type %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Composite type not found")
+		}
+
+		return nil
+	}
+}
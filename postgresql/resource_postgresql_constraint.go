@@ -0,0 +1,680 @@
+package postgresql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	constraintNameAttr              = "name"
+	constraintSchemaAttr            = "schema"
+	constraintDatabaseAttr          = "database"
+	constraintTableAttr             = "table"
+	constraintTypeAttr              = "type"
+	constraintExpressionAttr        = "expression"
+	constraintColumnsAttr           = "columns"
+	constraintIndexMethodAttr       = "index_method"
+	constraintReferencesAttr        = "references"
+	constraintRefSchemaAttr         = "schema"
+	constraintRefTableAttr          = "table"
+	constraintRefColumnsAttr        = "columns"
+	constraintRefOnDeleteAttr       = "on_delete"
+	constraintRefOnUpdateAttr       = "on_update"
+	constraintDeferrableAttr        = "deferrable"
+	constraintInitiallyDeferredAttr = "initially_deferred"
+	constraintNotValidAttr          = "not_valid"
+	constraintValidateAttr          = "validate"
+	constraintValidatedAttr         = "validated"
+	constraintDefinitionAttr        = "definition"
+
+	constraintTypeCheck      = "check"
+	constraintTypeForeignKey = "foreign_key"
+	constraintTypeUnique     = "unique"
+	constraintTypeExclusion  = "exclusion"
+)
+
+// constraintTypes maps the constraint types to their pg_constraint.contype value
+var constraintTypes = map[string]string{
+	constraintTypeCheck:      "c",
+	constraintTypeForeignKey: "f",
+	constraintTypeUnique:     "u",
+	constraintTypeExclusion:  "x",
+}
+
+// constraintRefActions maps the referential actions to their pg_constraint.confupdtype/confdeltype value
+var constraintRefActions = map[string]string{
+	"NO ACTION":   "a",
+	"RESTRICT":    "r",
+	"CASCADE":     "c",
+	"SET NULL":    "n",
+	"SET DEFAULT": "d",
+}
+
+func resourcePostgreSQLConstraint() *schema.Resource {
+	refActions := make([]string, 0, len(constraintRefActions))
+	for action := range constraintRefActions {
+		refActions = append(refActions, action)
+	}
+
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLConstraintCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLConstraintRead),
+		Update: PGResourceFunc(resourcePostgreSQLConstraintUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLConstraintDelete),
+		Exists: PGResourceExistsFunc(resourcePostgreSQLConstraintExists),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourcePostgreSQLConstraintCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			constraintNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the constraint",
+				ValidateFunc: validateIdentifier,
+			},
+			constraintSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema of the table",
+			},
+			constraintDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the table",
+			},
+			constraintTableAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the table the constraint is added to",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			constraintTypeAttr: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					constraintTypeCheck,
+					constraintTypeForeignKey,
+					constraintTypeUnique,
+					constraintTypeExclusion,
+				}, false),
+				Description: "The type of the constraint, one of check, foreign_key, unique or exclusion",
+			},
+			constraintExpressionAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+				Description:      "The boolean expression of a check constraint or the elements of an exclusion constraint (e.g.: `room WITH =, during WITH &&`)",
+			},
+			constraintColumnsAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The columns of a unique or foreign key constraint",
+			},
+			constraintIndexMethodAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The index method of an exclusion constraint, defaults to gist",
+			},
+			constraintReferencesAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "The table referenced by a foreign key constraint",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						constraintRefSchemaAttr: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Default:     "public",
+							Description: "The schema of the referenced table",
+						},
+						constraintRefTableAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							Description:  "The name of the referenced table",
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						constraintRefColumnsAttr: {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Computed:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The referenced columns, defaults to the primary key of the referenced table",
+						},
+						constraintRefOnDeleteAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							Default:      "NO ACTION",
+							ValidateFunc: validation.StringInSlice(refActions, false),
+							Description:  "The action performed when a referenced row is deleted",
+						},
+						constraintRefOnUpdateAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							Default:      "NO ACTION",
+							ValidateFunc: validation.StringInSlice(refActions, false),
+							Description:  "The action performed when a referenced column is updated",
+						},
+					},
+				},
+			},
+			constraintDeferrableAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the checking of the constraint can be deferred to the end of the transaction",
+			},
+			constraintInitiallyDeferredAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the constraint is checked at the end of the transaction by default",
+			},
+			constraintNotValidAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Create the constraint as NOT VALID, i.e.: without checking the existing rows of the table",
+			},
+			constraintValidateAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Validate the constraint against the existing rows of the table if it has been created as NOT VALID",
+			},
+			constraintValidatedAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the constraint has been validated against the existing rows of the table",
+			},
+			constraintDefinitionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The definition of the constraint as returned by pg_get_constraintdef",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLConstraintCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	constraintType := diff.Get(constraintTypeAttr).(string)
+	_, hasExpression := diff.GetOk(constraintExpressionAttr)
+	_, hasReferences := diff.GetOk(constraintReferencesAttr)
+	columns := diff.Get(constraintColumnsAttr).([]interface{})
+
+	switch constraintType {
+	case constraintTypeCheck, constraintTypeExclusion:
+		if !hasExpression {
+			return fmt.Errorf("%s is required for a %s constraint", constraintExpressionAttr, constraintType)
+		}
+		if hasReferences {
+			return fmt.Errorf("%s is only supported by foreign key constraints", constraintReferencesAttr)
+		}
+	case constraintTypeUnique, constraintTypeForeignKey:
+		if hasExpression {
+			return fmt.Errorf("%s is only supported by check and exclusion constraints", constraintExpressionAttr)
+		}
+		if len(columns) == 0 && diff.NewValueKnown(constraintColumnsAttr) {
+			return fmt.Errorf("%s is required for a %s constraint", constraintColumnsAttr, constraintType)
+		}
+		if constraintType == constraintTypeForeignKey && !hasReferences {
+			return fmt.Errorf("%s is required for a foreign key constraint", constraintReferencesAttr)
+		}
+		if constraintType == constraintTypeUnique && hasReferences {
+			return fmt.Errorf("%s is only supported by foreign key constraints", constraintReferencesAttr)
+		}
+	}
+
+	if _, ok := diff.GetOk(constraintIndexMethodAttr); ok && constraintType != constraintTypeExclusion {
+		return fmt.Errorf("%s is only supported by exclusion constraints", constraintIndexMethodAttr)
+	}
+
+	deferrable := diff.Get(constraintDeferrableAttr).(bool)
+	if deferrable && constraintType == constraintTypeCheck {
+		return fmt.Errorf("check constraints cannot be deferrable")
+	}
+	if diff.Get(constraintInitiallyDeferredAttr).(bool) && !deferrable {
+		return fmt.Errorf("%s requires %s to be set", constraintInitiallyDeferredAttr, constraintDeferrableAttr)
+	}
+	if diff.Get(constraintNotValidAttr).(bool) && constraintType != constraintTypeCheck && constraintType != constraintTypeForeignKey {
+		return fmt.Errorf("%s is only supported by check and foreign key constraints", constraintNotValidAttr)
+	}
+
+	if diff.Id() == "" {
+		return nil
+	}
+
+	// Only foreign keys can be altered, the other constraints have to be recreated
+	if constraintType != constraintTypeForeignKey {
+		for _, attr := range []string{constraintDeferrableAttr, constraintInitiallyDeferredAttr} {
+			if diff.HasChange(attr) {
+				if err := diff.ForceNew(attr); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// A constraint not yet validated is validated during the update
+	if diff.Get(constraintValidateAttr).(bool) && !diff.Get(constraintValidatedAttr).(bool) {
+		return diff.SetNew(constraintValidatedAttr, true)
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLConstraintCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(constraintSchemaAttr).(string)
+	tableName := d.Get(constraintTableAttr).(string)
+	constraintName := d.Get(constraintNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(createConstraintQuery(d)); err != nil {
+		return fmt.Errorf("could not create constraint %s: %w", constraintName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating constraint: %w", err)
+	}
+
+	d.SetId(generateConstraintID(database, schemaName, tableName, constraintName))
+
+	// The validation is done in its own transaction, so the table is not locked during the whole scan
+	if d.Get(constraintNotValidAttr).(bool) && d.Get(constraintValidateAttr).(bool) {
+		if err := validateConstraint(db, database, schemaName, tableName, constraintName); err != nil {
+			return err
+		}
+	}
+
+	return resourcePostgreSQLConstraintReadImpl(db, d)
+}
+
+// createConstraintQuery returns the ALTER TABLE ... ADD CONSTRAINT statement of the constraint
+func createConstraintQuery(d *schema.ResourceData) string {
+	b := bytes.NewBufferString("ALTER TABLE ")
+	fmt.Fprint(b, pq.QuoteIdentifier(d.Get(constraintSchemaAttr).(string)), ".", pq.QuoteIdentifier(d.Get(constraintTableAttr).(string)),
+		" ADD CONSTRAINT ", pq.QuoteIdentifier(d.Get(constraintNameAttr).(string)),
+	)
+
+	switch d.Get(constraintTypeAttr).(string) {
+	case constraintTypeCheck:
+		fmt.Fprint(b, " CHECK (", d.Get(constraintExpressionAttr).(string), ")")
+	case constraintTypeUnique:
+		fmt.Fprint(b, " UNIQUE (", quoteConstraintColumns(d.Get(constraintColumnsAttr).([]interface{})), ")")
+	case constraintTypeExclusion:
+		method := "gist"
+		if v, ok := d.GetOk(constraintIndexMethodAttr); ok {
+			method = v.(string)
+		}
+		fmt.Fprint(b, " EXCLUDE USING ", method, " (", d.Get(constraintExpressionAttr).(string), ")")
+	case constraintTypeForeignKey:
+		fmt.Fprint(b, " FOREIGN KEY (", quoteConstraintColumns(d.Get(constraintColumnsAttr).([]interface{})), ")")
+		references := d.Get(constraintReferencesAttr).([]interface{})[0].(map[string]interface{})
+		fmt.Fprint(b, " REFERENCES ",
+			pq.QuoteIdentifier(references[constraintRefSchemaAttr].(string)), ".",
+			pq.QuoteIdentifier(references[constraintRefTableAttr].(string)),
+		)
+		if refColumns := references[constraintRefColumnsAttr].([]interface{}); len(refColumns) > 0 {
+			fmt.Fprint(b, " (", quoteConstraintColumns(refColumns), ")")
+		}
+		fmt.Fprint(b, " ON DELETE ", references[constraintRefOnDeleteAttr].(string),
+			" ON UPDATE ", references[constraintRefOnUpdateAttr].(string),
+		)
+	}
+
+	if d.Get(constraintDeferrableAttr).(bool) {
+		fmt.Fprint(b, " DEFERRABLE")
+		if d.Get(constraintInitiallyDeferredAttr).(bool) {
+			fmt.Fprint(b, " INITIALLY DEFERRED")
+		}
+	}
+
+	if d.Get(constraintNotValidAttr).(bool) {
+		fmt.Fprint(b, " NOT VALID")
+	}
+
+	return b.String()
+}
+
+func quoteConstraintColumns(columns []interface{}) string {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, pq.QuoteIdentifier(column.(string)))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func validateConstraint(db *DBConnection, database, schemaName, tableName, constraintName string) error {
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	sql := fmt.Sprintf("ALTER TABLE %s.%s VALIDATE CONSTRAINT %s",
+		pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(tableName), pq.QuoteIdentifier(constraintName),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not validate constraint %s: %w", constraintName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error validating constraint: %w", err)
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLConstraintExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	database, schemaName, tableName, constraintName, err := getDBConstraintName(d, db.client)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the database exists
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	var _rez bool
+	err = txn.QueryRow(
+		`SELECT TRUE FROM pg_catalog.pg_constraint c `+
+			`JOIN pg_catalog.pg_class t ON t.oid = c.conrelid `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace `+
+			`WHERE n.nspname = $1 AND t.relname = $2 AND c.conname = $3`,
+		schemaName, tableName, constraintName,
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if constraint exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func resourcePostgreSQLConstraintRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLConstraintReadImpl(db, d)
+}
+
+func resourcePostgreSQLConstraintReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, tableName, constraintName, err := getDBConstraintName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var contype, definition, checkExpression, refSchema, refTable, onUpdate, onDelete, method string
+	var deferrable, initiallyDeferred, validated bool
+	var columns, refColumns []string
+	query := `SELECT c.contype::text, c.condeferrable, c.condeferred, c.convalidated, ` +
+		`pg_catalog.pg_get_constraintdef(c.oid, true), COALESCE(pg_catalog.pg_get_expr(c.conbin, c.conrelid, true), ''), ` +
+		`ARRAY(SELECT a.attname FROM generate_series(1, array_length(c.conkey, 1)) AS k(n) ` +
+		`JOIN pg_catalog.pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = c.conkey[k.n] ORDER BY k.n), ` +
+		`COALESCE(rn.nspname, ''), COALESCE(rc.relname, ''), ` +
+		`ARRAY(SELECT a.attname FROM generate_series(1, array_length(c.confkey, 1)) AS k(n) ` +
+		`JOIN pg_catalog.pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = c.confkey[k.n] ORDER BY k.n), ` +
+		`c.confupdtype::text, c.confdeltype::text, COALESCE(am.amname, '') ` +
+		`FROM pg_catalog.pg_constraint c ` +
+		`JOIN pg_catalog.pg_class t ON t.oid = c.conrelid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace ` +
+		`LEFT JOIN pg_catalog.pg_class rc ON rc.oid = c.confrelid ` +
+		`LEFT JOIN pg_catalog.pg_namespace rn ON rn.oid = rc.relnamespace ` +
+		`LEFT JOIN pg_catalog.pg_class ic ON ic.oid = c.conindid AND c.contype = 'x' ` +
+		`LEFT JOIN pg_catalog.pg_am am ON am.oid = ic.relam ` +
+		`WHERE n.nspname = $1 AND t.relname = $2 AND c.conname = $3`
+	err = txn.QueryRow(query, schemaName, tableName, constraintName).Scan(
+		&contype, &deferrable, &initiallyDeferred, &validated, &definition, &checkExpression,
+		pq.Array(&columns), &refSchema, &refTable, pq.Array(&refColumns), &onUpdate, &onDelete, &method,
+	)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL constraint %s on %s.%s not found in database %s", constraintName, schemaName, tableName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading constraint: %w", err)
+	}
+
+	constraintType := ""
+	for name, value := range constraintTypes {
+		if value == contype {
+			constraintType = name
+		}
+	}
+	if constraintType == "" {
+		return fmt.Errorf("constraint %s of type %q is not supported", constraintName, contype)
+	}
+
+	// PostgreSQL rewrites the check expressions, so the configured one is kept if Postgres stores it as the catalog one.
+	// The exclusion elements are only set from the catalog when importing.
+	switch constraintType {
+	case constraintTypeCheck:
+		from := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(tableName))
+		expression, err := configuredSQLExpression(txn, from, d.Get(constraintExpressionAttr).(string), checkExpression)
+		if err != nil {
+			return fmt.Errorf("Error reading constraint: %w", err)
+		}
+		d.Set(constraintExpressionAttr, expression)
+	case constraintTypeExclusion:
+		if _, ok := d.GetOk(constraintTableAttr); !ok {
+			d.Set(constraintExpressionAttr, parseExclusionElements(definition))
+		}
+	}
+
+	if constraintType == constraintTypeForeignKey {
+		d.Set(constraintReferencesAttr, []interface{}{
+			map[string]interface{}{
+				constraintRefSchemaAttr:   refSchema,
+				constraintRefTableAttr:    refTable,
+				constraintRefColumnsAttr:  refColumns,
+				constraintRefOnDeleteAttr: findConstraintRefAction(onDelete),
+				constraintRefOnUpdateAttr: findConstraintRefAction(onUpdate),
+			},
+		})
+	} else {
+		d.Set(constraintReferencesAttr, nil)
+	}
+
+	if constraintType == constraintTypeUnique || constraintType == constraintTypeForeignKey {
+		d.Set(constraintColumnsAttr, columns)
+	} else {
+		d.Set(constraintColumnsAttr, []string{})
+	}
+
+	d.Set(constraintNameAttr, constraintName)
+	d.Set(constraintSchemaAttr, schemaName)
+	d.Set(constraintDatabaseAttr, database)
+	d.Set(constraintTableAttr, tableName)
+	d.Set(constraintTypeAttr, constraintType)
+	d.Set(constraintIndexMethodAttr, method)
+	d.Set(constraintDeferrableAttr, deferrable)
+	d.Set(constraintInitiallyDeferredAttr, initiallyDeferred)
+	d.Set(constraintValidatedAttr, validated)
+	d.Set(constraintDefinitionAttr, definition)
+	d.SetId(generateConstraintID(database, schemaName, tableName, constraintName))
+
+	return nil
+}
+
+func findConstraintRefAction(value string) string {
+	for action, v := range constraintRefActions {
+		if v == value {
+			return action
+		}
+	}
+	return ""
+}
+
+// parseExclusionElements returns the elements of an exclusion constraint definition,
+// e.g.: `EXCLUDE USING gist (room WITH =, during WITH &&)` => `room WITH =, during WITH &&`
+func parseExclusionElements(definition string) string {
+	start, depth := -1, 0
+	var quote byte
+	for i := 0; i < len(definition); i++ {
+		c := definition[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			if depth == 0 {
+				start = i + 1
+			}
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+			if depth == 0 {
+				return definition[start:i]
+			}
+		}
+	}
+	return ""
+}
+
+func resourcePostgreSQLConstraintUpdate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(constraintSchemaAttr).(string)
+	tableName := d.Get(constraintTableAttr).(string)
+	constraintName := d.Get(constraintNameAttr).(string)
+
+	if d.HasChange(constraintDeferrableAttr) || d.HasChange(constraintInitiallyDeferredAttr) {
+		txn, err := startTransaction(db.client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		sql := fmt.Sprintf("ALTER TABLE %s.%s ALTER CONSTRAINT %s",
+			pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(tableName), pq.QuoteIdentifier(constraintName),
+		)
+		switch {
+		case !d.Get(constraintDeferrableAttr).(bool):
+			sql += " NOT DEFERRABLE"
+		case d.Get(constraintInitiallyDeferredAttr).(bool):
+			sql += " DEFERRABLE INITIALLY DEFERRED"
+		default:
+			sql += " DEFERRABLE INITIALLY IMMEDIATE"
+		}
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not alter constraint %s: %w", constraintName, err)
+		}
+
+		if err = txn.Commit(); err != nil {
+			return fmt.Errorf("Error updating constraint: %w", err)
+		}
+	}
+
+	if validated, _ := d.GetChange(constraintValidatedAttr); d.Get(constraintValidateAttr).(bool) && !validated.(bool) {
+		if err := validateConstraint(db, database, schemaName, tableName, constraintName); err != nil {
+			return err
+		}
+	}
+
+	return resourcePostgreSQLConstraintReadImpl(db, d)
+}
+
+func resourcePostgreSQLConstraintDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	constraintName := d.Get(constraintNameAttr).(string)
+	sql := fmt.Sprintf("ALTER TABLE %s.%s DROP CONSTRAINT %s",
+		pq.QuoteIdentifier(d.Get(constraintSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(constraintTableAttr).(string)),
+		pq.QuoteIdentifier(constraintName),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop constraint %s: %w", constraintName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting constraint: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func generateConstraintID(database, schemaName, tableName, constraintName string) string {
+	return joinResourceID(database, schemaName, tableName, constraintName)
+}
+
+// getDBConstraintName returns database, schema, table and name of the constraint. If we are importing this resource,
+// they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBConstraintName(d *schema.ResourceData, client *Client) (string, string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(constraintSchemaAttr).(string)
+	tableName := d.Get(constraintTableAttr).(string)
+	constraintName := d.Get(constraintNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema, table and constraint names.
+	if constraintName == "" {
+		parsed := splitResourceIDN(d.Id(), 4)
+		if len(parsed) != 4 || parsed[3] == "" {
+			return "", "", "", "", fmt.Errorf("constraint ID %s has not the expected format 'database.schema.table.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		tableName = parsed[2]
+		constraintName = parsed[3]
+	}
+	return database, schemaName, tableName, constraintName, nil
+}
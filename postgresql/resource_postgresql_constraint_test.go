@@ -0,0 +1,331 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCreateConstraintQuery(t *testing.T) {
+	var cases = []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			raw:      map[string]interface{}{"name": "tenant_range", "table": "orders", "type": "check", "expression": "tenant_id BETWEEN 1 AND 1000", "not_valid": true},
+			expected: `ALTER TABLE "public"."orders" ADD CONSTRAINT "tenant_range" CHECK (tenant_id BETWEEN 1 AND 1000) NOT VALID`,
+		},
+		{
+			raw:      map[string]interface{}{"name": "orders_ref_key", "schema": "app", "table": "orders", "type": "unique", "columns": []interface{}{"tenant_id", "ref"}},
+			expected: `ALTER TABLE "app"."orders" ADD CONSTRAINT "orders_ref_key" UNIQUE ("tenant_id", "ref")`,
+		},
+		{
+			raw: map[string]interface{}{
+				"name": "orders_customer_fkey", "table": "orders", "type": "foreign_key", "columns": []interface{}{"customer_id"},
+				"references": []interface{}{map[string]interface{}{"schema": "ref", "table": "customers", "on_delete": "CASCADE"}},
+				"deferrable": true, "initially_deferred": true,
+			},
+			expected: `ALTER TABLE "public"."orders" ADD CONSTRAINT "orders_customer_fkey" FOREIGN KEY ("customer_id") REFERENCES "ref"."customers" ` +
+				`ON DELETE CASCADE ON UPDATE NO ACTION DEFERRABLE INITIALLY DEFERRED`,
+		},
+		{
+			raw: map[string]interface{}{
+				"name": "orders_customer_fkey", "table": "orders", "type": "foreign_key", "columns": []interface{}{"customer_id"},
+				"references": []interface{}{map[string]interface{}{"table": "customers", "columns": []interface{}{"id"}}},
+			},
+			expected: `ALTER TABLE "public"."orders" ADD CONSTRAINT "orders_customer_fkey" FOREIGN KEY ("customer_id") REFERENCES "public"."customers" ("id") ` +
+				`ON DELETE NO ACTION ON UPDATE NO ACTION`,
+		},
+		{
+			raw:      map[string]interface{}{"name": "no_overlap", "table": "bookings", "type": "exclusion", "expression": "room WITH =, during WITH &&"},
+			expected: `ALTER TABLE "public"."bookings" ADD CONSTRAINT "no_overlap" EXCLUDE USING gist (room WITH =, during WITH &&)`,
+		},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourcePostgreSQLConstraint().Schema, c.raw)
+		if out := createConstraintQuery(d); out != c.expected {
+			t.Errorf("createConstraintQuery(%v): expected %q, got %q", c.raw, c.expected, out)
+		}
+	}
+}
+
+func TestParseExclusionElements(t *testing.T) {
+	var cases = []struct {
+		definition string
+		expected   string
+	}{
+		{
+			definition: "EXCLUDE USING gist (room WITH =, during WITH &&)",
+			expected:   "room WITH =, during WITH &&",
+		},
+		{
+			definition: "EXCLUDE USING gist (\"Room\" WITH =, int4range(low, high) WITH &&) WHERE (active)",
+			expected:   "\"Room\" WITH =, int4range(low, high) WITH &&",
+		},
+		{
+			definition: "EXCLUDE USING gist (int4range(low, high) WITH &&) WHERE (active)",
+			expected:   "int4range(low, high) WITH &&",
+		},
+	}
+
+	for _, c := range cases {
+		if out := parseExclusionElements(c.definition); out != c.expected {
+			t.Errorf("parseExclusionElements(%q): expected %q, got %q", c.definition, c.expected, out)
+		}
+	}
+}
+
+func TestAccPostgresqlConstraint_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_customers (id integer PRIMARY KEY)")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_orders (id integer, tenant_id integer, customer_id integer)")
+	// This row violates the check constraint until it is deleted
+	dbExecute(t, testConfig.connStr(dbName), "INSERT INTO test_orders VALUES (1, 0, NULL)")
+
+	config := `
+resource "postgresql_constraint" "tenant_range" {
+  database   = "%[1]s"
+  table      = "test_orders"
+  name       = "test_orders_tenant_range"
+  type       = "check"
+  expression = "tenant_id BETWEEN 1 AND 1000"
+  not_valid  = true
+  validate   = %[2]t
+}
+
+resource "postgresql_constraint" "customer_fkey" {
+  database           = "%[1]s"
+  table              = "test_orders"
+  name               = "test_orders_customer_fkey"
+  type               = "foreign_key"
+  columns            = ["customer_id"]
+  deferrable         = true
+  initially_deferred = %[2]t
+
+  references {
+    table     = "test_customers"
+    on_delete = "CASCADE"
+  }
+}
+
+resource "postgresql_constraint" "unique_id" {
+  database = "%[1]s"
+  table    = "test_orders"
+  name     = "test_orders_id_key"
+  type     = "unique"
+  columns  = ["tenant_id", "id"]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlConstraintDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlConstraintExists("postgresql_constraint.tenant_range"),
+					resource.TestCheckResourceAttr("postgresql_constraint.tenant_range", "validated", "false"),
+					testAccCheckPostgresqlConstraintExists("postgresql_constraint.customer_fkey"),
+					resource.TestCheckResourceAttr("postgresql_constraint.customer_fkey", "validated", "true"),
+					resource.TestCheckResourceAttr("postgresql_constraint.customer_fkey", "initially_deferred", "false"),
+					resource.TestCheckResourceAttr("postgresql_constraint.customer_fkey", "references.0.schema", "public"),
+					resource.TestCheckResourceAttr("postgresql_constraint.customer_fkey", "references.0.columns.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_constraint.customer_fkey", "references.0.columns.0", "id"),
+					resource.TestCheckResourceAttr("postgresql_constraint.customer_fkey", "references.0.on_update", "NO ACTION"),
+					testAccCheckPostgresqlConstraintExists("postgresql_constraint.unique_id"),
+					resource.TestCheckResourceAttr("postgresql_constraint.unique_id", "columns.#", "2"),
+				),
+			},
+			{
+				// The check constraint is validated in a later apply and the foreign key is altered in place
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "DELETE FROM test_orders WHERE tenant_id = 0")
+				},
+				Config: fmt.Sprintf(config, dbName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_constraint.tenant_range", "validated", "true"),
+					resource.TestCheckResourceAttr("postgresql_constraint.customer_fkey", "initially_deferred", "true"),
+					func(*terraform.State) error {
+						// The foreign key is only checked at commit, once the customer is loaded
+						dbExecute(t, testConfig.connStr(dbName), "BEGIN; INSERT INTO test_orders VALUES (2, 1, 42); INSERT INTO test_customers VALUES (42); COMMIT")
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            "postgresql_constraint.tenant_range",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"expression", "not_valid", "validate"},
+			},
+			{
+				ResourceName:      "postgresql_constraint.customer_fkey",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlConstraint_RewrittenExpression(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_tickets (id integer, status text, priority integer)")
+
+	// Postgres stores status = 'open'::text OR priority > 0 AND priority < 10,
+	// the configured expression is kept so the plan is empty after the apply.
+	config := fmt.Sprintf(`
+resource "postgresql_constraint" "status" {
+  database   = "%s"
+  table      = "test_tickets"
+  name       = "test_tickets_status"
+  type       = "check"
+  expression = "(status = 'open') OR ((priority > 0) AND (priority < 10))"
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlConstraintDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlConstraintExists("postgresql_constraint.status"),
+					resource.TestCheckResourceAttr("postgresql_constraint.status", "expression", "(status = 'open') OR ((priority > 0) AND (priority < 10))"),
+				),
+			},
+			{
+				// The expression is changed outside of Terraform, the constraint is replaced
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "ALTER TABLE test_tickets DROP CONSTRAINT test_tickets_status")
+					dbExecute(t, testConfig.connStr(dbName), "ALTER TABLE test_tickets ADD CONSTRAINT test_tickets_status CHECK (status = 'closed')")
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlConstraintExists("postgresql_constraint.status"),
+					resource.TestCheckResourceAttr("postgresql_constraint.status", "expression", "(status = 'open') OR ((priority > 0) AND (priority < 10))"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlConstraint_Exclusion(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_bookings (room integer, during int4range)")
+
+	config := fmt.Sprintf(`
+resource "postgresql_constraint" "no_overlap" {
+  database     = "%s"
+  table        = "test_bookings"
+  name         = "test_bookings_no_overlap"
+  type         = "exclusion"
+  index_method = "gist"
+  expression   = "during WITH &&"
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlConstraintDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlConstraintExists("postgresql_constraint.no_overlap"),
+					resource.TestCheckResourceAttr("postgresql_constraint.no_overlap", "index_method", "gist"),
+					resource.TestCheckResourceAttr("postgresql_constraint.no_overlap", "definition", "EXCLUDE USING gist (during WITH &&)"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_constraint.no_overlap",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlConstraintExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		exists, err := checkConstraintExists(rs)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("Constraint %s not found", rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
+func testAccCheckPostgresqlConstraintDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_constraint" {
+			continue
+		}
+
+		exists, err := checkConstraintExists(rs)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("Constraint %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func checkConstraintExists(rs *terraform.ResourceState) (bool, error) {
+	client := testAccProvider.Meta().(*Client)
+	txn, err := startTransaction(client, rs.Primary.Attributes["database"])
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	var count int
+	err = txn.QueryRow(
+		`SELECT count(*) FROM pg_catalog.pg_constraint c `+
+			`JOIN pg_catalog.pg_class t ON t.oid = c.conrelid `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace `+
+			`WHERE n.nspname = $1 AND t.relname = $2 AND c.conname = $3`,
+		rs.Primary.Attributes["schema"], rs.Primary.Attributes["table"], rs.Primary.Attributes["name"],
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("Error checking constraint: %w", err)
+	}
+	return count > 0, nil
+}
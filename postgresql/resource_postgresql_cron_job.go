@@ -0,0 +1,257 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	cronJobNameAttr     = "job_name"
+	cronJobScheduleAttr = "schedule"
+	cronJobCommandAttr  = "command"
+	cronJobDatabaseAttr = "database"
+	cronJobUsernameAttr = "username"
+	cronJobIDAttr       = "job_id"
+)
+
+func resourcePostgreSQLCronJob() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLCronJobCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLCronJobRead),
+		Update: PGResourceFunc(resourcePostgreSQLCronJobUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLCronJobDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			cronJobNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the job",
+			},
+			cronJobScheduleAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The schedule of the job, in cron syntax (e.g.: 0 3 * * *) or an interval of seconds (e.g.: 30 seconds)",
+			},
+			cronJobCommandAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The SQL command run by the job",
+			},
+			cronJobDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The database in which the command is run, defaults to the database where pg_cron is installed",
+			},
+			cronJobUsernameAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The role running the command, defaults to the role of the provider",
+			},
+			cronJobIDAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The identifier of the job in cron.job",
+			},
+		},
+	}
+}
+
+// checkCronInstalled returns an error if the pg_cron extension is not installed in the database of the
+// provider, the jobs being managed in the database set by the cron.database_name parameter.
+func checkCronInstalled(txn *sql.Tx, database string) error {
+	var installed bool
+	if err := txn.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_extension WHERE extname = 'pg_cron')",
+	).Scan(&installed); err != nil {
+		return fmt.Errorf("could not check if pg_cron is installed: %w", err)
+	}
+	if !installed {
+		return fmt.Errorf(
+			"pg_cron extension is not installed in database %s, the provider must connect to the database "+
+				"where pg_cron is installed (set by the cron.database_name parameter)", database,
+		)
+	}
+	return nil
+}
+
+func resourcePostgreSQLCronJobCreate(db *DBConnection, d *schema.ResourceData) error {
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := checkCronInstalled(txn, db.client.databaseName); err != nil {
+		return err
+	}
+
+	jobID, err := scheduleCronJob(txn, d)
+	if err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating cron job: %w", err)
+	}
+
+	d.SetId(strconv.FormatInt(jobID, 10))
+
+	return resourcePostgreSQLCronJobReadImpl(db, d)
+}
+
+// scheduleCronJob schedules the job, cron.schedule_in_database (pg_cron 1.4+) is only used
+// when the database or the username of the job differ from the ones of the connection.
+func scheduleCronJob(txn *sql.Tx, d *schema.ResourceData) (int64, error) {
+	name := d.Get(cronJobNameAttr).(string)
+	schedule := d.Get(cronJobScheduleAttr).(string)
+	command := d.Get(cronJobCommandAttr).(string)
+	database := d.Get(cronJobDatabaseAttr).(string)
+	username := d.Get(cronJobUsernameAttr).(string)
+
+	var currentDatabase, currentUser string
+	if err := txn.QueryRow("SELECT pg_catalog.current_database(), current_user").Scan(&currentDatabase, &currentUser); err != nil {
+		return 0, fmt.Errorf("could not read current database and user: %w", err)
+	}
+
+	var jobID int64
+	var err error
+	if (database == "" || database == currentDatabase) && (username == "" || username == currentUser) {
+		err = txn.QueryRow("SELECT cron.schedule($1, $2, $3)", name, schedule, command).Scan(&jobID)
+	} else {
+		err = txn.QueryRow(
+			"SELECT cron.schedule_in_database($1, $2, $3, COALESCE(NULLIF($4, ''), pg_catalog.current_database()), NULLIF($5, ''))",
+			name, schedule, command, database, username,
+		).Scan(&jobID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not schedule cron job %s: %w", name, err)
+	}
+	return jobID, nil
+}
+
+func resourcePostgreSQLCronJobRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLCronJobReadImpl(db, d)
+}
+
+func resourcePostgreSQLCronJobReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	jobID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("cron job ID %s is not a job identifier: %w", d.Id(), err)
+	}
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := checkCronInstalled(txn, db.client.databaseName); err != nil {
+		return err
+	}
+
+	var name, schedule, command, database, username string
+	err = txn.QueryRow(
+		"SELECT COALESCE(jobname, ''), schedule, command, database, username FROM cron.job WHERE jobid = $1",
+		jobID,
+	).Scan(&name, &schedule, &command, &database, &username)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL cron job %d not found", jobID)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading cron job: %w", err)
+	}
+
+	d.Set(cronJobIDAttr, jobID)
+	d.Set(cronJobNameAttr, name)
+	d.Set(cronJobScheduleAttr, schedule)
+	d.Set(cronJobCommandAttr, command)
+	d.Set(cronJobDatabaseAttr, database)
+	d.Set(cronJobUsernameAttr, username)
+
+	return nil
+}
+
+func resourcePostgreSQLCronJobUpdate(db *DBConnection, d *schema.ResourceData) error {
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := checkCronInstalled(txn, db.client.databaseName); err != nil {
+		return err
+	}
+
+	// cron.alter_job has been added in pg_cron 1.4, the job is scheduled again with older versions
+	var canAlter bool
+	if err := txn.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_proc p JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace " +
+			"WHERE n.nspname = 'cron' AND p.proname = 'alter_job')",
+	).Scan(&canAlter); err != nil {
+		return fmt.Errorf("could not check if cron.alter_job exists: %w", err)
+	}
+
+	if canAlter {
+		if _, err := txn.Exec(
+			"SELECT cron.alter_job($1::bigint, $2, $3, $4, $5)",
+			d.Id(), d.Get(cronJobScheduleAttr), d.Get(cronJobCommandAttr), d.Get(cronJobDatabaseAttr), d.Get(cronJobUsernameAttr),
+		); err != nil {
+			return fmt.Errorf("could not update cron job %s: %w", d.Get(cronJobNameAttr), err)
+		}
+	} else {
+		if _, err := txn.Exec("SELECT cron.unschedule($1::bigint)", d.Id()); err != nil {
+			return fmt.Errorf("could not unschedule cron job %s: %w", d.Get(cronJobNameAttr), err)
+		}
+		jobID, err := scheduleCronJob(txn, d)
+		if err != nil {
+			return err
+		}
+		d.SetId(strconv.FormatInt(jobID, 10))
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating cron job: %w", err)
+	}
+
+	return resourcePostgreSQLCronJobReadImpl(db, d)
+}
+
+func resourcePostgreSQLCronJobDelete(db *DBConnection, d *schema.ResourceData) error {
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := checkCronInstalled(txn, db.client.databaseName); err != nil {
+		return err
+	}
+
+	if _, err := txn.Exec("SELECT cron.unschedule($1::bigint)", d.Id()); err != nil {
+		return fmt.Errorf("could not unschedule cron job %s: %w", d.Get(cronJobNameAttr), err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting cron job: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
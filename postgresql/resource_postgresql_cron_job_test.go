@@ -0,0 +1,158 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// isCronInstalled checks if pg_cron is installed in the database of the provider,
+// it requires the server to be started with pg_cron in shared_preload_libraries.
+func isCronInstalled(t *testing.T) bool {
+	config := getTestConfig(t)
+	db, err := sql.Open("postgres", config.connStr("postgres"))
+	if err != nil {
+		t.Fatalf("could not connect to the test database: %v", err)
+	}
+	defer db.Close()
+
+	var installed bool
+	if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_extension WHERE extname = 'pg_cron')").Scan(&installed); err != nil {
+		t.Fatalf("could not check if pg_cron is installed: %v", err)
+	}
+	return installed
+}
+
+func TestAccPostgresqlCronJob_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+	if !isCronInstalled(t) {
+		t.Skip("Skip as pg_cron is not installed in the postgres database")
+	}
+
+	config := `
+resource "postgresql_cron_job" "vacuum" {
+  job_name = "tf_test_vacuum"
+  schedule = "%s"
+  command  = "VACUUM pg_catalog.pg_class"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlCronJobDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "0 3 * * *"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlCronJobExists("postgresql_cron_job.vacuum", "0 3 * * *"),
+					resource.TestCheckResourceAttr("postgresql_cron_job.vacuum", "job_name", "tf_test_vacuum"),
+					resource.TestCheckResourceAttr("postgresql_cron_job.vacuum", "database", "postgres"),
+					resource.TestCheckResourceAttrSet("postgresql_cron_job.vacuum", "job_id"),
+					resource.TestCheckResourceAttrSet("postgresql_cron_job.vacuum", "username"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, "30 4 * * 0"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlCronJobExists("postgresql_cron_job.vacuum", "30 4 * * 0"),
+					resource.TestCheckResourceAttr("postgresql_cron_job.vacuum", "schedule", "30 4 * * 0"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_cron_job.vacuum",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlCronJob_NotInstalled(t *testing.T) {
+	skipIfNotAcc(t)
+	if isCronInstalled(t) {
+		t.Skip("Skip as pg_cron is installed in the postgres database")
+	}
+
+	config := `
+resource "postgresql_cron_job" "vacuum" {
+  job_name = "tf_test_vacuum"
+  schedule = "0 3 * * *"
+  command  = "VACUUM"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile("pg_cron extension is not installed in database postgres"),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlCronJobDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_cron_job" {
+			continue
+		}
+
+		schedule, err := getCronJobSchedule(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if schedule != "" {
+			return fmt.Errorf("Cron job %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlCronJobExists(n string, expectedSchedule string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		schedule, err := getCronJobSchedule(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if schedule != expectedSchedule {
+			return fmt.Errorf("Cron job %s has schedule %q, expected %q", rs.Primary.ID, schedule, expectedSchedule)
+		}
+
+		return nil
+	}
+}
+
+func getCronJobSchedule(jobID string) (string, error) {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return "", err
+	}
+
+	var schedule string
+	err = db.QueryRow("SELECT schedule FROM cron.job WHERE jobid = $1", jobID).Scan(&schedule)
+	switch {
+	case err == sql.ErrNoRows:
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("Error reading cron job %s: %w", jobID, err)
+	}
+	return schedule, nil
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -24,24 +26,41 @@ const (
 	dbOwnerAttr      = "owner"
 	dbTablespaceAttr = "tablespace_name"
 	dbTemplateAttr   = "template"
+
+	dbSessionPreloadLibrariesAttr = "session_preload_libraries"
+	dbLocalPreloadLibrariesAttr   = "local_preload_libraries"
 )
 
+// dbListSettings are the list-valued configuration parameters which can be set on a database
+var dbListSettings = []string{
+	dbSessionPreloadLibrariesAttr,
+	dbLocalPreloadLibrariesAttr,
+}
+
 func resourcePostgreSQLDatabase() *schema.Resource {
 	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLDatabaseCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLDatabaseRead),
-		Update: PGResourceFunc(resourcePostgreSQLDatabaseUpdate),
-		Delete: PGResourceFunc(resourcePostgreSQLDatabaseDelete),
-		Exists: PGResourceExistsFunc(resourcePostgreSQLDatabaseExists),
+		CreateContext: PGResourceContextFunc(resourcePostgreSQLDatabaseCreate),
+		ReadContext:   PGResourceContextFunc(resourcePostgreSQLDatabaseRead),
+		UpdateContext: PGResourceContextFunc(resourcePostgreSQLDatabaseUpdate),
+		DeleteContext: PGResourceContextFunc(resourcePostgreSQLDatabaseDelete),
+		Exists:        PGResourceExistsFunc(resourcePostgreSQLDatabaseExists),
+		Timeouts: &schema.ResourceTimeout{
+			// Copying a large template database can take a long time
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
 			dbNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The PostgreSQL database name to connect to",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The PostgreSQL database name to connect to",
 			},
 			dbOwnerAttr: {
 				Type:        schema.TypeString,
@@ -102,6 +121,20 @@ func resourcePostgreSQLDatabase() *schema.Resource {
 				Computed:    true,
 				Description: "If true, then this database can be cloned by any user with CREATEDB privileges",
 			},
+			dbSessionPreloadLibrariesAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Shared libraries to preload at the start of the sessions connecting to this database (e.g.: auto_explain)",
+			},
+			dbLocalPreloadLibrariesAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Shared libraries from the plugins directory to preload at the start of the sessions connecting to this database",
+			},
 		},
 	}
 }
@@ -113,6 +146,10 @@ func resourcePostgreSQLDatabaseCreate(db *DBConnection, d *schema.ResourceData)
 
 	d.SetId(d.Get(dbNameAttr).(string))
 
+	if err := setDBListSettings(db, d); err != nil {
+		return err
+	}
+
 	return resourcePostgreSQLDatabaseReadImpl(db, d)
 }
 
@@ -137,11 +174,11 @@ func createDatabase(db *DBConnection, d *schema.ResourceData) error {
 		// superuser
 		ownerGranted, err := grantRoleMembership(db, owner, currentUser)
 		if err != nil {
-			return err
+			return databaseOwnerError(err, owner, currentUser)
 		}
 		if ownerGranted {
 			defer func() {
-				_, err = revokeRoleMembership(db, owner, currentUser)
+				_, err = revokeRoleMembership(db.withoutDeadline(), owner, currentUser)
 			}()
 		}
 	}
@@ -221,6 +258,9 @@ func createDatabase(db *DBConnection, d *schema.ResourceData) error {
 
 	sql := b.String()
 	if _, err := db.Exec(sql); err != nil {
+		if owner != "" {
+			err = databaseOwnerError(err, owner, currentUser)
+		}
 		return fmt.Errorf("Error creating database %q: %w", dbName, err)
 	}
 
@@ -229,6 +269,17 @@ func createDatabase(db *DBConnection, d *schema.ResourceData) error {
 	return err
 }
 
+// databaseOwnerError explains the errors returned when the connected user cannot be granted the owner
+// of the database, which is required to manage a database owned by another role when the connected user
+// is not a superuser (e.g.: on AWS RDS).
+func databaseOwnerError(err error, owner, currentUser string) error {
+	return ownerMembershipError(err, owner, currentUser, fmt.Sprintf(
+		"must be a member of the role %[1]s to manage a database owned by it, "+
+			"and could not be granted it (it needs the ADMIN OPTION on %[1]s, or CREATEROLE before Postgres 16)",
+		pq.QuoteIdentifier(owner),
+	))
+}
+
 func resourcePostgreSQLDatabaseDelete(db *DBConnection, d *schema.ResourceData) error {
 	currentUser := db.client.config.getDatabaseUsername()
 	owner := d.Get(dbOwnerAttr).(string)
@@ -246,11 +297,11 @@ func resourcePostgreSQLDatabaseDelete(db *DBConnection, d *schema.ResourceData)
 		// superuser
 		ownerGranted, err := grantRoleMembership(db, owner, currentUser)
 		if err != nil {
-			return err
+			return databaseOwnerError(err, owner, currentUser)
 		}
 		if ownerGranted {
 			defer func() {
-				_, err = revokeRoleMembership(db, owner, currentUser)
+				_, err = revokeRoleMembership(db.withoutDeadline(), owner, currentUser)
 			}()
 		}
 	}
@@ -385,9 +436,35 @@ func resourcePostgreSQLDatabaseReadImpl(db *DBConnection, d *schema.ResourceData
 		d.Set(dbIsTemplateAttr, dbIsTemplate)
 	}
 
+	var dbConfig []string
+	err = db.QueryRow(
+		`SELECT COALESCE(s.setconfig, '{}') FROM pg_catalog.pg_database d `+
+			`LEFT JOIN pg_catalog.pg_db_role_setting s ON s.setdatabase = d.oid AND s.setrole = 0 `+
+			`WHERE d.datname = $1`,
+		dbId,
+	).Scan(pq.Array(&dbConfig))
+	if err != nil {
+		return fmt.Errorf("Error reading database settings: %w", err)
+	}
+
+	for _, setting := range dbListSettings {
+		d.Set(setting, readDBListSetting(dbConfig, setting))
+	}
+
 	return nil
 }
 
+// readDBListSetting searches for a list-valued setting in the setconfig array of the database.
+// In case no such value is present, it returns an empty list.
+func readDBListSetting(dbConfig []string, setting string) []string {
+	for _, config := range dbConfig {
+		if strings.HasPrefix(config, setting+"=") {
+			return parseSettingList(strings.TrimPrefix(config, setting+"="))
+		}
+	}
+	return []string{}
+}
+
 func resourcePostgreSQLDatabaseUpdate(db *DBConnection, d *schema.ResourceData) error {
 	if err := setDBName(db, d); err != nil {
 		return err
@@ -413,7 +490,9 @@ func resourcePostgreSQLDatabaseUpdate(db *DBConnection, d *schema.ResourceData)
 		return err
 	}
 
-	// Empty values: ALTER DATABASE name RESET configuration_parameter;
+	if err := setDBListSettings(db, d); err != nil {
+		return err
+	}
 
 	return resourcePostgreSQLDatabaseReadImpl(db, d)
 }
@@ -459,11 +538,11 @@ func setDBOwner(db *DBConnection, d *schema.ResourceData) error {
 	//needed in order to set the owner of the db if the connection user is not a superuser
 	ownerGranted, err := grantRoleMembership(db, owner, currentUser)
 	if err != nil {
-		return err
+		return databaseOwnerError(err, owner, currentUser)
 	}
 	if ownerGranted {
 		defer func() {
-			_, err = revokeRoleMembership(db, owner, currentUser)
+			_, err = revokeRoleMembership(db.withoutDeadline(), owner, currentUser)
 		}()
 	}
 
@@ -497,6 +576,36 @@ func setDBTablespace(db QueryAble, d *schema.ResourceData) error {
 	return nil
 }
 
+func setDBListSettings(db QueryAble, d *schema.ResourceData) error {
+	dbName := d.Get(dbNameAttr).(string)
+
+	for _, setting := range dbListSettings {
+		if !d.HasChange(setting) {
+			continue
+		}
+
+		values := d.Get(setting).(*schema.Set).List()
+		sort.Slice(values, func(i, j int) bool { return values[i].(string) < values[j].(string) })
+
+		var sql string
+		if len(values) == 0 {
+			sql = fmt.Sprintf("ALTER DATABASE %s RESET %s", pq.QuoteIdentifier(dbName), setting)
+		} else {
+			quotedValues := make([]string, len(values))
+			for i, value := range values {
+				quotedValues[i] = pq.QuoteLiteral(value.(string))
+			}
+			sql = fmt.Sprintf("ALTER DATABASE %s SET %s TO %s", pq.QuoteIdentifier(dbName), setting, strings.Join(quotedValues, ", "))
+		}
+
+		if _, err := db.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating database %s: %w", setting, err)
+		}
+	}
+
+	return nil
+}
+
 func setDBConnLimit(db QueryAble, d *schema.ResourceData) error {
 	if !d.HasChange(dbConnLimitAttr) {
 		return nil
@@ -518,7 +627,7 @@ func setDBAllowConns(db *DBConnection, d *schema.ResourceData) error {
 	}
 
 	if !db.featureSupported(featureDBAllowConnections) {
-		return fmt.Errorf("PostgreSQL client is talking with a server (%q) that does not support database ALLOW_CONNECTIONS", db.version.String())
+		return fmt.Errorf("database ALLOW_CONNECTIONS is not supported %s", db.unsupportedServer())
 	}
 
 	allowConns := d.Get(dbAllowConnsAttr).(bool)
@@ -545,7 +654,7 @@ func setDBIsTemplate(db *DBConnection, d *schema.ResourceData) error {
 
 func doSetDBIsTemplate(db *DBConnection, dbName string, isTemplate bool) error {
 	if !db.featureSupported(featureDBIsTemplate) {
-		return fmt.Errorf("PostgreSQL client is talking with a server (%q) that does not support database IS_TEMPLATE", db.version.String())
+		return fmt.Errorf("database IS_TEMPLATE is not supported %s", db.unsupportedServer())
 	}
 
 	sql := fmt.Sprintf("ALTER DATABASE %s IS_TEMPLATE %t", pq.QuoteIdentifier(dbName), isTemplate)
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestAccPostgresqlDatabase_Basic(t *testing.T) {
@@ -187,6 +190,93 @@ resource postgresql_database test_db {
 	})
 }
 
+func TestAccPostgresqlDatabase_PreloadLibraries(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			// session_preload_libraries can only be set by a superuser
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_database test_db {
+	name                      = "test_preload_db"
+	session_preload_libraries = ["auto_explain"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.test_db"),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "session_preload_libraries.#", "1"),
+					resource.TestCheckTypeSetElemAttr("postgresql_database.test_db", "session_preload_libraries.*", "auto_explain"),
+					testAccCheckDatabaseSetting("test_preload_db", "session_preload_libraries", "auto_explain"),
+				),
+			},
+			{
+				// The order of the libraries does not matter
+				Config: `
+resource postgresql_database test_db {
+	name                      = "test_preload_db"
+	session_preload_libraries = ["pg_prewarm", "auto_explain"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "session_preload_libraries.#", "2"),
+					testAccCheckDatabaseSetting("test_preload_db", "session_preload_libraries", "auto_explain, pg_prewarm"),
+				),
+			},
+			{
+				Config: `
+resource postgresql_database test_db {
+	name = "test_preload_db"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "session_preload_libraries.#", "0"),
+					testAccCheckDatabaseSetting("test_preload_db", "session_preload_libraries", ""),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckDatabaseSetting checks the value of a setting set on a database (empty if the setting is not set).
+func testAccCheckDatabaseSetting(dbName, setting, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var dbConfig []string
+		err = db.QueryRow(
+			`SELECT COALESCE(s.setconfig, '{}') FROM pg_catalog.pg_database d `+
+				`LEFT JOIN pg_catalog.pg_db_role_setting s ON s.setdatabase = d.oid AND s.setrole = 0 `+
+				`WHERE d.datname = $1`,
+			dbName,
+		).Scan(pq.Array(&dbConfig))
+		if err != nil {
+			return fmt.Errorf("could not read settings of database %s: %w", dbName, err)
+		}
+
+		var value string
+		for _, config := range dbConfig {
+			if strings.HasPrefix(config, setting+"=") {
+				value = strings.TrimPrefix(config, setting+"=")
+			}
+		}
+
+		if value != expected {
+			return fmt.Errorf("setting %s of database %s is %q, expected %q", setting, dbName, value, expected)
+		}
+
+		return nil
+	}
+}
+
 // Test the case where we need to grant the owner to the connected user.
 // The owner should be revoked
 func TestAccPostgresqlDatabase_GrantOwner(t *testing.T) {
@@ -266,6 +356,65 @@ resource postgresql_database "test_db" {
 	})
 }
 
+func TestDatabaseOwnerError(t *testing.T) {
+	var tests = []struct {
+		err        error
+		membership bool
+	}{
+		{&pq.Error{Code: "42501", Message: `must have admin option on role "app_owner"`}, true},
+		{&pq.Error{Code: "42501", Message: `must be able to SET ROLE "app_owner"`}, true},
+		{&pq.Error{Code: "42P04", Message: `database "app" already exists`}, false},
+		{errors.New("connection refused"), false},
+	}
+
+	for _, test := range tests {
+		err := databaseOwnerError(test.err, "app_owner", "rds_admin")
+		if !errors.Is(err, test.err) {
+			t.Errorf("databaseOwnerError(%v) should wrap the original error, got %v", test.err, err)
+		}
+		if membership := strings.Contains(err.Error(), `GRANT "app_owner" TO "rds_admin"`); membership != test.membership {
+			t.Errorf("databaseOwnerError(%v): expected membership message %v, got %v", test.err, test.membership, err)
+		}
+	}
+}
+
+// Test the error returned when the connected user, which is not a superuser (e.g.: on RDS),
+// cannot be granted the owner of the database.
+func TestAccPostgresqlDatabase_OwnerNotGrantable(t *testing.T) {
+	skipIfNotAcc(t)
+	skipIfNotSuperuser(t)
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+
+	dbExecute(t, dsn, "CREATE ROLE test_db_creator LOGIN CREATEDB PASSWORD 'test_db_creator'")
+	defer dbExecute(t, dsn, "DROP ROLE test_db_creator")
+	dbExecute(t, dsn, "CREATE ROLE test_foreign_owner")
+	defer dbExecute(t, dsn, "DROP ROLE test_foreign_owner")
+
+	config.Username = "test_db_creator"
+	config.Password = "test_db_creator"
+	config.Superuser = false
+	db, err := config.NewClient("postgres").Connect()
+	if err != nil {
+		t.Fatalf("could not connect as test_db_creator: %v", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLDatabase().Schema, map[string]interface{}{
+		"name":  "test_foreign_db",
+		"owner": "test_foreign_owner",
+	})
+	err = createDatabase(db, d)
+	if err == nil {
+		dbExecute(t, dsn, "DROP DATABASE test_foreign_db")
+		t.Fatalf("expected an error creating a database owned by a role not granted to the connected user")
+	}
+	expected := `the connected user "test_db_creator" must be a member of the role "test_foreign_owner"`
+	if !strings.Contains(err.Error(), expected) {
+		t.Fatalf("expected error to contain %q, got %v", expected, err)
+	}
+}
+
 func checkUserMembership(
 	t *testing.T, dsn, member, role string, shouldHaveRole bool,
 ) resource.TestCheckFunc {
@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -15,35 +16,43 @@ import (
 
 func resourcePostgreSQLDefaultPrivileges() *schema.Resource {
 	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLDefaultPrivilegesCreate),
-		Update: PGResourceFunc(resourcePostgreSQLDefaultPrivilegesCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLDefaultPrivilegesRead),
-		Delete: PGResourceFunc(resourcePostgreSQLDefaultPrivilegesDelete),
+		Create:        PGResourceFunc(resourcePostgreSQLDefaultPrivilegesCreate),
+		Update:        PGResourceFunc(resourcePostgreSQLDefaultPrivilegesCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLDefaultPrivilegesRead),
+		Delete:        PGResourceFunc(resourcePostgreSQLDefaultPrivilegesDelete),
+		CustomizeDiff: resourcePostgreSQLDefaultPrivilegesCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePostgreSQLDefaultPrivilegesImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"role": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The name of the role to which grant default privileges on",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the role to which grant default privileges on",
 			},
 			"database": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The database to grant default privileges for this role",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The database to grant default privileges for this role",
 			},
 			"owner": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Target role for which to alter default privileges.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "Target role for which to alter default privileges.",
 			},
 			"schema": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "The database schema to set default privileges for this role",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateOptionalIdentifier,
+				Description:  "The database schema to set default privileges for this role",
 			},
 			"object_type": {
 				Type:     schema.TypeString,
@@ -82,8 +91,8 @@ func resourcePostgreSQLDefaultPrivilegesRead(db *DBConnection, d *schema.Resourc
 
 	if pgSchema != "" && objectType == "schema" && !db.featureSupported(featurePrivilegesOnSchemas) {
 		return fmt.Errorf(
-			"changing default privileges for schemas is not supported for this Postgres version (%s)",
-			db.version,
+			"changing default privileges for schemas is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -105,6 +114,97 @@ func resourcePostgreSQLDefaultPrivilegesRead(db *DBConnection, d *schema.Resourc
 	return readRoleDefaultPrivileges(txn, d)
 }
 
+// resourcePostgreSQLDefaultPrivilegesImport imports default privileges from an ID in the format
+// role|object_type|database|schema|owner (schema is empty for the default privileges of the database),
+// the privileges are read from the catalog.
+func resourcePostgreSQLDefaultPrivilegesImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "|")
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("invalid import ID %q: expected the format role|object_type|database|schema|owner", d.Id())
+	}
+	role, objectType, database, pgSchema, owner := parts[0], parts[1], parts[2], parts[3], parts[4]
+
+	for name, value := range map[string]string{"role": role, "database": database, "owner": owner} {
+		if value == "" {
+			return nil, fmt.Errorf("invalid import ID %q: %s is empty", d.Id(), name)
+		}
+	}
+	if _, ok := objectTypes[objectType]; !ok {
+		return nil, fmt.Errorf("invalid import ID %q: object type %q is not one of table, sequence, function, type, schema", d.Id(), objectType)
+	}
+
+	d.Set("role", role)
+	d.Set("object_type", objectType)
+	d.Set("database", database)
+	d.Set("schema", pgSchema)
+	d.Set("owner", owner)
+
+	db, err := meta.(*Client).Connect()
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return nil, err
+	}
+	defer deferredRollback(txn)
+
+	if err := readRoleDefaultPrivileges(txn, d); err != nil {
+		return nil, err
+	}
+	if d.Get("privileges").(*schema.Set).Len() == 0 && implicitDefaultPrivileges(d) == nil {
+		return nil, fmt.Errorf("role %s has no default privileges on %ss created by %s matching import ID %s", role, objectType, owner, d.Id())
+	}
+
+	withGrantOption, err := readDefaultPrivilegesGrantOption(txn, d)
+	if err != nil {
+		return nil, err
+	}
+	d.Set("with_grant_option", withGrantOption)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// readDefaultPrivilegesGrantOption returns true if all the default privileges of the role are grantable.
+func readDefaultPrivilegesGrantOption(txn *sql.Tx, d *schema.ResourceData) (bool, error) {
+	query := `SELECT COALESCE(bool_and(a.is_grantable), false) ` +
+		`FROM pg_catalog.pg_default_acl da ` +
+		`LEFT JOIN pg_catalog.pg_namespace n ON n.oid = da.defaclnamespace, ` +
+		`pg_catalog.aclexplode(da.defaclacl) a ` +
+		`WHERE da.defaclobjtype = $1 AND COALESCE(n.nspname, '') = $2 ` +
+		`AND pg_catalog.pg_get_userbyid(da.defaclrole) = $3 AND pg_catalog.pg_get_userbyid(a.grantee) = $4`
+
+	var withGrantOption bool
+	if err := txn.QueryRow(
+		query, objectTypes[d.Get("object_type").(string)], d.Get("schema").(string), d.Get("owner").(string), d.Get("role").(string),
+	).Scan(&withGrantOption); err != nil {
+		return false, fmt.Errorf("could not read default privileges grant option: %w", err)
+	}
+	return withGrantOption, nil
+}
+
+// resourcePostgreSQLDefaultPrivilegesCustomizeDiff rejects at plan time the privileges not allowed for the
+// object type and the default privileges on schemas if the version of the server does not support them.
+func resourcePostgreSQLDefaultPrivilegesCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeDiffPrivileges(ctx, diff, meta); err != nil {
+		return err
+	}
+	if !diff.NewValueKnown("object_type") || diff.Get("object_type").(string) != "schema" {
+		return nil
+	}
+	if diff.NewValueKnown("schema") && diff.Get("schema").(string) != "" {
+		return fmt.Errorf("cannot specify `schema` when `object_type` is `schema`")
+	}
+	if db := planConnection(meta); db != nil && !db.featureSupported(featurePrivilegesOnSchemas) {
+		return fmt.Errorf(
+			"changing default privileges for schemas is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+	return nil
+}
+
 func resourcePostgreSQLDefaultPrivilegesCreate(db *DBConnection, d *schema.ResourceData) error {
 	pgSchema := d.Get("schema").(string)
 	objectType := d.Get("object_type").(string)
@@ -112,8 +212,8 @@ func resourcePostgreSQLDefaultPrivilegesCreate(db *DBConnection, d *schema.Resou
 	if pgSchema != "" && objectType == "schema" {
 		if !db.featureSupported(featurePrivilegesOnSchemas) {
 			return fmt.Errorf(
-				"changing default privileges for schemas is not supported for this Postgres version (%s)",
-				db.version,
+				"changing default privileges for schemas is not supported %s",
+				db.unsupportedServer(),
 			)
 		}
 		return fmt.Errorf("cannot specify `schema` when `object_type` is `schema`")
@@ -146,14 +246,9 @@ func resourcePostgreSQLDefaultPrivilegesCreate(db *DBConnection, d *schema.Resou
 		// Revoke all privileges before granting otherwise reducing privileges will not work.
 		// We just have to revoke them in the same transaction so role will not lost his privileges
 		// between revoke and grant.
-		if err = revokeRoleDefaultPrivileges(txn, d); err != nil {
-			return err
-		}
-
-		if err = grantRoleDefaultPrivileges(txn, d); err != nil {
-			return err
-		}
-		return nil
+		statements := append(revokeRoleDefaultPrivilegesStatements(d), grantRoleDefaultPrivilegesStatements(d)...)
+		_, err := execStatements(txn, statements, nil)
+		return err
 	}); err != nil {
 		return err
 	}
@@ -180,8 +275,8 @@ func resourcePostgreSQLDefaultPrivilegesDelete(db *DBConnection, d *schema.Resou
 
 	if pgSchema != "" && objectType == "schema" && !db.featureSupported(featurePrivilegesOnSchemas) {
 		return fmt.Errorf(
-			"changing default privileges for schemas is not supported for this Postgres version (%s)",
-			db.version,
+			"changing default privileges for schemas is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -197,7 +292,9 @@ func resourcePostgreSQLDefaultPrivilegesDelete(db *DBConnection, d *schema.Resou
 
 	// Needed in order to set the owner of the db if the connection user is not a superuser
 	if err := withRolesGranted(txn, []string{owner}, func() error {
-		return revokeRoleDefaultPrivileges(txn, d)
+		statements := append(revokeRoleDefaultPrivilegesStatements(d), restoreImplicitDefaultPrivilegesStatements(d)...)
+		_, err := execStatements(txn, statements, nil)
+		return err
 	}); err != nil {
 		return err
 	}
@@ -258,6 +355,24 @@ func readRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 		return fmt.Errorf("could not read default privileges: %w", err)
 	}
 
+	// Without default ACL, PUBLIC has the privileges Postgres grants by default (e.g.: EXECUTE on functions),
+	// so they are distinguished from a default ACL which revoked them.
+	if implicitPrivileges := implicitDefaultPrivileges(d); implicitPrivileges != nil {
+		var hasDefaultACL bool
+		if err := txn.QueryRow(
+			"SELECT EXISTS (SELECT 1 FROM pg_default_acl WHERE defaclobjtype = $1 AND defaclnamespace = 0 AND pg_get_userbyid(defaclrole) = $2)",
+			objectTypes[objectType], owner,
+		).Scan(&hasDefaultACL); err != nil {
+			return fmt.Errorf("could not read default ACL: %w", err)
+		}
+		if !hasDefaultACL {
+			privileges = pq.ByteaArray{}
+			for _, privilege := range implicitPrivileges {
+				privileges = append(privileges, []byte(privilege))
+			}
+		}
+	}
+
 	// We consider no privileges as "not exists" unless no privileges were provided as input
 	if len(privileges) == 0 {
 		log.Printf("[DEBUG] no default privileges for role %s in schema %s", role, pgSchema)
@@ -267,79 +382,92 @@ func readRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 		}
 	}
 
-	privilegesSet := pgArrayToSet(privileges)
+	privilegesSet := normalizeAllPrivileges(objectType, d.Get("privileges").(*schema.Set), pgArrayToSet(privileges))
 	d.Set("privileges", privilegesSet)
 	d.SetId(generateDefaultPrivilegesID(d))
 
 	return nil
 }
 
-func grantRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+// grantRoleDefaultPrivilegesStatements returns the statements altering the default privileges
+// to grant the privileges of the resource.
+func grantRoleDefaultPrivilegesStatements(d *schema.ResourceData) []string {
 	role := d.Get("role").(string)
-	pgSchema := d.Get("schema").(string)
 
-	privileges := []string{}
-	for _, priv := range d.Get("privileges").(*schema.Set).List() {
-		privileges = append(privileges, priv.(string))
-	}
+	privileges := sortedSetStrings(d.Get("privileges").(*schema.Set))
 
 	if len(privileges) == 0 {
 		log.Printf("[DEBUG] no default privileges to grant for role %s, owner %s in database: %s,", d.Get("role").(string), d.Get("owner").(string), d.Get("database").(string))
 		return nil
 	}
 
-	var inSchema string
-
-	// If a schema is specified we need to build the part of the query string to action this
-	if pgSchema != "" {
-		inSchema = fmt.Sprintf("IN SCHEMA %s", pq.QuoteIdentifier(pgSchema))
-	}
-
 	query := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s %s GRANT %s ON %sS TO %s",
 		pq.QuoteIdentifier(d.Get("owner").(string)),
-		inSchema,
+		defaultPrivilegesInSchema(d),
 		strings.Join(privileges, ","),
 		strings.ToUpper(d.Get("object_type").(string)),
-		pq.QuoteIdentifier(role),
+		quoteRoleName(role),
 	)
 
 	if d.Get("with_grant_option").(bool) {
 		query = query + " WITH GRANT OPTION"
 	}
 
-	_, err := txn.Exec(
-		query,
-	)
-	if err != nil {
-		return fmt.Errorf("could not alter default privileges: %w", err)
-	}
-
-	return nil
+	return []string{query}
 }
 
-func revokeRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
-	pgSchema := d.Get("schema").(string)
-
-	var inSchema string
-
-	// If a schema is specified we need to build the part of the query string to action this
-	if pgSchema != "" {
-		inSchema = fmt.Sprintf("IN SCHEMA %s", pq.QuoteIdentifier(pgSchema))
-	}
-	query := fmt.Sprintf(
+// revokeRoleDefaultPrivilegesStatements returns the statements altering the default privileges
+// to revoke all the privileges of the role.
+func revokeRoleDefaultPrivilegesStatements(d *schema.ResourceData) []string {
+	return []string{fmt.Sprintf(
 		"ALTER DEFAULT PRIVILEGES FOR ROLE %s %s REVOKE ALL ON %sS FROM %s",
 		pq.QuoteIdentifier(d.Get("owner").(string)),
-		inSchema,
+		defaultPrivilegesInSchema(d),
 		strings.ToUpper(d.Get("object_type").(string)),
-		pq.QuoteIdentifier(d.Get("role").(string)),
-	)
+		quoteRoleName(d.Get("role").(string)),
+	)}
+}
+
+// defaultPrivilegesInSchema returns the IN SCHEMA clause if a schema is specified.
+func defaultPrivilegesInSchema(d *schema.ResourceData) string {
+	if pgSchema := d.Get("schema").(string); pgSchema != "" {
+		return fmt.Sprintf("IN SCHEMA %s", pq.QuoteIdentifier(pgSchema))
+	}
+	return ""
+}
+
+// implicitDefaultPrivileges returns the privileges Postgres grants by default to PUBLIC on new objects
+// if no default ACL is defined, or nil if there are none for the role, schema and object type of the resource.
+// Only global default privileges (without schema) can revoke them.
+func implicitDefaultPrivileges(d *schema.ResourceData) []string {
+	if d.Get("role").(string) != publicRole || d.Get("schema").(string) != "" {
+		return nil
+	}
 
-	if _, err := txn.Exec(query); err != nil {
-		return fmt.Errorf("could not revoke default privileges: %w", err)
+	switch d.Get("object_type").(string) {
+	case "function":
+		return []string{"EXECUTE"}
+	case "type":
+		return []string{"USAGE"}
 	}
 	return nil
 }
 
+// restoreImplicitDefaultPrivilegesStatements returns the statements granting back to PUBLIC the privileges
+// Postgres grants by default, so Postgres removes the default ACL if it is back to the default.
+func restoreImplicitDefaultPrivilegesStatements(d *schema.ResourceData) []string {
+	implicitPrivileges := implicitDefaultPrivileges(d)
+	if implicitPrivileges == nil {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s GRANT %s ON %sS TO PUBLIC",
+		pq.QuoteIdentifier(d.Get("owner").(string)),
+		strings.Join(implicitPrivileges, ","),
+		strings.ToUpper(d.Get("object_type").(string)),
+	)}
+}
+
 func generateDefaultPrivilegesID(d *schema.ResourceData) string {
 	pgSchema := d.Get("schema").(string)
 	if pgSchema == "" {
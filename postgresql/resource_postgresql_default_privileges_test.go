@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccPostgresqlDefaultPrivileges(t *testing.T) {
@@ -46,7 +48,8 @@ resource "postgresql_default_privileges" "test_ro" {
 					testAccPreCheck(t)
 					testCheckCompatibleVersion(t, featurePrivileges)
 				},
-				Providers: testAccProviders,
+				Providers:    testAccProviders,
+				CheckDestroy: testAccCheckPostgresqlDefaultPrivilegesDestroy,
 				Steps: []resource.TestStep{
 					{
 						Config: fmt.Sprintf(tfConfig, `[]`),
@@ -160,7 +163,8 @@ resource "postgresql_default_privileges" "test_ro" {
 			testAccPreCheck(t)
 			testCheckCompatibleVersion(t, featurePrivileges)
 		},
-		Providers: testAccProviders,
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDefaultPrivilegesDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: stateConfig,
@@ -221,7 +225,8 @@ resource "postgresql_default_privileges" "test_ro" {
 					testAccPreCheck(t)
 					testCheckCompatibleVersion(t, featurePrivileges)
 				},
-				Providers: testAccProviders,
+				Providers:    testAccProviders,
+				CheckDestroy: testAccCheckPostgresqlDefaultPrivilegesDestroy,
 				Steps: []resource.TestStep{
 					{
 						Config: fmt.Sprintf(tfConfig, `["SELECT"]`),
@@ -298,7 +303,8 @@ resource "postgresql_default_privileges" "test_ro" {
 					testCheckCompatibleVersion(t, featurePrivileges)
 					testCheckCompatibleVersion(t, featurePrivilegesOnSchemas)
 				},
-				Providers: testAccProviders,
+				Providers:    testAccProviders,
+				CheckDestroy: testAccCheckPostgresqlDefaultPrivilegesDestroy,
 				Steps: []resource.TestStep{
 					{
 						Config: fmt.Sprintf(tfConfig, `[]`),
@@ -341,3 +347,137 @@ resource "postgresql_default_privileges" "test_ro" {
 		})
 	}
 }
+
+// Test the revocation of EXECUTE on functions which PUBLIC has by default
+func TestAccPostgresqlDefaultPrivileges_RevokePublicExecute(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	config := getTestConfig(t)
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	// We set PGUSER as owner as he will create the test function
+	tfConfig := fmt.Sprintf(`
+resource "postgresql_default_privileges" "revoke_public" {
+	database    = "%s"
+	owner       = "%s"
+	role        = "public"
+	object_type = "function"
+	privileges  = []
+}
+`, dbName, config.Username)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		CheckDestroy: func(*terraform.State) error {
+			return testCheckPublicCanExecuteNewFunction(t, dbName, true)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: tfConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_default_privileges.revoke_public", "privileges.#", "0"),
+					func(*terraform.State) error {
+						return testCheckPublicCanExecuteNewFunction(t, dbName, false)
+					},
+				),
+			},
+			{
+				// Restoring the Postgres default removes the default ACL, which is detected as a drift
+				PreConfig: func() {
+					dbExecute(t, config.connStr(dbName), fmt.Sprintf(
+						"ALTER DEFAULT PRIVILEGES FOR ROLE %s GRANT EXECUTE ON FUNCTIONS TO PUBLIC", config.Username,
+					))
+				},
+				Config:             tfConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: tfConfig,
+				Check: func(*terraform.State) error {
+					return testCheckPublicCanExecuteNewFunction(t, dbName, false)
+				},
+			},
+			{
+				ResourceName:      "postgresql_default_privileges.revoke_public",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("public|function|%s||%s", dbName, config.Username),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccCheckPostgresqlDefaultPrivilegesDestroy checks the owners no longer grant default privileges to the roles.
+var testAccCheckPostgresqlDefaultPrivilegesDestroy = testAccCheckResourcesDestroyed("postgresql_default_privileges", func(rs *terraform.ResourceState) (string, string, []interface{}) {
+	query := `
+SELECT TRUE FROM pg_catalog.pg_default_acl a, aclexplode(a.defaclacl) acl
+WHERE a.defaclrole = (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $1)
+AND a.defaclnamespace = COALESCE((SELECT oid FROM pg_catalog.pg_namespace WHERE nspname = $2), 0)
+AND acl.grantee = CASE WHEN $3::text = 'public' THEN 0::oid ELSE (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $3) END`
+	attrs := rs.Primary.Attributes
+	return attrs["database"], query, []interface{}{attrs["owner"], attrs["schema"], attrs["role"]}
+})
+
+// testCheckPublicCanExecuteNewFunction creates a function and checks if PUBLIC can execute it.
+func testCheckPublicCanExecuteNewFunction(t *testing.T, dbName string, expected bool) error {
+	config := getTestConfig(t)
+	dbExecute(t, config.connStr(dbName), "CREATE FUNCTION test_default_privileges() RETURNS integer AS 'SELECT 1' LANGUAGE SQL")
+	defer dbExecute(t, config.connStr(dbName), "DROP FUNCTION test_default_privileges()")
+
+	client := testAccProvider.Meta().(*Client)
+	txn, err := startTransaction(client, dbName)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var canExecute bool
+	if err := txn.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM pg_proc p, aclexplode(COALESCE(p.proacl, acldefault('f', p.proowner))) a ` +
+			`WHERE p.proname = 'test_default_privileges' AND a.grantee = 0 AND a.privilege_type = 'EXECUTE')`,
+	).Scan(&canExecute); err != nil {
+		return fmt.Errorf("could not read function privileges: %w", err)
+	}
+	if canExecute != expected {
+		return fmt.Errorf("expected PUBLIC to be able to execute new functions: %t, got %t", expected, canExecute)
+	}
+	return nil
+}
+
+func TestDefaultPrivilegesStatements(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLDefaultPrivileges().Schema, map[string]interface{}{
+		"role":              "public",
+		"database":          "app",
+		"owner":             "app_owner",
+		"object_type":       "function",
+		"privileges":        []interface{}{},
+		"with_grant_option": false,
+	})
+
+	statements := append(revokeRoleDefaultPrivilegesStatements(d), grantRoleDefaultPrivilegesStatements(d)...)
+	assert.Equal(t, []string{`ALTER DEFAULT PRIVILEGES FOR ROLE "app_owner"  REVOKE ALL ON FUNCTIONS FROM PUBLIC`}, statements)
+	assert.Equal(
+		t,
+		[]string{`ALTER DEFAULT PRIVILEGES FOR ROLE "app_owner" GRANT EXECUTE ON FUNCTIONS TO PUBLIC`},
+		restoreImplicitDefaultPrivilegesStatements(d),
+	)
+
+	d.Set("role", "reader")
+	d.Set("schema", "reporting")
+	d.Set("object_type", "table")
+	d.Set("privileges", []interface{}{"SELECT"})
+	d.Set("with_grant_option", true)
+	assert.Equal(t, []string{
+		`ALTER DEFAULT PRIVILEGES FOR ROLE "app_owner" IN SCHEMA "reporting" REVOKE ALL ON TABLES FROM "reader"`,
+		`ALTER DEFAULT PRIVILEGES FOR ROLE "app_owner" IN SCHEMA "reporting" GRANT SELECT ON TABLES TO "reader" WITH GRANT OPTION`,
+	}, append(revokeRoleDefaultPrivilegesStatements(d), grantRoleDefaultPrivilegesStatements(d)...))
+	assert.Nil(t, restoreImplicitDefaultPrivilegesStatements(d))
+}
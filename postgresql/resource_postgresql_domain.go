@@ -0,0 +1,554 @@
+package postgresql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	domainNameAttr                = "name"
+	domainSchemaAttr              = "schema"
+	domainDatabaseAttr            = "database"
+	domainBaseTypeAttr            = "base_type"
+	domainDefaultAttr             = "default"
+	domainNotNullAttr             = "not_null"
+	domainConstraintAttr          = "constraint"
+	domainConstraintNameAttr      = "name"
+	domainConstraintCheckAttr     = "check"
+	domainConstraintValidatedAttr = "validated"
+	domainSkipValidationAttr      = "skip_validation"
+	domainDropCascadeAttr         = "drop_cascade"
+)
+
+func resourcePostgreSQLDomain() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLDomainCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLDomainRead),
+		Update: PGResourceFunc(resourcePostgreSQLDomainUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLDomainDelete),
+		Exists: PGResourceExistsFunc(resourcePostgreSQLDomainExists),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourcePostgreSQLDomainCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			domainNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the domain",
+				ValidateFunc: validateIdentifier,
+			},
+			domainSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the domain is created",
+			},
+			domainDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the domain is created",
+			},
+			domainBaseTypeAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "The underlying data type of the domain",
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: typeDiffSuppressFunc,
+			},
+			domainDefaultAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The default value expression of the domain",
+
+				DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+			},
+			domainNotNullAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether values of the domain are prevented from being null",
+			},
+			domainConstraintAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The CHECK constraints of the domain",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						domainConstraintNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The name of the constraint",
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						domainConstraintCheckAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The boolean expression the values of the domain must satisfy (use VALUE to refer to the value being tested)",
+							ValidateFunc: validation.StringIsNotEmpty,
+
+							DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+						},
+						domainConstraintValidatedAttr: {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the constraint has been validated against the existing data",
+						},
+					},
+				},
+			},
+			domainSkipValidationAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Add new constraints as NOT VALID, i.e.: without checking the existing data using the domain",
+			},
+			domainDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the domain",
+			},
+		},
+	}
+}
+
+type domainConstraint struct {
+	name  string
+	check string
+}
+
+func (c domainConstraint) definition() string {
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s)", pq.QuoteIdentifier(c.name), c.check)
+}
+
+func getDomainConstraints(raw []interface{}) []domainConstraint {
+	constraints := make([]domainConstraint, 0, len(raw))
+	for _, r := range raw {
+		constraint := r.(map[string]interface{})
+		constraints = append(constraints, domainConstraint{
+			name:  constraint[domainConstraintNameAttr].(string),
+			check: constraint[domainConstraintCheckAttr].(string),
+		})
+	}
+	return constraints
+}
+
+func resourcePostgreSQLDomainCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	names := []interface{}{}
+	for _, constraint := range getDomainConstraints(diff.Get(domainConstraintAttr).([]interface{})) {
+		names = append(names, constraint.name)
+	}
+	if name, ok := isUniqueArr(names); !ok {
+		return fmt.Errorf("domain constraint %v is duplicated", name)
+	}
+	return nil
+}
+
+func resourcePostgreSQLDomainCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(domainSchemaAttr).(string)
+	domainName := d.Get(domainNameAttr).(string)
+
+	b := bytes.NewBufferString("CREATE DOMAIN ")
+	fmt.Fprintf(b, "%s.%s AS %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(domainName), d.Get(domainBaseTypeAttr).(string))
+
+	if v, ok := d.GetOk(domainDefaultAttr); ok {
+		fmt.Fprint(b, " DEFAULT ", v.(string))
+	}
+	if d.Get(domainNotNullAttr).(bool) {
+		fmt.Fprint(b, " NOT NULL")
+	}
+	// The domain is not used yet, so constraints are always valid at creation
+	for _, constraint := range getDomainConstraints(d.Get(domainConstraintAttr).([]interface{})) {
+		fmt.Fprint(b, " ", constraint.definition())
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("could not create domain %s: %w", domainName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating domain: %w", err)
+	}
+
+	d.SetId(generateDomainID(database, schemaName, domainName))
+
+	return resourcePostgreSQLDomainReadImpl(db, d)
+}
+
+func resourcePostgreSQLDomainExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	database, schemaName, domainName, err := getDBDomainName(d, db.client)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the database exists
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	return domainExists(txn, schemaName, domainName)
+}
+
+func resourcePostgreSQLDomainRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLDomainReadImpl(db, d)
+}
+
+func resourcePostgreSQLDomainReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, domainName, err := getDBDomainName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var baseType string
+	var defaultValue sql.NullString
+	var notNull bool
+	err = txn.QueryRow(
+		`SELECT pg_catalog.format_type(t.typbasetype, t.typtypmod), t.typdefault, t.typnotnull `+
+			`FROM pg_catalog.pg_type t JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace `+
+			`WHERE n.nspname = $1 AND t.typname = $2 AND t.typtype = 'd'`,
+		schemaName, domainName,
+	).Scan(&baseType, &defaultValue, &notNull)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL domain (%s.%s) not found in database %s", schemaName, domainName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading domain: %w", err)
+	}
+
+	constraints, err := readDomainConstraints(txn, schemaName, domainName, baseType, d.Get(domainConstraintAttr).([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	// Postgres rewrites the default expression (e.g.: 'foo' becomes 'foo'::text),
+	// so the configured one is kept if Postgres stores it as the catalog one.
+	defaultExpression := defaultValue.String
+	if configured := d.Get(domainDefaultAttr).(string); configured != "" && defaultValue.Valid {
+		equivalent, err := equivalentSQLExpressions(txn, "", domainDefaultCast(configured, baseType), domainDefaultCast(defaultValue.String, baseType))
+		if err != nil {
+			return fmt.Errorf("Error reading domain: %w", err)
+		}
+		if equivalent {
+			defaultExpression = configured
+		}
+	}
+	d.Set(domainDefaultAttr, defaultExpression)
+
+	d.Set(domainNameAttr, domainName)
+	d.Set(domainSchemaAttr, schemaName)
+	d.Set(domainDatabaseAttr, database)
+	d.Set(domainBaseTypeAttr, baseType)
+	d.Set(domainNotNullAttr, notNull)
+	d.Set(domainConstraintAttr, constraints)
+	d.SetId(generateDomainID(database, schemaName, domainName))
+
+	return nil
+}
+
+func resourcePostgreSQLDomainUpdate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(domainSchemaAttr).(string)
+	domainName := d.Get(domainNameAttr).(string)
+	alterDomain := fmt.Sprintf("ALTER DOMAIN %s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(domainName))
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if d.HasChange(domainDefaultAttr) {
+		sql := alterDomain + " DROP DEFAULT"
+		if v := d.Get(domainDefaultAttr).(string); v != "" {
+			sql = fmt.Sprintf("%s SET DEFAULT %s", alterDomain, v)
+		}
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not update default of domain %s: %w", domainName, err)
+		}
+	}
+
+	if d.HasChange(domainNotNullAttr) {
+		sql := alterDomain + " DROP NOT NULL"
+		if d.Get(domainNotNullAttr).(bool) {
+			sql = alterDomain + " SET NOT NULL"
+		}
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not update not null constraint of domain %s: %w", domainName, err)
+		}
+	}
+
+	if err := setDomainConstraints(txn, d, schemaName, domainName); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating domain: %w", err)
+	}
+
+	return resourcePostgreSQLDomainReadImpl(db, d)
+}
+
+// setDomainConstraints drops the removed or modified constraints and adds the new ones.
+// Unless skip_validation is set, the constraints not yet validated are then validated.
+func setDomainConstraints(txn *sql.Tx, d *schema.ResourceData, schemaName, domainName string) error {
+	alterDomain := fmt.Sprintf("ALTER DOMAIN %s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(domainName))
+	skipValidation := d.Get(domainSkipValidationAttr).(bool)
+
+	oraw, nraw := d.GetChange(domainConstraintAttr)
+	oldConstraints := getDomainConstraints(oraw.([]interface{}))
+	newConstraints := getDomainConstraints(nraw.([]interface{}))
+
+	oldByName := make(map[string]domainConstraint, len(oldConstraints))
+	for _, constraint := range oldConstraints {
+		oldByName[constraint.name] = constraint
+	}
+	newByName := make(map[string]domainConstraint, len(newConstraints))
+	for _, constraint := range newConstraints {
+		newByName[constraint.name] = constraint
+	}
+
+	for _, constraint := range oldConstraints {
+		if newConstraint, ok := newByName[constraint.name]; ok && normalizeSQLExpression(newConstraint.check) == normalizeSQLExpression(constraint.check) {
+			continue
+		}
+		sql := fmt.Sprintf("%s DROP CONSTRAINT %s", alterDomain, pq.QuoteIdentifier(constraint.name))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not drop constraint %s of domain: %w", constraint.name, err)
+		}
+	}
+
+	for _, constraint := range newConstraints {
+		if oldConstraint, ok := oldByName[constraint.name]; ok && normalizeSQLExpression(oldConstraint.check) == normalizeSQLExpression(constraint.check) {
+			continue
+		}
+		sql := fmt.Sprintf("%s ADD %s", alterDomain, constraint.definition())
+		if skipValidation {
+			sql += " NOT VALID"
+		}
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not add constraint %s to domain: %w", constraint.name, err)
+		}
+	}
+
+	if skipValidation {
+		return nil
+	}
+
+	// Constraints previously added with skip_validation need to be validated
+	rows, err := txn.Query(
+		`SELECT c.conname FROM pg_catalog.pg_constraint c `+
+			`JOIN pg_catalog.pg_type t ON t.oid = c.contypid `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace `+
+			`WHERE n.nspname = $1 AND t.typname = $2 AND c.contype = 'c' AND NOT c.convalidated`,
+		schemaName, domainName,
+	)
+	if err != nil {
+		return fmt.Errorf("could not read constraints of domain %s: %w", domainName, err)
+	}
+	defer rows.Close()
+
+	notValidated := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("could not scan constraint of domain %s: %w", domainName, err)
+		}
+		notValidated = append(notValidated, name)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, name := range notValidated {
+		sql := fmt.Sprintf("%s VALIDATE CONSTRAINT %s", alterDomain, pq.QuoteIdentifier(name))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not validate constraint %s of domain: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLDomainDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(domainSchemaAttr).(string)
+	domainName := d.Get(domainNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	dropMode := "RESTRICT"
+	if d.Get(domainDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	}
+
+	sql := fmt.Sprintf("DROP DOMAIN %s.%s %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(domainName), dropMode)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop domain: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting domain: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// readDomainConstraints reads the CHECK constraints of a domain.
+// As Postgres rewrites the check expressions, the expression of the state is kept for existing constraints
+// if Postgres stores it as the catalog one, and they are returned in the state order.
+// Constraints unknown to the state are appended sorted by name.
+func readDomainConstraints(txn *sql.Tx, schemaName, domainName, baseType string, stateConstraints []interface{}) ([]map[string]interface{}, error) {
+	query := `SELECT c.conname, pg_catalog.pg_get_constraintdef(c.oid), c.convalidated ` +
+		`FROM pg_catalog.pg_constraint c ` +
+		`JOIN pg_catalog.pg_type t ON t.oid = c.contypid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace ` +
+		`WHERE n.nspname = $1 AND t.typname = $2 AND c.contype = 'c' ` +
+		`ORDER BY c.conname`
+	rows, err := txn.Query(query, schemaName, domainName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read constraints of domain %s: %w", domainName, err)
+	}
+	defer rows.Close()
+
+	constraints := map[string]map[string]interface{}{}
+	for rows.Next() {
+		var name, definition string
+		var validated bool
+		if err := rows.Scan(&name, &definition, &validated); err != nil {
+			return nil, fmt.Errorf("could not scan constraint of domain %s: %w", domainName, err)
+		}
+		constraints[name] = map[string]interface{}{
+			domainConstraintNameAttr:      name,
+			domainConstraintCheckAttr:     parseDomainCheckDefinition(definition),
+			domainConstraintValidatedAttr: validated,
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(constraints))
+	for _, constraint := range getDomainConstraints(stateConstraints) {
+		if c, ok := constraints[constraint.name]; ok {
+			// VALUE is deparsed as a column of the base type
+			from := fmt.Sprintf("(SELECT NULL::%s AS value) AS domain_value", baseType)
+			check, err := configuredSQLExpression(txn, from, constraint.check, c[domainConstraintCheckAttr].(string))
+			if err != nil {
+				return nil, fmt.Errorf("could not read constraint %s of domain %s: %w", constraint.name, domainName, err)
+			}
+			c[domainConstraintCheckAttr] = check
+			result = append(result, c)
+			delete(constraints, constraint.name)
+		}
+	}
+
+	names := make([]string, 0, len(constraints))
+	for name := range constraints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result = append(result, constraints[name])
+	}
+
+	return result, nil
+}
+
+// parseDomainCheckDefinition extracts the check expression from a constraint definition
+// (e.g.: CHECK ((VALUE > 0)) NOT VALID => (VALUE > 0))
+// domainDefaultCast casts the default expression to the base type of the domain, as Postgres does when storing it.
+func domainDefaultCast(expression, baseType string) string {
+	return fmt.Sprintf("(%s)::%s", expression, baseType)
+}
+
+func parseDomainCheckDefinition(definition string) string {
+	definition = strings.TrimSuffix(definition, " NOT VALID")
+	definition = strings.TrimPrefix(definition, "CHECK (")
+	return strings.TrimSuffix(definition, ")")
+}
+
+func domainExists(txn *sql.Tx, schemaName, domainName string) (bool, error) {
+	var _rez bool
+	err := txn.QueryRow(
+		`SELECT TRUE FROM pg_catalog.pg_type t JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace `+
+			`WHERE n.nspname = $1 AND t.typname = $2 AND t.typtype = 'd'`,
+		schemaName, domainName,
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if domain exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func generateDomainID(database, schemaName, domainName string) string {
+	return joinResourceID(database, schemaName, domainName)
+}
+
+// getDBDomainName returns database, schema and domain name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBDomainName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(domainSchemaAttr).(string)
+	domainName := d.Get(domainNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and domain names.
+	if domainName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("domain ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		domainName = parsed[2]
+	}
+	return database, schemaName, domainName, nil
+}
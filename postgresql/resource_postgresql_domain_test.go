@@ -0,0 +1,182 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseDomainCheckDefinition(t *testing.T) {
+	var tests = []struct {
+		definition string
+		want       string
+	}{
+		{"CHECK ((VALUE > 0))", "(VALUE > 0)"},
+		{"CHECK ((VALUE > 0)) NOT VALID", "(VALUE > 0)"},
+		{"CHECK ((VALUE ~ '^[a-z]+$'::text))", "(VALUE ~ '^[a-z]+$'::text)"},
+	}
+
+	for _, test := range tests {
+		if got := parseDomainCheckDefinition(test.definition); got != test.want {
+			t.Errorf("parseDomainCheckDefinition(%q) returned %q, want %q", test.definition, got, test.want)
+		}
+	}
+}
+
+func TestAccPostgresqlDomain_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_domain" "test" {
+  database  = "%s"
+  schema    = "test_schema"
+  name      = "test_domain"
+  base_type = "int4"
+  default   = "1"
+
+  constraint {
+    name  = "positive"
+    check = "VALUE > 0"
+  }
+}
+`, dbName)
+
+	configUpdated := fmt.Sprintf(`
+resource "postgresql_domain" "test" {
+  database        = "%s"
+  schema          = "test_schema"
+  name            = "test_domain"
+  base_type       = "integer"
+  not_null        = true
+  skip_validation = true
+
+  constraint {
+    name  = "positive"
+    check = "VALUE > 0"
+  }
+  constraint {
+    name  = "small"
+    check = "VALUE < 1000"
+  }
+}
+`, dbName)
+
+	configValidated := fmt.Sprintf(`
+resource "postgresql_domain" "test" {
+  database  = "%s"
+  schema    = "test_schema"
+  name      = "test_domain"
+  base_type = "integer"
+  not_null  = true
+
+  constraint {
+    name  = "small"
+    check = "VALUE < 1000"
+  }
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDomainDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDomainExists("postgresql_domain.test"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "base_type", "integer"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "default", "1"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "not_null", "false"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "constraint.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "constraint.0.validated", "true"),
+				),
+			},
+			{
+				Config: configUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDomainExists("postgresql_domain.test"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "default", ""),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "not_null", "true"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "constraint.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "constraint.1.name", "small"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "constraint.1.validated", "false"),
+				),
+			},
+			{
+				Config: configValidated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDomainExists("postgresql_domain.test"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "constraint.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "constraint.0.name", "small"),
+					resource.TestCheckResourceAttr("postgresql_domain.test", "constraint.0.validated", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlDomainDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_domain" {
+			continue
+		}
+
+		txn, err := startTransaction(client, rs.Primary.Attributes[domainDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := domainExists(txn, rs.Primary.Attributes[domainSchemaAttr], rs.Primary.Attributes[domainNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking domain %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Domain still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlDomainExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[domainDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := domainExists(txn, rs.Primary.Attributes[domainSchemaAttr], rs.Primary.Attributes[domainNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking domain %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Domain not found")
+		}
+
+		return nil
+	}
+}
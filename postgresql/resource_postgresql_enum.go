@@ -0,0 +1,440 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	enumNameAttr          = "name"
+	enumSchemaAttr        = "schema"
+	enumDatabaseAttr      = "database"
+	enumValuesAttr        = "values"
+	enumForceRecreateAttr = "force_recreate"
+	enumDropCascadeAttr   = "drop_cascade"
+)
+
+func resourcePostgreSQLEnum() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLEnumCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLEnumRead),
+		Update: PGResourceFunc(resourcePostgreSQLEnumUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLEnumDelete),
+		Exists: PGResourceExistsFunc(resourcePostgreSQLEnumExists),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourcePostgreSQLEnumCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			enumNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the enum type",
+				ValidateFunc: validateIdentifier,
+			},
+			enumSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the enum type is created",
+			},
+			enumDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the enum type is created",
+			},
+			enumValuesAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The ordered list of values of the enum type",
+			},
+			enumForceRecreateAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Recreate the enum type if values are removed or reordered (the type must not be used)",
+			},
+			enumDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the enum type",
+			},
+		},
+	}
+}
+
+// resourcePostgreSQLEnumCustomizeDiff checks that the values of the enum are only added, as Postgres
+// does not support removing or reordering values. In this case the type needs to be recreated, which
+// we only do if force_recreate is set.
+func resourcePostgreSQLEnumCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	values := diff.Get(enumValuesAttr).([]interface{})
+	if value, ok := isUniqueArr(values); !ok {
+		return fmt.Errorf("enum value %v is duplicated", value)
+	}
+
+	if diff.Id() == "" || !diff.HasChange(enumValuesAttr) {
+		return nil
+	}
+
+	oraw, nraw := diff.GetChange(enumValuesAttr)
+	if _, err := enumValuesToAdd(interfaceSliceToStrings(oraw.([]interface{})), interfaceSliceToStrings(nraw.([]interface{}))); err != nil {
+		if diff.Get(enumForceRecreateAttr).(bool) {
+			return diff.ForceNew(enumValuesAttr)
+		}
+		return fmt.Errorf(
+			"%w. Postgres does not support removing or reordering the values of an enum type, "+
+				"set `force_recreate = true` to drop and recreate the type (it must not be used by any column)",
+			err,
+		)
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLEnumCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(enumSchemaAttr).(string)
+	enumName := d.Get(enumNameAttr).(string)
+
+	values := []string{}
+	for _, value := range d.Get(enumValuesAttr).([]interface{}) {
+		values = append(values, pq.QuoteLiteral(value.(string)))
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	sql := fmt.Sprintf("CREATE TYPE %s.%s AS ENUM (%s)",
+		pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(enumName), strings.Join(values, ", "),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not create enum type %s: %w", enumName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating enum type: %w", err)
+	}
+
+	d.SetId(generateEnumID(database, schemaName, enumName))
+
+	return resourcePostgreSQLEnumReadImpl(db, d)
+}
+
+func resourcePostgreSQLEnumExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	database, schemaName, enumName, err := getDBEnumName(d, db.client)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the database exists
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	return enumExists(txn, schemaName, enumName)
+}
+
+func resourcePostgreSQLEnumRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLEnumReadImpl(db, d)
+}
+
+func resourcePostgreSQLEnumReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, enumName, err := getDBEnumName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	exists, err := enumExists(txn, schemaName, enumName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL enum type (%s.%s) not found in database %s", schemaName, enumName, database)
+		d.SetId("")
+		return nil
+	}
+
+	values, err := getEnumValues(txn, schemaName, enumName)
+	if err != nil {
+		return err
+	}
+
+	d.Set(enumNameAttr, enumName)
+	d.Set(enumSchemaAttr, schemaName)
+	d.Set(enumDatabaseAttr, database)
+	d.Set(enumValuesAttr, values)
+	d.SetId(generateEnumID(database, schemaName, enumName))
+
+	return nil
+}
+
+func resourcePostgreSQLEnumUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(enumValuesAttr) {
+		return resourcePostgreSQLEnumReadImpl(db, d)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(enumSchemaAttr).(string)
+	enumName := d.Get(enumNameAttr).(string)
+
+	oraw, nraw := d.GetChange(enumValuesAttr)
+	additions, err := enumValuesToAdd(interfaceSliceToStrings(oraw.([]interface{})), interfaceSliceToStrings(nraw.([]interface{})))
+	if err != nil {
+		return err
+	}
+
+	queries := make([]string, 0, len(additions))
+	for _, addition := range additions {
+		queries = append(queries, addition.query(schemaName, enumName))
+	}
+
+	if db.featureSupported(featureEnumAddValueInTransaction) {
+		txn, err := startTransaction(db.client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		for _, query := range queries {
+			if _, err := txn.Exec(query); err != nil {
+				return fmt.Errorf("could not add value to enum type %s: %w", enumName, err)
+			}
+		}
+
+		if err = txn.Commit(); err != nil {
+			return fmt.Errorf("Error updating enum type: %w", err)
+		}
+	} else {
+		// ALTER TYPE ... ADD VALUE cannot be executed inside a transaction block before Postgres 12
+		conn, err := connectDatabase(db.client, database)
+		if err != nil {
+			return err
+		}
+
+		for _, query := range queries {
+			if _, err := conn.Exec(query); err != nil {
+				return fmt.Errorf("could not add value to enum type %s: %w", enumName, err)
+			}
+		}
+	}
+
+	return resourcePostgreSQLEnumReadImpl(db, d)
+}
+
+func resourcePostgreSQLEnumDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(enumSchemaAttr).(string)
+	enumName := d.Get(enumNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	dropMode := "RESTRICT"
+	if d.Get(enumDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	} else {
+		columns, err := getEnumColumns(txn, schemaName, enumName)
+		if err != nil {
+			return err
+		}
+		if len(columns) > 0 {
+			return fmt.Errorf(
+				"could not drop enum type %s.%s as it is used by the columns: %s",
+				schemaName, enumName, strings.Join(columns, ", "),
+			)
+		}
+	}
+
+	sql := fmt.Sprintf("DROP TYPE %s.%s %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(enumName), dropMode)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop enum type: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting enum type: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+type enumValueAddition struct {
+	value  string
+	before string
+	after  string
+}
+
+func (a enumValueAddition) query(schemaName, enumName string) string {
+	query := fmt.Sprintf("ALTER TYPE %s.%s ADD VALUE %s",
+		pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(enumName), pq.QuoteLiteral(a.value),
+	)
+
+	switch {
+	case a.before != "":
+		query += " BEFORE " + pq.QuoteLiteral(a.before)
+	case a.after != "":
+		query += " AFTER " + pq.QuoteLiteral(a.after)
+	}
+
+	return query
+}
+
+// enumValuesToAdd returns the values to add to an enum type to go from the old values to the new ones,
+// with their position. It returns an error if values have been removed or reordered,
+// as Postgres only supports adding values to an enum type.
+func enumValuesToAdd(oldValues, newValues []string) ([]enumValueAddition, error) {
+	additions := []enumValueAddition{}
+
+	i := 0
+	for j, value := range newValues {
+		if i < len(oldValues) && value == oldValues[i] {
+			i++
+			continue
+		}
+
+		if sliceContainsStr(oldValues, value) {
+			return nil, fmt.Errorf("enum value %s has been reordered", value)
+		}
+
+		addition := enumValueAddition{value: value}
+		switch {
+		case i < len(oldValues):
+			addition.before = oldValues[i]
+		case j > 0:
+			addition.after = newValues[j-1]
+		}
+		additions = append(additions, addition)
+	}
+
+	if i < len(oldValues) {
+		return nil, fmt.Errorf("enum value %s has been removed", oldValues[i])
+	}
+
+	return additions, nil
+}
+
+func getEnumValues(txn *sql.Tx, schemaName, enumName string) ([]string, error) {
+	query := `SELECT e.enumlabel FROM pg_catalog.pg_enum e ` +
+		`JOIN pg_catalog.pg_type t ON t.oid = e.enumtypid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace ` +
+		`WHERE n.nspname = $1 AND t.typname = $2 ORDER BY e.enumsortorder`
+	rows, err := txn.Query(query, schemaName, enumName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read values of enum type %s: %w", enumName, err)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("could not scan value of enum type %s: %w", enumName, err)
+		}
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
+
+// getEnumColumns returns the table columns using the enum type (or an array of it).
+func getEnumColumns(txn *sql.Tx, schemaName, enumName string) ([]string, error) {
+	query := `SELECT cn.nspname || '.' || c.relname || '.' || a.attname ` +
+		`FROM pg_catalog.pg_attribute a ` +
+		`JOIN pg_catalog.pg_class c ON c.oid = a.attrelid ` +
+		`JOIN pg_catalog.pg_namespace cn ON cn.oid = c.relnamespace ` +
+		`JOIN pg_catalog.pg_type t ON t.oid = a.atttypid OR t.typarray = a.atttypid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace ` +
+		`WHERE n.nspname = $1 AND t.typname = $2 AND a.attnum > 0 AND NOT a.attisdropped ` +
+		`ORDER BY 1`
+	rows, err := txn.Query(query, schemaName, enumName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read columns using enum type %s: %w", enumName, err)
+	}
+	defer rows.Close()
+
+	columns := []string{}
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("could not scan column using enum type %s: %w", enumName, err)
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
+
+func enumExists(txn *sql.Tx, schemaName, enumName string) (bool, error) {
+	var _rez bool
+	err := txn.QueryRow(
+		`SELECT TRUE FROM pg_catalog.pg_type t JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace `+
+			`WHERE n.nspname = $1 AND t.typname = $2 AND t.typtype = 'e'`,
+		schemaName, enumName,
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if enum type exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func generateEnumID(database, schemaName, enumName string) string {
+	return joinResourceID(database, schemaName, enumName)
+}
+
+// getDBEnumName returns database, schema and enum type name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBEnumName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(enumSchemaAttr).(string)
+	enumName := d.Get(enumNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and enum names.
+	if enumName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("enum ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		enumName = parsed[2]
+	}
+	return database, schemaName, enumName, nil
+}
@@ -0,0 +1,153 @@
+package postgresql
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestEnumValuesToAdd(t *testing.T) {
+	var tests = []struct {
+		oldValues []string
+		newValues []string
+		want      []enumValueAddition
+		wantErr   bool
+	}{
+		{[]string{"a", "b"}, []string{"a", "b"}, []enumValueAddition{}, false},
+		{[]string{"a", "b"}, []string{"a", "b", "c", "d"}, []enumValueAddition{{value: "c", after: "b"}, {value: "d", after: "c"}}, false},
+		{[]string{"a", "b"}, []string{"z", "a", "b"}, []enumValueAddition{{value: "z", before: "a"}}, false},
+		{[]string{"a", "b"}, []string{"a", "x", "y", "b"}, []enumValueAddition{{value: "x", before: "b"}, {value: "y", before: "b"}}, false},
+		{[]string{}, []string{"a"}, []enumValueAddition{{value: "a"}}, false},
+		{[]string{"a", "b"}, []string{"a"}, nil, true},
+		{[]string{"a", "b"}, []string{"b", "a"}, nil, true},
+	}
+
+	for _, test := range tests {
+		additions, err := enumValuesToAdd(test.oldValues, test.newValues)
+		if (err != nil) != test.wantErr {
+			t.Errorf("enumValuesToAdd(%v, %v) returned error %v, want error: %t", test.oldValues, test.newValues, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !reflect.DeepEqual(additions, test.want) {
+			t.Errorf("enumValuesToAdd(%v, %v) returned %#v, want %#v", test.oldValues, test.newValues, additions, test.want)
+		}
+	}
+}
+
+func TestAccPostgresqlEnum_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_enum" "test" {
+  database       = "%s"
+  schema         = "test_schema"
+  name           = "test_enum"
+  values         = [%s]
+  force_recreate = %t
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlEnumDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, `"b", "d"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlEnumExists("postgresql_enum.test"),
+					resource.TestCheckResourceAttr("postgresql_enum.test", "values.#", "2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, `"a", "b", "c", "d", "e"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlEnumExists("postgresql_enum.test"),
+					resource.TestCheckResourceAttr("postgresql_enum.test", "values.#", "5"),
+					resource.TestCheckResourceAttr("postgresql_enum.test", "values.0", "a"),
+					resource.TestCheckResourceAttr("postgresql_enum.test", "values.2", "c"),
+					resource.TestCheckResourceAttr("postgresql_enum.test", "values.4", "e"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(config, dbName, `"a", "c", "b", "d", "e"`, false),
+				ExpectError: regexp.MustCompile("does not support removing or reordering the values"),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, `"a", "e"`, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlEnumExists("postgresql_enum.test"),
+					resource.TestCheckResourceAttr("postgresql_enum.test", "values.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_enum.test", "values.1", "e"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlEnumDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_enum" {
+			continue
+		}
+
+		txn, err := startTransaction(client, rs.Primary.Attributes[enumDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := enumExists(txn, rs.Primary.Attributes[enumSchemaAttr], rs.Primary.Attributes[enumNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking enum type %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Enum type still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlEnumExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[enumDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := enumExists(txn, rs.Primary.Attributes[enumSchemaAttr], rs.Primary.Attributes[enumNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking enum type %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Enum type not found")
+		}
+
+		return nil
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/lib/pq"
@@ -22,21 +21,24 @@ const (
 )
 
 func resourcePostgreSQLExtension() *schema.Resource {
-	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLExtensionCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLExtensionRead),
-		Update: PGResourceFunc(resourcePostgreSQLExtensionUpdate),
-		Delete: PGResourceFunc(resourcePostgreSQLExtensionDelete),
-		Exists: PGResourceExistsFunc(resourcePostgreSQLExtensionExists),
+	resource := &schema.Resource{
+		// Version 1 escapes the dots of the names in the ID
+		SchemaVersion: 1,
+		Create:        PGResourceFunc(resourcePostgreSQLExtensionCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLExtensionRead),
+		Update:        PGResourceFunc(resourcePostgreSQLExtensionUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLExtensionDelete),
+		Exists:        PGResourceExistsFunc(resourcePostgreSQLExtensionExists),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
 			extNameAttr: {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
 			},
 			extSchemaAttr: {
 				Type:        schema.TypeString,
@@ -69,46 +71,47 @@ func resourcePostgreSQLExtension() *schema.Resource {
 				Default:     false,
 				Description: "When true, will also create any extensions that this extension depends on that are not already installed",
 			},
+			adoptExistingAttr: adoptExistingSchema(),
 		},
 	}
+	resource.StateUpgraders = []schema.StateUpgrader{
+		resourceIDStateUpgrader(resource, 0, func(rawState map[string]interface{}) string {
+			return databaseObjectIDFromRawState(rawState, extDatabaseAttr, extNameAttr)
+		}),
+	}
+
+	return resource
 }
 
 func resourcePostgreSQLExtensionCreate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureExtension) {
 		return fmt.Errorf(
-			"postgresql_extension resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_extension resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
-	extName := d.Get(extNameAttr).(string)
 	databaseName := getDatabaseForExtension(d, db.client.databaseName)
 
-	b := bytes.NewBufferString("CREATE EXTENSION IF NOT EXISTS ")
-	fmt.Fprint(b, pq.QuoteIdentifier(extName))
-
-	if v, ok := d.GetOk(extSchemaAttr); ok {
-		fmt.Fprint(b, " SCHEMA ", pq.QuoteIdentifier(v.(string)))
-	}
-
-	if v, ok := d.GetOk(extVersionAttr); ok {
-		fmt.Fprint(b, " VERSION ", pq.QuoteIdentifier(v.(string)))
-	}
-
-	if d.Get(extCreateCascadeAttr).(bool) {
-		fmt.Fprint(b, " CASCADE")
-	}
-
 	txn, err := startTransaction(db.client, databaseName)
 	if err != nil {
 		return err
 	}
 	defer deferredRollback(txn)
 
-	sql := b.String()
-	if _, err := txn.Exec(sql); err != nil {
+	adopted, err := createOrAdopt(
+		txn, adoptExisting(d, true), "postgresql_extension",
+		generateExtensionID(d, databaseName), createExtensionQuery(d, false),
+	)
+	if err != nil {
 		return err
 	}
+	if adopted {
+		// The existing extension may have another schema or version
+		if err := reconcileExtension(txn, d); err != nil {
+			return err
+		}
+	}
 
 	if err = txn.Commit(); err != nil {
 		return fmt.Errorf("Error creating extension: %w", err)
@@ -119,11 +122,35 @@ func resourcePostgreSQLExtensionCreate(db *DBConnection, d *schema.ResourceData)
 	return resourcePostgreSQLExtensionReadImpl(db, d)
 }
 
+// createExtensionQuery returns the CREATE EXTENSION statement of the extension configured in d,
+// doing nothing if the extension already exists when ifNotExists is set.
+func createExtensionQuery(d *schema.ResourceData, ifNotExists bool) string {
+	b := bytes.NewBufferString("CREATE EXTENSION ")
+	if ifNotExists {
+		fmt.Fprint(b, "IF NOT EXISTS ")
+	}
+	fmt.Fprint(b, pq.QuoteIdentifier(d.Get(extNameAttr).(string)))
+
+	if v, ok := d.GetOk(extSchemaAttr); ok {
+		fmt.Fprint(b, " SCHEMA ", pq.QuoteIdentifier(v.(string)))
+	}
+
+	if v, ok := d.GetOk(extVersionAttr); ok {
+		fmt.Fprint(b, " VERSION ", pq.QuoteIdentifier(v.(string)))
+	}
+
+	if d.Get(extCreateCascadeAttr).(bool) {
+		fmt.Fprint(b, " CASCADE")
+	}
+
+	return b.String()
+}
+
 func resourcePostgreSQLExtensionExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
 	if !db.featureSupported(featureExtension) {
 		return false, fmt.Errorf(
-			"postgresql_extension resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_extension resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -161,8 +188,8 @@ func resourcePostgreSQLExtensionExists(db *DBConnection, d *schema.ResourceData)
 func resourcePostgreSQLExtensionRead(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureExtension) {
 		return fmt.Errorf(
-			"postgresql_extension resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_extension resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -207,8 +234,8 @@ func resourcePostgreSQLExtensionReadImpl(db *DBConnection, d *schema.ResourceDat
 func resourcePostgreSQLExtensionDelete(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureExtension) {
 		return fmt.Errorf(
-			"postgresql_extension resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_extension resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -243,8 +270,8 @@ func resourcePostgreSQLExtensionDelete(db *DBConnection, d *schema.ResourceData)
 func resourcePostgreSQLExtensionUpdate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureExtension) {
 		return fmt.Errorf(
-			"postgresql_extension resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_extension resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -326,14 +353,11 @@ func getDatabaseForExtension(d *schema.ResourceData, databaseName string) string
 }
 
 func generateExtensionID(d *schema.ResourceData, databaseName string) string {
-	return strings.Join([]string{
-		databaseName,
-		d.Get(extNameAttr).(string),
-	}, ".")
+	return joinResourceID(databaseName, d.Get(extNameAttr).(string))
 }
 
 func getExtensionNameFromID(ID string) string {
-	splitted := strings.Split(ID, ".")
+	splitted := splitResourceID(ID)
 	return splitted[0]
 }
 
@@ -346,7 +370,7 @@ func getDBExtName(d *schema.ResourceData, client *Client) (string, string, error
 
 	// When importing, we have to parse the ID to find extension and database names.
 	if extName == "" {
-		parsed := strings.Split(d.Id(), ".")
+		parsed := splitResourceID(d.Id())
 		if len(parsed) != 2 {
 			return "", "", fmt.Errorf("extension ID %s has not the expected format 'database.extension': %v", d.Id(), parsed)
 		}
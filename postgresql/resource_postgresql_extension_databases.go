@@ -0,0 +1,363 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	extDatabasesAttr = "databases"
+
+	extDatabasesPatternMatchingTarget = "datname"
+)
+
+func resourcePostgreSQLExtensionDatabases() *schema.Resource {
+	return &schema.Resource{
+		Create:        PGResourceFunc(resourcePostgreSQLExtensionDatabasesCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLExtensionDatabasesRead),
+		Update:        PGResourceFunc(resourcePostgreSQLExtensionDatabasesUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLExtensionDatabasesDelete),
+		CustomizeDiff: resourcePostgreSQLExtensionDatabasesCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			extNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the extension to install in the databases",
+			},
+			extSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Sets the schema of the extension in every database",
+			},
+			extVersionAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Sets the version number of the extension in every database",
+			},
+			"like_any_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Expression(s) which will be pattern matched against database names using the PostgreSQL LIKE ANY operator",
+			},
+			"like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Expression(s) which will be pattern matched against database names using the PostgreSQL LIKE ALL operator",
+			},
+			"not_like_all_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Expression(s) which will be pattern matched against database names using the PostgreSQL NOT LIKE ALL operator",
+			},
+			"regex_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Expression which will be pattern matched against database names using the PostgreSQL ~ (regular expression match) operator",
+			},
+			extDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the extension, and in turn all objects that depend on those objects",
+			},
+			extCreateCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also create any extensions that this extension depends on that are not already installed",
+			},
+			extDatabasesAttr: {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The databases matching the patterns in which the extension is installed",
+			},
+		},
+	}
+}
+
+// resourcePostgreSQLExtensionDatabasesCustomizeDiff plans the installation of the extension in the databases
+// matching the patterns where it is missing, including the databases created since the last apply.
+func resourcePostgreSQLExtensionDatabasesCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	db, err := meta.(*Client).Connect()
+	if err != nil {
+		return err
+	}
+
+	databases, err := listExtensionDatabases(db, diff)
+	if err != nil {
+		return err
+	}
+
+	old, _ := diff.GetChange(extDatabasesAttr)
+	if !old.(*schema.Set).Equal(stringSliceToSet(databases)) {
+		return diff.SetNew(extDatabasesAttr, databases)
+	}
+	return nil
+}
+
+func resourcePostgreSQLExtensionDatabasesCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureExtension) {
+		return fmt.Errorf(
+			"postgresql_extension_databases resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	if err := installExtensionInDatabases(db, d); err != nil {
+		return err
+	}
+
+	d.SetId(d.Get(extNameAttr).(string))
+
+	return resourcePostgreSQLExtensionDatabasesReadImpl(db, d)
+}
+
+func resourcePostgreSQLExtensionDatabasesRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureExtension) {
+		return fmt.Errorf(
+			"postgresql_extension_databases resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	return resourcePostgreSQLExtensionDatabasesReadImpl(db, d)
+}
+
+// resourcePostgreSQLExtensionDatabasesReadImpl sets the databases matching the patterns in which the extension
+// is installed with the configured schema and version, the other ones are reconciled by the next apply.
+func resourcePostgreSQLExtensionDatabasesReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	extName := d.Get(extNameAttr).(string)
+
+	databases, err := listExtensionDatabases(db, d)
+	if err != nil {
+		return err
+	}
+
+	installed := []string{}
+	for _, database := range databases {
+		extSchema, extVersion, err := readExtensionInDatabase(db, database, extName)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case extVersion == "":
+			log.Printf("[WARN] PostgreSQL extension (%s) not found for database %s", extName, database)
+		case d.Get(extSchemaAttr).(string) != "" && d.Get(extSchemaAttr).(string) != extSchema:
+			log.Printf("[WARN] PostgreSQL extension (%s) is in schema %s of database %s", extName, extSchema, database)
+		case d.Get(extVersionAttr).(string) != "" && d.Get(extVersionAttr).(string) != extVersion:
+			log.Printf("[WARN] PostgreSQL extension (%s) has version %s in database %s", extName, extVersion, database)
+		default:
+			installed = append(installed, database)
+		}
+	}
+
+	d.Set(extDatabasesAttr, installed)
+
+	return nil
+}
+
+func resourcePostgreSQLExtensionDatabasesUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureExtension) {
+		return fmt.Errorf(
+			"postgresql_extension_databases resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	if err := installExtensionInDatabases(db, d); err != nil {
+		return err
+	}
+
+	return resourcePostgreSQLExtensionDatabasesReadImpl(db, d)
+}
+
+func resourcePostgreSQLExtensionDatabasesDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureExtension) {
+		return fmt.Errorf(
+			"postgresql_extension_databases resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	extName := d.Get(extNameAttr).(string)
+
+	dropMode := "RESTRICT"
+	if d.Get(extDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	}
+
+	for _, database := range d.Get(extDatabasesAttr).(*schema.Set).List() {
+		if err := dropDatabaseExtension(db, database.(string), extName, dropMode); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func dropDatabaseExtension(db *DBConnection, database, extName, dropMode string) error {
+	// The database may have been dropped since the last apply
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	sql := fmt.Sprintf("DROP EXTENSION IF EXISTS %s %s", pq.QuoteIdentifier(extName), dropMode)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop extension %s in database %s: %w", extName, database, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting extension in database %s: %w", database, err)
+	}
+
+	return nil
+}
+
+// installExtensionInDatabases creates the extension in the databases matching the patterns
+// and sets its schema and version where they differ, each database in its own transaction.
+func installExtensionInDatabases(db *DBConnection, d *schema.ResourceData) error {
+	extName := d.Get(extNameAttr).(string)
+
+	databases, err := listExtensionDatabases(db, d)
+	if err != nil {
+		return err
+	}
+
+	for _, database := range databases {
+		if err := installDatabaseExtension(db, d, database); err != nil {
+			return fmt.Errorf("could not install extension %s in database %s: %w", extName, database, err)
+		}
+	}
+
+	return nil
+}
+
+func installDatabaseExtension(db *DBConnection, d *schema.ResourceData, database string) error {
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(createExtensionQuery(d, true)); err != nil {
+		return err
+	}
+
+	// The extension may already have been installed with another schema or version
+	if err := reconcileExtension(txn, d); err != nil {
+		return err
+	}
+
+	return txn.Commit()
+}
+
+// reconcileExtension updates the schema and version of the installed extension to the configured ones, if set.
+func reconcileExtension(txn *sql.Tx, d *schema.ResourceData) error {
+	extName := d.Get(extNameAttr).(string)
+
+	extSchema, extVersion, err := readDatabaseExtension(txn, extName)
+	if err != nil {
+		return err
+	}
+
+	if v := d.Get(extSchemaAttr).(string); v != "" && v != extSchema {
+		sql := fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s", pq.QuoteIdentifier(extName), pq.QuoteIdentifier(v))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating extension SCHEMA: %w", err)
+		}
+	}
+
+	if v := d.Get(extVersionAttr).(string); v != "" && v != extVersion {
+		sql := fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s", pq.QuoteIdentifier(extName), pq.QuoteIdentifier(v))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating extension version: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readExtensionInDatabase returns the schema and version of the extension in the database, empty if it is not installed.
+func readExtensionInDatabase(db *DBConnection, database, extName string) (string, string, error) {
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return "", "", err
+	}
+	defer deferredRollback(txn)
+
+	return readDatabaseExtension(txn, extName)
+}
+
+// readDatabaseExtension returns the schema and version of the extension, empty if it is not installed.
+func readDatabaseExtension(queryAble QueryAble, extName string) (string, string, error) {
+	var extSchema, extVersion string
+	err := queryAble.QueryRow(
+		`SELECT n.nspname, e.extversion `+
+			`FROM pg_catalog.pg_extension e JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace `+
+			`WHERE e.extname = $1`,
+		extName,
+	).Scan(&extSchema, &extVersion)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", "", nil
+	case err != nil:
+		return "", "", fmt.Errorf("Error reading extension: %w", err)
+	}
+
+	return extSchema, extVersion, nil
+}
+
+// listExtensionDatabases returns the sorted names of the databases accepting connections,
+// templates excluded, matching the patterns.
+func listExtensionDatabases(db QueryAble, d attributeGetter) ([]string, error) {
+	query := finalizeQueryWithFilters(
+		"SELECT datname FROM pg_catalog.pg_database WHERE NOT datistemplate AND datallowconn",
+		queryConcatKeywordAnd,
+		applyPatternMatchingToQuery(extDatabasesPatternMatchingTarget, d),
+	)
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("could not list databases: %w", err)
+	}
+	defer rows.Close()
+
+	databases := []string{}
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, fmt.Errorf("could not scan database name: %w", err)
+		}
+		databases = append(databases, database)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(databases)
+	return databases, nil
+}
@@ -0,0 +1,82 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlExtensionDatabases_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix1, teardown1 := setupTestDatabase(t, true, false)
+	defer teardown1()
+	dbSuffix2, teardown2 := setupTestDatabase(t, true, false)
+	defer teardown2()
+
+	dbName1, _ := getTestDBNames(dbSuffix1)
+	dbName2, _ := getTestDBNames(dbSuffix2)
+	testConfig := getTestConfig(t)
+
+	config := fmt.Sprintf(`
+	resource "postgresql_extension_databases" "trgm" {
+		name              = "pg_trgm"
+		like_any_patterns = ["%s", "%s"]
+	}
+	`, dbName1, dbName2)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureExtension)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckExtensionInDatabases("pg_trgm", false, dbName1, dbName2),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_extension_databases.trgm", "databases.#", "2"),
+					resource.TestCheckTypeSetElemAttr("postgresql_extension_databases.trgm", "databases.*", dbName1),
+					resource.TestCheckTypeSetElemAttr("postgresql_extension_databases.trgm", "databases.*", dbName2),
+					testAccCheckExtensionInDatabases("pg_trgm", true, dbName1, dbName2),
+				),
+			},
+			{
+				// The extension dropped outside of Terraform is installed again
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName2), "DROP EXTENSION pg_trgm")
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_extension_databases.trgm", "databases.#", "2"),
+					testAccCheckExtensionInDatabases("pg_trgm", true, dbName1, dbName2),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckExtensionInDatabases(extName string, expected bool, databases ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		for _, database := range databases {
+			txn, err := startTransaction(client, database)
+			if err != nil {
+				return err
+			}
+			exists, err := checkExtensionExists(txn, extName)
+			deferredRollback(txn)
+			if err != nil {
+				return err
+			}
+			if exists != expected {
+				return fmt.Errorf("expected extension %s to exist in database %s: %t, got %t", extName, database, expected, exists)
+			}
+		}
+		return nil
+	}
+}
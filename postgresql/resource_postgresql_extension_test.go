@@ -3,6 +3,7 @@ package postgresql
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -209,6 +210,53 @@ func TestAccPostgresqlExtension_Database(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlExtension_AdoptExisting(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_extension" "myextension" {
+  name           = "pg_trgm"
+  database       = "%s"
+  schema         = "test_schema"
+  adopt_existing = %t
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureExtension)
+			testSuperuserPreCheck(t)
+			testConfig := getTestConfig(t)
+			// The extension installed outside of Terraform, in another schema
+			dbExecute(t, testConfig.connStr(dbName), "CREATE EXTENSION pg_trgm SCHEMA public")
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlExtensionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, false),
+				ExpectError: regexp.MustCompile(regexp.QuoteMeta(
+					fmt.Sprintf("terraform import postgresql_extension.<name> %s.pg_trgm", dbName),
+				)),
+			},
+			{
+				// The existing extension is moved to the configured schema
+				Config: fmt.Sprintf(config, dbName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlExtensionExists("postgresql_extension.myextension"),
+					resource.TestCheckResourceAttr("postgresql_extension.myextension", "schema", "test_schema"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccPostgresqlExtension_DropCascade(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -0,0 +1,319 @@
+package postgresql
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	fdwNameAttr        = "name"
+	fdwHandlerAttr     = "handler"
+	fdwValidatorAttr   = "validator"
+	fdwOwnerAttr       = "owner"
+	fdwOptionsAttr     = "options"
+	fdwDropCascadeAttr = "drop_cascade"
+)
+
+func resourcePostgreSQLForeignDataWrapper() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLForeignDataWrapperCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLForeignDataWrapperRead),
+		Update: PGResourceFunc(resourcePostgreSQLForeignDataWrapperUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLForeignDataWrapperDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			fdwNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the foreign-data wrapper",
+			},
+			fdwHandlerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of a previously registered function that will be called to retrieve the execution functions for foreign tables",
+			},
+			fdwValidatorAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of a previously registered function that will be called to check the generic options given to the foreign-data wrapper, as well as options for foreign servers, user mappings and foreign tables using the foreign-data wrapper",
+			},
+			fdwOwnerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The user name of the owner of the foreign-data wrapper",
+			},
+			fdwOptionsAttr: {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:    true,
+				Description: "The options of the foreign-data wrapper, the allowed option names and values are specific to each foreign-data wrapper and are validated using its validator function",
+			},
+			fdwDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Automatically drop objects that depend on the foreign-data wrapper (such as foreign tables and servers), and in turn all objects that depend on those objects. Drop RESTRICT is the default",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLForeignDataWrapperCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Data Wrapper resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	fdwName := d.Get(fdwNameAttr).(string)
+
+	b := bytes.NewBufferString("CREATE FOREIGN DATA WRAPPER ")
+	fmt.Fprint(b, pq.QuoteIdentifier(fdwName))
+
+	if v, ok := d.GetOk(fdwHandlerAttr); ok {
+		fmt.Fprint(b, " HANDLER ", v.(string))
+	}
+
+	if v, ok := d.GetOk(fdwValidatorAttr); ok {
+		fmt.Fprint(b, " VALIDATOR ", v.(string))
+	}
+
+	if options, ok := d.GetOk(fdwOptionsAttr); ok {
+		fmt.Fprintf(b, " OPTIONS (%s)", foreignOptionsToSQL(options.(map[string]interface{})))
+	}
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("could not create foreign data wrapper %s: %w", fdwName, err)
+	}
+
+	if v, ok := d.GetOk(fdwOwnerAttr); ok {
+		currentUser, err := getCurrentUser(txn)
+		if err != nil {
+			return err
+		}
+		if v != currentUser {
+			if err := setFDWOwner(txn, d); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating foreign data wrapper: %w", err)
+	}
+
+	d.SetId(fdwName)
+
+	return resourcePostgreSQLForeignDataWrapperReadImpl(db, d)
+}
+
+func resourcePostgreSQLForeignDataWrapperRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Data Wrapper resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	return resourcePostgreSQLForeignDataWrapperReadImpl(db, d)
+}
+
+func resourcePostgreSQLForeignDataWrapperReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	fdwName := d.Get(fdwNameAttr).(string)
+	if fdwName == "" {
+		// When importing, the ID is the name of the foreign-data wrapper
+		fdwName = d.Id()
+	}
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var handler, validator, owner string
+	var options []string
+	query := `SELECT CASE WHEN fdwhandler = 0 THEN '' ELSE fdwhandler::regproc::text END, ` +
+		`CASE WHEN fdwvalidator = 0 THEN '' ELSE fdwvalidator::regproc::text END, ` +
+		`fdwowner::regrole, fdwoptions ` +
+		`FROM pg_catalog.pg_foreign_data_wrapper WHERE fdwname = $1`
+	err = txn.QueryRow(query, fdwName).Scan(&handler, &validator, &owner, pq.Array(&options))
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL foreign data wrapper (%s) not found", fdwName)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading foreign data wrapper: %w", err)
+	}
+
+	d.Set(fdwNameAttr, fdwName)
+	d.Set(fdwHandlerAttr, handler)
+	d.Set(fdwValidatorAttr, validator)
+	d.Set(fdwOwnerAttr, owner)
+	d.Set(fdwOptionsAttr, parseForeignOptions(options))
+	d.SetId(fdwName)
+
+	return nil
+}
+
+func resourcePostgreSQLForeignDataWrapperDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Data Wrapper resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	fdwName := d.Get(fdwNameAttr).(string)
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	dropMode := "RESTRICT"
+	if d.Get(fdwDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	}
+
+	sql := fmt.Sprintf("DROP FOREIGN DATA WRAPPER %s %s", pq.QuoteIdentifier(fdwName), dropMode)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop foreign data wrapper: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting foreign data wrapper: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourcePostgreSQLForeignDataWrapperUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Data Wrapper resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := setFDWNameIfChanged(txn, d); err != nil {
+		return err
+	}
+
+	if err := setFDWOwnerIfChanged(txn, d); err != nil {
+		return err
+	}
+
+	if err := setFDWFunctionsOptionsIfChanged(txn, d); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating foreign data wrapper: %w", err)
+	}
+
+	return resourcePostgreSQLForeignDataWrapperReadImpl(db, d)
+}
+
+func setFDWFunctionsOptionsIfChanged(txn *sql.Tx, d *schema.ResourceData) error {
+	actions := []string{}
+
+	if d.HasChange(fdwHandlerAttr) {
+		action := "NO HANDLER"
+		if v := d.Get(fdwHandlerAttr).(string); v != "" {
+			action = "HANDLER " + v
+		}
+		actions = append(actions, action)
+	}
+
+	if d.HasChange(fdwValidatorAttr) {
+		action := "NO VALIDATOR"
+		if v := d.Get(fdwValidatorAttr).(string); v != "" {
+			action = "VALIDATOR " + v
+		}
+		actions = append(actions, action)
+	}
+
+	if d.HasChange(fdwOptionsAttr) {
+		oldOptions, newOptions := d.GetChange(fdwOptionsAttr)
+		if options := alterForeignOptionsToSQL(oldOptions.(map[string]interface{}), newOptions.(map[string]interface{})); options != "" {
+			actions = append(actions, fmt.Sprintf("OPTIONS (%s)", options))
+		}
+	}
+
+	if len(actions) == 0 {
+		return nil
+	}
+
+	fdwName := d.Get(fdwNameAttr).(string)
+	sql := fmt.Sprintf("ALTER FOREIGN DATA WRAPPER %s %s", pq.QuoteIdentifier(fdwName), strings.Join(actions, " "))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating foreign data wrapper handler, validator and/or options: %w", err)
+	}
+
+	return nil
+}
+
+func setFDWNameIfChanged(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(fdwNameAttr) {
+		return nil
+	}
+
+	oldName, newName := d.GetChange(fdwNameAttr)
+
+	sql := fmt.Sprintf("ALTER FOREIGN DATA WRAPPER %s RENAME TO %s", pq.QuoteIdentifier(oldName.(string)), pq.QuoteIdentifier(newName.(string)))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating foreign data wrapper name: %w", err)
+	}
+
+	return nil
+}
+
+func setFDWOwnerIfChanged(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(fdwOwnerAttr) {
+		return nil
+	}
+	return setFDWOwner(txn, d)
+}
+
+func setFDWOwner(txn *sql.Tx, d *schema.ResourceData) error {
+	fdwName := d.Get(fdwNameAttr).(string)
+	owner := d.Get(fdwOwnerAttr).(string)
+
+	sql := fmt.Sprintf("ALTER FOREIGN DATA WRAPPER %s OWNER TO %s", pq.QuoteIdentifier(fdwName), pq.QuoteIdentifier(owner))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating foreign data wrapper owner: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlForeignDataWrapper_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureServer)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlForeignDataWrapperDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPostgresqlForeignDataWrapperConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlForeignDataWrapperExists("postgresql_foreign_data_wrapper.myfdw"),
+					resource.TestCheckResourceAttr(
+						"postgresql_foreign_data_wrapper.myfdw", "name", "myfdw"),
+					resource.TestCheckResourceAttr(
+						"postgresql_foreign_data_wrapper.myfdw", "handler", ""),
+					resource.TestCheckResourceAttr(
+						"postgresql_foreign_data_wrapper.myfdw", "validator", ""),
+					resource.TestCheckResourceAttr(
+						"postgresql_foreign_data_wrapper.myfdw", "options.debug", "true"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_foreign_data_wrapper.myfdw",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{fdwDropCascadeAttr},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlForeignDataWrapper_Update(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureServer)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlForeignDataWrapperDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPostgresqlForeignDataWrapperConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlForeignDataWrapperExists("postgresql_foreign_data_wrapper.myfdw"),
+					resource.TestCheckResourceAttr(
+						"postgresql_foreign_data_wrapper.myfdw", "options.%", "1"),
+				),
+			},
+			{
+				Config: testAccPostgresqlForeignDataWrapperChanges,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlForeignDataWrapperExists("postgresql_foreign_data_wrapper.myfdw"),
+					resource.TestCheckResourceAttr(
+						"postgresql_foreign_data_wrapper.myfdw", "name", "myfdw_renamed"),
+					resource.TestCheckResourceAttr(
+						"postgresql_foreign_data_wrapper.myfdw", "options.%", "1"),
+					resource.TestCheckResourceAttr(
+						"postgresql_foreign_data_wrapper.myfdw", "options.level", "high"),
+				),
+			},
+		},
+	})
+}
+
+func checkForeignDataWrapperExists(txn *sql.Tx, fdwName string) (bool, error) {
+	var _rez bool
+	err := txn.QueryRow("SELECT TRUE FROM pg_foreign_data_wrapper WHERE fdwname = $1", fdwName).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("Error reading info about foreign data wrapper: %s", err)
+	}
+
+	return true, nil
+}
+
+func testAccCheckPostgresqlForeignDataWrapperDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_foreign_data_wrapper" {
+			continue
+		}
+
+		txn, err := startTransaction(client, "")
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := checkForeignDataWrapperExists(txn, rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Error checking foreign data wrapper %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Foreign data wrapper still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlForeignDataWrapperExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, "")
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := checkForeignDataWrapperExists(txn, rs.Primary.Attributes[fdwNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking foreign data wrapper %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Foreign data wrapper not found")
+		}
+
+		return nil
+	}
+}
+
+var testAccPostgresqlForeignDataWrapperConfig = `
+resource "postgresql_foreign_data_wrapper" "myfdw" {
+  name = "myfdw"
+  options = {
+    debug = "true"
+  }
+}
+`
+
+var testAccPostgresqlForeignDataWrapperChanges = `
+resource "postgresql_foreign_data_wrapper" "myfdw" {
+  name = "myfdw_renamed"
+  options = {
+    level = "high"
+  }
+}
+`
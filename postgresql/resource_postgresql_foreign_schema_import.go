@@ -0,0 +1,349 @@
+package postgresql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	foreignSchemaImportDatabaseAttr       = "database"
+	foreignSchemaImportServerAttr         = "server"
+	foreignSchemaImportRemoteSchemaAttr   = "remote_schema"
+	foreignSchemaImportLocalSchemaAttr    = "local_schema"
+	foreignSchemaImportLimitToAttr        = "limit_to"
+	foreignSchemaImportExceptAttr         = "except"
+	foreignSchemaImportOptionsAttr        = "options"
+	foreignSchemaImportDropCascadeAttr    = "drop_cascade"
+	foreignSchemaImportImportedTablesAttr = "imported_tables"
+	foreignSchemaImportMissingTablesAttr  = "missing_tables"
+)
+
+func resourcePostgreSQLForeignSchemaImport() *schema.Resource {
+	return &schema.Resource{
+		Create:        PGResourceFunc(resourcePostgreSQLForeignSchemaImportCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLForeignSchemaImportRead),
+		Update:        PGResourceFunc(resourcePostgreSQLForeignSchemaImportUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLForeignSchemaImportDelete),
+		CustomizeDiff: resourcePostgreSQLForeignSchemaImportCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			foreignSchemaImportDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the foreign tables are imported",
+			},
+			foreignSchemaImportServerAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The foreign server to import from",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			foreignSchemaImportRemoteSchemaAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The remote schema to import from",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			foreignSchemaImportLocalSchemaAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The local schema in which the foreign tables are created",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			foreignSchemaImportLimitToAttr: {
+				Type:          schema.TypeSet,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{foreignSchemaImportExceptAttr},
+				Description:   "Import only the listed remote tables",
+			},
+			foreignSchemaImportExceptAttr: {
+				Type:          schema.TypeSet,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{foreignSchemaImportLimitToAttr},
+				Description:   "Import all the remote tables except the listed ones",
+			},
+			foreignSchemaImportOptionsAttr: {
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The options of the import, specific to the foreign-data wrapper (e.g.: import_default for postgres_fdw)",
+			},
+			foreignSchemaImportDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the imported foreign tables",
+			},
+			foreignSchemaImportImportedTablesAttr: {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The foreign tables created by the import",
+			},
+			foreignSchemaImportMissingTablesAttr: {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The imported foreign tables which do not exist anymore, they will be imported again on the next apply",
+			},
+		},
+	}
+}
+
+// resourcePostgreSQLForeignSchemaImportCustomizeDiff plans an update if imported foreign tables have been dropped,
+// so they are imported again.
+func resourcePostgreSQLForeignSchemaImportCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+	if len(diff.Get(foreignSchemaImportMissingTablesAttr).([]interface{})) > 0 {
+		return diff.SetNewComputed(foreignSchemaImportMissingTablesAttr)
+	}
+	return nil
+}
+
+// importForeignSchemaSQL returns the IMPORT FOREIGN SCHEMA statement, limited to the given tables if any.
+func importForeignSchemaSQL(d *schema.ResourceData, limitTo []string) string {
+	b := bytes.NewBufferString("IMPORT FOREIGN SCHEMA ")
+	fmt.Fprint(b, pq.QuoteIdentifier(d.Get(foreignSchemaImportRemoteSchemaAttr).(string)))
+
+	quoteTables := func(tables []string) string {
+		quoted := make([]string, len(tables))
+		for i, table := range tables {
+			quoted[i] = pq.QuoteIdentifier(table)
+		}
+		return strings.Join(quoted, ", ")
+	}
+
+	if len(limitTo) > 0 {
+		fmt.Fprintf(b, " LIMIT TO (%s)", quoteTables(limitTo))
+	} else if except := d.Get(foreignSchemaImportExceptAttr).(*schema.Set); except.Len() > 0 {
+		fmt.Fprintf(b, " EXCEPT (%s)", quoteTables(interfaceSliceToStrings(except.List())))
+	}
+
+	fmt.Fprintf(b, " FROM SERVER %s INTO %s",
+		pq.QuoteIdentifier(d.Get(foreignSchemaImportServerAttr).(string)),
+		pq.QuoteIdentifier(d.Get(foreignSchemaImportLocalSchemaAttr).(string)),
+	)
+
+	if options, ok := d.GetOk(foreignSchemaImportOptionsAttr); ok {
+		fmt.Fprintf(b, " OPTIONS (%s)", foreignOptionsToSQL(options.(map[string]interface{})))
+	}
+
+	return b.String()
+}
+
+func resourcePostgreSQLForeignSchemaImportCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Schema Import resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	serverName := d.Get(foreignSchemaImportServerAttr).(string)
+	localSchema := d.Get(foreignSchemaImportLocalSchemaAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	existingTables, err := getServerForeignTables(txn, serverName, localSchema)
+	if err != nil {
+		return err
+	}
+
+	limitTo := interfaceSliceToStrings(d.Get(foreignSchemaImportLimitToAttr).(*schema.Set).List())
+	if _, err := txn.Exec(importForeignSchemaSQL(d, limitTo)); err != nil {
+		return fmt.Errorf("could not import foreign schema: %w", err)
+	}
+
+	tables, err := getServerForeignTables(txn, serverName, localSchema)
+	if err != nil {
+		return err
+	}
+
+	importedTables := []string{}
+	for _, table := range tables {
+		if !sliceContainsStr(existingTables, table) {
+			importedTables = append(importedTables, table)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error importing foreign schema: %w", err)
+	}
+
+	d.Set(foreignSchemaImportImportedTablesAttr, importedTables)
+	d.SetId(strings.Join([]string{
+		database, serverName, d.Get(foreignSchemaImportRemoteSchemaAttr).(string), localSchema,
+	}, "."))
+
+	return resourcePostgreSQLForeignSchemaImportReadImpl(db, d)
+}
+
+func resourcePostgreSQLForeignSchemaImportRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Schema Import resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	return resourcePostgreSQLForeignSchemaImportReadImpl(db, d)
+}
+
+// resourcePostgreSQLForeignSchemaImportReadImpl only checks the foreign tables created by the import,
+// other foreign tables of the local schema are not managed by this resource.
+func resourcePostgreSQLForeignSchemaImportReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	serverName := d.Get(foreignSchemaImportServerAttr).(string)
+	localSchema := d.Get(foreignSchemaImportLocalSchemaAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	tables, err := getServerForeignTables(txn, serverName, localSchema)
+	if err != nil {
+		return err
+	}
+
+	missingTables := []string{}
+	for _, table := range interfaceSliceToStrings(d.Get(foreignSchemaImportImportedTablesAttr).([]interface{})) {
+		if !sliceContainsStr(tables, table) {
+			log.Printf("[WARN] PostgreSQL imported foreign table (%s.%s) not found in database %s", localSchema, table, database)
+			missingTables = append(missingTables, table)
+		}
+	}
+
+	d.Set(foreignSchemaImportDatabaseAttr, database)
+	d.Set(foreignSchemaImportMissingTablesAttr, missingTables)
+
+	return nil
+}
+
+func resourcePostgreSQLForeignSchemaImportUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Schema Import resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	// The missing tables are planned as unknown, so we take them from the state
+	oldMissingTables, _ := d.GetChange(foreignSchemaImportMissingTablesAttr)
+	missingTables := interfaceSliceToStrings(oldMissingTables.([]interface{}))
+
+	if len(missingTables) > 0 {
+		database := getDatabase(d, db.client.databaseName)
+
+		txn, err := startTransaction(db.client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		if _, err := txn.Exec(importForeignSchemaSQL(d, missingTables)); err != nil {
+			return fmt.Errorf("could not import missing foreign tables: %w", err)
+		}
+
+		if err = txn.Commit(); err != nil {
+			return fmt.Errorf("Error importing foreign schema: %w", err)
+		}
+	}
+
+	return resourcePostgreSQLForeignSchemaImportReadImpl(db, d)
+}
+
+func resourcePostgreSQLForeignSchemaImportDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Schema Import resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	localSchema := d.Get(foreignSchemaImportLocalSchemaAttr).(string)
+
+	tables := []string{}
+	for _, table := range interfaceSliceToStrings(d.Get(foreignSchemaImportImportedTablesAttr).([]interface{})) {
+		tables = append(tables, fmt.Sprintf("%s.%s", pq.QuoteIdentifier(localSchema), pq.QuoteIdentifier(table)))
+	}
+
+	if len(tables) > 0 {
+		txn, err := startTransaction(db.client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		dropMode := "RESTRICT"
+		if d.Get(foreignSchemaImportDropCascadeAttr).(bool) {
+			dropMode = "CASCADE"
+		}
+
+		sql := fmt.Sprintf("DROP FOREIGN TABLE IF EXISTS %s %s", strings.Join(tables, ", "), dropMode)
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not drop imported foreign tables: %w", err)
+		}
+
+		if err = txn.Commit(); err != nil {
+			return fmt.Errorf("Error deleting imported foreign tables: %w", err)
+		}
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// getServerForeignTables returns the foreign tables of a server in a local schema.
+func getServerForeignTables(txn *sql.Tx, serverName, schemaName string) ([]string, error) {
+	rows, err := txn.Query(
+		`SELECT foreign_table_name FROM information_schema.foreign_tables `+
+			`WHERE foreign_server_name = $1 AND foreign_table_schema = $2 ORDER BY foreign_table_name`,
+		serverName, schemaName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not read foreign tables of server %s: %w", serverName, err)
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, fmt.Errorf("could not scan foreign table of server %s: %w", serverName, err)
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
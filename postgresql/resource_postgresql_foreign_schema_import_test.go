@@ -0,0 +1,154 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlForeignSchemaImport_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+
+	// The foreign server points back to the test database itself.
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.users (id int, name text)")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.orders (id int, user_id int)")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.audit (id int)")
+
+	config := fmt.Sprintf(`
+resource "postgresql_extension" "ext_postgres_fdw" {
+  database = "%[1]s"
+  name     = "postgres_fdw"
+}
+
+resource "postgresql_server" "loopback" {
+  server_name = "loopback_%[1]s"
+  fdw_name    = "postgres_fdw"
+  options = {
+    host   = "%[2]s"
+    port   = "%[3]d"
+    dbname = "%[1]s"
+  }
+
+  depends_on = [postgresql_extension.ext_postgres_fdw]
+}
+
+resource "postgresql_user_mapping" "loopback" {
+  server_name = postgresql_server.loopback.server_name
+  user_name   = "%[4]s"
+  options = {
+    user     = "%[4]s"
+    password = "%[5]s"
+  }
+}
+
+resource "postgresql_foreign_schema_import" "test" {
+  database      = "%[1]s"
+  server        = postgresql_server.loopback.server_name
+  remote_schema = "test_schema"
+  local_schema  = "dev_schema"
+  limit_to      = ["users", "orders"]
+
+  depends_on = [postgresql_user_mapping.loopback]
+}
+`, dbName, testConfig.Host, testConfig.Port, testConfig.Username, testConfig.Password)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureServer)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlForeignSchemaImportDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlForeignSchemaImportExists("postgresql_foreign_schema_import.test"),
+					resource.TestCheckResourceAttr("postgresql_foreign_schema_import.test", "imported_tables.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_foreign_schema_import.test", "imported_tables.0", "orders"),
+					resource.TestCheckResourceAttr("postgresql_foreign_schema_import.test", "imported_tables.1", "users"),
+					resource.TestCheckResourceAttr("postgresql_foreign_schema_import.test", "missing_tables.#", "0"),
+				),
+			},
+			{
+				// A dropped foreign table is detected and imported again.
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "DROP FOREIGN TABLE dev_schema.orders")
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlForeignSchemaImportExists("postgresql_foreign_schema_import.test"),
+					resource.TestCheckResourceAttr("postgresql_foreign_schema_import.test", "imported_tables.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_foreign_schema_import.test", "missing_tables.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlForeignSchemaImportDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_foreign_schema_import" {
+			continue
+		}
+
+		txn, err := startTransaction(client, rs.Primary.Attributes[foreignSchemaImportDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		tables, err := getServerForeignTables(txn, rs.Primary.Attributes[foreignSchemaImportServerAttr], rs.Primary.Attributes[foreignSchemaImportLocalSchemaAttr])
+		if err != nil {
+			return err
+		}
+
+		if len(tables) > 0 {
+			return fmt.Errorf("Imported foreign tables still exist after destroy: %v", tables)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlForeignSchemaImportExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[foreignSchemaImportDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		tables, err := getServerForeignTables(txn, rs.Primary.Attributes[foreignSchemaImportServerAttr], rs.Primary.Attributes[foreignSchemaImportLocalSchemaAttr])
+		if err != nil {
+			return err
+		}
+
+		if len(tables) == 0 {
+			return fmt.Errorf("No imported foreign tables found")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,525 @@
+package postgresql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	foreignTableNameAttr          = "name"
+	foreignTableSchemaAttr        = "schema"
+	foreignTableDatabaseAttr      = "database"
+	foreignTableServerAttr        = "server"
+	foreignTableColumnAttr        = "column"
+	foreignTableColumnNameAttr    = "name"
+	foreignTableColumnTypeAttr    = "type"
+	foreignTableColumnNotNullAttr = "not_null"
+	foreignTableColumnOptionsAttr = "options"
+	foreignTableOptionsAttr       = "options"
+	foreignTableDropCascadeAttr   = "drop_cascade"
+)
+
+func resourcePostgreSQLForeignTable() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLForeignTableCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLForeignTableRead),
+		Update: PGResourceFunc(resourcePostgreSQLForeignTableUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLForeignTableDelete),
+		Exists: PGResourceExistsFunc(resourcePostgreSQLForeignTableExists),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourcePostgreSQLForeignTableCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			foreignTableNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the foreign table",
+				ValidateFunc: validateIdentifier,
+			},
+			foreignTableSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the foreign table is created",
+			},
+			foreignTableDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the foreign table is created",
+			},
+			foreignTableServerAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the foreign server to use for the foreign table",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			foreignTableColumnAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The ordered list of columns of the foreign table",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						foreignTableColumnNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The name of the column",
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						foreignTableColumnTypeAttr: {
+							Type:             schema.TypeString,
+							Required:         true,
+							Description:      "The data type of the column",
+							ValidateFunc:     validation.StringIsNotEmpty,
+							DiffSuppressFunc: typeDiffSuppressFunc,
+						},
+						foreignTableColumnNotNullAttr: {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether the column is not allowed to contain null values",
+						},
+						foreignTableColumnOptionsAttr: {
+							Type:        schema.TypeMap,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Optional:    true,
+							Description: "The options of the column (e.g.: column_name for postgres_fdw)",
+						},
+					},
+				},
+			},
+			foreignTableOptionsAttr: {
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "The options of the foreign table (e.g.: schema_name and table_name for postgres_fdw)",
+			},
+			foreignTableDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the foreign table",
+			},
+		},
+	}
+}
+
+type foreignTableColumn struct {
+	name     string
+	typeName string
+	notNull  bool
+	options  map[string]interface{}
+}
+
+func (c foreignTableColumn) definition() string {
+	definition := fmt.Sprintf("%s %s", pq.QuoteIdentifier(c.name), c.typeName)
+	if len(c.options) > 0 {
+		definition += fmt.Sprintf(" OPTIONS (%s)", foreignOptionsToSQL(c.options))
+	}
+	if c.notNull {
+		definition += " NOT NULL"
+	}
+	return definition
+}
+
+func getForeignTableColumns(raw []interface{}) []foreignTableColumn {
+	columns := make([]foreignTableColumn, 0, len(raw))
+	for _, r := range raw {
+		column := r.(map[string]interface{})
+		columns = append(columns, foreignTableColumn{
+			name:     column[foreignTableColumnNameAttr].(string),
+			typeName: column[foreignTableColumnTypeAttr].(string),
+			notNull:  column[foreignTableColumnNotNullAttr].(bool),
+			options:  column[foreignTableColumnOptionsAttr].(map[string]interface{}),
+		})
+	}
+	return columns
+}
+
+// resourcePostgreSQLForeignTableCustomizeDiff forces the recreation of the foreign table if the new columns
+// cannot be reached with ALTER FOREIGN TABLE, i.e.: if the kept columns have been reordered
+// or a column has been added before a kept one.
+func resourcePostgreSQLForeignTableCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	names := []interface{}{}
+	for _, column := range getForeignTableColumns(diff.Get(foreignTableColumnAttr).([]interface{})) {
+		names = append(names, column.name)
+	}
+	if name, ok := isUniqueArr(names); !ok {
+		return fmt.Errorf("foreign table column %v is duplicated", name)
+	}
+
+	if diff.Id() == "" || !diff.HasChange(foreignTableColumnAttr) {
+		return nil
+	}
+
+	oraw, nraw := diff.GetChange(foreignTableColumnAttr)
+	if _, err := foreignTableAlterations(
+		getForeignTableColumns(oraw.([]interface{})), getForeignTableColumns(nraw.([]interface{})),
+	); err != nil {
+		log.Printf("[DEBUG] foreign table will be recreated: %v", err)
+		return diff.ForceNew(foreignTableColumnAttr)
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLForeignTableCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Table resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(foreignTableSchemaAttr).(string)
+	tableName := d.Get(foreignTableNameAttr).(string)
+
+	definitions := []string{}
+	for _, column := range getForeignTableColumns(d.Get(foreignTableColumnAttr).([]interface{})) {
+		definitions = append(definitions, column.definition())
+	}
+
+	b := bytes.NewBufferString("CREATE FOREIGN TABLE ")
+	fmt.Fprintf(b, "%s.%s (%s) SERVER %s",
+		pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(tableName), strings.Join(definitions, ", "),
+		pq.QuoteIdentifier(d.Get(foreignTableServerAttr).(string)),
+	)
+
+	if options, ok := d.GetOk(foreignTableOptionsAttr); ok {
+		fmt.Fprintf(b, " OPTIONS (%s)", foreignOptionsToSQL(options.(map[string]interface{})))
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("could not create foreign table %s: %w", tableName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating foreign table: %w", err)
+	}
+
+	d.SetId(generateForeignTableID(database, schemaName, tableName))
+
+	return resourcePostgreSQLForeignTableReadImpl(db, d)
+}
+
+func resourcePostgreSQLForeignTableExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	database, schemaName, tableName, err := getDBForeignTableName(d, db.client)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the database exists
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	return foreignTableExists(txn, schemaName, tableName)
+}
+
+func resourcePostgreSQLForeignTableRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Table resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	return resourcePostgreSQLForeignTableReadImpl(db, d)
+}
+
+func resourcePostgreSQLForeignTableReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, tableName, err := getDBForeignTableName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var serverName string
+	var options []string
+	query := `SELECT ft.foreign_server_name, pft.ftoptions ` +
+		`FROM information_schema.foreign_tables ft ` +
+		`JOIN pg_catalog.pg_namespace n ON n.nspname = ft.foreign_table_schema ` +
+		`JOIN pg_catalog.pg_class c ON c.relnamespace = n.oid AND c.relname = ft.foreign_table_name ` +
+		`JOIN pg_catalog.pg_foreign_table pft ON pft.ftrelid = c.oid ` +
+		`WHERE ft.foreign_table_schema = $1 AND ft.foreign_table_name = $2`
+	err = txn.QueryRow(query, schemaName, tableName).Scan(&serverName, pq.Array(&options))
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL foreign table (%s.%s) not found in database %s", schemaName, tableName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading foreign table: %w", err)
+	}
+
+	columns, err := readForeignTableColumns(txn, schemaName, tableName)
+	if err != nil {
+		return err
+	}
+
+	d.Set(foreignTableNameAttr, tableName)
+	d.Set(foreignTableSchemaAttr, schemaName)
+	d.Set(foreignTableDatabaseAttr, database)
+	d.Set(foreignTableServerAttr, serverName)
+	d.Set(foreignTableColumnAttr, columns)
+	d.Set(foreignTableOptionsAttr, parseForeignOptions(options))
+	d.SetId(generateForeignTableID(database, schemaName, tableName))
+
+	return nil
+}
+
+func resourcePostgreSQLForeignTableUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Table resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(foreignTableSchemaAttr).(string)
+	tableName := d.Get(foreignTableNameAttr).(string)
+
+	actions := []string{}
+
+	if d.HasChange(foreignTableColumnAttr) {
+		oraw, nraw := d.GetChange(foreignTableColumnAttr)
+		alterations, err := foreignTableAlterations(
+			getForeignTableColumns(oraw.([]interface{})), getForeignTableColumns(nraw.([]interface{})),
+		)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, alterations...)
+	}
+
+	if d.HasChange(foreignTableOptionsAttr) {
+		oldOptions, newOptions := d.GetChange(foreignTableOptionsAttr)
+		if options := alterForeignOptionsToSQL(oldOptions.(map[string]interface{}), newOptions.(map[string]interface{})); options != "" {
+			actions = append(actions, fmt.Sprintf("OPTIONS (%s)", options))
+		}
+	}
+
+	if len(actions) > 0 {
+		txn, err := startTransaction(db.client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		sql := fmt.Sprintf("ALTER FOREIGN TABLE %s.%s %s",
+			pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(tableName), strings.Join(actions, ", "),
+		)
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not alter foreign table %s: %w", tableName, err)
+		}
+
+		if err = txn.Commit(); err != nil {
+			return fmt.Errorf("Error updating foreign table: %w", err)
+		}
+	}
+
+	return resourcePostgreSQLForeignTableReadImpl(db, d)
+}
+
+func resourcePostgreSQLForeignTableDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureServer) {
+		return fmt.Errorf(
+			"Foreign Table resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(foreignTableSchemaAttr).(string)
+	tableName := d.Get(foreignTableNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	dropMode := "RESTRICT"
+	if d.Get(foreignTableDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	}
+
+	sql := fmt.Sprintf("DROP FOREIGN TABLE %s.%s %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(tableName), dropMode)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop foreign table: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting foreign table: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// foreignTableAlterations returns the ALTER FOREIGN TABLE actions needed to go from the old columns to the new ones.
+// As ADD COLUMN always appends the column, it returns an error if the kept columns have been reordered
+// or if a column has been added before a kept one.
+func foreignTableAlterations(oldColumns, newColumns []foreignTableColumn) ([]string, error) {
+	oldByName := make(map[string]foreignTableColumn, len(oldColumns))
+	for _, column := range oldColumns {
+		oldByName[column.name] = column
+	}
+	newByName := make(map[string]foreignTableColumn, len(newColumns))
+	for _, column := range newColumns {
+		newByName[column.name] = column
+	}
+
+	alterations := []string{}
+	kept := []string{}
+	for _, column := range oldColumns {
+		if _, ok := newByName[column.name]; !ok {
+			alterations = append(alterations, "DROP COLUMN "+pq.QuoteIdentifier(column.name))
+			continue
+		}
+		kept = append(kept, column.name)
+	}
+
+	i := 0
+	for _, column := range newColumns {
+		old, ok := oldByName[column.name]
+		if !ok {
+			if i < len(kept) {
+				return nil, fmt.Errorf("column %s has been added before the existing column %s", column.name, kept[i])
+			}
+			alterations = append(alterations, "ADD COLUMN "+column.definition())
+			continue
+		}
+
+		if kept[i] != column.name {
+			return nil, fmt.Errorf("column %s has been reordered", column.name)
+		}
+		i++
+
+		quotedName := pq.QuoteIdentifier(column.name)
+		if normalizeTypeName(old.typeName) != normalizeTypeName(column.typeName) {
+			alterations = append(alterations, fmt.Sprintf("ALTER COLUMN %s TYPE %s", quotedName, column.typeName))
+		}
+		if old.notNull != column.notNull {
+			action := "DROP NOT NULL"
+			if column.notNull {
+				action = "SET NOT NULL"
+			}
+			alterations = append(alterations, fmt.Sprintf("ALTER COLUMN %s %s", quotedName, action))
+		}
+		if options := alterForeignOptionsToSQL(old.options, column.options); options != "" {
+			alterations = append(alterations, fmt.Sprintf("ALTER COLUMN %s OPTIONS (%s)", quotedName, options))
+		}
+	}
+
+	return alterations, nil
+}
+
+func readForeignTableColumns(txn *sql.Tx, schemaName, tableName string) ([]map[string]interface{}, error) {
+	query := `SELECT a.attname, pg_catalog.format_type(a.atttypid, a.atttypmod), a.attnotnull, a.attfdwoptions ` +
+		`FROM pg_catalog.pg_attribute a ` +
+		`JOIN pg_catalog.pg_class c ON c.oid = a.attrelid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+		`WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped ` +
+		`ORDER BY a.attnum`
+	rows, err := txn.Query(query, schemaName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read columns of foreign table %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	columns := []map[string]interface{}{}
+	for rows.Next() {
+		var name, columnType string
+		var notNull bool
+		var options []string
+		if err := rows.Scan(&name, &columnType, &notNull, pq.Array(&options)); err != nil {
+			return nil, fmt.Errorf("could not scan column of foreign table %s: %w", tableName, err)
+		}
+		columns = append(columns, map[string]interface{}{
+			foreignTableColumnNameAttr:    name,
+			foreignTableColumnTypeAttr:    columnType,
+			foreignTableColumnNotNullAttr: notNull,
+			foreignTableColumnOptionsAttr: parseForeignOptions(options),
+		})
+	}
+
+	return columns, rows.Err()
+}
+
+func foreignTableExists(txn *sql.Tx, schemaName, tableName string) (bool, error) {
+	var _rez bool
+	err := txn.QueryRow(
+		`SELECT TRUE FROM information_schema.foreign_tables WHERE foreign_table_schema = $1 AND foreign_table_name = $2`,
+		schemaName, tableName,
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if foreign table exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func generateForeignTableID(database, schemaName, tableName string) string {
+	return joinResourceID(database, schemaName, tableName)
+}
+
+// getDBForeignTableName returns database, schema and foreign table name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBForeignTableName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(foreignTableSchemaAttr).(string)
+	tableName := d.Get(foreignTableNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and foreign table names.
+	if tableName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("foreign table ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		tableName = parsed[2]
+	}
+	return database, schemaName, tableName, nil
+}
@@ -0,0 +1,209 @@
+package postgresql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestForeignTableAlterations(t *testing.T) {
+	id := foreignTableColumn{name: "id", typeName: "integer", notNull: true, options: map[string]interface{}{}}
+	label := foreignTableColumn{name: "label", typeName: "text", options: map[string]interface{}{}}
+	code := foreignTableColumn{name: "code", typeName: "varchar(10)", options: map[string]interface{}{"column_name": "remote_code"}}
+
+	var tests = []struct {
+		oldColumns []foreignTableColumn
+		newColumns []foreignTableColumn
+		want       []string
+		wantErr    bool
+	}{
+		{[]foreignTableColumn{id, label}, []foreignTableColumn{id, label}, []string{}, false},
+		{[]foreignTableColumn{id, label}, []foreignTableColumn{id, label, code}, []string{`ADD COLUMN "code" varchar(10) OPTIONS ("column_name" 'remote_code')`}, false},
+		{[]foreignTableColumn{id, label}, []foreignTableColumn{id}, []string{`DROP COLUMN "label"`}, false},
+		{
+			[]foreignTableColumn{id, label},
+			[]foreignTableColumn{{name: "id", typeName: "bigint", options: map[string]interface{}{}}, {name: "label", typeName: "text", options: map[string]interface{}{"column_name": "name"}}},
+			[]string{`ALTER COLUMN "id" TYPE bigint`, `ALTER COLUMN "id" DROP NOT NULL`, `ALTER COLUMN "label" OPTIONS (ADD "column_name" 'name')`},
+			false,
+		},
+		{[]foreignTableColumn{id, label}, []foreignTableColumn{code, id, label}, nil, true},
+		{[]foreignTableColumn{id, label}, []foreignTableColumn{label, id}, nil, true},
+	}
+
+	for _, test := range tests {
+		alterations, err := foreignTableAlterations(test.oldColumns, test.newColumns)
+		if (err != nil) != test.wantErr {
+			t.Errorf("foreignTableAlterations(%v, %v) returned error %v, want error: %t", test.oldColumns, test.newColumns, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !reflect.DeepEqual(alterations, test.want) {
+			t.Errorf("foreignTableAlterations(%v, %v) returned %#v, want %#v", test.oldColumns, test.newColumns, alterations, test.want)
+		}
+	}
+}
+
+func TestAccPostgresqlForeignTable_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_extension" "ext_postgres_fdw" {
+  database = "%[1]s"
+  name     = "postgres_fdw"
+}
+
+resource "postgresql_server" "remote" {
+  server_name = "remote_%[1]s"
+  fdw_name    = "postgres_fdw"
+  options = {
+    host   = "foo"
+    dbname = "foodb"
+  }
+
+  depends_on = [postgresql_extension.ext_postgres_fdw]
+}
+
+resource "postgresql_foreign_table" "test" {
+  database = "%[1]s"
+  schema   = "test_schema"
+  name     = "remote_users"
+  server   = postgresql_server.remote.server_name
+
+  %[2]s
+
+  options = {
+    schema_name = "public"
+    table_name  = "users"
+  }
+}
+`
+	columns := `
+  column {
+    name     = "id"
+    type     = "int"
+    not_null = true
+  }
+  column {
+    name = "name"
+    type = "varchar(100)"
+  }
+`
+	columnsUpdated := `
+  column {
+    name     = "id"
+    type     = "bigint"
+    not_null = true
+  }
+  column {
+    name = "email"
+    type = "text"
+    options = {
+      column_name = "mail"
+    }
+  }
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureServer)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlForeignTableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, columns),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlForeignTableExists("postgresql_foreign_table.test"),
+					resource.TestCheckResourceAttr("postgresql_foreign_table.test", "column.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_foreign_table.test", "column.0.type", "integer"),
+					resource.TestCheckResourceAttr("postgresql_foreign_table.test", "column.0.not_null", "true"),
+					resource.TestCheckResourceAttr("postgresql_foreign_table.test", "options.table_name", "users"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, columnsUpdated),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlForeignTableExists("postgresql_foreign_table.test"),
+					resource.TestCheckResourceAttr("postgresql_foreign_table.test", "column.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_foreign_table.test", "column.0.type", "bigint"),
+					resource.TestCheckResourceAttr("postgresql_foreign_table.test", "column.1.name", "email"),
+					resource.TestCheckResourceAttr("postgresql_foreign_table.test", "column.1.options.column_name", "mail"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_foreign_table.test",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("%s.test_schema.remote_users", dbName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{foreignTableDropCascadeAttr},
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlForeignTableDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_foreign_table" {
+			continue
+		}
+
+		txn, err := startTransaction(client, rs.Primary.Attributes[foreignTableDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := foreignTableExists(txn, rs.Primary.Attributes[foreignTableSchemaAttr], rs.Primary.Attributes[foreignTableNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking foreign table %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Foreign table still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlForeignTableExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[foreignTableDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := foreignTableExists(txn, rs.Primary.Attributes[foreignTableSchemaAttr], rs.Primary.Attributes[foreignTableNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking foreign table %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Foreign table not found")
+		}
+
+		return nil
+	}
+}
@@ -49,10 +49,11 @@ func resourcePostgreSQLFunction() *schema.Resource {
 				DiffSuppressFunc: defaultDiffSuppressFunc,
 			},
 			funcNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Name of the function.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "Name of the function.",
 			},
 			funcArgAttr: {
 				Type: schema.TypeList,
@@ -98,7 +99,6 @@ func resourcePostgreSQLFunction() *schema.Resource {
 			funcLanguageAttr: {
 				Type:        schema.TypeString,
 				Optional:    true,
-				ForceNew:    true,
 				Default:     "plpgsql",
 				Description: "Language of theof the function. One of: internal, sql, c, plpgsql",
 
@@ -147,8 +147,8 @@ func resourcePostgreSQLFunction() *schema.Resource {
 func resourcePostgreSQLFunctionCreate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureFunction) {
 		return fmt.Errorf(
-			"postgresql_function resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_function resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -162,8 +162,8 @@ func resourcePostgreSQLFunctionCreate(db *DBConnection, d *schema.ResourceData)
 func resourcePostgreSQLFunctionExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
 	if !db.featureSupported(featureFunction) {
 		return false, fmt.Errorf(
-			"postgresql_function resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_function resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -198,8 +198,8 @@ func resourcePostgreSQLFunctionExists(db *DBConnection, d *schema.ResourceData)
 func resourcePostgreSQLFunctionRead(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureFunction) {
 		return fmt.Errorf(
-			"postgresql_function resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_function resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -232,13 +232,19 @@ func resourcePostgreSQLFunctionReadImpl(db *DBConnection, d *schema.ResourceData
 
 	txn, err := startTransaction(db.client, databaseName)
 	if err != nil {
+		if isObjectNotFoundError(err) {
+			log.Printf("[WARN] PostgreSQL database %s of function %s not found", databaseName, functionId)
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 	defer deferredRollback(txn)
 
+	// to_regprocedure fails if a type of the arguments has been dropped
 	err = txn.QueryRow(query, functionSignature).Scan(&funcDefinition)
 	switch {
-	case err == sql.ErrNoRows:
+	case err == sql.ErrNoRows || isObjectNotFoundError(err):
 		log.Printf("[WARN] PostgreSQL function: %s", functionId)
 		d.SetId("")
 		return nil
@@ -284,8 +290,8 @@ func resourcePostgreSQLFunctionReadImpl(db *DBConnection, d *schema.ResourceData
 func resourcePostgreSQLFunctionDelete(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureFunction) {
 		return fmt.Errorf(
-			"postgresql_function resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_function resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -323,11 +329,13 @@ func resourcePostgreSQLFunctionDelete(db *DBConnection, d *schema.ResourceData)
 func resourcePostgreSQLFunctionUpdate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureFunction) {
 		return fmt.Errorf(
-			"postgresql_function resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_function resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
+	// The function is always replaced in place, as dropping it would also drop
+	// its privileges and the objects depending on it.
 	if err := createFunction(db, d, true); err != nil {
 		return err
 	}
@@ -6,9 +6,48 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
+func TestFunctionExistsOnConnectionError(t *testing.T) {
+	exists := PGResourceExistsFunc(resourcePostgreSQLFunctionExists)
+	newResourceData := func() *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, resourcePostgreSQLFunction().Schema, map[string]interface{}{})
+		d.SetId("mydb.public.increment(integer)")
+		return d
+	}
+
+	for _, connErr := range connectionErrors {
+		found, err := exists(newResourceData(), newFailingClient(t, "mydb", connErr))
+		if err == nil {
+			t.Errorf("expected an error to be returned for %v", connErr)
+		}
+		if found {
+			t.Errorf("expected the function not to be reported as existing on %v", connErr)
+		}
+	}
+
+	notFound := &pq.Error{Code: "42704", Message: `type "mytype" does not exist`}
+	found, err := exists(newResourceData(), newFailingClient(t, "mydb", notFound))
+	if err != nil || found {
+		t.Errorf("expected the function to be reported as missing, got %t, %v", found, err)
+	}
+
+	d := newResourceData()
+	db, err := newFailingClient(t, "mydb", &pq.Error{Code: "3D000"}).Connect()
+	if err != nil {
+		t.Fatalf("could not connect: %v", err)
+	}
+	if err := resourcePostgreSQLFunctionReadImpl(db, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the function to be removed from state when its database does not exist")
+	}
+}
+
 func TestAccPostgresqlFunction_Basic(t *testing.T) {
 	config := `
 resource "postgresql_function" "basic_function" {
@@ -204,6 +243,91 @@ resource "postgresql_function" "func" {
 	})
 }
 
+func TestAccPostgresqlFunction_UpdateKeepsPrivileges(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+
+	dbExecute(t, dsn, "CREATE ROLE test_function_role")
+	defer func() {
+		dbExecute(t, dsn, "DROP ROLE test_function_role")
+	}()
+
+	configTemplate := `
+resource "postgresql_function" "func" {
+    name = "func_privileges"
+    returns = "integer"
+    language = "%s"
+    body = "%s"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureFunction)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlFunctionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, "plpgsql", "BEGIN RETURN 1; END;"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlFunctionExists("postgresql_function.func", ""),
+					func(*terraform.State) error {
+						dbExecute(t, dsn, "GRANT EXECUTE ON FUNCTION public.func_privileges() TO test_function_role")
+						return nil
+					},
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, "plpgsql", "BEGIN RETURN 2; END;"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlFunctionExists("postgresql_function.func", ""),
+					testAccCheckPostgresqlFunctionGrantee("public.func_privileges()", "test_function_role"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, "sql", "SELECT 3;"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlFunctionExists("postgresql_function.func", ""),
+					resource.TestCheckResourceAttr("postgresql_function.func", "language", "sql"),
+					testAccCheckPostgresqlFunctionGrantee("public.func_privileges()", "test_function_role"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckPostgresqlFunctionGrantee checks that EXECUTE is granted on the function to the role itself,
+// as has_function_privilege would also be true through the default privileges of PUBLIC.
+func testAccCheckPostgresqlFunctionGrantee(signature, role string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var granted bool
+		err = db.QueryRow(
+			"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_proc p, aclexplode(p.proacl) acl "+
+				"WHERE p.oid = $1::regprocedure AND acl.grantee = $2::regrole AND acl.privilege_type = 'EXECUTE')",
+			signature, role,
+		).Scan(&granted)
+		if err != nil {
+			return fmt.Errorf("Error reading privileges of function %s: %w", signature, err)
+		}
+
+		if !granted {
+			return fmt.Errorf("EXECUTE on function %s is not granted to %s anymore", signature, role)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckPostgresqlFunctionExists(n string, database string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
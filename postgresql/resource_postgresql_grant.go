@@ -1,10 +1,13 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -24,6 +27,8 @@ var allowedObjectTypes = []string{
 	"foreign_data_wrapper",
 	"foreign_server",
 	"column",
+	"type",
+	"language",
 }
 
 var objectTypes = map[string]string{
@@ -36,31 +41,43 @@ var objectTypes = map[string]string{
 
 func resourcePostgreSQLGrant() *schema.Resource {
 	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLGrantCreate),
-		// Since all of this resource's arguments force a recreation
-		// there's no need for an Update function
-		// Update:
-		Read:   PGResourceFunc(resourcePostgreSQLGrantRead),
-		Delete: PGResourceFunc(resourcePostgreSQLGrantDelete),
+		CreateContext: PGResourceContextFunc(resourcePostgreSQLGrantCreate),
+		// All of this resource's arguments force a recreation
+		// except prevent_self_lockout, which is only used by the provider
+		UpdateContext: PGResourceContextFunc(resourcePostgreSQLGrantRead),
+		ReadContext:   PGResourceContextFunc(resourcePostgreSQLGrantRead),
+		DeleteContext: PGResourceContextFunc(resourcePostgreSQLGrantDelete),
+		CustomizeDiff: resourcePostgreSQLGrantCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePostgreSQLGrantImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"role": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The name of the role to grant privileges on",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the role to grant privileges on",
 			},
 			"database": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The database to grant privileges on for this role",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The database to grant privileges on for this role",
 			},
 			"schema": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "The database schema to grant privileges on for this role",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateOptionalIdentifier,
+				Description:  "The database schema to grant privileges on for this role",
 			},
 			"object_type": {
 				Type:         schema.TypeString,
@@ -100,18 +117,51 @@ func resourcePostgreSQLGrant() *schema.Resource {
 				Default:     false,
 				Description: "Permit the grant recipient to grant it to others",
 			},
+			"prevent_self_lockout": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Refuse to revoke the CONNECT privilege of the role used by the provider on the database, instead of only logging a warning",
+			},
+			"statement_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Abort the statements granting or revoking the privileges that take more than the specified number of milliseconds, bounded by the timeouts of the resource",
+			},
+			"batch_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Grant the privileges on all the tables or sequences of the schema in statements of at most this number of objects, instead of a single statement",
+			},
 		},
 	}
 }
 
+// resourcePostgreSQLGrantCustomizeDiff rejects at plan time the privileges not allowed for the object type
+// and the object types not supported by the version of the server.
+func resourcePostgreSQLGrantCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeDiffPrivileges(ctx, diff, meta); err != nil {
+		return err
+	}
+	if !diff.NewValueKnown("object_type") {
+		return nil
+	}
+	if db := planConnection(meta); db != nil {
+		return validateFeatureSupport(db, diff.Get("object_type").(string))
+	}
+	return nil
+}
+
 func resourcePostgreSQLGrantRead(db *DBConnection, d *schema.ResourceData) error {
-	if err := validateFeatureSupport(db, d); err != nil {
+	if err := validateFeatureSupport(db, d.Get("object_type").(string)); err != nil {
 		return fmt.Errorf("feature is not supported: %v", err)
 	}
 
 	exists, err := checkRoleDBSchemaExists(db.client, d)
 	if err != nil {
-		return err
+		return asCatalogReadError(err)
 	}
 	if !exists {
 		d.SetId("")
@@ -125,17 +175,272 @@ func resourcePostgreSQLGrantRead(db *DBConnection, d *schema.ResourceData) error
 	}
 	defer deferredRollback(txn)
 
-	return readRolePrivileges(txn, d)
+	return asCatalogReadError(readRolePrivileges(txn, d))
+}
+
+// resourcePostgreSQLGrantImport imports a grant from an ID in the format
+// role|object_type|database|schema|objects|columns, the privileges are read from the catalog.
+func resourcePostgreSQLGrantImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	grant, err := parseGrantImportID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("role", grant.role)
+	d.Set("object_type", grant.objectType)
+	d.Set("database", grant.database)
+	d.Set("schema", grant.schema)
+	d.Set("objects", grant.objects)
+	d.Set("columns", grant.columns)
+
+	db, err := meta.(*Client).Connect()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFeatureSupport(db, d.Get("object_type").(string)); err != nil {
+		return nil, fmt.Errorf("feature is not supported: %v", err)
+	}
+
+	txn, err := startTransaction(db.client, grant.database)
+	if err != nil {
+		return nil, err
+	}
+	defer deferredRollback(txn)
+
+	if err := checkGrantObjectsExist(txn, d); err != nil {
+		return nil, err
+	}
+
+	roleOID, err := getRoleOID(txn, grant.role)
+	if err != nil {
+		return nil, err
+	}
+
+	privileges, withGrantOption, err := readGrantedPrivileges(txn, grant, roleOID)
+	if err != nil {
+		return nil, err
+	}
+	if len(privileges) == 0 {
+		return nil, fmt.Errorf("role %s has no privileges on %s matching import ID %s", grant.role, grant.objectType, d.Id())
+	}
+
+	d.Set("privileges", privileges)
+	d.Set("with_grant_option", withGrantOption)
+	d.Set("prevent_self_lockout", true)
+	d.SetId(generateGrantID(d))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// grantImportID is the parsed import ID of a postgresql_grant resource.
+type grantImportID struct {
+	role       string
+	objectType string
+	database   string
+	schema     string
+	objects    []string
+	columns    []string
+}
+
+const grantImportIDFormat = "role|object_type|database|schema|objects|columns"
+
+func parseGrantImportID(id string) (grantImportID, error) {
+	parts := strings.Split(id, "|")
+	if len(parts) < 3 || len(parts) > 6 {
+		return grantImportID{}, fmt.Errorf(
+			"invalid import ID %q: expected the format %s (objects and columns are comma separated, trailing empty parts can be omitted)",
+			id, grantImportIDFormat,
+		)
+	}
+	for len(parts) < 6 {
+		parts = append(parts, "")
+	}
+
+	splitList := func(value string) []string {
+		if value == "" {
+			return []string{}
+		}
+		return strings.Split(value, ",")
+	}
+
+	grant := grantImportID{
+		role:       parts[0],
+		objectType: parts[1],
+		database:   parts[2],
+		schema:     parts[3],
+		objects:    splitList(parts[4]),
+		columns:    splitList(parts[5]),
+	}
+
+	invalid := func(format string, args ...interface{}) (grantImportID, error) {
+		return grantImportID{}, fmt.Errorf("invalid import ID %q: %s", id, fmt.Sprintf(format, args...))
+	}
+
+	if grant.role == "" {
+		return invalid("role is empty")
+	}
+	if grant.database == "" {
+		return invalid("database is empty")
+	}
+	if !sliceContainsStr(allowedObjectTypes, grant.objectType) {
+		return invalid("object type %q is not one of %s", grant.objectType, strings.Join(allowedObjectTypes, ", "))
+	}
+	for _, object := range append(append([]string{}, grant.objects...), grant.columns...) {
+		if object == "" {
+			return invalid("objects and columns can not be empty")
+		}
+	}
+	if grant.objectType != "column" && len(grant.columns) > 0 {
+		return invalid("columns can only be set for object type column")
+	}
+
+	switch grant.objectType {
+	case "database":
+		if grant.schema != "" || len(grant.objects) > 0 {
+			return invalid("schema and objects can not be set for object type database")
+		}
+	case "schema":
+		if grant.schema == "" {
+			return invalid("schema is required for object type schema")
+		}
+		if len(grant.objects) > 0 {
+			return invalid("objects can not be set for object type schema")
+		}
+	case "foreign_data_wrapper", "foreign_server", "language":
+		if grant.schema != "" {
+			return invalid("schema can not be set for object type %s", grant.objectType)
+		}
+		if len(grant.objects) != 1 {
+			return invalid("exactly one object is required for object type %s", grant.objectType)
+		}
+	case "type":
+		if grant.schema == "" {
+			return invalid("schema is required for object type type")
+		}
+		if len(grant.objects) == 0 {
+			return invalid("objects are required for object type type")
+		}
+	case "column":
+		if grant.schema == "" {
+			return invalid("schema is required for object type column")
+		}
+		if len(grant.objects) != 1 {
+			return invalid("exactly one table is required for object type column")
+		}
+		if len(grant.columns) == 0 {
+			return invalid("columns are required for object type column")
+		}
+	default:
+		if grant.schema == "" {
+			return invalid("schema is required for object type %s", grant.objectType)
+		}
+	}
+
+	return grant, nil
+}
+
+// readGrantedPrivileges returns the privileges granted to the role on all the objects of the grant
+// and whether they are all grantable.
+func readGrantedPrivileges(txn *sql.Tx, grant grantImportID, roleOID int) ([]string, bool, error) {
+	var from, acl, key string
+	conditions := []string{}
+	args := []interface{}{roleOID}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	switch grant.objectType {
+	case "database":
+		from, acl, key = "pg_catalog.pg_database o", aclWithDefault(roleOID, "o.datacl", "d", "o.datdba"), "o.oid"
+		addCondition("o.datname = $%d", grant.database)
+	case "schema":
+		from, acl, key = "pg_catalog.pg_namespace o", "o.nspacl", "o.oid"
+		addCondition("o.nspname = $%d", grant.schema)
+	case "foreign_data_wrapper":
+		from, acl, key = "pg_catalog.pg_foreign_data_wrapper o", "o.fdwacl", "o.oid"
+		addCondition("o.fdwname = ANY($%d)", pq.Array(grant.objects))
+	case "foreign_server":
+		from, acl, key = "pg_catalog.pg_foreign_server o", "o.srvacl", "o.oid"
+		addCondition("o.srvname = ANY($%d)", pq.Array(grant.objects))
+	case "language":
+		from, acl, key = "pg_catalog.pg_language o", aclWithDefault(roleOID, "o.lanacl", "l", "o.lanowner"), "o.oid"
+		addCondition("o.lanname = ANY($%d)", pq.Array(grant.objects))
+	case "function", "procedure", "routine":
+		from = "pg_catalog.pg_proc o JOIN pg_catalog.pg_namespace n ON n.oid = o.pronamespace"
+		acl, key = aclWithDefault(roleOID, "o.proacl", "f", "o.proowner"), "o.oid"
+		addCondition("n.nspname = $%d", grant.schema)
+		if len(grant.objects) > 0 {
+			addCondition("o.proname = ANY($%d)", pq.Array(grant.objects))
+		}
+	case "type":
+		from = "pg_catalog.pg_type o JOIN pg_catalog.pg_namespace n ON n.oid = o.typnamespace"
+		acl, key = aclWithDefault(roleOID, "o.typacl", "T", "o.typowner"), "o.oid"
+		addCondition("n.nspname = $%d", grant.schema)
+		addCondition("o.typname = ANY($%d)", pq.Array(grant.objects))
+	case "column":
+		from = "pg_catalog.pg_attribute o " +
+			"JOIN pg_catalog.pg_class c ON c.oid = o.attrelid " +
+			"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace"
+		acl, key = "o.attacl", "o.attnum"
+		addCondition("n.nspname = $%d", grant.schema)
+		addCondition("c.relname = $%d", grant.objects[0])
+		addCondition("c.relkind = $%d", objectTypes["table"])
+		addCondition("o.attname = ANY($%d)", pq.Array(grant.columns))
+	default:
+		from = "pg_catalog.pg_class o JOIN pg_catalog.pg_namespace n ON n.oid = o.relnamespace"
+		acl, key = "o.relacl", "o.oid"
+		addCondition("n.nspname = $%d", grant.schema)
+		addCondition("o.relkind = $%d", objectTypes[grant.objectType])
+		if len(grant.objects) > 0 {
+			addCondition("o.relname = ANY($%d)", pq.Array(grant.objects))
+		}
+	}
+
+	// Only the privileges granted on every object are part of the grant
+	where := strings.Join(conditions, " AND ")
+	query := fmt.Sprintf(
+		`SELECT a.privilege_type, pg_catalog.bool_and(a.is_grantable) `+
+			`FROM %[1]s, pg_catalog.aclexplode(%[2]s) a `+
+			`WHERE %[3]s AND a.grantee = $1 `+
+			`GROUP BY a.privilege_type `+
+			`HAVING count(DISTINCT %[4]s) = (SELECT count(*) FROM %[1]s WHERE %[3]s) `+
+			`ORDER BY 1`,
+		from, acl, where, key,
+	)
+
+	rows, err := txn.Query(query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not read privileges of role %s: %w", grant.role, err)
+	}
+	defer rows.Close()
+
+	privileges := []string{}
+	withGrantOption := true
+	for rows.Next() {
+		var privilege string
+		var grantable bool
+		if err := rows.Scan(&privilege, &grantable); err != nil {
+			return nil, false, fmt.Errorf("could not scan privileges of role %s: %w", grant.role, err)
+		}
+		privileges = append(privileges, privilege)
+		withGrantOption = withGrantOption && grantable
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	return privileges, withGrantOption && len(privileges) > 0, nil
 }
 
 func resourcePostgreSQLGrantCreate(db *DBConnection, d *schema.ResourceData) error {
-	if err := validateFeatureSupport(db, d); err != nil {
+	if err := validateFeatureSupport(db, d.Get("object_type").(string)); err != nil {
 		return fmt.Errorf("feature is not supported: %v", err)
 	}
 
 	// Validate parameters.
 	objectType := d.Get("object_type").(string)
-	if d.Get("schema").(string) == "" && !sliceContainsStr([]string{"database", "foreign_data_wrapper", "foreign_server"}, objectType) {
+	if d.Get("schema").(string) == "" && !sliceContainsStr([]string{"database", "foreign_data_wrapper", "foreign_server", "language"}, objectType) {
 		return fmt.Errorf("parameter 'schema' is mandatory for postgresql_grant resource")
 	}
 	if d.Get("objects").(*schema.Set).Len() > 0 && (objectType == "database" || objectType == "schema") {
@@ -153,9 +458,18 @@ func resourcePostgreSQLGrantCreate(db *DBConnection, d *schema.ResourceData) err
 	if (d.Get("objects").(*schema.Set).Len() != 1) && (objectType == "column") {
 		return fmt.Errorf("must specify exactly 1 table in the `objects` field when `object_type` is `column`")
 	}
+	if d.Get("objects").(*schema.Set).Len() == 0 && objectType == "type" {
+		return fmt.Errorf("must specify at least one type in the `objects` field when `object_type` is `type`")
+	}
 	if d.Get("objects").(*schema.Set).Len() != 1 && (objectType == "foreign_data_wrapper" || objectType == "foreign_server") {
 		return fmt.Errorf("one element must be specified in `objects` when `object_type` is `foreign_data_wrapper` or `foreign_server`")
 	}
+	if d.Get("objects").(*schema.Set).Len() != 1 && objectType == "language" {
+		return fmt.Errorf("one element must be specified in `objects` when `object_type` is `language`")
+	}
+	if d.Get("batch_size").(int) > 0 && objectType != "table" && objectType != "sequence" {
+		return fmt.Errorf("`batch_size` can only be set when `object_type` is `table` or `sequence`")
+	}
 	if err := validatePrivileges(d); err != nil {
 		return err
 	}
@@ -168,6 +482,10 @@ func resourcePostgreSQLGrantCreate(db *DBConnection, d *schema.ResourceData) err
 	}
 	defer deferredRollback(txn)
 
+	if err := checkGrantObjectsExist(txn, d); err != nil {
+		return err
+	}
+
 	role := d.Get("role").(string)
 	if err := pgLockRole(txn, role); err != nil {
 		return err
@@ -179,6 +497,11 @@ func resourcePostgreSQLGrantCreate(db *DBConnection, d *schema.ResourceData) err
 		}
 	}
 
+	// Set after the locks which disable the statement timeout to wait for them
+	if err := setLocalStatementTimeout(db.client, txn, int64(d.Get("statement_timeout").(int))); err != nil {
+		return err
+	}
+
 	owners, err := getRolesToGrant(txn, d)
 	if err != nil {
 		return err
@@ -187,17 +510,25 @@ func resourcePostgreSQLGrantCreate(db *DBConnection, d *schema.ResourceData) err
 		// Revoke all privileges before granting otherwise reducing privileges will not work.
 		// We just have to revoke them in the same transaction so the role will not lost its
 		// privileges between the revoke and grant statements.
-		if err := revokeRolePrivileges(txn, d); err != nil {
+		objects, err := schemaObjectsToBatch(txn, d)
+		if err != nil {
 			return err
 		}
-		if err := grantRolePrivileges(txn, d); err != nil {
-			return err
+
+		statements := append(revokeRolePrivilegesStatements(d), grantRolePrivilegesStatements(d)...)
+		if objects != nil {
+			statements = batchedPrivilegesStatements(d, objects, d.Get("batch_size").(int), true)
 		}
-		return nil
+		_, err = execStatements(txn, statements, nil)
+		return err
 	}); err != nil {
 		return err
 	}
 
+	if err := checkSelfLockout(txn, d); err != nil {
+		return err
+	}
+
 	if err = txn.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -214,7 +545,7 @@ func resourcePostgreSQLGrantCreate(db *DBConnection, d *schema.ResourceData) err
 }
 
 func resourcePostgreSQLGrantDelete(db *DBConnection, d *schema.ResourceData) error {
-	if err := validateFeatureSupport(db, d); err != nil {
+	if err := validateFeatureSupport(db, d.Get("object_type").(string)); err != nil {
 		return fmt.Errorf("feature is not supported: %v", err)
 	}
 
@@ -237,17 +568,36 @@ func resourcePostgreSQLGrantDelete(db *DBConnection, d *schema.ResourceData) err
 		}
 	}
 
+	// Set after the locks which disable the statement timeout to wait for them
+	if err := setLocalStatementTimeout(db.client, txn, int64(d.Get("statement_timeout").(int))); err != nil {
+		return err
+	}
+
 	owners, err := getRolesToGrant(txn, d)
 	if err != nil {
 		return err
 	}
 
 	if err := withRolesGranted(txn, owners, func() error {
-		return revokeRolePrivileges(txn, d)
+		objects, err := schemaObjectsToBatch(txn, d)
+		if err != nil {
+			return err
+		}
+
+		statements := revokeRolePrivilegesStatements(d)
+		if objects != nil {
+			statements = batchedPrivilegesStatements(d, objects, d.Get("batch_size").(int), false)
+		}
+		_, err = execStatements(txn, statements, nil)
+		return err
 	}); err != nil {
 		return err
 	}
 
+	if err := checkSelfLockout(txn, d); err != nil {
+		return err
+	}
+
 	if err = txn.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -257,20 +607,20 @@ func resourcePostgreSQLGrantDelete(db *DBConnection, d *schema.ResourceData) err
 
 func readDatabaseRolePriviges(txn *sql.Tx, d *schema.ResourceData, roleOID int) error {
 	dbName := d.Get("database").(string)
-	query := `
+	query := fmt.Sprintf(`
 SELECT array_agg(privilege_type)
 FROM (
-	SELECT (aclexplode(datacl)).* FROM pg_database WHERE datname=$1
+	SELECT (aclexplode(%s)).* FROM pg_database WHERE datname=$1
 ) as privileges
-WHERE grantee = $2
-`
+WHERE grantee = $2 AND %s
+`, aclWithDefault(roleOID, "datacl", "d", "datdba"), grantorExists)
 
 	var privileges pq.ByteaArray
 	if err := txn.QueryRow(query, dbName, roleOID).Scan(&privileges); err != nil {
 		return fmt.Errorf("could not read privileges for database %s: %w", dbName, err)
 	}
 
-	d.Set("privileges", pgArrayToSet(privileges))
+	d.Set("privileges", normalizeAllPrivileges(d.Get("object_type").(string), d.Get("privileges").(*schema.Set), pgArrayToSet(privileges)))
 	return nil
 }
 
@@ -281,7 +631,7 @@ SELECT array_agg(privilege_type)
 FROM (
 	SELECT (aclexplode(nspacl)).* FROM pg_namespace WHERE nspname=$1
 ) as privileges
-WHERE grantee = $2
+WHERE grantee = $2 AND ` + grantorExists + `
 `
 
 	var privileges pq.ByteaArray
@@ -289,7 +639,7 @@ WHERE grantee = $2
 		return fmt.Errorf("could not read privileges for schema %s: %w", dbName, err)
 	}
 
-	d.Set("privileges", pgArrayToSet(privileges))
+	d.Set("privileges", normalizeAllPrivileges(d.Get("object_type").(string), d.Get("privileges").(*schema.Set), pgArrayToSet(privileges)))
 	return nil
 }
 
@@ -301,7 +651,7 @@ SELECT pg_catalog.array_agg(privilege_type)
 FROM (
 	SELECT (pg_catalog.aclexplode(fdwacl)).* FROM pg_catalog.pg_foreign_data_wrapper WHERE fdwname=$1
 ) as privileges
-WHERE grantee = $2
+WHERE grantee = $2 AND ` + grantorExists + `
 `
 
 	var privileges pq.ByteaArray
@@ -309,7 +659,7 @@ WHERE grantee = $2
 		return fmt.Errorf("could not read privileges for foreign data wrapper %s: %w", fdwName, err)
 	}
 
-	d.Set("privileges", pgArrayToSet(privileges))
+	d.Set("privileges", normalizeAllPrivileges(d.Get("object_type").(string), d.Get("privileges").(*schema.Set), pgArrayToSet(privileges)))
 	return nil
 }
 
@@ -321,7 +671,7 @@ SELECT pg_catalog.array_agg(privilege_type)
 FROM (
 	SELECT (pg_catalog.aclexplode(srvacl)).* FROM pg_catalog.pg_foreign_server WHERE srvname=$1
 ) as privileges
-WHERE grantee = $2
+WHERE grantee = $2 AND ` + grantorExists + `
 `
 
 	var privileges pq.ByteaArray
@@ -329,7 +679,27 @@ WHERE grantee = $2
 		return fmt.Errorf("could not read privileges for foreign server %s: %w", srvName, err)
 	}
 
-	d.Set("privileges", pgArrayToSet(privileges))
+	d.Set("privileges", normalizeAllPrivileges(d.Get("object_type").(string), d.Get("privileges").(*schema.Set), pgArrayToSet(privileges)))
+	return nil
+}
+
+func readLanguageRolePrivileges(txn *sql.Tx, d *schema.ResourceData, roleOID int) error {
+	objects := d.Get("objects").(*schema.Set).List()
+	lanName := objects[0].(string)
+	query := fmt.Sprintf(`
+SELECT pg_catalog.array_agg(privilege_type)
+FROM (
+	SELECT (pg_catalog.aclexplode(%s)).* FROM pg_catalog.pg_language WHERE lanname=$1
+) as privileges
+WHERE grantee = $2 AND %s
+`, aclWithDefault(roleOID, "lanacl", "l", "lanowner"), grantorExists)
+
+	var privileges pq.ByteaArray
+	if err := txn.QueryRow(query, lanName, roleOID).Scan(&privileges); err != nil {
+		return fmt.Errorf("could not read privileges for language %s: %w", lanName, err)
+	}
+
+	d.Set("privileges", normalizeAllPrivileges(d.Get("object_type").(string), d.Get("privileges").(*schema.Set), pgArrayToSet(privileges)))
 	return nil
 }
 
@@ -354,8 +724,8 @@ FROM (SELECT relname, attname, (aclexplode(attacl)).*
          AS col_privs
          JOIN pg_roles ON pg_roles.oid = col_privs.grantee
 WHERE rolname = $1
-  AND privilege_type = $5
-GROUP BY col_privs.relname, col_privs.attname, col_privs.privilege_type
+  AND ($5::text = 'ALL' OR privilege_type = $5::text)
+GROUP BY col_privs.relname, col_privs.attname
 ORDER BY col_privs.attname
 ;`
 	rows, err := txn.Query(
@@ -383,7 +753,7 @@ ORDER BY col_privs.attname
 			missingColumns.Remove(colName)
 		}
 
-		privilegesSet := pgArrayToSet(privileges)
+		privilegesSet := normalizeAllPrivileges("column", d.Get("privileges").(*schema.Set), pgArrayToSet(privileges))
 
 		if !privilegesSet.Equal(d.Get("privileges").(*schema.Set)) {
 			// If any object doesn't have the same privileges as saved in the state,
@@ -421,6 +791,17 @@ func readRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 		return err
 	}
 
+	orphaned, err := readOrphanedGrants(txn, d, roleOID)
+	if err != nil {
+		return err
+	}
+	if len(orphaned) > 0 {
+		log.Printf(
+			"[WARN] privileges of role %s were granted by roles which no longer exist, they are ignored and will be granted again: %s",
+			role, orphanedGrantsMessage(objectType, orphaned),
+		)
+	}
+
 	var query string
 	var rows *sql.Rows
 
@@ -437,22 +818,25 @@ func readRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 	case "foreign_server":
 		return readForeignServerRolePrivileges(txn, d, roleOID)
 
+	case "language":
+		return readLanguageRolePrivileges(txn, d, roleOID)
+
 	case "function", "procedure", "routine":
-		query = `
+		query = fmt.Sprintf(`
 SELECT pg_proc.proname, array_remove(array_agg(privilege_type), NULL)
 FROM pg_proc
 JOIN pg_namespace ON pg_namespace.oid = pg_proc.pronamespace
 LEFT JOIN (
     select acls.*
     from (
-             SELECT proname, pronamespace, (aclexplode(proacl)).* FROM pg_proc
+             SELECT proname, pronamespace, (aclexplode(%s)).* FROM pg_proc
          ) acls
-    WHERE grantee = $1
+    WHERE grantee = $1 AND `+grantorExists+`
 ) privs
 USING (proname, pronamespace)
       WHERE nspname = $2
 GROUP BY pg_proc.proname
-`
+`, aclWithDefault(roleOID, "proacl", "f", "proowner"))
 		rows, err = txn.Query(
 			query, roleOID, d.Get("schema"),
 		)
@@ -460,6 +844,26 @@ GROUP BY pg_proc.proname
 	case "column":
 		return readColumnRolePrivileges(txn, d)
 
+	case "type":
+		// Types (including domains) have no ALL TYPES IN SCHEMA form, so the objects are always specified
+		query = fmt.Sprintf(`
+SELECT pg_type.typname, array_remove(array_agg(privilege_type), NULL)
+FROM pg_type
+JOIN pg_namespace ON pg_namespace.oid = pg_type.typnamespace
+LEFT JOIN (
+    SELECT acls.* FROM (
+        SELECT oid, (aclexplode(%s)).* FROM pg_type
+    ) as acls
+    WHERE grantee = $1 AND `+grantorExists+`
+) privs
+ON privs.oid = pg_type.oid
+WHERE nspname = $2 AND typname = ANY($3)
+GROUP BY pg_type.typname
+`, aclWithDefault(roleOID, "typacl", "T", "typowner"))
+		rows, err = txn.Query(
+			query, roleOID, d.Get("schema"), pq.Array(interfaceSliceToStrings(objects.List())),
+		)
+
 	default:
 		query = `
 SELECT pg_class.relname, array_remove(array_agg(privilege_type), NULL)
@@ -469,7 +873,7 @@ LEFT JOIN (
     SELECT acls.* FROM (
         SELECT relname, relnamespace, relkind, (aclexplode(relacl)).* FROM pg_class c
     ) as acls
-    WHERE grantee=$1
+    WHERE grantee=$1 AND ` + grantorExists + `
 ) privs
 USING (relname, relnamespace, relkind)
 WHERE nspname = $2 AND relkind = $3
@@ -501,7 +905,7 @@ GROUP BY pg_class.relname
 			continue
 		}
 
-		privilegesSet := pgArrayToSet(privileges)
+		privilegesSet := normalizeAllPrivileges(objectType, d.Get("privileges").(*schema.Set), pgArrayToSet(privileges))
 
 		if !privilegesSet.Equal(d.Get("privileges").(*schema.Set)) {
 			// If any object doesn't have the same privileges as saved in the state,
@@ -527,14 +931,14 @@ func createGrantQuery(d *schema.ResourceData, privileges []string) string {
 			"GRANT %s ON DATABASE %s TO %s",
 			strings.Join(privileges, ","),
 			pq.QuoteIdentifier(d.Get("database").(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
+			quoteRoleName(d.Get("role").(string)),
 		)
 	case "SCHEMA":
 		query = fmt.Sprintf(
 			"GRANT %s ON SCHEMA %s TO %s",
 			strings.Join(privileges, ","),
 			pq.QuoteIdentifier(d.Get("schema").(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
+			quoteRoleName(d.Get("role").(string)),
 		)
 	case "FOREIGN_DATA_WRAPPER":
 		fdwName := d.Get("objects").(*schema.Set).List()[0]
@@ -542,7 +946,7 @@ func createGrantQuery(d *schema.ResourceData, privileges []string) string {
 			"GRANT %s ON FOREIGN DATA WRAPPER %s TO %s",
 			strings.Join(privileges, ","),
 			pq.QuoteIdentifier(fdwName.(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
+			quoteRoleName(d.Get("role").(string)),
 		)
 	case "FOREIGN_SERVER":
 		srvName := d.Get("objects").(*schema.Set).List()[0]
@@ -550,7 +954,15 @@ func createGrantQuery(d *schema.ResourceData, privileges []string) string {
 			"GRANT %s ON FOREIGN SERVER %s TO %s",
 			strings.Join(privileges, ","),
 			pq.QuoteIdentifier(srvName.(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
+			quoteRoleName(d.Get("role").(string)),
+		)
+	case "LANGUAGE":
+		lanName := d.Get("objects").(*schema.Set).List()[0]
+		query = fmt.Sprintf(
+			"GRANT %s ON LANGUAGE %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(lanName.(string)),
+			quoteRoleName(d.Get("role").(string)),
 		)
 	case "COLUMN":
 		objects := d.Get("objects").(*schema.Set)
@@ -559,7 +971,14 @@ func createGrantQuery(d *schema.ResourceData, privileges []string) string {
 			strings.Join(privileges, ","),
 			setToPgIdentListWithoutSchema(d.Get("columns").(*schema.Set)),
 			setToPgIdentList(d.Get("schema").(string), objects),
-			pq.QuoteIdentifier(d.Get("role").(string)),
+			quoteRoleName(d.Get("role").(string)),
+		)
+	case "TYPE":
+		query = fmt.Sprintf(
+			"GRANT %s ON TYPE %s TO %s",
+			strings.Join(privileges, ","),
+			setToPgIdentList(d.Get("schema").(string), d.Get("objects").(*schema.Set)),
+			quoteRoleName(d.Get("role").(string)),
 		)
 	case "TABLE", "SEQUENCE", "FUNCTION", "PROCEDURE", "ROUTINE":
 		objects := d.Get("objects").(*schema.Set)
@@ -569,7 +988,7 @@ func createGrantQuery(d *schema.ResourceData, privileges []string) string {
 				strings.Join(privileges, ","),
 				strings.ToUpper(d.Get("object_type").(string)),
 				setToPgIdentList(d.Get("schema").(string), objects),
-				pq.QuoteIdentifier(d.Get("role").(string)),
+				quoteRoleName(d.Get("role").(string)),
 			)
 		} else {
 			query = fmt.Sprintf(
@@ -577,7 +996,7 @@ func createGrantQuery(d *schema.ResourceData, privileges []string) string {
 				strings.Join(privileges, ","),
 				strings.ToUpper(d.Get("object_type").(string)),
 				pq.QuoteIdentifier(d.Get("schema").(string)),
-				pq.QuoteIdentifier(d.Get("role").(string)),
+				quoteRoleName(d.Get("role").(string)),
 			)
 		}
 	}
@@ -597,27 +1016,34 @@ func createRevokeQuery(d *schema.ResourceData) string {
 		query = fmt.Sprintf(
 			"REVOKE ALL PRIVILEGES ON DATABASE %s FROM %s",
 			pq.QuoteIdentifier(d.Get("database").(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
+			quoteRoleName(d.Get("role").(string)),
 		)
 	case "SCHEMA":
 		query = fmt.Sprintf(
 			"REVOKE ALL PRIVILEGES ON SCHEMA %s FROM %s",
 			pq.QuoteIdentifier(d.Get("schema").(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
+			quoteRoleName(d.Get("role").(string)),
 		)
 	case "FOREIGN_DATA_WRAPPER":
 		fdwName := d.Get("objects").(*schema.Set).List()[0]
 		query = fmt.Sprintf(
 			"REVOKE ALL PRIVILEGES ON FOREIGN DATA WRAPPER %s FROM %s",
 			pq.QuoteIdentifier(fdwName.(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
+			quoteRoleName(d.Get("role").(string)),
 		)
 	case "FOREIGN_SERVER":
 		srvName := d.Get("objects").(*schema.Set).List()[0]
 		query = fmt.Sprintf(
 			"REVOKE ALL PRIVILEGES ON FOREIGN SERVER %s FROM %s",
 			pq.QuoteIdentifier(srvName.(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
+			quoteRoleName(d.Get("role").(string)),
+		)
+	case "LANGUAGE":
+		lanName := d.Get("objects").(*schema.Set).List()[0]
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON LANGUAGE %s FROM %s",
+			pq.QuoteIdentifier(lanName.(string)),
+			quoteRoleName(d.Get("role").(string)),
 		)
 	case "COLUMN":
 		objects := d.Get("objects").(*schema.Set)
@@ -632,9 +1058,15 @@ func createRevokeQuery(d *schema.ResourceData) string {
 				setToPgIdentSimpleList(privileges),
 				setToPgIdentListWithoutSchema(columns),
 				setToPgIdentList(d.Get("schema").(string), objects),
-				pq.QuoteIdentifier(d.Get("role").(string)),
+				quoteRoleName(d.Get("role").(string)),
 			)
 		}
+	case "TYPE":
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON TYPE %s FROM %s",
+			setToPgIdentList(d.Get("schema").(string), d.Get("objects").(*schema.Set)),
+			quoteRoleName(d.Get("role").(string)),
+		)
 	case "TABLE", "SEQUENCE", "FUNCTION", "PROCEDURE", "ROUTINE":
 		objects := d.Get("objects").(*schema.Set)
 		privileges := d.Get("privileges").(*schema.Set)
@@ -647,14 +1079,14 @@ func createRevokeQuery(d *schema.ResourceData) string {
 					setToPgIdentSimpleList(privileges),
 					strings.ToUpper(d.Get("object_type").(string)),
 					setToPgIdentList(d.Get("schema").(string), objects),
-					pq.QuoteIdentifier(d.Get("role").(string)),
+					quoteRoleName(d.Get("role").(string)),
 				)
 			} else {
 				query = fmt.Sprintf(
 					"REVOKE ALL PRIVILEGES ON %s %s FROM %s",
 					strings.ToUpper(d.Get("object_type").(string)),
 					setToPgIdentList(d.Get("schema").(string), objects),
-					pq.QuoteIdentifier(d.Get("role").(string)),
+					quoteRoleName(d.Get("role").(string)),
 				)
 			}
 		} else {
@@ -662,7 +1094,7 @@ func createRevokeQuery(d *schema.ResourceData) string {
 				"REVOKE ALL PRIVILEGES ON ALL %sS IN SCHEMA %s FROM %s",
 				strings.ToUpper(d.Get("object_type").(string)),
 				pq.QuoteIdentifier(d.Get("schema").(string)),
-				pq.QuoteIdentifier(d.Get("role").(string)),
+				quoteRoleName(d.Get("role").(string)),
 			)
 		}
 	}
@@ -670,32 +1102,123 @@ func createRevokeQuery(d *schema.ResourceData) string {
 	return query
 }
 
-func grantRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
-	privileges := []string{}
-	for _, priv := range d.Get("privileges").(*schema.Set).List() {
-		privileges = append(privileges, priv.(string))
-	}
+// grantRolePrivilegesStatements returns the statements granting the privileges of the resource.
+func grantRolePrivilegesStatements(d *schema.ResourceData) []string {
+	privileges := sortedSetStrings(d.Get("privileges").(*schema.Set))
 
 	if len(privileges) == 0 {
 		log.Printf("[DEBUG] no privileges to grant for role %s in database: %s,", d.Get("role").(string), d.Get("database"))
 		return nil
 	}
 
-	query := createGrantQuery(d, privileges)
-
-	_, err := txn.Exec(query)
-	return err
+	return []string{createGrantQuery(d, privileges)}
 }
 
-func revokeRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+// revokeRolePrivilegesStatements returns the statements revoking all the privileges of the role
+// on the objects of the resource.
+func revokeRolePrivilegesStatements(d *schema.ResourceData) []string {
 	query := createRevokeQuery(d)
 	if len(query) == 0 {
 		// Query is empty, don't run anything
 		return nil
 	}
-	if _, err := txn.Exec(query); err != nil {
-		return fmt.Errorf("could not execute revoke query: %w", err)
+	return []string{query}
+}
+
+// schemaObjectsToBatch returns the tables or sequences of the schema when the privileges on all of them
+// are granted in batches, or nil when they are granted in a single statement.
+func schemaObjectsToBatch(txn *sql.Tx, d *schema.ResourceData) ([]string, error) {
+	if d.Get("batch_size").(int) == 0 || d.Get("objects").(*schema.Set).Len() > 0 {
+		return nil, nil
+	}
+
+	// The relation kinds are the ones of GRANT ON ALL TABLES / SEQUENCES IN SCHEMA
+	relkinds := []string{"r", "v", "m", "f", "p"}
+	if d.Get("object_type").(string) == "sequence" {
+		relkinds = []string{"S"}
+	}
+
+	rows, err := txn.Query(
+		`SELECT c.relname FROM pg_catalog.pg_class c `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+			`WHERE n.nspname = $1 AND c.relkind::text = ANY($2) ORDER BY c.relname`,
+		d.Get("schema").(string), pq.Array(relkinds),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not list the objects of schema %s: %w", d.Get("schema").(string), err)
+	}
+	defer rows.Close()
+
+	objects := []string{}
+	for rows.Next() {
+		var object string
+		if err := rows.Scan(&object); err != nil {
+			return nil, fmt.Errorf("could not scan the objects of schema %s: %w", d.Get("schema").(string), err)
+		}
+		objects = append(objects, object)
 	}
+	return objects, rows.Err()
+}
+
+// batchedPrivilegesStatements returns the statements revoking all the privileges of the role on the objects,
+// and granting it the privileges of the resource if grant is true, by batches of at most batchSize objects.
+func batchedPrivilegesStatements(d *schema.ResourceData, objects []string, batchSize int, grant bool) []string {
+	objectType := strings.ToUpper(d.Get("object_type").(string))
+	schemaName := pq.QuoteIdentifier(d.Get("schema").(string))
+	role := quoteRoleName(d.Get("role").(string))
+	privileges := sortedSetStrings(d.Get("privileges").(*schema.Set))
+
+	statements := []string{}
+	for start := 0; start < len(objects); start += batchSize {
+		end := start + batchSize
+		if end > len(objects) {
+			end = len(objects)
+		}
+
+		quotedObjects := make([]string, 0, end-start)
+		for _, object := range objects[start:end] {
+			quotedObjects = append(quotedObjects, schemaName+"."+pq.QuoteIdentifier(object))
+		}
+		objectList := strings.Join(quotedObjects, ",")
+
+		statements = append(statements, fmt.Sprintf("REVOKE ALL PRIVILEGES ON %s %s FROM %s", objectType, objectList, role))
+		if grant && len(privileges) > 0 {
+			query := fmt.Sprintf("GRANT %s ON %s %s TO %s", strings.Join(privileges, ","), objectType, objectList, role)
+			if d.Get("with_grant_option").(bool) {
+				query += " WITH GRANT OPTION"
+			}
+			statements = append(statements, query)
+		}
+	}
+	return statements
+}
+
+// checkSelfLockout checks, before the commit of the privileges changes, that the role used by the provider
+// can still connect to the database, as the next refresh of the resources of this database would fail otherwise.
+func checkSelfLockout(txn *sql.Tx, d *schema.ResourceData) error {
+	if d.Get("object_type").(string) != "database" {
+		return nil
+	}
+
+	database := d.Get("database").(string)
+
+	// The privileges of the login role are checked, the connections are not opened with the current role
+	var canConnect bool
+	if err := txn.QueryRow("SELECT has_database_privilege(session_user, $1, 'CONNECT')", database).Scan(&canConnect); err != nil {
+		return fmt.Errorf("could not check the CONNECT privilege on database %s: %w", database, err)
+	}
+	if canConnect {
+		return nil
+	}
+
+	if d.Get("prevent_self_lockout").(bool) {
+		return fmt.Errorf(
+			"the role used by the provider would lose the CONNECT privilege on database %s, set prevent_self_lockout to false to revoke it anyway",
+			database,
+		)
+	}
+	log.Printf("[WARN] the role used by the provider lost the CONNECT privilege on database %s", database)
+
 	return nil
 }
 
@@ -732,7 +1255,7 @@ func checkRoleDBSchemaExists(client *Client, d *schema.ResourceData) (bool, erro
 
 	pgSchema := d.Get("schema").(string)
 
-	if !sliceContainsStr([]string{"database", "foreign_data_wrapper", "foreign_server"}, d.Get("object_type").(string)) && pgSchema != "" {
+	if !sliceContainsStr([]string{"database", "foreign_data_wrapper", "foreign_server", "language"}, d.Get("object_type").(string)) && pgSchema != "" {
 		// Connect on this database to check if schema exists
 		dbTxn, err := startTransaction(client, database)
 		if err != nil {
@@ -754,11 +1277,148 @@ func checkRoleDBSchemaExists(client *Client, d *schema.ResourceData) (bool, erro
 	return true, nil
 }
 
+// checkGrantObjectsExist verifies that the objects targeted by the grant exist before granting on them,
+// as Postgres returns a generic error which does not help to find a missing dependency.
+// The transaction needs to be on the grant database.
+func checkGrantObjectsExist(txn *sql.Tx, d *schema.ResourceData) error {
+	database := d.Get("database").(string)
+	schemaName := d.Get("schema").(string)
+	objectType := d.Get("object_type").(string)
+	objects := d.Get("objects").(*schema.Set).List()
+
+	missingObjectError := func(kind, name string) error {
+		return fmt.Errorf(
+			"%s %s does not exist in database %s, if it is managed by Terraform make sure this grant depends on it (e.g.: with `depends_on`)",
+			kind, name, database,
+		)
+	}
+
+	switch objectType {
+	case "database":
+		return nil
+	case "foreign_data_wrapper":
+		for _, object := range objects {
+			exists, err := grantObjectExists(txn, "SELECT 1 FROM pg_catalog.pg_foreign_data_wrapper WHERE fdwname = $1", object)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return missingObjectError("foreign data wrapper", object.(string))
+			}
+		}
+		return nil
+	case "foreign_server":
+		for _, object := range objects {
+			exists, err := grantObjectExists(txn, "SELECT 1 FROM pg_catalog.pg_foreign_server WHERE srvname = $1", object)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return missingObjectError("foreign server", object.(string))
+			}
+		}
+		return nil
+	case "language":
+		for _, object := range objects {
+			exists, err := grantObjectExists(txn, "SELECT 1 FROM pg_catalog.pg_language WHERE lanname = $1", object)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return missingObjectError("language", object.(string))
+			}
+		}
+		return nil
+	}
+
+	exists, err := schemaExists(txn, schemaName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return missingObjectError("schema", schemaName)
+	}
+
+	for _, raw := range objects {
+		object := raw.(string)
+		var query string
+
+		switch objectType {
+		case "table", "column":
+			query = `SELECT 1 FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+				`WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('r', 'v', 'm', 'f', 'p')`
+		case "sequence":
+			query = `SELECT 1 FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+				`WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind = 'S'`
+		case "type":
+			// Domains and standalone composite types, the row types of tables cannot be granted
+			query = `SELECT 1 FROM pg_catalog.pg_type t JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace ` +
+				`LEFT JOIN pg_catalog.pg_class c ON c.oid = t.typrelid ` +
+				`WHERE n.nspname = $1 AND t.typname = $2 AND (c.oid IS NULL OR c.relkind = 'c')`
+		case "function", "procedure", "routine":
+			// Functions can be specified with their arguments (e.g.: "test(text, char)"),
+			// we only check a function with this name exists.
+			object = strings.TrimSpace(strings.Split(object, "(")[0])
+			query = `SELECT 1 FROM pg_catalog.pg_proc p JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace ` +
+				`WHERE n.nspname = $1 AND p.proname = $2`
+		default:
+			continue
+		}
+
+		exists, err := grantObjectExists(txn, query, schemaName, object)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			kind := objectType
+			if objectType == "column" {
+				kind = "table"
+			}
+			return missingObjectError(kind, fmt.Sprintf("%s.%s", schemaName, object))
+		}
+	}
+
+	if objectType == "column" && len(objects) == 1 {
+		tableName := objects[0].(string)
+		for _, column := range d.Get("columns").(*schema.Set).List() {
+			exists, err := grantObjectExists(
+				txn,
+				`SELECT 1 FROM pg_catalog.pg_attribute a `+
+					`JOIN pg_catalog.pg_class c ON c.oid = a.attrelid `+
+					`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+					`WHERE n.nspname = $1 AND c.relname = $2 AND a.attname = $3 AND a.attnum > 0 AND NOT a.attisdropped`,
+				schemaName, tableName, column,
+			)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return missingObjectError("column", fmt.Sprintf("%s.%s.%s", schemaName, tableName, column.(string)))
+			}
+		}
+	}
+
+	return nil
+}
+
+func grantObjectExists(txn *sql.Tx, query string, args ...interface{}) (bool, error) {
+	var _rez int
+	err := txn.QueryRow(query, args...).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if grant object exists: %w", err)
+	}
+
+	return true, nil
+}
+
 func generateGrantID(d *schema.ResourceData) string {
 	parts := []string{d.Get("role").(string), d.Get("database").(string)}
 
 	objectType := d.Get("object_type").(string)
-	if objectType != "database" && objectType != "foreign_data_wrapper" && objectType != "foreign_server" {
+	if objectType != "database" && objectType != "foreign_data_wrapper" && objectType != "foreign_server" && objectType != "language" {
 		parts = append(parts, d.Get("schema").(string))
 	}
 	parts = append(parts, objectType)
@@ -781,15 +1441,20 @@ func getRolesToGrant(txn *sql.Tx, d *schema.ResourceData) ([]string, error) {
 	owners := []string{}
 	objectType := d.Get("object_type")
 
-	if objectType == "database" || objectType == "foreign_data_wrapper" || objectType == "foreign_server" {
+	if objectType == "database" || objectType == "foreign_data_wrapper" || objectType == "foreign_server" || objectType == "language" {
 		return owners, nil
 	}
 
 	schemaName := d.Get("schema").(string)
 
 	if objectType != "schema" {
+		getOwners := getTablesOwner
+		if objectType == "type" {
+			getOwners = getTypesOwner
+		}
+
 		var err error
-		owners, err = getTablesOwner(txn, schemaName)
+		owners, err = getOwners(txn, schemaName)
 		if err != nil {
 			return nil, err
 		}
@@ -806,24 +1471,160 @@ func getRolesToGrant(txn *sql.Tx, d *schema.ResourceData) ([]string, error) {
 	return owners, nil
 }
 
-func validateFeatureSupport(db *DBConnection, d *schema.ResourceData) error {
+func validateFeatureSupport(db *DBConnection, objectType string) error {
 	if !db.featureSupported(featurePrivileges) {
 		return fmt.Errorf(
-			"postgresql_grant resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_grant resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
-	if d.Get("object_type") == "procedure" && !db.featureSupported(featureProcedure) {
+	if objectType == "procedure" && !db.featureSupported(featureProcedure) {
 		return fmt.Errorf(
-			"object type PROCEDURE is not supported for this Postgres version (%s)",
-			db.version,
+			"object type PROCEDURE is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
-	if d.Get("object_type") == "routine" && !db.featureSupported(featureRoutine) {
+	if objectType == "routine" && !db.featureSupported(featureRoutine) {
 		return fmt.Errorf(
-			"object type ROUTINE is not supported for this Postgres version (%s)",
-			db.version,
+			"object type ROUTINE is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 	return nil
 }
+
+// grantorExists filters the ACL entries (from aclexplode) whose grantor still exists.
+// PostgreSQL keeps the entries of a grantor removed without revoking its grants, for example
+// by editing the catalogs or restoring a dump, the grantor being then only known by its OID.
+// These privileges are not counted as granted, so they are granted again by the connected user.
+const grantorExists = "grantor IN (SELECT oid FROM pg_catalog.pg_roles)"
+
+// orphanedGrant is a privilege granted to the role by a grantor which no longer exists.
+type orphanedGrant struct {
+	object     string
+	privilege  string
+	grantorOID int
+}
+
+// readOrphanedGrants returns the privileges granted to the role on the objects of the resource
+// by grantors which no longer exist.
+func readOrphanedGrants(txn *sql.Tx, d *schema.ResourceData, roleOID int) ([]orphanedGrant, error) {
+	objectType := d.Get("object_type").(string)
+
+	var rows *sql.Rows
+	var err error
+	switch objectType {
+	case "database":
+		rows, err = txn.Query(`
+SELECT datname, acl.privilege_type, acl.grantor
+FROM pg_catalog.pg_database, aclexplode(datacl) acl
+WHERE datname = $2 AND acl.grantee = $1 AND NOT `+grantorExists,
+			roleOID, d.Get("database"),
+		)
+	case "schema":
+		rows, err = txn.Query(`
+SELECT nspname, acl.privilege_type, acl.grantor
+FROM pg_catalog.pg_namespace, aclexplode(nspacl) acl
+WHERE nspname = $2 AND acl.grantee = $1 AND NOT `+grantorExists,
+			roleOID, d.Get("schema"),
+		)
+	case "function", "procedure", "routine":
+		rows, err = txn.Query(`
+SELECT proname, acl.privilege_type, acl.grantor
+FROM pg_catalog.pg_proc
+JOIN pg_catalog.pg_namespace ON pg_namespace.oid = pg_proc.pronamespace, aclexplode(proacl) acl
+WHERE nspname = $2 AND acl.grantee = $1 AND NOT `+grantorExists,
+			roleOID, d.Get("schema"),
+		)
+	case "type":
+		rows, err = txn.Query(`
+SELECT typname, acl.privilege_type, acl.grantor
+FROM pg_catalog.pg_type
+JOIN pg_catalog.pg_namespace ON pg_namespace.oid = pg_type.typnamespace, aclexplode(typacl) acl
+WHERE nspname = $2 AND acl.grantee = $1 AND NOT `+grantorExists,
+			roleOID, d.Get("schema"),
+		)
+	case "table", "sequence":
+		rows, err = txn.Query(`
+SELECT relname, acl.privilege_type, acl.grantor
+FROM pg_catalog.pg_class
+JOIN pg_catalog.pg_namespace ON pg_namespace.oid = pg_class.relnamespace, aclexplode(relacl) acl
+WHERE nspname = $2 AND relkind = $3 AND acl.grantee = $1 AND NOT `+grantorExists,
+			roleOID, d.Get("schema"), objectTypes[objectType],
+		)
+	case "foreign_data_wrapper":
+		rows, err = txn.Query(`
+SELECT fdwname, acl.privilege_type, acl.grantor
+FROM pg_catalog.pg_foreign_data_wrapper, aclexplode(fdwacl) acl
+WHERE acl.grantee = $1 AND NOT `+grantorExists,
+			roleOID,
+		)
+	case "foreign_server":
+		rows, err = txn.Query(`
+SELECT srvname, acl.privilege_type, acl.grantor
+FROM pg_catalog.pg_foreign_server, aclexplode(srvacl) acl
+WHERE acl.grantee = $1 AND NOT `+grantorExists,
+			roleOID,
+		)
+	case "language":
+		rows, err = txn.Query(`
+SELECT lanname, acl.privilege_type, acl.grantor
+FROM pg_catalog.pg_language, aclexplode(lanacl) acl
+WHERE acl.grantee = $1 AND NOT `+grantorExists,
+			roleOID,
+		)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the privileges granted by removed roles: %w", err)
+	}
+	defer rows.Close()
+
+	objects := d.Get("objects").(*schema.Set)
+	var orphaned []orphanedGrant
+	for rows.Next() {
+		var grant orphanedGrant
+		if err := rows.Scan(&grant.object, &grant.privilege, &grant.grantorOID); err != nil {
+			return nil, err
+		}
+		if objectType != "database" && objectType != "schema" && objects.Len() > 0 && !objects.Contains(grant.object) {
+			continue
+		}
+		orphaned = append(orphaned, grant)
+	}
+	return orphaned, rows.Err()
+}
+
+// orphanedGrantsMessage describes the orphaned grants, grouped by object and grantor.
+func orphanedGrantsMessage(objectType string, grants []orphanedGrant) string {
+	type key struct {
+		object     string
+		grantorOID int
+	}
+	privileges := make(map[key][]string)
+	var keys []key
+	for _, grant := range grants {
+		k := key{grant.object, grant.grantorOID}
+		if _, ok := privileges[k]; !ok {
+			keys = append(keys, k)
+		}
+		privileges[k] = append(privileges[k], grant.privilege)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].object != keys[j].object {
+			return keys[i].object < keys[j].object
+		}
+		return keys[i].grantorOID < keys[j].grantorOID
+	})
+
+	messages := make([]string, len(keys))
+	for i, k := range keys {
+		sort.Strings(privileges[k])
+		messages[i] = fmt.Sprintf(
+			"%s on %s %s by removed role with OID %d",
+			strings.Join(privileges[k], ", "), objectType, pq.QuoteIdentifier(k.object), k.grantorOID,
+		)
+	}
+	return strings.Join(messages, "; ")
+}
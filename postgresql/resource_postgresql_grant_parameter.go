@@ -0,0 +1,226 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	grantParameterRoleAttr            = "role"
+	grantParameterParameterAttr       = "parameter"
+	grantParameterPrivilegesAttr      = "privileges"
+	grantParameterWithGrantOptionAttr = "with_grant_option"
+)
+
+// parameterPrivileges are the privileges which can be granted on a configuration parameter
+var parameterPrivileges = []string{"SET", "ALTER SYSTEM"}
+
+func resourcePostgreSQLGrantParameter() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLGrantParameterCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLGrantParameterRead),
+		Delete: PGResourceFunc(resourcePostgreSQLGrantParameterDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePostgreSQLGrantParameterImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			grantParameterRoleAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the role to grant the privileges on the parameter to (public for all the roles)",
+			},
+			grantParameterParameterAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(settingNameRegexp, "must be the name of a configuration parameter"),
+				Description:  "The name of the configuration parameter",
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					// The parameter names are case insensitive
+					return strings.EqualFold(old, new)
+				},
+			},
+			grantParameterPrivilegesAttr: {
+				Type:     schema.TypeSet,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(parameterPrivileges, false),
+				},
+				Set:         schema.HashString,
+				Description: "The privileges to grant on the parameter: SET and/or ALTER SYSTEM",
+			},
+			grantParameterWithGrantOptionAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Permit the grant recipient to grant the privileges to others",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLGrantParameterCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureParameterPrivileges) {
+		return fmt.Errorf(
+			"postgresql_grant_parameter resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// Revoke the privileges before granting them again, so the privileges removed from
+	// the configuration and the grant option are not kept.
+	for _, query := range []string{createRevokeParameterQuery(d), createGrantParameterQuery(d)} {
+		if _, err := txn.Exec(query); err != nil {
+			return fmt.Errorf("could not grant privileges on parameter %s: %w", d.Get(grantParameterParameterAttr), err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateGrantParameterID(d))
+
+	return readGrantParameter(db, d)
+}
+
+func resourcePostgreSQLGrantParameterRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureParameterPrivileges) {
+		return fmt.Errorf(
+			"postgresql_grant_parameter resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	return readGrantParameter(db, d)
+}
+
+// resourcePostgreSQLGrantParameterImport imports the privileges on a parameter from an ID in the
+// format role|parameter, the privileges being read from pg_parameter_acl.
+func resourcePostgreSQLGrantParameterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "|")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid import ID %q: expected the format role|parameter", d.Id())
+	}
+
+	d.Set(grantParameterRoleAttr, parts[0])
+	d.Set(grantParameterParameterAttr, parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func readGrantParameter(db *DBConnection, d *schema.ResourceData) error {
+	role := d.Get(grantParameterRoleAttr).(string)
+	parameter := d.Get(grantParameterParameterAttr).(string)
+
+	roleOID, err := getRoleOID(db, role)
+	if err != nil {
+		return err
+	}
+
+	// pg_parameter_acl only has the parameters which have been granted privileges,
+	// their names being stored in lower case.
+	var privileges pq.ByteaArray
+	var withGrantOption bool
+	err = db.QueryRow(`
+SELECT array_agg(acl.privilege_type), bool_and(acl.is_grantable)
+FROM pg_catalog.pg_parameter_acl, aclexplode(paracl) acl
+WHERE parname = lower($1) AND acl.grantee = $2
+HAVING count(*) > 0`,
+		parameter, roleOID,
+	).Scan(&privileges, &withGrantOption)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL privileges of role %s on parameter %s not found", role, parameter)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read privileges of role %s on parameter %s: %w", role, parameter, err)
+	}
+
+	d.Set(grantParameterPrivilegesAttr, pgArrayToSet(privileges))
+	d.Set(grantParameterWithGrantOptionAttr, withGrantOption)
+	d.SetId(generateGrantParameterID(d))
+
+	return nil
+}
+
+func resourcePostgreSQLGrantParameterDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureParameterPrivileges) {
+		return fmt.Errorf(
+			"postgresql_grant_parameter resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(createRevokeParameterQuery(d)); err != nil {
+		return fmt.Errorf("could not revoke privileges on parameter %s: %w", d.Get(grantParameterParameterAttr), err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// quoteParameterName quotes the parts of a parameter name, the custom parameters being
+// prefixed by the name of their extension (e.g.: pg_stat_statements.track).
+func quoteParameterName(name string) string {
+	parts := strings.Split(strings.ToLower(name), ".")
+	for i, part := range parts {
+		parts[i] = pq.QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func createGrantParameterQuery(d *schema.ResourceData) string {
+	query := fmt.Sprintf(
+		"GRANT %s ON PARAMETER %s TO %s",
+		strings.Join(sortedSetStrings(d.Get(grantParameterPrivilegesAttr).(*schema.Set)), ", "),
+		quoteParameterName(d.Get(grantParameterParameterAttr).(string)),
+		quoteRoleName(d.Get(grantParameterRoleAttr).(string)),
+	)
+	if d.Get(grantParameterWithGrantOptionAttr).(bool) {
+		query += " WITH GRANT OPTION"
+	}
+	return query
+}
+
+func createRevokeParameterQuery(d *schema.ResourceData) string {
+	return fmt.Sprintf(
+		"REVOKE ALL ON PARAMETER %s FROM %s",
+		quoteParameterName(d.Get(grantParameterParameterAttr).(string)),
+		quoteRoleName(d.Get(grantParameterRoleAttr).(string)),
+	)
+}
+
+func generateGrantParameterID(d *schema.ResourceData) string {
+	return strings.Join([]string{d.Get(grantParameterRoleAttr).(string), d.Get(grantParameterParameterAttr).(string)}, "|")
+}
@@ -0,0 +1,144 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCreateGrantParameterQuery(t *testing.T) {
+	cases := []struct {
+		resource map[string]interface{}
+		grant    string
+		revoke   string
+	}{
+		{
+			resource: map[string]interface{}{
+				"role":       "app",
+				"parameter":  "work_mem",
+				"privileges": []interface{}{"SET"},
+			},
+			grant:  `GRANT SET ON PARAMETER "work_mem" TO "app"`,
+			revoke: `REVOKE ALL ON PARAMETER "work_mem" FROM "app"`,
+		},
+		{
+			resource: map[string]interface{}{
+				"role":              "public",
+				"parameter":         "Log_Min_Duration_Statement",
+				"privileges":        []interface{}{"SET", "ALTER SYSTEM"},
+				"with_grant_option": true,
+			},
+			grant:  `GRANT ALTER SYSTEM, SET ON PARAMETER "log_min_duration_statement" TO PUBLIC WITH GRANT OPTION`,
+			revoke: `REVOKE ALL ON PARAMETER "log_min_duration_statement" FROM PUBLIC`,
+		},
+		{
+			// The custom parameters are prefixed by the name of their extension
+			resource: map[string]interface{}{
+				"role":       "app",
+				"parameter":  "pg_stat_statements.track",
+				"privileges": []interface{}{"SET"},
+			},
+			grant:  `GRANT SET ON PARAMETER "pg_stat_statements"."track" TO "app"`,
+			revoke: `REVOKE ALL ON PARAMETER "pg_stat_statements"."track" FROM "app"`,
+		},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrantParameter().Schema, c.resource)
+		if out := createGrantParameterQuery(d); out != c.grant {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.grant)
+		}
+		if out := createRevokeParameterQuery(d); out != c.revoke {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.revoke)
+		}
+	}
+}
+
+func TestAccPostgresqlGrantParameter(t *testing.T) {
+	skipIfNotAcc(t)
+
+	_, roleName := getTestDBNames("grant_parameter")
+	teardown := createTestRole(t, roleName)
+	defer teardown()
+
+	config := `
+resource "postgresql_grant_parameter" "work_mem" {
+  role       = "%s"
+  parameter  = "work_mem"
+  privileges = %s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureParameterPrivileges)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGrantParameterPrivileges(roleName, "work_mem", ""),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, roleName, `["SET"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant_parameter.work_mem", "id", roleName+"|work_mem"),
+					resource.TestCheckResourceAttr("postgresql_grant_parameter.work_mem", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_grant_parameter.work_mem", "with_grant_option", "false"),
+					testAccCheckGrantParameterPrivileges(roleName, "work_mem", "SET"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_grant_parameter.work_mem",
+				ImportState:       true,
+				ImportStateId:     roleName + "|work_mem",
+				ImportStateVerify: true,
+			},
+			{
+				Config: fmt.Sprintf(config, roleName, `["ALTER SYSTEM", "SET"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant_parameter.work_mem", "privileges.#", "2"),
+					testAccCheckGrantParameterPrivileges(roleName, "work_mem", "ALTER SYSTEM,SET"),
+				),
+			},
+			{
+				// A privilege revoked outside of Terraform is granted again
+				PreConfig: func() {
+					testConfig := getTestConfig(t)
+					dbExecute(t, testConfig.connStr("postgres"), fmt.Sprintf("REVOKE ALTER SYSTEM ON PARAMETER work_mem FROM %s", roleName))
+				},
+				Config: fmt.Sprintf(config, roleName, `["ALTER SYSTEM", "SET"]`),
+				Check:  testAccCheckGrantParameterPrivileges(roleName, "work_mem", "ALTER SYSTEM,SET"),
+			},
+		},
+	})
+}
+
+// testAccCheckGrantParameterPrivileges checks the privileges granted to the role on the parameter,
+// comma separated and sorted (empty if none).
+func testAccCheckGrantParameterPrivileges(roleName, parameter, expected string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var privileges string
+		err = db.QueryRow(`
+SELECT COALESCE(string_agg(acl.privilege_type, ',' ORDER BY acl.privilege_type), '')
+FROM pg_catalog.pg_parameter_acl, aclexplode(paracl) acl
+WHERE parname = $1 AND acl.grantee = (SELECT oid FROM pg_roles WHERE rolname = $2)`,
+			parameter, roleName,
+		).Scan(&privileges)
+		if err != nil {
+			return fmt.Errorf("could not read privileges on parameter %s: %w", parameter, err)
+		}
+		if privileges != expected {
+			return fmt.Errorf("expected privileges %q on parameter %s for role %s, got %q", expected, parameter, roleName, privileges)
+		}
+		return nil
+	}
+}
@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -31,6 +32,9 @@ func resourcePostgreSQLGrantRole() *schema.Resource {
 		Create: PGResourceFunc(resourcePostgreSQLGrantRoleCreate),
 		Read:   PGResourceFunc(resourcePostgreSQLGrantRoleRead),
 		Delete: PGResourceFunc(resourcePostgreSQLGrantRoleDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePostgreSQLGrantRoleImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"role": {
@@ -59,19 +63,33 @@ func resourcePostgreSQLGrantRole() *schema.Resource {
 func resourcePostgreSQLGrantRoleRead(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featurePrivileges) {
 		return fmt.Errorf(
-			"postgresql_grant_role resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_grant_role resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
 	return readGrantRole(db, d)
 }
 
+// resourcePostgreSQLGrantRoleImport imports a role membership from an ID in the format role|grant_role,
+// with_admin_option is read from the catalog.
+func resourcePostgreSQLGrantRoleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "|")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid import ID %q: expected the format role|grant_role", d.Id())
+	}
+
+	d.Set("role", parts[0])
+	d.Set("grant_role", parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourcePostgreSQLGrantRoleCreate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featurePrivileges) {
 		return fmt.Errorf(
-			"postgresql_grant_role resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_grant_role resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -102,8 +120,8 @@ func resourcePostgreSQLGrantRoleCreate(db *DBConnection, d *schema.ResourceData)
 func resourcePostgreSQLGrantRoleDelete(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featurePrivileges) {
 		return fmt.Errorf(
-			"postgresql_grant_role resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_grant_role resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -121,7 +121,8 @@ func TestAccPostgresqlGrantRole(t *testing.T) {
 			testAccPreCheck(t)
 			testCheckCompatibleVersion(t, featurePrivileges)
 		},
-		Providers: testAccProviders,
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlGrantRoleDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccPostgresqlGrantRoleResources,
@@ -139,6 +140,12 @@ func TestAccPostgresqlGrantRole(t *testing.T) {
 	})
 }
 
+// testAccCheckPostgresqlGrantRoleDestroy checks the roles are no longer members of the granted roles.
+var testAccCheckPostgresqlGrantRoleDestroy = testAccCheckResourcesDestroyed("postgresql_grant_role", func(rs *terraform.ResourceState) (string, string, []interface{}) {
+	return "", "SELECT TRUE FROM pg_catalog.pg_auth_members WHERE pg_get_userbyid(member) = $1 AND pg_get_userbyid(roleid) = $2",
+		[]interface{}{rs.Primary.Attributes["role"], rs.Primary.Attributes["grant_role"]}
+})
+
 func checkGrantRole(t *testing.T, dsn, role string, grantRole string, withAdmin bool) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		db, err := sql.Open("postgres", dsn)
@@ -0,0 +1,379 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	grantSchemaObjectsDatabaseAttr        = "database"
+	grantSchemaObjectsRoleAttr            = "role"
+	grantSchemaObjectsSchemaAttr          = "schema"
+	grantSchemaObjectsWithGrantOptionAttr = "with_grant_option"
+)
+
+// grantSchemaObjectsType describes an object type whose privileges are granted on all the objects of the schema.
+type grantSchemaObjectsType struct {
+	// attr is the attribute with the privileges of the object type
+	attr string
+	// objectType is the object type in allowedPrivileges
+	objectType string
+	// keyword is the object type in the GRANT ... ON ALL ... IN SCHEMA statements
+	keyword string
+}
+
+var grantSchemaObjectsTypes = []grantSchemaObjectsType{
+	{attr: "table_privileges", objectType: "table", keyword: "TABLES"},
+	{attr: "sequence_privileges", objectType: "sequence", keyword: "SEQUENCES"},
+	{attr: "function_privileges", objectType: "function", keyword: "FUNCTIONS"},
+}
+
+func resourcePostgreSQLGrantSchemaObjects() *schema.Resource {
+	privilegesAttrs := make([]string, len(grantSchemaObjectsTypes))
+	for i, objectsType := range grantSchemaObjectsTypes {
+		privilegesAttrs[i] = objectsType.attr
+	}
+
+	resourceSchema := map[string]*schema.Schema{
+		grantSchemaObjectsDatabaseAttr: {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The database of the schema",
+		},
+		grantSchemaObjectsRoleAttr: {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The name of the role to grant privileges on, public for all roles",
+		},
+		grantSchemaObjectsSchemaAttr: {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The schema whose objects are granted",
+		},
+		grantSchemaObjectsWithGrantOptionAttr: {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Permit the grant recipient to grant the privileges to others",
+		},
+	}
+	for _, objectsType := range grantSchemaObjectsTypes {
+		resourceSchema[objectsType.attr] = &schema.Schema{
+			Type:         schema.TypeSet,
+			Optional:     true,
+			Elem:         &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice(allowedPrivileges[objectsType.objectType], false)},
+			Set:          schema.HashString,
+			AtLeastOneOf: privilegesAttrs,
+			Description:  fmt.Sprintf("The privileges to grant on all the existing %s of the schema", strings.ToLower(objectsType.keyword)),
+		}
+	}
+
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLGrantSchemaObjectsCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLGrantSchemaObjectsRead),
+		Update: PGResourceFunc(resourcePostgreSQLGrantSchemaObjectsUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLGrantSchemaObjectsDelete),
+
+		Schema: resourceSchema,
+	}
+}
+
+func resourcePostgreSQLGrantSchemaObjectsCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featurePrivileges) {
+		return fmt.Errorf(
+			"postgresql_grant_schema_objects resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	// Only the object types with privileges are granted, the privileges on the other types are left untouched
+	objectsTypes := []grantSchemaObjectsType{}
+	for _, objectsType := range grantSchemaObjectsTypes {
+		if d.Get(objectsType.attr).(*schema.Set).Len() > 0 {
+			objectsTypes = append(objectsTypes, objectsType)
+		}
+	}
+
+	if err := grantSchemaObjects(db, d, objectsTypes, false); err != nil {
+		return err
+	}
+
+	d.SetId(generateGrantSchemaObjectsID(d))
+
+	return resourcePostgreSQLGrantSchemaObjectsReadImpl(db, d)
+}
+
+func resourcePostgreSQLGrantSchemaObjectsRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featurePrivileges) {
+		return fmt.Errorf(
+			"postgresql_grant_schema_objects resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	return resourcePostgreSQLGrantSchemaObjectsReadImpl(db, d)
+}
+
+func resourcePostgreSQLGrantSchemaObjectsReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get(grantSchemaObjectsDatabaseAttr).(string)
+	role := d.Get(grantSchemaObjectsRoleAttr).(string)
+	schemaName := d.Get(grantSchemaObjectsSchemaAttr).(string)
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing the grant of schema %s objects from state", database, schemaName)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if role != publicRole {
+		exists, err := roleExists(txn, role)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			log.Printf("[WARN] PostgreSQL role (%s) not found, removing the grant of schema %s objects from state", role, schemaName)
+			d.SetId("")
+			return nil
+		}
+	}
+
+	exists, err = schemaExists(txn, schemaName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL schema (%s) not found in database %s, removing its objects grant from state", schemaName, database)
+		d.SetId("")
+		return nil
+	}
+
+	roleOID, err := getRoleOID(txn, role)
+	if err != nil {
+		return err
+	}
+
+	for _, objectsType := range grantSchemaObjectsTypes {
+		// The privileges on the object types which are not configured are not managed
+		if d.Get(objectsType.attr).(*schema.Set).Len() == 0 {
+			continue
+		}
+
+		privileges, err := readSchemaObjectsPrivileges(db, txn, objectsType, roleOID, schemaName)
+		if err != nil {
+			return err
+		}
+		// No object to read the privileges from, they will be granted on the next objects by the next apply
+		if privileges == nil {
+			continue
+		}
+		d.Set(objectsType.attr, normalizeAllPrivileges(objectsType.objectType, d.Get(objectsType.attr).(*schema.Set), privileges))
+	}
+
+	d.SetId(generateGrantSchemaObjectsID(d))
+
+	return nil
+}
+
+// readSchemaObjectsPrivileges returns the privileges of the role granted on all the objects of the type
+// in the schema, or nil if there is no object of this type.
+func readSchemaObjectsPrivileges(db *DBConnection, txn *sql.Tx, objectsType grantSchemaObjectsType, roleOID int, schemaName string) (*schema.Set, error) {
+	var query string
+	switch objectsType.objectType {
+	case "function":
+		// ALL FUNCTIONS does not include the procedures
+		procedureFilter := ""
+		if db.featureSupported(featureProcedure) {
+			procedureFilter = "AND p.prokind <> 'p'"
+		}
+		query = fmt.Sprintf(`
+SELECT array_remove(array_agg(privs.privilege_type), NULL)
+FROM pg_catalog.pg_proc p
+JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+LEFT JOIN LATERAL (SELECT * FROM aclexplode(%s)) privs ON privs.grantee = $1
+WHERE n.nspname = $2 %s
+GROUP BY p.oid
+`, aclWithDefault(roleOID, "p.proacl", "f", "p.proowner"), procedureFilter)
+	default:
+		// ALL TABLES also includes the views, materialized views, foreign and partitioned tables
+		relkinds := "'r', 'v', 'm', 'f', 'p'"
+		if objectsType.objectType == "sequence" {
+			relkinds = "'S'"
+		}
+		query = fmt.Sprintf(`
+SELECT array_remove(array_agg(privs.privilege_type), NULL)
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN LATERAL (SELECT * FROM aclexplode(c.relacl)) privs ON privs.grantee = $1
+WHERE n.nspname = $2 AND c.relkind IN (%s)
+GROUP BY c.oid
+`, relkinds)
+	}
+
+	rows, err := txn.Query(query, roleOID, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read privileges on %s of schema %s: %w", strings.ToLower(objectsType.keyword), schemaName, err)
+	}
+	defer rows.Close()
+
+	// Only the privileges granted on every object are returned
+	var privileges *schema.Set
+	for rows.Next() {
+		var objectPrivileges pq.ByteaArray
+		if err := rows.Scan(&objectPrivileges); err != nil {
+			return nil, err
+		}
+
+		if privileges == nil {
+			privileges = pgArrayToSet(objectPrivileges)
+		} else {
+			privileges = privileges.Intersection(pgArrayToSet(objectPrivileges))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return privileges, nil
+}
+
+func resourcePostgreSQLGrantSchemaObjectsUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featurePrivileges) {
+		return fmt.Errorf(
+			"postgresql_grant_schema_objects resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	// The object types whose privileges were removed from the configuration are revoked
+	objectsTypes := []grantSchemaObjectsType{}
+	for _, objectsType := range grantSchemaObjectsTypes {
+		if d.HasChange(objectsType.attr) || d.Get(objectsType.attr).(*schema.Set).Len() > 0 {
+			objectsTypes = append(objectsTypes, objectsType)
+		}
+	}
+
+	if err := grantSchemaObjects(db, d, objectsTypes, false); err != nil {
+		return err
+	}
+
+	return resourcePostgreSQLGrantSchemaObjectsReadImpl(db, d)
+}
+
+func resourcePostgreSQLGrantSchemaObjectsDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featurePrivileges) {
+		return fmt.Errorf(
+			"postgresql_grant_schema_objects resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	objectsTypes := []grantSchemaObjectsType{}
+	for _, objectsType := range grantSchemaObjectsTypes {
+		if d.Get(objectsType.attr).(*schema.Set).Len() > 0 {
+			objectsTypes = append(objectsTypes, objectsType)
+		}
+	}
+
+	if err := grantSchemaObjects(db, d, objectsTypes, true); err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// grantSchemaObjects revokes the privileges of the role on all the objects of the types in the schema,
+// then grants the configured ones unless revokeOnly is set, in a single transaction.
+func grantSchemaObjects(db *DBConnection, d *schema.ResourceData, objectsTypes []grantSchemaObjectsType, revokeOnly bool) error {
+	role := d.Get(grantSchemaObjectsRoleAttr).(string)
+	schemaName := d.Get(grantSchemaObjectsSchemaAttr).(string)
+
+	txn, err := startTransaction(db.client, d.Get(grantSchemaObjectsDatabaseAttr).(string))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := pgLockRole(txn, role); err != nil {
+		return err
+	}
+
+	// If the user used by Terraform is not a superuser, it needs to be a member of
+	// the owners of the schema and of its tables to change their privileges.
+	owners, err := getTablesOwner(txn, schemaName)
+	if err != nil {
+		return err
+	}
+	schemaOwner, err := getSchemaOwner(txn, schemaName)
+	if err != nil {
+		return err
+	}
+	if !sliceContainsStr(owners, schemaOwner) {
+		owners = append(owners, schemaOwner)
+	}
+
+	if err := withRolesGranted(txn, owners, func() error {
+		for _, objectsType := range objectsTypes {
+			// Revoke all privileges before granting otherwise reducing privileges will not work.
+			if _, err := txn.Exec(fmt.Sprintf(
+				"REVOKE ALL PRIVILEGES ON ALL %s IN SCHEMA %s FROM %s",
+				objectsType.keyword, pq.QuoteIdentifier(schemaName), quoteRoleName(role),
+			)); err != nil {
+				return fmt.Errorf("could not revoke privileges on %s of schema %s: %w", strings.ToLower(objectsType.keyword), schemaName, err)
+			}
+
+			privileges := d.Get(objectsType.attr).(*schema.Set)
+			if revokeOnly || privileges.Len() == 0 {
+				continue
+			}
+
+			query := fmt.Sprintf(
+				"GRANT %s ON ALL %s IN SCHEMA %s TO %s",
+				setToPgIdentSimpleList(privileges), objectsType.keyword, pq.QuoteIdentifier(schemaName), quoteRoleName(role),
+			)
+			if d.Get(grantSchemaObjectsWithGrantOptionAttr).(bool) {
+				query += " WITH GRANT OPTION"
+			}
+			if _, err := txn.Exec(query); err != nil {
+				return fmt.Errorf("could not grant privileges on %s of schema %s: %w", strings.ToLower(objectsType.keyword), schemaName, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func generateGrantSchemaObjectsID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get(grantSchemaObjectsRoleAttr).(string),
+		d.Get(grantSchemaObjectsDatabaseAttr).(string),
+		d.Get(grantSchemaObjectsSchemaAttr).(string),
+	}, "_")
+}
@@ -0,0 +1,84 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlGrantSchemaObjects(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE SEQUENCE test_schema.test_seq")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		CheckDestroy: func(*terraform.State) error {
+			return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{})
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_grant_schema_objects" "test" {
+	database            = "%s"
+	role                = "%s"
+	schema              = "test_schema"
+	table_privileges    = ["SELECT"]
+	sequence_privileges = ["USAGE"]
+}
+`, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant_schema_objects.test", "id", fmt.Sprintf("%s_%s_test_schema", roleName, dbName)),
+					resource.TestCheckResourceAttr("postgresql_grant_schema_objects.test", "table_privileges.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_grant_schema_objects.test", "sequence_privileges.#", "1"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT"})
+					},
+					testCheckSequenceUsage(t, dbName, roleName, "test_schema.test_seq", true),
+				),
+			},
+			{
+				// The privileges on the sequences are revoked when they are removed from the configuration
+				Config: fmt.Sprintf(`
+resource "postgresql_grant_schema_objects" "test" {
+	database         = "%s"
+	role             = "%s"
+	schema           = "test_schema"
+	table_privileges = ["SELECT", "INSERT"]
+}
+`, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant_schema_objects.test", "table_privileges.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_grant_schema_objects.test", "sequence_privileges.#", "0"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT", "INSERT"})
+					},
+					testCheckSequenceUsage(t, dbName, roleName, "test_schema.test_seq", false),
+				),
+			},
+		},
+	})
+}
+
+func testCheckSequenceUsage(t *testing.T, dbName, roleName, sequence string, usage bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		db := connectAsTestRole(t, roleName, dbName)
+		defer db.Close()
+
+		return testHasGrantForQuery(db, fmt.Sprintf("SELECT nextval('%s')", sequence), usage)
+	}
+}
@@ -1,10 +1,13 @@
 package postgresql
 
 import (
+	"database/sql"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -33,6 +36,16 @@ func TestCreateGrantQuery(t *testing.T) {
 			privileges: []string{"SELECT"},
 			expected:   fmt.Sprintf("GRANT SELECT ON ALL TABLES IN SCHEMA %s TO %s", pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "table",
+				"objects":     []interface{}{"o1"},
+				"schema":      databaseName,
+				"role":        "public",
+			}),
+			privileges: []string{"SELECT"},
+			expected:   fmt.Sprintf(`GRANT SELECT ON TABLE %s."o1" TO PUBLIC`, pq.QuoteIdentifier(databaseName)),
+		},
 		{
 			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
 				"object_type": "sequence",
@@ -115,7 +128,7 @@ func TestCreateGrantQuery(t *testing.T) {
 				"role":        roleName,
 			}),
 			privileges: []string{"SELECT"},
-			expected:   fmt.Sprintf(`GRANT SELECT ON TABLE %[1]s."o2",%[1]s."o1" TO %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+			expected:   fmt.Sprintf(`GRANT SELECT ON TABLE %[1]s."o1",%[1]s."o2" TO %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
 		},
 		{
 			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
@@ -126,7 +139,7 @@ func TestCreateGrantQuery(t *testing.T) {
 				"role":        roleName,
 			}),
 			privileges: []string{"SELECT"},
-			expected:   fmt.Sprintf(`GRANT SELECT (%[2]s,%[3]s) ON TABLE %[1]s."o1" TO %[4]s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier("col2"), pq.QuoteIdentifier("col1"), pq.QuoteIdentifier(roleName)),
+			expected:   fmt.Sprintf(`GRANT SELECT (%[2]s,%[3]s) ON TABLE %[1]s."o1" TO %[4]s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier("col1"), pq.QuoteIdentifier("col2"), pq.QuoteIdentifier(roleName)),
 		},
 		{
 			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
@@ -166,6 +179,25 @@ func TestCreateGrantQuery(t *testing.T) {
 			privileges: []string{"ALL PRIVILEGES"},
 			expected:   fmt.Sprintf(`GRANT ALL PRIVILEGES ON FOREIGN SERVER "baz" TO %s WITH GRANT OPTION`, pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "language",
+				"objects":     []interface{}{"plperl"},
+				"role":        roleName,
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf(`GRANT USAGE ON LANGUAGE "plperl" TO %s`, pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "type",
+				"schema":      databaseName,
+				"objects":     []interface{}{"geo"},
+				"role":        roleName,
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf(`GRANT USAGE ON TYPE %s."geo" TO %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
 	}
 
 	for _, c := range cases {
@@ -195,6 +227,14 @@ func TestCreateRevokeQuery(t *testing.T) {
 			}),
 			expected: fmt.Sprintf("REVOKE ALL PRIVILEGES ON ALL TABLES IN SCHEMA %s FROM %s", pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "database",
+				"database":    databaseName,
+				"role":        "public",
+			}),
+			expected: fmt.Sprintf("REVOKE ALL PRIVILEGES ON DATABASE %s FROM PUBLIC", pq.QuoteIdentifier(databaseName)),
+		},
 		{
 			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
 				"object_type": "sequence",
@@ -250,7 +290,7 @@ func TestCreateRevokeQuery(t *testing.T) {
 				"schema":      databaseName,
 				"role":        roleName,
 			}),
-			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON TABLE %[1]s."o2",%[1]s."o1" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON TABLE %[1]s."o1",%[1]s."o2" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
 		},
 		{
 			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
@@ -260,7 +300,7 @@ func TestCreateRevokeQuery(t *testing.T) {
 				"role":        roleName,
 				"privileges":  []interface{}{"INSERT", "UPDATE"},
 			}),
-			expected: fmt.Sprintf(`REVOKE UPDATE,INSERT ON TABLE %[1]s."o2",%[1]s."o1" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+			expected: fmt.Sprintf(`REVOKE INSERT,UPDATE ON TABLE %[1]s."o1",%[1]s."o2" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
 		},
 		{
 			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
@@ -271,7 +311,7 @@ func TestCreateRevokeQuery(t *testing.T) {
 				"role":        roleName,
 				"privileges":  []interface{}{"SELECT"},
 			}),
-			expected: fmt.Sprintf(`REVOKE SELECT ("col2","col1") ON TABLE %[1]s."o1" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+			expected: fmt.Sprintf(`REVOKE SELECT ("col1","col2") ON TABLE %[1]s."o1" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
 		},
 		{
 			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
@@ -289,6 +329,23 @@ func TestCreateRevokeQuery(t *testing.T) {
 			}),
 			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON FOREIGN SERVER "baz" FROM %s`, pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "language",
+				"objects":     []interface{}{"plperl"},
+				"role":        roleName,
+			}),
+			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON LANGUAGE "plperl" FROM %s`, pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "type",
+				"schema":      databaseName,
+				"objects":     []interface{}{"geo"},
+				"role":        roleName,
+			}),
+			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON TYPE %s."geo" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
 	}
 
 	for _, c := range cases {
@@ -299,6 +356,192 @@ func TestCreateRevokeQuery(t *testing.T) {
 	}
 }
 
+func TestParseGrantImportID(t *testing.T) {
+	valid := map[string]grantImportID{
+		"role|database|db": {
+			role: "role", objectType: "database", database: "db", objects: []string{}, columns: []string{},
+		},
+		"my_role|table|my_db|my_schema": {
+			role: "my_role", objectType: "table", database: "my_db", schema: "my_schema", objects: []string{}, columns: []string{},
+		},
+		"role|sequence|db|schema|seq_1,seq_2": {
+			role: "role", objectType: "sequence", database: "db", schema: "schema", objects: []string{"seq_1", "seq_2"}, columns: []string{},
+		},
+		"role|foreign_server|db||srv": {
+			role: "role", objectType: "foreign_server", database: "db", objects: []string{"srv"}, columns: []string{},
+		},
+		"role|language|db||plperl": {
+			role: "role", objectType: "language", database: "db", objects: []string{"plperl"}, columns: []string{},
+		},
+		"role|column|db|schema|table|col_1,col_2": {
+			role: "role", objectType: "column", database: "db", schema: "schema", objects: []string{"table"}, columns: []string{"col_1", "col_2"},
+		},
+		"role|type|db|schema|geo,positive_int": {
+			role: "role", objectType: "type", database: "db", schema: "schema", objects: []string{"geo", "positive_int"}, columns: []string{},
+		},
+	}
+	for id, expected := range valid {
+		grant, err := parseGrantImportID(id)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", id, err)
+			continue
+		}
+		if !reflect.DeepEqual(grant, expected) {
+			t.Errorf("parseGrantImportID(%q): expected %+v, got %+v", id, expected, grant)
+		}
+	}
+
+	invalid := map[string]string{
+		"role_db_schema_table":          "expected the format",
+		"role|table|db|schema|a|b|c":    "expected the format",
+		"|table|db|schema":              "role is empty",
+		"role|table||schema":            "database is empty",
+		"role|view|db|schema":           `object type "view" is not one of`,
+		"role|table|db":                 "schema is required for object type table",
+		"role|database|db|schema":       "schema and objects can not be set for object type database",
+		"role|schema|db|schema|obj":     "objects can not be set for object type schema",
+		"role|foreign_server|db":        "exactly one object is required for object type foreign_server",
+		"role|language|db|schema|lang":  "schema can not be set for object type language",
+		"role|column|db|schema|table":   "columns are required for object type column",
+		"role|table|db|schema|tbl|col":  "columns can only be set for object type column",
+		"role|table|db|schema|tbl,,tbl": "objects and columns can not be empty",
+		"role|type|db|schema":           "objects are required for object type type",
+	}
+	for id, message := range invalid {
+		_, err := parseGrantImportID(id)
+		if err == nil {
+			t.Errorf("expected an error for %q", id)
+			continue
+		}
+		if !strings.Contains(err.Error(), message) {
+			t.Errorf("parseGrantImportID(%q): expected error containing %q, got %q", id, message, err)
+		}
+	}
+}
+
+func TestBatchedPrivilegesStatements(t *testing.T) {
+	tables := make([]string, 250)
+	for i := range tables {
+		tables[i] = fmt.Sprintf("table_%03d", i)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"object_type": "table",
+		"database":    "foo",
+		"schema":      "app",
+		"role":        "bar",
+		"privileges":  []interface{}{"SELECT", "INSERT"},
+		"batch_size":  100,
+	})
+
+	statements := batchedPrivilegesStatements(d, tables, 100, true)
+	if len(statements) != 6 {
+		t.Fatalf("expected a revoke and a grant statement for each of the 3 batches, got %d statements", len(statements))
+	}
+	if expected := `REVOKE ALL PRIVILEGES ON TABLE "app"."table_000",`; !strings.HasPrefix(statements[0], expected) {
+		t.Fatalf("expected the first statement to start with %s, got %s", expected, statements[0])
+	}
+	if expected := `GRANT INSERT,SELECT ON TABLE "app"."table_200",`; !strings.HasPrefix(statements[5], expected) {
+		t.Fatalf("expected the last statement to start with %s, got %s", expected, statements[5])
+	}
+	for i, batch := range []int{100, 100, 50} {
+		if count := strings.Count(statements[2*i+1], `"app".`); count != batch {
+			t.Fatalf("expected batch %d to grant on %d tables, got %d", i, batch, count)
+		}
+	}
+
+	if revokes := batchedPrivilegesStatements(d, tables, 100, false); len(revokes) != 3 || !strings.HasPrefix(revokes[2], "REVOKE") {
+		t.Fatalf("expected 3 revoke statements, got %#v", revokes)
+	}
+
+	// Each batch is a statement of its own, bounded by the statement timeout of the resource
+	conn := &recordingConn{}
+	db := sql.OpenDB(conn)
+	defer db.Close()
+
+	txn, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deferredRollback(txn)
+
+	if err := setLocalStatementTimeout(&Client{}, txn, 60000); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := execStatements(txn, statements, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(conn.statements, append([]string{"SET LOCAL statement_timeout = 60000"}, statements...)) {
+		t.Fatalf("unexpected statements executed: %#v", conn.statements)
+	}
+}
+
+func TestAccPostgresqlGrant_StatementTimeout(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	createTestTables(t, dbSuffix, []string{"test_schema.test_table"}, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database          = "%s"
+		role              = "%s"
+		schema            = "test_schema"
+		object_type       = "table"
+		objects           = ["test_table"]
+		privileges        = ["SELECT"]
+		statement_timeout = 500
+	}
+	`, dbName, roleName)
+
+	// A concurrent grant on the table, not committed, blocks the grant of the resource
+	// which has to be canceled by the statement timeout. The blocking transaction is rolled
+	// back after a while so the test fails instead of hanging if the timeout is not applied.
+	testConfig := getTestConfig(t)
+	blocker, err := sql.Open("postgres", testConfig.connStr(dbName))
+	if err != nil {
+		t.Fatalf("could not open connection pool for db %s: %v", dbName, err)
+	}
+	defer blocker.Close()
+
+	var blockingTxn *sql.Tx
+	defer func() {
+		// Rolled back before the database is dropped
+		if blockingTxn != nil {
+			deferredRollback(blockingTxn)
+		}
+	}()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					txn, err := blocker.Begin()
+					if err != nil {
+						t.Fatalf("could not start blocking transaction: %v", err)
+					}
+					blockingTxn = txn
+					if _, err := txn.Exec(fmt.Sprintf("GRANT INSERT ON test_schema.test_table TO %s", roleName)); err != nil {
+						t.Fatalf("could not grant in blocking transaction: %v", err)
+					}
+					time.AfterFunc(10*time.Second, func() { deferredRollback(txn) })
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile("canceling statement due to statement timeout"),
+			},
+		},
+	})
+}
+
 func TestAccPostgresqlGrant(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -345,6 +588,12 @@ func TestAccPostgresqlGrant(t *testing.T) {
 					},
 				),
 			},
+			{
+				ResourceName:      "postgresql_grant.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s|table|%s|test_schema", roleName, dbName),
+				ImportStateVerify: true,
+			},
 			{
 				Config: fmt.Sprintf(testGrant, `["SELECT", "INSERT", "UPDATE"]`),
 				Check: resource.ComposeTestCheckFunc(
@@ -382,6 +631,291 @@ func TestAccPostgresqlGrant(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlGrant_ManyPrivilegesEmptyPlan(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	// The privileges are neither in the order of the catalog nor sorted
+	config := fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database    = "%s"
+		role        = "%s"
+		schema      = "test_schema"
+		object_type = "table"
+		objects     = ["test_table2", "test_table"]
+		privileges  = ["UPDATE", "TRUNCATE", "SELECT", "DELETE", "INSERT"]
+	}
+	`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "5"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE"})
+					},
+				),
+			},
+			// Applying the same configuration again must not change anything
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrantAllPrivileges(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	// The privileges read back are the enumerated ones, they must not produce a diff with ALL
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database    = "%s"
+		role        = "%s"
+		schema      = "test_schema"
+		object_type = "table"
+		objects     = ["test_table"]
+		privileges  = %%s
+	}
+	`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testGrant, `["ALL"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.0", "ALL"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT", "INSERT", "UPDATE", "DELETE"})
+					},
+				),
+			},
+			{
+				Config:   fmt.Sprintf(testGrant, `["ALL"]`),
+				PlanOnly: true,
+			},
+			{
+				// A privilege revoked outside of Terraform is detected
+				PreConfig: func() {
+					testConfig := getTestConfig(t)
+					dbExecute(t, testConfig.connStr(dbName), fmt.Sprintf("REVOKE DELETE ON test_schema.test_table FROM %s", roleName))
+				},
+				Config: fmt.Sprintf(testGrant, `["ALL"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT", "INSERT", "UPDATE", "DELETE"})
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrant_OrphanedGrantor(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	grantorName := fmt.Sprintf("%s_grantor", roleName)
+
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database    = "%s"
+		role        = "%s"
+		schema      = "test_schema"
+		object_type = "table"
+		objects     = ["test_table"]
+		privileges  = ["SELECT"]
+	}
+	`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrant,
+				Check:  testCheckGrantedByExistingRole(t, dbName, roleName),
+			},
+			{
+				// SELECT is granted again by a grantor which is then removed from the catalog
+				// without revoking its grants, leaving an ACL entry with the OID of the grantor.
+				PreConfig: func() {
+					testConfig := getTestConfig(t)
+					dsn := testConfig.connStr(dbName)
+					dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE %s", grantorName))
+					dbExecute(t, dsn, fmt.Sprintf("GRANT USAGE ON SCHEMA test_schema TO %s", grantorName))
+					dbExecute(t, dsn, fmt.Sprintf("GRANT SELECT ON test_schema.test_table TO %s WITH GRANT OPTION", grantorName))
+					dbExecute(t, dsn, fmt.Sprintf("REVOKE SELECT ON test_schema.test_table FROM %s", roleName))
+					dbExecute(t, dsn, fmt.Sprintf(
+						"SET ROLE %s; GRANT SELECT ON test_schema.test_table TO %s; RESET ROLE",
+						grantorName, roleName,
+					))
+					dbExecute(t, dsn, fmt.Sprintf("DELETE FROM pg_authid WHERE rolname = '%s'", grantorName))
+				},
+				Config:             testGrant,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// The privilege is granted again by the connected user
+				Config: testGrant,
+				Check:  testCheckGrantedByExistingRole(t, dbName, roleName),
+			},
+			{
+				Config:   testGrant,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrant_OrphanedGrantorForeignDataWrapper(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	grantorName := fmt.Sprintf("%s_grantor", roleName)
+
+	testConfig := getTestConfig(t)
+	dsn := testConfig.connStr(dbName)
+	dbExecute(t, dsn, "CREATE FOREIGN DATA WRAPPER test_fdw")
+
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database    = "%s"
+		role        = "%s"
+		object_type = "foreign_data_wrapper"
+		objects     = ["test_fdw"]
+		privileges  = ["USAGE"]
+	}
+	`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrant,
+			},
+			{
+				// USAGE is granted again by a grantor which is then removed from the catalog
+				// without revoking its grants.
+				PreConfig: func() {
+					dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE %s", grantorName))
+					dbExecute(t, dsn, fmt.Sprintf("GRANT USAGE ON FOREIGN DATA WRAPPER test_fdw TO %s WITH GRANT OPTION", grantorName))
+					dbExecute(t, dsn, fmt.Sprintf("REVOKE USAGE ON FOREIGN DATA WRAPPER test_fdw FROM %s", roleName))
+					dbExecute(t, dsn, fmt.Sprintf(
+						"SET ROLE %s; GRANT USAGE ON FOREIGN DATA WRAPPER test_fdw TO %s; RESET ROLE",
+						grantorName, roleName,
+					))
+					dbExecute(t, dsn, fmt.Sprintf("DELETE FROM pg_authid WHERE rolname = '%s'", grantorName))
+				},
+				Config:             testGrant,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// The privilege is granted again by the connected user
+				Config: testGrant,
+			},
+			{
+				Config:   testGrant,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// testCheckGrantedByExistingRole checks that SELECT is granted on test_schema.test_table
+// to the role by a grantor which still exists.
+func testCheckGrantedByExistingRole(t *testing.T, dbName, roleName string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		testConfig := getTestConfig(t)
+		db, err := sql.Open("postgres", testConfig.connStr(dbName))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var exists bool
+		if err := db.QueryRow(`
+SELECT EXISTS (
+	SELECT 1 FROM pg_class, aclexplode(relacl) acl
+	WHERE oid = 'test_schema.test_table'::regclass AND acl.privilege_type = 'SELECT'
+	AND acl.grantee = (SELECT oid FROM pg_roles WHERE rolname = $1) AND `+grantorExists+`
+)`, roleName).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("SELECT is not granted to %s by an existing role", roleName)
+		}
+		return nil
+	}
+}
+
+func TestOrphanedGrantsMessage(t *testing.T) {
+	// The privileges of a table granted by two grantors which were dropped
+	grants := []orphanedGrant{
+		{object: "t2", privilege: "SELECT", grantorOID: 16384},
+		{object: "t1", privilege: "UPDATE", grantorOID: 16390},
+		{object: "t1", privilege: "SELECT", grantorOID: 16390},
+		{object: "t1", privilege: "DELETE", grantorOID: 16384},
+	}
+
+	expected := `DELETE on table "t1" by removed role with OID 16384; ` +
+		`SELECT, UPDATE on table "t1" by removed role with OID 16390; ` +
+		`SELECT on table "t2" by removed role with OID 16384`
+	if message := orphanedGrantsMessage("table", grants); message != expected {
+		t.Fatalf("expected %q, got %q", expected, message)
+	}
+}
+
 func TestAccPostgresqlGrantColumns(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -681,6 +1215,51 @@ func TestAccPostgresqlGrantColumnsError(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlGrantMissingObject(t *testing.T) {
+	skipIfNotAcc(t)
+
+	// We have to create the database outside of resource.Test
+	// because we need to create a table to assert that only the missing one is reported.
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`resource "postgresql_grant" "test" {
+					database    = "%s"
+					role        = "%s"
+					schema      = "test_schema"
+					object_type = "table"
+					objects     = ["test_table", "missing_table"]
+					privileges  = ["SELECT"]
+				}`, dbName, roleName),
+				ExpectError: regexp.MustCompile("table test_schema.missing_table does not exist in database .*, if it is managed by Terraform make sure this grant depends on it"),
+			},
+			{
+				Config: fmt.Sprintf(`resource "postgresql_grant" "test" {
+					database    = "%s"
+					role        = "%s"
+					schema      = "missing_schema"
+					object_type = "schema"
+					privileges  = ["USAGE"]
+				}`, dbName, roleName),
+				ExpectError: regexp.MustCompile("schema missing_schema does not exist in database"),
+			},
+		},
+	})
+}
+
 func TestAccPostgresqlGrantPublic(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -775,6 +1354,87 @@ func TestAccPostgresqlGrantPublic(t *testing.T) {
 	})
 }
 
+// TestAccPostgresqlGrantPublicDefaultPrivileges checks the privileges that PUBLIC has by default
+// (i.e. while the ACL of the objects are NULL) are read and can be revoked.
+func TestAccPostgresqlGrantPublicDefaultPrivileges(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE FUNCTION test_schema.answer() RETURNS integer AS 'SELECT 42' LANGUAGE SQL")
+
+	tfConfig := `
+resource "postgresql_grant" "database" {
+  database    = "%[1]s"
+  role        = "public"
+  object_type = "database"
+  privileges  = ["CONNECT", "TEMPORARY"]
+}
+
+resource "postgresql_grant" "function" {
+  database    = "%[1]s"
+  role        = "public"
+  schema      = "test_schema"
+  object_type = "function"
+  objects     = ["answer"]
+  privileges  = %[2]s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				// The database has its default ACL, in which PUBLIC can connect and create temporary tables
+				Config:        fmt.Sprintf(tfConfig, dbName, `["EXECUTE"]`),
+				ResourceName:  "postgresql_grant.database",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("public|database|%s", dbName),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported grant, got %d", len(states))
+					}
+					if privileges := states[0].Attributes["privileges.#"]; privileges != "2" {
+						return fmt.Errorf("expected PUBLIC to have 2 privileges on database %s, got %s", dbName, privileges)
+					}
+					return nil
+				},
+			},
+			{
+				Config: fmt.Sprintf(tfConfig, dbName, `["EXECUTE"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.database", "privileges.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_grant.function", "privileges.#", "1"),
+					testCheckFunctionExecutableInDatabase(t, roleName, dbName, "test_schema.answer", true),
+				),
+			},
+			{
+				Config: fmt.Sprintf(tfConfig, dbName, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.function", "privileges.#", "0"),
+					testCheckFunctionExecutableInDatabase(t, roleName, dbName, "test_schema.answer", false),
+				),
+			},
+		},
+	})
+}
+
+func testCheckFunctionExecutableInDatabase(t *testing.T, role, dbName, function string, allowed bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		db := connectAsTestRole(t, role, dbName)
+		defer db.Close()
+
+		return testHasGrantForQuery(db, fmt.Sprintf("SELECT %s()", function), allowed)
+	}
+}
+
 func TestAccPostgresqlGrantEmptyPrivileges(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -1138,6 +1798,79 @@ resource "postgresql_grant" "test" {
 	})
 }
 
+func TestAccPostgresqlGrantDatabaseSelfLockout(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+
+	// The provider connects with the owner of the database, which is the only role allowed to connect to it
+	dbExecute(t, testConfig.connStr("postgres"), fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", dbName, roleName))
+	dbExecute(t, testConfig.connStr("postgres"), fmt.Sprintf("REVOKE CONNECT ON DATABASE %s FROM PUBLIC", dbName))
+
+	providerConfig := fmt.Sprintf(`
+provider "postgresql" {
+	username  = "%s"
+	password  = "%s"
+	superuser = false
+}
+`, roleName, testRolePassword)
+
+	grantConfig := fmt.Sprintf(`
+resource "postgresql_grant" "self" {
+	database             = "%s"
+	role                 = "%s"
+	object_type          = "database"
+	privileges           = ["CONNECT"]
+	prevent_self_lockout = %%t
+}
+`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(grantConfig, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.self", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_grant.self", "prevent_self_lockout", "true"),
+				),
+			},
+			// Revoking its own CONNECT privilege is refused
+			{
+				Config:      providerConfig,
+				ExpectError: regexp.MustCompile("would lose the CONNECT privilege on database " + dbName),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(grantConfig, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.self", "prevent_self_lockout", "false"),
+				),
+			},
+			// Once allowed, the privilege is revoked
+			{
+				Config: providerConfig,
+				Check: func(*terraform.State) error {
+					db := connectAsTestRole(t, roleName, dbName)
+					defer db.Close()
+
+					if err := db.Ping(); err == nil {
+						return fmt.Errorf("role %s should not be able to connect to database %s anymore", roleName, dbName)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
 func TestAccPostgresqlGrantSchema(t *testing.T) {
 	// create a TF config with placeholder for privileges
 	// it will be filled in each step.
@@ -1186,6 +1919,12 @@ resource "postgresql_grant" "test" {
 					testCheckSchemaPrivileges(t, true, true),
 				),
 			},
+			{
+				ResourceName:      "postgresql_grant.test",
+				ImportState:       true,
+				ImportStateId:     "test_grant_role|schema|postgres|test_schema",
+				ImportStateVerify: true,
+			},
 			{
 				//Config: fmt.Sprintf(config, "[]"),
 				Config: fmt.Sprintf(config, `[]`),
@@ -1327,6 +2066,170 @@ resource "postgresql_grant" "test" {
 	})
 }
 
+func TestAccPostgresqlGrantType(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+
+	// Create a test role and a schema as public has too wide open privileges
+	dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE test_role LOGIN PASSWORD '%s'", testRolePassword))
+	dbExecute(t, dsn, "CREATE SCHEMA test_schema")
+	dbExecute(t, dsn, "GRANT USAGE ON SCHEMA test_schema TO test_role")
+
+	// Create a composite type and a domain in this schema, PUBLIC has USAGE on types by default
+	dbExecute(t, dsn, "CREATE TYPE test_schema.geo AS (x float8, y float8)")
+	dbExecute(t, dsn, "CREATE DOMAIN test_schema.positive_int AS integer CHECK (VALUE > 0)")
+	dbExecute(t, dsn, "REVOKE USAGE ON TYPE test_schema.geo, test_schema.positive_int FROM PUBLIC")
+
+	defer func() {
+		dbExecute(t, dsn, "DROP SCHEMA test_schema CASCADE")
+		dbExecute(t, dsn, "DROP ROLE test_role")
+	}()
+
+	tfConfig := `
+resource postgresql_grant "test" {
+  database    = "postgres"
+  role        = "test_role"
+  schema      = "test_schema"
+  object_type = "type"
+  objects     = ["geo", "positive_int"]
+  privileges  = %s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(tfConfig, `["USAGE"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.0", "USAGE"),
+					testCheckTypesUsage(t, true),
+				),
+			},
+			{
+				ResourceName:      "postgresql_grant.test",
+				ImportState:       true,
+				ImportStateId:     "test_role|type|postgres|test_schema|geo,positive_int",
+				ImportStateVerify: true,
+			},
+			{
+				Config: fmt.Sprintf(tfConfig, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "0"),
+					testCheckTypesUsage(t, false),
+				),
+			},
+		},
+	})
+}
+
+func testCheckTypesUsage(t *testing.T, usage bool) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		db := connectAsTestRole(t, "test_role", "postgres")
+		defer db.Close()
+
+		return testHasGrantForQuery(
+			db, "CREATE TEMPORARY TABLE test_type_usage (g test_schema.geo, i test_schema.positive_int)", usage,
+		)
+	}
+}
+
+func TestAccPostgresqlGrantPostgresFdwUsage(t *testing.T) {
+	skipIfNotAcc(t)
+	skipIfNotSuperuser(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	config := getTestConfig(t)
+	dsn := config.connStr(dbName)
+
+	dbExecute(t, dsn, "CREATE EXTENSION postgres_fdw")
+	dbExecute(t, dsn, "CREATE SERVER test_pg_srv FOREIGN DATA WRAPPER postgres_fdw OPTIONS (host 'localhost', dbname 'postgres')")
+
+	tfConfig := `
+resource "postgresql_grant" "fdw" {
+  database    = "%[1]s"
+  role        = "%[2]s"
+  object_type = "foreign_data_wrapper"
+  objects     = ["postgres_fdw"]
+  privileges  = %[3]s
+}
+
+resource "postgresql_grant" "server" {
+  database    = "%[1]s"
+  role        = "%[2]s"
+  object_type = "foreign_server"
+  objects     = ["test_pg_srv"]
+  privileges  = %[3]s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(tfConfig, dbName, roleName, `["USAGE"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.fdw", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_grant.server", "privileges.#", "1"),
+					testCheckPostgresFdwUsage(t, dbName, roleName, true),
+				),
+			},
+			{
+				ResourceName:      "postgresql_grant.server",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s|foreign_server|%s||test_pg_srv", roleName, dbName),
+				ImportStateVerify: true,
+			},
+			{
+				Config: fmt.Sprintf(tfConfig, dbName, roleName, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.fdw", "privileges.#", "0"),
+					resource.TestCheckResourceAttr("postgresql_grant.server", "privileges.#", "0"),
+					testCheckPostgresFdwUsage(t, dbName, roleName, false),
+				),
+			},
+		},
+	})
+}
+
+// testCheckPostgresFdwUsage checks that the role can (or cannot) create its user mapping on the test server
+// and a server using postgres_fdw, which are the operations the USAGE privileges allow to non-superusers.
+func testCheckPostgresFdwUsage(t *testing.T, dbName, roleName string, usage bool) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		config := getTestConfig(t)
+		dsn := config.connStr(dbName)
+
+		defer func() {
+			dbExecute(t, dsn, fmt.Sprintf("DROP USER MAPPING IF EXISTS FOR %s SERVER test_pg_srv", roleName))
+			dbExecute(t, dsn, "DROP SERVER IF EXISTS test_role_srv")
+		}()
+
+		db := connectAsTestRole(t, roleName, dbName)
+		defer db.Close()
+
+		if err := testHasGrantForQuery(
+			db, "CREATE USER MAPPING FOR CURRENT_USER SERVER test_pg_srv OPTIONS (user 'foo', password 'bar')", usage,
+		); err != nil {
+			return err
+		}
+		return testHasGrantForQuery(db, "CREATE SERVER test_role_srv FOREIGN DATA WRAPPER postgres_fdw", usage)
+	}
+}
+
 func testCheckDatabasesPrivileges(t *testing.T, canCreate bool) func(*terraform.State) error {
 	return func(*terraform.State) error {
 		db := connectAsTestRole(t, "test_grant_role", "test_grant_db")
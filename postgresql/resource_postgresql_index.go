@@ -0,0 +1,556 @@
+package postgresql
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	indexNameAttr             = "name"
+	indexSchemaAttr           = "schema"
+	indexDatabaseAttr         = "database"
+	indexTableAttr            = "table"
+	indexColumnsAttr          = "columns"
+	indexExpressionsAttr      = "expressions"
+	indexUniqueAttr           = "unique"
+	indexMethodAttr           = "method"
+	indexWhereAttr            = "where"
+	indexIncludeAttr          = "include"
+	indexNullsNotDistinctAttr = "nulls_not_distinct"
+	indexConcurrentlyAttr     = "concurrently"
+	indexDropConcurrentlyAttr = "drop_concurrently"
+	indexDefinitionAttr       = "definition"
+)
+
+func resourcePostgreSQLIndex() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: PGResourceContextFunc(resourcePostgreSQLIndexCreate),
+		ReadContext:   PGResourceContextFunc(resourcePostgreSQLIndexRead),
+		UpdateContext: PGResourceContextFunc(resourcePostgreSQLIndexUpdate),
+		DeleteContext: PGResourceContextFunc(resourcePostgreSQLIndexDelete),
+		Exists:        PGResourceExistsFunc(resourcePostgreSQLIndexExists),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Hour),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(time.Hour),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			indexNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the index",
+				ValidateFunc: validateIdentifier,
+			},
+			indexSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema of the indexed table",
+			},
+			indexDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the index is created",
+			},
+			indexTableAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the table to be indexed",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			indexColumnsAttr: {
+				Type:         schema.TypeList,
+				Optional:     true,
+				ForceNew:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				AtLeastOneOf: []string{indexColumnsAttr, indexExpressionsAttr},
+				Description:  "The columns of the index",
+			},
+			indexExpressionsAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+				},
+				AtLeastOneOf: []string{indexColumnsAttr, indexExpressionsAttr},
+				Description:  "The expressions of the index, placed after the columns",
+			},
+			indexUniqueAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Causes the system to check for duplicate values in the table when the index is created",
+			},
+			indexMethodAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "btree",
+				ValidateFunc: validation.StringInSlice([]string{
+					"btree",
+					"hash",
+					"gist",
+					"spgist",
+					"gin",
+					"brin",
+				}, false),
+				Description: "The name of the index method to be used",
+			},
+			indexWhereAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The constraint expression for a partial index",
+
+				DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+			},
+			indexIncludeAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Non-key columns to include in the index",
+			},
+			indexNullsNotDistinctAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether null values are considered equal by an unique index",
+			},
+			indexConcurrentlyAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Build the index without taking any locks that prevent concurrent writes on the table",
+			},
+			indexDropConcurrentlyAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Drop the index without locking out concurrent selects, inserts, updates, and deletes on the table",
+			},
+			indexDefinitionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The index definition as returned by pg_get_indexdef",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLIndexCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := checkIndexFeatures(db, d); err != nil {
+		return err
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(indexSchemaAttr).(string)
+	indexName := d.Get(indexNameAttr).(string)
+	concurrently := d.Get(indexConcurrentlyAttr).(bool)
+
+	sql := createIndexQuery(d, concurrently)
+
+	if concurrently {
+		if err := createIndexConcurrently(db, database, schemaName, indexName, sql); err != nil {
+			return err
+		}
+	} else {
+		txn, err := startTransaction(db.client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		// The read removes the invalid indexes from the state to recreate them:
+		// the index left by a failed concurrent build is dropped in the transaction creating the new one.
+		if _, err := dropInvalidIndex(txn, schemaName, indexName, false); err != nil {
+			return err
+		}
+
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not create index %s: %w", indexName, err)
+		}
+
+		if err = txn.Commit(); err != nil {
+			return fmt.Errorf("Error creating index: %w", err)
+		}
+	}
+
+	d.SetId(generateIndexID(database, schemaName, indexName))
+
+	return resourcePostgreSQLIndexReadImpl(db, d)
+}
+
+// createIndexConcurrently runs CREATE INDEX CONCURRENTLY, which cannot be executed inside a transaction.
+// If a build fails, Postgres leaves an INVALID index behind which would make any later attempt fail,
+// so we drop it before creating the index and after a failed build.
+func createIndexConcurrently(db *DBConnection, database, schemaName, indexName, query string) error {
+	conn, err := connectDatabase(db.client, database)
+	if err != nil {
+		return err
+	}
+
+	// DROP INDEX CONCURRENTLY is only supported since Postgres 9.2
+	dropConcurrently := db.featureSupported(featureDropIndexConcurrently)
+	if _, err := dropInvalidIndex(conn, schemaName, indexName, dropConcurrently); err != nil {
+		return err
+	}
+
+	if _, err := conn.Exec(query); err != nil {
+		// The cleanup doesn't use the operation context as the build may have failed because of its deadline
+		dropped, dropErr := dropInvalidIndex(conn.DB, schemaName, indexName, dropConcurrently)
+		switch {
+		case dropErr != nil:
+			drop := "DROP INDEX"
+			if dropConcurrently {
+				drop = "DROP INDEX CONCURRENTLY"
+			}
+			return fmt.Errorf(
+				"could not create index %s concurrently: %v. The invalid index left by the failed build could not be dropped (%v), "+
+					"drop it with `%s %s.%s` before applying again",
+				indexName, err, dropErr, drop, pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(indexName),
+			)
+		case dropped:
+			return fmt.Errorf("could not create index %s concurrently (the invalid index left by the failed build has been dropped): %w", indexName, err)
+		}
+		return fmt.Errorf("could not create index %s concurrently: %w", indexName, err)
+	}
+
+	return nil
+}
+
+// dropInvalidIndex drops the index if it has been marked as invalid by a failed concurrent build,
+// concurrently or in the transaction db. It returns true if the index has been dropped.
+func dropInvalidIndex(db QueryAble, schemaName, indexName string, concurrently bool) (bool, error) {
+	var valid bool
+	query := `SELECT i.indisvalid FROM pg_catalog.pg_index i ` +
+		`JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+		`WHERE n.nspname = $1 AND c.relname = $2`
+	err := db.QueryRow(query, schemaName, indexName).Scan(&valid)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if index %s is valid: %w", indexName, err)
+	case valid:
+		return false, nil
+	}
+
+	log.Printf("[WARN] Dropping invalid index %s.%s left by a failed concurrent build", schemaName, indexName)
+
+	drop := "DROP INDEX"
+	if concurrently {
+		drop = "DROP INDEX CONCURRENTLY"
+	}
+	sql := fmt.Sprintf("%s IF EXISTS %s.%s", drop, pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(indexName))
+	if _, err := db.Exec(sql); err != nil {
+		return false, fmt.Errorf("could not drop invalid index %s: %w", indexName, err)
+	}
+
+	return true, nil
+}
+
+func createIndexQuery(d *schema.ResourceData, concurrently bool) string {
+	b := bytes.NewBufferString("CREATE ")
+	if d.Get(indexUniqueAttr).(bool) {
+		fmt.Fprint(b, "UNIQUE ")
+	}
+	fmt.Fprint(b, "INDEX ")
+	if concurrently {
+		fmt.Fprint(b, "CONCURRENTLY ")
+	}
+	fmt.Fprint(b, pq.QuoteIdentifier(d.Get(indexNameAttr).(string)),
+		" ON ", pq.QuoteIdentifier(d.Get(indexSchemaAttr).(string)), ".", pq.QuoteIdentifier(d.Get(indexTableAttr).(string)),
+		" USING ", d.Get(indexMethodAttr).(string),
+	)
+
+	keys := []string{}
+	for _, column := range d.Get(indexColumnsAttr).([]interface{}) {
+		keys = append(keys, pq.QuoteIdentifier(column.(string)))
+	}
+	for _, expression := range d.Get(indexExpressionsAttr).([]interface{}) {
+		keys = append(keys, fmt.Sprintf("(%s)", expression.(string)))
+	}
+	fmt.Fprint(b, " (", strings.Join(keys, ", "), ")")
+
+	if v, ok := d.GetOk(indexIncludeAttr); ok {
+		include := []string{}
+		for _, column := range v.([]interface{}) {
+			include = append(include, pq.QuoteIdentifier(column.(string)))
+		}
+		fmt.Fprint(b, " INCLUDE (", strings.Join(include, ", "), ")")
+	}
+
+	if d.Get(indexNullsNotDistinctAttr).(bool) {
+		fmt.Fprint(b, " NULLS NOT DISTINCT")
+	}
+
+	if v, ok := d.GetOk(indexWhereAttr); ok {
+		fmt.Fprint(b, " WHERE ", v.(string))
+	}
+
+	return b.String()
+}
+
+func checkIndexFeatures(db *DBConnection, d *schema.ResourceData) error {
+	if _, ok := d.GetOk(indexIncludeAttr); ok && !db.featureSupported(featureIndexInclude) {
+		return fmt.Errorf("index include columns are not supported %s", db.unsupportedServer())
+	}
+
+	if d.Get(indexNullsNotDistinctAttr).(bool) && !db.featureSupported(featureIndexNullsNotDistinct) {
+		return fmt.Errorf("index nulls_not_distinct is not supported %s", db.unsupportedServer())
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLIndexExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	database, schemaName, indexName, err := getDBIndexName(d, db.client)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the database exists
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	return indexExists(txn, schemaName, indexName)
+}
+
+func resourcePostgreSQLIndexRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLIndexReadImpl(db, d)
+}
+
+func resourcePostgreSQLIndexReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, indexName, err := getDBIndexName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	nullsNotDistinct := "false"
+	if db.featureSupported(featureIndexNullsNotDistinct) {
+		nullsNotDistinct = "i.indnullsnotdistinct"
+	}
+
+	var indexOID int
+	var tableName, method, definition, predicate string
+	var unique, valid, hasNullsNotDistinct bool
+	query := `SELECT i.indexrelid, t.relname, am.amname, i.indisunique, i.indisvalid, ` + nullsNotDistinct + `, ` +
+		`pg_catalog.pg_get_indexdef(i.indexrelid), COALESCE(pg_catalog.pg_get_expr(i.indpred, i.indrelid, true), '') ` +
+		`FROM pg_catalog.pg_index i ` +
+		`JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid ` +
+		`JOIN pg_catalog.pg_class t ON t.oid = i.indrelid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+		`JOIN pg_catalog.pg_am am ON am.oid = c.relam ` +
+		`WHERE n.nspname = $1 AND c.relname = $2`
+	err = txn.QueryRow(query, schemaName, indexName).Scan(
+		&indexOID, &tableName, &method, &unique, &valid, &hasNullsNotDistinct, &definition, &predicate,
+	)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL index (%s.%s) not found in database %s", schemaName, indexName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading index: %w", err)
+	}
+
+	if !valid {
+		// An invalid index is left by a failed concurrent build, so we let Terraform recreate it.
+		log.Printf("[WARN] PostgreSQL index (%s.%s) is invalid in database %s", schemaName, indexName, database)
+		d.SetId("")
+		return nil
+	}
+
+	columns, expressions, include, err := readIndexKeys(db, txn, indexOID)
+	if err != nil {
+		return err
+	}
+
+	// PostgreSQL rewrites expressions and predicates, so we only set them from the catalog when importing.
+	if _, ok := d.GetOk(indexTableAttr); !ok {
+		d.Set(indexExpressionsAttr, expressions)
+		d.Set(indexWhereAttr, predicate)
+	}
+
+	d.Set(indexNameAttr, indexName)
+	d.Set(indexSchemaAttr, schemaName)
+	d.Set(indexDatabaseAttr, database)
+	d.Set(indexTableAttr, tableName)
+	d.Set(indexColumnsAttr, columns)
+	d.Set(indexIncludeAttr, include)
+	d.Set(indexUniqueAttr, unique)
+	d.Set(indexMethodAttr, method)
+	d.Set(indexNullsNotDistinctAttr, hasNullsNotDistinct)
+	d.Set(indexDefinitionAttr, definition)
+	d.SetId(generateIndexID(database, schemaName, indexName))
+
+	return nil
+}
+
+// readIndexKeys returns the key columns, the key expressions and the included columns of an index.
+func readIndexKeys(db *DBConnection, txn *sql.Tx, indexOID int) ([]string, []string, []string, error) {
+	keyAttrs := "i.indnatts"
+	if db.featureSupported(featureIndexInclude) {
+		keyAttrs = "i.indnkeyatts"
+	}
+
+	query := `SELECT k.n <= ` + keyAttrs + `, COALESCE(a.attname, ''), pg_catalog.pg_get_indexdef(i.indexrelid, k.n, true) ` +
+		`FROM pg_catalog.pg_index i ` +
+		`CROSS JOIN LATERAL generate_series(1, i.indnatts) AS k(n) ` +
+		`LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = i.indkey[k.n - 1] AND a.attnum > 0 ` +
+		`WHERE i.indexrelid = $1 ORDER BY k.n`
+	rows, err := txn.Query(query, indexOID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not read index keys: %w", err)
+	}
+	defer rows.Close()
+
+	columns, expressions, include := []string{}, []string{}, []string{}
+	for rows.Next() {
+		var isKey bool
+		var column, expression string
+		if err := rows.Scan(&isKey, &column, &expression); err != nil {
+			return nil, nil, nil, fmt.Errorf("could not scan index key: %w", err)
+		}
+		switch {
+		case !isKey:
+			include = append(include, column)
+		case column != "":
+			columns = append(columns, column)
+		default:
+			expressions = append(expressions, expression)
+		}
+	}
+
+	return columns, expressions, include, rows.Err()
+}
+
+// resourcePostgreSQLIndexUpdate only refreshes the state, as every index attribute forces a new resource
+// except the flags which control how the index is created or dropped.
+func resourcePostgreSQLIndexUpdate(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLIndexReadImpl(db, d)
+}
+
+func resourcePostgreSQLIndexDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	indexName := fmt.Sprintf("%s.%s",
+		pq.QuoteIdentifier(d.Get(indexSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(indexNameAttr).(string)),
+	)
+
+	if d.Get(indexDropConcurrentlyAttr).(bool) {
+		if !db.featureSupported(featureDropIndexConcurrently) {
+			return fmt.Errorf("dropping index concurrently is not supported %s", db.unsupportedServer())
+		}
+
+		// DROP INDEX CONCURRENTLY cannot be executed inside a transaction
+		conn, err := connectDatabase(db.client, database)
+		if err != nil {
+			return err
+		}
+
+		if _, err := conn.Exec(fmt.Sprintf("DROP INDEX CONCURRENTLY %s", indexName)); err != nil {
+			return fmt.Errorf("could not drop index %s concurrently: %w", indexName, err)
+		}
+
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf("DROP INDEX %s", indexName)); err != nil {
+		return fmt.Errorf("could not drop index %s: %w", indexName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting index: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func indexExists(txn *sql.Tx, schemaName, indexName string) (bool, error) {
+	var _rez bool
+	err := txn.QueryRow(
+		"SELECT TRUE FROM pg_catalog.pg_indexes WHERE schemaname = $1 AND indexname = $2",
+		schemaName, indexName,
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if index exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func generateIndexID(database, schemaName, indexName string) string {
+	return joinResourceID(database, schemaName, indexName)
+}
+
+// getDBIndexName returns database, schema and index name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBIndexName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(indexSchemaAttr).(string)
+	indexName := d.Get(indexNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and index names.
+	if indexName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("index ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		indexName = parsed[2]
+	}
+	return database, schemaName, indexName, nil
+}
@@ -0,0 +1,276 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlIndex_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	createTestTables(t, dbSuffix, []string{"test_schema.test_table"}, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_index" "test" {
+  database = "%s"
+  schema   = "test_schema"
+  table    = "test_table"
+  name     = "test_table_val_idx"
+  columns  = ["val", "test_column_one"]
+  unique   = true
+  where    = "test_column_two IS NOT NULL"
+}
+
+resource "postgresql_index" "test_expr" {
+  database          = "%s"
+  schema            = "test_schema"
+  table             = "test_table"
+  name              = "test_table_lower_val_idx"
+  expressions       = ["lower(val)"]
+  concurrently      = true
+  drop_concurrently = true
+}
+`, dbName, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlIndexExists("postgresql_index.test"),
+					resource.TestCheckResourceAttr("postgresql_index.test", "method", "btree"),
+					resource.TestCheckResourceAttr("postgresql_index.test", "columns.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_index.test", "columns.0", "val"),
+					resource.TestCheckResourceAttr("postgresql_index.test", "columns.1", "test_column_one"),
+					resource.TestCheckResourceAttr("postgresql_index.test", "unique", "true"),
+					testAccCheckPostgresqlIndexExists("postgresql_index.test_expr"),
+					resource.TestCheckResourceAttr("postgresql_index.test_expr", "columns.#", "0"),
+					resource.TestCheckResourceAttr("postgresql_index.test_expr", "expressions.#", "1"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_index.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"where", "concurrently", "drop_concurrently"},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlIndex_Timeouts(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	createTestTables(t, dbSuffix, []string{"test_schema.test_table"}, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	config := getTestConfig(t)
+
+	// Simulate a slow index build: every indexed row takes one second to be evaluated
+	dbExecute(t, config.connStr(dbName), `
+CREATE FUNCTION test_schema.slow_lower(value text) RETURNS text IMMUTABLE LANGUAGE plpgsql AS $$
+BEGIN
+  PERFORM pg_sleep(1);
+  RETURN lower(value);
+END
+$$`)
+	dbExecute(t, config.connStr(dbName), "INSERT INTO test_schema.test_table (val) VALUES ('a'), ('b'), ('c')")
+
+	indexConfig := `
+resource "postgresql_index" "test" {
+  database    = "%s"
+  schema      = "test_schema"
+  table       = "test_table"
+  name        = "test_table_slow_idx"
+  expressions = ["test_schema.slow_lower(val)"]
+
+  timeouts {
+    create = "%s"
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(indexConfig, dbName, "1s"),
+				ExpectError: regexp.MustCompile("the operation exceeded its timeout"),
+			},
+			{
+				Config: fmt.Sprintf(indexConfig, dbName, "5m"),
+				Check:  testAccCheckPostgresqlIndexExists("postgresql_index.test"),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlIndex_Include(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	createTestTables(t, dbSuffix, []string{"test_schema.test_table"}, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_index" "test" {
+  database = "%s"
+  schema   = "test_schema"
+  table    = "test_table"
+  name     = "test_table_val_idx"
+  columns  = ["val"]
+  include  = ["test_column_one"]
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureIndexInclude)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlIndexExists("postgresql_index.test"),
+					resource.TestCheckResourceAttr("postgresql_index.test", "columns.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_index.test", "include.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_index.test", "include.0", "test_column_one"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlIndex_RecreateInvalid(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	createTestTables(t, dbSuffix, []string{"test_schema.test_table"}, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+
+	config := fmt.Sprintf(`
+resource "postgresql_index" "test" {
+  database = "%s"
+  schema   = "test_schema"
+  table    = "test_table"
+  name     = "test_table_val_idx"
+  columns  = ["val"]
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckPostgresqlIndexExists("postgresql_index.test"),
+			},
+			{
+				// The index is marked as invalid as a failed concurrent build leaves it, it is
+				// dropped and created again without concurrently.
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "UPDATE pg_catalog.pg_index SET indisvalid = false WHERE indexrelid = 'test_schema.test_table_val_idx'::regclass")
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlIndexExists("postgresql_index.test"),
+					resource.TestCheckResourceAttr("postgresql_index.test", "columns.#", "1"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlIndexDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_index" {
+			continue
+		}
+
+		txn, err := startTransaction(client, rs.Primary.Attributes[indexDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := indexExists(txn, rs.Primary.Attributes[indexSchemaAttr], rs.Primary.Attributes[indexNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking index %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Index still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlIndexExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[indexDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := indexExists(txn, rs.Primary.Attributes[indexSchemaAttr], rs.Primary.Attributes[indexNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking index %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Index not found")
+		}
+
+		return nil
+	}
+}
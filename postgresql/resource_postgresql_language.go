@@ -0,0 +1,275 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	languageNameAttr          = "name"
+	languageDatabaseAttr      = "database"
+	languageTrustedAttr       = "trusted"
+	languageHandlerAttr       = "handler"
+	languageInlineHandlerAttr = "inline_handler"
+	languageValidatorAttr     = "validator"
+	languageExtensionAttr     = "extension"
+	languageDropCascadeAttr   = "drop_cascade"
+)
+
+func resourcePostgreSQLLanguage() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLLanguageCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLLanguageRead),
+		Update: PGResourceFunc(resourcePostgreSQLLanguageUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLLanguageDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			languageNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the procedural language",
+			},
+			languageDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the language is created",
+			},
+			languageTrustedAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Whether the language grants access to data the user would not otherwise have, it is defined by the extension for extension-backed languages",
+			},
+			languageHandlerAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: functionSignatureDiffSuppressFunc,
+				Description:      "The function called to execute the functions of the language, the language is created from the extension of the same name if not set",
+			},
+			languageInlineHandlerAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				RequiredWith:     []string{languageHandlerAttr},
+				DiffSuppressFunc: functionSignatureDiffSuppressFunc,
+				Description:      "The function called to execute anonymous code blocks (DO statements) in the language",
+			},
+			languageValidatorAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				RequiredWith:     []string{languageHandlerAttr},
+				DiffSuppressFunc: functionSignatureDiffSuppressFunc,
+				Description:      "The function called to check the functions of the language when they are created",
+			},
+			languageExtensionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The extension the language belongs to, if any",
+			},
+			languageDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the language (e.g.: functions written in this language)",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLLanguageCreate(db *DBConnection, d *schema.ResourceData) error {
+	name := d.Get(languageNameAttr).(string)
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := createLanguageQuery(d)
+	if d.Get(languageHandlerAttr).(string) == "" {
+		// Since PostgreSQL 13 the procedural languages shipped with PostgreSQL can only be
+		// created with their extension, which also creates their handler functions.
+		var available bool
+		if err := txn.QueryRow(
+			"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_available_extensions WHERE name = $1)", name,
+		).Scan(&available); err != nil {
+			return fmt.Errorf("could not check if extension %s is available: %w", name, err)
+		}
+		if !available {
+			return fmt.Errorf("no extension %s is available in database %s, handler must be set to create the language", name, database)
+		}
+		query = fmt.Sprintf("CREATE EXTENSION %s", pq.QuoteIdentifier(name))
+	}
+
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not create language %s: %w", name, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating language: %w", err)
+	}
+
+	d.SetId(generateLanguageID(database, name))
+
+	return resourcePostgreSQLLanguageReadImpl(db, d)
+}
+
+func createLanguageQuery(d *schema.ResourceData) string {
+	b := &strings.Builder{}
+	fmt.Fprint(b, "CREATE ")
+	if d.Get(languageTrustedAttr).(bool) {
+		fmt.Fprint(b, "TRUSTED ")
+	}
+	fmt.Fprint(b, "LANGUAGE ", pq.QuoteIdentifier(d.Get(languageNameAttr).(string)))
+	fmt.Fprint(b, " HANDLER ", d.Get(languageHandlerAttr).(string))
+
+	if v, ok := d.GetOk(languageInlineHandlerAttr); ok {
+		fmt.Fprint(b, " INLINE ", v.(string))
+	}
+	if v, ok := d.GetOk(languageValidatorAttr); ok {
+		fmt.Fprint(b, " VALIDATOR ", v.(string))
+	}
+
+	return b.String()
+}
+
+func resourcePostgreSQLLanguageRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLLanguageReadImpl(db, d)
+}
+
+func resourcePostgreSQLLanguageReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, name, err := getDBLanguageName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var trusted bool
+	var handler, inlineHandler, validator, extension string
+	err = txn.QueryRow(
+		`SELECT l.lanpltrusted, `+
+			`CASE WHEN l.lanplcallfoid = 0 THEN '' ELSE l.lanplcallfoid::regproc::text END, `+
+			`CASE WHEN l.laninline = 0 THEN '' ELSE l.laninline::regproc::text END, `+
+			`CASE WHEN l.lanvalidator = 0 THEN '' ELSE l.lanvalidator::regproc::text END, `+
+			`COALESCE(e.extname, '') `+
+			`FROM pg_catalog.pg_language l `+
+			`LEFT JOIN pg_catalog.pg_depend dep ON dep.classid = 'pg_catalog.pg_language'::regclass `+
+			`AND dep.objid = l.oid AND dep.deptype = 'e' `+
+			`LEFT JOIN pg_catalog.pg_extension e ON e.oid = dep.refobjid `+
+			`WHERE l.lanname = $1`,
+		name,
+	).Scan(&trusted, &handler, &inlineHandler, &validator, &extension)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL language %s not found in database %s", name, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading language: %w", err)
+	}
+
+	// The handler functions of extension-backed languages are created by the extension,
+	// they are not part of the configuration.
+	if extension == "" {
+		for attr, function := range map[string]string{
+			languageHandlerAttr:       handler,
+			languageInlineHandlerAttr: inlineHandler,
+			languageValidatorAttr:     validator,
+		} {
+			if normalizeFunctionSignature(d.Get(attr).(string)) != normalizeFunctionSignature(function) {
+				d.Set(attr, function)
+			}
+		}
+	}
+
+	d.Set(languageNameAttr, name)
+	d.Set(languageDatabaseAttr, database)
+	d.Set(languageTrustedAttr, trusted)
+	d.Set(languageExtensionAttr, extension)
+	d.SetId(generateLanguageID(database, name))
+
+	return nil
+}
+
+// resourcePostgreSQLLanguageUpdate only handles drop_cascade which is not stored in the database.
+func resourcePostgreSQLLanguageUpdate(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLLanguageReadImpl(db, d)
+}
+
+func resourcePostgreSQLLanguageDelete(db *DBConnection, d *schema.ResourceData) error {
+	name := d.Get(languageNameAttr).(string)
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	dropMode := "RESTRICT"
+	if d.Get(languageDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	}
+
+	// A language which belongs to an extension can only be dropped with its extension
+	query := fmt.Sprintf("DROP LANGUAGE %s %s", pq.QuoteIdentifier(name), dropMode)
+	if extension := d.Get(languageExtensionAttr).(string); extension != "" {
+		query = fmt.Sprintf("DROP EXTENSION %s %s", pq.QuoteIdentifier(extension), dropMode)
+	}
+
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not drop language %s: %w", name, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting language: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func generateLanguageID(database, name string) string {
+	return joinResourceID(database, name)
+}
+
+// getDBLanguageName returns database and language name. If we are importing this resource, they will be parsed
+// from the resource ID (it will return an error if parsing failed) otherwise they will be simply
+// get from the state.
+func getDBLanguageName(d *schema.ResourceData, client *Client) (string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	name := d.Get(languageNameAttr).(string)
+
+	// When importing, we have to parse the ID to find language and database names.
+	if name == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 2 {
+			return "", "", fmt.Errorf("language ID %s has not the expected format 'database.language': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		name = parsed[1]
+	}
+	return database, name, nil
+}
@@ -0,0 +1,293 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCreateLanguageQuery(t *testing.T) {
+	var cases = []struct {
+		resource *schema.ResourceData
+		expected string
+	}{
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLLanguage().Schema, map[string]interface{}{
+				"name":    "plsample",
+				"handler": "plsample_call_handler",
+			}),
+			expected: `CREATE LANGUAGE "plsample" HANDLER plsample_call_handler`,
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLLanguage().Schema, map[string]interface{}{
+				"name":           "plsample",
+				"trusted":        true,
+				"handler":        "my_schema.plsample_call_handler",
+				"inline_handler": "my_schema.plsample_inline_handler",
+				"validator":      "my_schema.plsample_validator",
+			}),
+			expected: `CREATE TRUSTED LANGUAGE "plsample" HANDLER my_schema.plsample_call_handler INLINE my_schema.plsample_inline_handler VALIDATOR my_schema.plsample_validator`,
+		},
+	}
+
+	for _, c := range cases {
+		if out := createLanguageQuery(c.resource); out != c.expected {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.expected)
+		}
+	}
+}
+
+func TestAccPostgresqlLanguage_Handler(t *testing.T) {
+	skipIfNotAcc(t)
+	skipIfNotSuperuser(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	// The language reuses the handler functions of plpgsql, which are always available
+	config := `
+resource "postgresql_language" "test" {
+  database       = "%[1]s"
+  name           = "test_plpgsql"
+  trusted        = true
+  handler        = "plpgsql_call_handler"
+  inline_handler = "plpgsql_inline_handler"
+  validator      = "plpgsql_validator"
+}
+
+# USAGE on trusted languages is granted to PUBLIC by default
+resource "postgresql_grant" "public" {
+  database    = "%[1]s"
+  role        = "public"
+  object_type = "language"
+  objects     = [postgresql_language.test.name]
+  privileges  = []
+}
+
+resource "postgresql_grant" "test" {
+  database    = "%[1]s"
+  role        = "%[2]s"
+  object_type = "language"
+  objects     = [postgresql_language.test.name]
+  privileges  = %[3]s
+
+  depends_on = [postgresql_grant.public]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlLanguageDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, roleName, `["USAGE"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlLanguageExists("postgresql_language.test"),
+					resource.TestCheckResourceAttr("postgresql_language.test", "trusted", "true"),
+					resource.TestCheckResourceAttr("postgresql_language.test", "extension", ""),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
+					testCheckLanguageUsage(t, dbName, roleName, true),
+				),
+			},
+			{
+				ResourceName:            "postgresql_language.test",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("%s.test_plpgsql", dbName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"drop_cascade"},
+			},
+			{
+				ResourceName:      "postgresql_grant.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s|language|%s||test_plpgsql", roleName, dbName),
+				ImportStateVerify: true,
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, roleName, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "0"),
+					testCheckLanguageUsage(t, dbName, roleName, false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlLanguage_Extension(t *testing.T) {
+	skipIfNotAcc(t)
+	skipIfNotSuperuser(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+
+	// plpgsql is installed in every new database, drop it to create it again from its extension
+	dbExecute(t, testConfig.connStr(dbName), "DROP EXTENSION plpgsql")
+
+	config := fmt.Sprintf(`
+resource "postgresql_language" "plpgsql" {
+  database     = "%s"
+  name         = "plpgsql"
+  drop_cascade = true
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlLanguageDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlLanguageExists("postgresql_language.plpgsql"),
+					resource.TestCheckResourceAttr("postgresql_language.plpgsql", "trusted", "true"),
+					resource.TestCheckResourceAttr("postgresql_language.plpgsql", "extension", "plpgsql"),
+					resource.TestCheckResourceAttr("postgresql_language.plpgsql", "handler", ""),
+				),
+			},
+			{
+				ResourceName:            "postgresql_language.plpgsql",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("%s.plpgsql", dbName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"drop_cascade"},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlLanguage_PLPerl(t *testing.T) {
+	skipIfNotAcc(t)
+	skipIfNotSuperuser(t)
+	if !isExtensionAvailable(t, "plperl") {
+		t.Skip("Skip as plperl is not available on the test server")
+	}
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlLanguageDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_language" "plperl" {
+  database = "%s"
+  name     = "plperl"
+}
+`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlLanguageExists("postgresql_language.plperl"),
+					resource.TestCheckResourceAttr("postgresql_language.plperl", "trusted", "true"),
+					resource.TestCheckResourceAttr("postgresql_language.plperl", "extension", "plperl"),
+				),
+			},
+		},
+	})
+}
+
+// isExtensionAvailable checks if the extension can be installed on the test server,
+// the procedural languages other than plpgsql being optional packages.
+func isExtensionAvailable(t *testing.T, name string) bool {
+	config := getTestConfig(t)
+	db, err := sql.Open("postgres", config.connStr("postgres"))
+	if err != nil {
+		t.Fatalf("could not connect to the test database: %v", err)
+	}
+	defer db.Close()
+
+	var available bool
+	if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_available_extensions WHERE name = $1)", name).Scan(&available); err != nil {
+		t.Fatalf("could not check if extension %s is available: %v", name, err)
+	}
+	return available
+}
+
+// testCheckLanguageUsage checks if the role can run an anonymous code block in the test language
+func testCheckLanguageUsage(t *testing.T, dbName, roleName string, usage bool) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		db := connectAsTestRole(t, roleName, dbName)
+		defer db.Close()
+
+		return testHasGrantForQuery(db, "DO LANGUAGE test_plpgsql 'BEGIN NULL; END'", usage)
+	}
+}
+
+func testAccCheckPostgresqlLanguageDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_language" {
+			continue
+		}
+
+		exists, err := checkLanguageExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking language %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Language still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlLanguageExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		exists, err := checkLanguageExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking language %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Language not found")
+		}
+
+		return nil
+	}
+}
+
+func checkLanguageExists(rs *terraform.ResourceState) (bool, error) {
+	client := testAccProvider.Meta().(*Client)
+	txn, err := startTransaction(client, rs.Primary.Attributes[languageDatabaseAttr])
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	var _rez bool
+	err = txn.QueryRow(
+		"SELECT TRUE FROM pg_catalog.pg_language WHERE lanname = $1", rs.Primary.Attributes[languageNameAttr],
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
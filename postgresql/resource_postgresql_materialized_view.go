@@ -0,0 +1,697 @@
+package postgresql
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	matViewNameAttr                = "name"
+	matViewSchemaAttr              = "schema"
+	matViewDatabaseAttr            = "database"
+	matViewQueryAttr               = "query"
+	matViewWithDataAttr            = "with_data"
+	matViewTablespaceAttr          = "tablespace"
+	matViewStorageParametersAttr   = "storage_parameters"
+	matViewIndexAttr               = "index"
+	matViewRefreshOnApplyAttr      = "refresh_on_apply"
+	matViewRefreshConcurrentlyAttr = "refresh_concurrently"
+	matViewDropCascadeAttr         = "drop_cascade"
+	matViewPopulatedAttr           = "populated"
+
+	matViewIndexNameAttr    = "name"
+	matViewIndexColumnsAttr = "columns"
+	matViewIndexUniqueAttr  = "unique"
+)
+
+func resourcePostgreSQLMaterializedView() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: PGResourceContextFunc(resourcePostgreSQLMaterializedViewCreate),
+		ReadContext:   PGResourceContextFunc(resourcePostgreSQLMaterializedViewRead),
+		UpdateContext: PGResourceContextFunc(resourcePostgreSQLMaterializedViewUpdate),
+		DeleteContext: PGResourceContextFunc(resourcePostgreSQLMaterializedViewDelete),
+		Exists:        PGResourceExistsFunc(resourcePostgreSQLMaterializedViewExists),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Hour),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(time.Hour),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			matViewNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the materialized view",
+				ValidateFunc: validateIdentifier,
+			},
+			matViewSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the materialized view is created",
+			},
+			matViewDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the materialized view is created",
+			},
+			matViewQueryAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The SELECT, TABLE, or VALUES command which provides the data of the materialized view",
+				ValidateFunc: validation.StringIsNotEmpty,
+
+				DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+			},
+			matViewWithDataAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If false, the materialized view is left unscannable until it is refreshed",
+			},
+			matViewTablespaceAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The tablespace in which the materialized view is created",
+			},
+			matViewStorageParametersAttr: {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Storage parameters of the materialized view (e.g.: fillfactor, autovacuum_enabled)",
+			},
+			matViewIndexAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Indexes to create on the materialized view",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						matViewIndexNameAttr: {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the index",
+						},
+						matViewIndexColumnsAttr: {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The columns of the index",
+						},
+						matViewIndexUniqueAttr: {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether the index is unique (required to refresh concurrently)",
+						},
+					},
+				},
+			},
+			matViewRefreshOnApplyAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refresh the materialized view when the resource is updated",
+			},
+			matViewRefreshConcurrentlyAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refresh the materialized view without locking out concurrent selects (requires a unique index)",
+			},
+			matViewDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the materialized view",
+			},
+			matViewPopulatedAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the materialized view is currently populated",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLMaterializedViewCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureMaterializedView) {
+		return fmt.Errorf(
+			"postgresql_materialized_view resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(matViewSchemaAttr).(string)
+	viewName := d.Get(matViewNameAttr).(string)
+
+	b := bytes.NewBufferString("CREATE MATERIALIZED VIEW ")
+	fmt.Fprint(b, pq.QuoteIdentifier(schemaName), ".", pq.QuoteIdentifier(viewName))
+
+	if v, ok := d.GetOk(matViewStorageParametersAttr); ok {
+		fmt.Fprint(b, " WITH (", storageParametersToSQL(v.(map[string]interface{})), ")")
+	}
+
+	if v, ok := d.GetOk(matViewTablespaceAttr); ok {
+		fmt.Fprint(b, " TABLESPACE ", pq.QuoteIdentifier(v.(string)))
+	}
+
+	fmt.Fprint(b, " AS ", strings.TrimRight(strings.TrimSpace(d.Get(matViewQueryAttr).(string)), ";"))
+
+	if d.Get(matViewWithDataAttr).(bool) {
+		fmt.Fprint(b, " WITH DATA")
+	} else {
+		fmt.Fprint(b, " WITH NO DATA")
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("could not create materialized view %s: %w", viewName, err)
+	}
+
+	if err := createMatViewIndexes(txn, schemaName, viewName, d.Get(matViewIndexAttr).([]interface{})); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating materialized view: %w", err)
+	}
+
+	d.SetId(generateMatViewID(database, schemaName, viewName))
+
+	return resourcePostgreSQLMaterializedViewReadImpl(db, d)
+}
+
+func resourcePostgreSQLMaterializedViewExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	if !db.featureSupported(featureMaterializedView) {
+		return false, fmt.Errorf(
+			"postgresql_materialized_view resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database, schemaName, viewName, err := getDBMatViewName(d, db.client)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the database exists
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	return matViewExists(txn, schemaName, viewName)
+}
+
+func resourcePostgreSQLMaterializedViewRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureMaterializedView) {
+		return fmt.Errorf(
+			"postgresql_materialized_view resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	return resourcePostgreSQLMaterializedViewReadImpl(db, d)
+}
+
+func resourcePostgreSQLMaterializedViewReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, viewName, err := getDBMatViewName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var definition, tablespace string
+	var populated bool
+	var relOptions []string
+	query := `SELECT m.definition, COALESCE(m.tablespace, ''), c.relispopulated, COALESCE(c.reloptions, '{}') ` +
+		`FROM pg_catalog.pg_matviews m ` +
+		`JOIN pg_catalog.pg_namespace n ON n.nspname = m.schemaname ` +
+		`JOIN pg_catalog.pg_class c ON c.relname = m.matviewname AND c.relnamespace = n.oid ` +
+		`WHERE m.schemaname = $1 AND m.matviewname = $2`
+	err = txn.QueryRow(query, schemaName, viewName).Scan(&definition, &tablespace, &populated, pq.Array(&relOptions))
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL materialized view (%s.%s) not found in database %s", schemaName, viewName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading materialized view: %w", err)
+	}
+
+	indexes, err := readMatViewIndexes(txn, schemaName, viewName, d.Get(matViewIndexAttr).([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	// PostgreSQL rewrites the query of the view, so we only set it from the catalog when importing.
+	if _, ok := d.GetOk(matViewQueryAttr); !ok {
+		d.Set(matViewQueryAttr, definition)
+	}
+
+	d.Set(matViewNameAttr, viewName)
+	d.Set(matViewSchemaAttr, schemaName)
+	d.Set(matViewDatabaseAttr, database)
+	d.Set(matViewTablespaceAttr, tablespace)
+	d.Set(matViewStorageParametersAttr, relOptionsToMap(relOptions))
+	d.Set(matViewPopulatedAttr, populated)
+	d.Set(matViewIndexAttr, indexes)
+	d.SetId(generateMatViewID(database, schemaName, viewName))
+
+	return nil
+}
+
+func resourcePostgreSQLMaterializedViewUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureMaterializedView) {
+		return fmt.Errorf(
+			"postgresql_materialized_view resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := setMatViewTablespace(txn, d); err != nil {
+		return err
+	}
+
+	if err := setMatViewStorageParameters(txn, d); err != nil {
+		return err
+	}
+
+	if err := setMatViewIndexes(txn, d); err != nil {
+		return err
+	}
+
+	if err := setMatViewWithData(txn, d); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating materialized view: %w", err)
+	}
+
+	if d.Get(matViewRefreshOnApplyAttr).(bool) && !d.HasChange(matViewWithDataAttr) {
+		if err := refreshMatView(db, d); err != nil {
+			return err
+		}
+	}
+
+	return resourcePostgreSQLMaterializedViewReadImpl(db, d)
+}
+
+func resourcePostgreSQLMaterializedViewDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureMaterializedView) {
+		return fmt.Errorf(
+			"postgresql_materialized_view resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	dropMode := "RESTRICT"
+	if d.Get(matViewDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	}
+
+	sql := fmt.Sprintf("DROP MATERIALIZED VIEW %s.%s %s",
+		pq.QuoteIdentifier(d.Get(matViewSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(matViewNameAttr).(string)),
+		dropMode,
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop materialized view: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting materialized view: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// refreshMatView runs REFRESH MATERIALIZED VIEW, concurrently if asked.
+// A concurrent refresh requires a unique index on the view (and the view to be populated),
+// so we check it beforehand to return an explicit error.
+func refreshMatView(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(matViewSchemaAttr).(string)
+	viewName := d.Get(matViewNameAttr).(string)
+	concurrently := d.Get(matViewRefreshConcurrentlyAttr).(bool)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	b := bytes.NewBufferString("REFRESH MATERIALIZED VIEW ")
+	if concurrently {
+		if !db.featureSupported(featureRefreshMaterializedViewConcurrently) {
+			return fmt.Errorf(
+				"refreshing materialized view concurrently is not supported %s",
+				db.unsupportedServer(),
+			)
+		}
+
+		hasUniqueIndex, err := matViewHasUniqueIndex(txn, schemaName, viewName)
+		if err != nil {
+			return err
+		}
+		if !hasUniqueIndex {
+			return fmt.Errorf(
+				"cannot refresh materialized view %s.%s concurrently: it needs at least one unique index "+
+					"on columns only and without WHERE clause (see `index` attribute)",
+				schemaName, viewName,
+			)
+		}
+		fmt.Fprint(b, "CONCURRENTLY ")
+	}
+	fmt.Fprint(b, pq.QuoteIdentifier(schemaName), ".", pq.QuoteIdentifier(viewName))
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("could not refresh materialized view %s.%s: %w", schemaName, viewName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error refreshing materialized view: %w", err)
+	}
+
+	return nil
+}
+
+func setMatViewTablespace(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(matViewTablespaceAttr) {
+		return nil
+	}
+
+	tablespace := d.Get(matViewTablespaceAttr).(string)
+	if tablespace == "" {
+		tablespace = "pg_default"
+	}
+
+	sql := fmt.Sprintf("ALTER MATERIALIZED VIEW %s.%s SET TABLESPACE %s",
+		pq.QuoteIdentifier(d.Get(matViewSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(matViewNameAttr).(string)),
+		pq.QuoteIdentifier(tablespace),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating materialized view tablespace: %w", err)
+	}
+
+	return nil
+}
+
+func setMatViewStorageParameters(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(matViewStorageParametersAttr) {
+		return nil
+	}
+
+	viewName := fmt.Sprintf("%s.%s",
+		pq.QuoteIdentifier(d.Get(matViewSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(matViewNameAttr).(string)),
+	)
+
+	oraw, nraw := d.GetChange(matViewStorageParametersAttr)
+	oldParams := oraw.(map[string]interface{})
+	newParams := nraw.(map[string]interface{})
+
+	var toReset []string
+	for k := range oldParams {
+		if _, ok := newParams[k]; !ok {
+			toReset = append(toReset, k)
+		}
+	}
+
+	if len(toReset) > 0 {
+		sql := fmt.Sprintf("ALTER MATERIALIZED VIEW %s RESET (%s)", viewName, strings.Join(toReset, ", "))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error resetting materialized view storage parameters: %w", err)
+		}
+	}
+
+	if len(newParams) > 0 {
+		sql := fmt.Sprintf("ALTER MATERIALIZED VIEW %s SET (%s)", viewName, storageParametersToSQL(newParams))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error setting materialized view storage parameters: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func setMatViewIndexes(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(matViewIndexAttr) {
+		return nil
+	}
+
+	schemaName := d.Get(matViewSchemaAttr).(string)
+	viewName := d.Get(matViewNameAttr).(string)
+
+	oraw, nraw := d.GetChange(matViewIndexAttr)
+	dropped, created := matViewIndexChanges(oraw.([]interface{}), nraw.([]interface{}))
+	for _, indexName := range dropped {
+		sql := fmt.Sprintf("DROP INDEX IF EXISTS %s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(indexName))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not drop index %s on materialized view %s: %w", indexName, viewName, err)
+		}
+	}
+
+	return createMatViewIndexes(txn, schemaName, viewName, created)
+}
+
+// matViewIndexChanges returns the names of the indexes to drop and the indexes to create to go from the old
+// index blocks to the new ones: only the indexes removed or changed are dropped, and only the ones added or
+// changed are created, as building an index on a large view is expensive.
+func matViewIndexChanges(oldIndexes, newIndexes []interface{}) ([]string, []interface{}) {
+	oldByName := matViewIndexesByName(oldIndexes)
+	newByName := matViewIndexesByName(newIndexes)
+
+	dropped := []string{}
+	for _, raw := range oldIndexes {
+		indexName := raw.(map[string]interface{})[matViewIndexNameAttr].(string)
+		if newIndex, ok := newByName[indexName]; !ok || !reflect.DeepEqual(newIndex, oldByName[indexName]) {
+			dropped = append(dropped, indexName)
+		}
+	}
+
+	created := []interface{}{}
+	for _, raw := range newIndexes {
+		indexName := raw.(map[string]interface{})[matViewIndexNameAttr].(string)
+		if oldIndex, ok := oldByName[indexName]; !ok || !reflect.DeepEqual(oldIndex, newByName[indexName]) {
+			created = append(created, raw)
+		}
+	}
+
+	return dropped, created
+}
+
+// matViewIndexesByName returns the index blocks by name.
+func matViewIndexesByName(indexes []interface{}) map[string]map[string]interface{} {
+	byName := make(map[string]map[string]interface{}, len(indexes))
+	for _, raw := range indexes {
+		index := raw.(map[string]interface{})
+		byName[index[matViewIndexNameAttr].(string)] = index
+	}
+	return byName
+}
+
+func setMatViewWithData(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(matViewWithDataAttr) {
+		return nil
+	}
+
+	withData := "WITH DATA"
+	if !d.Get(matViewWithDataAttr).(bool) {
+		withData = "WITH NO DATA"
+	}
+
+	sql := fmt.Sprintf("REFRESH MATERIALIZED VIEW %s.%s %s",
+		pq.QuoteIdentifier(d.Get(matViewSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(matViewNameAttr).(string)),
+		withData,
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error refreshing materialized view %s: %w", withData, err)
+	}
+
+	return nil
+}
+
+func createMatViewIndexes(txn *sql.Tx, schemaName, viewName string, indexes []interface{}) error {
+	for _, raw := range indexes {
+		index := raw.(map[string]interface{})
+		indexName := index[matViewIndexNameAttr].(string)
+
+		columns := []string{}
+		for _, column := range index[matViewIndexColumnsAttr].([]interface{}) {
+			columns = append(columns, pq.QuoteIdentifier(column.(string)))
+		}
+
+		unique := ""
+		if index[matViewIndexUniqueAttr].(bool) {
+			unique = "UNIQUE "
+		}
+
+		sql := fmt.Sprintf("CREATE %sINDEX %s ON %s.%s (%s)",
+			unique,
+			pq.QuoteIdentifier(indexName),
+			pq.QuoteIdentifier(schemaName),
+			pq.QuoteIdentifier(viewName),
+			strings.Join(columns, ", "),
+		)
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not create index %s on materialized view %s: %w", indexName, viewName, err)
+		}
+	}
+
+	return nil
+}
+
+// readMatViewIndexes returns the indexes from the state which still exist on the materialized view,
+// so a removed index will be recreated.
+func readMatViewIndexes(txn *sql.Tx, schemaName, viewName string, stateIndexes []interface{}) ([]interface{}, error) {
+	query := `SELECT ic.relname, i.indisunique ` +
+		`FROM pg_catalog.pg_index i ` +
+		`JOIN pg_catalog.pg_class ic ON ic.oid = i.indexrelid ` +
+		`JOIN pg_catalog.pg_class c ON c.oid = i.indrelid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+		`WHERE n.nspname = $1 AND c.relname = $2`
+	rows, err := txn.Query(query, schemaName, viewName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read indexes of materialized view %s: %w", viewName, err)
+	}
+	defer rows.Close()
+
+	existing := map[string]bool{}
+	for rows.Next() {
+		var indexName string
+		var unique bool
+		if err := rows.Scan(&indexName, &unique); err != nil {
+			return nil, fmt.Errorf("could not scan index of materialized view %s: %w", viewName, err)
+		}
+		existing[indexName] = unique
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	indexes := make([]interface{}, 0, len(stateIndexes))
+	for _, raw := range stateIndexes {
+		index := raw.(map[string]interface{})
+		unique, ok := existing[index[matViewIndexNameAttr].(string)]
+		if !ok {
+			continue
+		}
+		index[matViewIndexUniqueAttr] = unique
+		indexes = append(indexes, index)
+	}
+
+	return indexes, nil
+}
+
+func matViewHasUniqueIndex(txn *sql.Tx, schemaName, viewName string) (bool, error) {
+	var hasUniqueIndex bool
+	query := `SELECT EXISTS (` +
+		`SELECT 1 FROM pg_catalog.pg_index i ` +
+		`JOIN pg_catalog.pg_class c ON c.oid = i.indrelid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+		`WHERE n.nspname = $1 AND c.relname = $2 AND i.indisunique ` +
+		`AND i.indpred IS NULL AND i.indexprs IS NULL)`
+	if err := txn.QueryRow(query, schemaName, viewName).Scan(&hasUniqueIndex); err != nil {
+		return false, fmt.Errorf("could not check unique indexes of materialized view %s: %w", viewName, err)
+	}
+
+	return hasUniqueIndex, nil
+}
+
+func matViewExists(txn *sql.Tx, schemaName, viewName string) (bool, error) {
+	var _rez bool
+	err := txn.QueryRow(
+		"SELECT TRUE FROM pg_catalog.pg_matviews WHERE schemaname = $1 AND matviewname = $2",
+		schemaName, viewName,
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if materialized view exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func generateMatViewID(database, schemaName, viewName string) string {
+	return joinResourceID(database, schemaName, viewName)
+}
+
+// getDBMatViewName returns database, schema and materialized view name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBMatViewName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(matViewSchemaAttr).(string)
+	viewName := d.Get(matViewNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and view names.
+	if viewName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("materialized view ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		viewName = parsed[2]
+	}
+	return database, schemaName, viewName, nil
+}
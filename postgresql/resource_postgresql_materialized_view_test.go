@@ -0,0 +1,225 @@
+package postgresql
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestMatViewIndexChanges(t *testing.T) {
+	index := func(name string, unique bool, columns ...string) interface{} {
+		cols := []interface{}{}
+		for _, column := range columns {
+			cols = append(cols, column)
+		}
+		return map[string]interface{}{"name": name, "columns": cols, "unique": unique}
+	}
+
+	oldIndexes := []interface{}{
+		index("kept", true, "id"),
+		index("removed", false, "name"),
+		index("changed", false, "a"),
+	}
+	newIndexes := []interface{}{
+		index("added", false, "b"),
+		index("kept", true, "id"),
+		index("changed", false, "a", "b"),
+	}
+
+	dropped, created := matViewIndexChanges(oldIndexes, newIndexes)
+	if expected := []string{"removed", "changed"}; !reflect.DeepEqual(dropped, expected) {
+		t.Errorf("matViewIndexChanges: expected to drop %v, got %v", expected, dropped)
+	}
+	if expected := []interface{}{newIndexes[0], newIndexes[2]}; !reflect.DeepEqual(created, expected) {
+		t.Errorf("matViewIndexChanges: expected to create %v, got %v", expected, created)
+	}
+
+	dropped, created = matViewIndexChanges(oldIndexes, oldIndexes)
+	if len(dropped) != 0 || len(created) != 0 {
+		t.Errorf("matViewIndexChanges: expected no change, got %v dropped and %v created", dropped, created)
+	}
+}
+
+func TestAccPostgresqlMaterializedView_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_materialized_view" "test" {
+  database  = "%s"
+  schema    = "test_schema"
+  name      = "test_matview"
+  query     = "SELECT 1 AS id, 'foo'::text AS val"
+  with_data = false
+
+  storage_parameters = {
+    fillfactor = "70"
+  }
+}
+`, dbName)
+
+	configUpdated := fmt.Sprintf(`
+resource "postgresql_materialized_view" "test" {
+  database  = "%s"
+  schema    = "test_schema"
+  name      = "test_matview"
+  query     = "SELECT 1 AS id, 'foo'::text AS val"
+
+  index {
+    name    = "test_matview_id_idx"
+    columns = ["id"]
+    unique  = true
+  }
+
+  refresh_on_apply     = true
+  refresh_concurrently = false
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureMaterializedView)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlMaterializedViewDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlMaterializedViewExists("postgresql_materialized_view.test"),
+					resource.TestCheckResourceAttr("postgresql_materialized_view.test", "name", "test_matview"),
+					resource.TestCheckResourceAttr("postgresql_materialized_view.test", "populated", "false"),
+					resource.TestCheckResourceAttr("postgresql_materialized_view.test", "storage_parameters.fillfactor", "70"),
+				),
+			},
+			{
+				Config: configUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlMaterializedViewExists("postgresql_materialized_view.test"),
+					resource.TestCheckResourceAttr("postgresql_materialized_view.test", "populated", "true"),
+					resource.TestCheckResourceAttr("postgresql_materialized_view.test", "storage_parameters.%", "0"),
+					resource.TestCheckResourceAttr("postgresql_materialized_view.test", "index.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_materialized_view.test", "index.0.unique", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlMaterializedView_ConcurrentRefreshWithoutUniqueIndex(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_materialized_view" "test" {
+  database = "%s"
+  name     = "test_matview"
+  query    = "SELECT 1 AS id"
+}
+`, dbName)
+
+	configConcurrent := fmt.Sprintf(`
+resource "postgresql_materialized_view" "test" {
+  database = "%s"
+  name     = "test_matview"
+  query    = "SELECT 1 AS id"
+
+  refresh_on_apply     = true
+  refresh_concurrently = true
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureRefreshMaterializedViewConcurrently)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlMaterializedViewDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlMaterializedViewExists("postgresql_materialized_view.test"),
+				),
+			},
+			{
+				Config:      configConcurrent,
+				ExpectError: regexp.MustCompile("needs at least one unique index"),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlMaterializedViewDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_materialized_view" {
+			continue
+		}
+
+		database, schemaName, viewName := rs.Primary.Attributes[matViewDatabaseAttr], rs.Primary.Attributes[matViewSchemaAttr], rs.Primary.Attributes[matViewNameAttr]
+
+		txn, err := startTransaction(client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := matViewExists(txn, schemaName, viewName)
+		if err != nil {
+			return fmt.Errorf("Error checking materialized view %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Materialized view still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlMaterializedViewExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[matViewDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := matViewExists(txn, rs.Primary.Attributes[matViewSchemaAttr], rs.Primary.Attributes[matViewNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking materialized view %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Materialized view not found")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	notifyDatabaseAttr       = "database"
+	notifyChannelAttr        = "channel"
+	notifyPayloadAttr        = "payload"
+	notifyDestroyPayloadAttr = "destroy_payload"
+	notifyTriggersAttr       = "triggers"
+
+	// maxNotifyPayloadLength is the limit of the payload of a notification in the default configuration.
+	maxNotifyPayloadLength = 7999
+)
+
+// resourcePostgreSQLNotify sends a notification to the listeners of a channel when it is created,
+// and so when its triggers change, to let external processes react to the changes of the infrastructure.
+func resourcePostgreSQLNotify() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLNotifyCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLNotifyRead),
+		Update: PGResourceFunc(resourcePostgreSQLNotifyRead),
+		Delete: PGResourceFunc(resourcePostgreSQLNotifyDelete),
+
+		Schema: map[string]*schema.Schema{
+			notifyDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database in which the notification is sent, the listeners being connected to it",
+			},
+			notifyChannelAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The channel on which the notification is sent",
+			},
+			notifyPayloadAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(0, maxNotifyPayloadLength),
+				Description:  "The payload of the notification sent when the resource is created",
+			},
+			notifyDestroyPayloadAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(0, maxNotifyPayloadLength),
+				Description:  "If set, a notification with this payload is also sent when the resource is destroyed",
+			},
+			notifyTriggersAttr: {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values which send the notification again when they change",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLNotifyCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	if err := sendNotification(db, database, d.Get(notifyChannelAttr).(string), d.Get(notifyPayloadAttr).(string)); err != nil {
+		return err
+	}
+
+	d.SetId(resource.PrefixedUniqueId(database + "."))
+
+	return resourcePostgreSQLNotifyRead(db, d)
+}
+
+// resourcePostgreSQLNotifyRead only sets the database: a notification leaves nothing to read.
+func resourcePostgreSQLNotifyRead(db *DBConnection, d *schema.ResourceData) error {
+	d.Set(notifyDatabaseAttr, getDatabase(d, db.client.databaseName))
+
+	return nil
+}
+
+func resourcePostgreSQLNotifyDelete(db *DBConnection, d *schema.ResourceData) error {
+	if payload, ok := d.GetOk(notifyDestroyPayloadAttr); ok {
+		database := getDatabase(d, db.client.databaseName)
+		if err := sendNotification(db, database, d.Get(notifyChannelAttr).(string), payload.(string)); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// sendNotification sends the notification in a transaction, the listeners receiving it once committed.
+func sendNotification(db *DBConnection, database, channel, payload string) error {
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(notifyStatement(channel, payload)); err != nil {
+		return fmt.Errorf("could not notify channel %s: %w", channel, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit notification on channel %s: %w", channel, err)
+	}
+
+	return nil
+}
+
+// notifyStatement returns the NOTIFY statement, NOTIFY not accepting bind parameters.
+func notifyStatement(channel, payload string) string {
+	statement := "NOTIFY " + pq.QuoteIdentifier(channel)
+	if payload != "" {
+		statement += ", " + pq.QuoteLiteral(payload)
+	}
+	return statement
+}
@@ -0,0 +1,101 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyStatement(t *testing.T) {
+	cases := []struct {
+		channel  string
+		payload  string
+		expected string
+	}{
+		{"events", "", `NOTIFY "events"`},
+		{"events", "schema changed", `NOTIFY "events", 'schema changed'`},
+		{"My Channel", "it's", `NOTIFY "My Channel", 'it''s'`},
+		{"events", `{"path": "C:\\tmp"}`, `NOTIFY "events",  E'{"path": "C:\\\\tmp"}'`},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, notifyStatement(c.channel, c.payload))
+	}
+}
+
+func TestAccPostgresqlNotify_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dsn := testConfig.connStr(dbName)
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, nil)
+	defer listener.Close()
+	if err := listener.Listen("terraform events"); err != nil {
+		t.Fatalf("could not listen to channel: %v", err)
+	}
+
+	tfConfig := `
+resource "postgresql_notify" "test" {
+  database        = "%s"
+  channel         = "terraform events"
+  payload         = "%s"
+  destroy_payload = "destroyed"
+
+  triggers = {
+    version = "%s"
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(tfConfig, dbName, `it's \"quoted\"`, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_notify.test", "database", dbName),
+					testCheckNotificationReceived(listener, "terraform events", `it's "quoted"`),
+				),
+			},
+			{
+				// Changing the triggers sends the destroy payload then the payload again
+				Config: fmt.Sprintf(tfConfig, dbName, "v2", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckNotificationReceived(listener, "terraform events", "destroyed"),
+					testCheckNotificationReceived(listener, "terraform events", "v2"),
+				),
+			},
+		},
+	})
+}
+
+// testCheckNotificationReceived checks that the next notification received by the listener
+// has the expected channel and payload.
+func testCheckNotificationReceived(listener *pq.Listener, channel, payload string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		select {
+		case notification := <-listener.Notify:
+			if notification == nil {
+				return fmt.Errorf("listener connection has been lost")
+			}
+			if notification.Channel != channel || notification.Extra != payload {
+				return fmt.Errorf("expected notification %q on channel %q, got %q on channel %q",
+					payload, channel, notification.Extra, notification.Channel)
+			}
+			return nil
+		case <-time.After(10 * time.Second):
+			return fmt.Errorf("no notification received on channel %q", channel)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	ownershipDatabaseAttr         = "database"
+	ownershipSchemaAttr           = "schema"
+	ownershipObjectTypeAttr       = "object_type"
+	ownershipObjectNameAttr       = "object_name"
+	ownershipAllInSchemaAttr      = "all_in_schema"
+	ownershipOwnerAttr            = "owner"
+	ownershipDeviatingObjectsAttr = "deviating_objects"
+
+	// ownershipAllObjects replaces the object name in the ID when all the objects of the schema are managed
+	ownershipAllObjects = "*"
+)
+
+// ownershipObjectType describes how to alter the owner of an object type, relkinds being
+// empty for the functions which are stored in pg_proc.
+type ownershipObjectType struct {
+	keyword  string
+	relkinds []string
+}
+
+var ownershipObjectTypes = map[string]ownershipObjectType{
+	"table":             {keyword: "TABLE", relkinds: []string{"r", "p"}},
+	"sequence":          {keyword: "SEQUENCE", relkinds: []string{"S"}},
+	"view":              {keyword: "VIEW", relkinds: []string{"v"}},
+	"materialized_view": {keyword: "MATERIALIZED VIEW", relkinds: []string{"m"}},
+	"function":          {keyword: "FUNCTION"},
+}
+
+// ownedObject is an object of the schema with its current owner, target being
+// its qualified name in the ALTER ... OWNER TO statement.
+type ownedObject struct {
+	name   string
+	target string
+	owner  string
+}
+
+func resourcePostgreSQLObjectOwnership() *schema.Resource {
+	objectTypes := make([]string, 0, len(ownershipObjectTypes))
+	for objectType := range ownershipObjectTypes {
+		objectTypes = append(objectTypes, objectType)
+	}
+	sort.Strings(objectTypes)
+
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLObjectOwnershipCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLObjectOwnershipRead),
+		Update: PGResourceFunc(resourcePostgreSQLObjectOwnershipUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLObjectOwnershipDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			ownershipDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the objects",
+			},
+			ownershipSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema of the objects",
+			},
+			ownershipObjectTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(objectTypes, false),
+				Description:  "The type of the objects",
+			},
+			ownershipObjectNameAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{ownershipObjectNameAttr, ownershipAllInSchemaAttr},
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: ownershipObjectNameDiffSuppressFunc,
+				Description:      "The name of the object, with its arguments for functions (e.g.: my_function(integer))",
+			},
+			ownershipAllInSchemaAttr: {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{ownershipObjectNameAttr, ownershipAllInSchemaAttr},
+				Description:  "Whether all the objects of this type in the schema are managed, they are listed when applied",
+			},
+			ownershipOwnerAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The role owning the objects",
+			},
+			ownershipDeviatingObjectsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The objects which are not owned by owner",
+			},
+		},
+	}
+}
+
+// ownedObjectNameMatches compares the name of an object with the configured one, the signatures
+// of the functions being normalized as they can be written in different ways.
+func ownedObjectNameMatches(objectTypeName, name, configured string) bool {
+	if objectTypeName == "function" {
+		return normalizeFunctionSignature(name) == normalizeFunctionSignature(configured)
+	}
+	return name == configured
+}
+
+func ownershipObjectNameDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return ownedObjectNameMatches(d.Get(ownershipObjectTypeAttr).(string), old, new)
+}
+
+func resourcePostgreSQLObjectOwnershipCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := reconcileObjectOwnership(db, d); err != nil {
+		return err
+	}
+
+	name := d.Get(ownershipObjectNameAttr).(string)
+	if d.Get(ownershipAllInSchemaAttr).(bool) {
+		name = ownershipAllObjects
+	}
+	d.SetId(generateObjectOwnershipID(
+		getDatabase(d, db.client.databaseName), d.Get(ownershipSchemaAttr).(string), d.Get(ownershipObjectTypeAttr).(string), name,
+	))
+
+	return resourcePostgreSQLObjectOwnershipReadImpl(db, d)
+}
+
+// reconcileObjectOwnership alters the owner of the managed objects not owned by owner.
+func reconcileObjectOwnership(db *DBConnection, d *schema.ResourceData) error {
+	schemaName := d.Get(ownershipSchemaAttr).(string)
+	objectTypeName := d.Get(ownershipObjectTypeAttr).(string)
+	objectName := d.Get(ownershipObjectNameAttr).(string)
+	owner := d.Get(ownershipOwnerAttr).(string)
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	objects, err := listOwnedObjects(db, txn, schemaName, objectTypeName, objectName)
+	if err != nil {
+		return err
+	}
+	if objectName != "" && len(objects) == 0 {
+		return fmt.Errorf("%s %s.%s does not exist", objectTypeName, schemaName, objectName)
+	}
+
+	keyword := ownershipObjectTypes[objectTypeName].keyword
+	if err := withRolesGranted(txn, []string{owner}, func() error {
+		for _, object := range objects {
+			if object.owner == owner {
+				continue
+			}
+			if _, err := txn.Exec(fmt.Sprintf("ALTER %s %s OWNER TO %s", keyword, object.target, pq.QuoteIdentifier(owner))); err != nil {
+				return fmt.Errorf("could not alter owner of %s %s.%s: %w", objectTypeName, schemaName, object.name, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error altering owner of %s objects: %w", objectTypeName, err)
+	}
+
+	return nil
+}
+
+// listOwnedObjects returns the objects of the type in the schema, sorted by name, or only the object
+// named objectName if it is not empty. The objects belonging to an extension and the sequences owned by
+// a column (whose owner is the one of their table) are not listed.
+func listOwnedObjects(db *DBConnection, txn *sql.Tx, schemaName, objectTypeName, objectName string) ([]ownedObject, error) {
+	objectType := ownershipObjectTypes[objectTypeName]
+
+	var query string
+	args := []interface{}{schemaName}
+	if len(objectType.relkinds) > 0 {
+		query = `SELECT c.relname, pg_catalog.quote_ident(n.nspname) || '.' || pg_catalog.quote_ident(c.relname), ` +
+			`pg_catalog.pg_get_userbyid(c.relowner) ` +
+			`FROM pg_catalog.pg_class c ` +
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+			`WHERE n.nspname = $1 AND c.relkind::text = ANY($2) ` +
+			`AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_depend dep WHERE dep.classid = 'pg_catalog.pg_class'::regclass ` +
+			`AND dep.objid = c.oid AND (dep.deptype = 'e' OR (c.relkind = 'S' AND dep.deptype IN ('a', 'i'))))`
+		args = append(args, pq.Array(objectType.relkinds))
+	} else {
+		kindFilter := "NOT p.proisagg"
+		if db.featureSupported(featureProcedure) {
+			kindFilter = "p.prokind = 'f'"
+		}
+		query = `SELECT p.proname || '(' || pg_catalog.pg_get_function_identity_arguments(p.oid) || ')', ` +
+			`pg_catalog.quote_ident(n.nspname) || '.' || pg_catalog.quote_ident(p.proname) || '(' || pg_catalog.pg_get_function_identity_arguments(p.oid) || ')', ` +
+			`pg_catalog.pg_get_userbyid(p.proowner) ` +
+			`FROM pg_catalog.pg_proc p ` +
+			`JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace ` +
+			`WHERE n.nspname = $1 AND ` + kindFilter + ` ` +
+			`AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_depend dep WHERE dep.classid = 'pg_catalog.pg_proc'::regclass ` +
+			`AND dep.objid = p.oid AND dep.deptype = 'e')`
+	}
+
+	rows, err := txn.Query(query+" ORDER BY 1", args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not list %s objects of schema %s: %w", objectTypeName, schemaName, err)
+	}
+	defer rows.Close()
+
+	var objects []ownedObject
+	for rows.Next() {
+		var object ownedObject
+		if err := rows.Scan(&object.name, &object.target, &object.owner); err != nil {
+			return nil, fmt.Errorf("could not scan %s object: %w", objectTypeName, err)
+		}
+		if objectName != "" && !ownedObjectNameMatches(objectTypeName, object.name, objectName) {
+			continue
+		}
+		objects = append(objects, object)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func resourcePostgreSQLObjectOwnershipRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLObjectOwnershipReadImpl(db, d)
+}
+
+func resourcePostgreSQLObjectOwnershipReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	if d.Get(ownershipObjectTypeAttr).(string) == "" {
+		if err := parseObjectOwnershipID(d); err != nil {
+			return err
+		}
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(ownershipSchemaAttr).(string)
+	objectTypeName := d.Get(ownershipObjectTypeAttr).(string)
+	objectName := d.Get(ownershipObjectNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	objects, err := listOwnedObjects(db, txn, schemaName, objectTypeName, objectName)
+	if err != nil {
+		return err
+	}
+	if objectName != "" && len(objects) == 0 {
+		log.Printf("[WARN] PostgreSQL %s %s.%s not found in database %s", objectTypeName, schemaName, objectName, database)
+		d.SetId("")
+		return nil
+	}
+
+	owner := d.Get(ownershipOwnerAttr).(string)
+	if owner == "" && len(objects) > 0 {
+		owner = objects[0].owner
+	}
+
+	// The owner is set to the owner of the first deviating object to plan the reconciliation
+	deviatingObjects := []string{}
+	stateOwner := owner
+	for _, object := range objects {
+		if object.owner != owner {
+			if len(deviatingObjects) == 0 {
+				stateOwner = object.owner
+			}
+			deviatingObjects = append(deviatingObjects, object.name)
+		}
+	}
+	if len(deviatingObjects) > 0 {
+		log.Printf("[WARN] PostgreSQL %s objects of schema %s not owned by %s: %s", objectTypeName, schemaName, owner, strings.Join(deviatingObjects, ", "))
+	}
+
+	d.Set(ownershipDatabaseAttr, database)
+	d.Set(ownershipOwnerAttr, stateOwner)
+	d.Set(ownershipDeviatingObjectsAttr, deviatingObjects)
+
+	return nil
+}
+
+func resourcePostgreSQLObjectOwnershipUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := reconcileObjectOwnership(db, d); err != nil {
+		return err
+	}
+
+	return resourcePostgreSQLObjectOwnershipReadImpl(db, d)
+}
+
+// resourcePostgreSQLObjectOwnershipDelete only removes the resource from the state,
+// the objects keep their owner.
+func resourcePostgreSQLObjectOwnershipDelete(db *DBConnection, d *schema.ResourceData) error {
+	log.Printf("[WARN] PostgreSQL object ownership %s removed from state, the objects keep their owner", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func generateObjectOwnershipID(database, schemaName, objectType, objectName string) string {
+	return joinResourceID(database, schemaName, objectType, objectName)
+}
+
+// parseObjectOwnershipID sets the attributes of the resource from the ID when importing,
+// the object name being the last part of the ID as function arguments can contain dots.
+func parseObjectOwnershipID(d *schema.ResourceData) error {
+	parsed := splitResourceIDN(d.Id(), 4)
+	if len(parsed) != 4 {
+		return fmt.Errorf("object ownership ID %s has not the expected format 'database.schema.object_type.name': %v", d.Id(), parsed)
+	}
+	if _, ok := ownershipObjectTypes[parsed[2]]; !ok {
+		return fmt.Errorf("object ownership ID %s has an unknown object type: %s", d.Id(), parsed[2])
+	}
+
+	d.Set(ownershipDatabaseAttr, parsed[0])
+	d.Set(ownershipSchemaAttr, parsed[1])
+	d.Set(ownershipObjectTypeAttr, parsed[2])
+	if parsed[3] == ownershipAllObjects {
+		d.Set(ownershipAllInSchemaAttr, true)
+	} else {
+		d.Set(ownershipObjectNameAttr, parsed[3])
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseObjectOwnershipID(t *testing.T) {
+	var cases = []struct {
+		id          string
+		objectType  string
+		objectName  string
+		allInSchema bool
+		valid       bool
+	}{
+		{id: "mydb.public.table.my_table", objectType: "table", objectName: "my_table", valid: true},
+		{id: "mydb.public.table.*", objectType: "table", allInSchema: true, valid: true},
+		{id: "mydb.app.function.to_point(app.geo, integer)", objectType: "function", objectName: "to_point(app.geo, integer)", valid: true},
+		{id: "mydb.public.my_table"},
+		{id: "mydb.public.index.my_index"},
+	}
+
+	for _, c := range cases {
+		d := resourcePostgreSQLObjectOwnership().TestResourceData()
+		d.SetId(c.id)
+
+		err := parseObjectOwnershipID(d)
+		if (err == nil) != c.valid {
+			t.Fatalf("Expected parsing of %s to be %t, got error: %v", c.id, c.valid, err)
+		}
+		if !c.valid {
+			continue
+		}
+		if out := d.Get(ownershipObjectTypeAttr).(string); out != c.objectType {
+			t.Fatalf("Error matching object type of %s: %#v vs %#v", c.id, out, c.objectType)
+		}
+		if out := d.Get(ownershipObjectNameAttr).(string); out != c.objectName {
+			t.Fatalf("Error matching object name of %s: %#v vs %#v", c.id, out, c.objectName)
+		}
+		if out := d.Get(ownershipAllInSchemaAttr).(bool); out != c.allInSchema {
+			t.Fatalf("Error matching all_in_schema of %s: %#v vs %#v", c.id, out, c.allInSchema)
+		}
+	}
+}
+
+func TestOwnedObjectNameMatches(t *testing.T) {
+	var cases = []struct {
+		objectType string
+		name       string
+		configured string
+		expected   bool
+	}{
+		{objectType: "table", name: "my_table", configured: "my_table", expected: true},
+		{objectType: "table", name: "My_Table", configured: "my_table", expected: false},
+		{objectType: "function", name: "to_point(geo, integer)", configured: "To_Point(public.geo, int4)", expected: true},
+		{objectType: "function", name: "to_point(geo, integer)", configured: "to_point(geo)", expected: false},
+	}
+
+	for _, c := range cases {
+		if out := ownedObjectNameMatches(c.objectType, c.name, c.configured); out != c.expected {
+			t.Fatalf("Error matching %s %s with %s: %t vs %t", c.objectType, c.name, c.configured, out, c.expected)
+		}
+	}
+}
+
+func TestAccPostgresqlObjectOwnership_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	tablesTeardown := createTestTables(t, dbSuffix, []string{"test_table", "test_table2"}, "")
+	defer tablesTeardown()
+
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE FUNCTION test_function(a integer) RETURNS integer LANGUAGE sql AS 'SELECT a'")
+
+	config := fmt.Sprintf(`
+resource "postgresql_object_ownership" "tables" {
+  database      = "%[1]s"
+  object_type   = "table"
+  all_in_schema = true
+  owner         = "%[2]s"
+}
+
+resource "postgresql_object_ownership" "function" {
+  database    = "%[1]s"
+  object_type = "function"
+  object_name = "test_function(int4)"
+  owner       = "%[2]s"
+}
+`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_object_ownership.tables", "id", fmt.Sprintf("%s.public.table.*", dbName)),
+					resource.TestCheckResourceAttr("postgresql_object_ownership.tables", "deviating_objects.#", "0"),
+					resource.TestCheckResourceAttr("postgresql_object_ownership.function", "deviating_objects.#", "0"),
+					testAccCheckObjectOwner(dbName, "SELECT pg_get_userbyid(relowner) FROM pg_class WHERE oid = 'test_table'::regclass", roleName),
+					testAccCheckObjectOwner(dbName, "SELECT pg_get_userbyid(relowner) FROM pg_class WHERE oid = 'test_table2'::regclass", roleName),
+					testAccCheckObjectOwner(dbName, "SELECT pg_get_userbyid(proowner) FROM pg_proc WHERE oid = 'test_function(integer)'::regprocedure", roleName),
+				),
+			},
+			{
+				// A table created afterwards is not owned by the role, its owner is reconciled on apply
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_table3 (id integer)")
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_object_ownership.tables", "owner", roleName),
+					resource.TestCheckResourceAttr("postgresql_object_ownership.tables", "deviating_objects.#", "0"),
+					testAccCheckObjectOwner(dbName, "SELECT pg_get_userbyid(relowner) FROM pg_class WHERE oid = 'test_table3'::regclass", roleName),
+				),
+			},
+			{
+				ResourceName:      "postgresql_object_ownership.function",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.public.function.test_function(integer)", dbName),
+				ImportStateVerify: true,
+				// The signature is imported as displayed by Postgres
+				ImportStateVerifyIgnore: []string{"object_name"},
+			},
+		},
+	})
+}
+
+// testAccCheckObjectOwner checks the owner returned by the query in the database.
+func testAccCheckObjectOwner(dbName, query, expected string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var owner string
+		if err := txn.QueryRow(query).Scan(&owner); err != nil {
+			return fmt.Errorf("could not read owner: %w", err)
+		}
+		if owner != expected {
+			return fmt.Errorf("expected owner to be %q, got %q", expected, owner)
+		}
+		return nil
+	}
+}
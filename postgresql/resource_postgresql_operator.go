@@ -0,0 +1,318 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	operatorNameAttr       = "name"
+	operatorSchemaAttr     = "schema"
+	operatorDatabaseAttr   = "database"
+	operatorLeftTypeAttr   = "left_type"
+	operatorRightTypeAttr  = "right_type"
+	operatorFunctionAttr   = "function"
+	operatorCommutatorAttr = "commutator"
+	operatorNegatorAttr    = "negator"
+	operatorRestrictAttr   = "restrict"
+	operatorJoinAttr       = "join"
+)
+
+// operatorNameChars are the characters an operator name can be composed of
+const operatorNameChars = "+-*/<>=~!@#%^&|`?"
+
+func resourcePostgreSQLOperator() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLOperatorCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLOperatorRead),
+		Delete: PGResourceFunc(resourcePostgreSQLOperatorDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			operatorNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateOperatorName,
+				Description:  "The name of the operator",
+			},
+			operatorSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the operator is created",
+			},
+			operatorDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the operator is created",
+			},
+			operatorLeftTypeAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: typeDiffSuppressFunc,
+				Description:      "The data type of the left operand, if any (prefix operators have none)",
+			},
+			operatorRightTypeAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: typeDiffSuppressFunc,
+				Description:      "The data type of the right operand",
+			},
+			operatorFunctionAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: functionSignatureDiffSuppressFunc,
+				Description:      "The function used to implement the operator",
+			},
+			operatorCommutatorAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateOperatorName,
+				Description:  "The commutator of the operator",
+			},
+			operatorNegatorAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateOperatorName,
+				Description:  "The negator of the operator",
+			},
+			operatorRestrictAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: functionSignatureDiffSuppressFunc,
+				Description:      "The restriction selectivity estimator function of the operator (e.g.: eqsel)",
+			},
+			operatorJoinAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: functionSignatureDiffSuppressFunc,
+				Description:      "The join selectivity estimator function of the operator (e.g.: eqjoinsel)",
+			},
+		},
+	}
+}
+
+func validateOperatorName(v interface{}, key string) ([]string, []error) {
+	name := v.(string)
+	if name == "" || strings.Trim(name, operatorNameChars) != "" {
+		return nil, []error{fmt.Errorf("%s must only be composed of the characters %s, got: %q", key, operatorNameChars, name)}
+	}
+	return nil, nil
+}
+
+func resourcePostgreSQLOperatorCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(operatorSchemaAttr).(string)
+	operatorName := d.Get(operatorNameAttr).(string)
+	leftType := d.Get(operatorLeftTypeAttr).(string)
+	rightType := d.Get(operatorRightTypeAttr).(string)
+
+	options := []string{"FUNCTION = " + d.Get(operatorFunctionAttr).(string)}
+	if leftType != "" {
+		options = append(options, "LEFTARG = "+leftType)
+	}
+	options = append(options, "RIGHTARG = "+rightType)
+	if v, ok := d.GetOk(operatorCommutatorAttr); ok {
+		options = append(options, fmt.Sprintf("COMMUTATOR = OPERATOR(%s.%s)", pq.QuoteIdentifier(schemaName), v.(string)))
+	}
+	if v, ok := d.GetOk(operatorNegatorAttr); ok {
+		options = append(options, fmt.Sprintf("NEGATOR = OPERATOR(%s.%s)", pq.QuoteIdentifier(schemaName), v.(string)))
+	}
+	if v, ok := d.GetOk(operatorRestrictAttr); ok {
+		options = append(options, "RESTRICT = "+v.(string))
+	}
+	if v, ok := d.GetOk(operatorJoinAttr); ok {
+		options = append(options, "JOIN = "+v.(string))
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	sql := fmt.Sprintf("CREATE OPERATOR %s.%s (%s)", pq.QuoteIdentifier(schemaName), operatorName, strings.Join(options, ", "))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not create operator %s: %w", operatorName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating operator: %w", err)
+	}
+
+	d.SetId(generateOperatorID(database, schemaName, operatorName, leftType, rightType))
+
+	return resourcePostgreSQLOperatorReadImpl(db, d)
+}
+
+func resourcePostgreSQLOperatorRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLOperatorReadImpl(db, d)
+}
+
+func resourcePostgreSQLOperatorReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	op, err := getDBOperator(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, op.database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var leftType, rightType, function, commutator, negator, restrict, join string
+	// A prefix operator has no left operand (oprleft = 0), to_regtype returns NULL for an empty
+	// type name so we compare with 0 in this case.
+	err = txn.QueryRow(
+		`SELECT CASE WHEN o.oprleft = 0 THEN '' ELSE pg_catalog.format_type(o.oprleft, NULL) END, `+
+			`pg_catalog.format_type(o.oprright, NULL), o.oprcode::text, `+
+			`COALESCE((SELECT c.oprname FROM pg_catalog.pg_operator c WHERE c.oid = o.oprcom), ''), `+
+			`COALESCE((SELECT c.oprname FROM pg_catalog.pg_operator c WHERE c.oid = o.oprnegate), ''), `+
+			`CASE WHEN o.oprrest::oid = 0 THEN '' ELSE o.oprrest::text END, `+
+			`CASE WHEN o.oprjoin::oid = 0 THEN '' ELSE o.oprjoin::text END `+
+			`FROM pg_catalog.pg_operator o JOIN pg_catalog.pg_namespace n ON n.oid = o.oprnamespace `+
+			`WHERE n.nspname = $1 AND o.oprname = $2 `+
+			`AND o.oprleft = COALESCE(pg_catalog.to_regtype(NULLIF($3, ''))::oid, 0::oid) AND o.oprright = pg_catalog.to_regtype($4)`,
+		op.schemaName, op.name, op.leftType, op.rightType,
+	).Scan(&leftType, &rightType, &function, &commutator, &negator, &restrict, &join)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL operator %s.%s not found in database %s", op.schemaName, op.name, op.database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading operator: %w", err)
+	}
+
+	// The types are looked up with the names of the configuration, which may be written differently
+	// from format_type (e.g.: schema qualified), so we only take them from the catalog when importing.
+	importing := d.Get(operatorRightTypeAttr).(string) == ""
+	if importing {
+		d.Set(operatorLeftTypeAttr, leftType)
+		d.Set(operatorRightTypeAttr, rightType)
+	}
+
+	// Creating an operator with this one as commutator or negator also sets them on this operator,
+	// so they are only tracked if they have been configured.
+	if importing || d.Get(operatorCommutatorAttr).(string) != "" {
+		d.Set(operatorCommutatorAttr, commutator)
+	}
+	if importing || d.Get(operatorNegatorAttr).(string) != "" {
+		d.Set(operatorNegatorAttr, negator)
+	}
+	for attr, value := range map[string]string{
+		operatorFunctionAttr: function,
+		operatorRestrictAttr: restrict,
+		operatorJoinAttr:     join,
+	} {
+		if normalizeFunctionSignature(d.Get(attr).(string)) != normalizeFunctionSignature(value) {
+			d.Set(attr, value)
+		}
+	}
+
+	d.Set(operatorNameAttr, op.name)
+	d.Set(operatorSchemaAttr, op.schemaName)
+	d.Set(operatorDatabaseAttr, op.database)
+	d.SetId(generateOperatorID(op.database, op.schemaName, op.name, op.leftType, op.rightType))
+
+	return nil
+}
+
+func resourcePostgreSQLOperatorDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(operatorSchemaAttr).(string)
+	operatorName := d.Get(operatorNameAttr).(string)
+
+	leftType := d.Get(operatorLeftTypeAttr).(string)
+	if leftType == "" {
+		leftType = "NONE"
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	sql := fmt.Sprintf("DROP OPERATOR %s.%s (%s, %s)",
+		pq.QuoteIdentifier(schemaName), operatorName, leftType, d.Get(operatorRightTypeAttr).(string),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop operator: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting operator: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+type operatorID struct {
+	database   string
+	schemaName string
+	name       string
+	leftType   string
+	rightType  string
+}
+
+// generateOperatorID uses | as separator as type names can contain dots (schema qualified)
+// and spaces (e.g.: character varying). The left type is empty for prefix operators.
+func generateOperatorID(database, schemaName, name, leftType, rightType string) string {
+	return strings.Join([]string{database, schemaName, name, leftType, rightType}, "|")
+}
+
+// getDBOperator returns the identity of the operator. If we are importing this resource,
+// it will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise it will be simply get from the state.
+func getDBOperator(d *schema.ResourceData, client *Client) (operatorID, error) {
+	op := operatorID{
+		database:   getDatabase(d, client.databaseName),
+		schemaName: d.Get(operatorSchemaAttr).(string),
+		name:       d.Get(operatorNameAttr).(string),
+		leftType:   d.Get(operatorLeftTypeAttr).(string),
+		rightType:  d.Get(operatorRightTypeAttr).(string),
+	}
+
+	// When importing, we have to parse the ID to find the operator.
+	if op.name == "" {
+		parsed := strings.Split(d.Id(), "|")
+		if len(parsed) != 5 || parsed[2] == "" || parsed[4] == "" {
+			return operatorID{}, fmt.Errorf(
+				"operator ID %s has not the expected format 'database|schema|name|left_type|right_type': %v", d.Id(), parsed,
+			)
+		}
+		op = operatorID{
+			database:   parsed[0],
+			schemaName: parsed[1],
+			name:       parsed[2],
+			leftType:   parsed[3],
+			rightType:  parsed[4],
+		}
+	}
+	return op, nil
+}
@@ -0,0 +1,307 @@
+package postgresql
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	operatorClassNameAttr        = "name"
+	operatorClassSchemaAttr      = "schema"
+	operatorClassDatabaseAttr    = "database"
+	operatorClassIndexMethodAttr = "index_method"
+	operatorClassDataTypeAttr    = "data_type"
+	operatorClassDefaultAttr     = "default"
+	operatorClassFamilyAttr      = "family"
+	operatorClassStorageAttr     = "storage"
+	operatorClassOperatorAttr    = "operator"
+	operatorClassFunctionAttr    = "function"
+)
+
+func resourcePostgreSQLOperatorClass() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLOperatorClassCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLOperatorClassRead),
+		Delete: PGResourceFunc(resourcePostgreSQLOperatorClassDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			operatorClassNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the operator class",
+			},
+			operatorClassSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the operator class is created",
+			},
+			operatorClassDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the operator class is created",
+			},
+			operatorClassIndexMethodAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The index method of the operator class (e.g.: btree, hash, gist)",
+			},
+			operatorClassDataTypeAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: typeDiffSuppressFunc,
+				Description:      "The column data type the operator class is for",
+			},
+			operatorClassDefaultAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether the operator class is the default one for its data type and index method",
+			},
+			operatorClassFamilyAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: textSearchNameDiffSuppressFunc,
+				Description:      "The operator family the class is added to, defaults to a family with the name of the class",
+			},
+			operatorClassStorageAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: typeDiffSuppressFunc,
+				Description:      "The data type actually stored in the index, if different from the column data type",
+			},
+			operatorClassOperatorAttr: {
+				Type:         schema.TypeList,
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{operatorClassOperatorAttr, operatorClassFunctionAttr},
+				Description:  "The operators of the class",
+				Elem:         opMemberOperatorResource(true, true),
+			},
+			operatorClassFunctionAttr: {
+				Type:         schema.TypeList,
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{operatorClassOperatorAttr, operatorClassFunctionAttr},
+				Description:  "The support functions of the class",
+				Elem:         opMemberFunctionResource(true, true),
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLOperatorClassCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(operatorClassSchemaAttr).(string)
+	name := d.Get(operatorClassNameAttr).(string)
+	indexMethod := d.Get(operatorClassIndexMethodAttr).(string)
+
+	items := []string{}
+	for _, member := range append(
+		opMembersFromList(d.Get(operatorClassOperatorAttr).([]interface{}), false),
+		opMembersFromList(d.Get(operatorClassFunctionAttr).([]interface{}), true)...,
+	) {
+		if (member.leftType == "") != (member.rightType == "") {
+			return fmt.Errorf("both %s and %s must be set for the member %d of operator class %s", opMemberLeftTypeAttr, opMemberRightTypeAttr, member.number, name)
+		}
+		items = append(items, member.toSQL())
+	}
+	if v, ok := d.GetOk(operatorClassStorageAttr); ok {
+		items = append(items, "STORAGE "+v.(string))
+	}
+
+	b := bytes.NewBufferString("CREATE OPERATOR CLASS ")
+	fmt.Fprintf(b, "%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(name))
+	if d.Get(operatorClassDefaultAttr).(bool) {
+		b.WriteString(" DEFAULT")
+	}
+	fmt.Fprintf(b, " FOR TYPE %s USING %s", d.Get(operatorClassDataTypeAttr).(string), pq.QuoteIdentifier(indexMethod))
+	if v, ok := d.GetOk(operatorClassFamilyAttr); ok {
+		b.WriteString(" FAMILY " + v.(string))
+	}
+	b.WriteString(" AS " + strings.Join(items, ", "))
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("could not create operator class %s: %w", name, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating operator class: %w", err)
+	}
+
+	d.SetId(generateOperatorFamilyID(database, schemaName, indexMethod, name))
+
+	return resourcePostgreSQLOperatorClassReadImpl(db, d)
+}
+
+func resourcePostgreSQLOperatorClassRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLOperatorClassReadImpl(db, d)
+}
+
+func resourcePostgreSQLOperatorClassReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, indexMethod, name, err := parseOperatorFamilyID(
+		d, db.client, operatorClassNameAttr, operatorClassSchemaAttr, operatorClassIndexMethodAttr,
+	)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var opclassOID, familyOID, dataType, family, storage string
+	var isDefault bool
+	err = txn.QueryRow(
+		`SELECT c.oid::text, c.opcfamily::text, pg_catalog.format_type(c.opcintype, NULL), c.opcdefault, `+
+			`CASE WHEN fn.nspname IN ('pg_catalog', 'public') THEN f.opfname `+
+			`ELSE pg_catalog.quote_ident(fn.nspname) || '.' || pg_catalog.quote_ident(f.opfname) END, `+
+			`CASE WHEN c.opckeytype = 0 THEN '' ELSE pg_catalog.format_type(c.opckeytype, NULL) END `+
+			`FROM pg_catalog.pg_opclass c `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.opcnamespace `+
+			`JOIN pg_catalog.pg_am am ON am.oid = c.opcmethod `+
+			`JOIN pg_catalog.pg_opfamily f ON f.oid = c.opcfamily `+
+			`JOIN pg_catalog.pg_namespace fn ON fn.oid = f.opfnamespace `+
+			`WHERE n.nspname = $1 AND am.amname = $2 AND c.opcname = $3`,
+		schemaName, indexMethod, name,
+	).Scan(&opclassOID, &familyOID, &dataType, &isDefault, &family, &storage)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL operator class %s.%s using %s not found in database %s", schemaName, name, indexMethod, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading operator class: %w", err)
+	}
+
+	operators, functions, err := readOpMembers(txn, familyOID, opclassOID)
+	if err != nil {
+		return err
+	}
+
+	d.Set(operatorClassNameAttr, name)
+	d.Set(operatorClassSchemaAttr, schemaName)
+	d.Set(operatorClassDatabaseAttr, database)
+	d.Set(operatorClassIndexMethodAttr, indexMethod)
+	d.Set(operatorClassDataTypeAttr, dataType)
+	d.Set(operatorClassDefaultAttr, isDefault)
+	d.Set(operatorClassFamilyAttr, family)
+	d.Set(operatorClassStorageAttr, storage)
+	d.Set(operatorClassOperatorAttr, opMembersToList(mergeOrderedOpMembers(
+		operators, opMembersFromList(d.Get(operatorClassOperatorAttr).([]interface{}), false),
+	)))
+	d.Set(operatorClassFunctionAttr, opMembersToList(mergeOrderedOpMembers(
+		functions, opMembersFromList(d.Get(operatorClassFunctionAttr).([]interface{}), true),
+	)))
+	d.SetId(generateOperatorFamilyID(database, schemaName, indexMethod, name))
+
+	return nil
+}
+
+// mergeOrderedOpMembers returns the configured members if they all match the ones read from
+// the catalog, whatever their order, otherwise the members read from the catalog.
+func mergeOrderedOpMembers(read, configured []opMember) []opMember {
+	if len(read) != len(configured) {
+		return read
+	}
+	merged := mergeOpMembers(read, configured)
+	for _, m := range configured {
+		found := false
+		for _, r := range merged {
+			if m == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return read
+		}
+	}
+	return configured
+}
+
+func resourcePostgreSQLOperatorClassDelete(db *DBConnection, d *schema.ResourceData) error {
+	schemaName := d.Get(operatorClassSchemaAttr).(string)
+	name := d.Get(operatorClassNameAttr).(string)
+	indexMethod := pq.QuoteIdentifier(d.Get(operatorClassIndexMethodAttr).(string))
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var familyOID string
+	if err := txn.QueryRow(
+		`SELECT c.opcfamily::text FROM pg_catalog.pg_opclass c `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.opcnamespace `+
+			`JOIN pg_catalog.pg_am am ON am.oid = c.opcmethod `+
+			`WHERE n.nspname = $1 AND am.amname = $2 AND c.opcname = $3`,
+		schemaName, d.Get(operatorClassIndexMethodAttr).(string), name,
+	).Scan(&familyOID); err != nil {
+		return fmt.Errorf("could not read operator class family: %w", err)
+	}
+
+	query := fmt.Sprintf("DROP OPERATOR CLASS %s.%s USING %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(name), indexMethod)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not drop operator class: %w", err)
+	}
+
+	// Postgres creates a family with the name of the class if none is specified but does not drop it with the class.
+	var implicitFamily bool
+	if err := txn.QueryRow(
+		`SELECT f.opfname = $2 AND n.nspname = $3 `+
+			`AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_opclass c WHERE c.opcfamily = f.oid) `+
+			`AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_amop o WHERE o.amopfamily = f.oid) `+
+			`AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_amproc p WHERE p.amprocfamily = f.oid) `+
+			`FROM pg_catalog.pg_opfamily f JOIN pg_catalog.pg_namespace n ON n.oid = f.opfnamespace WHERE f.oid = $1`,
+		familyOID, name, schemaName,
+	).Scan(&implicitFamily); err != nil {
+		return fmt.Errorf("could not read operator class family: %w", err)
+	}
+	if implicitFamily {
+		query := fmt.Sprintf("DROP OPERATOR FAMILY %s.%s USING %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(name), indexMethod)
+		if _, err := txn.Exec(query); err != nil {
+			return fmt.Errorf("could not drop operator family: %w", err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting operator class: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
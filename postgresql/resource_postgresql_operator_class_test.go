@@ -0,0 +1,115 @@
+package postgresql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestMergeOrderedOpMembers(t *testing.T) {
+	read := []opMember{
+		{number: 1, name: "<", leftType: "integer", rightType: "integer"},
+		{number: 3, name: "=", leftType: "integer", rightType: "integer"},
+	}
+
+	var cases = []struct {
+		configured []opMember
+		expected   []opMember
+	}{
+		{
+			// The operand types default to the type of the class
+			configured: []opMember{{number: 3, name: "="}, {number: 1, name: "<"}},
+			expected:   []opMember{{number: 3, name: "="}, {number: 1, name: "<"}},
+		},
+		{
+			configured: []opMember{{number: 1, name: "<"}},
+			expected:   read,
+		},
+		{
+			configured: []opMember{{number: 1, name: "<"}, {number: 3, name: "<>"}},
+			expected:   read,
+		},
+	}
+
+	for _, c := range cases {
+		if out := mergeOrderedOpMembers(read, c.configured); !reflect.DeepEqual(out, c.expected) {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.expected)
+		}
+	}
+}
+
+func TestAccPostgresqlOperatorClass_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_operator_class" "test" {
+  database     = "%s"
+  name         = "test_int4_ops"
+  index_method = "btree"
+  data_type    = "int4"
+
+  operator {
+    strategy_number = 1
+    operator        = "<"
+  }
+  operator {
+    strategy_number = 2
+    operator        = "<="
+  }
+  operator {
+    strategy_number = 3
+    operator        = "="
+  }
+  operator {
+    strategy_number = 4
+    operator        = ">="
+  }
+  operator {
+    strategy_number = 5
+    operator        = ">"
+  }
+
+  function {
+    support_number = 1
+    function       = "btint4cmp(int4, int4)"
+  }
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlOperatorFamilyDestroy("postgresql_operator_class"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_operator_class.test", "id", fmt.Sprintf("%s.public.btree.test_int4_ops", dbName)),
+					resource.TestCheckResourceAttr("postgresql_operator_class.test", "family", "test_int4_ops"),
+					resource.TestCheckResourceAttr("postgresql_operator_class.test", "default", "false"),
+					resource.TestCheckResourceAttr("postgresql_operator_class.test", "operator.#", "5"),
+					resource.TestCheckResourceAttr("postgresql_operator_class.test", "function.#", "1"),
+					testAccCheckOperatorFamilyMembers(dbName, "test_int4_ops", 5, 1),
+				),
+			},
+			{
+				ResourceName:      "postgresql_operator_class.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.public.btree.test_int4_ops", dbName),
+				ImportStateVerify: true,
+				// The types and functions are imported as displayed by Postgres
+				ImportStateVerifyIgnore: []string{"data_type", "operator", "function"},
+			},
+		},
+	})
+}
@@ -0,0 +1,583 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	operatorFamilyNameAttr        = "name"
+	operatorFamilySchemaAttr      = "schema"
+	operatorFamilyDatabaseAttr    = "database"
+	operatorFamilyIndexMethodAttr = "index_method"
+	operatorFamilyOperatorAttr    = "operator"
+	operatorFamilyFunctionAttr    = "function"
+
+	opMemberStrategyNumberAttr = "strategy_number"
+	opMemberSupportNumberAttr  = "support_number"
+	opMemberOperatorAttr       = "operator"
+	opMemberFunctionAttr       = "function"
+	opMemberLeftTypeAttr       = "left_type"
+	opMemberRightTypeAttr      = "right_type"
+	opMemberForOrderByAttr     = "for_order_by"
+)
+
+func resourcePostgreSQLOperatorFamily() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLOperatorFamilyCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLOperatorFamilyRead),
+		Update: PGResourceFunc(resourcePostgreSQLOperatorFamilyUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLOperatorFamilyDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			operatorFamilyNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the operator family",
+			},
+			operatorFamilySchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the operator family is created",
+			},
+			operatorFamilyDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the operator family is created",
+			},
+			operatorFamilyIndexMethodAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The index method of the operator family (e.g.: btree, hash, gist)",
+			},
+			operatorFamilyOperatorAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The operators of the family which are not part of an operator class",
+				Elem:        opMemberOperatorResource(false, false),
+			},
+			operatorFamilyFunctionAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The support functions of the family which are not part of an operator class",
+				Elem:        opMemberFunctionResource(false, false),
+			},
+		},
+	}
+}
+
+// opMemberOperatorResource returns the schema of an operator of an operator family or class.
+// The operand types are optional in an operator class, they default to the data type of the class.
+func opMemberOperatorResource(optionalTypes, forceNew bool) *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			opMemberStrategyNumberAttr: {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     forceNew,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The strategy number of the operator for the index method",
+			},
+			opMemberOperatorAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     forceNew,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the operator, optionally schema qualified",
+			},
+			opMemberLeftTypeAttr: {
+				Type:        schema.TypeString,
+				Required:    !optionalTypes,
+				Optional:    optionalTypes,
+				ForceNew:    forceNew,
+				Description: "The data type of the left operand of the operator",
+			},
+			opMemberRightTypeAttr: {
+				Type:        schema.TypeString,
+				Required:    !optionalTypes,
+				Optional:    optionalTypes,
+				ForceNew:    forceNew,
+				Description: "The data type of the right operand of the operator",
+			},
+			opMemberForOrderByAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    forceNew,
+				Description: "The btree operator family of an ordering operator, the operator is a search operator if empty",
+			},
+		},
+	}
+}
+
+// opMemberFunctionResource returns the schema of a support function of an operator family or class.
+func opMemberFunctionResource(optionalTypes, forceNew bool) *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			opMemberSupportNumberAttr: {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     forceNew,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The support function number for the index method",
+			},
+			opMemberFunctionAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     forceNew,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The signature of the support function (e.g.: btint4cmp(integer, integer))",
+			},
+			opMemberLeftTypeAttr: {
+				Type:        schema.TypeString,
+				Required:    !optionalTypes,
+				Optional:    optionalTypes,
+				ForceNew:    forceNew,
+				Description: "The left operand data type the function supports",
+			},
+			opMemberRightTypeAttr: {
+				Type:        schema.TypeString,
+				Required:    !optionalTypes,
+				Optional:    optionalTypes,
+				ForceNew:    forceNew,
+				Description: "The right operand data type the function supports",
+			},
+		},
+	}
+}
+
+// opMember is an operator or a support function of an operator family or class.
+type opMember struct {
+	isFunction bool
+	number     int
+	// The operator name or the function signature
+	name       string
+	leftType   string
+	rightType  string
+	forOrderBy string
+}
+
+func opMembersFromList(members []interface{}, isFunction bool) []opMember {
+	result := make([]opMember, 0, len(members))
+	for _, m := range members {
+		attrs := m.(map[string]interface{})
+		member := opMember{
+			isFunction: isFunction,
+			leftType:   attrs[opMemberLeftTypeAttr].(string),
+			rightType:  attrs[opMemberRightTypeAttr].(string),
+		}
+		if isFunction {
+			member.number = attrs[opMemberSupportNumberAttr].(int)
+			member.name = attrs[opMemberFunctionAttr].(string)
+		} else {
+			member.number = attrs[opMemberStrategyNumberAttr].(int)
+			member.name = attrs[opMemberOperatorAttr].(string)
+			member.forOrderBy = attrs[opMemberForOrderByAttr].(string)
+		}
+		result = append(result, member)
+	}
+	return result
+}
+
+func opMembersToList(members []opMember) []interface{} {
+	result := make([]interface{}, 0, len(members))
+	for _, m := range members {
+		attrs := map[string]interface{}{
+			opMemberLeftTypeAttr:  m.leftType,
+			opMemberRightTypeAttr: m.rightType,
+		}
+		if m.isFunction {
+			attrs[opMemberSupportNumberAttr] = m.number
+			attrs[opMemberFunctionAttr] = m.name
+		} else {
+			attrs[opMemberStrategyNumberAttr] = m.number
+			attrs[opMemberOperatorAttr] = m.name
+			attrs[opMemberForOrderByAttr] = m.forOrderBy
+		}
+		result = append(result, attrs)
+	}
+	return result
+}
+
+// toSQL returns the member as used in CREATE OPERATOR CLASS and ALTER OPERATOR FAMILY ADD
+// (e.g.: OPERATOR 1 < (integer, bigint) or FUNCTION 1 (integer, bigint) btint48cmp(integer, bigint))
+func (m opMember) toSQL() string {
+	var types string
+	if m.leftType != "" || m.rightType != "" {
+		types = " (" + strings.Join(nonEmptyStrings(m.leftType, m.rightType), ", ") + ")"
+	}
+
+	if m.isFunction {
+		return fmt.Sprintf("FUNCTION %d%s %s", m.number, types, m.name)
+	}
+
+	query := fmt.Sprintf("OPERATOR %d %s%s", m.number, m.name, types)
+	if m.forOrderBy != "" {
+		query += " FOR ORDER BY " + m.forOrderBy
+	}
+	return query
+}
+
+// toDropSQL returns the member as used in ALTER OPERATOR FAMILY DROP
+func (m opMember) toDropSQL() string {
+	kind := "OPERATOR"
+	if m.isFunction {
+		kind = "FUNCTION"
+	}
+	return fmt.Sprintf("%s %d (%s, %s)", kind, m.number, m.leftType, m.rightType)
+}
+
+// matches returns true if the member read from the catalog corresponds to the configured one,
+// the names being written differently (e.g.: int4 vs integer), and the operand types being optional
+// in the configuration of an operator class.
+func (m opMember) matches(configured opMember) bool {
+	normalizeName := normalizeOperatorName
+	if m.isFunction {
+		normalizeName = normalizeFunctionSignature
+	}
+	typeMatches := func(read, configured string) bool {
+		return configured == "" || normalizeOpMemberType(read) == normalizeOpMemberType(configured)
+	}
+
+	return m.isFunction == configured.isFunction &&
+		m.number == configured.number &&
+		normalizeName(m.name) == normalizeName(configured.name) &&
+		typeMatches(m.leftType, configured.leftType) &&
+		typeMatches(m.rightType, configured.rightType) &&
+		normalizeTextSearchName(m.forOrderBy) == normalizeTextSearchName(configured.forOrderBy)
+}
+
+// mergeOpMembers returns the members read from the catalog, keeping the configured representation
+// of the ones which match, so they don't produce a diff.
+func mergeOpMembers(read, configured []opMember) []opMember {
+	result := make([]opMember, 0, len(read))
+	used := make([]bool, len(configured))
+	for _, r := range read {
+		member := r
+		for i, c := range configured {
+			if !used[i] && r.matches(c) {
+				member = c
+				used[i] = true
+				break
+			}
+		}
+		result = append(result, member)
+	}
+	return result
+}
+
+func normalizeOperatorName(name string) string {
+	name = strings.TrimSpace(name)
+	for _, prefix := range []string{"pg_catalog.", "public."} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+func normalizeOpMemberType(typeName string) string {
+	return strings.TrimPrefix(normalizeTypeName(typeName), "public.")
+}
+
+func nonEmptyStrings(values ...string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// readOpMembers returns the operators and support functions of an operator family.
+// If opclassOID is set, only the members of this operator class are returned, otherwise only the
+// members which are not part of an operator class (loosely bound to the family) are returned.
+func readOpMembers(txn *sql.Tx, familyOID, opclassOID string) ([]opMember, []opMember, error) {
+	dependency := `EXISTS (SELECT 1 FROM pg_catalog.pg_depend dep WHERE dep.classid = '%[1]s'::regclass ` +
+		`AND dep.objid = %[2]s.oid AND dep.refclassid = 'pg_catalog.pg_opclass'::regclass`
+	filter := func(catalog, alias string) string {
+		if opclassOID != "" {
+			return fmt.Sprintf(dependency+" AND dep.refobjid = $2)", catalog, alias)
+		}
+		return fmt.Sprintf("NOT "+dependency+")", catalog, alias)
+	}
+	args := []interface{}{familyOID}
+	if opclassOID != "" {
+		args = append(args, opclassOID)
+	}
+
+	operators, err := queryOpMembers(txn, false,
+		`SELECT o.amopstrategy, o.amopopr::regoper::text, `+
+			`pg_catalog.format_type(o.amoplefttype, NULL), pg_catalog.format_type(o.amoprighttype, NULL), `+
+			`COALESCE((SELECT CASE WHEN n.nspname IN ('pg_catalog', 'public') THEN f.opfname `+
+			`ELSE pg_catalog.quote_ident(n.nspname) || '.' || pg_catalog.quote_ident(f.opfname) END `+
+			`FROM pg_catalog.pg_opfamily f JOIN pg_catalog.pg_namespace n ON n.oid = f.opfnamespace `+
+			`WHERE f.oid = o.amopsortfamily), '') `+
+			`FROM pg_catalog.pg_amop o WHERE o.amopfamily = $1 AND `+filter("pg_catalog.pg_amop", "o")+` `+
+			`ORDER BY o.amopstrategy, o.amoplefttype, o.amoprighttype`,
+		args...,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read operators: %w", err)
+	}
+
+	functions, err := queryOpMembers(txn, true,
+		`SELECT p.amprocnum, p.amproc::regprocedure::text, `+
+			`pg_catalog.format_type(p.amproclefttype, NULL), pg_catalog.format_type(p.amprocrighttype, NULL), '' `+
+			`FROM pg_catalog.pg_amproc p WHERE p.amprocfamily = $1 AND `+filter("pg_catalog.pg_amproc", "p")+` `+
+			`ORDER BY p.amprocnum, p.amproclefttype, p.amprocrighttype`,
+		args...,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read support functions: %w", err)
+	}
+
+	return operators, functions, nil
+}
+
+func queryOpMembers(txn *sql.Tx, isFunction bool, query string, args ...interface{}) ([]opMember, error) {
+	rows, err := txn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []opMember{}
+	for rows.Next() {
+		member := opMember{isFunction: isFunction}
+		if err := rows.Scan(&member.number, &member.name, &member.leftType, &member.rightType, &member.forOrderBy); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func resourcePostgreSQLOperatorFamilyCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(operatorFamilySchemaAttr).(string)
+	name := d.Get(operatorFamilyNameAttr).(string)
+	indexMethod := d.Get(operatorFamilyIndexMethodAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := fmt.Sprintf("CREATE OPERATOR FAMILY %s.%s USING %s",
+		pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(name), pq.QuoteIdentifier(indexMethod),
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not create operator family %s: %w", name, err)
+	}
+
+	members := append(
+		opMembersFromList(d.Get(operatorFamilyOperatorAttr).(*schema.Set).List(), false),
+		opMembersFromList(d.Get(operatorFamilyFunctionAttr).(*schema.Set).List(), true)...,
+	)
+	if err := alterOperatorFamilyMembers(txn, d, nil, members); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating operator family: %w", err)
+	}
+
+	d.SetId(generateOperatorFamilyID(database, schemaName, indexMethod, name))
+
+	return resourcePostgreSQLOperatorFamilyReadImpl(db, d)
+}
+
+func resourcePostgreSQLOperatorFamilyRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLOperatorFamilyReadImpl(db, d)
+}
+
+func resourcePostgreSQLOperatorFamilyReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, indexMethod, name, err := getDBOperatorFamilyName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var familyOID string
+	err = txn.QueryRow(
+		`SELECT f.oid::text FROM pg_catalog.pg_opfamily f `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = f.opfnamespace `+
+			`JOIN pg_catalog.pg_am am ON am.oid = f.opfmethod `+
+			`WHERE n.nspname = $1 AND am.amname = $2 AND f.opfname = $3`,
+		schemaName, indexMethod, name,
+	).Scan(&familyOID)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL operator family %s.%s using %s not found in database %s", schemaName, name, indexMethod, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading operator family: %w", err)
+	}
+
+	operators, functions, err := readOpMembers(txn, familyOID, "")
+	if err != nil {
+		return err
+	}
+
+	d.Set(operatorFamilyNameAttr, name)
+	d.Set(operatorFamilySchemaAttr, schemaName)
+	d.Set(operatorFamilyDatabaseAttr, database)
+	d.Set(operatorFamilyIndexMethodAttr, indexMethod)
+	d.Set(operatorFamilyOperatorAttr, opMembersToList(mergeOpMembers(
+		operators, opMembersFromList(d.Get(operatorFamilyOperatorAttr).(*schema.Set).List(), false),
+	)))
+	d.Set(operatorFamilyFunctionAttr, opMembersToList(mergeOpMembers(
+		functions, opMembersFromList(d.Get(operatorFamilyFunctionAttr).(*schema.Set).List(), true),
+	)))
+	d.SetId(generateOperatorFamilyID(database, schemaName, indexMethod, name))
+
+	return nil
+}
+
+func resourcePostgreSQLOperatorFamilyUpdate(db *DBConnection, d *schema.ResourceData) error {
+	var removed, added []opMember
+	for attr, isFunction := range map[string]bool{operatorFamilyOperatorAttr: false, operatorFamilyFunctionAttr: true} {
+		if !d.HasChange(attr) {
+			continue
+		}
+		oraw, nraw := d.GetChange(attr)
+		oldSet, newSet := oraw.(*schema.Set), nraw.(*schema.Set)
+		removed = append(removed, opMembersFromList(oldSet.Difference(newSet).List(), isFunction)...)
+		added = append(added, opMembersFromList(newSet.Difference(oldSet).List(), isFunction)...)
+	}
+
+	if len(removed) == 0 && len(added) == 0 {
+		return resourcePostgreSQLOperatorFamilyReadImpl(db, d)
+	}
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := alterOperatorFamilyMembers(txn, d, removed, added); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating operator family: %w", err)
+	}
+
+	return resourcePostgreSQLOperatorFamilyReadImpl(db, d)
+}
+
+// alterOperatorFamilyMembers drops the removed members then adds the new ones,
+// a member whose operator or function changed being dropped and added again.
+func alterOperatorFamilyMembers(txn *sql.Tx, d *schema.ResourceData, removed, added []opMember) error {
+	prefix := fmt.Sprintf("ALTER OPERATOR FAMILY %s.%s USING %s",
+		pq.QuoteIdentifier(d.Get(operatorFamilySchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(operatorFamilyNameAttr).(string)),
+		pq.QuoteIdentifier(d.Get(operatorFamilyIndexMethodAttr).(string)),
+	)
+
+	if len(removed) > 0 {
+		items := make([]string, len(removed))
+		for i, m := range removed {
+			items[i] = m.toDropSQL()
+		}
+		if _, err := txn.Exec(prefix + " DROP " + strings.Join(items, ", ")); err != nil {
+			return fmt.Errorf("could not drop operator family members: %w", err)
+		}
+	}
+
+	if len(added) > 0 {
+		items := make([]string, len(added))
+		for i, m := range added {
+			items[i] = m.toSQL()
+		}
+		if _, err := txn.Exec(prefix + " ADD " + strings.Join(items, ", ")); err != nil {
+			return fmt.Errorf("could not add operator family members: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLOperatorFamilyDelete(db *DBConnection, d *schema.ResourceData) error {
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := fmt.Sprintf("DROP OPERATOR FAMILY %s.%s USING %s",
+		pq.QuoteIdentifier(d.Get(operatorFamilySchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(operatorFamilyNameAttr).(string)),
+		pq.QuoteIdentifier(d.Get(operatorFamilyIndexMethodAttr).(string)),
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not drop operator family: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting operator family: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// generateOperatorFamilyID is also used for operator classes,
+// their names are unique per schema and index method.
+func generateOperatorFamilyID(database, schemaName, indexMethod, name string) string {
+	return joinResourceID(database, schemaName, indexMethod, name)
+}
+
+// getDBOperatorFamilyName returns database, schema, index method and name of an operator family.
+// If we are importing this resource, they will be parsed from the resource ID (it will return an error
+// if parsing failed) otherwise they will be simply get from the state.
+func getDBOperatorFamilyName(d *schema.ResourceData, client *Client) (string, string, string, string, error) {
+	return parseOperatorFamilyID(d, client, operatorFamilyNameAttr, operatorFamilySchemaAttr, operatorFamilyIndexMethodAttr)
+}
+
+func parseOperatorFamilyID(d *schema.ResourceData, client *Client, nameAttr, schemaAttr, indexMethodAttr string) (string, string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(schemaAttr).(string)
+	indexMethod := d.Get(indexMethodAttr).(string)
+	name := d.Get(nameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema, index method and name.
+	if name == "" {
+		parsed := splitResourceIDN(d.Id(), 4)
+		if len(parsed) != 4 || parsed[3] == "" {
+			return "", "", "", "", fmt.Errorf(
+				"ID %s has not the expected format 'database.schema.index_method.name': %v", d.Id(), parsed,
+			)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		indexMethod = parsed[2]
+		name = parsed[3]
+	}
+	return database, schemaName, indexMethod, name, nil
+}
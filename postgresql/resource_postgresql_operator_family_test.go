@@ -0,0 +1,210 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestOpMemberSQL(t *testing.T) {
+	var cases = []struct {
+		member   opMember
+		expected string
+		drop     string
+	}{
+		{
+			member:   opMember{number: 1, name: "<", leftType: "integer", rightType: "bigint"},
+			expected: "OPERATOR 1 < (integer, bigint)",
+			drop:     "OPERATOR 1 (integer, bigint)",
+		},
+		{
+			member:   opMember{number: 15, name: "app.<->", leftType: "point", rightType: "point", forOrderBy: "float_ops"},
+			expected: "OPERATOR 15 app.<-> (point, point) FOR ORDER BY float_ops",
+			drop:     "OPERATOR 15 (point, point)",
+		},
+		{
+			member:   opMember{isFunction: true, number: 1, name: "btint48cmp(integer, bigint)", leftType: "integer", rightType: "bigint"},
+			expected: "FUNCTION 1 (integer, bigint) btint48cmp(integer, bigint)",
+			drop:     "FUNCTION 1 (integer, bigint)",
+		},
+		{
+			member:   opMember{isFunction: true, number: 1, name: "btint4cmp(int4, int4)"},
+			expected: "FUNCTION 1 btint4cmp(int4, int4)",
+		},
+	}
+
+	for _, c := range cases {
+		if out := c.member.toSQL(); out != c.expected {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.expected)
+		}
+		if c.drop == "" {
+			continue
+		}
+		if out := c.member.toDropSQL(); out != c.drop {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.drop)
+		}
+	}
+}
+
+func TestMergeOpMembers(t *testing.T) {
+	read := []opMember{
+		{number: 1, name: "<", leftType: "integer", rightType: "bigint"},
+		{number: 3, name: "=", leftType: "integer", rightType: "bigint"},
+		{isFunction: true, number: 1, name: "btint48cmp(integer, bigint)", leftType: "integer", rightType: "bigint"},
+	}
+	configured := []opMember{
+		{number: 1, name: "pg_catalog.<", leftType: "int4", rightType: "int8"},
+		{number: 3, name: "<>", leftType: "int4", rightType: "int8"},
+		{isFunction: true, number: 1, name: "BTINT48CMP(int4, int8)"},
+	}
+	expected := []opMember{configured[0], read[1], configured[2]}
+
+	if out := mergeOpMembers(read, configured); !reflect.DeepEqual(out, expected) {
+		t.Fatalf("Error matching output and expected: %#v vs %#v", out, expected)
+	}
+}
+
+func TestAccPostgresqlOperatorFamily_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_operator_family" "test" {
+  database     = "%s"
+  name         = "test_family"
+  index_method = "btree"
+
+  operator {
+    strategy_number = 1
+    operator        = "<"
+    left_type       = "int4"
+    right_type      = "int8"
+  }
+  %s
+  function {
+    support_number = 1
+    function       = "btint48cmp(int4, int8)"
+    left_type      = "integer"
+    right_type     = "bigint"
+  }
+}
+`
+	equalOperator := `
+  operator {
+    strategy_number = 3
+    operator        = "="
+    left_type       = "integer"
+    right_type      = "bigint"
+  }
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlOperatorFamilyDestroy("postgresql_operator_family"),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_operator_family.test", "id", fmt.Sprintf("%s.public.btree.test_family", dbName)),
+					resource.TestCheckResourceAttr("postgresql_operator_family.test", "operator.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_operator_family.test", "function.#", "1"),
+					testAccCheckOperatorFamilyMembers(dbName, "test_family", 1, 1),
+				),
+			},
+			{
+				// Adding a member alters the family
+				Config: fmt.Sprintf(config, dbName, equalOperator),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_operator_family.test", "operator.#", "2"),
+					testAccCheckOperatorFamilyMembers(dbName, "test_family", 2, 1),
+				),
+			},
+			{
+				ResourceName:      "postgresql_operator_family.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.public.btree.test_family", dbName),
+				ImportStateVerify: true,
+				// The types and functions are imported as displayed by Postgres
+				ImportStateVerifyIgnore: []string{"operator", "function"},
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, ""),
+				Check:  testAccCheckOperatorFamilyMembers(dbName, "test_family", 1, 1),
+			},
+		},
+	})
+}
+
+// testAccCheckPostgresqlOperatorFamilyDestroy checks that the families of the resources are dropped,
+// for operator classes it checks the family created implicitly with the name of the class.
+func testAccCheckPostgresqlOperatorFamilyDestroy(resourceType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			txn, err := startTransaction(client, rs.Primary.Attributes["database"])
+			if err != nil {
+				return err
+			}
+			defer deferredRollback(txn)
+
+			var _rez bool
+			err = txn.QueryRow("SELECT TRUE FROM pg_catalog.pg_opfamily WHERE opfname = $1", rs.Primary.Attributes["name"]).Scan(&_rez)
+			switch {
+			case err == sql.ErrNoRows:
+				continue
+			case err != nil:
+				return fmt.Errorf("Error checking operator family %s", err)
+			}
+
+			return fmt.Errorf("Operator family still exists after destroy")
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckOperatorFamilyMembers checks the number of operators and support functions of the family.
+func testAccCheckOperatorFamilyMembers(dbName, family string, operators, functions int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var operatorCount, functionCount int
+		if err := txn.QueryRow(
+			`SELECT (SELECT count(*) FROM pg_amop WHERE amopfamily = f.oid), `+
+				`(SELECT count(*) FROM pg_amproc WHERE amprocfamily = f.oid) `+
+				`FROM pg_opfamily f WHERE f.opfname = $1`,
+			family,
+		).Scan(&operatorCount, &functionCount); err != nil {
+			return fmt.Errorf("could not read operator family members: %w", err)
+		}
+		if operatorCount != operators || functionCount != functions {
+			return fmt.Errorf(
+				"expected %d operators and %d functions in family %s, got %d and %d",
+				operators, functions, family, operatorCount, functionCount,
+			)
+		}
+		return nil
+	}
+}
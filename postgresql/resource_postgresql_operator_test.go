@@ -0,0 +1,156 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateOperatorName(t *testing.T) {
+	for _, name := range []string{"=", "===", "<->", "@@", "~>~"} {
+		if _, errs := validateOperatorName(name, "name"); len(errs) > 0 {
+			t.Errorf("validateOperatorName(%q) returned unexpected errors: %v", name, errs)
+		}
+	}
+	for _, name := range []string{"", "eq", "= =", "=;"} {
+		if _, errs := validateOperatorName(name, "name"); len(errs) == 0 {
+			t.Errorf("validateOperatorName(%q) should have returned an error", name)
+		}
+	}
+}
+
+func TestAccPostgresqlOperator_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TYPE test_schema.geo AS (x float8, y float8)")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE FUNCTION test_schema.geo_eq(test_schema.geo, test_schema.geo) RETURNS boolean IMMUTABLE LANGUAGE sql AS 'SELECT $1.x = $2.x AND $1.y = $2.y'")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE FUNCTION test_schema.geo_neg(test_schema.geo) RETURNS test_schema.geo IMMUTABLE LANGUAGE sql AS 'SELECT ROW(-$1.x, -$1.y)::test_schema.geo'")
+
+	config := fmt.Sprintf(`
+resource "postgresql_operator" "eq" {
+  database   = "%[1]s"
+  schema     = "test_schema"
+  name       = "==="
+  left_type  = "test_schema.geo"
+  right_type = "test_schema.geo"
+  function   = "test_schema.geo_eq"
+  commutator = "==="
+  restrict   = "eqsel"
+  join       = "eqjoinsel"
+}
+
+resource "postgresql_operator" "neg" {
+  database   = "%[1]s"
+  schema     = "test_schema"
+  name       = "~-"
+  right_type = "test_schema.geo"
+  function   = "test_schema.geo_neg"
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlOperatorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlOperatorExists("postgresql_operator.eq"),
+					resource.TestCheckResourceAttr("postgresql_operator.eq", "commutator", "==="),
+					resource.TestCheckResourceAttr("postgresql_operator.eq", "restrict", "eqsel"),
+					resource.TestCheckResourceAttr("postgresql_operator.eq", "join", "eqjoinsel"),
+					testAccCheckPostgresqlOperatorExists("postgresql_operator.neg"),
+					resource.TestCheckResourceAttr("postgresql_operator.neg", "left_type", ""),
+				),
+			},
+			{
+				ResourceName:      "postgresql_operator.eq",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s|test_schema|===|test_schema.geo|test_schema.geo", dbName),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "postgresql_operator.neg",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s|test_schema|~-||test_schema.geo", dbName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlOperatorDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_operator" {
+			continue
+		}
+
+		exists, err := checkOperatorExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking operator %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Operator still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlOperatorExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		exists, err := checkOperatorExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking operator %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Operator not found")
+		}
+
+		return nil
+	}
+}
+
+func checkOperatorExists(rs *terraform.ResourceState) (bool, error) {
+	client := testAccProvider.Meta().(*Client)
+	txn, err := startTransaction(client, rs.Primary.Attributes[operatorDatabaseAttr])
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	var _rez bool
+	err = txn.QueryRow(
+		"SELECT TRUE FROM pg_catalog.pg_operator o JOIN pg_catalog.pg_namespace n ON n.oid = o.oprnamespace "+
+			"WHERE n.nspname = $1 AND o.oprname = $2 AND o.oprright = pg_catalog.to_regtype($3)",
+		rs.Primary.Attributes[operatorSchemaAttr], rs.Primary.Attributes[operatorNameAttr], rs.Primary.Attributes[operatorRightTypeAttr],
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
@@ -18,15 +18,23 @@ func resourcePostgreSQLPhysicalReplicationSlot() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
 			},
 		},
 	}
 }
 
 func resourcePostgreSQLPhysicalReplicationSlotCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureReplicationSlot) {
+		return fmt.Errorf(
+			"postgresql_physical_replication_slot resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
 	name := d.Get("name").(string)
 	sql := "SELECT FROM pg_create_physical_replication_slot($1)"
 	if _, err := db.Exec(sql, name); err != nil {
@@ -38,6 +46,13 @@ func resourcePostgreSQLPhysicalReplicationSlotCreate(db *DBConnection, d *schema
 }
 
 func resourcePostgreSQLPhysicalReplicationSlotExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	if !db.featureSupported(featureReplicationSlot) {
+		return false, fmt.Errorf(
+			"postgresql_physical_replication_slot resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
 	query := "SELECT 1 FROM pg_catalog.pg_replication_slots WHERE slot_name = $1 and slot_type = 'physical'"
 	var unused int
 	err := db.QueryRow(query, d.Id()).Scan(&unused)
@@ -52,11 +67,24 @@ func resourcePostgreSQLPhysicalReplicationSlotExists(db *DBConnection, d *schema
 }
 
 func resourcePostgreSQLPhysicalReplicationSlotRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureReplicationSlot) {
+		return fmt.Errorf(
+			"postgresql_physical_replication_slot resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
 	d.Set("name", d.Id())
 	return nil
 }
 
 func resourcePostgreSQLPhysicalReplicationSlotDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureReplicationSlot) {
+		return fmt.Errorf(
+			"postgresql_physical_replication_slot resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
 
 	replicationSlotName := d.Get("name").(string)
 
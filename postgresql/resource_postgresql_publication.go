@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -17,18 +18,25 @@ const (
 	pubDatabaseAttr                = "database"
 	pubAllTablesAttr               = "all_tables"
 	pubTablesAttr                  = "tables"
+	pubTableAttr                   = "table"
+	pubTableNameAttr               = "name"
+	pubTableColumnsAttr            = "columns"
+	pubTableWhereAttr              = "where"
+	pubSchemasAttr                 = "schemas"
 	pubDropCascadeAttr             = "drop_cascade"
 	pubPublishAttr                 = "publish_param"
 	pubPublisViaPartitionRoothAttr = "publish_via_partition_root_param"
 )
 
 func resourcePostgreSQLPublication() *schema.Resource {
-	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLPublicationCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLPublicationRead),
-		Delete: PGResourceFunc(resourcePostgreSQLPublicationDelete),
-		Update: PGResourceFunc(resourcePostgreSQLPublicationUpdate),
-		Exists: PGResourceExistsFunc(resourcePostgreSQLPublicationExists),
+	resource := &schema.Resource{
+		// Version 1 escapes the dots of the names in the ID
+		SchemaVersion: 1,
+		Create:        PGResourceFunc(resourcePostgreSQLPublicationCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLPublicationRead),
+		Delete:        PGResourceFunc(resourcePostgreSQLPublicationDelete),
+		Update:        PGResourceFunc(resourcePostgreSQLPublicationUpdate),
+		Exists:        PGResourceExistsFunc(resourcePostgreSQLPublicationExists),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -38,7 +46,7 @@ func resourcePostgreSQLPublication() *schema.Resource {
 				Type:         schema.TypeString,
 				Required:     true,
 				ForceNew:     false,
-				ValidateFunc: validation.StringIsNotEmpty,
+				ValidateFunc: validateIdentifier,
 			},
 			pubDatabaseAttr: {
 				Type:        schema.TypeString,
@@ -64,6 +72,42 @@ func resourcePostgreSQLPublication() *schema.Resource {
 				Description:   "Sets the tables list to publish",
 				ConflictsWith: []string{pubAllTablesAttr},
 			},
+			pubTableAttr: {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Description:   "Sets the tables to publish with their column list and row filter (PostgreSQL 15+)",
+				ConflictsWith: []string{pubTablesAttr, pubAllTablesAttr},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						pubTableNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The table to publish ('schema.table')",
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						pubTableColumnsAttr: {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The columns of the table to publish (all columns if empty)",
+						},
+						pubTableWhereAttr: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The row filter expression, only rows for which it evaluates to true are published",
+
+							DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+						},
+					},
+				},
+			},
+			pubSchemasAttr: {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Description:   "Sets the schemas whose tables (including the ones created later) are published (PostgreSQL 15+)",
+				ConflictsWith: []string{pubAllTablesAttr},
+			},
 			pubAllTablesAttr: {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -93,13 +137,20 @@ func resourcePostgreSQLPublication() *schema.Resource {
 			},
 		},
 	}
+	resource.StateUpgraders = []schema.StateUpgrader{
+		resourceIDStateUpgrader(resource, 0, func(rawState map[string]interface{}) string {
+			return databaseObjectIDFromRawState(rawState, pubDatabaseAttr, pubNameAttr)
+		}),
+	}
+
+	return resource
 }
 
 func resourcePostgreSQLPublicationUpdate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featurePublication) {
 		return fmt.Errorf(
-			"postgresql_publication resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_publication resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -119,6 +170,14 @@ func resourcePostgreSQLPublicationUpdate(db *DBConnection, d *schema.ResourceDat
 		return fmt.Errorf("could not update publication tables: %w", err)
 	}
 
+	if err := setPubTableFilters(db, txn, d); err != nil {
+		return fmt.Errorf("could not update publication tables: %w", err)
+	}
+
+	if err := setPubSchemas(db, txn, d); err != nil {
+		return fmt.Errorf("could not update publication schemas: %w", err)
+	}
+
 	if err := setPubParams(txn, d, db.featureSupported(featurePublishViaRoot)); err != nil {
 		return fmt.Errorf("could not update publication tables: %w", err)
 	}
@@ -200,6 +259,140 @@ func setPubTables(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+// setPubTableFilters replaces the published tables, with their column list and row filter,
+// when the table blocks change.
+func setPubTableFilters(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(pubTableAttr) {
+		return nil
+	}
+
+	if err := checkPubTableFiltersSupport(db, d); err != nil {
+		return err
+	}
+
+	pubName := d.Get(pubNameAttr).(string)
+	tables := d.Get(pubTableAttr).([]interface{})
+
+	var query string
+	if len(tables) == 0 {
+		// SET TABLE needs at least one table (and would remove the published schemas), so we drop the previous ones.
+		oraw, _ := d.GetChange(pubTableAttr)
+		var oldTables []string
+		for _, raw := range oraw.([]interface{}) {
+			oldTables = append(oldTables, quotePubTableName(raw.(map[string]interface{})[pubTableNameAttr].(string)))
+		}
+		if len(oldTables) == 0 {
+			return nil
+		}
+		query = fmt.Sprintf("ALTER PUBLICATION %s DROP TABLE %s", pq.QuoteIdentifier(pubName), strings.Join(oldTables, ", "))
+	} else {
+		query = fmt.Sprintf("ALTER PUBLICATION %s SET TABLE %s", pq.QuoteIdentifier(pubName), pubTableFiltersToSQL(tables))
+		// SET replaces the whole publication content, including the published schemas.
+		if schemas := d.Get(pubSchemasAttr).(*schema.Set); schemas.Len() > 0 {
+			query += ", TABLES IN SCHEMA " + setToPgIdentListWithoutSchema(schemas)
+		}
+	}
+
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not alter publication table: %w", err)
+	}
+	return nil
+}
+
+// setPubSchemas adds and drops the published schemas when they change.
+func setPubSchemas(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(pubSchemasAttr) {
+		return nil
+	}
+
+	if err := checkPubSchemasSupport(db, d); err != nil {
+		return err
+	}
+
+	// The SET TABLE of the table blocks has already replaced the published schemas.
+	if d.HasChange(pubTableAttr) && len(d.Get(pubTableAttr).([]interface{})) > 0 {
+		return nil
+	}
+
+	oraw, nraw := d.GetChange(pubSchemasAttr)
+	added := nraw.(*schema.Set).Difference(oraw.(*schema.Set))
+	dropped := oraw.(*schema.Set).Difference(nraw.(*schema.Set))
+	pubName := pq.QuoteIdentifier(d.Get(pubNameAttr).(string))
+
+	var queries []string
+	if added.Len() > 0 {
+		queries = append(queries, fmt.Sprintf("ALTER PUBLICATION %s ADD TABLES IN SCHEMA %s", pubName, setToPgIdentListWithoutSchema(added)))
+	}
+	if dropped.Len() > 0 {
+		queries = append(queries, fmt.Sprintf("ALTER PUBLICATION %s DROP TABLES IN SCHEMA %s", pubName, setToPgIdentListWithoutSchema(dropped)))
+	}
+
+	for _, query := range queries {
+		if _, err := txn.Exec(query); err != nil {
+			return fmt.Errorf("could not alter publication schemas: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkPubSchemasSupport returns an error if schemas are published and the server does not support it.
+func checkPubSchemasSupport(db *DBConnection, d *schema.ResourceData) error {
+	if db.featureSupported(featurePublicationSchemas) || d.Get(pubSchemasAttr).(*schema.Set).Len() == 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"publishing schemas is supported only for postgres version 15 and above (%s)",
+		db.version,
+	)
+}
+
+// checkPubTableFiltersSupport returns an error if a column list or a row filter is set
+// and the server does not support them.
+func checkPubTableFiltersSupport(db *DBConnection, d *schema.ResourceData) error {
+	if db.featureSupported(featurePublicationTableFilters) {
+		return nil
+	}
+
+	for _, raw := range d.Get(pubTableAttr).([]interface{}) {
+		table := raw.(map[string]interface{})
+		if table[pubTableColumnsAttr].(*schema.Set).Len() > 0 || table[pubTableWhereAttr].(string) != "" {
+			return fmt.Errorf(
+				"publication column lists and row filters are supported only for postgres version 15 and above (%s)",
+				db.version,
+			)
+		}
+	}
+
+	return nil
+}
+
+func quotePubTableName(name string) string {
+	parts := strings.Split(name, ".")
+	for i := range parts {
+		parts[i] = pq.QuoteIdentifier(parts[i])
+	}
+	return strings.Join(parts, ".")
+}
+
+func pubTableFiltersToSQL(tables []interface{}) string {
+	var tlist []string
+	for _, raw := range tables {
+		table := raw.(map[string]interface{})
+		tableSQL := quotePubTableName(table[pubTableNameAttr].(string))
+
+		if columns := table[pubTableColumnsAttr].(*schema.Set); columns.Len() > 0 {
+			tableSQL += fmt.Sprintf(" (%s)", setToPgIdentListWithoutSchema(columns))
+		}
+		if where := table[pubTableWhereAttr].(string); where != "" {
+			tableSQL += fmt.Sprintf(" WHERE (%s)", where)
+		}
+
+		tlist = append(tlist, tableSQL)
+	}
+	return strings.Join(tlist, ", ")
+}
+
 func setPubParams(txn *sql.Tx, d *schema.ResourceData, pubViaRootEnabled bool) error {
 	pubName := d.Get(pubNameAttr).(string)
 	paramAlterTemplate := "ALTER PUBLICATION %s %s"
@@ -219,11 +412,19 @@ func setPubParams(txn *sql.Tx, d *schema.ResourceData, pubViaRootEnabled bool) e
 func resourcePostgreSQLPublicationCreate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featurePublication) {
 		return fmt.Errorf(
-			"postgresql_publication resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_publication resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
+	if err := checkPubTableFiltersSupport(db, d); err != nil {
+		return err
+	}
+
+	if err := checkPubSchemasSupport(db, d); err != nil {
+		return err
+	}
+
 	name := d.Get(pubNameAttr).(string)
 	databaseName := getDatabaseForPublication(d, db.client.databaseName)
 	tables, err := getTablesForPublication(d)
@@ -261,8 +462,8 @@ func resourcePostgreSQLPublicationCreate(db *DBConnection, d *schema.ResourceDat
 func resourcePostgreSQLPublicationExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
 	if !db.featureSupported(featurePublication) {
 		return false, fmt.Errorf(
-			"postgresql_publication resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_publication resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -304,8 +505,8 @@ func resourcePostgreSQLPublicationRead(db *DBConnection, d *schema.ResourceData)
 func resourcePostgreSQLPublicationReadImpl(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featurePublication) {
 		return fmt.Errorf(
-			"postgresql_publication resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_publication resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -354,11 +555,35 @@ func resourcePostgreSQLPublicationReadImpl(db *DBConnection, d *schema.ResourceD
 		return fmt.Errorf("Error reading publication info: %w", err)
 	}
 
+	schemas := []string{}
+	if db.featureSupported(featurePublicationSchemas) {
+		query = `SELECT COALESCE(array_agg(n.nspname::text ORDER BY n.nspname), '{}') ` +
+			`FROM pg_catalog.pg_publication_namespace pn ` +
+			`JOIN pg_catalog.pg_publication p ON p.oid = pn.pnpubid ` +
+			`JOIN pg_catalog.pg_namespace n ON n.oid = pn.pnnspid ` +
+			`WHERE p.pubname = $1`
+		if err := txn.QueryRow(query, PublicationName).Scan(pq.Array(&schemas)); err != nil {
+			return fmt.Errorf("could not get publication schemas: %w", err)
+		}
+	}
+
+	// The tables of the published schemas are listed in pg_publication_tables,
+	// we only keep the ones which are also explicitly published.
 	query = `SELECT CONCAT(schemaname,'.',tablename) as fulltablename ` +
-		`FROM pg_catalog.pg_publication_tables ` +
+		`FROM pg_catalog.pg_publication_tables pt ` +
 		`WHERE pubname = $1`
-
-	rows, err := txn.Query(query, pqQuoteLiteral(PublicationName))
+	args := []interface{}{pqQuoteLiteral(PublicationName)}
+	if len(schemas) > 0 {
+		query += ` AND (NOT schemaname = ANY($2) OR EXISTS (` +
+			`SELECT 1 FROM pg_catalog.pg_publication_rel pr ` +
+			`JOIN pg_catalog.pg_publication p ON p.oid = pr.prpubid ` +
+			`JOIN pg_catalog.pg_class c ON c.oid = pr.prrelid ` +
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+			`WHERE p.pubname = pt.pubname AND n.nspname = pt.schemaname AND c.relname = pt.tablename))`
+		args = append(args, pq.Array(schemas))
+	}
+
+	rows, err := txn.Query(query, args...)
 	if err != nil {
 		return fmt.Errorf("could not get publication tables: %w", err)
 	}
@@ -376,6 +601,27 @@ func resourcePostgreSQLPublicationReadImpl(db *DBConnection, d *schema.ResourceD
 		return fmt.Errorf("Got rows.Err: %w", err)
 	}
 
+	// The table blocks are read when they are configured, when some tables are published with a column list
+	// or a row filter, which the tables attribute cannot describe, and when importing the publication.
+	importing := d.Get(pubNameAttr).(string) == ""
+	readTableBlocks := importing || len(d.Get(pubTableAttr).([]interface{})) > 0
+	if !readTableBlocks && db.featureSupported(featurePublicationTableFilters) {
+		query = `SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_publication_rel pr ` +
+			`JOIN pg_catalog.pg_publication p ON p.oid = pr.prpubid ` +
+			`WHERE p.pubname = $1 AND (pr.prattrs IS NOT NULL OR pr.prqual IS NOT NULL))`
+		if err := txn.QueryRow(query, PublicationName).Scan(&readTableBlocks); err != nil {
+			return fmt.Errorf("could not get publication table filters: %w", err)
+		}
+	}
+
+	if readTableBlocks {
+		tableFilters, err := readPubTableFilters(db, txn, d, PublicationName)
+		if err != nil {
+			return err
+		}
+		d.Set(pubTableAttr, tableFilters)
+	}
+
 	if pubinsert {
 		publishParams = append(publishParams, "insert")
 	}
@@ -394,19 +640,90 @@ func resourcePostgreSQLPublicationReadImpl(db *DBConnection, d *schema.ResourceD
 	d.Set(pubDatabaseAttr, database)
 	d.Set(pubOwnerAttr, pubowner)
 	d.Set(pubTablesAttr, tables)
+	d.Set(pubSchemasAttr, schemas)
 	d.Set(pubAllTablesAttr, puballtables)
-	d.Set(pubPublishAttr, publishParams)
+	d.Set(pubPublishAttr, listInStateOrder(d, pubPublishAttr, publishParams))
 	if sliceContainsStr(columns, "pubviaroot") {
 		d.Set(pubPublisViaPartitionRoothAttr, pubviaroot)
 	}
 	return nil
 }
 
+// readPubTableFilters returns the published tables with their column list and row filter,
+// in the order of the state.
+func readPubTableFilters(db *DBConnection, txn *sql.Tx, d *schema.ResourceData, pubName string) ([]interface{}, error) {
+	columnsQuery, whereQuery := "'{}'::text[]", "''"
+	if db.featureSupported(featurePublicationTableFilters) {
+		columnsQuery = `COALESCE((SELECT array_agg(a.attname::text ORDER BY a.attnum) FROM pg_catalog.pg_attribute a ` +
+			`WHERE a.attrelid = pr.prrelid AND a.attnum = ANY(pr.prattrs)), '{}')`
+		whereQuery = `COALESCE(pg_catalog.pg_get_expr(pr.prqual, pr.prrelid), '')`
+	}
+
+	query := `SELECT n.nspname || '.' || c.relname, ` + columnsQuery + `, ` + whereQuery + ` ` +
+		`FROM pg_catalog.pg_publication_rel pr ` +
+		`JOIN pg_catalog.pg_publication p ON p.oid = pr.prpubid ` +
+		`JOIN pg_catalog.pg_class c ON c.oid = pr.prrelid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+		`WHERE p.pubname = $1 ORDER BY 1`
+	rows, err := txn.Query(query, pubName)
+	if err != nil {
+		return nil, fmt.Errorf("could not get publication tables: %w", err)
+	}
+	defer rows.Close()
+
+	stateTables := map[string]map[string]interface{}{}
+	for _, raw := range d.Get(pubTableAttr).([]interface{}) {
+		table := raw.(map[string]interface{})
+		stateTables[table[pubTableNameAttr].(string)] = table
+	}
+
+	dbTables := map[string]map[string]interface{}{}
+	var dbTableNames []string
+	for rows.Next() {
+		var name, where string
+		var columns []string
+		if err := rows.Scan(&name, pq.Array(&columns), &where); err != nil {
+			return nil, fmt.Errorf("could not get tables: %w", err)
+		}
+
+		// Postgres rewrites the row filter expression, so we keep the one from the state if there's still a filter.
+		if stateTable, ok := stateTables[name]; ok && where != "" && stateTable[pubTableWhereAttr].(string) != "" {
+			where = stateTable[pubTableWhereAttr].(string)
+		}
+
+		dbTables[name] = map[string]interface{}{
+			pubTableNameAttr:    name,
+			pubTableColumnsAttr: columns,
+			pubTableWhereAttr:   where,
+		}
+		dbTableNames = append(dbTableNames, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Got rows.Err: %w", err)
+	}
+
+	tables := make([]interface{}, 0, len(dbTables))
+	for _, raw := range d.Get(pubTableAttr).([]interface{}) {
+		name := raw.(map[string]interface{})[pubTableNameAttr].(string)
+		if table, ok := dbTables[name]; ok {
+			tables = append(tables, table)
+			delete(dbTables, name)
+		}
+	}
+	for _, name := range dbTableNames {
+		if table, ok := dbTables[name]; ok {
+			tables = append(tables, table)
+		}
+	}
+
+	return tables, nil
+}
+
 func resourcePostgreSQLPublicationDelete(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featurePublication) {
 		return fmt.Errorf(
-			"postgresql_publication resource is not supported for this Postgres version (%s)",
-			db.version,
+			"postgresql_publication resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -465,6 +782,17 @@ func getTablesForPublication(d *schema.ResourceData) (string, error) {
 		}
 		tablesString = fmt.Sprintf("FOR TABLE %s", strings.Join(tlist, ", "))
 	}
+	if v, ok := d.GetOk(pubTableAttr); ok {
+		tablesString = fmt.Sprintf("FOR TABLE %s", pubTableFiltersToSQL(v.([]interface{})))
+	}
+	if v, ok := d.GetOk(pubSchemasAttr); ok {
+		schemasString := fmt.Sprintf("TABLES IN SCHEMA %s", setToPgIdentListWithoutSchema(v.(*schema.Set)))
+		if tablesString == "" {
+			tablesString = "FOR " + schemasString
+		} else {
+			tablesString += ", " + schemasString
+		}
+	}
 
 	return tablesString, nil
 }
@@ -537,6 +865,7 @@ func getPublicationParameters(d *schema.ResourceData, pubViaRootEnabled bool) (s
 	for k, v := range pubParams {
 		paramsList = append(paramsList, fmt.Sprintf("%s = %s", k, v))
 	}
+	sort.Strings(paramsList)
 	if len(paramsList) > 0 {
 		returnValue = fmt.Sprintf(parmeterSQLTemplate, strings.Join(paramsList, ","))
 	}
@@ -544,10 +873,7 @@ func getPublicationParameters(d *schema.ResourceData, pubViaRootEnabled bool) (s
 }
 
 func generatePublicationID(d *schema.ResourceData, databaseName string) string {
-	return strings.Join([]string{
-		databaseName,
-		d.Get(pubNameAttr).(string),
-	}, ".")
+	return joinResourceID(databaseName, d.Get(pubNameAttr).(string))
 }
 
 // getDBPublicationName returns database and publication name. If we are importing this
@@ -559,7 +885,7 @@ func getDBPublicationName(d *schema.ResourceData, client *Client) (string, strin
 
 	// When importing, we have to parse the ID to find publication and database names.
 	if PublicationName == "" {
-		parsed := strings.Split(d.Id(), ".")
+		parsed := splitResourceID(d.Id())
 		if len(parsed) != 2 {
 			return "", "", fmt.Errorf("Publication ID %s has not the expected format 'database.publication_name': %v", d.Id(), parsed)
 		}
@@ -570,6 +896,6 @@ func getDBPublicationName(d *schema.ResourceData, client *Client) (string, strin
 }
 
 func getPublicationNameFromID(ID string) string {
-	splitted := strings.Split(ID, ".")
+	splitted := splitResourceID(ID)
 	return splitted[0]
 }
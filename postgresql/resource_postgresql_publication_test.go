@@ -200,6 +200,186 @@ func TestAccPostgresqlPublication_UpdateTables(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlPublication_TableFilters(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+	testTables := []string{"test_schema.test_table_1", "test_schema.test_table_2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	testAccPostgresqlPublicationTableFiltersConfig := fmt.Sprintf(`
+	resource "postgresql_publication" "test" {
+		name     = "publication"
+		database = "%s"
+
+		table {
+			name    = "test_schema.test_table_1"
+			columns = ["val", "test_column_one"]
+		}
+
+		table {
+			name  = "test_schema.test_table_2"
+			where = "test_column_one IS NOT NULL"
+		}
+	}
+	`, dbName)
+
+	testAccPostgresqlPublicationUpdateTableFiltersConfig := fmt.Sprintf(`
+	resource "postgresql_publication" "test" {
+		name     = "publication"
+		database = "%s"
+
+		table {
+			name    = "test_schema.test_table_1"
+			columns = ["val"]
+			where   = "val <> 'foo'"
+		}
+	}
+	`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePublicationTableFilters)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlPublicationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPostgresqlPublicationTableFiltersConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlPublicationExists("postgresql_publication.test"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.#", "2"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.0.name", "test_schema.test_table_1"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.0.columns.#", "2"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.0.where", ""),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.1.name", "test_schema.test_table_2"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.1.columns.#", "0"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.1.where", "test_column_one IS NOT NULL"),
+				),
+			},
+			{
+				Config: testAccPostgresqlPublicationUpdateTableFiltersConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlPublicationExists("postgresql_publication.test"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.#", "1"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.0.columns.#", "1"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.0.where", "val <> 'foo'"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", fmt.Sprintf("%s.#", pubTablesAttr), "1"),
+				),
+			},
+			{
+				// The column list and the row filter are read when importing
+				ResourceName: "postgresql_publication.test",
+				ImportState:  true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					attributes := states[0].Attributes
+					expected := map[string]string{
+						"table.#":           "1",
+						"table.0.name":      "test_schema.test_table_1",
+						"table.0.columns.#": "1",
+						"table.0.where":     "(val <> 'foo'::text)",
+					}
+					for key, value := range expected {
+						if attributes[key] != value {
+							return fmt.Errorf("expected %s to be %q, got %q", key, value, attributes[key])
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlPublication_Schemas(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+	testTables := []string{"test_schema.test_table_1", "test_schema.test_table_2", "dev_schema.test_table_3"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	testAccPostgresqlPublicationSchemasConfig := fmt.Sprintf(`
+	resource "postgresql_publication" "test" {
+		name     = "publication"
+		database = "%s"
+		schemas  = ["dev_schema"]
+
+		table {
+			name  = "test_schema.test_table_1"
+			where = "test_column_one IS NOT NULL"
+		}
+	}
+	`, dbName)
+
+	testAccPostgresqlPublicationUpdateSchemasConfig := fmt.Sprintf(`
+	resource "postgresql_publication" "test" {
+		name     = "publication"
+		database = "%s"
+		schemas  = ["test_schema"]
+	}
+	`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePublicationSchemas)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlPublicationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPostgresqlPublicationSchemasConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlPublicationExists("postgresql_publication.test"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "schemas.#", "1"),
+					resource.TestCheckTypeSetElemAttr(
+						"postgresql_publication.test", "schemas.*", "dev_schema"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.#", "1"),
+					// The tables of the published schemas are not listed
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", fmt.Sprintf("%s.#", pubTablesAttr), "1"),
+				),
+			},
+			{
+				Config: testAccPostgresqlPublicationUpdateSchemasConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlPublicationExists("postgresql_publication.test"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "schemas.#", "1"),
+					resource.TestCheckTypeSetElemAttr(
+						"postgresql_publication.test", "schemas.*", "test_schema"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "table.#", "0"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", fmt.Sprintf("%s.#", pubTablesAttr), "0"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccPostgresqlPublication_UpdatePublishParams(t *testing.T) {
 	skipIfNotAcc(t)
 
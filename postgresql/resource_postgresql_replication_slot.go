@@ -1,29 +1,42 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+const (
+	// Time to wait for the consumer of a slot to exit once it has been terminated
+	replicationSlotTerminateTimeout  = 10 * time.Second
+	replicationSlotTerminateInterval = 500 * time.Millisecond
+)
+
 func resourcePostgreSQLReplicationSlot() *schema.Resource {
-	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLReplicationSlotCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLReplicationSlotRead),
-		Delete: PGResourceFunc(resourcePostgreSQLReplicationSlotDelete),
-		Exists: PGResourceExistsFunc(resourcePostgreSQLReplicationSlotExists),
+	resource := &schema.Resource{
+		// Version 1 escapes the dots of the names in the ID
+		SchemaVersion: 1,
+		Create:        PGResourceFunc(resourcePostgreSQLReplicationSlotCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLReplicationSlotRead),
+		Update:        PGResourceFunc(resourcePostgreSQLReplicationSlotUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLReplicationSlotDelete),
+		Exists:        PGResourceExistsFunc(resourcePostgreSQLReplicationSlotExists),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourcePostgreSQLReplicationSlotCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
 			},
 			"database": {
 				Type:        schema.TypeString,
@@ -34,29 +47,111 @@ func resourcePostgreSQLReplicationSlot() *schema.Resource {
 			},
 			"plugin": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Sets the output plugin to use, required for logical replication slots",
+			},
+			"physical": {
+				Type:        schema.TypeBool,
+				Optional:    true,
 				ForceNew:    true,
-				Description: "Sets the output plugin to use",
+				Default:     false,
+				Description: "Create a physical replication slot instead of a logical one",
+			},
+			"two_phase": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Enable the decoding of prepared transactions, for logical replication slots",
+			},
+			"failover": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Enable the synchronization of the slot to the standbys, for logical replication slots",
+			},
+			"immediately_reserve": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Reserve the LSN of the physical replication slot immediately instead of on the first connection of a streaming replication client",
+			},
+			"force_drop": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Terminate the process consuming the replication slot when it is destroyed while active",
+			},
+			"restart_lsn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The oldest WAL which might be required by the consumer of the slot",
+			},
+			"active": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the replication slot is currently being used",
 			},
 		},
 	}
+	resource.StateUpgraders = []schema.StateUpgrader{
+		resourceIDStateUpgrader(resource, 0, func(rawState map[string]interface{}) string {
+			return databaseObjectIDFromRawState(rawState, "database", "name")
+		}),
+	}
+
+	return resource
+}
+
+func resourcePostgreSQLReplicationSlotCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Get("physical").(bool) {
+		for _, attr := range []string{"two_phase", "failover"} {
+			if diff.Get(attr).(bool) {
+				return fmt.Errorf("`%s` can not be set on a physical replication slot", attr)
+			}
+		}
+		if diff.Get("plugin").(string) != "" {
+			return fmt.Errorf("`plugin` can not be set on a physical replication slot")
+		}
+		return nil
+	}
+
+	if diff.Get("immediately_reserve").(bool) {
+		return fmt.Errorf("`immediately_reserve` can only be set on a physical replication slot")
+	}
+	if diff.NewValueKnown("plugin") && diff.Get("plugin").(string) == "" {
+		return fmt.Errorf("`plugin` is required for a logical replication slot")
+	}
+	return nil
 }
 
 func resourcePostgreSQLReplicationSlotCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureReplicationSlot) {
+		return fmt.Errorf(
+			"postgresql_replication_slot resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
 
 	name := d.Get("name").(string)
-	plugin := d.Get("plugin").(string)
 	databaseName := getDatabaseForReplicationSlot(d, db.client.databaseName)
 
+	sql, args, err := getReplicationSlotCreateQuery(db, d)
+	if err != nil {
+		return err
+	}
+
 	txn, err := startTransaction(db.client, databaseName)
 	if err != nil {
 		return err
 	}
 	defer deferredRollback(txn)
 
-	sql := "SELECT FROM pg_create_logical_replication_slot($1, $2)"
-	if _, err := txn.Exec(sql, name, plugin); err != nil {
-		return err
+	if _, err := txn.Exec(sql, args...); err != nil {
+		return fmt.Errorf("could not create replication slot %s: %w", name, err)
 	}
 
 	if err = txn.Commit(); err != nil {
@@ -68,7 +163,43 @@ func resourcePostgreSQLReplicationSlotCreate(db *DBConnection, d *schema.Resourc
 	return resourcePostgreSQLReplicationSlotReadImpl(db, d)
 }
 
+// getReplicationSlotCreateQuery returns the query, and its arguments, creating the replication slot.
+func getReplicationSlotCreateQuery(db *DBConnection, d *schema.ResourceData) (string, []interface{}, error) {
+	name := d.Get("name").(string)
+
+	if d.Get("physical").(bool) {
+		return "SELECT FROM pg_create_physical_replication_slot($1, $2)",
+			[]interface{}{name, d.Get("immediately_reserve").(bool)}, nil
+	}
+
+	twoPhase := d.Get("two_phase").(bool)
+	failover := d.Get("failover").(bool)
+	if twoPhase && !db.featureSupported(featureReplicationSlotTwoPhase) {
+		return "", nil, fmt.Errorf("two_phase replication slots are not supported %s", db.unsupportedServer())
+	}
+	if failover && !db.featureSupported(featureReplicationSlotFailover) {
+		return "", nil, fmt.Errorf("failover replication slots are not supported %s", db.unsupportedServer())
+	}
+
+	args := []interface{}{name, d.Get("plugin").(string)}
+	switch {
+	case failover:
+		// pg_create_logical_replication_slot(slot_name, plugin, temporary, twophase, failover)
+		return "SELECT FROM pg_create_logical_replication_slot($1, $2, false, $3, $4)", append(args, twoPhase, failover), nil
+	case twoPhase:
+		return "SELECT FROM pg_create_logical_replication_slot($1, $2, false, $3)", append(args, twoPhase), nil
+	default:
+		return "SELECT FROM pg_create_logical_replication_slot($1, $2)", args, nil
+	}
+}
+
 func resourcePostgreSQLReplicationSlotExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	if !db.featureSupported(featureReplicationSlot) {
+		return false, fmt.Errorf(
+			"postgresql_replication_slot resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
 
 	var ReplicationSlotName string
 
@@ -89,7 +220,8 @@ func resourcePostgreSQLReplicationSlotExists(db *DBConnection, d *schema.Resourc
 	}
 	defer deferredRollback(txn)
 
-	query := "SELECT slot_name FROM pg_catalog.pg_replication_slots WHERE slot_name = $1 and database = $2"
+	// physical replication slots are not bound to a database
+	query := "SELECT slot_name FROM pg_catalog.pg_replication_slots WHERE slot_name = $1 and COALESCE(database, $2) = $2"
 	err = txn.QueryRow(query, replicationSlotName, database).Scan(&ReplicationSlotName)
 	switch {
 	case err == sql.ErrNoRows:
@@ -105,7 +237,19 @@ func resourcePostgreSQLReplicationSlotRead(db *DBConnection, d *schema.ResourceD
 	return resourcePostgreSQLReplicationSlotReadImpl(db, d)
 }
 
+// resourcePostgreSQLReplicationSlotUpdate only applies force_drop, every other attribute forces a new slot.
+func resourcePostgreSQLReplicationSlotUpdate(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLReplicationSlotReadImpl(db, d)
+}
+
 func resourcePostgreSQLReplicationSlotReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureReplicationSlot) {
+		return fmt.Errorf(
+			"postgresql_replication_slot resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
 	database, replicationSlotName, err := getDBReplicationSlotName(d, db.client)
 	if err != nil {
 		return err
@@ -117,11 +261,28 @@ func resourcePostgreSQLReplicationSlotReadImpl(db *DBConnection, d *schema.Resou
 	}
 	defer deferredRollback(txn)
 
-	var replicationSlotPlugin string
-	query := `SELECT plugin ` +
-		`FROM pg_catalog.pg_replication_slots ` +
-		`WHERE slot_name = $1 AND database = $2`
-	err = txn.QueryRow(query, replicationSlotName, database).Scan(&replicationSlotPlugin)
+	var replicationSlotPlugin, slotType string
+	var restartLSN sql.NullString
+	var active, twoPhase, failover bool
+
+	columns := []string{"COALESCE(plugin, '')", "slot_type", "restart_lsn::text", "active"}
+	values := []interface{}{&replicationSlotPlugin, &slotType, &restartLSN, &active}
+	if db.featureSupported(featureReplicationSlotTwoPhase) {
+		columns = append(columns, "two_phase")
+		values = append(values, &twoPhase)
+	}
+	if db.featureSupported(featureReplicationSlotFailover) {
+		columns = append(columns, "failover")
+		values = append(values, &failover)
+	}
+
+	query := fmt.Sprintf(
+		`SELECT %s `+
+			`FROM pg_catalog.pg_replication_slots `+
+			`WHERE slot_name = $1 AND COALESCE(database, $2) = $2`,
+		strings.Join(columns, ", "),
+	)
+	err = txn.QueryRow(query, replicationSlotName, database).Scan(values...)
 	switch {
 	case err == sql.ErrNoRows:
 		log.Printf("[WARN] PostgreSQL ReplicationSlot (%s) not found for database %s", replicationSlotName, database)
@@ -133,17 +294,35 @@ func resourcePostgreSQLReplicationSlotReadImpl(db *DBConnection, d *schema.Resou
 
 	d.Set("name", replicationSlotName)
 	d.Set("plugin", replicationSlotPlugin)
+	d.Set("physical", slotType == "physical")
+	d.Set("two_phase", twoPhase)
+	d.Set("failover", failover)
+	d.Set("restart_lsn", restartLSN.String)
+	d.Set("active", active)
 	d.Set("database", database)
+	if _, ok := d.GetOkExists("force_drop"); !ok { //nolint:staticcheck
+		d.Set("force_drop", false)
+	}
 	d.SetId(generateReplicationSlotID(d, database))
 
 	return nil
 }
 
 func resourcePostgreSQLReplicationSlotDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureReplicationSlot) {
+		return fmt.Errorf(
+			"postgresql_replication_slot resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
 
 	replicationSlotName := d.Get("name").(string)
 	database := getDatabaseForReplicationSlot(d, db.client.databaseName)
 
+	if err := releaseReplicationSlot(db, replicationSlotName, d.Get("force_drop").(bool)); err != nil {
+		return err
+	}
+
 	txn, err := startTransaction(db.client, database)
 	if err != nil {
 		return err
@@ -164,6 +343,54 @@ func resourcePostgreSQLReplicationSlotDelete(db *DBConnection, d *schema.Resourc
 	return nil
 }
 
+// releaseReplicationSlot makes sure the replication slot is not active anymore before dropping it.
+// If force is true, its consumer is terminated, otherwise an error is returned.
+func releaseReplicationSlot(db *DBConnection, slotName string, force bool) error {
+	var activePID sql.NullInt64
+	query := "SELECT active_pid FROM pg_catalog.pg_replication_slots WHERE slot_name = $1"
+	if err := db.QueryRow(query, slotName).Scan(&activePID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return fmt.Errorf("could not read replication slot %s: %w", slotName, err)
+	}
+
+	if !activePID.Valid {
+		return nil
+	}
+	if !force {
+		return fmt.Errorf(
+			"replication slot %s is active for PID %d, set force_drop to terminate it before dropping the slot",
+			slotName, activePID.Int64,
+		)
+	}
+
+	log.Printf("[WARN] Terminating process %d using replication slot %s", activePID.Int64, slotName)
+	if _, err := db.Exec("SELECT pg_terminate_backend($1)", activePID.Int64); err != nil {
+		return fmt.Errorf("could not terminate process %d using replication slot %s: %w", activePID.Int64, slotName, err)
+	}
+
+	// The slot is released once the process has exited
+	deadline := time.Now().Add(replicationSlotTerminateTimeout)
+	for {
+		var active bool
+		query := "SELECT active FROM pg_catalog.pg_replication_slots WHERE slot_name = $1"
+		if err := db.QueryRow(query, slotName).Scan(&active); err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("could not read replication slot %s: %w", slotName, err)
+		}
+		if !active {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("replication slot %s is still active after terminating process %d", slotName, activePID.Int64)
+		}
+		time.Sleep(replicationSlotTerminateInterval)
+	}
+}
+
 func getDatabaseForReplicationSlot(d *schema.ResourceData, databaseName string) string {
 	if v, ok := d.GetOk("database"); ok {
 		databaseName = v.(string)
@@ -173,14 +400,11 @@ func getDatabaseForReplicationSlot(d *schema.ResourceData, databaseName string)
 }
 
 func generateReplicationSlotID(d *schema.ResourceData, databaseName string) string {
-	return strings.Join([]string{
-		databaseName,
-		d.Get("name").(string),
-	}, ".")
+	return joinResourceID(databaseName, d.Get("name").(string))
 }
 
 func getReplicationSlotNameFromID(ID string) string {
-	splitted := strings.Split(ID, ".")
+	splitted := splitResourceID(ID)
 	return splitted[0]
 }
 
@@ -193,7 +417,7 @@ func getDBReplicationSlotName(d *schema.ResourceData, client *Client) (string, s
 
 	// When importing, we have to parse the ID to find replication slot and database names.
 	if replicationSlotName == "" {
-		parsed := strings.Split(d.Id(), ".")
+		parsed := splitResourceID(d.Id())
 		if len(parsed) != 2 {
 			return "", "", fmt.Errorf("Replication Slot ID %s has not the expected format 'database.replication_slot': %v", d.Id(), parsed)
 		}
@@ -3,7 +3,9 @@ package postgresql
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -162,3 +164,163 @@ func checkReplicationSlotExists(txn *sql.Tx, slotName string) (bool, error) {
 
 	return true, nil
 }
+
+func TestAccPostgresqlReplicationSlot_Physical(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlReplicationSlotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "postgresql_replication_slot" "myslot" {
+					name                = "physical_slot"
+					physical            = true
+					immediately_reserve = true
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlReplicationSlotExists("postgresql_replication_slot.myslot"),
+					resource.TestCheckResourceAttr(
+						"postgresql_replication_slot.myslot", "physical", "true"),
+					resource.TestCheckResourceAttr(
+						"postgresql_replication_slot.myslot", "plugin", ""),
+					resource.TestCheckResourceAttr(
+						"postgresql_replication_slot.myslot", "active", "false"),
+					resource.TestCheckResourceAttrSet(
+						"postgresql_replication_slot.myslot", "restart_lsn"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_replication_slot.myslot",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"immediately_reserve", "force_drop", "restart_lsn"},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlReplicationSlot_TwoPhase(t *testing.T) {
+	skipIfNotAcc(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+			testCheckCompatibleVersion(t, featureReplicationSlotTwoPhase)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlReplicationSlotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "postgresql_replication_slot" "myslot" {
+					name      = "two_phase_slot"
+					plugin    = "test_decoding"
+					two_phase = true
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlReplicationSlotExists("postgresql_replication_slot.myslot"),
+					resource.TestCheckResourceAttr(
+						"postgresql_replication_slot.myslot", "physical", "false"),
+					resource.TestCheckResourceAttr(
+						"postgresql_replication_slot.myslot", "two_phase", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlReplicationSlot_ForceDrop(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffixPub, teardownPub := setupTestDatabase(t, true, true)
+	dbSuffixSub, teardownSub := setupTestDatabase(t, true, true)
+	defer teardownPub()
+	defer teardownSub()
+
+	dbNamePub, _ := getTestDBNames(dbSuffixPub)
+	dbNameSub, _ := getTestDBNames(dbSuffixSub)
+	config := getTestConfig(t)
+
+	// The subscription has to be dropped without its slot before dropping its database
+	defer func() {
+		dbExecute(t, config.connStr(dbNameSub), "ALTER SUBSCRIPTION slot_consumer DISABLE")
+		dbExecute(t, config.connStr(dbNameSub), "ALTER SUBSCRIPTION slot_consumer SET (slot_name = NONE)")
+		dbExecute(t, config.connStr(dbNameSub), "DROP SUBSCRIPTION slot_consumer")
+	}()
+
+	slotConfig := `
+	resource "postgresql_replication_slot" "myslot" {
+		name       = "active_slot"
+		plugin     = "pgoutput"
+		database   = "%s"
+		force_drop = %t
+	}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlReplicationSlotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(slotConfig, dbNamePub, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlReplicationSlotExists("postgresql_replication_slot.myslot"),
+					resource.TestCheckResourceAttr(
+						"postgresql_replication_slot.myslot", "active", "false"),
+				),
+			},
+			{
+				// Consume the slot with a subscription
+				PreConfig: func() {
+					dbExecute(t, config.connStr(dbNamePub), "CREATE PUBLICATION slot_publication")
+					dbExecute(t, config.connStr(dbNameSub), fmt.Sprintf(
+						"CREATE SUBSCRIPTION slot_consumer CONNECTION '%s' PUBLICATION slot_publication "+
+							"WITH (create_slot = false, slot_name = 'active_slot')",
+						getConnInfo(t, dbNamePub),
+					))
+					waitForReplicationSlotActive(t, "active_slot")
+				},
+				Config:      fmt.Sprintf(slotConfig, dbNamePub, false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("replication slot active_slot is active for PID [0-9]+"),
+			},
+			{
+				Config: fmt.Sprintf(slotConfig, dbNamePub, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"postgresql_replication_slot.myslot", "force_drop", "true"),
+				),
+			},
+		},
+	})
+}
+
+func waitForReplicationSlotActive(t *testing.T, slotName string) {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		t.Fatalf("could not connect to database: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		var active bool
+		if err := db.QueryRow(
+			"SELECT active FROM pg_catalog.pg_replication_slots WHERE slot_name = $1", slotName,
+		).Scan(&active); err != nil {
+			t.Fatalf("could not read replication slot %s: %v", slotName, err)
+		}
+		if active {
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Fatalf("replication slot %s is not active", slotName)
+}
@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"context"
 	"crypto/md5"
 	"database/sql"
 	"encoding/hex"
@@ -26,15 +27,20 @@ const (
 	roleLoginAttr                           = "login"
 	roleNameAttr                            = "name"
 	rolePasswordAttr                        = "password"
+	rolePasswordEncryptionAttr              = "password_encryption"
 	roleReplicationAttr                     = "replication"
 	roleSkipDropRoleAttr                    = "skip_drop_role"
 	roleSkipReassignOwnedAttr               = "skip_reassign_owned"
+	roleResetDefaultPrivilegesAttr          = "reset_default_privileges"
 	roleSuperuserAttr                       = "superuser"
 	roleValidUntilAttr                      = "valid_until"
 	roleRolesAttr                           = "roles"
+	roleMembersAttr                         = "members"
+	roleAdminsAttr                          = "admins"
 	roleSearchPathAttr                      = "search_path"
 	roleStatementTimeoutAttr                = "statement_timeout"
 	roleAssumeRoleAttr                      = "assume_role"
+	roleDefaultTablespaceAttr               = "default_tablespace"
 
 	// Deprecated options
 	roleDepEncryptedAttr = "encrypted"
@@ -42,20 +48,22 @@ const (
 
 func resourcePostgreSQLRole() *schema.Resource {
 	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLRoleCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLRoleRead),
-		Update: PGResourceFunc(resourcePostgreSQLRoleUpdate),
-		Delete: PGResourceFunc(resourcePostgreSQLRoleDelete),
-		Exists: PGResourceExistsFunc(resourcePostgreSQLRoleExists),
+		Create:      PGResourceFunc(resourcePostgreSQLRoleCreate),
+		ReadContext: PGResourceContextFunc(resourcePostgreSQLRoleRead),
+		Update:      PGResourceFunc(resourcePostgreSQLRoleUpdate),
+		Delete:      PGResourceFunc(resourcePostgreSQLRoleDelete),
+		Exists:      PGResourceExistsFunc(resourcePostgreSQLRoleExists),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourcePostgreSQLRoleCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			roleNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the role",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the role",
 			},
 			rolePasswordAttr: {
 				Type:        schema.TypeString,
@@ -76,6 +84,20 @@ func resourcePostgreSQLRole() *schema.Resource {
 				MinItems:    0,
 				Description: "Role(s) to grant to this new role",
 			},
+			roleMembersAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Role(s) which are members of this role",
+			},
+			roleAdminsAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Role(s) which are members of this role with the right to grant it to others",
+			},
 			roleSearchPathAttr: {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -89,6 +111,12 @@ func resourcePostgreSQLRole() *schema.Resource {
 				Default:     true,
 				Description: "Control whether the password is stored encrypted in the system catalogs",
 			},
+			rolePasswordEncryptionAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"md5", "scram-sha-256"}, false),
+				Description:  "The algorithm used to hash the password (sets password_encryption when the password is set)",
+			},
 			roleValidUntilAttr: {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -162,6 +190,12 @@ func resourcePostgreSQLRole() *schema.Resource {
 				Default:     false,
 				Description: "Skip actually running the REASSIGN OWNED command when removing a role from PostgreSQL",
 			},
+			roleResetDefaultPrivilegesAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Revoke the default privileges referencing the role in all databases before removing it from PostgreSQL",
+			},
 			roleStatementTimeoutAttr: {
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -173,6 +207,12 @@ func resourcePostgreSQLRole() *schema.Resource {
 				Optional:    true,
 				Description: "Role to switch to at login",
 			},
+			roleDefaultTablespaceAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateOptionalIdentifier,
+				Description:  "The tablespace in which the objects created by the role are stored by default",
+			},
 		},
 	}
 }
@@ -184,6 +224,10 @@ func resourcePostgreSQLRoleCreate(db *DBConnection, d *schema.ResourceData) erro
 	}
 	defer deferredRollback(txn)
 
+	if err := setPasswordEncryption(db, txn, d); err != nil {
+		return err
+	}
+
 	stringOpts := []struct {
 		hclKey string
 		sqlKey string
@@ -275,6 +319,12 @@ func resourcePostgreSQLRoleCreate(db *DBConnection, d *schema.ResourceData) erro
 		createOpts = append(createOpts, valStr)
 	}
 
+	// The memberships are part of CREATE ROLE, except on the forks not supporting its WITH syntax
+	// where they are granted afterwards.
+	if db.featureSupported(featureCreateRoleWith) {
+		createOpts = append(createOpts, roleMembershipClauses(d)...)
+	}
+
 	roleName := d.Get(roleNameAttr).(string)
 	createStr := strings.Join(createOpts, " ")
 	if len(createOpts) > 0 {
@@ -291,8 +341,14 @@ func resourcePostgreSQLRoleCreate(db *DBConnection, d *schema.ResourceData) erro
 		return fmt.Errorf("error creating role %s: %w", roleName, err)
 	}
 
-	if err = grantRoles(txn, d); err != nil {
-		return err
+	if !db.featureSupported(featureCreateRoleWith) {
+		if err = grantRoles(txn, d); err != nil {
+			return err
+		}
+
+		if err = setRoleMembers(txn, d); err != nil {
+			return err
+		}
 	}
 
 	if err = alterSearchPath(txn, d); err != nil {
@@ -311,6 +367,10 @@ func resourcePostgreSQLRoleCreate(db *DBConnection, d *schema.ResourceData) erro
 		return err
 	}
 
+	if err = setRoleDefaultTablespace(txn, d); err != nil {
+		return err
+	}
+
 	if err = txn.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -323,6 +383,14 @@ func resourcePostgreSQLRoleCreate(db *DBConnection, d *schema.ResourceData) erro
 func resourcePostgreSQLRoleDelete(db *DBConnection, d *schema.ResourceData) error {
 	roleName := d.Get(roleNameAttr).(string)
 
+	// The default privileges are reset in their own transactions before dropping the role,
+	// as they could temporarily grant the same roles as the transaction dropping it.
+	if !d.Get(roleSkipDropRoleAttr).(bool) && d.Get(roleResetDefaultPrivilegesAttr).(bool) {
+		if err := resetRoleDefaultPrivileges(db, roleName); err != nil {
+			return err
+		}
+	}
+
 	txn, err := startTransaction(db.client, "")
 	if err != nil {
 		return err
@@ -350,6 +418,17 @@ func resourcePostgreSQLRoleDelete(db *DBConnection, d *schema.ResourceData) erro
 	}
 	if !d.Get(roleSkipDropRoleAttr).(bool) {
 		if _, err := txn.Exec(fmt.Sprintf("DROP ROLE %s", pq.QuoteIdentifier(roleName))); err != nil {
+			// Postgres only reports the number of default privileges the role depends on in the other databases.
+			privileges, privilegesErr := getRoleDefaultPrivileges(db, roleName)
+			switch {
+			case privilegesErr != nil:
+				log.Printf("[WARN] could not list the default privileges of role %s: %v", roleName, privilegesErr)
+			case len(privileges) > 0:
+				return fmt.Errorf(
+					"could not delete role %s, it is still referenced by default privileges (set %s to revoke them): %s: %w",
+					roleName, roleResetDefaultPrivilegesAttr, strings.Join(formatRoleDefaultPrivileges(privileges), ", "), err,
+				)
+			}
 			return fmt.Errorf("could not delete role %s: %w", roleName, err)
 		}
 	}
@@ -363,6 +442,217 @@ func resourcePostgreSQLRoleDelete(db *DBConnection, d *schema.ResourceData) erro
 	return nil
 }
 
+// roleDefaultPrivilege is an entry of pg_default_acl referencing a role, either as owner or as grantee.
+// An empty grantee means that the entry has no privileges (e.g. all privileges were revoked from the owner).
+type roleDefaultPrivilege struct {
+	database   string
+	owner      string
+	schema     string
+	objectType string
+	grantee    string
+}
+
+// getRoleDefaultPrivileges returns the default privileges referencing the role
+// in each database accepting connections.
+func getRoleDefaultPrivileges(db *DBConnection, roleName string) ([]roleDefaultPrivilege, error) {
+	rows, err := db.Query("SELECT datname FROM pg_catalog.pg_database WHERE datallowconn ORDER BY datname")
+	if err != nil {
+		return nil, fmt.Errorf("could not list databases: %w", err)
+	}
+
+	var databases []string
+	for rows.Next() {
+		var dbName string
+		if err := rows.Scan(&dbName); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("could not scan database: %w", err)
+		}
+		databases = append(databases, dbName)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	privileges := []roleDefaultPrivilege{}
+	for _, dbName := range databases {
+		dbPrivileges, err := getDatabaseRoleDefaultPrivileges(db, dbName, roleName)
+		if err != nil {
+			return nil, fmt.Errorf("could not list default privileges of database %s: %w", dbName, err)
+		}
+		privileges = append(privileges, dbPrivileges...)
+	}
+
+	return privileges, nil
+}
+
+func getDatabaseRoleDefaultPrivileges(db *DBConnection, database, roleName string) ([]roleDefaultPrivilege, error) {
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return nil, err
+	}
+	defer deferredRollback(txn)
+
+	rows, err := txn.Query(
+		`SELECT DISTINCT pg_catalog.pg_get_userbyid(da.defaclrole), COALESCE(n.nspname, ''), da.defaclobjtype::text, `+
+			`CASE WHEN a.grantee IS NULL THEN '' WHEN a.grantee = 0 THEN 'public' ELSE pg_catalog.pg_get_userbyid(a.grantee) END `+
+			`FROM pg_catalog.pg_default_acl da `+
+			`LEFT JOIN pg_catalog.pg_namespace n ON n.oid = da.defaclnamespace `+
+			`LEFT JOIN LATERAL pg_catalog.aclexplode(da.defaclacl) a ON TRUE `+
+			`WHERE pg_catalog.pg_get_userbyid(da.defaclrole) = $1 `+
+			`OR a.grantee = (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $1) `+
+			`ORDER BY 1, 2, 3, 4`,
+		roleName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	privileges := []roleDefaultPrivilege{}
+	for rows.Next() {
+		privilege := roleDefaultPrivilege{database: database}
+		if err := rows.Scan(&privilege.owner, &privilege.schema, &privilege.objectType, &privilege.grantee); err != nil {
+			return nil, err
+		}
+		privileges = append(privileges, privilege)
+	}
+
+	return privileges, rows.Err()
+}
+
+func formatRoleDefaultPrivileges(privileges []roleDefaultPrivilege) []string {
+	formatted := make([]string, 0, len(privileges))
+	for _, p := range privileges {
+		scope := "all schemas"
+		if p.schema != "" {
+			scope = "schema " + p.schema
+		}
+		grantee := ""
+		if p.grantee != "" {
+			grantee = " to " + p.grantee
+		}
+		formatted = append(formatted, fmt.Sprintf(
+			"%ss created by %s in %s of database %s%s", defaultPrivilegesObjectType(p.objectType), p.owner, scope, p.database, grantee,
+		))
+	}
+	return formatted
+}
+
+// defaultPrivilegesObjectType returns the object type (e.g.: table) of a pg_default_acl object type (e.g.: r).
+func defaultPrivilegesObjectType(aclType string) string {
+	for objectType, t := range objectTypes {
+		if t == aclType {
+			return objectType
+		}
+	}
+	return aclType
+}
+
+// resetDefaultPrivilegesStatements returns the statements removing the default privileges referencing the role:
+//   - the privileges granted to the role by other owners are revoked,
+//   - the default privileges defined by the role are reset to the built-in defaults
+//     (the owner has all privileges and PUBLIC can execute functions and use types),
+//     which removes them from pg_default_acl.
+func resetDefaultPrivilegesStatements(roleName string, privileges []roleDefaultPrivilege) []string {
+	statements := []string{}
+	seen := map[string]bool{}
+	add := func(statement string) {
+		if !seen[statement] {
+			seen[statement] = true
+			statements = append(statements, statement)
+		}
+	}
+
+	for _, p := range privileges {
+		var inSchema string
+		if p.schema != "" {
+			inSchema = fmt.Sprintf(" IN SCHEMA %s", pq.QuoteIdentifier(p.schema))
+		}
+		prefix := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s%s", pq.QuoteIdentifier(p.owner), inSchema)
+		objects := strings.ToUpper(defaultPrivilegesObjectType(p.objectType)) + "S"
+
+		if p.owner != roleName {
+			if p.grantee == roleName {
+				add(fmt.Sprintf("%s REVOKE ALL ON %s FROM %s", prefix, objects, pq.QuoteIdentifier(roleName)))
+			}
+			continue
+		}
+
+		// Schema specific default privileges are added to the global ones, they are all revoked.
+		if p.schema != "" {
+			if p.grantee != "" {
+				add(fmt.Sprintf("%s REVOKE ALL ON %s FROM %s", prefix, objects, pq.QuoteIdentifier(p.grantee)))
+			}
+			continue
+		}
+
+		if p.grantee != "" && p.grantee != roleName && p.grantee != "public" {
+			add(fmt.Sprintf("%s REVOKE ALL ON %s FROM %s", prefix, objects, pq.QuoteIdentifier(p.grantee)))
+		}
+
+		add(fmt.Sprintf("%s GRANT ALL ON %s TO %s", prefix, objects, pq.QuoteIdentifier(roleName)))
+		if p.objectType == objectTypes["function"] || p.objectType == objectTypes["type"] {
+			add(fmt.Sprintf("%s GRANT ALL ON %s TO PUBLIC", prefix, objects))
+		} else {
+			add(fmt.Sprintf("%s REVOKE ALL ON %s FROM PUBLIC", prefix, objects))
+		}
+	}
+
+	return statements
+}
+
+// resetRoleDefaultPrivileges removes the default privileges referencing the role in all databases,
+// otherwise the role cannot be dropped.
+func resetRoleDefaultPrivileges(db *DBConnection, roleName string) error {
+	privileges, err := getRoleDefaultPrivileges(db, roleName)
+	if err != nil {
+		return err
+	}
+
+	byDatabase := map[string][]roleDefaultPrivilege{}
+	var databases []string
+	for _, p := range privileges {
+		if _, ok := byDatabase[p.database]; !ok {
+			databases = append(databases, p.database)
+		}
+		byDatabase[p.database] = append(byDatabase[p.database], p)
+	}
+
+	for _, database := range databases {
+		owners := []string{}
+		for _, p := range byDatabase[database] {
+			if !sliceContainsStr(owners, p.owner) {
+				owners = append(owners, p.owner)
+			}
+		}
+
+		txn, err := startTransaction(db.client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		// Needed in order to alter the default privileges of the owners if the connection user is not a superuser
+		if err := withRolesGranted(txn, owners, func() error {
+			for _, statement := range resetDefaultPrivilegesStatements(roleName, byDatabase[database]) {
+				if _, err := txn.Exec(statement); err != nil {
+					return fmt.Errorf("could not reset default privileges of role %s in database %s: %w", roleName, database, err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := txn.Commit(); err != nil {
+			return fmt.Errorf("Error resetting default privileges in database %s: %w", database, err)
+		}
+	}
+
+	return nil
+}
+
 func resourcePostgreSQLRoleExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
 	var roleName string
 	err := db.QueryRow("SELECT rolname FROM pg_catalog.pg_roles WHERE rolname=$1", d.Id()).Scan(&roleName)
@@ -370,21 +660,22 @@ func resourcePostgreSQLRoleExists(db *DBConnection, d *schema.ResourceData) (boo
 	case err == sql.ErrNoRows:
 		return false, nil
 	case err != nil:
-		return false, err
+		return false, asCatalogReadError(err)
 	}
 
 	return true, nil
 }
 
 func resourcePostgreSQLRoleRead(db *DBConnection, d *schema.ResourceData) error {
-	return resourcePostgreSQLRoleReadImpl(db, d)
+	return asCatalogReadError(resourcePostgreSQLRoleReadImpl(db, d))
 }
 
 func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) error {
 	var roleSuperuser, roleInherit, roleCreateRole, roleCreateDB, roleCanLogin, roleReplication, roleBypassRLS bool
 	var roleConnLimit int
 	var roleName, roleValidUntil string
-	var roleRoles, roleConfig pq.ByteaArray
+	var roleRoles, roleMembers, roleAdmins, roleConfig pq.ByteaArray
+	var currentUser string
 
 	roleID := d.Id()
 
@@ -402,6 +693,9 @@ func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) er
 
 	values := []interface{}{
 		&roleRoles,
+		&roleMembers,
+		&roleAdmins,
+		&currentUser,
 		&roleName,
 		&roleSuperuser,
 		&roleInherit,
@@ -425,7 +719,13 @@ func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) er
 
 	roleSQL := fmt.Sprintf(`SELECT ARRAY(
 			SELECT pg_get_userbyid(roleid) FROM pg_catalog.pg_auth_members members WHERE member = pg_roles.oid
-		), %s
+		), ARRAY(
+			SELECT pg_get_userbyid(member) FROM pg_catalog.pg_auth_members members
+			WHERE roleid = pg_roles.oid AND NOT admin_option
+		), ARRAY(
+			SELECT pg_get_userbyid(member) FROM pg_catalog.pg_auth_members members
+			WHERE roleid = pg_roles.oid AND admin_option
+		), current_user, %s
 		FROM pg_catalog.pg_roles WHERE rolname=$1`,
 		// select columns
 		strings.Join(columns, ", "),
@@ -455,8 +755,11 @@ func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) er
 	d.Set(roleReplicationAttr, roleReplication)
 	d.Set(roleBypassRLSAttr, roleBypassRLS)
 	d.Set(roleRolesAttr, pgArrayToSet(roleRoles))
+	d.Set(roleMembersAttr, pgArrayToSet(roleMembers))
+	d.Set(roleAdminsAttr, readRoleAdmins(roleAdmins, currentUser, d.Get(roleAdminsAttr).(*schema.Set)))
 	d.Set(roleSearchPathAttr, readSearchPath(roleConfig))
 	d.Set(roleAssumeRoleAttr, readAssumeRole(roleConfig))
+	d.Set(roleDefaultTablespaceAttr, readRoleSetting(roleConfig, "default_tablespace"))
 
 	statementTimeout, err := readStatementTimeout(roleConfig)
 	if err != nil {
@@ -474,11 +777,20 @@ func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) er
 
 	d.SetId(roleName)
 
-	password, err := readRolePassword(db, d, roleCanLogin)
+	password, passwordHash, err := readRolePassword(db, d, roleCanLogin)
 	if err != nil {
 		return err
 	}
 
+	// The algorithm is only read if it is managed and the password is not given already hashed,
+	// so a password hashed with another algorithm is set again.
+	statePassword := d.Get(rolePasswordAttr).(string)
+	if d.Get(rolePasswordEncryptionAttr).(string) != "" && !isHashedPassword(statePassword) {
+		if algorithm := passwordHashAlgorithm(passwordHash); algorithm != "" {
+			d.Set(rolePasswordEncryptionAttr, algorithm)
+		}
+	}
+
 	d.Set(rolePasswordAttr, password)
 	return nil
 }
@@ -547,26 +859,37 @@ func readAssumeRole(roleConfig pq.ByteaArray) string {
 	return res
 }
 
+// readRoleSetting searches for the value of the setting in the rolconfig array.
+// In case no such value is present, it returns empty string.
+func readRoleSetting(roleConfig pq.ByteaArray, name string) string {
+	for _, v := range roleConfig {
+		if value := strings.TrimPrefix(string(v), name+"="); value != string(v) {
+			return value
+		}
+	}
+	return ""
+}
+
 // readRolePassword reads password either from Postgres if admin user is a superuser
-// or only from Terraform state.
-func readRolePassword(db *DBConnection, d *schema.ResourceData, roleCanLogin bool) (string, error) {
+// or only from Terraform state. The password hash is also returned when it has been read from Postgres.
+func readRolePassword(db *DBConnection, d *schema.ResourceData, roleCanLogin bool) (string, string, error) {
 	statePassword := d.Get(rolePasswordAttr).(string)
 
 	// Role which cannot login does not have password in pg_shadow.
 	// Also, if user specifies that admin is not a superuser we don't try to read pg_shadow
 	// (only superuser can read pg_shadow)
 	if !roleCanLogin || !db.client.config.Superuser {
-		return statePassword, nil
+		return statePassword, "", nil
 	}
 
 	// Otherwise we check if connected user is really a superuser
 	// (in order to warn user instead of having a permission denied error)
 	superuser, err := db.isSuperuser()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	if !superuser {
-		return "", fmt.Errorf(
+		return "", "", fmt.Errorf(
 			"could not read role password from Postgres as "+
 				"connected user %s is not a SUPERUSER. "+
 				"You can set `superuser = false` in the provider configuration "+
@@ -580,32 +903,53 @@ func readRolePassword(db *DBConnection, d *schema.ResourceData, roleCanLogin boo
 	switch {
 	case err == sql.ErrNoRows:
 		// They don't have a password
-		return "", nil
+		return "", "", nil
 	case err != nil:
-		return "", fmt.Errorf("Error reading role: %w", err)
+		return "", "", fmt.Errorf("Error reading role: %w", err)
+	}
+	// A password explicitly set to NULL is read as an empty one
+	if strings.ToUpper(statePassword) == "NULL" && rolePassword == "" {
+		return statePassword, rolePassword, nil
 	}
+
 	// If the password isn't already in md5 format, but hashing the input
 	// matches the password in the database for the user, they are the same
-	if statePassword != "" && !strings.HasPrefix(statePassword, "md5") && !strings.HasPrefix(statePassword, "SCRAM-SHA-256") {
+	if statePassword != "" && !isHashedPassword(statePassword) {
 		if strings.HasPrefix(rolePassword, "md5") {
 			hasher := md5.New()
 			if _, err := hasher.Write([]byte(statePassword + d.Id())); err != nil {
-				return "", err
+				return "", "", err
 			}
 			hashedPassword := "md5" + hex.EncodeToString(hasher.Sum(nil))
 
 			if hashedPassword == rolePassword {
 				// The passwords are actually the same
 				// make Terraform think they are the same
-				return statePassword, nil
+				return statePassword, rolePassword, nil
 			}
 		}
 		if strings.HasPrefix(rolePassword, "SCRAM-SHA-256") {
-			return statePassword, nil
+			return statePassword, rolePassword, nil
 			// TODO : implement scram-sha-256 challenge request to the server
 		}
 	}
-	return rolePassword, nil
+	return rolePassword, rolePassword, nil
+}
+
+// isHashedPassword returns true if the password is given already hashed, in which case Postgres stores it as is.
+func isHashedPassword(password string) bool {
+	return strings.HasPrefix(password, "md5") || strings.HasPrefix(password, "SCRAM-SHA-256")
+}
+
+// passwordHashAlgorithm returns the password_encryption value matching a password hash.
+func passwordHashAlgorithm(hash string) string {
+	switch {
+	case strings.HasPrefix(hash, "md5"):
+		return "md5"
+	case strings.HasPrefix(hash, "SCRAM-SHA-256$"):
+		return "scram-sha-256"
+	}
+	return ""
 }
 
 func resourcePostgreSQLRoleUpdate(db *DBConnection, d *schema.ResourceData) error {
@@ -624,7 +968,7 @@ func resourcePostgreSQLRoleUpdate(db *DBConnection, d *schema.ResourceData) erro
 		return err
 	}
 
-	if err := setRolePassword(txn, d); err != nil {
+	if err := setRolePassword(db, txn, d); err != nil {
 		return err
 	}
 
@@ -673,6 +1017,10 @@ func resourcePostgreSQLRoleUpdate(db *DBConnection, d *schema.ResourceData) erro
 		return err
 	}
 
+	if err = setRoleMembers(txn, d); err != nil {
+		return err
+	}
+
 	if err = alterSearchPath(txn, d); err != nil {
 		return err
 	}
@@ -689,6 +1037,10 @@ func resourcePostgreSQLRoleUpdate(db *DBConnection, d *schema.ResourceData) erro
 		return err
 	}
 
+	if err = setRoleDefaultTablespace(txn, d); err != nil {
+		return err
+	}
+
 	if err = txn.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -718,30 +1070,69 @@ func setRoleName(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func setRolePassword(txn *sql.Tx, d *schema.ResourceData) error {
+func setRolePassword(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
 	// If role is renamed, password is reset (as the md5 sum is also base on the role name)
 	// so we need to update it
-	if !d.HasChange(rolePasswordAttr) && !d.HasChange(roleNameAttr) {
+	if !d.HasChange(rolePasswordAttr) && !d.HasChange(roleNameAttr) && !d.HasChange(rolePasswordEncryptionAttr) {
 		return nil
 	}
 
+	if err := setPasswordEncryption(db, txn, d); err != nil {
+		return err
+	}
+
 	roleName := d.Get(roleNameAttr).(string)
 	password := d.Get(rolePasswordAttr).(string)
 
 	sql := fmt.Sprintf("ALTER ROLE %s PASSWORD '%s'", pq.QuoteIdentifier(roleName), pqQuoteLiteral(password))
+	if isRolePasswordNull(d) {
+		// An empty password is still a password, removing it from the configuration removes it from the role.
+		sql = fmt.Sprintf("ALTER ROLE %s PASSWORD NULL", pq.QuoteIdentifier(roleName))
+	}
 	if _, err := txn.Exec(sql); err != nil {
 		return fmt.Errorf("Error updating role password: %w", err)
 	}
 	return nil
 }
 
+// setPasswordEncryption sets password_encryption for the transaction,
+// so the password of the role is hashed with the chosen algorithm.
+func setPasswordEncryption(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
+	passwordEncryption := d.Get(rolePasswordEncryptionAttr).(string)
+	if passwordEncryption == "" {
+		return nil
+	}
+
+	if !db.featureSupported(featurePasswordEncryption) {
+		return fmt.Errorf("password_encryption is not supported %s", db.unsupportedServer())
+	}
+	if !d.Get(roleEncryptedPassAttr).(bool) {
+		return fmt.Errorf("%s cannot be set with %s = false", rolePasswordEncryptionAttr, roleEncryptedPassAttr)
+	}
+
+	if _, err := txn.Exec(fmt.Sprintf("SET LOCAL password_encryption = '%s'", pqQuoteLiteral(passwordEncryption))); err != nil {
+		return fmt.Errorf("could not set password_encryption: %w", err)
+	}
+	return nil
+}
+
+// isRolePasswordNull returns true if the password is not set in the configuration
+// (as opposed to set to an empty string) or explicitly set to NULL.
+func isRolePasswordNull(d *schema.ResourceData) bool {
+	if strings.ToUpper(d.Get(rolePasswordAttr).(string)) == "NULL" {
+		return true
+	}
+	rawPassword := d.GetRawConfig().GetAttr(rolePasswordAttr)
+	return rawPassword.IsKnown() && rawPassword.IsNull()
+}
+
 func setRoleBypassRLS(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
 	if !d.HasChange(roleBypassRLSAttr) {
 		return nil
 	}
 
 	if !db.featureSupported(featureRLS) {
-		return fmt.Errorf("PostgreSQL client is talking with a server (%q) that does not support PostgreSQL Row-Level Security", db.version.String())
+		return fmt.Errorf("row-level security is not supported %s", db.unsupportedServer())
 	}
 
 	bypassRLS := d.Get(roleBypassRLSAttr).(bool)
@@ -914,7 +1305,8 @@ func revokeRoles(txn *sql.Tx, d *schema.ResourceData) error {
 	query := `SELECT pg_get_userbyid(roleid)
 		FROM pg_catalog.pg_auth_members members
 		JOIN pg_catalog.pg_roles ON members.member = pg_roles.oid
-		WHERE rolname = $1`
+		WHERE rolname = $1
+		ORDER BY 1`
 
 	rows, err := txn.Query(query, role)
 	if err != nil {
@@ -950,9 +1342,9 @@ func revokeRoles(txn *sql.Tx, d *schema.ResourceData) error {
 func grantRoles(txn *sql.Tx, d *schema.ResourceData) error {
 	role := d.Get(roleNameAttr).(string)
 
-	for _, grantingRole := range d.Get("roles").(*schema.Set).List() {
+	for _, grantingRole := range sortedSetStrings(d.Get("roles").(*schema.Set)) {
 		query := fmt.Sprintf(
-			"GRANT %s TO %s", pq.QuoteIdentifier(grantingRole.(string)), pq.QuoteIdentifier(role),
+			"GRANT %s TO %s", pq.QuoteIdentifier(grantingRole), pq.QuoteIdentifier(role),
 		)
 		if _, err := txn.Exec(query); err != nil {
 			return fmt.Errorf("could not grant role %s to %s: %w", grantingRole, role, err)
@@ -961,6 +1353,100 @@ func grantRoles(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+// roleMembershipClauses returns the IN ROLE, ROLE and ADMIN clauses of CREATE ROLE.
+func roleMembershipClauses(d *schema.ResourceData) []string {
+	var clauses []string
+	for _, clause := range []struct {
+		hclKey string
+		sqlKey string
+	}{
+		{roleRolesAttr, "IN ROLE"},
+		{roleMembersAttr, "ROLE"},
+		{roleAdminsAttr, "ADMIN"},
+	} {
+		roles := sortedSetStrings(d.Get(clause.hclKey).(*schema.Set))
+		if len(roles) == 0 {
+			continue
+		}
+		for i, role := range roles {
+			roles[i] = pq.QuoteIdentifier(role)
+		}
+		clauses = append(clauses, clause.sqlKey+" "+strings.Join(roles, ", "))
+	}
+	return clauses
+}
+
+func setRoleMembers(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChanges(roleMembersAttr, roleAdminsAttr) {
+		return nil
+	}
+
+	oldMembers, newMembers := d.GetChange(roleMembersAttr)
+	oldAdmins, newAdmins := d.GetChange(roleAdminsAttr)
+	statements := roleMembersStatements(
+		d.Get(roleNameAttr).(string),
+		oldMembers.(*schema.Set), oldAdmins.(*schema.Set),
+		newMembers.(*schema.Set), newAdmins.(*schema.Set),
+	)
+
+	_, err := execStatements(txn, statements, nil)
+	return err
+}
+
+// roleMembersStatements returns the statements changing the members of the role from the old
+// members and admins to the new ones, a member becoming an admin (or the reverse) keeping
+// its membership.
+func roleMembersStatements(role string, oldMembers, oldAdmins, newMembers, newAdmins *schema.Set) []string {
+	quotedRole := pq.QuoteIdentifier(role)
+	statements := []string{}
+
+	for _, member := range sortedSetStrings(oldMembers.Union(oldAdmins).Difference(newMembers.Union(newAdmins))) {
+		statements = append(statements, fmt.Sprintf("REVOKE %s FROM %s", quotedRole, pq.QuoteIdentifier(member)))
+	}
+
+	for _, admin := range sortedSetStrings(newAdmins.Difference(oldAdmins)) {
+		statements = append(statements, fmt.Sprintf("GRANT %s TO %s WITH ADMIN OPTION", quotedRole, pq.QuoteIdentifier(admin)))
+	}
+
+	for _, member := range sortedSetStrings(newMembers) {
+		switch {
+		case oldAdmins.Contains(member):
+			statements = append(statements, fmt.Sprintf("REVOKE ADMIN OPTION FOR %s FROM %s", quotedRole, pq.QuoteIdentifier(member)))
+		case !oldMembers.Contains(member):
+			statements = append(statements, fmt.Sprintf("GRANT %s TO %s", quotedRole, pq.QuoteIdentifier(member)))
+		}
+	}
+
+	return statements
+}
+
+// readRoleAdmins returns the admins of the role read in pg_auth_members. Since PostgreSQL 16 the role
+// creating another one without being superuser is granted it with ADMIN OPTION, so the current user
+// is only kept when it is configured.
+func readRoleAdmins(admins pq.ByteaArray, currentUser string, configured *schema.Set) *schema.Set {
+	set := pgArrayToSet(admins)
+	if !configured.Contains(currentUser) {
+		set.Remove(currentUser)
+	}
+	return set
+}
+
+// resourcePostgreSQLRoleCustomizeDiff checks that a role is not both a member and an admin of the role,
+// the admins being members too.
+func resourcePostgreSQLRoleCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown(roleMembersAttr) || !diff.NewValueKnown(roleAdminsAttr) {
+		return nil
+	}
+
+	admins := diff.Get(roleAdminsAttr).(*schema.Set)
+	for _, member := range sortedSetStrings(diff.Get(roleMembersAttr).(*schema.Set)) {
+		if admins.Contains(member) {
+			return fmt.Errorf("role %s can not be in both %s and %s", member, roleMembersAttr, roleAdminsAttr)
+		}
+	}
+	return nil
+}
+
 func alterSearchPath(txn *sql.Tx, d *schema.ResourceData) error {
 	role := d.Get(roleNameAttr).(string)
 	searchPathInterface := d.Get(roleSearchPathAttr).([]interface{})
@@ -1062,3 +1548,28 @@ func setAssumeRole(txn *sql.Tx, d *schema.ResourceData) error {
 	}
 	return nil
 }
+
+func setRoleDefaultTablespace(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(roleDefaultTablespaceAttr) {
+		return nil
+	}
+
+	roleName := d.Get(roleNameAttr).(string)
+	tablespace := d.Get(roleDefaultTablespaceAttr).(string)
+	if tablespace != "" {
+		sql := fmt.Sprintf(
+			"ALTER ROLE %s SET default_tablespace TO %s", pq.QuoteIdentifier(roleName), pq.QuoteIdentifier(tablespace),
+		)
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not set default_tablespace %s for %s: %w", tablespace, roleName, err)
+		}
+	} else {
+		sql := fmt.Sprintf(
+			"ALTER ROLE %s RESET default_tablespace", pq.QuoteIdentifier(roleName),
+		)
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not reset default_tablespace for %s: %w", roleName, err)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package postgresql
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -10,7 +11,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestAccPostgresqlRole_Basic(t *testing.T) {
@@ -44,6 +47,7 @@ func TestAccPostgresqlRole_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "valid_until", "infinity"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "skip_drop_role", "false"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "skip_reassign_owned", "false"),
+					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "reset_default_privileges", "false"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "statement_timeout", "0"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "idle_in_transaction_session_timeout", "0"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "assume_role", ""),
@@ -192,6 +196,209 @@ resource "postgresql_role" "update_role" {
 	})
 }
 
+func TestAccPostgresqlRole_DefaultTablespace(t *testing.T) {
+	var config = `
+resource "postgresql_tablespace" "role_tablespace" {
+  name     = "role_tablespace"
+  location = "%s"
+}
+
+resource "postgresql_role" "tablespace_role" {
+  name               = "tablespace_role"
+  login              = true
+  password           = "toto"
+  default_tablespace = %s
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			// Only superusers can create tablespaces
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, getTestTablespaceLocation(), "postgresql_tablespace.role_tablespace.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.tablespace_role", "default_tablespace", "role_tablespace"),
+					testAccCheckRoleSetting(t, "tablespace_role", "toto", "default_tablespace", "role_tablespace"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, getTestTablespaceLocation(), `""`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.tablespace_role", "default_tablespace", ""),
+					testAccCheckRoleSetting(t, "tablespace_role", "toto", "default_tablespace", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlRole_RemovePassword(t *testing.T) {
+	var configWithPassword = `
+resource "postgresql_role" "password_role" {
+  name     = "password_role"
+  login    = true
+  password = "toto"
+}
+`
+
+	var configWithoutPassword = `
+resource "postgresql_role" "password_role" {
+  name  = "password_role"
+  login = true
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			// Only superusers can read the password of a role
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: configWithPassword,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlRoleExists("password_role", []string{}, nil),
+					resource.TestCheckResourceAttr("postgresql_role.password_role", "password", "toto"),
+					testAccCheckRolePasswordIsNull("password_role", false),
+				),
+			},
+			{
+				Config: configWithoutPassword,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.password_role", "password", ""),
+					testAccCheckRolePasswordIsNull("password_role", true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlRole_DisableLogins(t *testing.T) {
+	var config = `
+resource "postgresql_role" "disabled_role" {
+  name             = "disabled_role"
+  login            = %t
+  password         = "toto"
+  connection_limit = %d
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				// A connection limit of 0 refuses the new connections of the role, which keeps LOGIN
+				Config: fmt.Sprintf(config, true, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.disabled_role", "connection_limit", "0"),
+					resource.TestCheckResourceAttr("postgresql_role.disabled_role", "login", "true"),
+					testAccCheckRoleLoginRefused(t, "disabled_role", "toto", "53300"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, true, -1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.disabled_role", "connection_limit", "-1"),
+					testAccCheckRoleCanLogin(t, "disabled_role", "toto"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, false, -1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.disabled_role", "login", "false"),
+					testAccCheckRoleLoginRefused(t, "disabled_role", "toto", "28000"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlRole_PasswordEncryption(t *testing.T) {
+	var config = `
+resource "postgresql_role" "encryption_role" {
+  name                = "encryption_role"
+  login               = true
+  password            = "toto"
+  password_encryption = "%s"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePasswordEncryption)
+			// Only superusers can read the password of a role
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "md5"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.encryption_role", "password", "toto"),
+					resource.TestCheckResourceAttr("postgresql_role.encryption_role", "password_encryption", "md5"),
+					testAccCheckRolePasswordHash("encryption_role", "md5"),
+				),
+			},
+			{
+				// Only the algorithm changes, the password is hashed again
+				Config: fmt.Sprintf(config, "scram-sha-256"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.encryption_role", "password", "toto"),
+					resource.TestCheckResourceAttr("postgresql_role.encryption_role", "password_encryption", "scram-sha-256"),
+					testAccCheckRolePasswordHash("encryption_role", "SCRAM-SHA-256$"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRolePasswordHash(roleName, prefix string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var hash string
+		if err := db.QueryRow("SELECT COALESCE(rolpassword, '') FROM pg_authid WHERE rolname = $1", roleName).Scan(&hash); err != nil {
+			return fmt.Errorf("Error reading password of role %s: %w", roleName, err)
+		}
+		if !strings.HasPrefix(hash, prefix) {
+			return fmt.Errorf("expected password of role %s to be hashed with %s, got %s", roleName, prefix, hash)
+		}
+		return nil
+	}
+}
+
+func testAccCheckRolePasswordIsNull(roleName string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var isNull bool
+		if err := db.QueryRow("SELECT rolpassword IS NULL FROM pg_authid WHERE rolname = $1", roleName).Scan(&isNull); err != nil {
+			return fmt.Errorf("Error reading password of role %s: %w", roleName, err)
+		}
+		if isNull != expected {
+			return fmt.Errorf("expected password of role %s to be null: %t, got %t", roleName, expected, isNull)
+		}
+		return nil
+	}
+}
+
 // Test to create a role with admin user (usually postgres) granted to it
 // There were a bug on RDS like setup (with a non-superuser postgres role)
 // where it couldn't delete the role in this case.
@@ -228,6 +435,174 @@ resource "postgresql_role" "test_role" {
 	})
 }
 
+func TestAccPostgresqlRole_MultipleRolesEmptyPlan(t *testing.T) {
+	config := `
+resource "postgresql_role" "group_c" {
+  name = "group_c"
+}
+
+resource "postgresql_role" "group_a" {
+  name = "group_a"
+}
+
+resource "postgresql_role" "group_b" {
+  name = "group_b"
+}
+
+resource "postgresql_role" "member" {
+  name  = "member"
+  roles = [
+    postgresql_role.group_c.name,
+    postgresql_role.group_a.name,
+    postgresql_role.group_b.name,
+  ]
+}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlRoleExists("member", []string{"group_a", "group_b", "group_c"}, nil),
+					resource.TestCheckResourceAttr("postgresql_role.member", "roles.#", "3"),
+				),
+			},
+			// Applying the same configuration again must not change anything
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestResetDefaultPrivilegesStatements(t *testing.T) {
+	privileges := []roleDefaultPrivilege{
+		{database: "app", owner: "owner", objectType: "r", grantee: "owner"},
+		{database: "app", owner: "owner", objectType: "r", grantee: "myrole"},
+		{database: "app", owner: "myrole", objectType: "f", grantee: "myrole"},
+		{database: "app", owner: "myrole", objectType: "r", grantee: "reader"},
+		{database: "app", owner: "myrole", objectType: "r", grantee: "myrole"},
+		{database: "app", owner: "myrole", schema: "app", objectType: "S", grantee: "public"},
+	}
+	expected := []string{
+		`ALTER DEFAULT PRIVILEGES FOR ROLE "owner" REVOKE ALL ON TABLES FROM "myrole"`,
+		`ALTER DEFAULT PRIVILEGES FOR ROLE "myrole" GRANT ALL ON FUNCTIONS TO "myrole"`,
+		`ALTER DEFAULT PRIVILEGES FOR ROLE "myrole" GRANT ALL ON FUNCTIONS TO PUBLIC`,
+		`ALTER DEFAULT PRIVILEGES FOR ROLE "myrole" REVOKE ALL ON TABLES FROM "reader"`,
+		`ALTER DEFAULT PRIVILEGES FOR ROLE "myrole" GRANT ALL ON TABLES TO "myrole"`,
+		`ALTER DEFAULT PRIVILEGES FOR ROLE "myrole" REVOKE ALL ON TABLES FROM PUBLIC`,
+		`ALTER DEFAULT PRIVILEGES FOR ROLE "myrole" IN SCHEMA "app" REVOKE ALL ON SEQUENCES FROM "public"`,
+	}
+
+	if out := resetDefaultPrivilegesStatements("myrole", privileges); !reflect.DeepEqual(out, expected) {
+		t.Fatalf("Error matching output and expected: %#v vs %#v", out, expected)
+	}
+}
+
+func TestReadRoleSetting(t *testing.T) {
+	roleConfig := pq.ByteaArray{
+		[]byte("default_tablespace=My Tablespace"),
+		[]byte("default_tablespace_foo=bar"),
+		[]byte(`search_path="$user", public`),
+	}
+
+	if out := readRoleSetting(roleConfig, "default_tablespace"); out != "My Tablespace" {
+		t.Fatalf("expected default_tablespace to be %q, got %q", "My Tablespace", out)
+	}
+	if out := readRoleSetting(roleConfig, "default"); out != "" {
+		t.Fatalf("expected no value for a prefix of a setting, got %q", out)
+	}
+	if out := readRoleSetting(nil, "default_tablespace"); out != "" {
+		t.Fatalf("expected no value without rolconfig, got %q", out)
+	}
+}
+
+func TestAccPostgresqlRole_ResetDefaultPrivileges(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, ownerName := getTestDBNames(dbSuffix)
+	roleName := fmt.Sprintf("tf_tests_reset_role_%s", dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_role" "test" {
+  name                     = "%s"
+  reset_default_privileges = true
+}
+`, roleName)
+
+	testConfig := getTestConfig(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+			testSuperuserPreCheck(t)
+		},
+		Providers: testAccProviders,
+		// The default privileges referencing the role in the test database have to be removed to drop it.
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckPostgresqlRoleDestroy,
+			testAccCheckDefaultACLCount(dbName, ownerName, 0),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckPostgresqlRoleExists(roleName, nil, nil),
+			},
+			{
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), fmt.Sprintf(
+						"ALTER DEFAULT PRIVILEGES FOR ROLE %s GRANT SELECT ON TABLES TO %s", ownerName, roleName,
+					))
+					dbExecute(t, testConfig.connStr(dbName), fmt.Sprintf(
+						"ALTER DEFAULT PRIVILEGES FOR ROLE %s REVOKE EXECUTE ON FUNCTIONS FROM PUBLIC", roleName,
+					))
+					dbExecute(t, testConfig.connStr(dbName), fmt.Sprintf(
+						"ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA test_schema GRANT SELECT ON TABLES TO %s", roleName, ownerName,
+					))
+				},
+				Config: config,
+				Check:  testAccCheckDefaultACLCount(dbName, roleName, 3),
+			},
+		},
+	})
+}
+
+// testAccCheckDefaultACLCount checks the number of default privileges defined in the database
+// by or for the role.
+func testAccCheckDefaultACLCount(dbName, roleName string, expected int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var count int
+		if err := txn.QueryRow(
+			`SELECT count(DISTINCT da.oid) FROM pg_default_acl da, aclexplode(da.defaclacl) a `+
+				`WHERE pg_get_userbyid(da.defaclrole) = $1 OR pg_get_userbyid(a.grantee) = $1`,
+			roleName,
+		).Scan(&count); err != nil {
+			return fmt.Errorf("could not count default privileges: %w", err)
+		}
+		if count != expected {
+			return fmt.Errorf("expected %d default privileges for role %s, got %d", expected, roleName, count)
+		}
+		return nil
+	}
+}
+
 func testAccCheckPostgresqlRoleDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client)
 
@@ -311,6 +686,54 @@ func testAccCheckRoleCanLogin(t *testing.T, role, password string) resource.Test
 	}
 }
 
+// testAccCheckRoleLoginRefused checks that the connections of the role are refused with the
+// expected error code, e.g. 53300 (too_many_connections) or 28000 (invalid_authorization_specification).
+func testAccCheckRoleLoginRefused(t *testing.T, role, password, expectedCode string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		config := getTestConfig(t)
+		config.Username = role
+		config.Password = password
+		db, err := sql.Open("postgres", config.connStr("postgres"))
+		if err != nil {
+			return fmt.Errorf("could not open SQL connection: %v", err)
+		}
+		defer db.Close()
+
+		err = db.Ping()
+		if err == nil {
+			return fmt.Errorf("role %s could connect while its logins should be refused", role)
+		}
+		var pqErr *pq.Error
+		if !errors.As(err, &pqErr) || string(pqErr.Code) != expectedCode {
+			return fmt.Errorf("expected connection of role %s to be refused with code %s, got: %v", role, expectedCode, err)
+		}
+		return nil
+	}
+}
+
+// testAccCheckRoleSetting checks the value of a setting in a new session of the role.
+func testAccCheckRoleSetting(t *testing.T, role, password, setting, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		config := getTestConfig(t)
+		config.Username = role
+		config.Password = password
+		db, err := sql.Open("postgres", config.connStr("postgres"))
+		if err != nil {
+			return fmt.Errorf("could not open SQL connection: %v", err)
+		}
+		defer db.Close()
+
+		var value string
+		if err := db.QueryRow("SELECT pg_catalog.current_setting($1)", setting).Scan(&value); err != nil {
+			return fmt.Errorf("could not read setting %s as role %s: %v", setting, role, err)
+		}
+		if value != expected {
+			return fmt.Errorf("expected %s of role %s to be %q, got %q", setting, role, expected, value)
+		}
+		return nil
+	}
+}
+
 func checkGrantedRoles(client *Client, roleName string, expectedRoles []string) error {
 	db, err := client.Connect()
 	if err != nil {
@@ -438,3 +861,137 @@ resource "postgresql_role" "role_with_search_path" {
   search_path = ["bar", "foo-with-hyphen"]
 }
 `
+
+func TestRoleMembersStatements(t *testing.T) {
+	set := func(roles ...interface{}) *schema.Set {
+		return schema.NewSet(schema.HashString, roles)
+	}
+
+	out := roleMembersStatements("group",
+		set("kept", "removed", "promoted"), set("demoted", "dropped_admin"),
+		set("kept", "demoted", "added"), set("promoted", "new_admin"),
+	)
+	expected := []string{
+		`REVOKE "group" FROM "dropped_admin"`,
+		`REVOKE "group" FROM "removed"`,
+		`GRANT "group" TO "new_admin" WITH ADMIN OPTION`,
+		`GRANT "group" TO "promoted" WITH ADMIN OPTION`,
+		`GRANT "group" TO "added"`,
+		`REVOKE ADMIN OPTION FOR "group" FROM "demoted"`,
+	}
+	if !reflect.DeepEqual(out, expected) {
+		t.Fatalf("Error matching output and expected: %#v vs %#v", out, expected)
+	}
+
+	if out := roleMembersStatements("group", set("a"), set("b"), set("a"), set("b")); len(out) != 0 {
+		t.Fatalf("expected no statements without changes, got %#v", out)
+	}
+}
+
+func TestReadRoleAdmins(t *testing.T) {
+	admins := pq.ByteaArray{[]byte("creator"), []byte("admin")}
+
+	if out := readRoleAdmins(admins, "creator", schema.NewSet(schema.HashString, nil)); !reflect.DeepEqual(sortedSetStrings(out), []string{"admin"}) {
+		t.Fatalf("expected the implicit admin to be ignored, got %v", out.List())
+	}
+	configured := schema.NewSet(schema.HashString, []interface{}{"creator"})
+	if out := readRoleAdmins(admins, "creator", configured); !reflect.DeepEqual(sortedSetStrings(out), []string{"admin", "creator"}) {
+		t.Fatalf("expected the configured admins to be kept, got %v", out.List())
+	}
+}
+
+func TestAccPostgresqlRole_Memberships(t *testing.T) {
+	var config = `
+resource "postgresql_role" "group_one" {
+  name = "group_one"
+}
+
+resource "postgresql_role" "group_two" {
+  name = "group_two"
+}
+
+resource "postgresql_role" "member_one" {
+  name = "member_one"
+}
+
+resource "postgresql_role" "member_two" {
+  name = "member_two"
+}
+
+resource "postgresql_role" "grouped_role" {
+  name    = "grouped_role"
+  roles   = [postgresql_role.group_one.name, postgresql_role.group_two.name]
+  members = %s
+  admins  = %s
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, `[postgresql_role.member_one.name]`, `[postgresql_role.member_two.name]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlRoleExists("grouped_role", []string{"group_one", "group_two"}, nil),
+					testAccCheckRoleMembers(t, "grouped_role", map[string]bool{"member_one": false, "member_two": true}),
+					resource.TestCheckResourceAttr("postgresql_role.grouped_role", "roles.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_role.grouped_role", "members.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_role.grouped_role", "admins.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, `[postgresql_role.member_two.name]`, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlRoleExists("grouped_role", []string{"group_one", "group_two"}, nil),
+					testAccCheckRoleMembers(t, "grouped_role", map[string]bool{"member_two": false}),
+					resource.TestCheckResourceAttr("postgresql_role.grouped_role", "members.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_role.grouped_role", "admins.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckRoleMembers checks the members of the role, the value telling if they have the ADMIN OPTION.
+func testAccCheckRoleMembers(t *testing.T, role string, expected map[string]bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		rows, err := db.Query(
+			`SELECT pg_get_userbyid(member), admin_option FROM pg_catalog.pg_auth_members
+			WHERE roleid = (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $1) AND member <> (
+				SELECT oid FROM pg_catalog.pg_roles WHERE rolname = current_user
+			)`,
+			role,
+		)
+		if err != nil {
+			return fmt.Errorf("could not read members of role %s: %w", role, err)
+		}
+		defer rows.Close()
+
+		members := map[string]bool{}
+		for rows.Next() {
+			var member string
+			var admin bool
+			if err := rows.Scan(&member, &admin); err != nil {
+				return err
+			}
+			members[member] = admin
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(members, expected) {
+			return fmt.Errorf("expected members of role %s to be %v, got %v", role, expected, members)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,486 @@
+package postgresql
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	ruleNameAttr       = "name"
+	ruleSchemaAttr     = "schema"
+	ruleDatabaseAttr   = "database"
+	ruleTableAttr      = "table"
+	ruleEventAttr      = "event"
+	ruleInsteadAttr    = "instead"
+	ruleConditionAttr  = "condition"
+	ruleCommandsAttr   = "commands"
+	ruleDefinitionAttr = "definition"
+)
+
+// ruleEvents maps the events of a rule to pg_rewrite.ev_type
+var ruleEvents = map[string]string{
+	"SELECT": "1",
+	"UPDATE": "2",
+	"INSERT": "3",
+	"DELETE": "4",
+}
+
+func resourcePostgreSQLRule() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLRuleCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLRuleRead),
+		Update: PGResourceFunc(resourcePostgreSQLRuleUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLRuleDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			ruleNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the rule",
+			},
+			ruleSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema of the table or view",
+			},
+			ruleDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the table or view",
+			},
+			ruleTableAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The table or view the rule applies to",
+			},
+			ruleEventAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"SELECT", "INSERT", "UPDATE", "DELETE"}, false),
+				Description:  "The event the rule applies to, one of SELECT, INSERT, UPDATE or DELETE",
+			},
+			ruleInsteadAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the commands are executed instead of the original command (DO INSTEAD) or in addition to it (DO ALSO)",
+			},
+			ruleConditionAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+				Description:      "The condition of the rule (WHERE clause), which can reference the NEW and OLD relations",
+			},
+			ruleCommandsAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateFunc:     validation.StringIsNotEmpty,
+					DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+				},
+				Description: "The commands of the rule, the rule does nothing (DO NOTHING) if empty",
+			},
+			ruleDefinitionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The definition of the rule as returned by pg_get_ruledef",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLRuleCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(createRuleQuery(d, false, ruleTableName(d))); err != nil {
+		return fmt.Errorf("could not create rule %s: %w", d.Get(ruleNameAttr).(string), err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating rule: %w", err)
+	}
+
+	d.SetId(generateRuleID(database, d.Get(ruleSchemaAttr).(string), d.Get(ruleTableAttr).(string), d.Get(ruleNameAttr).(string)))
+
+	return resourcePostgreSQLRuleReadImpl(db, d)
+}
+
+// ruleTableName returns the quoted name of the table of the rule
+func ruleTableName(d *schema.ResourceData) string {
+	return fmt.Sprintf("%s.%s", pq.QuoteIdentifier(d.Get(ruleSchemaAttr).(string)), pq.QuoteIdentifier(d.Get(ruleTableAttr).(string)))
+}
+
+// createRuleQuery returns the CREATE [OR REPLACE] RULE statement of the rule on table
+func createRuleQuery(d *schema.ResourceData, replace bool, table string) string {
+	b := bytes.NewBufferString("CREATE ")
+	if replace {
+		b.WriteString("OR REPLACE ")
+	}
+	fmt.Fprintf(b, "RULE %s AS ON %s TO %s",
+		pq.QuoteIdentifier(d.Get(ruleNameAttr).(string)),
+		d.Get(ruleEventAttr).(string),
+		table,
+	)
+	if condition := d.Get(ruleConditionAttr).(string); condition != "" {
+		b.WriteString(" WHERE " + condition)
+	}
+
+	if d.Get(ruleInsteadAttr).(bool) {
+		b.WriteString(" DO INSTEAD ")
+	} else {
+		b.WriteString(" DO ALSO ")
+	}
+
+	commands := []string{}
+	for _, command := range d.Get(ruleCommandsAttr).([]interface{}) {
+		commands = append(commands, strings.TrimRight(strings.TrimSpace(command.(string)), ";"))
+	}
+	switch len(commands) {
+	case 0:
+		b.WriteString("NOTHING")
+	case 1:
+		b.WriteString(commands[0])
+	default:
+		b.WriteString("(" + strings.Join(commands, "; ") + ")")
+	}
+
+	return b.String()
+}
+
+func resourcePostgreSQLRuleRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLRuleReadImpl(db, d)
+}
+
+func resourcePostgreSQLRuleReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, tableName, ruleName, err := getDBRuleName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var eventType, definition string
+	var instead bool
+	err = txn.QueryRow(
+		`SELECT r.ev_type::text, r.is_instead, pg_catalog.pg_get_ruledef(r.oid, true) `+
+			`FROM pg_catalog.pg_rewrite r `+
+			`JOIN pg_catalog.pg_class c ON c.oid = r.ev_class `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+			`WHERE n.nspname = $1 AND c.relname = $2 AND r.rulename = $3`,
+		schemaName, tableName, ruleName,
+	).Scan(&eventType, &instead, &definition)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL rule %s on %s.%s not found in database %s", ruleName, schemaName, tableName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading rule: %w", err)
+	}
+
+	for event, t := range ruleEvents {
+		if t == eventType {
+			d.Set(ruleEventAttr, event)
+		}
+	}
+
+	// PostgreSQL rewrites the condition and the commands of the rule, so we only set them from the catalog
+	// when importing or when the rule has been changed outside of Terraform since the last apply,
+	// unless Postgres stores the configured rule as the catalog one.
+	importing := d.Get(ruleTableAttr).(string) == ""
+	stored := d.Get(ruleDefinitionAttr).(string)
+	if importing || (stored != "" && stored != definition) {
+		condition, commands, err := parseRuleDefinition(definition)
+		if err != nil {
+			return err
+		}
+		equivalent := false
+		if !importing {
+			if equivalent, err = equivalentRule(txn, d, condition, commands); err != nil {
+				return fmt.Errorf("Error reading rule: %w", err)
+			}
+		}
+		if !equivalent {
+			d.Set(ruleConditionAttr, condition)
+			d.Set(ruleCommandsAttr, commands)
+		}
+	}
+
+	d.Set(ruleNameAttr, ruleName)
+	d.Set(ruleSchemaAttr, schemaName)
+	d.Set(ruleDatabaseAttr, database)
+	d.Set(ruleTableAttr, tableName)
+	d.Set(ruleInsteadAttr, instead)
+	d.Set(ruleDefinitionAttr, definition)
+	d.SetId(generateRuleID(database, schemaName, tableName, ruleName))
+
+	return nil
+}
+
+// ruleDefinitionWord is a word of a rule definition outside of quotes and parentheses.
+type ruleDefinitionWord struct {
+	word       string
+	start, end int
+}
+
+// ruleDefinitionWords returns the words of a rule definition which are not quoted nor in parentheses,
+// with their position.
+func ruleDefinitionWords(definition string) []ruleDefinitionWord {
+	words := []ruleDefinitionWord{}
+	depth, start := 0, -1
+	var quote rune
+
+	for i, r := range definition {
+		isWordChar := quote == 0 && depth == 0 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+		if start >= 0 && !isWordChar {
+			words = append(words, ruleDefinitionWord{word: strings.ToUpper(definition[start:i]), start: start, end: i})
+			start = -1
+		}
+
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case isWordChar && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, ruleDefinitionWord{word: strings.ToUpper(definition[start:]), start: start, end: len(definition)})
+	}
+
+	return words
+}
+
+// splitRuleCommands splits the commands of a rule on the semicolons which are not quoted nor in parentheses.
+// equivalentRule returns whether Postgres stores the condition and the commands of the rule configured in d
+// as condition and commands, by creating the rule on a temporary copy of its table.
+// The rules which cannot be created are considered different.
+func equivalentRule(txn *sql.Tx, d *schema.ResourceData, condition string, commands []string) (bool, error) {
+	statements := []string{
+		fmt.Sprintf("CREATE TEMPORARY TABLE terraform_scratch (LIKE %s)", ruleTableName(d)),
+		createRuleQuery(d, false, "pg_temp.terraform_scratch"),
+	}
+	query := fmt.Sprintf(
+		"SELECT pg_catalog.pg_get_ruledef(r.oid, true) FROM pg_catalog.pg_rewrite r "+
+			"WHERE r.ev_class = 'pg_temp.terraform_scratch'::regclass AND r.rulename = '%s'",
+		pqQuoteLiteral(d.Get(ruleNameAttr).(string)),
+	)
+	definition, err := scratchQuery(txn, statements, query)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		log.Printf("[WARN] could not create rule %s on a copy of its table: %v", d.Get(ruleNameAttr).(string), err)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	configuredCondition, configuredCommands, err := parseRuleDefinition(definition)
+	if err != nil {
+		return false, err
+	}
+	return configuredCondition == condition && reflect.DeepEqual(configuredCommands, commands), nil
+}
+
+func splitRuleCommands(commands string) []string {
+	result := []string{}
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range commands {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ';' && depth == 0:
+			result = append(result, commands[start:i])
+			start = i + 1
+		}
+	}
+	result = append(result, commands[start:])
+
+	commandList := []string{}
+	for _, command := range result {
+		if command = strings.TrimSpace(command); command != "" {
+			commandList = append(commandList, command)
+		}
+	}
+	return commandList
+}
+
+// parseRuleDefinition returns the condition and the commands of a rule from its definition
+// as returned by pg_get_ruledef, e.g.:
+//
+//	CREATE RULE r AS
+//	    ON INSERT TO v
+//	   WHERE new.id > 0 DO INSTEAD  INSERT INTO t (id)
+//	  VALUES (new.id);
+func parseRuleDefinition(definition string) (string, []string, error) {
+	words := ruleDefinitionWords(definition)
+
+	// The condition and the commands follow the table name (ON <event> TO <table>)
+	toIndex := -1
+	for i := 0; i+1 < len(words); i++ {
+		if _, ok := ruleEvents[words[i+1].word]; ok && words[i].word == "ON" && i+2 < len(words) && words[i+2].word == "TO" {
+			toIndex = i + 2
+			break
+		}
+	}
+	if toIndex < 0 {
+		return "", nil, fmt.Errorf("could not parse rule definition: %s", definition)
+	}
+
+	whereIndex, doIndex := -1, -1
+	for i := toIndex + 1; i < len(words); i++ {
+		if words[i].word == "WHERE" && whereIndex < 0 {
+			whereIndex = i
+		}
+		if words[i].word == "DO" {
+			doIndex = i
+			break
+		}
+	}
+	if doIndex < 0 {
+		return "", nil, fmt.Errorf("could not parse rule definition: %s", definition)
+	}
+
+	var condition string
+	if whereIndex >= 0 {
+		condition = strings.TrimSpace(definition[words[whereIndex].end:words[doIndex].start])
+	}
+
+	commandsStart := words[doIndex].end
+	if doIndex+1 < len(words) && (words[doIndex+1].word == "INSTEAD" || words[doIndex+1].word == "ALSO") {
+		commandsStart = words[doIndex+1].end
+	}
+	commands := strings.TrimSpace(definition[commandsStart:])
+	commands = strings.TrimSpace(strings.TrimSuffix(commands, ";"))
+
+	switch {
+	case strings.EqualFold(commands, "NOTHING"):
+		return condition, []string{}, nil
+	case strings.HasPrefix(commands, "(") && strings.HasSuffix(commands, ")"):
+		commands = commands[1 : len(commands)-1]
+	}
+
+	return condition, splitRuleCommands(commands), nil
+}
+
+func resourcePostgreSQLRuleUpdate(db *DBConnection, d *schema.ResourceData) error {
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(createRuleQuery(d, true, ruleTableName(d))); err != nil {
+		return fmt.Errorf("could not replace rule %s: %w", d.Get(ruleNameAttr).(string), err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating rule: %w", err)
+	}
+
+	// The definition changes with the rule, it must not be considered as changed outside of Terraform.
+	d.Set(ruleDefinitionAttr, "")
+
+	return resourcePostgreSQLRuleReadImpl(db, d)
+}
+
+func resourcePostgreSQLRuleDelete(db *DBConnection, d *schema.ResourceData) error {
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := fmt.Sprintf("DROP RULE %s ON %s.%s",
+		pq.QuoteIdentifier(d.Get(ruleNameAttr).(string)),
+		pq.QuoteIdentifier(d.Get(ruleSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(ruleTableAttr).(string)),
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not drop rule: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting rule: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func generateRuleID(database, schemaName, tableName, ruleName string) string {
+	return joinResourceID(database, schemaName, tableName, ruleName)
+}
+
+// getDBRuleName returns database, schema, table and name of the rule. If we are importing this resource,
+// they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBRuleName(d *schema.ResourceData, client *Client) (string, string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(ruleSchemaAttr).(string)
+	tableName := d.Get(ruleTableAttr).(string)
+	ruleName := d.Get(ruleNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema, table and rule names.
+	if ruleName == "" {
+		parsed := splitResourceIDN(d.Id(), 4)
+		if len(parsed) != 4 || parsed[3] == "" {
+			return "", "", "", "", fmt.Errorf("rule ID %s has not the expected format 'database.schema.table.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		tableName = parsed[2]
+		ruleName = parsed[3]
+	}
+	return database, schemaName, tableName, ruleName, nil
+}
@@ -0,0 +1,153 @@
+package postgresql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseRuleDefinition(t *testing.T) {
+	var cases = []struct {
+		definition string
+		condition  string
+		commands   []string
+	}{
+		{
+			definition: "CREATE RULE test_rule AS\n    ON INSERT TO test_view\n   WHERE new.id > 0 DO INSTEAD  INSERT INTO test_table (id)\n  VALUES (new.id);",
+			condition:  "new.id > 0",
+			commands:   []string{"INSERT INTO test_table (id)\n  VALUES (new.id)"},
+		},
+		{
+			definition: "CREATE RULE \"On Delete\" AS\n    ON DELETE TO app.\"My View\" DO INSTEAD NOTHING;",
+			commands:   []string{},
+		},
+		{
+			definition: "CREATE RULE test_rule AS\n    ON UPDATE TO test_view\n   WHERE old.val <> 'do; (not)' DO ( UPDATE test_table SET val = new.val\n  WHERE test_table.id = old.id;\n NOTIFY test_table;\n);",
+			condition:  "old.val <> 'do; (not)'",
+			commands:   []string{"UPDATE test_table SET val = new.val\n  WHERE test_table.id = old.id", "NOTIFY test_table"},
+		},
+	}
+
+	for _, c := range cases {
+		condition, commands, err := parseRuleDefinition(c.definition)
+		if err != nil {
+			t.Fatalf("Error parsing rule definition %s: %v", c.definition, err)
+		}
+		if condition != c.condition {
+			t.Fatalf("Error matching condition and expected: %#v vs %#v", condition, c.condition)
+		}
+		if !reflect.DeepEqual(commands, c.commands) {
+			t.Fatalf("Error matching commands and expected: %#v vs %#v", commands, c.commands)
+		}
+	}
+
+	if _, _, err := parseRuleDefinition("CREATE RULE test_rule"); err == nil {
+		t.Fatalf("Expected an error parsing an incomplete rule definition")
+	}
+}
+
+func TestAccPostgresqlRule_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_rule_table (id integer, val text)")
+	dbExecute(t, testConfig.connStr(dbName), "CREATE VIEW test_rule_view AS SELECT id, val FROM test_rule_table")
+
+	config := `
+resource "postgresql_rule" "test" {
+  database  = "%s"
+  table     = "test_rule_view"
+  name      = "test_insert"
+  event     = "INSERT"
+  instead   = true
+  condition = "NEW.id > 0"
+  commands  = [%s]
+}
+`
+	insert := `"INSERT INTO test_rule_table VALUES (NEW.id, NEW.val)"`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, insert),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_rule.test", "id", fmt.Sprintf("%s.public.test_rule_view.test_insert", dbName)),
+					resource.TestCheckResourceAttr("postgresql_rule.test", "commands.#", "1"),
+					testAccCheckRuleDefinition(dbName, "test_insert", "new.id > 0", "DO INSTEAD"),
+				),
+			},
+			{
+				// The rule is replaced with an additional command
+				Config: fmt.Sprintf(config, dbName, insert+`, "NOTIFY test_rule_table"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_rule.test", "commands.#", "2"),
+					testAccCheckRuleDefinition(dbName, "test_insert", "NOTIFY test_rule_table"),
+				),
+			},
+			{
+				// A rule changed outside of Terraform is replaced again
+				PreConfig: func() {
+					dbExecute(t, testConfig.connStr(dbName), "CREATE OR REPLACE RULE test_insert AS ON INSERT TO test_rule_view DO INSTEAD NOTHING")
+				},
+				Config: fmt.Sprintf(config, dbName, insert+`, "NOTIFY test_rule_table"`),
+				Check:  testAccCheckRuleDefinition(dbName, "test_insert", "new.id > 0", "NOTIFY test_rule_table"),
+			},
+			{
+				ResourceName:      "postgresql_rule.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.public.test_rule_view.test_insert", dbName),
+				ImportStateVerify: true,
+				// The commands are imported as rewritten by Postgres
+				ImportStateVerifyIgnore: []string{"commands"},
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlRuleDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_rule" {
+			continue
+		}
+
+		if err := testAccCheckRuleDefinition(rs.Primary.Attributes["database"], rs.Primary.Attributes["name"])(s); err == nil {
+			return fmt.Errorf("Rule still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+// testAccCheckRuleDefinition checks that the rule exists and its definition contains the expected parts.
+func testAccCheckRuleDefinition(dbName, ruleName string, expected ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var definition string
+		if err := txn.QueryRow("SELECT pg_get_ruledef(oid, true) FROM pg_rewrite WHERE rulename = $1", ruleName).Scan(&definition); err != nil {
+			return fmt.Errorf("could not read rule %s: %w", ruleName, err)
+		}
+		for _, part := range expected {
+			if !strings.Contains(definition, part) {
+				return fmt.Errorf("expected definition of rule %s to contain %q, got %q", ruleName, part, definition)
+			}
+		}
+		return nil
+	}
+}
@@ -30,21 +30,24 @@ const (
 )
 
 func resourcePostgreSQLSchema() *schema.Resource {
-	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLSchemaCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLSchemaRead),
-		Update: PGResourceFunc(resourcePostgreSQLSchemaUpdate),
-		Delete: PGResourceFunc(resourcePostgreSQLSchemaDelete),
-		Exists: PGResourceExistsFunc(resourcePostgreSQLSchemaExists),
+	resource := &schema.Resource{
+		// Version 1 escapes the dots of the names in the ID
+		SchemaVersion: 1,
+		Create:        PGResourceFunc(resourcePostgreSQLSchemaCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLSchemaRead),
+		Update:        PGResourceFunc(resourcePostgreSQLSchemaUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLSchemaDelete),
+		Exists:        PGResourceExistsFunc(resourcePostgreSQLSchemaExists),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
 			schemaNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the schema",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the schema",
 			},
 			schemaDatabaseAttr: {
 				Type:        schema.TypeString,
@@ -63,8 +66,10 @@ func resourcePostgreSQLSchema() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     true,
-				Description: "When true, use the existing schema if it exists",
+				Description: "When true, use the existing schema if it exists, unless adopt_existing is set",
+				Deprecated:  "Use adopt_existing instead",
 			},
+			adoptExistingAttr: adoptExistingSchema(),
 			schemaDropCascade: {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -114,6 +119,13 @@ func resourcePostgreSQLSchema() *schema.Resource {
 			},
 		},
 	}
+	resource.StateUpgraders = []schema.StateUpgrader{
+		resourceIDStateUpgrader(resource, 0, func(rawState map[string]interface{}) string {
+			return databaseObjectIDFromRawState(rawState, schemaDatabaseAttr, schemaNameAttr)
+		}),
+	}
+
+	return resource
 }
 
 func resourcePostgreSQLSchemaCreate(db *DBConnection, d *schema.ResourceData) error {
@@ -160,6 +172,10 @@ func resourcePostgreSQLSchemaCreate(db *DBConnection, d *schema.ResourceData) er
 func createSchema(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
 	schemaName := d.Get(schemaNameAttr).(string)
 
+	// The existing schema is used by default, as it was before adopt_existing replaced if_not_exists
+	adopt := adoptExisting(d, d.Get(schemaIfNotExists).(bool))
+	schemaID := generateSchemaID(d, db.client.databaseName)
+
 	// Check if previous tasks haven't already create schema
 	var foundSchema bool
 	err := txn.QueryRow(`SELECT TRUE FROM pg_catalog.pg_namespace WHERE nspname = $1`, schemaName).Scan(&foundSchema)
@@ -168,23 +184,31 @@ func createSchema(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
 	switch {
 	case err == sql.ErrNoRows:
 		b := bytes.NewBufferString("CREATE SCHEMA ")
-		if db.featureSupported(featureSchemaCreateIfNotExist) {
-			if v := d.Get(schemaIfNotExists); v.(bool) {
-				fmt.Fprint(b, "IF NOT EXISTS ")
-			}
-		}
 		fmt.Fprint(b, pq.QuoteIdentifier(schemaName))
 
 		switch v, ok := d.GetOk(schemaOwnerAttr); {
 		case ok:
 			fmt.Fprint(b, " AUTHORIZATION ", pq.QuoteIdentifier(v.(string)))
 		}
-		queries = append(queries, b.String())
+
+		// The schema may have been created concurrently since it has been looked for
+		adopted, err := createOrAdopt(txn, adopt, "postgresql_schema", schemaID, b.String())
+		if err != nil {
+			return fmt.Errorf("Error creating schema %s: %w", schemaName, err)
+		}
+		if adopted {
+			if err := setSchemaOwner(txn, d); err != nil {
+				return err
+			}
+		}
 
 	case err != nil:
 		return fmt.Errorf("Error looking for schema: %w", err)
 
 	default:
+		if err := existingObject(adopt, "postgresql_schema", schemaID, fmt.Errorf("schema %s already exists", schemaName)); err != nil {
+			return err
+		}
 		// The schema already exists, we just set the owner.
 		if err := setSchemaOwner(txn, d); err != nil {
 			return err
@@ -376,7 +400,7 @@ func resourcePostgreSQLSchemaUpdate(db *DBConnection, d *schema.ResourceData) er
 		return err
 	}
 
-	if err := setSchemaOwner(txn, d); err != nil {
+	if err := updateSchemaOwner(db, txn, d); err != nil {
 		return err
 	}
 
@@ -432,6 +456,44 @@ func setSchemaOwner(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+// updateSchemaOwner transfers the ownership of the schema to the new owner in place. When the
+// connected user is not a superuser (e.g. on AWS RDS), it must be a member of the current owner
+// to alter the schema and be able to SET ROLE to the new owner, so both roles are granted to it
+// for the time of the transfer.
+func updateSchemaOwner(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(schemaOwnerAttr) {
+		return nil
+	}
+
+	oldOwner, newOwner := d.GetChange(schemaOwnerAttr)
+	var rolesToGrant []string
+	for _, owner := range []string{oldOwner.(string), newOwner.(string)} {
+		if owner != "" {
+			rolesToGrant = append(rolesToGrant, owner)
+		}
+	}
+
+	err := withRolesGranted(txn, rolesToGrant, func() error {
+		return setSchemaOwner(txn, d)
+	})
+	if err != nil {
+		// The transaction is aborted, the connected user cannot be queried
+		return schemaOwnerError(err, newOwner.(string), db.client.config.getDatabaseUsername())
+	}
+
+	return nil
+}
+
+// schemaOwnerError explains the privileges needed to transfer the ownership of a schema if err
+// has been caused by a missing privilege.
+func schemaOwnerError(err error, owner, currentUser string) error {
+	return ownerMembershipError(err, owner, currentUser, fmt.Sprintf(
+		"must be able to SET ROLE %[1]s to transfer the ownership of a schema to it, "+
+			"and %[1]s must have the CREATE privilege on the database",
+		pq.QuoteIdentifier(owner),
+	))
+}
+
 func setSchemaPolicy(txn *sql.Tx, d *schema.ResourceData) error {
 	if !d.HasChange(schemaPolicyAttr) {
 		return nil
@@ -593,10 +655,7 @@ func schemaPolicyToACL(policyMap map[string]interface{}) acl.Schema {
 }
 
 func generateSchemaID(d *schema.ResourceData, databaseName string) string {
-	SchemaID := strings.Join([]string{
-		getDatabase(d, databaseName),
-		d.Get(schemaNameAttr).(string),
-	}, ".")
+	SchemaID := joinResourceID(getDatabase(d, databaseName), d.Get(schemaNameAttr).(string))
 
 	return SchemaID
 }
@@ -607,7 +666,7 @@ func getDBSchemaName(d *schema.ResourceData, databaseName string) (string, strin
 
 	// When importing, we have to parse the ID to find schema and database names.
 	if schemaName == "" {
-		parsed := strings.Split(d.Id(), ".")
+		parsed := splitResourceID(d.Id())
 		if len(parsed) != 2 {
 			return "", "", fmt.Errorf("schema ID %s has not the expected format 'database.schema': %v", d.Id(), parsed)
 		}
@@ -2,11 +2,15 @@ package postgresql
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestAccPostgresqlSchema_Basic(t *testing.T) {
@@ -282,6 +286,162 @@ resource "postgresql_schema" "public" {
 	})
 }
 
+func TestAccPostgresqlSchema_AdoptExisting(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_schema" "test" {
+  name           = "test_schema"
+  database       = "%s"
+  owner          = "%s"
+  adopt_existing = %t
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				// test_schema is created by setupTestDatabase
+				Config: fmt.Sprintf(config, dbName, roleName, false),
+				ExpectError: regexp.MustCompile(regexp.QuoteMeta(
+					fmt.Sprintf("terraform import postgresql_schema.<name> %s.test_schema", dbName),
+				)),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, roleName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlSchemaExists("postgresql_schema.test", "test_schema"),
+					testAccCheckSchemaOwner(dbName, "test_schema", roleName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlSchema_ChangeOwner(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	newOwner := roleName + "_new_owner"
+	teardownRole := createTestRole(t, newOwner)
+	defer teardownRole()
+
+	// test_schema is created by setupTestDatabase
+	config := `
+resource "postgresql_schema" "test" {
+  name           = "test_schema"
+  database       = "%s"
+  owner          = "%s"
+  adopt_existing = true
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema.test", "owner", roleName),
+					testAccCheckSchemaOwner(dbName, "test_schema", roleName),
+					testAccCreateSchemaTable(dbName, "test_schema"),
+				),
+			},
+			{
+				// The ownership is transferred in place, the objects of the schema are kept
+				Config: fmt.Sprintf(config, dbName, newOwner),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema.test", "owner", newOwner),
+					testAccCheckSchemaOwner(dbName, "test_schema", newOwner),
+					testAccCheckTableExists(dbName, "test_schema.test_table"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccPostgresqlSchema_ChangeOwnerNotMember transfers the ownership of a schema with a connected user
+// which is not a superuser and cannot be granted the new owner.
+func TestAccPostgresqlSchema_ChangeOwnerNotMember(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	newOwner := roleName + "_new_owner"
+	teardownRole := createTestRole(t, newOwner)
+	defer teardownRole()
+
+	// The provider connects with the owner of the database and of the schema
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr("postgres"), fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", dbName, roleName))
+	dbExecute(t, testConfig.connStr(dbName), fmt.Sprintf("CREATE SCHEMA limited AUTHORIZATION %s", roleName))
+
+	config := `
+provider "postgresql" {
+  username  = "%s"
+  password  = "%s"
+  superuser = false
+}
+
+resource "postgresql_schema" "test" {
+  name     = "limited"
+  database = "%s"
+  owner    = "%s"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, roleName, testRolePassword, dbName, roleName),
+				Check:  testAccCheckSchemaOwner(dbName, "limited", roleName),
+			},
+			{
+				Config: fmt.Sprintf(config, roleName, testRolePassword, dbName, newOwner),
+				// The hint is added to the error of the aborted transaction
+				ExpectError: regexp.MustCompile("must be able to SET ROLE"),
+			},
+		},
+	})
+}
+
+func TestSchemaOwnerError(t *testing.T) {
+	var tests = []struct {
+		err        error
+		membership bool
+	}{
+		{&pq.Error{Code: "42501", Message: `must be able to SET ROLE "app_owner"`}, true},
+		{&pq.Error{Code: "42501", Message: `must be member of role "app_owner"`}, true},
+		{&pq.Error{Code: "3F000", Message: `schema "app" does not exist`}, false},
+		{errors.New("connection refused"), false},
+	}
+
+	for _, test := range tests {
+		err := schemaOwnerError(test.err, "app_owner", "rds_admin")
+		if !errors.Is(err, test.err) {
+			t.Errorf("schemaOwnerError(%v) should wrap the original error, got %v", test.err, err)
+		}
+		if membership := strings.Contains(err.Error(), `GRANT "app_owner" TO "rds_admin"`); membership != test.membership {
+			t.Errorf("schemaOwnerError(%v): expected membership message %v, got %v", test.err, test.membership, err)
+		}
+	}
+}
+
 func testAccCheckPostgresqlSchemaDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client)
 
@@ -387,6 +547,26 @@ func testAccCreateSchemaTable(database, schemaName string) resource.TestCheckFun
 	}
 }
 
+func testAccCheckTableExists(database, tableName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client).config.NewClient(database)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var exists bool
+		if err := db.QueryRow("SELECT to_regclass($1) IS NOT NULL", tableName).Scan(&exists); err != nil {
+			return fmt.Errorf("could not check if table %s exists: %w", tableName, err)
+		}
+		if !exists {
+			return fmt.Errorf("table %s does not exist", tableName)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckSchemaOwner(database, schemaName, expectedOwner string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		client := testAccProvider.Meta().(*Client).config.NewClient(database)
@@ -0,0 +1,574 @@
+package postgresql
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	seqNameAttr        = "name"
+	seqSchemaAttr      = "schema"
+	seqDatabaseAttr    = "database"
+	seqDataTypeAttr    = "data_type"
+	seqStartAttr       = "start"
+	seqIncrementAttr   = "increment"
+	seqMinValueAttr    = "min_value"
+	seqMaxValueAttr    = "max_value"
+	seqCacheAttr       = "cache"
+	seqCycleAttr       = "cycle"
+	seqOwnerAttr       = "owner"
+	seqOwnedByAttr     = "owned_by"
+	seqDropCascadeAttr = "drop_cascade"
+	seqLastValueAttr   = "last_value"
+)
+
+var seqOwnedByRegexp = regexp.MustCompile(`^[^.]+\.[^.]+$`)
+
+func resourcePostgreSQLSequence() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLSequenceCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLSequenceRead),
+		Update: PGResourceFunc(resourcePostgreSQLSequenceUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLSequenceDelete),
+		Exists: PGResourceExistsFunc(resourcePostgreSQLSequenceExists),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			seqNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The name of the sequence",
+				ValidateFunc: validateIdentifier,
+			},
+			seqSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "public",
+				Description: "The schema where the sequence is created",
+			},
+			seqDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the sequence is created",
+			},
+			seqDataTypeAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "bigint",
+				ValidateFunc: validation.StringInSlice([]string{
+					"smallint",
+					"integer",
+					"bigint",
+				}, false),
+				Description: "The data type of the sequence (requires PostgreSQL 10+ if not bigint)",
+			},
+			seqStartAttr: {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "The starting value of the sequence",
+			},
+			seqIncrementAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "The value added to the current sequence value to create a new value",
+				ValidateFunc: validation.IntNotInSlice([]int{0}),
+			},
+			seqMinValueAttr: {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "The minimum value of the sequence",
+			},
+			seqMaxValueAttr: {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "The maximum value of the sequence",
+			},
+			seqCacheAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "How many sequence numbers are to be preallocated and stored in memory for faster access",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			seqCycleAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the sequence wraps around when the max or min value has been reached",
+			},
+			seqOwnerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ROLE name who owns the sequence",
+			},
+			seqOwnedByAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The table column ('table.column') the sequence is associated with",
+				ValidateFunc: validation.StringMatch(seqOwnedByRegexp, "must be in the format 'table.column'"),
+			},
+			seqDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the sequence",
+			},
+			seqLastValueAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The last sequence value written to disk",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLSequenceCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := checkSequenceFeatures(db, d); err != nil {
+		return err
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(seqSchemaAttr).(string)
+	seqName := d.Get(seqNameAttr).(string)
+
+	b := bytes.NewBufferString("CREATE SEQUENCE ")
+	fmt.Fprint(b, pq.QuoteIdentifier(schemaName), ".", pq.QuoteIdentifier(seqName))
+
+	if db.featureSupported(featureSequenceDataType) {
+		fmt.Fprint(b, " AS ", d.Get(seqDataTypeAttr).(string))
+	}
+
+	fmt.Fprint(b, " INCREMENT BY ", d.Get(seqIncrementAttr).(int))
+
+	// The bounds and the start can be set to 0
+	if v, ok := getConfiguredInt(d, seqMinValueAttr); ok {
+		fmt.Fprint(b, " MINVALUE ", v)
+	}
+	if v, ok := getConfiguredInt(d, seqMaxValueAttr); ok {
+		fmt.Fprint(b, " MAXVALUE ", v)
+	}
+	if v, ok := getConfiguredInt(d, seqStartAttr); ok {
+		fmt.Fprint(b, " START WITH ", v)
+	}
+
+	fmt.Fprint(b, " CACHE ", d.Get(seqCacheAttr).(int))
+
+	if d.Get(seqCycleAttr).(bool) {
+		fmt.Fprint(b, " CYCLE")
+	} else {
+		fmt.Fprint(b, " NO CYCLE")
+	}
+
+	if v, ok := d.GetOk(seqOwnedByAttr); ok {
+		fmt.Fprint(b, " OWNED BY ", seqOwnedByToSQL(schemaName, v.(string)))
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("could not create sequence %s: %w", seqName, err)
+	}
+
+	if v, ok := d.GetOk(seqOwnerAttr); ok {
+		if err := alterSequenceOwner(txn, schemaName, seqName, v.(string)); err != nil {
+			return err
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating sequence: %w", err)
+	}
+
+	d.SetId(generateSequenceID(database, schemaName, seqName))
+
+	return resourcePostgreSQLSequenceReadImpl(db, d)
+}
+
+func checkSequenceFeatures(db *DBConnection, d *schema.ResourceData) error {
+	if d.Get(seqDataTypeAttr).(string) != "bigint" && !db.featureSupported(featureSequenceDataType) {
+		return fmt.Errorf("sequence data_type is not supported %s", db.unsupportedServer())
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLSequenceExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	database, schemaName, seqName, err := getDBSequenceName(d, db.client)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the database exists
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	return sequenceExists(txn, schemaName, seqName)
+}
+
+func resourcePostgreSQLSequenceRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLSequenceReadImpl(db, d)
+}
+
+func resourcePostgreSQLSequenceReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, seqName, err := getDBSequenceName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	exists, err := sequenceExists(txn, schemaName, seqName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL sequence (%s.%s) not found in database %s", schemaName, seqName, database)
+		d.SetId("")
+		return nil
+	}
+
+	var dataType, owner string
+	var start, increment, minValue, maxValue, cache int
+	var cycle bool
+	var lastValue sql.NullInt64
+
+	if db.featureSupported(featureSequenceDataType) {
+		query := `SELECT data_type::text, start_value, increment_by, min_value, max_value, cache_size, cycle, last_value, sequenceowner ` +
+			`FROM pg_catalog.pg_sequences WHERE schemaname = $1 AND sequencename = $2`
+		err = txn.QueryRow(query, schemaName, seqName).Scan(
+			&dataType, &start, &increment, &minValue, &maxValue, &cache, &cycle, &lastValue, &owner,
+		)
+	} else {
+		// pg_sequences does not exist before Postgres 10, the parameters are stored in the sequence itself.
+		dataType = "bigint"
+		query := fmt.Sprintf(
+			`SELECT start_value, increment_by, min_value, max_value, cache_value, is_cycled, `+
+				`CASE WHEN is_called THEN last_value END, `+
+				`(SELECT pg_catalog.pg_get_userbyid(relowner) FROM pg_catalog.pg_class WHERE oid = '%[1]s'::regclass) `+
+				`FROM %[1]s`,
+			fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(seqName)),
+		)
+		err = txn.QueryRow(query).Scan(&start, &increment, &minValue, &maxValue, &cache, &cycle, &lastValue, &owner)
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading sequence: %w", err)
+	}
+
+	ownedBy, err := getSequenceOwnedBy(txn, schemaName, seqName)
+	if err != nil {
+		return err
+	}
+
+	d.Set(seqNameAttr, seqName)
+	d.Set(seqSchemaAttr, schemaName)
+	d.Set(seqDatabaseAttr, database)
+	d.Set(seqDataTypeAttr, dataType)
+	d.Set(seqStartAttr, start)
+	d.Set(seqIncrementAttr, increment)
+	d.Set(seqMinValueAttr, minValue)
+	d.Set(seqMaxValueAttr, maxValue)
+	d.Set(seqCacheAttr, cache)
+	d.Set(seqCycleAttr, cycle)
+	d.Set(seqOwnerAttr, owner)
+	d.Set(seqOwnedByAttr, ownedBy)
+	d.Set(seqLastValueAttr, lastValue.Int64)
+	d.SetId(generateSequenceID(database, schemaName, seqName))
+
+	return nil
+}
+
+// getSequenceOwnedBy returns the table column ('table.column') the sequence is owned by
+// (i.e.: the column the sequence will be dropped with), or an empty string.
+func getSequenceOwnedBy(txn *sql.Tx, schemaName, seqName string) (string, error) {
+	var tableName, columnName string
+	query := `SELECT t.relname, a.attname ` +
+		`FROM pg_catalog.pg_depend d ` +
+		`JOIN pg_catalog.pg_class s ON s.oid = d.objid ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = s.relnamespace ` +
+		`JOIN pg_catalog.pg_class t ON t.oid = d.refobjid ` +
+		`JOIN pg_catalog.pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid ` +
+		`WHERE d.classid = 'pg_catalog.pg_class'::regclass AND d.refclassid = 'pg_catalog.pg_class'::regclass ` +
+		`AND d.deptype = 'a' AND n.nspname = $1 AND s.relname = $2`
+	err := txn.QueryRow(query, schemaName, seqName).Scan(&tableName, &columnName)
+	switch {
+	case err == sql.ErrNoRows:
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("could not read owner column of sequence %s: %w", seqName, err)
+	}
+
+	return fmt.Sprintf("%s.%s", tableName, columnName), nil
+}
+
+func resourcePostgreSQLSequenceUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := checkSequenceFeatures(db, d); err != nil {
+		return err
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := setSequenceName(txn, d, database); err != nil {
+		return err
+	}
+
+	if err := setSequenceSchema(txn, d, database); err != nil {
+		return err
+	}
+
+	if err := setSequenceParameters(db, txn, d); err != nil {
+		return err
+	}
+
+	if err := setSequenceOwner(txn, d); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating sequence: %w", err)
+	}
+
+	return resourcePostgreSQLSequenceReadImpl(db, d)
+}
+
+func setSequenceName(txn *sql.Tx, d *schema.ResourceData, database string) error {
+	if !d.HasChange(seqNameAttr) {
+		return nil
+	}
+
+	oraw, nraw := d.GetChange(seqNameAttr)
+	oldSchema, _ := d.GetChange(seqSchemaAttr)
+
+	sql := fmt.Sprintf("ALTER SEQUENCE %s.%s RENAME TO %s",
+		pq.QuoteIdentifier(oldSchema.(string)),
+		pq.QuoteIdentifier(oraw.(string)),
+		pq.QuoteIdentifier(nraw.(string)),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating sequence NAME: %w", err)
+	}
+	d.SetId(generateSequenceID(database, oldSchema.(string), nraw.(string)))
+
+	return nil
+}
+
+func setSequenceSchema(txn *sql.Tx, d *schema.ResourceData, database string) error {
+	if !d.HasChange(seqSchemaAttr) {
+		return nil
+	}
+
+	oraw, nraw := d.GetChange(seqSchemaAttr)
+	seqName := d.Get(seqNameAttr).(string)
+
+	sql := fmt.Sprintf("ALTER SEQUENCE %s.%s SET SCHEMA %s",
+		pq.QuoteIdentifier(oraw.(string)),
+		pq.QuoteIdentifier(seqName),
+		pq.QuoteIdentifier(nraw.(string)),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating sequence SCHEMA: %w", err)
+	}
+	d.SetId(generateSequenceID(database, nraw.(string), seqName))
+
+	return nil
+}
+
+func setSequenceParameters(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
+	schemaName := d.Get(seqSchemaAttr).(string)
+	seqName := d.Get(seqNameAttr).(string)
+
+	var params []string
+	if d.HasChange(seqDataTypeAttr) && db.featureSupported(featureSequenceDataType) {
+		params = append(params, "AS "+d.Get(seqDataTypeAttr).(string))
+	}
+	if d.HasChange(seqIncrementAttr) {
+		params = append(params, fmt.Sprintf("INCREMENT BY %d", d.Get(seqIncrementAttr).(int)))
+	}
+	if d.HasChange(seqMinValueAttr) {
+		params = append(params, fmt.Sprintf("MINVALUE %d", d.Get(seqMinValueAttr).(int)))
+	}
+	if d.HasChange(seqMaxValueAttr) {
+		params = append(params, fmt.Sprintf("MAXVALUE %d", d.Get(seqMaxValueAttr).(int)))
+	}
+	if d.HasChange(seqStartAttr) {
+		params = append(params, fmt.Sprintf("START WITH %d", d.Get(seqStartAttr).(int)))
+	}
+	if d.HasChange(seqCacheAttr) {
+		params = append(params, fmt.Sprintf("CACHE %d", d.Get(seqCacheAttr).(int)))
+	}
+	if d.HasChange(seqCycleAttr) {
+		if d.Get(seqCycleAttr).(bool) {
+			params = append(params, "CYCLE")
+		} else {
+			params = append(params, "NO CYCLE")
+		}
+	}
+	if d.HasChange(seqOwnedByAttr) {
+		params = append(params, "OWNED BY "+seqOwnedByToSQL(schemaName, d.Get(seqOwnedByAttr).(string)))
+	}
+
+	if len(params) == 0 {
+		return nil
+	}
+
+	sql := fmt.Sprintf("ALTER SEQUENCE %s.%s %s",
+		pq.QuoteIdentifier(schemaName),
+		pq.QuoteIdentifier(seqName),
+		strings.Join(params, " "),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating sequence: %w", err)
+	}
+
+	return nil
+}
+
+func setSequenceOwner(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(seqOwnerAttr) {
+		return nil
+	}
+
+	owner := d.Get(seqOwnerAttr).(string)
+	if owner == "" {
+		return errors.New("Error setting sequence owner to an empty string")
+	}
+
+	return alterSequenceOwner(txn, d.Get(seqSchemaAttr).(string), d.Get(seqNameAttr).(string), owner)
+}
+
+func alterSequenceOwner(txn *sql.Tx, schemaName, seqName, owner string) error {
+	sql := fmt.Sprintf("ALTER SEQUENCE %s.%s OWNER TO %s",
+		pq.QuoteIdentifier(schemaName),
+		pq.QuoteIdentifier(seqName),
+		pq.QuoteIdentifier(owner),
+	)
+
+	return withRolesGranted(txn, []string{owner}, func() error {
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating sequence OWNER: %w", err)
+		}
+		return nil
+	})
+}
+
+func resourcePostgreSQLSequenceDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	dropMode := "RESTRICT"
+	if d.Get(seqDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	}
+
+	sql := fmt.Sprintf("DROP SEQUENCE %s.%s %s",
+		pq.QuoteIdentifier(d.Get(seqSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(seqNameAttr).(string)),
+		dropMode,
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop sequence: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting sequence: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// seqOwnedByToSQL returns the OWNED BY target of a sequence,
+// the table needs to be in the same schema as the sequence.
+func seqOwnedByToSQL(schemaName, ownedBy string) string {
+	if ownedBy == "" {
+		return "NONE"
+	}
+
+	parts := strings.SplitN(ownedBy, ".", 2)
+	return fmt.Sprintf("%s.%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(parts[0]), pq.QuoteIdentifier(parts[1]))
+}
+
+func sequenceExists(txn *sql.Tx, schemaName, seqName string) (bool, error) {
+	var _rez bool
+	err := txn.QueryRow(
+		`SELECT TRUE FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+			`WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind = 'S'`,
+		schemaName, seqName,
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if sequence exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func generateSequenceID(database, schemaName, seqName string) string {
+	return joinResourceID(database, schemaName, seqName)
+}
+
+// getDBSequenceName returns database, schema and sequence name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBSequenceName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(seqSchemaAttr).(string)
+	seqName := d.Get(seqNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and sequence names.
+	if seqName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("sequence ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		seqName = parsed[2]
+	}
+	return database, schemaName, seqName, nil
+}
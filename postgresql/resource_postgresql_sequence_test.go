@@ -0,0 +1,231 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlSequence_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	createTestTables(t, dbSuffix, []string{"test_schema.test_table"}, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_sequence" "test" {
+  database = "%s"
+  schema   = "test_schema"
+  name     = "test_seq"
+  start    = 10
+}
+`, dbName)
+
+	configUpdated := fmt.Sprintf(`
+resource "postgresql_sequence" "test" {
+  database  = "%s"
+  schema    = "test_schema"
+  name      = "test_seq_renamed"
+  start     = 10
+  increment = 5
+  min_value = 5
+  max_value = 1000
+  cache     = 10
+  cycle     = true
+  owner     = "%s"
+  owned_by  = "test_table.val"
+}
+`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSequenceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlSequenceExists("postgresql_sequence.test"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "start", "10"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "increment", "1"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "min_value", "1"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "data_type", "bigint"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "owned_by", ""),
+				),
+			},
+			{
+				Config: configUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlSequenceExists("postgresql_sequence.test"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "name", "test_seq_renamed"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "increment", "5"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "min_value", "5"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "max_value", "1000"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "cache", "10"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "cycle", "true"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "owner", roleName),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "owned_by", "test_table.val"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_sequence.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"drop_cascade"},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlSequence_DataType(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_sequence" "test" {
+  database  = "%s"
+  name      = "test_seq"
+  data_type = "%%s"
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureSequenceDataType)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSequenceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "smallint"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlSequenceExists("postgresql_sequence.test"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "data_type", "smallint"),
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "max_value", "32767"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, "integer"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_sequence.test", "data_type", "integer"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlSequence_ZeroValues(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	// The creation fails if the zeros are not sent: the default minimum of an ascending sequence is 1
+	// and the default maximum of a descending one is -1.
+	config := fmt.Sprintf(`
+resource "postgresql_sequence" "ascending" {
+  database  = "%[1]s"
+  name      = "test_seq_ascending"
+  min_value = 0
+  start     = 0
+}
+
+resource "postgresql_sequence" "descending" {
+  database  = "%[1]s"
+  name      = "test_seq_descending"
+  increment = -1
+  max_value = 0
+  start     = 0
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSequenceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlSequenceExists("postgresql_sequence.ascending"),
+					resource.TestCheckResourceAttr("postgresql_sequence.ascending", "min_value", "0"),
+					resource.TestCheckResourceAttr("postgresql_sequence.ascending", "start", "0"),
+					testAccCheckPostgresqlSequenceExists("postgresql_sequence.descending"),
+					resource.TestCheckResourceAttr("postgresql_sequence.descending", "max_value", "0"),
+					resource.TestCheckResourceAttr("postgresql_sequence.descending", "start", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlSequenceDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_sequence" {
+			continue
+		}
+
+		txn, err := startTransaction(client, rs.Primary.Attributes[seqDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := sequenceExists(txn, rs.Primary.Attributes[seqSchemaAttr], rs.Primary.Attributes[seqNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking sequence %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Sequence still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlSequenceExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[seqDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := sequenceExists(txn, rs.Primary.Attributes[seqSchemaAttr], rs.Primary.Attributes[seqNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking sequence %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Sequence not found")
+		}
+
+		return nil
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/lib/pq"
@@ -33,9 +32,10 @@ func resourcePostgreSQLServer() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			serverNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the foreign server to be created",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the foreign server to be created",
 			},
 			serverTypeAttr: {
 				Type:        schema.TypeString,
@@ -81,8 +81,8 @@ func resourcePostgreSQLServer() *schema.Resource {
 func resourcePostgreSQLServerCreate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureServer) {
 		return fmt.Errorf(
-			"Foreign Server resource is not supported for this Postgres version (%s)",
-			db.version,
+			"Foreign Server resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -102,17 +102,7 @@ func resourcePostgreSQLServerCreate(db *DBConnection, d *schema.ResourceData) er
 	fmt.Fprint(b, " FOREIGN DATA WRAPPER ", pq.QuoteIdentifier(d.Get(serverFDWAttr).(string)))
 
 	if options, ok := d.GetOk(serverOptionsAttr); ok {
-		fmt.Fprint(b, " OPTIONS ( ")
-		cnt := 0
-		len := len(options.(map[string]interface{}))
-		for k, v := range options.(map[string]interface{}) {
-			fmt.Fprint(b, " ", pq.QuoteIdentifier(k), " ", pq.QuoteLiteral(v.(string)))
-			if cnt < len-1 {
-				fmt.Fprint(b, ", ")
-			}
-			cnt++
-		}
-		fmt.Fprint(b, " ) ")
+		fmt.Fprintf(b, " OPTIONS (%s)", foreignOptionsToSQL(options.(map[string]interface{})))
 	}
 
 	txn, err := startTransaction(db.client, "")
@@ -150,8 +140,8 @@ func resourcePostgreSQLServerCreate(db *DBConnection, d *schema.ResourceData) er
 func resourcePostgreSQLServerRead(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureServer) {
 		return fmt.Errorf(
-			"Foreign Server resource is not supported for this Postgres version (%s)",
-			db.version,
+			"Foreign Server resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -181,17 +171,11 @@ func resourcePostgreSQLServerReadImpl(db *DBConnection, d *schema.ResourceData)
 		return fmt.Errorf("Error reading foreign server: %w", err)
 	}
 
-	mappedOptions := make(map[string]interface{})
-	for _, v := range serverOptions {
-		pair := strings.Split(v, "=")
-		mappedOptions[pair[0]] = pair[1]
-	}
-
 	d.Set(serverNameAttr, serverName)
 	d.Set(serverTypeAttr, serverType)
 	d.Set(serverVersionAttr, serverVersion)
 	d.Set(serverOwnerAttr, serverOwner)
-	d.Set(serverOptionsAttr, mappedOptions)
+	d.Set(serverOptionsAttr, parseForeignOptions(serverOptions))
 	d.Set(serverFDWAttr, serverFDW)
 	d.SetId(serverName)
 
@@ -201,8 +185,8 @@ func resourcePostgreSQLServerReadImpl(db *DBConnection, d *schema.ResourceData)
 func resourcePostgreSQLServerDelete(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureServer) {
 		return fmt.Errorf(
-			"Foreign Server resource is not supported for this Postgres version (%s)",
-			db.version,
+			"Foreign Server resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -236,8 +220,8 @@ func resourcePostgreSQLServerDelete(db *DBConnection, d *schema.ResourceData) er
 func resourcePostgreSQLServerUpdate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureServer) {
 		return fmt.Errorf(
-			"Foreign Server resource is not supported for this Postgres version (%s)",
-			db.version,
+			"Foreign Server resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -282,32 +266,9 @@ func setServerVersionOptionsIfChanged(txn *sql.Tx, d *schema.ResourceData) error
 
 	if d.HasChange(serverOptionsAttr) {
 		oldOptions, newOptions := d.GetChange(serverOptionsAttr)
-		fmt.Fprint(b, " OPTIONS ( ")
-		cnt := 0
-		len := len(newOptions.(map[string]interface{}))
-		toRemove := oldOptions.(map[string]interface{})
-		for k, v := range newOptions.(map[string]interface{}) {
-			operation := "ADD"
-			if oldOptions.(map[string]interface{})[k] != nil {
-				operation = "SET"
-				delete(toRemove, k)
-			}
-			fmt.Fprintf(b, " %s %s %s ", operation, pq.QuoteIdentifier(k), pq.QuoteLiteral(v.(string)))
-			if cnt < len-1 {
-				fmt.Fprint(b, ", ")
-			}
-			cnt++
-		}
-
-		for k := range toRemove {
-			if cnt != 0 { // starting with 0 means to drop all the options. Cannot start with comma
-				fmt.Fprint(b, " , ")
-			}
-			fmt.Fprintf(b, " DROP %s ", pq.QuoteIdentifier(k))
-			cnt++
+		if options := alterForeignOptionsToSQL(oldOptions.(map[string]interface{}), newOptions.(map[string]interface{})); options != "" {
+			fmt.Fprintf(b, " OPTIONS (%s)", options)
 		}
-
-		fmt.Fprint(b, " ) ")
 	}
 
 	sql := b.String()
@@ -0,0 +1,374 @@
+package postgresql
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	sqlDatabaseAttr          = "database"
+	sqlCreateSQLAttr         = "create_sql"
+	sqlCreateSQLFileAttr     = "create_sql_file"
+	sqlCreateSQLFileHashAttr = "create_sql_file_sha256"
+	sqlUpdateSQLAttr         = "update_sql"
+	sqlUpdateSQLFileAttr     = "update_sql_file"
+	sqlDestroySQLAttr        = "destroy_sql"
+	sqlDestroySQLFileAttr    = "destroy_sql_file"
+	sqlExistsQueryAttr       = "exists_query"
+	sqlTriggersAttr          = "triggers"
+)
+
+// resourcePostgreSQLSQL runs arbitrary statements to manage objects the provider does not model.
+// Terraform only knows about these objects through exists_query, so drift is not detected.
+func resourcePostgreSQLSQL() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLSQLCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLSQLRead),
+		Update: PGResourceFunc(resourcePostgreSQLSQLUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLSQLDelete),
+		Exists: PGResourceExistsFunc(resourcePostgreSQLSQLExists),
+
+		CustomizeDiff: resourcePostgreSQLSQLCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			sqlDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the statements are executed",
+			},
+			sqlCreateSQLAttr: {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MinItems:     1,
+				Elem:         &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotWhiteSpace},
+				ExactlyOneOf: []string{sqlCreateSQLAttr, sqlCreateSQLFileAttr},
+				Description:  "The statements executed in order, in one transaction, to create the objects",
+			},
+			sqlCreateSQLFileAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "The path of a file whose statements, separated by semicolons, are executed in order in one transaction to create the objects",
+			},
+			sqlCreateSQLFileHashAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 of the content of create_sql_file, a change being applied as a change of create_sql",
+			},
+			sqlUpdateSQLAttr: {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotWhiteSpace},
+				ConflictsWith: []string{sqlUpdateSQLFileAttr},
+				Description:   "The statements executed in order, in one transaction, when create_sql or triggers change. The resource is recreated if not set",
+			},
+			sqlUpdateSQLFileAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "The path of a file whose statements are executed instead of update_sql",
+			},
+			sqlDestroySQLAttr: {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotWhiteSpace},
+				ConflictsWith: []string{sqlDestroySQLFileAttr},
+				Description:   "The statements executed in order, in one transaction, to drop the objects. The objects are left in place if not set",
+			},
+			sqlDestroySQLFileAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "The path of a file whose statements are executed instead of destroy_sql, it is read when the resource is destroyed",
+			},
+			sqlExistsQueryAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "A query returning one boolean, the resource is created again if it returns false",
+			},
+			sqlTriggersAttr: {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values which run update_sql, or recreate the resource, when they change",
+			},
+		},
+	}
+}
+
+// resourcePostgreSQLSQLCustomizeDiff plans the change of the content of create_sql_file, and recreates
+// the resource when the create statements or triggers change and there is no update statement to apply the change.
+func resourcePostgreSQLSQLCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if path := diff.Get(sqlCreateSQLFileAttr).(string); path != "" {
+		hash, err := sqlFileHash(path)
+		if err != nil {
+			return err
+		}
+		if hash != diff.Get(sqlCreateSQLFileHashAttr).(string) {
+			if err := diff.SetNew(sqlCreateSQLFileHashAttr, hash); err != nil {
+				return err
+			}
+		}
+	}
+
+	if diff.Id() == "" || len(diff.Get(sqlUpdateSQLAttr).([]interface{})) > 0 || diff.Get(sqlUpdateSQLFileAttr).(string) != "" {
+		return nil
+	}
+
+	for _, attr := range []string{sqlCreateSQLAttr, sqlCreateSQLFileAttr, sqlCreateSQLFileHashAttr, sqlTriggersAttr} {
+		if diff.HasChange(attr) {
+			if err := diff.ForceNew(attr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sqlFileHash returns the SHA-256 of the content of the file.
+func sqlFileHash(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read SQL file: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func resourcePostgreSQLSQLCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	statements, err := sqlStatements(d, sqlCreateSQLAttr, sqlCreateSQLFileAttr)
+	if err != nil {
+		return err
+	}
+	if err := execSQLStatements(db, database, sqlCreateSQLAttr, statements); err != nil {
+		return err
+	}
+
+	d.SetId(resource.PrefixedUniqueId(database + "."))
+
+	return resourcePostgreSQLSQLReadImpl(db, d)
+}
+
+// sqlStatement is a statement of the resource, with the label identifying it in the errors.
+type sqlStatement struct {
+	label     string
+	statement string
+}
+
+// sqlStatements returns the statements of the list attribute, or of the file of fileAttr
+// split in statements when it is set, e.g.: create_sql or create_sql_file.
+func sqlStatements(d *schema.ResourceData, listAttr, fileAttr string) ([]sqlStatement, error) {
+	var statements []sqlStatement
+
+	path := d.Get(fileAttr).(string)
+	if path == "" {
+		for i, statement := range d.Get(listAttr).([]interface{}) {
+			statements = append(statements, sqlStatement{label: fmt.Sprintf("%s[%d]", listAttr, i), statement: statement.(string)})
+		}
+		return statements, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", fileAttr, err)
+	}
+	for i, statement := range splitSQLScript(string(content)) {
+		statements = append(statements, sqlStatement{
+			label:     fmt.Sprintf("statement %d of %s (line %d)", i+1, fileAttr, statement.line),
+			statement: statement.text,
+		})
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("%s %s contains no statement", fileAttr, path)
+	}
+	return statements, nil
+}
+
+// sqlScriptStatement is a statement of a SQL script, with the line where it starts.
+type sqlScriptStatement struct {
+	text string
+	line int
+}
+
+// splitSQLScript splits the script on the semicolons which are not in a literal, a quoted identifier,
+// a dollar-quoted string, a comment or parentheses (e.g.: the commands of a rule). The statements made
+// only of comments are ignored.
+func splitSQLScript(script string) []sqlScriptStatement {
+	var statements []sqlScriptStatement
+	depth, codeStart := 0, -1
+
+	appendStatement := func(end int) {
+		if codeStart >= 0 {
+			statements = append(statements, sqlScriptStatement{
+				text: strings.TrimSpace(script[codeStart:end]),
+				line: strings.Count(script[:codeStart], "\n") + 1,
+			})
+		}
+		codeStart = -1
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		end := i + 1
+		code := true
+		switch {
+		case c == '\'':
+			escapes := i > 0 && (script[i-1] == 'E' || script[i-1] == 'e')
+			end = literalEnd(script, i, escapes)
+		case c == '"':
+			end = literalEnd(script, i, false)
+		case c == '$':
+			end = dollarQuoteEnd(script, i)
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			code = false
+			if end = strings.IndexByte(script[i:], '\n'); end < 0 {
+				end = len(script)
+			} else {
+				end += i
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			code = false
+			if end = strings.Index(script[i+2:], "*/"); end < 0 {
+				end = len(script)
+			} else {
+				end += i + 4
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			code = false
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ';' && depth <= 0:
+			appendStatement(i)
+			i = end
+			continue
+		}
+		if code && codeStart < 0 {
+			codeStart = i
+		}
+		i = end
+	}
+	appendStatement(len(script))
+
+	return statements
+}
+
+// execSQLStatements executes the statements in order in one transaction. The error identifies
+// the statement which failed by its label.
+func execSQLStatements(db *DBConnection, database, attr string, statements []sqlStatement) error {
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	for _, statement := range statements {
+		if _, err := txn.Exec(statement.statement); err != nil {
+			return fmt.Errorf("could not execute %s (%s): %w", statement.label, sqlStatementSummary(statement.statement), err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit %s: %w", attr, err)
+	}
+
+	return nil
+}
+
+// sqlStatementSummary returns the first line of the statement, truncated, to identify it in errors.
+func sqlStatementSummary(statement string) string {
+	summary := strings.TrimSpace(statement)
+	if i := strings.IndexByte(summary, '\n'); i >= 0 {
+		summary = strings.TrimSpace(summary[:i]) + " ..."
+	}
+	if len(summary) > 80 {
+		summary = summary[:77] + "..."
+	}
+	return summary
+}
+
+func resourcePostgreSQLSQLExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	query := d.Get(sqlExistsQueryAttr).(string)
+	if query == "" {
+		return true, nil
+	}
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	var exists sql.NullBool
+	if err := txn.QueryRow(query).Scan(&exists); err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("could not run %s: %w", sqlExistsQueryAttr, err)
+	}
+
+	return exists.Bool, nil
+}
+
+func resourcePostgreSQLSQLRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLSQLReadImpl(db, d)
+}
+
+func resourcePostgreSQLSQLReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	exists, err := resourcePostgreSQLSQLExists(db, d)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] %s of PostgreSQL SQL resource %s returned false", sqlExistsQueryAttr, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set(sqlDatabaseAttr, getDatabase(d, db.client.databaseName))
+
+	return nil
+}
+
+func resourcePostgreSQLSQLUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if d.HasChanges(sqlCreateSQLAttr, sqlCreateSQLFileAttr, sqlCreateSQLFileHashAttr, sqlTriggersAttr) {
+		statements, err := sqlStatements(d, sqlUpdateSQLAttr, sqlUpdateSQLFileAttr)
+		if err != nil {
+			return err
+		}
+		if err := execSQLStatements(db, getDatabase(d, db.client.databaseName), sqlUpdateSQLAttr, statements); err != nil {
+			return err
+		}
+	}
+
+	return resourcePostgreSQLSQLReadImpl(db, d)
+}
+
+func resourcePostgreSQLSQLDelete(db *DBConnection, d *schema.ResourceData) error {
+	statements, err := sqlStatements(d, sqlDestroySQLAttr, sqlDestroySQLFileAttr)
+	if err != nil {
+		return err
+	}
+	if len(statements) == 0 {
+		log.Printf("[WARN] PostgreSQL SQL resource %s has no %s, its objects are left in place", d.Id(), sqlDestroySQLAttr)
+	} else if err := execSQLStatements(db, getDatabase(d, db.client.databaseName), sqlDestroySQLAttr, statements); err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,347 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSQLStatementSummary(t *testing.T) {
+	var cases = []struct {
+		statement string
+		expected  string
+	}{
+		{
+			statement: "CREATE TABLE test (id int)",
+			expected:  "CREATE TABLE test (id int)",
+		},
+		{
+			statement: "\n  CREATE TABLE test (\n    id int\n  )\n",
+			expected:  "CREATE TABLE test ( ...",
+		},
+		{
+			statement: "INSERT INTO test (value) VALUES ('0123456789012345678901234567890123456789012345678901234567890123456789')",
+			expected:  "INSERT INTO test (value) VALUES ('0123456789012345678901234567890123456789012...",
+		},
+	}
+
+	for _, c := range cases {
+		if out := sqlStatementSummary(c.statement); out != c.expected {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.expected)
+		}
+	}
+}
+
+func TestSplitSQLScript(t *testing.T) {
+	script := `-- Creates the events table
+CREATE TABLE events (id int, payload text DEFAULT 'a;b');
+INSERT INTO events VALUES (1, E'it\'s;');
+
+CREATE FUNCTION f() RETURNS int LANGUAGE plpgsql AS $body$
+BEGIN
+  RETURN 1;
+END
+$body$;
+CREATE RULE r AS ON INSERT TO v DO INSTEAD (INSERT INTO a VALUES (1); INSERT INTO b VALUES (2));
+/* ; */ SELECT ";"
+-- the end;
+`
+
+	expected := []sqlScriptStatement{
+		{text: "CREATE TABLE events (id int, payload text DEFAULT 'a;b')", line: 2},
+		{text: `INSERT INTO events VALUES (1, E'it\'s;')`, line: 3},
+		{text: "CREATE FUNCTION f() RETURNS int LANGUAGE plpgsql AS $body$\nBEGIN\n  RETURN 1;\nEND\n$body$", line: 5},
+		{text: "CREATE RULE r AS ON INSERT TO v DO INSTEAD (INSERT INTO a VALUES (1); INSERT INTO b VALUES (2))", line: 10},
+		{text: "SELECT \";\"\n-- the end;", line: 11},
+	}
+	if statements := splitSQLScript(script); !reflect.DeepEqual(statements, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, statements)
+	}
+
+	if statements := splitSQLScript("-- nothing to run;\n  ;\n"); len(statements) != 0 {
+		t.Fatalf("expected no statement, got %#v", statements)
+	}
+}
+
+func TestSQLStatementsFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "up.sql")
+	if err := os.WriteFile(path, []byte("CREATE TABLE a (id int);\n\nCREATE TABLE b (id int);\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	d := resourcePostgreSQLSQL().TestResourceData()
+	d.Set(sqlCreateSQLFileAttr, path)
+	statements, err := sqlStatements(d, sqlCreateSQLAttr, sqlCreateSQLFileAttr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []sqlStatement{
+		{label: "statement 1 of create_sql_file (line 1)", statement: "CREATE TABLE a (id int)"},
+		{label: "statement 2 of create_sql_file (line 3)", statement: "CREATE TABLE b (id int)"},
+	}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, statements)
+	}
+
+	d.Set(sqlCreateSQLFileAttr, filepath.Join(t.TempDir(), "missing.sql"))
+	if _, err := sqlStatements(d, sqlCreateSQLAttr, sqlCreateSQLFileAttr); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
+
+func TestSQLFileChangeDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "up.sql")
+	if err := os.WriteFile(path, []byte("CREATE TABLE a (id int);"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := sqlFileHash(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		stateHash   string
+		config      map[string]interface{}
+		changed     bool
+		requiresNew bool
+	}{
+		{hash, map[string]interface{}{sqlCreateSQLFileAttr: path}, false, false},
+		{"previous", map[string]interface{}{sqlCreateSQLFileAttr: path}, true, true},
+		{"previous", map[string]interface{}{sqlCreateSQLFileAttr: path, sqlUpdateSQLAttr: []interface{}{"SELECT 1"}}, true, false},
+	}
+
+	for _, test := range tests {
+		state := &terraform.InstanceState{ID: "db.1", Attributes: map[string]string{
+			"id": "db.1", sqlDatabaseAttr: "db", sqlCreateSQLFileAttr: path, sqlCreateSQLFileHashAttr: test.stateHash,
+		}}
+		if _, ok := test.config[sqlUpdateSQLAttr]; ok {
+			state.Attributes[sqlUpdateSQLAttr+".#"] = "1"
+			state.Attributes[sqlUpdateSQLAttr+".0"] = "SELECT 1"
+		}
+
+		diff, err := resourcePostgreSQLSQL().SimpleDiff(context.Background(), state, terraform.NewResourceConfigRaw(test.config), nil)
+		if err != nil {
+			t.Fatalf("could not compute the diff: %v", err)
+		}
+		if changed := diff != nil && diff.Attributes[sqlCreateSQLFileHashAttr] != nil; changed != test.changed {
+			t.Fatalf("expected the change of the file content to be %t with the hash %s in state, got %t", test.changed, test.stateHash, changed)
+		}
+		if changed := diff != nil && diff.RequiresNew(); changed != test.requiresNew {
+			t.Fatalf("expected the resource to be recreated: %t, got %t for %v", test.requiresNew, changed, test.config)
+		}
+	}
+}
+
+func TestAccPostgresqlSQL_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_sql" "test" {
+  database = "%s"
+
+  create_sql = [
+    "CREATE TABLE tf_test_sql (id serial PRIMARY KEY, value text)",
+    "INSERT INTO tf_test_sql (value) VALUES ('created')",
+    "CREATE INDEX tf_test_sql_value ON tf_test_sql (value)",
+  ]
+  update_sql = [
+    "INSERT INTO tf_test_sql (value) VALUES ('updated')",
+  ]
+  destroy_sql = [
+    "DROP INDEX tf_test_sql_value",
+    "DELETE FROM tf_test_sql",
+    "DROP TABLE tf_test_sql",
+  ]
+  exists_query = "SELECT to_regclass('tf_test_sql') IS NOT NULL"
+
+  triggers = {
+    version = "%s"
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSQLDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_sql.test", "database", dbName),
+					resource.TestCheckResourceAttr("postgresql_sql.test", "create_sql.#", "3"),
+					testAccCheckPostgresqlSQLRows(dbName, 1),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_sql.test", "triggers.version", "2"),
+					testAccCheckPostgresqlSQLRows(dbName, 2),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlSQL_StatementError(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSQLDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_sql" "test" {
+  database = "%s"
+
+  create_sql = [
+    "CREATE TABLE tf_test_sql (id int)",
+    "INSERT INTO tf_test_sql (unknown_column) VALUES (1)",
+  ]
+}
+`, dbName),
+				ExpectError: regexp.MustCompile(`could not execute create_sql\[1\] \(INSERT INTO tf_test_sql`),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlSQL_File(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	dir := t.TempDir()
+	upPath := filepath.Join(dir, "up.sql")
+	downPath := filepath.Join(dir, "down.sql")
+	writeFile := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(upPath, `
+CREATE TABLE tf_test_sql (id serial PRIMARY KEY, value text);
+INSERT INTO tf_test_sql (value) VALUES ('created; from a file');
+CREATE INDEX tf_test_sql_value ON tf_test_sql (value);
+`)
+	writeFile(downPath, `
+DROP INDEX tf_test_sql_value;
+DELETE FROM tf_test_sql;
+DROP TABLE tf_test_sql;
+`)
+
+	config := fmt.Sprintf(`
+resource "postgresql_sql" "test" {
+  database         = "%s"
+  create_sql_file  = "%s"
+  destroy_sql_file = "%s"
+  exists_query     = "SELECT to_regclass('tf_test_sql') IS NOT NULL"
+}
+`, dbName, upPath, downPath)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSQLDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("postgresql_sql.test", sqlCreateSQLFileHashAttr),
+					testAccCheckPostgresqlSQLRows(dbName, 1),
+				),
+			},
+			{
+				// Without update_sql, a change of the file recreates the resource: the table is dropped
+				// by the destroy statements and created again with the new rows.
+				PreConfig: func() {
+					writeFile(upPath, `
+CREATE TABLE tf_test_sql (id serial PRIMARY KEY, value text);
+INSERT INTO tf_test_sql (value) VALUES ('first'), ('second');
+CREATE INDEX tf_test_sql_value ON tf_test_sql (value);
+`)
+				},
+				Config: config,
+				Check:  testAccCheckPostgresqlSQLRows(dbName, 2),
+			},
+			{
+				// The failing statement of the file is identified, the previous ones being rolled back
+				PreConfig: func() {
+					writeFile(upPath, `
+CREATE TABLE tf_test_sql (id serial PRIMARY KEY, value text);
+INSERT INTO tf_test_sql (value) VALUES ('first');
+INSERT INTO tf_test_sql (unknown_column) VALUES (1);
+`)
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile(`could not execute statement 3 of create_sql_file \(line 4\)`),
+			},
+		},
+	})
+}
+
+// testAccCheckPostgresqlSQLDestroy checks the test table has been dropped, either by destroy_sql
+// or by the rollback of create_sql.
+func testAccCheckPostgresqlSQLDestroy(dbName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var exists bool
+		if err := txn.QueryRow("SELECT to_regclass('tf_test_sql') IS NOT NULL").Scan(&exists); err != nil {
+			return fmt.Errorf("Error checking table tf_test_sql: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("Table tf_test_sql still exists after destroy")
+		}
+		return nil
+	}
+}
+
+func testAccCheckPostgresqlSQLRows(dbName string, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var count int
+		if err := txn.QueryRow("SELECT count(*) FROM tf_test_sql").Scan(&count); err != nil {
+			return fmt.Errorf("Error reading table tf_test_sql: %w", err)
+		}
+
+		if count != expected {
+			return fmt.Errorf("Expected %d rows in tf_test_sql, got %d", expected, count)
+		}
+		return nil
+	}
+}
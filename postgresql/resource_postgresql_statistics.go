@@ -0,0 +1,412 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	statisticsNameAttr        = "name"
+	statisticsSchemaAttr      = "schema"
+	statisticsDatabaseAttr    = "database"
+	statisticsTableAttr       = "table"
+	statisticsTableSchemaAttr = "table_schema"
+	statisticsKindsAttr       = "kinds"
+	statisticsColumnsAttr     = "columns"
+	statisticsExpressionsAttr = "expressions"
+	statisticsTargetAttr      = "statistics_target"
+)
+
+// statisticsKinds maps the kinds of extended statistics to their code in pg_statistic_ext.stxkind
+var statisticsKinds = map[string]string{
+	"ndistinct":    "d",
+	"dependencies": "f",
+	"mcv":          "m",
+}
+
+func resourcePostgreSQLStatistics() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLStatisticsCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLStatisticsRead),
+		Update: PGResourceFunc(resourcePostgreSQLStatisticsUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLStatisticsDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourcePostgreSQLStatisticsCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			statisticsNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the statistics object",
+			},
+			statisticsSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the statistics object is created",
+			},
+			statisticsDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the statistics object is created",
+			},
+			statisticsTableAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The table on which the statistics are computed",
+			},
+			statisticsTableSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The schema of the table, defaults to the schema of the statistics object",
+			},
+			statisticsKindsAttr: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"ndistinct", "dependencies", "mcv"}, false),
+				},
+				Description: "The kinds of statistics to compute (ndistinct, dependencies, mcv), all the supported kinds if not set",
+			},
+			statisticsColumnsAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The columns covered by the statistics",
+			},
+			statisticsExpressionsAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+				},
+				Description: "The expressions covered by the statistics",
+			},
+			statisticsTargetAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      -1,
+				ValidateFunc: validation.IntBetween(-1, 10000),
+				Description:  "The statistics target of the statistics object, -1 to use the targets of the columns",
+			},
+		},
+	}
+}
+
+// resourcePostgreSQLStatisticsCustomizeDiff rejects the definitions Postgres would refuse:
+// extended statistics need at least two columns or expressions, except for a single expression.
+func resourcePostgreSQLStatisticsCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown(statisticsColumnsAttr) || !diff.NewValueKnown(statisticsExpressionsAttr) {
+		return nil
+	}
+
+	columns := len(diff.Get(statisticsColumnsAttr).([]interface{}))
+	expressions := len(diff.Get(statisticsExpressionsAttr).([]interface{}))
+
+	switch {
+	case columns == 1 && expressions == 0:
+		return fmt.Errorf(
+			"extended statistics require at least two columns or expressions, the statistics of a single column " +
+				"are already computed by ANALYZE (use ALTER TABLE ... ALTER COLUMN ... SET STATISTICS to change its statistics target)",
+		)
+	case columns == 0 && expressions == 0:
+		return fmt.Errorf("at least two columns or expressions must be set in columns or expressions")
+	case columns == 0 && expressions == 1:
+		// kinds is computed, so only the configuration tells if it has been set
+		if kinds := diff.GetRawConfig().GetAttr(statisticsKindsAttr); kinds.IsKnown() && !kinds.IsNull() && kinds.LengthInt() > 0 {
+			return fmt.Errorf("kinds cannot be set for statistics on a single expression")
+		}
+	}
+	return nil
+}
+
+func checkStatisticsFeatures(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureStatistics) {
+		return fmt.Errorf("postgresql_statistics resource is not supported %s", db.unsupportedServer())
+	}
+	if d.Get(statisticsKindsAttr).(*schema.Set).Contains("mcv") && !db.featureSupported(featureStatisticsMCV) {
+		return fmt.Errorf("mcv statistics are not supported %s", db.unsupportedServer())
+	}
+	if len(d.Get(statisticsExpressionsAttr).([]interface{})) > 0 && !db.featureSupported(featureStatisticsExpressions) {
+		return fmt.Errorf("statistics on expressions are not supported %s", db.unsupportedServer())
+	}
+	if d.Get(statisticsTargetAttr).(int) != -1 && !db.featureSupported(featureStatisticsTarget) {
+		return fmt.Errorf("statistics_target is not supported %s", db.unsupportedServer())
+	}
+	return nil
+}
+
+func createStatisticsQuery(d *schema.ResourceData) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "CREATE STATISTICS %s.%s",
+		pq.QuoteIdentifier(d.Get(statisticsSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(statisticsNameAttr).(string)),
+	)
+
+	if kinds := d.Get(statisticsKindsAttr).(*schema.Set); kinds.Len() > 0 {
+		// Sorted to generate a stable query
+		names := interfaceSliceToStrings(kinds.List())
+		sort.Strings(names)
+		fmt.Fprintf(b, " (%s)", strings.Join(names, ", "))
+	}
+
+	targets := []string{}
+	for _, column := range d.Get(statisticsColumnsAttr).([]interface{}) {
+		targets = append(targets, pq.QuoteIdentifier(column.(string)))
+	}
+	for _, expression := range d.Get(statisticsExpressionsAttr).([]interface{}) {
+		targets = append(targets, "("+expression.(string)+")")
+	}
+
+	tableSchema := d.Get(statisticsTableSchemaAttr).(string)
+	if tableSchema == "" {
+		tableSchema = d.Get(statisticsSchemaAttr).(string)
+	}
+
+	fmt.Fprintf(b, " ON %s FROM %s.%s",
+		strings.Join(targets, ", "),
+		pq.QuoteIdentifier(tableSchema),
+		pq.QuoteIdentifier(d.Get(statisticsTableAttr).(string)),
+	)
+
+	return b.String()
+}
+
+func resourcePostgreSQLStatisticsCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := checkStatisticsFeatures(db, d); err != nil {
+		return err
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(statisticsSchemaAttr).(string)
+	statisticsName := d.Get(statisticsNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(createStatisticsQuery(d)); err != nil {
+		return fmt.Errorf("could not create statistics %s: %w", statisticsName, err)
+	}
+
+	if target := d.Get(statisticsTargetAttr).(int); target != -1 {
+		if err := alterStatisticsTarget(txn, schemaName, statisticsName, target); err != nil {
+			return err
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating statistics: %w", err)
+	}
+
+	d.SetId(generateStatisticsID(database, schemaName, statisticsName))
+
+	return resourcePostgreSQLStatisticsReadImpl(db, d)
+}
+
+func resourcePostgreSQLStatisticsRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureStatistics) {
+		return fmt.Errorf("postgresql_statistics resource is not supported %s", db.unsupportedServer())
+	}
+
+	return resourcePostgreSQLStatisticsReadImpl(db, d)
+}
+
+func resourcePostgreSQLStatisticsReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, statisticsName, err := getDBStatisticsName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	expressions, target := "'{}'::text[]", "-1"
+	if db.featureSupported(featureStatisticsExpressions) {
+		expressions = "COALESCE(pg_catalog.pg_get_statisticsobjdef_expressions(s.oid), '{}'::text[])"
+	}
+	if db.featureSupported(featureStatisticsTarget) {
+		// stxstattarget is NULL for the default target since Postgres 17
+		target = "COALESCE(s.stxstattarget, -1)"
+	}
+
+	query := fmt.Sprintf(
+		`SELECT c.relname, cn.nspname, s.stxkind::text[], `+
+			`ARRAY(SELECT a.attname::text FROM pg_catalog.unnest(s.stxkeys::int2[]) WITH ORDINALITY k(attnum, ord) `+
+			`JOIN pg_catalog.pg_attribute a ON a.attrelid = s.stxrelid AND a.attnum = k.attnum ORDER BY k.ord), `+
+			`%s, %s `+
+			`FROM pg_catalog.pg_statistic_ext s `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = s.stxnamespace `+
+			`JOIN pg_catalog.pg_class c ON c.oid = s.stxrelid `+
+			`JOIN pg_catalog.pg_namespace cn ON cn.oid = c.relnamespace `+
+			`WHERE n.nspname = $1 AND s.stxname = $2`,
+		expressions, target,
+	)
+
+	var tableName, tableSchema string
+	var kindCodes, columns, statisticsExpressions []string
+	var statisticsTarget int
+	err = txn.QueryRow(query, schemaName, statisticsName).Scan(
+		&tableName, &tableSchema, pq.Array(&kindCodes), pq.Array(&columns), pq.Array(&statisticsExpressions), &statisticsTarget,
+	)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL statistics (%s.%s) not found in database %s", schemaName, statisticsName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading statistics: %w", err)
+	}
+
+	// Postgres rewrites the expressions (e.g.: adding parentheses and casts),
+	// so the configured ones are kept if Postgres stores them as the catalog ones.
+	from := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(tableSchema), pq.QuoteIdentifier(tableName))
+	keptExpressions, err := configuredSQLExpressions(txn, from, d.Get(statisticsExpressionsAttr).([]interface{}), statisticsExpressions)
+	if err != nil {
+		return fmt.Errorf("Error reading statistics: %w", err)
+	}
+
+	// The expressions kind ("e") is implied by the expressions and cannot be requested
+	kinds := []string{}
+	for name, code := range statisticsKinds {
+		if sliceContainsStr(kindCodes, code) {
+			kinds = append(kinds, name)
+		}
+	}
+
+	d.Set(statisticsNameAttr, statisticsName)
+	d.Set(statisticsSchemaAttr, schemaName)
+	d.Set(statisticsDatabaseAttr, database)
+	d.Set(statisticsTableAttr, tableName)
+	d.Set(statisticsTableSchemaAttr, tableSchema)
+	d.Set(statisticsKindsAttr, kinds)
+	// Postgres stores the columns in the order of the table
+	d.Set(statisticsColumnsAttr, listInStateOrder(d, statisticsColumnsAttr, columns))
+	d.Set(statisticsExpressionsAttr, keptExpressions)
+	d.Set(statisticsTargetAttr, statisticsTarget)
+	d.SetId(generateStatisticsID(database, schemaName, statisticsName))
+
+	return nil
+}
+
+func resourcePostgreSQLStatisticsUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(statisticsTargetAttr) {
+		return resourcePostgreSQLStatisticsReadImpl(db, d)
+	}
+
+	if !db.featureSupported(featureStatisticsTarget) {
+		return fmt.Errorf("statistics_target is not supported %s", db.unsupportedServer())
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := alterStatisticsTarget(
+		txn, d.Get(statisticsSchemaAttr).(string), d.Get(statisticsNameAttr).(string), d.Get(statisticsTargetAttr).(int),
+	); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating statistics: %w", err)
+	}
+
+	return resourcePostgreSQLStatisticsReadImpl(db, d)
+}
+
+func alterStatisticsTarget(txn *sql.Tx, schemaName, statisticsName string, target int) error {
+	sql := fmt.Sprintf("ALTER STATISTICS %s.%s SET STATISTICS %d",
+		pq.QuoteIdentifier(schemaName),
+		pq.QuoteIdentifier(statisticsName),
+		target,
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating statistics target: %w", err)
+	}
+	return nil
+}
+
+func resourcePostgreSQLStatisticsDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(statisticsSchemaAttr).(string)
+	statisticsName := d.Get(statisticsNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	sql := fmt.Sprintf("DROP STATISTICS %s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(statisticsName))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not drop statistics: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting statistics: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func generateStatisticsID(database, schemaName, statisticsName string) string {
+	return joinResourceID(database, schemaName, statisticsName)
+}
+
+// getDBStatisticsName returns database, schema and statistics name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBStatisticsName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(statisticsSchemaAttr).(string)
+	statisticsName := d.Get(statisticsNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and statistics names.
+	if statisticsName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("statistics ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		statisticsName = parsed[2]
+	}
+	return database, schemaName, statisticsName, nil
+}
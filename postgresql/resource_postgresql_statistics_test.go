@@ -0,0 +1,265 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCreateStatisticsQuery(t *testing.T) {
+	var cases = []struct {
+		resource *schema.ResourceData
+		expected string
+	}{
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLStatistics().Schema, map[string]interface{}{
+				"name":    "zip_city",
+				"table":   "addresses",
+				"columns": []interface{}{"zip", "city"},
+			}),
+			expected: `CREATE STATISTICS "public"."zip_city" ON "zip", "city" FROM "public"."addresses"`,
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLStatistics().Schema, map[string]interface{}{
+				"name":         "zip_city",
+				"schema":       "stats",
+				"table":        "addresses",
+				"table_schema": "app",
+				"kinds":        []interface{}{"mcv", "dependencies"},
+				"columns":      []interface{}{"zip"},
+				"expressions":  []interface{}{"lower(city)"},
+			}),
+			expected: `CREATE STATISTICS "stats"."zip_city" (dependencies, mcv) ON "zip", (lower(city)) FROM "app"."addresses"`,
+		},
+	}
+
+	for _, c := range cases {
+		if out := createStatisticsQuery(c.resource); out != c.expected {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.expected)
+		}
+	}
+}
+
+func TestAccPostgresqlStatistics_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.addresses (zip text, city text, country text)")
+
+	config := `
+resource "postgresql_statistics" "zip_city" {
+  database          = "%s"
+  schema            = "test_schema"
+  name              = "zip_city"
+  table             = "addresses"
+  kinds             = ["ndistinct", "dependencies"]
+  columns           = ["zip", "city"]
+  statistics_target = %d
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureStatisticsTarget)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlStatisticsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, -1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlStatisticsExists("postgresql_statistics.zip_city"),
+					resource.TestCheckResourceAttr("postgresql_statistics.zip_city", "table_schema", "test_schema"),
+					resource.TestCheckResourceAttr("postgresql_statistics.zip_city", "kinds.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_statistics.zip_city", "columns.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_statistics.zip_city", "columns.0", "zip"),
+					resource.TestCheckResourceAttr("postgresql_statistics.zip_city", "columns.1", "city"),
+					resource.TestCheckResourceAttr("postgresql_statistics.zip_city", "statistics_target", "-1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, 500),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlStatisticsExists("postgresql_statistics.zip_city"),
+					resource.TestCheckResourceAttr("postgresql_statistics.zip_city", "statistics_target", "500"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_statistics.zip_city",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.test_schema.zip_city", dbName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlStatistics_Expressions(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "CREATE TABLE test_schema.addresses (zip text, city text, country text)")
+
+	config := fmt.Sprintf(`
+resource "postgresql_statistics" "city_country" {
+  database     = "%[1]s"
+  schema       = "dev_schema"
+  name         = "city_country"
+  table        = "addresses"
+  table_schema = "test_schema"
+  columns      = ["country"]
+  expressions  = ["LOWER(city)"]
+}
+
+resource "postgresql_statistics" "lower_zip" {
+  database     = "%[1]s"
+  schema       = "test_schema"
+  name         = "lower_zip"
+  table        = "addresses"
+  expressions  = ["lower(zip)"]
+}
+
+resource "postgresql_statistics" "zip_prefix" {
+  database     = "%[1]s"
+  schema       = "test_schema"
+  name         = "zip_prefix"
+  table        = "addresses"
+  expressions  = ["(substr(zip, 1, 2) || 'x')"]
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureStatisticsExpressions)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlStatisticsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlStatisticsExists("postgresql_statistics.city_country"),
+					resource.TestCheckResourceAttr("postgresql_statistics.city_country", "kinds.#", "3"),
+					resource.TestCheckResourceAttr("postgresql_statistics.city_country", "expressions.#", "1"),
+					testAccCheckPostgresqlStatisticsExists("postgresql_statistics.lower_zip"),
+					resource.TestCheckResourceAttr("postgresql_statistics.lower_zip", "kinds.#", "0"),
+					// Postgres stores (substr(zip, 1, 2) || 'x'::text), the configured expression is kept
+					testAccCheckPostgresqlStatisticsExists("postgresql_statistics.zip_prefix"),
+					resource.TestCheckResourceAttr("postgresql_statistics.zip_prefix", "expressions.0", "(substr(zip, 1, 2) || 'x')"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_statistics.city_country",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.dev_schema.city_country", dbName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlStatistics_SingleColumn(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := `
+resource "postgresql_statistics" "zip" {
+  name    = "zip"
+  table   = "addresses"
+  columns = ["zip"]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("extended statistics require at least two columns or expressions"),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlStatisticsDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_statistics" {
+			continue
+		}
+
+		exists, err := checkStatisticsExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking statistics %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Statistics still exist after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlStatisticsExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		exists, err := checkStatisticsExists(rs)
+		if err != nil {
+			return fmt.Errorf("Error checking statistics %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Statistics not found")
+		}
+
+		return nil
+	}
+}
+
+func checkStatisticsExists(rs *terraform.ResourceState) (bool, error) {
+	client := testAccProvider.Meta().(*Client)
+	txn, err := startTransaction(client, rs.Primary.Attributes[statisticsDatabaseAttr])
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	var _rez bool
+	err = txn.QueryRow(
+		"SELECT TRUE FROM pg_catalog.pg_statistic_ext s JOIN pg_catalog.pg_namespace n ON n.oid = s.stxnamespace "+
+			"WHERE n.nspname = $1 AND s.stxname = $2",
+		rs.Primary.Attributes[statisticsSchemaAttr], rs.Primary.Attributes[statisticsNameAttr],
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
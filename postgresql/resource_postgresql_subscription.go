@@ -4,7 +4,11 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net"
+	"net/url"
+	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -12,12 +16,15 @@ import (
 )
 
 func resourcePostgreSQLSubscription() *schema.Resource {
-	return &schema.Resource{
-		Create:   PGResourceFunc(resourcePostgreSQLSubscriptionCreate),
-		Read:     PGResourceFunc(resourcePostgreSQLSubscriptionRead),
-		Delete:   PGResourceFunc(resourcePostgreSQLSubscriptionDelete),
-		Exists:   PGResourceExistsFunc(resourcePostgreSQLSubscriptionExists),
-		Importer: &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext},
+	resource := &schema.Resource{
+		// Version 1 escapes the dots of the names in the ID
+		SchemaVersion: 1,
+		Create:        PGResourceFunc(resourcePostgreSQLSubscriptionCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLSubscriptionRead),
+		Update:        PGResourceFunc(resourcePostgreSQLSubscriptionUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLSubscriptionDelete),
+		Exists:        PGResourceExistsFunc(resourcePostgreSQLSubscriptionExists),
+		Importer:      &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -25,7 +32,7 @@ func resourcePostgreSQLSubscription() *schema.Resource {
 				Required:     true,
 				ForceNew:     true,
 				Description:  "The name of the subscription",
-				ValidateFunc: validation.StringIsNotEmpty,
+				ValidateFunc: validateIdentifier,
 			},
 			"database": {
 				Type:        schema.TypeString,
@@ -37,7 +44,6 @@ func resourcePostgreSQLSubscription() *schema.Resource {
 			"conninfo": {
 				Type:         schema.TypeString,
 				Required:     true,
-				ForceNew:     true,
 				Sensitive:    true,
 				Description:  "The connection string to the publisher. It should follow the keyword/value format (https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING)",
 				ValidateFunc: validation.StringIsNotEmpty,
@@ -45,10 +51,60 @@ func resourcePostgreSQLSubscription() *schema.Resource {
 			"publications": {
 				Type:        schema.TypeSet,
 				Required:    true,
-				ForceNew:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "Names of the publications on the publisher to subscribe to",
 			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether the subscription should be actively replicating",
+			},
+			"refresh_publication_on_change": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether the tables of the publications should be refreshed when the publications list changes",
+			},
+			"copy_data": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether the existing data in the publications should be copied when the subscription is created or its publications are refreshed",
+			},
+			"streaming": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "off",
+				ValidateFunc: validation.StringInSlice([]string{"on", "off", "parallel"}, false),
+				Description:  "Specifies whether to enable streaming of in-progress transactions for this subscription (one of: on, off, parallel)",
+			},
+			"two_phase": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Specifies whether two-phase commit is enabled for this subscription",
+			},
+			"origin": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "any",
+				ValidateFunc: validation.StringInSlice([]string{"any", "none"}, false),
+				Description:  "Specifies whether the subscription requests the publisher to only send changes that don't have an origin (one of: any, none)",
+			},
+			"disable_on_destroy_before_drop": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Disable the subscription before dropping it",
+			},
+			"detach_slot_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Dissociate the replication slot from the subscription before dropping it, so the subscription can be dropped when the publisher is unreachable. The slot has to be dropped manually on the publisher",
+			},
 			"create_slot": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -65,9 +121,23 @@ func resourcePostgreSQLSubscription() *schema.Resource {
 			},
 		},
 	}
+	resource.StateUpgraders = []schema.StateUpgrader{
+		resourceIDStateUpgrader(resource, 0, func(rawState map[string]interface{}) string {
+			return databaseObjectIDFromRawState(rawState, "database", "name")
+		}),
+	}
+
+	return resource
 }
 
 func resourcePostgreSQLSubscriptionCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureSubscription) {
+		return fmt.Errorf(
+			"postgresql_subscription resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
 	subName := d.Get("name").(string)
 	databaseName := getDatabaseForSubscription(d, db.client.databaseName)
 
@@ -80,13 +150,16 @@ func resourcePostgreSQLSubscriptionCreate(db *DBConnection, d *schema.ResourceDa
 		return fmt.Errorf("could not get conninfo: %w", err)
 	}
 
+	if err := checkSubscriptionOptionsSupport(db, d); err != nil {
+		return err
+	}
+
 	optionalParams := getOptionalParameters(d)
 
 	// Creating of a subscription can not be done in an transaction
-	client := db.client.config.NewClient(databaseName)
-	conn, err := client.Connect()
+	conn, err := connectDatabase(db.client, databaseName)
 	if err != nil {
-		return fmt.Errorf("could not establish database connection: %w", err)
+		return err
 	}
 
 	sql := fmt.Sprintf("CREATE SUBSCRIPTION %s CONNECTION %s PUBLICATION %s %s;",
@@ -109,6 +182,13 @@ func resourcePostgreSQLSubscriptionRead(db *DBConnection, d *schema.ResourceData
 }
 
 func resourcePostgreSQLSubscriptionReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureSubscription) {
+		return fmt.Errorf(
+			"postgresql_subscription resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
 	databaseName, subName, err := getDBSubscriptionName(d, db.client)
 	if err != nil {
 		return fmt.Errorf("could not get subscription name: %w", err)
@@ -120,10 +200,6 @@ func resourcePostgreSQLSubscriptionReadImpl(db *DBConnection, d *schema.Resource
 	}
 	defer deferredRollback(txn)
 
-	var publications []string
-	var connInfo string
-	var slotName string
-
 	var subExists bool
 	queryExists := "SELECT TRUE FROM pg_catalog.pg_stat_subscription WHERE subname = $1"
 	err = txn.QueryRow(queryExists, pqQuoteLiteral(subName)).Scan(&subExists)
@@ -137,9 +213,34 @@ func resourcePostgreSQLSubscriptionReadImpl(db *DBConnection, d *schema.Resource
 		return nil
 	}
 
+	var enabled bool
+	var publications []string
+	var slotName sql.NullString
+	var streaming, origin string
+	var twoPhase bool
+
+	columns := []string{"subenabled", "subpublications", "subslotname"}
+	values := []interface{}{&enabled, pq.Array(&publications), &slotName}
+	if db.featureSupported(featureSubscriptionStreaming) {
+		// substream is a boolean before PostgreSQL 16
+		columns = append(columns, "substream::text")
+		values = append(values, &streaming)
+	}
+	if db.featureSupported(featureSubscriptionTwoPhase) {
+		columns = append(columns, "subtwophasestate <> 'd'")
+		values = append(values, &twoPhase)
+	}
+	if db.featureSupported(featureSubscriptionOrigin) {
+		columns = append(columns, "suborigin")
+		values = append(values, &origin)
+	}
+
 	// pg_subscription requires superuser permissions, it is okay to fail here
-	query := "SELECT subconninfo, subpublications, subslotname FROM pg_catalog.pg_subscription WHERE subname = $1"
-	err = txn.QueryRow(query, pqQuoteLiteral(subName)).Scan(&connInfo, pq.Array(&publications), &slotName)
+	query := fmt.Sprintf(
+		"SELECT %s FROM pg_catalog.pg_subscription WHERE subname = $1",
+		strings.Join(columns, ", "),
+	)
+	err = txn.QueryRow(query, pqQuoteLiteral(subName)).Scan(values...)
 
 	if err != nil {
 		// we already checked that the subscription exists
@@ -156,8 +257,23 @@ func resourcePostgreSQLSubscriptionReadImpl(db *DBConnection, d *schema.Resource
 		publications := setPublications.(*schema.Set).List()
 		d.Set("publications", publications)
 	} else {
-		d.Set("conninfo", connInfo)
 		d.Set("publications", publications)
+		d.Set("enabled", enabled)
+		if streaming != "" {
+			d.Set("streaming", subscriptionStreamingValues[streaming])
+		}
+		if db.featureSupported(featureSubscriptionTwoPhase) {
+			d.Set("two_phase", twoPhase)
+		}
+		if origin != "" {
+			d.Set("origin", origin)
+		}
+
+		// subconninfo is not readable by non-superusers even if the rest of pg_subscription is,
+		// it is queried last as the transaction is aborted if it fails.
+		if err := readSubscriptionConnInfo(txn, d, subName); err != nil {
+			return err
+		}
 	}
 	d.Set("name", subName)
 	d.Set("database", databaseName)
@@ -168,33 +284,182 @@ func resourcePostgreSQLSubscriptionReadImpl(db *DBConnection, d *schema.Resource
 		d.Set("create_slot", createSlot.(bool))
 	}
 	_, okSlotName := d.GetOk("slot_name")
-	if okSlotName {
-		d.Set("slot_name", slotName)
+	if okSlotName && slotName.Valid {
+		d.Set("slot_name", slotName.String)
+	}
+
+	// These attributes only drive the provider behavior and can't be read back,
+	// set their defaults if they are not known yet (e.g.: on import).
+	for attr, defaultValue := range map[string]bool{
+		"refresh_publication_on_change":  true,
+		"copy_data":                      true,
+		"disable_on_destroy_before_drop": false,
+		"detach_slot_on_destroy":         false,
+	} {
+		if _, ok := d.GetOkExists(attr); !ok { //nolint:staticcheck
+			d.Set(attr, defaultValue)
+		}
+	}
+
+	return nil
+}
+
+// subscriptionStreamingValues maps the values of pg_subscription.substream to the streaming attribute.
+var subscriptionStreamingValues = map[string]string{
+	"true":  "on",
+	"false": "off",
+	"t":     "on",
+	"f":     "off",
+	"p":     "parallel",
+}
+
+// readSubscriptionConnInfo sets the conninfo attribute from pg_subscription.
+// The connection string stored in the state is kept as long as its non-secret parts
+// are the same as the catalog ones, so the password is never read back from the database.
+func readSubscriptionConnInfo(txn *sql.Tx, d *schema.ResourceData, subName string) error {
+	var catalogConnInfo string
+	err := txn.QueryRow(
+		"SELECT subconninfo FROM pg_catalog.pg_subscription WHERE subname = $1", pqQuoteLiteral(subName),
+	).Scan(&catalogConnInfo)
+	if err != nil {
+		log.Printf("[DEBUG] could not read conninfo of subscription %s, keeping the one from the state: %v", subName, err)
+		return nil
 	}
 
+	catalogPublic, err := connInfoWithoutSecrets(catalogConnInfo)
+	if err != nil {
+		return fmt.Errorf("could not parse conninfo of subscription %s: %w", subName, err)
+	}
+
+	connInfo := d.Get("conninfo").(string)
+	if connInfo != "" {
+		statePublic, err := connInfoWithoutSecrets(connInfo)
+		if err == nil && statePublic == catalogPublic {
+			return nil
+		}
+	}
+
+	d.Set("conninfo", catalogPublic)
 	return nil
 }
 
+func resourcePostgreSQLSubscriptionUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureSubscription) {
+		return fmt.Errorf(
+			"postgresql_subscription resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	subName := d.Get("name").(string)
+	databaseName := getDatabaseForSubscription(d, db.client.databaseName)
+
+	if err := checkSubscriptionOptionsSupport(db, d); err != nil {
+		return err
+	}
+
+	// Refreshing the publications of a subscription can not be done in a transaction
+	conn, err := connectDatabase(db.client, databaseName)
+	if err != nil {
+		return err
+	}
+
+	var statements []string
+
+	if d.HasChange("conninfo") {
+		connInfo, err := getConnInfoForSubscription(d)
+		if err != nil {
+			return fmt.Errorf("could not get conninfo: %w", err)
+		}
+		statements = append(statements, fmt.Sprintf(
+			"ALTER SUBSCRIPTION %s CONNECTION %s", pq.QuoteIdentifier(subName), pq.QuoteLiteral(connInfo),
+		))
+	}
+
+	enabled := d.Get("enabled").(bool)
+	if d.HasChange("enabled") {
+		action := "DISABLE"
+		if enabled {
+			action = "ENABLE"
+		}
+		statements = append(statements, fmt.Sprintf("ALTER SUBSCRIPTION %s %s", pq.QuoteIdentifier(subName), action))
+	}
+
+	if d.HasChange("publications") {
+		publications, err := getPublicationsForSubscription(d)
+		if err != nil {
+			return fmt.Errorf("could not get publications: %w", err)
+		}
+
+		refresh := d.Get("refresh_publication_on_change").(bool)
+		if refresh && !enabled {
+			log.Printf("[WARN] PostgreSQL Subscription (%s) is disabled, publications will not be refreshed", subName)
+			refresh = false
+		}
+
+		options := "refresh = false"
+		if refresh {
+			options = fmt.Sprintf("refresh = true, copy_data = %t", d.Get("copy_data").(bool))
+		}
+		statements = append(statements, fmt.Sprintf(
+			"ALTER SUBSCRIPTION %s SET PUBLICATION %s WITH (%s)", pq.QuoteIdentifier(subName), publications, options,
+		))
+	}
+
+	var params []string
+	if d.HasChange("streaming") {
+		params = append(params, fmt.Sprintf("streaming = %s", d.Get("streaming").(string)))
+	}
+	if d.HasChange("origin") {
+		params = append(params, fmt.Sprintf("origin = %s", pq.QuoteLiteral(d.Get("origin").(string))))
+	}
+	if len(params) > 0 {
+		statements = append(statements, fmt.Sprintf(
+			"ALTER SUBSCRIPTION %s SET (%s)", pq.QuoteIdentifier(subName), strings.Join(params, ", "),
+		))
+	}
+
+	for _, statement := range statements {
+		if _, err := conn.Exec(statement); err != nil {
+			return fmt.Errorf("could not execute sql: %w", err)
+		}
+	}
+
+	return resourcePostgreSQLSubscriptionReadImpl(db, d)
+}
+
 func resourcePostgreSQLSubscriptionDelete(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureSubscription) {
+		return fmt.Errorf(
+			"postgresql_subscription resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
 	subName := d.Get("name").(string)
 	createSlot := d.Get("create_slot").(bool)
+	// without slot_name = NONE, DROP SUBSCRIPTION drops the slot on the publisher
+	detachSlot := !createSlot || d.Get("detach_slot_on_destroy").(bool)
+	disable := detachSlot || d.Get("disable_on_destroy_before_drop").(bool)
 
 	databaseName := getDatabaseForSubscription(d, db.client.databaseName)
 
 	// Dropping a subscription can not be done in a transaction
-	client := db.client.config.NewClient(databaseName)
-	conn, err := client.Connect()
+	conn, err := connectDatabase(db.client, databaseName)
 	if err != nil {
-		return fmt.Errorf("could not establish database connection: %w", err)
+		return err
 	}
 
-	// disable subscription and unset the slot before dropping in order to keep the replication slot
-	if !createSlot {
+	if disable {
 		sql := fmt.Sprintf("ALTER SUBSCRIPTION %s DISABLE", pq.QuoteIdentifier(subName))
 		if _, err := conn.Exec(sql); err != nil {
 			return fmt.Errorf("could not execute sql: %w", err)
 		}
-		sql = fmt.Sprintf("ALTER SUBSCRIPTION %s SET (slot_name = NONE)", pq.QuoteIdentifier(subName))
+	}
+
+	// unset the slot before dropping in order to keep the replication slot
+	if detachSlot {
+		sql := fmt.Sprintf("ALTER SUBSCRIPTION %s SET (slot_name = NONE)", pq.QuoteIdentifier(subName))
 		if _, err := conn.Exec(sql); err != nil {
 			return fmt.Errorf("could not execute sql: %w", err)
 		}
@@ -212,6 +477,13 @@ func resourcePostgreSQLSubscriptionDelete(db *DBConnection, d *schema.ResourceDa
 }
 
 func resourcePostgreSQLSubscriptionExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	if !db.featureSupported(featureSubscription) {
+		return false, fmt.Errorf(
+			"postgresql_subscription resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
 	var subName string
 
 	database, subName, err := getDBSubscriptionName(d, db.client)
@@ -274,7 +546,7 @@ func getConnInfoForSubscription(d *schema.ResourceData) (string, error) {
 }
 
 func generateSubscriptionID(d *schema.ResourceData, databaseName string) string {
-	return strings.Join([]string{databaseName, d.Get("name").(string)}, ".")
+	return joinResourceID(databaseName, d.Get("name").(string))
 }
 
 func getDatabaseForSubscription(d *schema.ResourceData, databaseName string) string {
@@ -294,7 +566,7 @@ func getDBSubscriptionName(d *schema.ResourceData, client *Client) (string, stri
 
 	// When importing, we have to parse the ID to find subscription and database names.
 	if subName == "" {
-		parsed := strings.Split(d.Id(), ".")
+		parsed := splitResourceID(d.Id())
 		if len(parsed) != 2 {
 			return "", "", fmt.Errorf("Subscription ID %s has not the expected format 'database.subscriptionName': %v", d.Id(), parsed)
 		}
@@ -305,19 +577,12 @@ func getDBSubscriptionName(d *schema.ResourceData, client *Client) (string, stri
 	return database, subName, nil
 }
 
-// slotName and createSlot require recreation of the subscription, only return WITH ...
+// getOptionalParameters returns the WITH clause of CREATE SUBSCRIPTION,
+// options left to their default values are not sent to the database.
 func getOptionalParameters(d *schema.ResourceData) string {
-	parameterSQLTemplate := "WITH (%s)"
-	returnValue := ""
-
 	createSlot, okCreate := d.GetOkExists("create_slot") //nolint:staticcheck
 	slotName, okName := d.GetOk("slot_name")
 
-	if !okCreate && !okName {
-		// use default behavior, no WITH statement
-		return ""
-	}
-
 	var params []string
 	if okCreate {
 		params = append(params, fmt.Sprintf("%s = %t", "create_slot", createSlot.(bool)))
@@ -325,12 +590,173 @@ func getOptionalParameters(d *schema.ResourceData) string {
 	if okName {
 		params = append(params, fmt.Sprintf("%s = %s", "slot_name", pq.QuoteLiteral(slotName.(string))))
 	}
+	if !d.Get("enabled").(bool) {
+		params = append(params, "enabled = false")
+	}
+	if !d.Get("copy_data").(bool) {
+		params = append(params, "copy_data = false")
+	}
+	if streaming := d.Get("streaming").(string); streaming != "off" {
+		params = append(params, fmt.Sprintf("%s = %s", "streaming", streaming))
+	}
+	if d.Get("two_phase").(bool) {
+		params = append(params, "two_phase = true")
+	}
+	if origin := d.Get("origin").(string); origin != "any" {
+		params = append(params, fmt.Sprintf("%s = %s", "origin", pq.QuoteLiteral(origin)))
+	}
+
+	if len(params) == 0 {
+		// use default behavior, no WITH statement
+		return ""
+	}
+
+	return fmt.Sprintf("WITH (%s)", strings.Join(params, ", "))
+}
+
+func checkSubscriptionOptionsSupport(db *DBConnection, d *schema.ResourceData) error {
+	streaming := d.Get("streaming").(string)
+	if streaming != "off" && !db.featureSupported(featureSubscriptionStreaming) {
+		return fmt.Errorf("subscription streaming is not supported %s", db.unsupportedServer())
+	}
+	if streaming == "parallel" && !db.featureSupported(featureSubscriptionParallelStreaming) {
+		return fmt.Errorf("subscription parallel streaming is not supported %s", db.unsupportedServer())
+	}
+	if d.Get("two_phase").(bool) && !db.featureSupported(featureSubscriptionTwoPhase) {
+		return fmt.Errorf("subscription two_phase is not supported %s", db.unsupportedServer())
+	}
+	if d.Get("origin").(string) != "any" && !db.featureSupported(featureSubscriptionOrigin) {
+		return fmt.Errorf("subscription origin is not supported %s", db.unsupportedServer())
+	}
+	return nil
+}
+
+// parseConnInfo parses a libpq connection string, either in the keyword/value or in the URI format.
+func parseConnInfo(connInfo string) (map[string]string, error) {
+	if strings.HasPrefix(connInfo, "postgresql://") || strings.HasPrefix(connInfo, "postgres://") {
+		return parseConnInfoURI(connInfo)
+	}
+
+	params := make(map[string]string)
+	runes := []rune(connInfo)
+	i := 0
+	skipSpaces := func() {
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+	}
+
+	for {
+		skipSpaces()
+		if i >= len(runes) {
+			return params, nil
+		}
+
+		start := i
+		for i < len(runes) && runes[i] != '=' && !unicode.IsSpace(runes[i]) {
+			i++
+		}
+		key := string(runes[start:i])
+
+		skipSpaces()
+		if i >= len(runes) || runes[i] != '=' {
+			return nil, fmt.Errorf("missing \"=\" after %q in connection info string", key)
+		}
+		i++
+		skipSpaces()
+
+		var value strings.Builder
+		if i < len(runes) && runes[i] == '\'' {
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				} else if runes[i] == '\'' {
+					closed = true
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quoted string in connection info string")
+			}
+		} else {
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+		}
+		params[key] = value.String()
+	}
+}
+
+func parseConnInfoURI(connInfo string) (map[string]string, error) {
+	u, err := url.Parse(connInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]string)
+	if u.User != nil {
+		params["user"] = u.User.Username()
+		if password, ok := u.User.Password(); ok {
+			params["password"] = password
+		}
+	}
+	if u.Host != "" {
+		if host, port, err := net.SplitHostPort(u.Host); err == nil && !strings.Contains(u.Host, ",") {
+			params["host"] = host
+			params["port"] = port
+		} else {
+			params["host"] = u.Host
+		}
+	}
+	if dbName := strings.TrimPrefix(u.Path, "/"); dbName != "" {
+		params["dbname"] = dbName
+	}
+	for key, values := range u.Query() {
+		if len(values) > 0 {
+			params[key] = values[len(values)-1]
+		}
+	}
+
+	return params, nil
+}
+
+// connInfoWithoutSecrets returns a normalized keyword/value connection string without the password,
+// it allows to compare connection strings without reading back the password from the database.
+func connInfoWithoutSecrets(connInfo string) (string, error) {
+	params, err := parseConnInfo(connInfo)
+	if err != nil {
+		return "", err
+	}
+	delete(params, "password")
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := params[key]
+		if value == "" || strings.ContainsAny(value, " \t\n'\\") {
+			value = "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(value) + "'"
+		}
+		parts = append(parts, key+"="+value)
+	}
 
-	returnValue = fmt.Sprintf(parameterSQLTemplate, strings.Join(params, ", "))
-	return returnValue
+	return strings.Join(parts, " "), nil
 }
 
 func getSubscriptionNameFromID(ID string) string {
-	splitted := strings.Split(ID, ".")
+	splitted := splitResourceID(ID)
 	return splitted[0]
 }
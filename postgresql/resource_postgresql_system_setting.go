@@ -0,0 +1,186 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	systemSettingNameAttr           = "name"
+	systemSettingValueAttr          = "value"
+	systemSettingCurrentValueAttr   = "current_value"
+	systemSettingSourceAttr         = "source"
+	systemSettingPendingRestartAttr = "pending_restart"
+)
+
+func resourcePostgreSQLSystemSetting() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLSystemSettingCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLSystemSettingRead),
+		Update: PGResourceFunc(resourcePostgreSQLSystemSettingUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLSystemSettingDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			systemSettingNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(settingNameRegexp, "must be the name of a run-time parameter"),
+				Description:  "The name of the run-time parameter",
+			},
+			systemSettingValueAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The value of the parameter written in postgresql.auto.conf",
+			},
+			systemSettingCurrentValueAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The value currently used by the server, with its unit",
+			},
+			systemSettingSourceAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The source of the current value (e.g.: configuration file)",
+			},
+			systemSettingPendingRestartAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the value has been changed in the configuration files but the server must be restarted to use it",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLSystemSettingCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureAlterSystem) {
+		return fmt.Errorf(
+			"postgresql_system_setting resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	name := d.Get(systemSettingNameAttr).(string)
+	if err := alterSystemSetting(db, name, d.Get(systemSettingValueAttr).(string)); err != nil {
+		return err
+	}
+
+	d.SetId(name)
+
+	return resourcePostgreSQLSystemSettingReadImpl(db, d)
+}
+
+func resourcePostgreSQLSystemSettingRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureAlterSystem) {
+		return fmt.Errorf(
+			"postgresql_system_setting resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	return resourcePostgreSQLSystemSettingReadImpl(db, d)
+}
+
+func resourcePostgreSQLSystemSettingReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	name := d.Id()
+
+	// The value set by ALTER SYSTEM is the last one of postgresql.auto.conf, pg_settings only has
+	// the value currently used which differs until the server is restarted for some parameters.
+	var value, currentValue, source string
+	var pendingRestart bool
+	err := db.QueryRow(
+		`SELECT f.setting, COALESCE(s.setting || COALESCE(s.unit, ''), ''), COALESCE(s.source, ''), COALESCE(s.pending_restart, false) `+
+			`FROM (SELECT setting FROM pg_catalog.pg_file_settings `+
+			`WHERE lower(name) = lower($1) AND sourcefile LIKE '%/postgresql.auto.conf' ORDER BY seqno DESC LIMIT 1) f `+
+			`LEFT JOIN pg_catalog.pg_settings s ON lower(s.name) = lower($1)`,
+		name,
+	).Scan(&value, &currentValue, &source, &pendingRestart)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL system setting %s not found in postgresql.auto.conf", name)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading system setting %s: %w", name, err)
+	}
+
+	if pendingRestart {
+		log.Printf("[WARN] PostgreSQL server must be restarted to use the new value of %s", name)
+	}
+
+	d.Set(systemSettingNameAttr, name)
+	d.Set(systemSettingValueAttr, value)
+	d.Set(systemSettingCurrentValueAttr, currentValue)
+	d.Set(systemSettingSourceAttr, source)
+	d.Set(systemSettingPendingRestartAttr, pendingRestart)
+
+	return nil
+}
+
+func resourcePostgreSQLSystemSettingUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if d.HasChange(systemSettingValueAttr) {
+		if err := alterSystemSetting(db, d.Get(systemSettingNameAttr).(string), d.Get(systemSettingValueAttr).(string)); err != nil {
+			return err
+		}
+	}
+
+	return resourcePostgreSQLSystemSettingReadImpl(db, d)
+}
+
+func resourcePostgreSQLSystemSettingDelete(db *DBConnection, d *schema.ResourceData) error {
+	name := d.Get(systemSettingNameAttr).(string)
+
+	if err := execAlterSystem(db, fmt.Sprintf("ALTER SYSTEM RESET %s", name)); err != nil {
+		return fmt.Errorf("could not reset system setting %s: %w", name, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func alterSystemSetting(db *DBConnection, name, value string) error {
+	if err := execAlterSystem(db, fmt.Sprintf("ALTER SYSTEM SET %s = %s", name, pq.QuoteLiteral(value))); err != nil {
+		return fmt.Errorf("could not set system setting %s: %w", name, err)
+	}
+	return nil
+}
+
+// execAlterSystem runs an ALTER SYSTEM statement, which cannot be executed in a transaction,
+// then reloads the configuration so the new value is used by the server.
+func execAlterSystem(db *DBConnection, query string) error {
+	if _, err := db.Exec(query); err != nil {
+		return alterSystemError(err)
+	}
+	if _, err := db.Exec("SELECT pg_catalog.pg_reload_conf()"); err != nil {
+		return fmt.Errorf("could not reload the configuration: %w", err)
+	}
+	return nil
+}
+
+// alterSystemError explains the errors returned when ALTER SYSTEM is forbidden, which is the case
+// on most managed services (e.g.: AWS RDS, Google Cloud SQL) where the parameters are set with their API.
+func alterSystemError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	// insufficient_privilege, or allow_alter_system disabled (Postgres 17+)
+	if pqErr.Code == "42501" || strings.Contains(pqErr.Message, "ALTER SYSTEM is not allowed") {
+		return fmt.Errorf(
+			"ALTER SYSTEM is not allowed for this role or on this server (managed services usually forbid it, "+
+				"their parameters must be set with their own API, e.g. RDS parameter groups): %w", err,
+		)
+	}
+	return err
+}
@@ -0,0 +1,129 @@
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
+)
+
+func TestAlterSystemError(t *testing.T) {
+	var tests = []struct {
+		err       error
+		forbidden bool
+	}{
+		{&pq.Error{Code: "42501", Message: "must be superuser to execute ALTER SYSTEM command"}, true},
+		{&pq.Error{Code: "55000", Message: "ALTER SYSTEM is not allowed in this environment"}, true},
+		{&pq.Error{Code: "42704", Message: `unrecognized configuration parameter "foo"`}, false},
+		{errors.New("connection refused"), false},
+	}
+
+	for _, test := range tests {
+		err := alterSystemError(test.err)
+		if !errors.Is(err, test.err) {
+			t.Errorf("alterSystemError(%v) should wrap the original error, got %v", test.err, err)
+		}
+		if forbidden := strings.Contains(err.Error(), "ALTER SYSTEM is not allowed for this role"); forbidden != test.forbidden {
+			t.Errorf("alterSystemError(%v): expected forbidden message %v, got %v", test.err, test.forbidden, err)
+		}
+	}
+}
+
+func TestAccPostgresqlSystemSetting_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+	skipIfNotSuperuser(t)
+
+	config := `
+resource "postgresql_system_setting" "work_mem" {
+  name  = "work_mem"
+  value = "%s"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureAlterSystem)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSystemSettingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "8MB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_system_setting.work_mem", "id", "work_mem"),
+					resource.TestCheckResourceAttr("postgresql_system_setting.work_mem", "value", "8MB"),
+					resource.TestCheckResourceAttr("postgresql_system_setting.work_mem", "pending_restart", "false"),
+					testAccCheckPostgresqlSystemSettingValue("work_mem", "8MB"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, "16MB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_system_setting.work_mem", "value", "16MB"),
+					testAccCheckPostgresqlSystemSettingValue("work_mem", "16MB"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_system_setting.work_mem",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"current_value", "source"},
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlSystemSettingValue(name, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		value, err := getSystemSettingFileValue(name)
+		if err != nil {
+			return err
+		}
+		if value != expected {
+			return fmt.Errorf("expected %s to be set to %s in postgresql.auto.conf, got %q", name, expected, value)
+		}
+		return nil
+	}
+}
+
+func testAccCheckPostgresqlSystemSettingDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_system_setting" {
+			continue
+		}
+
+		value, err := getSystemSettingFileValue(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if value != "" {
+			return fmt.Errorf("System setting %s still set after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func getSystemSettingFileValue(name string) (string, error) {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return "", err
+	}
+
+	var value string
+	err = db.QueryRow(
+		"SELECT COALESCE(max(setting), '') FROM pg_catalog.pg_file_settings "+
+			"WHERE name = $1 AND sourcefile LIKE '%/postgresql.auto.conf'",
+		name,
+	).Scan(&value)
+	if err != nil {
+		return "", fmt.Errorf("Error reading system setting %s: %w", name, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,458 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	partitionNameAttr               = "name"
+	partitionSchemaAttr             = "schema"
+	partitionDatabaseAttr           = "database"
+	partitionParentTableAttr        = "parent_table"
+	partitionParentSchemaAttr       = "parent_schema"
+	partitionForValuesAttr          = "for_values"
+	partitionDefaultAttr            = "default"
+	partitionAttachExistingAttr     = "attach_existing"
+	partitionDetachOnDestroyAttr    = "detach_on_destroy"
+	partitionDetachConcurrentlyAttr = "detach_concurrently"
+	partitionDropCascadeAttr        = "drop_cascade"
+	partitionParentKeyAttr          = "parent_partition_key"
+)
+
+func resourcePostgreSQLTablePartition() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLTablePartitionCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLTablePartitionRead),
+		Update: PGResourceFunc(resourcePostgreSQLTablePartitionUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLTablePartitionDelete),
+		Exists: PGResourceExistsFunc(resourcePostgreSQLTablePartitionExists),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourcePostgreSQLTablePartitionCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			partitionNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the partition",
+				ValidateFunc: validateIdentifier,
+			},
+			partitionSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema of the partition",
+			},
+			partitionDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the partition is created",
+			},
+			partitionParentTableAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the partitioned table",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			partitionParentSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The schema of the partitioned table, defaults to the schema of the partition",
+			},
+			partitionForValuesAttr: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{partitionDefaultAttr},
+				Description:   "The partition bound specification, e.g.: FROM ('2023-01-01') TO ('2024-01-01'), IN ('fr', 'de') or WITH (MODULUS 4, REMAINDER 0)",
+
+				DiffSuppressFunc: sqlExpressionDiffSuppressFunc,
+			},
+			partitionDefaultAttr: {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				Default:       false,
+				ConflictsWith: []string{partitionForValuesAttr},
+				Description:   "Create the default partition, storing the rows which do not fit in any other partition",
+			},
+			partitionAttachExistingAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Attach an existing table as a partition instead of creating it. The table is detached instead of dropped on destroy",
+			},
+			partitionDetachOnDestroyAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Detach the partition instead of dropping it on destroy, so the table and its data are kept",
+			},
+			partitionDetachConcurrentlyAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Detach the partition without blocking concurrent queries on the partitioned table",
+			},
+			partitionDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the partition",
+			},
+			partitionParentKeyAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The partition key of the partitioned table, e.g.: RANGE (created_at)",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLTablePartitionCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Get(partitionDetachConcurrentlyAttr).(bool) && !diff.Get(partitionDetachOnDestroyAttr).(bool) && !diff.Get(partitionAttachExistingAttr).(bool) {
+		return fmt.Errorf("`%s` requires `%s` or `%s` to be set", partitionDetachConcurrentlyAttr, partitionDetachOnDestroyAttr, partitionAttachExistingAttr)
+	}
+
+	if !diff.NewValueKnown(partitionForValuesAttr) {
+		return nil
+	}
+	if diff.Get(partitionForValuesAttr).(string) == "" && !diff.Get(partitionDefaultAttr).(bool) {
+		return fmt.Errorf("one of `%s` or `%s` must be specified", partitionForValuesAttr, partitionDefaultAttr)
+	}
+	return nil
+}
+
+// isPartitionDetachedOnDestroy returns true if the partition has to be detached, and not dropped, on destroy.
+func isPartitionDetachedOnDestroy(d *schema.ResourceData) bool {
+	// A table which has not been created by Terraform is always kept
+	return d.Get(partitionAttachExistingAttr).(bool) || d.Get(partitionDetachOnDestroyAttr).(bool)
+}
+
+// partitionBoundToSQL returns the partition bound clause of the partition.
+func partitionBoundToSQL(d *schema.ResourceData) string {
+	if d.Get(partitionDefaultAttr).(bool) {
+		return "DEFAULT"
+	}
+	return "FOR VALUES " + d.Get(partitionForValuesAttr).(string)
+}
+
+func resourcePostgreSQLTablePartitionCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureDeclarativePartitioning) {
+		return fmt.Errorf(
+			"postgresql_table_partition resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+	if d.Get(partitionDefaultAttr).(bool) && !db.featureSupported(featureDefaultPartition) {
+		return fmt.Errorf(
+			"default partitions are not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(partitionSchemaAttr).(string)
+	partitionName := d.Get(partitionNameAttr).(string)
+	parentSchema := getPartitionParentSchema(d)
+	parentTable := d.Get(partitionParentTableAttr).(string)
+
+	partition := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(partitionName))
+	parent := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(parentSchema), pq.QuoteIdentifier(parentTable))
+
+	sql := fmt.Sprintf("CREATE TABLE %s PARTITION OF %s %s", partition, parent, partitionBoundToSQL(d))
+	if d.Get(partitionAttachExistingAttr).(bool) {
+		sql = fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s %s", parent, partition, partitionBoundToSQL(d))
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not create partition %s of %s.%s: %w", partitionName, parentSchema, parentTable, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating partition: %w", err)
+	}
+
+	d.SetId(generatePartitionID(database, schemaName, partitionName))
+
+	return resourcePostgreSQLTablePartitionReadImpl(db, d)
+}
+
+func resourcePostgreSQLTablePartitionExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	database, schemaName, partitionName, err := getDBPartitionName(d, db.client)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the database exists
+	exists, err := dbExists(db, database)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	return partitionExists(txn, schemaName, partitionName)
+}
+
+func resourcePostgreSQLTablePartitionRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureDeclarativePartitioning) {
+		return fmt.Errorf(
+			"postgresql_table_partition resource is not supported %s",
+			db.unsupportedServer(),
+		)
+	}
+
+	return resourcePostgreSQLTablePartitionReadImpl(db, d)
+}
+
+func resourcePostgreSQLTablePartitionReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, partitionName, err := getDBPartitionName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var parentSchema, parentTable, bound, partitionKey string
+	query := `SELECT pn.nspname, p.relname, pg_catalog.pg_get_expr(c.relpartbound, c.oid), pg_catalog.pg_get_partkeydef(p.oid) ` +
+		`FROM pg_catalog.pg_class c ` +
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+		`JOIN pg_catalog.pg_inherits i ON i.inhrelid = c.oid ` +
+		`JOIN pg_catalog.pg_class p ON p.oid = i.inhparent ` +
+		`JOIN pg_catalog.pg_namespace pn ON pn.oid = p.relnamespace ` +
+		`JOIN pg_catalog.pg_partitioned_table pt ON pt.partrelid = p.oid ` +
+		`WHERE n.nspname = $1 AND c.relname = $2 AND c.relispartition`
+	err = txn.QueryRow(query, schemaName, partitionName).Scan(&parentSchema, &parentTable, &bound, &partitionKey)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL partition (%s.%s) not found in database %s", schemaName, partitionName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading partition: %w", err)
+	}
+
+	isDefault := bound == "DEFAULT"
+	// PostgreSQL rewrites the bound values (e.g.: adding casts),
+	// so the configured ones are kept if Postgres stores them as the catalog ones.
+	if !isDefault {
+		forValues := strings.TrimPrefix(bound, "FOR VALUES ")
+		parent := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(parentSchema), pq.QuoteIdentifier(parentTable))
+		if configured := d.Get(partitionForValuesAttr).(string); configured != "" {
+			equivalent, err := equivalentPartitionBound(txn, parent, partitionKey, configured, bound)
+			if err != nil {
+				return fmt.Errorf("Error reading partition: %w", err)
+			}
+			if equivalent {
+				forValues = configured
+			}
+		}
+		d.Set(partitionForValuesAttr, forValues)
+	}
+
+	d.Set(partitionNameAttr, partitionName)
+	d.Set(partitionSchemaAttr, schemaName)
+	d.Set(partitionDatabaseAttr, database)
+	d.Set(partitionParentSchemaAttr, parentSchema)
+	d.Set(partitionParentTableAttr, parentTable)
+	d.Set(partitionDefaultAttr, isDefault)
+	d.Set(partitionParentKeyAttr, partitionKey)
+	d.SetId(generatePartitionID(database, schemaName, partitionName))
+
+	return nil
+}
+
+func resourcePostgreSQLTablePartitionUpdate(db *DBConnection, d *schema.ResourceData) error {
+	// Only drop_cascade and the detach flags can be updated and they only affect the destroy
+	return resourcePostgreSQLTablePartitionReadImpl(db, d)
+}
+
+func resourcePostgreSQLTablePartitionDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(partitionSchemaAttr).(string)
+	partitionName := d.Get(partitionNameAttr).(string)
+	parentSchema := getPartitionParentSchema(d)
+	parentTable := d.Get(partitionParentTableAttr).(string)
+
+	partition := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(partitionName))
+	parent := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(parentSchema), pq.QuoteIdentifier(parentTable))
+
+	if isPartitionDetachedOnDestroy(d) && d.Get(partitionDetachConcurrentlyAttr).(bool) {
+		if !db.featureSupported(featureDetachPartitionConcurrently) {
+			return fmt.Errorf("detaching partition concurrently is not supported %s", db.unsupportedServer())
+		}
+
+		if err := detachPartitionConcurrently(db, database, parent, partition); err != nil {
+			return err
+		}
+
+		d.SetId("")
+		return nil
+	}
+
+	var sql string
+	if isPartitionDetachedOnDestroy(d) {
+		sql = fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", parent, partition)
+	} else {
+		dropMode := "RESTRICT"
+		if d.Get(partitionDropCascadeAttr).(bool) {
+			dropMode = "CASCADE"
+		}
+		sql = fmt.Sprintf("DROP TABLE %s %s", partition, dropMode)
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not delete partition %s: %w", partitionName, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting partition: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// detachPartitionConcurrently runs ALTER TABLE ... DETACH PARTITION ... CONCURRENTLY, which cannot be executed
+// inside a transaction. If a previous concurrent detach has been interrupted, the partition is left in a pending
+// state and the detach has to be completed with FINALIZE instead.
+func detachPartitionConcurrently(db *DBConnection, database, parent, partition string) error {
+	conn, err := connectDatabase(db.client, database)
+	if err != nil {
+		return err
+	}
+
+	var pending bool
+	err = conn.QueryRow(
+		"SELECT inhdetachpending FROM pg_catalog.pg_inherits WHERE inhrelid = $1::regclass", partition,
+	).Scan(&pending)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("could not check if partition %s is pending detach: %w", partition, err)
+	}
+
+	mode := "CONCURRENTLY"
+	if pending {
+		mode = "FINALIZE"
+	}
+
+	if _, err := conn.Exec(fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s %s", parent, partition, mode)); err != nil {
+		return fmt.Errorf("could not detach partition %s concurrently: %w", partition, err)
+	}
+
+	return nil
+}
+
+func getPartitionParentSchema(d *schema.ResourceData) string {
+	if parentSchema, ok := d.GetOk(partitionParentSchemaAttr); ok {
+		return parentSchema.(string)
+	}
+	return d.Get(partitionSchemaAttr).(string)
+}
+
+func partitionExists(txn *sql.Tx, schemaName, partitionName string) (bool, error) {
+	var _rez bool
+	err := txn.QueryRow(
+		`SELECT TRUE FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+			`WHERE n.nspname = $1 AND c.relname = $2 AND c.relispartition`,
+		schemaName, partitionName,
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if partition exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func generatePartitionID(database, schemaName, partitionName string) string {
+	return joinResourceID(database, schemaName, partitionName)
+}
+
+// getDBPartitionName returns database, schema and partition name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBPartitionName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(partitionSchemaAttr).(string)
+	partitionName := d.Get(partitionNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and partition names.
+	if partitionName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("partition ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		partitionName = parsed[2]
+	}
+	return database, schemaName, partitionName, nil
+}
+
+// equivalentPartitionBound returns whether Postgres stores the configured bound specification (without FOR VALUES)
+// as bound, by creating a temporary partition of a temporary copy of the parent table.
+// The bounds which cannot be created are considered different.
+func equivalentPartitionBound(txn *sql.Tx, parent, partitionKey, configured, bound string) (bool, error) {
+	if normalizeSQLExpression("FOR VALUES "+configured) == normalizeSQLExpression(bound) {
+		return true, nil
+	}
+
+	statements := []string{
+		fmt.Sprintf("CREATE TEMPORARY TABLE terraform_scratch_parent (LIKE %s) PARTITION BY %s", parent, partitionKey),
+		"CREATE TEMPORARY TABLE terraform_scratch PARTITION OF pg_temp.terraform_scratch_parent FOR VALUES " + configured,
+	}
+	scratchBound, err := scratchQuery(txn, statements,
+		"SELECT pg_catalog.pg_get_expr(c.relpartbound, c.oid) FROM pg_catalog.pg_class c WHERE c.oid = 'pg_temp.terraform_scratch'::regclass",
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		log.Printf("[WARN] could not create a partition of a copy of %s: %v", parent, err)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return scratchBound == bound, nil
+}
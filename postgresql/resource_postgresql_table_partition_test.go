@@ -0,0 +1,225 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
+)
+
+func TestAccPostgresqlTablePartition_Range(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	config := getTestConfig(t)
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	dbExecute(t, config.connStr(dbName), "CREATE TABLE test_schema.events (id int, created_at date) PARTITION BY RANGE (created_at)")
+	dbExecute(t, config.connStr(dbName), "CREATE TABLE test_schema.events_2022 (id int, created_at date)")
+
+	tfConfig := fmt.Sprintf(`
+resource "postgresql_table_partition" "events_2023" {
+  database     = "%[1]s"
+  schema       = "test_schema"
+  name         = "events_2023"
+  parent_table = "events"
+  for_values   = "FROM ('2023-01-01') TO ('2024-01-01')"
+}
+
+resource "postgresql_table_partition" "events_2022" {
+  database        = "%[1]s"
+  schema          = "test_schema"
+  name            = "events_2022"
+  parent_table    = "events"
+  for_values      = "FROM ('2022-01-01') TO ('2023-01-01')"
+  attach_existing = true
+}
+`, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureDeclarativePartitioning)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlTablePartitionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: tfConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTablePartitionExists("postgresql_table_partition.events_2023"),
+					testAccCheckPostgresqlTablePartitionExists("postgresql_table_partition.events_2022"),
+					resource.TestCheckResourceAttr("postgresql_table_partition.events_2023", "parent_schema", "test_schema"),
+					resource.TestCheckResourceAttr("postgresql_table_partition.events_2023", "parent_partition_key", "RANGE (created_at)"),
+					resource.TestCheckResourceAttr("postgresql_table_partition.events_2023", "default", "false"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_table_partition.events_2023",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("%s.test_schema.events_2023", dbName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{partitionDropCascadeAttr, partitionDetachOnDestroyAttr, partitionDetachConcurrentlyAttr},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlTablePartition_DetachOnDestroy(t *testing.T) {
+	testAccPostgresqlTablePartitionDetach(t, false)
+}
+
+func TestAccPostgresqlTablePartition_DetachConcurrently(t *testing.T) {
+	testAccPostgresqlTablePartitionDetach(t, true)
+}
+
+func testAccPostgresqlTablePartitionDetach(t *testing.T, concurrently bool) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	config := getTestConfig(t)
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	dbExecute(t, config.connStr(dbName), "CREATE TABLE test_schema.events (id int, created_at date) PARTITION BY RANGE (created_at)")
+
+	tfConfig := fmt.Sprintf(`
+resource "postgresql_table_partition" "events_2023" {
+  database            = "%s"
+  schema              = "test_schema"
+  name                = "events_2023"
+  parent_table        = "events"
+  for_values          = "FROM ('2023-01-01') TO ('2024-01-01')"
+  detach_on_destroy   = true
+  detach_concurrently = %t
+}
+`, dbName, concurrently)
+
+	feature := featureDeclarativePartitioning
+	if concurrently {
+		feature = featureDetachPartitionConcurrently
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, feature)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlTablePartitionDetached(dbName, "test_schema", "events_2023", 2),
+		Steps: []resource.TestStep{
+			{
+				Config: tfConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTablePartitionExists("postgresql_table_partition.events_2023"),
+					resource.TestCheckResourceAttr("postgresql_table_partition.events_2023", "detach_on_destroy", "true"),
+					resource.TestCheckResourceAttr("postgresql_table_partition.events_2023", "detach_concurrently", fmt.Sprintf("%t", concurrently)),
+				),
+			},
+			{
+				PreConfig: func() {
+					dbExecute(t, config.connStr(dbName), "INSERT INTO test_schema.events VALUES (1, '2023-03-01'), (2, '2023-06-01')")
+				},
+				Config: tfConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTablePartitionExists("postgresql_table_partition.events_2023"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckPostgresqlTablePartitionDetached checks that the partition has been detached on destroy
+// and that the table has kept its rows.
+func testAccCheckPostgresqlTablePartitionDetached(dbName, schemaName, tableName string, expectedRows int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := partitionExists(txn, schemaName, tableName)
+		if err != nil {
+			return fmt.Errorf("Error checking partition %s", err)
+		}
+		if exists {
+			return fmt.Errorf("Partition still attached after destroy")
+		}
+
+		var rows int
+		query := fmt.Sprintf("SELECT count(*) FROM %s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(tableName))
+		if err := txn.QueryRow(query).Scan(&rows); err != nil {
+			return fmt.Errorf("could not count rows of detached table %s.%s: %w", schemaName, tableName, err)
+		}
+		if rows != expectedRows {
+			return fmt.Errorf("detached table %s.%s has %d rows, expected %d", schemaName, tableName, rows, expectedRows)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckPostgresqlTablePartitionDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_table_partition" {
+			continue
+		}
+
+		txn, err := startTransaction(client, rs.Primary.Attributes[partitionDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := partitionExists(txn, rs.Primary.Attributes[partitionSchemaAttr], rs.Primary.Attributes[partitionNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking partition %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Partition still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlTablePartitionExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[partitionDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := partitionExists(txn, rs.Primary.Attributes[partitionSchemaAttr], rs.Primary.Attributes[partitionNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking partition %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Partition not found")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,355 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	tableStorageDatabaseAttr   = "database"
+	tableStorageSchemaAttr     = "schema"
+	tableStorageTableAttr      = "table"
+	tableStorageParametersAttr = "parameters"
+)
+
+// tableStorageParameters are the storage parameters which can be set on a table
+// with the Postgres versions supporting them.
+var tableStorageParameters = map[string]semver.Range{
+	"fillfactor":                            semver.MustParseRange(">=8.2.0"),
+	"toast_tuple_target":                    semver.MustParseRange(">=11.0.0"),
+	"parallel_workers":                      semver.MustParseRange(">=9.6.0"),
+	"autovacuum_enabled":                    semver.MustParseRange(">=8.4.0"),
+	"vacuum_index_cleanup":                  semver.MustParseRange(">=12.0.0"),
+	"vacuum_truncate":                       semver.MustParseRange(">=12.0.0"),
+	"autovacuum_vacuum_threshold":           semver.MustParseRange(">=8.4.0"),
+	"autovacuum_vacuum_scale_factor":        semver.MustParseRange(">=8.4.0"),
+	"autovacuum_vacuum_insert_threshold":    semver.MustParseRange(">=13.0.0"),
+	"autovacuum_vacuum_insert_scale_factor": semver.MustParseRange(">=13.0.0"),
+	"autovacuum_analyze_threshold":          semver.MustParseRange(">=8.4.0"),
+	"autovacuum_analyze_scale_factor":       semver.MustParseRange(">=8.4.0"),
+	"autovacuum_vacuum_cost_delay":          semver.MustParseRange(">=8.4.0"),
+	"autovacuum_vacuum_cost_limit":          semver.MustParseRange(">=8.4.0"),
+	"autovacuum_freeze_min_age":             semver.MustParseRange(">=8.4.0"),
+	"autovacuum_freeze_max_age":             semver.MustParseRange(">=8.4.0"),
+	"autovacuum_freeze_table_age":           semver.MustParseRange(">=8.4.0"),
+	"autovacuum_multixact_freeze_min_age":   semver.MustParseRange(">=9.3.0"),
+	"autovacuum_multixact_freeze_max_age":   semver.MustParseRange(">=9.3.0"),
+	"autovacuum_multixact_freeze_table_age": semver.MustParseRange(">=9.3.0"),
+	"log_autovacuum_min_duration":           semver.MustParseRange(">=9.4.0"),
+	"user_catalog_table":                    semver.MustParseRange(">=9.4.0"),
+}
+
+// toastStorageParameters are the storage parameters which can also be set on the TOAST
+// table of a table, prefixed by toast.
+var toastStorageParameters = []string{
+	"autovacuum_enabled",
+	"vacuum_index_cleanup",
+	"vacuum_truncate",
+	"autovacuum_vacuum_threshold",
+	"autovacuum_vacuum_scale_factor",
+	"autovacuum_vacuum_insert_threshold",
+	"autovacuum_vacuum_insert_scale_factor",
+	"autovacuum_vacuum_cost_delay",
+	"autovacuum_vacuum_cost_limit",
+	"autovacuum_freeze_min_age",
+	"autovacuum_freeze_max_age",
+	"autovacuum_freeze_table_age",
+	"autovacuum_multixact_freeze_min_age",
+	"autovacuum_multixact_freeze_max_age",
+	"autovacuum_multixact_freeze_table_age",
+	"log_autovacuum_min_duration",
+}
+
+func init() {
+	for _, name := range toastStorageParameters {
+		tableStorageParameters["toast."+name] = tableStorageParameters[name]
+	}
+}
+
+func resourcePostgreSQLTableStorageParameters() *schema.Resource {
+	resource := &schema.Resource{
+		// Version 1 escapes the dots of the names in the ID
+		SchemaVersion: 1,
+		Create:        PGResourceFunc(resourcePostgreSQLTableStorageParametersCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLTableStorageParametersRead),
+		Update:        PGResourceFunc(resourcePostgreSQLTableStorageParametersUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLTableStorageParametersDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			tableStorageDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the table",
+			},
+			tableStorageSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema of the table",
+			},
+			tableStorageTableAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the table",
+			},
+			tableStorageParametersAttr: {
+				Type:         schema.TypeMap,
+				Required:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateTableStorageParameters,
+				Description:  "The storage parameters managed on the table (e.g.: fillfactor, autovacuum_vacuum_scale_factor, toast.autovacuum_enabled)",
+			},
+		},
+	}
+	resource.StateUpgraders = []schema.StateUpgrader{
+		resourceIDStateUpgrader(resource, 0, func(rawState map[string]interface{}) string {
+			return tableIDFromRawState(rawState, tableStorageTableAttr)
+		}),
+	}
+
+	return resource
+}
+
+func validateTableStorageParameters(v interface{}, key string) (warnings []string, errors []error) {
+	for name := range v.(map[string]interface{}) {
+		if _, ok := tableStorageParameters[name]; !ok {
+			errors = append(errors, fmt.Errorf("%s: unknown table storage parameter %q", key, name))
+		}
+	}
+	return
+}
+
+// checkTableStorageParametersSupported returns an error if a parameter is not supported by the Postgres version.
+func checkTableStorageParametersSupported(db *DBConnection, params map[string]interface{}) error {
+	for name := range params {
+		if versions, ok := tableStorageParameters[name]; ok && !versions(db.version) {
+			return fmt.Errorf("table storage parameter %s is not supported %s", name, db.unsupportedServer())
+		}
+	}
+	return nil
+}
+
+func resourcePostgreSQLTableStorageParametersCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(tableStorageSchemaAttr).(string)
+	tableName := d.Get(tableStorageTableAttr).(string)
+
+	if err := setTableStorageParameters(db, d, map[string]interface{}{}); err != nil {
+		return err
+	}
+
+	d.SetId(generateTableStorageParametersID(database, schemaName, tableName))
+
+	return resourcePostgreSQLTableStorageParametersReadImpl(db, d)
+}
+
+// setTableStorageParameters resets the parameters removed from the configuration and sets the
+// parameters added or changed, the other parameters of the table are not modified.
+func setTableStorageParameters(db *DBConnection, d *schema.ResourceData, oldParams map[string]interface{}) error {
+	newParams := d.Get(tableStorageParametersAttr).(map[string]interface{})
+	if err := checkTableStorageParametersSupported(db, newParams); err != nil {
+		return err
+	}
+
+	var toReset []string
+	for k := range oldParams {
+		if _, ok := newParams[k]; !ok {
+			toReset = append(toReset, k)
+		}
+	}
+	sort.Strings(toReset)
+
+	toSet := map[string]interface{}{}
+	for k, v := range newParams {
+		if old, ok := oldParams[k]; !ok || old != v {
+			toSet[k] = v
+		}
+	}
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	table := fmt.Sprintf("%s.%s",
+		pq.QuoteIdentifier(d.Get(tableStorageSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(tableStorageTableAttr).(string)),
+	)
+
+	if len(toReset) > 0 {
+		if _, err := txn.Exec(fmt.Sprintf("ALTER TABLE %s RESET (%s)", table, strings.Join(toReset, ", "))); err != nil {
+			return fmt.Errorf("Error resetting storage parameters of table %s: %w", table, err)
+		}
+	}
+
+	if len(toSet) > 0 {
+		if _, err := txn.Exec(fmt.Sprintf("ALTER TABLE %s SET (%s)", table, storageParametersToSQL(toSet))); err != nil {
+			return fmt.Errorf("Error setting storage parameters of table %s: %w", table, err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error setting storage parameters of table %s: %w", table, err)
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLTableStorageParametersRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLTableStorageParametersReadImpl(db, d)
+}
+
+func resourcePostgreSQLTableStorageParametersReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, tableName, err := getDBTableStorageParametersName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var relOptions, toastRelOptions []string
+	err = txn.QueryRow(
+		`SELECT COALESCE(c.reloptions, '{}'), COALESCE(t.reloptions, '{}') `+
+			`FROM pg_catalog.pg_class c `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+			`LEFT JOIN pg_catalog.pg_class t ON t.oid = c.reltoastrelid `+
+			`WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('r', 'p')`,
+		schemaName, tableName,
+	).Scan(pq.Array(&relOptions), pq.Array(&toastRelOptions))
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL table (%s.%s) not found in database %s", schemaName, tableName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading storage parameters of table: %w", err)
+	}
+
+	current := relOptionsToMap(relOptions)
+	for name, value := range relOptionsToMap(toastRelOptions) {
+		current["toast."+name] = value
+	}
+
+	// Only the managed parameters are kept in the state, all of them when importing
+	params := current
+	if managed := d.Get(tableStorageParametersAttr).(map[string]interface{}); len(managed) > 0 {
+		params = map[string]interface{}{}
+		for name := range managed {
+			if value, ok := current[name]; ok {
+				params[name] = value
+			}
+		}
+	}
+
+	d.Set(tableStorageDatabaseAttr, database)
+	d.Set(tableStorageSchemaAttr, schemaName)
+	d.Set(tableStorageTableAttr, tableName)
+	d.Set(tableStorageParametersAttr, params)
+	d.SetId(generateTableStorageParametersID(database, schemaName, tableName))
+
+	return nil
+}
+
+func resourcePostgreSQLTableStorageParametersUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if d.HasChange(tableStorageParametersAttr) {
+		oraw, _ := d.GetChange(tableStorageParametersAttr)
+		if err := setTableStorageParameters(db, d, oraw.(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+
+	return resourcePostgreSQLTableStorageParametersReadImpl(db, d)
+}
+
+func resourcePostgreSQLTableStorageParametersDelete(db *DBConnection, d *schema.ResourceData) error {
+	params := d.Get(tableStorageParametersAttr).(map[string]interface{})
+	if len(params) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	table := fmt.Sprintf("%s.%s",
+		pq.QuoteIdentifier(d.Get(tableStorageSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(tableStorageTableAttr).(string)),
+	)
+	if _, err := txn.Exec(fmt.Sprintf("ALTER TABLE %s RESET (%s)", table, strings.Join(names, ", "))); err != nil {
+		return fmt.Errorf("Error resetting storage parameters of table %s: %w", table, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error resetting storage parameters of table %s: %w", table, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func generateTableStorageParametersID(database, schemaName, tableName string) string {
+	return joinResourceID(database, schemaName, tableName)
+}
+
+// tableIDFromRawState returns the ID of a resource managing a table from its raw state,
+// tableAttr being the attribute with the table name.
+func tableIDFromRawState(rawState map[string]interface{}, tableAttr string) string {
+	database := rawStateString(rawState, tableStorageDatabaseAttr)
+	schemaName := rawStateString(rawState, tableStorageSchemaAttr)
+	tableName := rawStateString(rawState, tableAttr)
+	if database == "" || schemaName == "" || tableName == "" {
+		return ""
+	}
+	return generateTableStorageParametersID(database, schemaName, tableName)
+}
+
+// getDBTableStorageParametersName returns database, schema and table name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBTableStorageParametersName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(tableStorageSchemaAttr).(string)
+	tableName := d.Get(tableStorageTableAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and table names.
+	if tableName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("table storage parameters ID %s has not the expected format 'database.schema.table': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		tableName = parsed[2]
+	}
+	return database, schemaName, tableName, nil
+}
@@ -0,0 +1,211 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
+)
+
+func TestValidateTableStorageParameters(t *testing.T) {
+	var cases = []struct {
+		params map[string]interface{}
+		valid  bool
+	}{
+		{params: map[string]interface{}{"fillfactor": "70", "autovacuum_vacuum_scale_factor": "0.05"}, valid: true},
+		{params: map[string]interface{}{"toast.autovacuum_enabled": "false"}, valid: true},
+		{params: map[string]interface{}{"toast.fillfactor": "70"}, valid: false},
+		{params: map[string]interface{}{"autovacuum_vaccum_scale_factor": "0.05"}, valid: false},
+	}
+
+	for _, c := range cases {
+		_, errors := validateTableStorageParameters(c.params, "parameters")
+		if valid := len(errors) == 0; valid != c.valid {
+			t.Fatalf("Expected validation of %v to be %t, got errors: %v", c.params, c.valid, errors)
+		}
+	}
+}
+
+func TestTableStorageParametersStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":         "mydb.Sales.Orders.2024/Q1",
+		"database":   "mydb",
+		"schema":     "Sales",
+		"table":      "Orders.2024/Q1",
+		"parameters": map[string]interface{}{"fillfactor": "70"},
+	}
+
+	upgraded, err := resourcePostgreSQLTableStorageParameters().StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `mydb.Sales.Orders\.2024/Q1`; upgraded["id"] != expected {
+		t.Fatalf("expected ID to be upgraded to %s, got %v", expected, upgraded["id"])
+	}
+
+	d := resourcePostgreSQLTableStorageParameters().TestResourceData()
+	d.SetId(upgraded["id"].(string))
+	database, schemaName, tableName, err := getDBTableStorageParametersName(d, &Client{})
+	if err != nil {
+		t.Fatalf("could not parse upgraded ID: %v", err)
+	}
+	if database != "mydb" || schemaName != "Sales" || tableName != "Orders.2024/Q1" {
+		t.Fatalf("unexpected names parsed from upgraded ID: %s, %s, %s", database, schemaName, tableName)
+	}
+}
+
+func TestAccPostgresqlTableStorageParameters_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	tablesTeardown := createTestTables(t, dbSuffix, []string{"test_table"}, "")
+	defer tablesTeardown()
+
+	// fillfactor is not managed by the resource, it must be left untouched
+	testConfig := getTestConfig(t)
+	dbExecute(t, testConfig.connStr(dbName), "ALTER TABLE test_table SET (fillfactor = 80)")
+
+	config := `
+resource "postgresql_table_storage_parameters" "test" {
+  database = "%s"
+  table    = "test_table"
+
+  parameters = {
+    %s
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: testAccCheckTableStorageParameters(dbName, "test_table", map[string]string{
+			"fillfactor": "80",
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, `
+    autovacuum_vacuum_scale_factor = "0.05"
+    "toast.autovacuum_enabled"     = "false"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_table_storage_parameters.test", "schema", "public"),
+					resource.TestCheckResourceAttr("postgresql_table_storage_parameters.test", "parameters.%", "2"),
+					resource.TestCheckResourceAttr("postgresql_table_storage_parameters.test", "parameters.autovacuum_vacuum_scale_factor", "0.05"),
+					testAccCheckTableStorageParameters(dbName, "test_table", map[string]string{
+						"fillfactor":                     "80",
+						"autovacuum_vacuum_scale_factor": "0.05",
+						"toast.autovacuum_enabled":       "false",
+					}),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, `
+    autovacuum_vacuum_scale_factor = "0.1"
+    autovacuum_analyze_threshold   = "1000"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_table_storage_parameters.test", "parameters.%", "2"),
+					testAccCheckTableStorageParameters(dbName, "test_table", map[string]string{
+						"fillfactor":                     "80",
+						"autovacuum_vacuum_scale_factor": "0.1",
+						"autovacuum_analyze_threshold":   "1000",
+					}),
+				),
+			},
+			{
+				ResourceName:      "postgresql_table_storage_parameters.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.public.test_table", dbName),
+				ImportStateVerify: true,
+				// All the parameters of the table are imported
+				ImportStateVerifyIgnore: []string{"parameters.%", "parameters.fillfactor"},
+			},
+			{
+				Config:      fmt.Sprintf(config, dbName, `autovacuum_vaccum_scale_factor = "0.1"`),
+				ExpectError: regexp.MustCompile(`unknown table storage parameter "autovacuum_vaccum_scale_factor"`),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlTableStorageParameters_FillfactorAutovacuum(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	tablesTeardown := createTestTables(t, dbSuffix, []string{"test_table"}, "")
+	defer tablesTeardown()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTableStorageParameters(dbName, "test_table", map[string]string{}),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_table_storage_parameters" "test" {
+  database = "%s"
+  table    = "test_table"
+
+  parameters = {
+    fillfactor         = "70"
+    autovacuum_enabled = "false"
+  }
+}
+`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_table_storage_parameters.test", "parameters.fillfactor", "70"),
+					resource.TestCheckResourceAttr("postgresql_table_storage_parameters.test", "parameters.autovacuum_enabled", "false"),
+					testAccCheckTableStorageParameters(dbName, "test_table", map[string]string{
+						"fillfactor":         "70",
+						"autovacuum_enabled": "false",
+					}),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckTableStorageParameters checks the storage parameters of the table and its TOAST table
+// are exactly the expected ones.
+func testAccCheckTableStorageParameters(dbName, tableName string, expected map[string]string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var relOptions, toastRelOptions []string
+		if err := txn.QueryRow(
+			"SELECT COALESCE(c.reloptions, '{}'), COALESCE(t.reloptions, '{}') FROM pg_class c "+
+				"LEFT JOIN pg_class t ON t.oid = c.reltoastrelid WHERE c.oid = $1::regclass",
+			tableName,
+		).Scan(pq.Array(&relOptions), pq.Array(&toastRelOptions)); err != nil {
+			return fmt.Errorf("could not read storage parameters of table %s: %w", tableName, err)
+		}
+
+		params := map[string]string{}
+		for name, value := range relOptionsToMap(relOptions) {
+			params[name] = value.(string)
+		}
+		for name, value := range relOptionsToMap(toastRelOptions) {
+			params["toast."+name] = value.(string)
+		}
+
+		if !reflect.DeepEqual(params, expected) {
+			return fmt.Errorf("expected storage parameters of table %s to be %v, got %v", tableName, expected, params)
+		}
+		return nil
+	}
+}
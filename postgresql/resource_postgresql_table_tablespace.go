@@ -0,0 +1,247 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	tableTablespaceDatabaseAttr    = "database"
+	tableTablespaceSchemaAttr      = "schema"
+	tableTablespaceObjectNameAttr  = "object_name"
+	tableTablespaceTablespaceAttr  = "tablespace"
+	tableTablespaceMoveIndexesAttr = "move_indexes"
+)
+
+func resourcePostgreSQLTableTablespace() *schema.Resource {
+	resource := &schema.Resource{
+		// Version 1 escapes the dots of the names in the ID
+		SchemaVersion: 1,
+		Create:        PGResourceFunc(resourcePostgreSQLTableTablespaceCreate),
+		Read:          PGResourceFunc(resourcePostgreSQLTableTablespaceRead),
+		Update:        PGResourceFunc(resourcePostgreSQLTableTablespaceUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLTableTablespaceDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			tableTablespaceDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the table",
+			},
+			tableTablespaceSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema of the table",
+			},
+			tableTablespaceObjectNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the table",
+			},
+			tableTablespaceTablespaceAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The tablespace in which the table is stored",
+			},
+			tableTablespaceMoveIndexesAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to also move the indexes of the table to the tablespace",
+			},
+		},
+	}
+	resource.StateUpgraders = []schema.StateUpgrader{
+		resourceIDStateUpgrader(resource, 0, func(rawState map[string]interface{}) string {
+			return tableIDFromRawState(rawState, tableTablespaceObjectNameAttr)
+		}),
+	}
+
+	return resource
+}
+
+func resourcePostgreSQLTableTablespaceCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(tableTablespaceSchemaAttr).(string)
+	tableName := d.Get(tableTablespaceObjectNameAttr).(string)
+
+	if err := setTableTablespace(db, d, d.Get(tableTablespaceTablespaceAttr).(string)); err != nil {
+		return err
+	}
+
+	d.SetId(generateTableStorageParametersID(database, schemaName, tableName))
+
+	return resourcePostgreSQLTableTablespaceReadImpl(db, d)
+}
+
+// setTableTablespace moves the table, and its indexes if move_indexes is set, to the tablespace.
+// The table is rewritten while holding an ACCESS EXCLUSIVE lock, which blocks reads and writes.
+func setTableTablespace(db *DBConnection, d *schema.ResourceData, tablespace string) error {
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	table := fmt.Sprintf("%s.%s",
+		pq.QuoteIdentifier(d.Get(tableTablespaceSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(tableTablespaceObjectNameAttr).(string)),
+	)
+
+	if _, err := txn.Exec(fmt.Sprintf("ALTER TABLE %s SET TABLESPACE %s", table, pq.QuoteIdentifier(tablespace))); err != nil {
+		return fmt.Errorf("Error moving table %s to tablespace %s: %w", table, tablespace, err)
+	}
+
+	if d.Get(tableTablespaceMoveIndexesAttr).(bool) {
+		indexes, err := listTableIndexes(txn, table)
+		if err != nil {
+			return err
+		}
+		for _, index := range indexes {
+			if _, err := txn.Exec(fmt.Sprintf("ALTER INDEX %s SET TABLESPACE %s", index, pq.QuoteIdentifier(tablespace))); err != nil {
+				return fmt.Errorf("Error moving index %s to tablespace %s: %w", index, tablespace, err)
+			}
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error moving table %s to tablespace %s: %w", table, tablespace, err)
+	}
+
+	return nil
+}
+
+// listTableIndexes returns the qualified and quoted names of the indexes of the table.
+func listTableIndexes(txn *sql.Tx, table string) ([]string, error) {
+	rows, err := txn.Query(
+		"SELECT indexrelid::regclass::text FROM pg_catalog.pg_index WHERE indrelid = $1::regclass ORDER BY 1",
+		table,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not list indexes of table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var indexes []string
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return nil, fmt.Errorf("could not scan index name: %w", err)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
+
+func resourcePostgreSQLTableTablespaceRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLTableTablespaceReadImpl(db, d)
+}
+
+func resourcePostgreSQLTableTablespaceReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, tableName, err := getDBTableTablespaceName(d, db.client)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// reltablespace is 0 when the table is in the default tablespace of the database
+	var tablespace string
+	err = txn.QueryRow(
+		`SELECT COALESCE(t.spcname, dt.spcname) `+
+			`FROM pg_catalog.pg_class c `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+			`LEFT JOIN pg_catalog.pg_tablespace t ON t.oid = c.reltablespace `+
+			`JOIN pg_catalog.pg_database d ON d.datname = pg_catalog.current_database() `+
+			`JOIN pg_catalog.pg_tablespace dt ON dt.oid = d.dattablespace `+
+			`WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('r', 'p')`,
+		schemaName, tableName,
+	).Scan(&tablespace)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL table (%s.%s) not found in database %s", schemaName, tableName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading tablespace of table: %w", err)
+	}
+
+	d.Set(tableTablespaceDatabaseAttr, database)
+	d.Set(tableTablespaceSchemaAttr, schemaName)
+	d.Set(tableTablespaceObjectNameAttr, tableName)
+	d.Set(tableTablespaceTablespaceAttr, tablespace)
+	d.SetId(generateTableStorageParametersID(database, schemaName, tableName))
+
+	return nil
+}
+
+func resourcePostgreSQLTableTablespaceUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if d.HasChanges(tableTablespaceTablespaceAttr, tableTablespaceMoveIndexesAttr) {
+		if err := setTableTablespace(db, d, d.Get(tableTablespaceTablespaceAttr).(string)); err != nil {
+			return err
+		}
+	}
+
+	return resourcePostgreSQLTableTablespaceReadImpl(db, d)
+}
+
+// resourcePostgreSQLTableTablespaceDelete moves the table back to the default tablespace of its database.
+func resourcePostgreSQLTableTablespaceDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	var defaultTablespace string
+	err := db.QueryRow(
+		"SELECT t.spcname FROM pg_catalog.pg_database d JOIN pg_catalog.pg_tablespace t ON t.oid = d.dattablespace WHERE d.datname = $1",
+		database,
+	).Scan(&defaultTablespace)
+	if err != nil {
+		return fmt.Errorf("could not read default tablespace of database %s: %w", database, err)
+	}
+
+	if err := setTableTablespace(db, d, defaultTablespace); err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// getDBTableTablespaceName returns database, schema and table name. If we are importing this
+// resource, they will be parsed from the resource ID (it will return an error if parsing failed)
+// otherwise they will be simply get from the state.
+func getDBTableTablespaceName(d *schema.ResourceData, client *Client) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(tableTablespaceSchemaAttr).(string)
+	tableName := d.Get(tableTablespaceObjectNameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and table names.
+	if tableName == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("table tablespace ID %s has not the expected format 'database.schema.table': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		tableName = parsed[2]
+	}
+	return database, schemaName, tableName, nil
+}
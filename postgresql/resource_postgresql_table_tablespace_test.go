@@ -0,0 +1,95 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlTableTablespace_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	tablespaceName := fmt.Sprintf("tablespace_%s", dbSuffix)
+	testConfig := getTestConfig(t)
+	dsn := testConfig.connStr(dbName)
+	dbExecute(t, dsn, "CREATE TABLE test_schema.events (id integer PRIMARY KEY, payload text)")
+
+	tfConfig := `
+resource "postgresql_tablespace" "test" {
+  name     = "%[1]s"
+  location = "%[2]s"
+}
+
+resource "postgresql_table_tablespace" "events" {
+  database     = "%[3]s"
+  schema       = "test_schema"
+  object_name  = "events"
+  tablespace   = %[4]s
+  move_indexes = true
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(tfConfig, tablespaceName, getTestTablespaceLocation(), dbName, "postgresql_tablespace.test.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_table_tablespace.events", "tablespace", tablespaceName),
+					resource.TestCheckResourceAttr("postgresql_table_tablespace.events", "id", dbName+".test_schema.events"),
+					testCheckIndexTablespace(t, dsn, "events_pkey", tablespaceName),
+				),
+			},
+			{
+				ResourceName:            "postgresql_table_tablespace.events",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"move_indexes"},
+			},
+			{
+				// The table and its indexes are moved back to the default tablespace
+				Config: fmt.Sprintf(tfConfig, tablespaceName, getTestTablespaceLocation(), dbName, `"pg_default"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_table_tablespace.events", "tablespace", "pg_default"),
+					testCheckIndexTablespace(t, dsn, "events_pkey", ""),
+				),
+			},
+		},
+	})
+}
+
+// testCheckIndexTablespace checks the tablespace of an index of test_schema,
+// an empty tablespace meaning the default tablespace of the database.
+func testCheckIndexTablespace(t *testing.T, dsn, index, expected string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			t.Fatalf("could not create connection pool: %v", err)
+		}
+		defer db.Close()
+
+		var tablespace string
+		err = db.QueryRow(
+			"SELECT COALESCE(tablespace, '') FROM pg_catalog.pg_indexes WHERE schemaname = 'test_schema' AND indexname = $1",
+			index,
+		).Scan(&tablespace)
+		if err != nil {
+			return fmt.Errorf("could not read tablespace of index %s: %w", index, err)
+		}
+		if tablespace != expected {
+			return fmt.Errorf("expected index %s to be in tablespace %q, got %q", index, expected, tablespace)
+		}
+		return nil
+	}
+}
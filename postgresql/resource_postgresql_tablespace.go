@@ -0,0 +1,384 @@
+package postgresql
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	tablespaceNameAttr                   = "name"
+	tablespaceLocationAttr               = "location"
+	tablespaceOwnerAttr                  = "owner"
+	tablespaceSeqPageCostAttr            = "seq_page_cost"
+	tablespaceRandomPageCostAttr         = "random_page_cost"
+	tablespaceEffectiveIOConcurrencyAttr = "effective_io_concurrency"
+	tablespaceSizeAttr                   = "size"
+)
+
+// tablespaceOptions are the options which can be set on a tablespace with ALTER TABLESPACE ... SET
+var tablespaceOptions = []string{
+	tablespaceSeqPageCostAttr,
+	tablespaceRandomPageCostAttr,
+	tablespaceEffectiveIOConcurrencyAttr,
+}
+
+func resourcePostgreSQLTablespace() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLTablespaceCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLTablespaceRead),
+		Update: PGResourceFunc(resourcePostgreSQLTablespaceUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLTablespaceDelete),
+		Exists: PGResourceExistsFunc(resourcePostgreSQLTablespaceExists),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			tablespaceNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The name of the tablespace",
+				ValidateFunc: validateIdentifier,
+			},
+			tablespaceLocationAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The directory that will be used for the tablespace, it must exist, be empty and be owned by the PostgreSQL system user",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			tablespaceOwnerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ROLE which owns the tablespace",
+			},
+			tablespaceSeqPageCostAttr: {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Description:  "The planner's estimate of the cost of a sequentially fetched disk page in this tablespace",
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			tablespaceRandomPageCostAttr: {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Description:  "The planner's estimate of the cost of a non-sequentially-fetched disk page in this tablespace",
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			tablespaceEffectiveIOConcurrencyAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The number of concurrent disk I/O operations that can be executed simultaneously on this tablespace",
+				ValidateFunc: validation.IntBetween(0, 1000),
+			},
+			tablespaceSizeAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The disk space used by the tablespace, in bytes",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLTablespaceCreate(db *DBConnection, d *schema.ResourceData) error {
+	tablespaceName := d.Get(tablespaceNameAttr).(string)
+
+	b := bytes.NewBufferString("CREATE TABLESPACE ")
+	fmt.Fprint(b, pq.QuoteIdentifier(tablespaceName))
+
+	if v, ok := d.GetOk(tablespaceOwnerAttr); ok {
+		fmt.Fprint(b, " OWNER ", pq.QuoteIdentifier(v.(string)))
+	}
+
+	fmt.Fprint(b, " LOCATION ", pq.QuoteLiteral(d.Get(tablespaceLocationAttr).(string)))
+
+	options := []string{}
+	for _, option := range tablespaceOptions {
+		if v, ok := d.GetOk(option); ok {
+			options = append(options, fmt.Sprintf("%s = %v", option, v))
+		}
+	}
+	if len(options) > 0 {
+		fmt.Fprintf(b, " WITH (%s)", strings.Join(options, ", "))
+	}
+
+	// CREATE TABLESPACE cannot be executed inside a transaction
+	if _, err := db.Exec(b.String()); err != nil {
+		return fmt.Errorf("Error creating tablespace %q: %w", tablespaceName, err)
+	}
+
+	d.SetId(tablespaceName)
+
+	return resourcePostgreSQLTablespaceReadImpl(db, d)
+}
+
+func resourcePostgreSQLTablespaceExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	var tablespaceName string
+	err := db.QueryRow("SELECT spcname FROM pg_catalog.pg_tablespace WHERE spcname = $1", d.Id()).Scan(&tablespaceName)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if tablespace exists: %w", err)
+	}
+
+	return true, nil
+}
+
+func resourcePostgreSQLTablespaceRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLTablespaceReadImpl(db, d)
+}
+
+func resourcePostgreSQLTablespaceReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	tablespaceID := d.Id()
+
+	var tablespaceName, owner, location string
+	var options []string
+	var size int64
+	query := `SELECT spcname, pg_catalog.pg_get_userbyid(spcowner), pg_catalog.pg_tablespace_location(oid), ` +
+		`spcoptions, pg_catalog.pg_tablespace_size(oid) ` +
+		`FROM pg_catalog.pg_tablespace WHERE spcname = $1`
+	err := db.QueryRow(query, tablespaceID).Scan(&tablespaceName, &owner, &location, pq.Array(&options), &size)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL tablespace (%q) not found", tablespaceID)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading tablespace: %w", err)
+	}
+
+	parsedOptions := parseForeignOptions(options)
+	for _, option := range []string{tablespaceSeqPageCostAttr, tablespaceRandomPageCostAttr} {
+		var value float64
+		if v, ok := parsedOptions[option]; ok {
+			if value, err = strconv.ParseFloat(v.(string), 64); err != nil {
+				return fmt.Errorf("could not parse tablespace option %s: %w", option, err)
+			}
+		}
+		d.Set(option, value)
+	}
+
+	var ioConcurrency int
+	if v, ok := parsedOptions[tablespaceEffectiveIOConcurrencyAttr]; ok {
+		if ioConcurrency, err = strconv.Atoi(v.(string)); err != nil {
+			return fmt.Errorf("could not parse tablespace option %s: %w", tablespaceEffectiveIOConcurrencyAttr, err)
+		}
+	}
+	d.Set(tablespaceEffectiveIOConcurrencyAttr, ioConcurrency)
+
+	d.Set(tablespaceNameAttr, tablespaceName)
+	d.Set(tablespaceOwnerAttr, owner)
+	d.Set(tablespaceLocationAttr, location)
+	d.Set(tablespaceSizeAttr, size)
+	d.SetId(tablespaceName)
+
+	return nil
+}
+
+func resourcePostgreSQLTablespaceUpdate(db *DBConnection, d *schema.ResourceData) error {
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := setTablespaceName(txn, d); err != nil {
+		return err
+	}
+
+	if err := setTablespaceOwner(txn, d); err != nil {
+		return err
+	}
+
+	if err := setTablespaceOptions(txn, d); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating tablespace: %w", err)
+	}
+
+	// The tablespace may have been renamed
+	d.SetId(d.Get(tablespaceNameAttr).(string))
+
+	return resourcePostgreSQLTablespaceReadImpl(db, d)
+}
+
+func setTablespaceName(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(tablespaceNameAttr) {
+		return nil
+	}
+
+	oldName, newName := d.GetChange(tablespaceNameAttr)
+
+	sql := fmt.Sprintf("ALTER TABLESPACE %s RENAME TO %s", pq.QuoteIdentifier(oldName.(string)), pq.QuoteIdentifier(newName.(string)))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating tablespace name: %w", err)
+	}
+
+	return nil
+}
+
+func setTablespaceOwner(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(tablespaceOwnerAttr) {
+		return nil
+	}
+
+	owner := d.Get(tablespaceOwnerAttr).(string)
+	if owner == "" {
+		return nil
+	}
+
+	tablespaceName := d.Get(tablespaceNameAttr).(string)
+	sql := fmt.Sprintf("ALTER TABLESPACE %s OWNER TO %s", pq.QuoteIdentifier(tablespaceName), pq.QuoteIdentifier(owner))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating tablespace owner: %w", err)
+	}
+
+	return nil
+}
+
+func setTablespaceOptions(txn *sql.Tx, d *schema.ResourceData) error {
+	setOptions := []string{}
+	resetOptions := []string{}
+	for _, option := range tablespaceOptions {
+		if !d.HasChange(option) {
+			continue
+		}
+		if v, ok := d.GetOk(option); ok {
+			setOptions = append(setOptions, fmt.Sprintf("%s = %v", option, v))
+		} else {
+			resetOptions = append(resetOptions, option)
+		}
+	}
+
+	tablespaceName := pq.QuoteIdentifier(d.Get(tablespaceNameAttr).(string))
+
+	if len(setOptions) > 0 {
+		sql := fmt.Sprintf("ALTER TABLESPACE %s SET (%s)", tablespaceName, strings.Join(setOptions, ", "))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating tablespace options: %w", err)
+		}
+	}
+
+	if len(resetOptions) > 0 {
+		sql := fmt.Sprintf("ALTER TABLESPACE %s RESET (%s)", tablespaceName, strings.Join(resetOptions, ", "))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error resetting tablespace options: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLTablespaceDelete(db *DBConnection, d *schema.ResourceData) error {
+	tablespaceName := d.Get(tablespaceNameAttr).(string)
+
+	// DROP TABLESPACE cannot be executed inside a transaction
+	sql := fmt.Sprintf("DROP TABLESPACE %s", pq.QuoteIdentifier(tablespaceName))
+	if _, err := db.Exec(sql); err != nil {
+		// Postgres refuses to drop a tablespace which is not empty, without telling what is still using it.
+		usages, usagesErr := getTablespaceUsages(db, tablespaceName)
+		switch {
+		case usagesErr != nil:
+			log.Printf("[WARN] could not list the objects using tablespace %s: %v", tablespaceName, usagesErr)
+		case len(usages) > 0:
+			return fmt.Errorf("Error dropping tablespace %s, it is still used by: %s: %w", tablespaceName, strings.Join(usages, ", "), err)
+		}
+		return fmt.Errorf("Error dropping tablespace %s: %w", tablespaceName, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// getTablespaceUsages returns the databases whose default tablespace is the given one
+// and the relations stored in it, in each database accepting connections.
+func getTablespaceUsages(db *DBConnection, tablespaceName string) ([]string, error) {
+	rows, err := db.Query(
+		`SELECT d.datname, d.datallowconn, d.dattablespace = t.oid `+
+			`FROM pg_catalog.pg_database d, pg_catalog.pg_tablespace t `+
+			`WHERE t.spcname = $1 ORDER BY d.datname`,
+		tablespaceName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not list databases: %w", err)
+	}
+
+	var databases []string
+	usages := []string{}
+	for rows.Next() {
+		var dbName string
+		var allowConn, isDefault bool
+		if err := rows.Scan(&dbName, &allowConn, &isDefault); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("could not scan database: %w", err)
+		}
+		if isDefault {
+			usages = append(usages, fmt.Sprintf("database %s", dbName))
+		}
+		if allowConn {
+			databases = append(databases, dbName)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, dbName := range databases {
+		relations, err := getTablespaceRelations(db, dbName, tablespaceName)
+		if err != nil {
+			log.Printf("[WARN] could not list the relations of database %s using tablespace %s: %v", dbName, tablespaceName, err)
+			continue
+		}
+		for _, relation := range relations {
+			usages = append(usages, fmt.Sprintf("relation %s.%s", dbName, relation))
+		}
+	}
+	sort.Strings(usages)
+
+	return usages, nil
+}
+
+// getTablespaceRelations returns the relations of a database stored in the given tablespace.
+func getTablespaceRelations(db *DBConnection, database, tablespaceName string) ([]string, error) {
+	conn, err := connectDatabase(db.client, database)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(
+		`SELECT n.nspname || '.' || c.relname FROM pg_catalog.pg_class c `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+			`JOIN pg_catalog.pg_tablespace t ON t.oid = c.reltablespace `+
+			`WHERE t.spcname = $1`,
+		tablespaceName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	relations := []string{}
+	for rows.Next() {
+		var relation string
+		if err := rows.Scan(&relation); err != nil {
+			return nil, err
+		}
+		relations = append(relations, relation)
+	}
+
+	return relations, rows.Err()
+}
@@ -0,0 +1,173 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// getTestTablespaceLocation returns an empty directory owned by the PostgreSQL system user
+// (see tests/docker-compose.yml).
+func getTestTablespaceLocation() string {
+	if location := os.Getenv("PGTABLESPACE_LOCATION"); location != "" {
+		return location
+	}
+	return "/var/lib/postgresql/tablespaces"
+}
+
+func TestAccPostgresqlTablespace_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	tablespaceName := fmt.Sprintf("tablespace_%s", dbSuffix)
+	config := getTestConfig(t)
+
+	tfConfig := fmt.Sprintf(`
+resource "postgresql_tablespace" "test" {
+  name             = "%s"
+  location         = "%s"
+  owner            = "%s"
+  seq_page_cost    = 1.5
+  random_page_cost = 2
+}
+`, tablespaceName, getTestTablespaceLocation(), roleName)
+
+	tfConfigUpdated := fmt.Sprintf(`
+resource "postgresql_tablespace" "test" {
+  name                     = "%s_renamed"
+  location                 = "%s"
+  seq_page_cost            = 1.5
+  effective_io_concurrency = 16
+}
+`, tablespaceName, getTestTablespaceLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlTablespaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: tfConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTablespaceExists("postgresql_tablespace.test"),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "name", tablespaceName),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "owner", roleName),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "location", getTestTablespaceLocation()),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "seq_page_cost", "1.5"),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "random_page_cost", "2"),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "effective_io_concurrency", "0"),
+					resource.TestCheckResourceAttrSet("postgresql_tablespace.test", "size"),
+				),
+			},
+			{
+				Config: tfConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTablespaceExists("postgresql_tablespace.test"),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "name", tablespaceName+"_renamed"),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "owner", roleName),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "seq_page_cost", "1.5"),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "random_page_cost", "0"),
+					resource.TestCheckResourceAttr("postgresql_tablespace.test", "effective_io_concurrency", "16"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_tablespace.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// A non-empty tablespace cannot be dropped, the error lists what is still using it
+				PreConfig: func() {
+					dbExecute(t, config.connStr(dbName), fmt.Sprintf("CREATE TABLE test_schema.stored (id int) TABLESPACE %s_renamed", tablespaceName))
+				},
+				Config:      tfConfigUpdated,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`still used by: relation %s\.test_schema\.stored`, dbName)),
+			},
+			{
+				PreConfig: func() {
+					dbExecute(t, config.connStr(dbName), "DROP TABLE test_schema.stored")
+				},
+				Config: tfConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTablespaceExists("postgresql_tablespace.test"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlTablespaceDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_tablespace" {
+			continue
+		}
+
+		exists, err := checkTablespaceExists(client, rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Error checking tablespace %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("Tablespace still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlTablespaceExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		exists, err := checkTablespaceExists(client, rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Error checking tablespace %s", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("Tablespace not found")
+		}
+
+		return nil
+	}
+}
+
+func checkTablespaceExists(client *Client, tablespaceName string) (bool, error) {
+	db, err := client.Connect()
+	if err != nil {
+		return false, err
+	}
+
+	var _rez int
+	err = db.QueryRow("SELECT 1 FROM pg_catalog.pg_tablespace WHERE spcname = $1", tablespaceName).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("Error reading info about tablespace: %s", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,352 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	textSearchConfigurationNameAttr     = "name"
+	textSearchConfigurationSchemaAttr   = "schema"
+	textSearchConfigurationDatabaseAttr = "database"
+	textSearchConfigurationParserAttr   = "parser"
+	textSearchConfigurationCopyFromAttr = "copy_from"
+	textSearchConfigurationMappingAttr  = "mapping"
+
+	textSearchMappingTokenTypeAttr    = "token_type"
+	textSearchMappingDictionariesAttr = "dictionaries"
+)
+
+func resourcePostgreSQLTextSearchConfiguration() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLTextSearchConfigurationCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLTextSearchConfigurationRead),
+		Update: PGResourceFunc(resourcePostgreSQLTextSearchConfigurationUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLTextSearchConfigurationDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			textSearchConfigurationNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the text search configuration",
+			},
+			textSearchConfigurationSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the text search configuration is created",
+			},
+			textSearchConfigurationDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the text search configuration is created",
+			},
+			textSearchConfigurationParserAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ConflictsWith:    []string{textSearchConfigurationCopyFromAttr},
+				DiffSuppressFunc: textSearchNameDiffSuppressFunc,
+				Description:      "The text search parser of the configuration, defaults to the parser of copy_from or to the default parser",
+			},
+			textSearchConfigurationCopyFromAttr: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{textSearchConfigurationParserAttr},
+				Description:   "An existing text search configuration whose parser and mappings are copied at creation",
+			},
+			textSearchConfigurationMappingAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Description: "The dictionaries used for each token type, all the mappings of the configuration are managed when set",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						textSearchMappingTokenTypeAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+							Description:  "The token type of the parser (e.g.: asciiword)",
+						},
+						textSearchMappingDictionariesAttr: {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The dictionaries consulted in order for the token type",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLTextSearchConfigurationCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(textSearchConfigurationSchemaAttr).(string)
+	name := d.Get(textSearchConfigurationNameAttr).(string)
+
+	option := "PARSER = default"
+	if v, ok := d.GetOk(textSearchConfigurationParserAttr); ok {
+		option = "PARSER = " + v.(string)
+	} else if v, ok := d.GetOk(textSearchConfigurationCopyFromAttr); ok {
+		option = "COPY = " + v.(string)
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := fmt.Sprintf("CREATE TEXT SEARCH CONFIGURATION %s.%s (%s)",
+		pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(name), option,
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not create text search configuration %s: %w", name, err)
+	}
+
+	if _, ok := d.GetOk(textSearchConfigurationMappingAttr); ok {
+		if err := setTextSearchMappings(txn, d); err != nil {
+			return err
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating text search configuration: %w", err)
+	}
+
+	d.SetId(generateTextSearchID(database, schemaName, name))
+
+	return resourcePostgreSQLTextSearchConfigurationReadImpl(db, d)
+}
+
+// readTextSearchMappings returns the dictionaries of each token type of the configuration.
+func readTextSearchMappings(txn *sql.Tx, schemaName, name string) (map[string][]string, error) {
+	rows, err := txn.Query(
+		`SELECT tt.alias, m.mapdict::pg_catalog.regdictionary::text `+
+			`FROM pg_catalog.pg_ts_config c `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.cfgnamespace `+
+			`JOIN pg_catalog.pg_ts_config_map m ON m.mapcfg = c.oid `+
+			`JOIN pg_catalog.ts_token_type(c.cfgparser) tt ON tt.tokid = m.maptokentype `+
+			`WHERE n.nspname = $1 AND c.cfgname = $2 `+
+			`ORDER BY tt.alias, m.mapseqno`,
+		schemaName, name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not read text search configuration mappings: %w", err)
+	}
+	defer rows.Close()
+
+	mappings := map[string][]string{}
+	for rows.Next() {
+		var tokenType, dictionary string
+		if err := rows.Scan(&tokenType, &dictionary); err != nil {
+			return nil, fmt.Errorf("could not scan text search configuration mapping: %w", err)
+		}
+		mappings[tokenType] = append(mappings[tokenType], dictionary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return mappings, nil
+}
+
+// textSearchMappingsFromSet converts the mapping blocks to the dictionaries of each token type.
+func textSearchMappingsFromSet(set *schema.Set) map[string][]string {
+	mappings := map[string][]string{}
+	for _, raw := range set.List() {
+		mapping := raw.(map[string]interface{})
+		mappings[mapping[textSearchMappingTokenTypeAttr].(string)] = interfaceSliceToStrings(mapping[textSearchMappingDictionariesAttr].([]interface{}))
+	}
+	return mappings
+}
+
+func textSearchDictionariesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if normalizeTextSearchName(a[i]) != normalizeTextSearchName(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// setTextSearchMappings computes the difference between the current and the configured mappings
+// for each token type and adds, alters or drops their mapping accordingly.
+func setTextSearchMappings(txn *sql.Tx, d *schema.ResourceData) error {
+	schemaName := d.Get(textSearchConfigurationSchemaAttr).(string)
+	name := d.Get(textSearchConfigurationNameAttr).(string)
+	configuration := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(name))
+
+	current, err := readTextSearchMappings(txn, schemaName, name)
+	if err != nil {
+		return err
+	}
+	desired := textSearchMappingsFromSet(d.Get(textSearchConfigurationMappingAttr).(*schema.Set))
+
+	tokenTypes := make([]string, 0, len(current)+len(desired))
+	for tokenType := range current {
+		tokenTypes = append(tokenTypes, tokenType)
+	}
+	for tokenType := range desired {
+		if _, ok := current[tokenType]; !ok {
+			tokenTypes = append(tokenTypes, tokenType)
+		}
+	}
+	sort.Strings(tokenTypes)
+
+	for _, tokenType := range tokenTypes {
+		currentDictionaries, isMapped := current[tokenType]
+		dictionaries, isDesired := desired[tokenType]
+
+		var query string
+		switch {
+		case isMapped && !isDesired:
+			query = fmt.Sprintf("ALTER TEXT SEARCH CONFIGURATION %s DROP MAPPING FOR %s", configuration, pq.QuoteIdentifier(tokenType))
+		case !isMapped:
+			query = fmt.Sprintf("ALTER TEXT SEARCH CONFIGURATION %s ADD MAPPING FOR %s WITH %s", configuration, pq.QuoteIdentifier(tokenType), strings.Join(dictionaries, ", "))
+		case !textSearchDictionariesEqual(currentDictionaries, dictionaries):
+			query = fmt.Sprintf("ALTER TEXT SEARCH CONFIGURATION %s ALTER MAPPING FOR %s WITH %s", configuration, pq.QuoteIdentifier(tokenType), strings.Join(dictionaries, ", "))
+		default:
+			continue
+		}
+
+		if _, err := txn.Exec(query); err != nil {
+			return fmt.Errorf("could not set mapping of token type %s: %w", tokenType, err)
+		}
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLTextSearchConfigurationRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLTextSearchConfigurationReadImpl(db, d)
+}
+
+func resourcePostgreSQLTextSearchConfigurationReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, name, err := getDBTextSearchName(d, db.client, textSearchConfigurationNameAttr, textSearchConfigurationSchemaAttr)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var parser string
+	err = txn.QueryRow(
+		`SELECT CASE WHEN pn.nspname IN ('pg_catalog', 'public') THEN p.prsname `+
+			`ELSE pg_catalog.quote_ident(pn.nspname) || '.' || pg_catalog.quote_ident(p.prsname) END `+
+			`FROM pg_catalog.pg_ts_config c `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.cfgnamespace `+
+			`JOIN pg_catalog.pg_ts_parser p ON p.oid = c.cfgparser `+
+			`JOIN pg_catalog.pg_namespace pn ON pn.oid = p.prsnamespace `+
+			`WHERE n.nspname = $1 AND c.cfgname = $2`,
+		schemaName, name,
+	).Scan(&parser)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL text search configuration (%s.%s) not found in database %s", schemaName, name, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading text search configuration: %w", err)
+	}
+
+	mappings, err := readTextSearchMappings(txn, schemaName, name)
+	if err != nil {
+		return err
+	}
+
+	// The dictionaries are kept as configured when they only differ by their schema
+	configured := textSearchMappingsFromSet(d.Get(textSearchConfigurationMappingAttr).(*schema.Set))
+	mapping := make([]interface{}, 0, len(mappings))
+	for tokenType, dictionaries := range mappings {
+		if configuredDictionaries, ok := configured[tokenType]; ok && textSearchDictionariesEqual(configuredDictionaries, dictionaries) {
+			dictionaries = configuredDictionaries
+		}
+		mapping = append(mapping, map[string]interface{}{
+			textSearchMappingTokenTypeAttr:    tokenType,
+			textSearchMappingDictionariesAttr: dictionaries,
+		})
+	}
+
+	d.Set(textSearchConfigurationNameAttr, name)
+	d.Set(textSearchConfigurationSchemaAttr, schemaName)
+	d.Set(textSearchConfigurationDatabaseAttr, database)
+	d.Set(textSearchConfigurationParserAttr, parser)
+	d.Set(textSearchConfigurationMappingAttr, mapping)
+	d.SetId(generateTextSearchID(database, schemaName, name))
+
+	return nil
+}
+
+func resourcePostgreSQLTextSearchConfigurationUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(textSearchConfigurationMappingAttr) {
+		return resourcePostgreSQLTextSearchConfigurationReadImpl(db, d)
+	}
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := setTextSearchMappings(txn, d); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating text search configuration: %w", err)
+	}
+
+	return resourcePostgreSQLTextSearchConfigurationReadImpl(db, d)
+}
+
+func resourcePostgreSQLTextSearchConfigurationDelete(db *DBConnection, d *schema.ResourceData) error {
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := fmt.Sprintf("DROP TEXT SEARCH CONFIGURATION %s.%s",
+		pq.QuoteIdentifier(d.Get(textSearchConfigurationSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(textSearchConfigurationNameAttr).(string)),
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not drop text search configuration: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting text search configuration: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestNormalizeTextSearchName(t *testing.T) {
+	var cases = []struct {
+		name     string
+		expected string
+	}{
+		{name: "english_stem", expected: "english_stem"},
+		{name: "pg_catalog.english_stem", expected: "english_stem"},
+		{name: "Public.My_Dict", expected: "my_dict"},
+		{name: "search.my_dict", expected: "search.my_dict"},
+	}
+
+	for _, c := range cases {
+		if out := normalizeTextSearchName(c.name); out != c.expected {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.expected)
+		}
+	}
+}
+
+func TestAccPostgresqlTextSearchConfiguration_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_text_search_dictionary" "stopwords" {
+  database = "%[1]s"
+  name     = "test_stopwords"
+  template = "simple"
+  options  = {
+    stopwords = "english"
+    accept    = "false"
+  }
+}
+
+resource "postgresql_text_search_configuration" "test" {
+  database = "%[1]s"
+  name     = "test_config"
+  parser   = "default"
+
+  mapping {
+    token_type   = "asciiword"
+    dictionaries = [%[2]s]
+  }
+
+  mapping {
+    token_type   = "word"
+    dictionaries = ["simple"]
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlTextSearchDestroy("postgresql_text_search_configuration", "pg_ts_config", "cfgname"),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, `postgresql_text_search_dictionary.stopwords.name, "english_stem"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTextSearchExists("postgresql_text_search_configuration.test", "pg_ts_config", "cfgname"),
+					resource.TestCheckResourceAttr("postgresql_text_search_configuration.test", "parser", "default"),
+					resource.TestCheckResourceAttr("postgresql_text_search_configuration.test", "mapping.#", "2"),
+					testAccCheckTextSearchVector(dbName, "the quick foxes", "'foxes':3 'quick':2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, `"pg_catalog.english_stem"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_text_search_configuration.test", "mapping.#", "2"),
+					testAccCheckTextSearchVector(dbName, "the quick foxes", "'fox':3 'quick':2"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_text_search_configuration.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.public.test_config", dbName),
+				ImportStateVerify: true,
+				// The dictionaries are imported as displayed by Postgres
+				ImportStateVerifyIgnore: []string{"mapping"},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlTextSearchConfiguration_CopyFrom(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlTextSearchDestroy("postgresql_text_search_configuration", "pg_ts_config", "cfgname"),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_text_search_configuration" "test" {
+  database  = "%s"
+  name      = "test_config"
+  copy_from = "pg_catalog.english"
+}
+`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_text_search_configuration.test", "parser", "default"),
+					resource.TestCheckResourceAttrSet("postgresql_text_search_configuration.test", "mapping.#"),
+					testAccCheckTextSearchVector(dbName, "the quick foxes", "'fox':3 'quick':2"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckTextSearchVector checks the tsvector of the text with the test configuration.
+func testAccCheckTextSearchVector(dbName, text, expected string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var vector string
+		if err := txn.QueryRow("SELECT to_tsvector('public.test_config', $1)::text", text).Scan(&vector); err != nil {
+			return fmt.Errorf("could not compute tsvector: %w", err)
+		}
+		if vector != expected {
+			return fmt.Errorf("expected tsvector of %q to be %q, got %q", text, expected, vector)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,314 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	textSearchDictionaryNameAttr     = "name"
+	textSearchDictionarySchemaAttr   = "schema"
+	textSearchDictionaryDatabaseAttr = "database"
+	textSearchDictionaryTemplateAttr = "template"
+	textSearchDictionaryOptionsAttr  = "options"
+)
+
+func resourcePostgreSQLTextSearchDictionary() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLTextSearchDictionaryCreate),
+		Read:   PGResourceFunc(resourcePostgreSQLTextSearchDictionaryRead),
+		Update: PGResourceFunc(resourcePostgreSQLTextSearchDictionaryUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLTextSearchDictionaryDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			textSearchDictionaryNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the text search dictionary",
+			},
+			textSearchDictionarySchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema where the text search dictionary is created",
+			},
+			textSearchDictionaryDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database where the text search dictionary is created",
+			},
+			textSearchDictionaryTemplateAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: textSearchNameDiffSuppressFunc,
+				Description:      "The text search template of the dictionary (e.g.: simple, synonym, snowball)",
+			},
+			textSearchDictionaryOptionsAttr: {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The options of the template (e.g.: stopwords, synonyms)",
+			},
+		},
+	}
+}
+
+// normalizeTextSearchName returns the name of a text search object as displayed by Postgres
+// when pg_catalog and public are in the search path, so it can be compared with the catalog.
+func normalizeTextSearchName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, prefix := range []string{"pg_catalog.", "public."} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+func textSearchNameDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeTextSearchName(old) == normalizeTextSearchName(new)
+}
+
+// textSearchOptionsToSQL formats the options of a text search dictionary to be used in the
+// CREATE or ALTER statement (e.g.: stopwords = 'english', accept = 'false')
+func textSearchOptionsToSQL(options map[string]interface{}) []string {
+	keys := make([]string, 0, len(options))
+	for k := range options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s = %s", pq.QuoteIdentifier(k), pq.QuoteLiteral(options[k].(string)))
+	}
+	return parts
+}
+
+// parseTextSearchOptions parses the dictinitoption column of pg_ts_dict
+// (e.g.: stopwords = 'english', "MaxLen" = 10)
+func parseTextSearchOptions(options string) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	var key string
+	var token strings.Builder
+	inKey, quoted := true, false
+	runes := []rune(options)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'' || (r == '"' && inKey):
+			// Quotes are escaped by doubling them
+			if quoted && i+1 < len(runes) && runes[i+1] == r {
+				token.WriteRune(r)
+				i++
+				continue
+			}
+			quoted = !quoted
+		case quoted:
+			token.WriteRune(r)
+		case r == '=' && inKey:
+			key = strings.TrimSpace(token.String())
+			token.Reset()
+			inKey = false
+		case r == ',' && !inKey:
+			result[key] = strings.TrimSpace(token.String())
+			token.Reset()
+			inKey = true
+		case r == ' ':
+			continue
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if !inKey {
+		result[key] = strings.TrimSpace(token.String())
+	}
+
+	return result
+}
+
+func resourcePostgreSQLTextSearchDictionaryCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(textSearchDictionarySchemaAttr).(string)
+	name := d.Get(textSearchDictionaryNameAttr).(string)
+
+	options := append(
+		[]string{"TEMPLATE = " + d.Get(textSearchDictionaryTemplateAttr).(string)},
+		textSearchOptionsToSQL(d.Get(textSearchDictionaryOptionsAttr).(map[string]interface{}))...,
+	)
+	query := fmt.Sprintf("CREATE TEXT SEARCH DICTIONARY %s.%s (%s)",
+		pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(name), strings.Join(options, ", "),
+	)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not create text search dictionary %s: %w", name, err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating text search dictionary: %w", err)
+	}
+
+	d.SetId(generateTextSearchID(database, schemaName, name))
+
+	return resourcePostgreSQLTextSearchDictionaryReadImpl(db, d)
+}
+
+func resourcePostgreSQLTextSearchDictionaryRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLTextSearchDictionaryReadImpl(db, d)
+}
+
+func resourcePostgreSQLTextSearchDictionaryReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, name, err := getDBTextSearchName(d, db.client, textSearchDictionaryNameAttr, textSearchDictionarySchemaAttr)
+	if err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var template, options string
+	err = txn.QueryRow(
+		`SELECT CASE WHEN tn.nspname IN ('pg_catalog', 'public') THEN t.tmplname `+
+			`ELSE pg_catalog.quote_ident(tn.nspname) || '.' || pg_catalog.quote_ident(t.tmplname) END, `+
+			`COALESCE(d.dictinitoption, '') `+
+			`FROM pg_catalog.pg_ts_dict d `+
+			`JOIN pg_catalog.pg_namespace n ON n.oid = d.dictnamespace `+
+			`JOIN pg_catalog.pg_ts_template t ON t.oid = d.dicttemplate `+
+			`JOIN pg_catalog.pg_namespace tn ON tn.oid = t.tmplnamespace `+
+			`WHERE n.nspname = $1 AND d.dictname = $2`,
+		schemaName, name,
+	).Scan(&template, &options)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL text search dictionary (%s.%s) not found in database %s", schemaName, name, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading text search dictionary: %w", err)
+	}
+
+	d.Set(textSearchDictionaryNameAttr, name)
+	d.Set(textSearchDictionarySchemaAttr, schemaName)
+	d.Set(textSearchDictionaryDatabaseAttr, database)
+	d.Set(textSearchDictionaryTemplateAttr, template)
+	d.Set(textSearchDictionaryOptionsAttr, parseTextSearchOptions(options))
+	d.SetId(generateTextSearchID(database, schemaName, name))
+
+	return nil
+}
+
+func resourcePostgreSQLTextSearchDictionaryUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(textSearchDictionaryOptionsAttr) {
+		return resourcePostgreSQLTextSearchDictionaryReadImpl(db, d)
+	}
+
+	oraw, nraw := d.GetChange(textSearchDictionaryOptionsAttr)
+	oldOptions := oraw.(map[string]interface{})
+	newOptions := nraw.(map[string]interface{})
+
+	// An option without value is removed from the dictionary
+	options := textSearchOptionsToSQL(newOptions)
+	for k := range oldOptions {
+		if _, ok := newOptions[k]; !ok {
+			options = append(options, pq.QuoteIdentifier(k))
+		}
+	}
+	if len(options) == 0 {
+		return resourcePostgreSQLTextSearchDictionaryReadImpl(db, d)
+	}
+
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := fmt.Sprintf("ALTER TEXT SEARCH DICTIONARY %s.%s (%s)",
+		pq.QuoteIdentifier(d.Get(textSearchDictionarySchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(textSearchDictionaryNameAttr).(string)),
+		strings.Join(options, ", "),
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not alter text search dictionary options: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error updating text search dictionary: %w", err)
+	}
+
+	return resourcePostgreSQLTextSearchDictionaryReadImpl(db, d)
+}
+
+func resourcePostgreSQLTextSearchDictionaryDelete(db *DBConnection, d *schema.ResourceData) error {
+	txn, err := startTransaction(db.client, getDatabase(d, db.client.databaseName))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := fmt.Sprintf("DROP TEXT SEARCH DICTIONARY %s.%s",
+		pq.QuoteIdentifier(d.Get(textSearchDictionarySchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(textSearchDictionaryNameAttr).(string)),
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not drop text search dictionary: %w", err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("Error deleting text search dictionary: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func generateTextSearchID(database, schemaName, name string) string {
+	return joinResourceID(database, schemaName, name)
+}
+
+// getDBTextSearchName returns database, schema and name of a text search dictionary or configuration.
+// If we are importing this resource, they will be parsed from the resource ID (it will return an error
+// if parsing failed) otherwise they will be simply get from the state.
+func getDBTextSearchName(d *schema.ResourceData, client *Client, nameAttr, schemaAttr string) (string, string, string, error) {
+	database := getDatabase(d, client.databaseName)
+	schemaName := d.Get(schemaAttr).(string)
+	name := d.Get(nameAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and names.
+	if name == "" {
+		parsed := splitResourceID(d.Id())
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("text search ID %s has not the expected format 'database.schema.name': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		name = parsed[2]
+	}
+	return database, schemaName, name, nil
+}
@@ -0,0 +1,155 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseTextSearchOptions(t *testing.T) {
+	var cases = []struct {
+		options  string
+		expected map[string]interface{}
+	}{
+		{
+			options:  "",
+			expected: map[string]interface{}{},
+		},
+		{
+			options:  "stopwords = 'english', accept = 'false'",
+			expected: map[string]interface{}{"stopwords": "english", "accept": "false"},
+		},
+		{
+			options:  `"MaxLen" = 10, dictfile = 'it''s'`,
+			expected: map[string]interface{}{"MaxLen": "10", "dictfile": "it's"},
+		},
+	}
+
+	for _, c := range cases {
+		if out := parseTextSearchOptions(c.options); !reflect.DeepEqual(out, c.expected) {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.expected)
+		}
+	}
+}
+
+func TestAccPostgresqlTextSearchDictionary_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_text_search_dictionary" "test" {
+  database = "%s"
+  name     = "test_dict"
+  template = "pg_catalog.simple"
+  options  = {
+    %s
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlTextSearchDestroy("postgresql_text_search_dictionary", "pg_ts_dict", "dictname"),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, `stopwords = "english"
+    accept    = "false"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTextSearchExists("postgresql_text_search_dictionary.test", "pg_ts_dict", "dictname"),
+					resource.TestCheckResourceAttr("postgresql_text_search_dictionary.test", "template", "simple"),
+					resource.TestCheckResourceAttr("postgresql_text_search_dictionary.test", "options.%", "2"),
+					resource.TestCheckResourceAttr("postgresql_text_search_dictionary.test", "options.stopwords", "english"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, dbName, `stopwords = "english"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_text_search_dictionary.test", "options.%", "1"),
+					resource.TestCheckResourceAttr("postgresql_text_search_dictionary.test", "options.stopwords", "english"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_text_search_dictionary.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.public.test_dict", dbName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlTextSearchDestroy(resourceType, catalog, nameColumn string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			exists, err := checkTextSearchObjectExists(rs, catalog, nameColumn)
+			if err != nil {
+				return fmt.Errorf("Error checking %s %s", resourceType, err)
+			}
+
+			if exists {
+				return fmt.Errorf("%s still exists after destroy", resourceType)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckPostgresqlTextSearchExists(n, catalog, nameColumn string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		exists, err := checkTextSearchObjectExists(rs, catalog, nameColumn)
+		if err != nil {
+			return fmt.Errorf("Error checking %s %s", n, err)
+		}
+
+		if !exists {
+			return fmt.Errorf("%s not found", n)
+		}
+
+		return nil
+	}
+}
+
+func checkTextSearchObjectExists(rs *terraform.ResourceState, catalog, nameColumn string) (bool, error) {
+	client := testAccProvider.Meta().(*Client)
+	txn, err := startTransaction(client, rs.Primary.Attributes["database"])
+	if err != nil {
+		return false, err
+	}
+	defer deferredRollback(txn)
+
+	var _rez bool
+	err = txn.QueryRow(
+		fmt.Sprintf("SELECT TRUE FROM pg_catalog.%s WHERE %s = $1", catalog, nameColumn), rs.Primary.Attributes["name"],
+	).Scan(&_rez)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
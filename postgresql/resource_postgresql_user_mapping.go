@@ -15,6 +15,8 @@ const (
 	userMappingUserNameAttr   = "user_name"
 	userMappingServerNameAttr = "server_name"
 	userMappingOptionsAttr    = "options"
+
+	userMappingPasswordOption = "password"
 )
 
 func resourcePostgreSQLUserMapping() *schema.Resource {
@@ -46,6 +48,7 @@ func resourcePostgreSQLUserMapping() *schema.Resource {
 					Type: schema.TypeString,
 				},
 				Optional:    true,
+				Sensitive:   true,
 				Description: "This clause specifies the options of the user mapping. The options typically define the actual user name and password of the mapping. Option names must be unique. The allowed option names and values are specific to the server's foreign-data wrapper",
 			},
 		},
@@ -55,8 +58,8 @@ func resourcePostgreSQLUserMapping() *schema.Resource {
 func resourcePostgreSQLUserMappingCreate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureServer) {
 		return fmt.Errorf(
-			"Foreign Server resource is not supported for this Postgres version (%s)",
-			db.version,
+			"Foreign Server resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -68,17 +71,7 @@ func resourcePostgreSQLUserMappingCreate(db *DBConnection, d *schema.ResourceDat
 	fmt.Fprint(b, " SERVER ", pq.QuoteIdentifier(serverName))
 
 	if options, ok := d.GetOk(userMappingOptionsAttr); ok {
-		fmt.Fprint(b, " OPTIONS ( ")
-		cnt := 0
-		len := len(options.(map[string]interface{}))
-		for k, v := range options.(map[string]interface{}) {
-			fmt.Fprint(b, " ", pq.QuoteIdentifier(k), " ", pq.QuoteLiteral(v.(string)))
-			if cnt < len-1 {
-				fmt.Fprint(b, ", ")
-			}
-			cnt++
-		}
-		fmt.Fprint(b, " ) ")
+		fmt.Fprintf(b, " OPTIONS (%s)", foreignOptionsToSQL(options.(map[string]interface{})))
 	}
 
 	if _, err := db.Exec(b.String()); err != nil {
@@ -93,8 +86,8 @@ func resourcePostgreSQLUserMappingCreate(db *DBConnection, d *schema.ResourceDat
 func resourcePostgreSQLUserMappingRead(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureServer) {
 		return fmt.Errorf(
-			"Foreign Server resource is not supported for this Postgres version (%s)",
-			db.version,
+			"Foreign Server resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -111,9 +104,7 @@ func resourcePostgreSQLUserMappingReadImpl(db *DBConnection, d *schema.ResourceD
 	}
 	defer deferredRollback(txn)
 
-	var userMappingOptions []string
-	query := "SELECT umoptions FROM information_schema._pg_user_mappings WHERE authorization_identifier = $1 and foreign_server_name = $2"
-	err = txn.QueryRow(query, username, serverName).Scan(pq.Array(&userMappingOptions))
+	userMappingOptions, err := getUserMappingOptions(txn, username, serverName)
 	switch {
 	case err == sql.ErrNoRows:
 		log.Printf("[WARN] PostgreSQL user mapping (%s) for server (%s) not found", username, serverName)
@@ -123,15 +114,25 @@ func resourcePostgreSQLUserMappingReadImpl(db *DBConnection, d *schema.ResourceD
 		return fmt.Errorf("Error reading user mapping: %w", err)
 	}
 
-	mappedOptions := make(map[string]interface{})
-	for _, v := range userMappingOptions {
-		pair := strings.SplitN(v, "=", 2)
-		mappedOptions[pair[0]] = pair[1]
+	// The options are not readable if the current user is not the mapped user, the server owner or a superuser,
+	// in this case we keep the options of the state.
+	if userMappingOptions != nil {
+		stateOptions := d.Get(userMappingOptionsAttr).(map[string]interface{})
+		mappedOptions := parseForeignOptions(userMappingOptions)
+
+		// The password is never read back from the database, the configured value is kept in the state
+		if _, ok := mappedOptions[userMappingPasswordOption]; ok {
+			if password, ok := stateOptions[userMappingPasswordOption]; ok {
+				mappedOptions[userMappingPasswordOption] = password
+			} else {
+				delete(mappedOptions, userMappingPasswordOption)
+			}
+		}
+		d.Set(userMappingOptionsAttr, mappedOptions)
 	}
 
 	d.Set(userMappingUserNameAttr, username)
 	d.Set(userMappingServerNameAttr, serverName)
-	d.Set(userMappingOptionsAttr, mappedOptions)
 	d.SetId(generateUserMappingID(d))
 
 	return nil
@@ -140,8 +141,8 @@ func resourcePostgreSQLUserMappingReadImpl(db *DBConnection, d *schema.ResourceD
 func resourcePostgreSQLUserMappingDelete(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureServer) {
 		return fmt.Errorf(
-			"Foreign Server resource is not supported for this Postgres version (%s)",
-			db.version,
+			"Foreign Server resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -171,8 +172,8 @@ func resourcePostgreSQLUserMappingDelete(db *DBConnection, d *schema.ResourceDat
 func resourcePostgreSQLUserMappingUpdate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureServer) {
 		return fmt.Errorf(
-			"Foreign Server resource is not supported for this Postgres version (%s)",
-			db.version,
+			"Foreign Server resource is not supported %s",
+			db.unsupportedServer(),
 		)
 	}
 
@@ -191,44 +192,42 @@ func setUserMappingOptionsIfChanged(db *DBConnection, d *schema.ResourceData) er
 	username := d.Get(userMappingUserNameAttr).(string)
 	serverName := d.Get(userMappingServerNameAttr).(string)
 
-	b := bytes.NewBufferString("ALTER USER MAPPING ")
-	fmt.Fprintf(b, " FOR %s SERVER %s ", pq.QuoteIdentifier(username), pq.QuoteIdentifier(serverName))
-
 	oldOptions, newOptions := d.GetChange(userMappingOptionsAttr)
-	fmt.Fprint(b, " OPTIONS ( ")
-	cnt := 0
-	len := len(newOptions.(map[string]interface{}))
-	toRemove := oldOptions.(map[string]interface{})
-	for k, v := range newOptions.(map[string]interface{}) {
-		operation := "ADD"
-		if oldOptions.(map[string]interface{})[k] != nil {
-			operation = "SET"
-			delete(toRemove, k)
-		}
-		fmt.Fprintf(b, " %s %s %s ", operation, pq.QuoteIdentifier(k), pq.QuoteLiteral(v.(string)))
-		if cnt < len-1 {
-			fmt.Fprint(b, ", ")
-		}
-		cnt++
-	}
 
-	for k := range toRemove {
-		if cnt != 0 { // starting with 0 means to drop all the options. Cannot start with comma
-			fmt.Fprint(b, " , ")
-		}
-		fmt.Fprintf(b, " DROP %s ", pq.QuoteIdentifier(k))
-		cnt++
+	// Compare with the current options if we can read them, as the state may not contain all of them
+	// (e.g.: the password is not imported).
+	currentOptions, err := getUserMappingOptions(db, username, serverName)
+	if err != nil {
+		return fmt.Errorf("Error reading user mapping: %w", err)
+	}
+	if currentOptions != nil {
+		oldOptions = parseForeignOptions(currentOptions)
 	}
 
-	fmt.Fprint(b, " ) ")
+	options := alterForeignOptionsToSQL(oldOptions.(map[string]interface{}), newOptions.(map[string]interface{}))
+	if options == "" {
+		return nil
+	}
 
-	if _, err := db.Exec(b.String()); err != nil {
+	sql := fmt.Sprintf("ALTER USER MAPPING FOR %s SERVER %s OPTIONS (%s)", pq.QuoteIdentifier(username), pq.QuoteIdentifier(serverName), options)
+	if _, err := db.Exec(sql); err != nil {
 		return fmt.Errorf("Error updating user mapping options: %w", err)
 	}
 
 	return nil
 }
 
+// getUserMappingOptions returns the options of a user mapping, they are nil if the mapping has no options
+// or if the current user is not allowed to read them. It returns sql.ErrNoRows if the user mapping does not exist.
+func getUserMappingOptions(db QueryAble, username, serverName string) ([]string, error) {
+	var userMappingOptions []string
+	query := "SELECT umoptions FROM pg_catalog.pg_user_mappings WHERE usename = $1 AND srvname = $2"
+	if err := db.QueryRow(query, username, serverName).Scan(pq.Array(&userMappingOptions)); err != nil {
+		return nil, err
+	}
+	return userMappingOptions, nil
+}
+
 func generateUserMappingID(d *schema.ResourceData) string {
 	return strings.Join([]string{
 		d.Get(userMappingUserNameAttr).(string),
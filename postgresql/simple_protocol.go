@@ -0,0 +1,223 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const simpleProtocolDriverName = "postgresql-proxy-simple-protocol"
+
+// simpleProtocolDriver opens connections running the queries with parameters through the simple query
+// protocol, for the poolers and proxies which do not support the extended protocol: the parameters are
+// interpolated into the query as literals, lib/pq only using the simple protocol for queries without any.
+type simpleProtocolDriver struct {
+	driver driver.Driver
+}
+
+func (d simpleProtocolDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &simpleProtocolConn{Conn: conn}, nil
+}
+
+type simpleProtocolConn struct {
+	driver.Conn
+}
+
+func (c *simpleProtocolConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	query, err := interpolateQuery(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return execer.ExecContext(ctx, query, nil)
+}
+
+func (c *simpleProtocolConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	query, err := interpolateQuery(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return queryer.QueryContext(ctx, query, nil)
+}
+
+func (c *simpleProtocolConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck
+}
+
+func (c *simpleProtocolConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// interpolateQuery replaces the $n parameters of the query by their values quoted as literals.
+// The parameters are only searched outside of the literals, quoted identifiers and comments.
+func interpolateQuery(query string, args []driver.NamedValue) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+
+	literals := make(map[int]string, len(args))
+	for _, arg := range args {
+		literal, err := quoteValue(arg.Value)
+		if err != nil {
+			return "", fmt.Errorf("could not interpolate parameter $%d: %w", arg.Ordinal, err)
+		}
+		literals[arg.Ordinal] = literal
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(query); {
+		end := i + 1
+		switch c := query[i]; {
+		case c == '\'':
+			// E'...' strings escape the quotes with backslashes
+			escapes := i > 0 && (query[i-1] == 'E' || query[i-1] == 'e')
+			end = literalEnd(query, i, escapes)
+		case c == '"':
+			end = literalEnd(query, i, false)
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			if end = strings.IndexByte(query[i:], '\n'); end < 0 {
+				end = len(query)
+			} else {
+				end += i
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			if end = strings.Index(query[i+2:], "*/"); end < 0 {
+				end = len(query)
+			} else {
+				end += i + 4
+			}
+		case c == '$':
+			digits := i + 1
+			for digits < len(query) && query[digits] >= '0' && query[digits] <= '9' {
+				digits++
+			}
+			if digits > i+1 {
+				ordinal, _ := strconv.Atoi(query[i+1 : digits])
+				literal, ok := literals[ordinal]
+				if !ok {
+					return "", fmt.Errorf("no value for parameter $%d", ordinal)
+				}
+				b.WriteString(literal)
+				i = digits
+				continue
+			}
+			end = dollarQuoteEnd(query, i)
+		}
+		b.WriteString(query[i:end])
+		i = end
+	}
+
+	return b.String(), nil
+}
+
+// literalEnd returns the index following the literal or quoted identifier starting at start,
+// the quote character being escaped by doubling it (or by a backslash when escapes is true).
+func literalEnd(query string, start int, escapes bool) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		switch {
+		case escapes && query[i] == '\\':
+			i++
+		case query[i] == quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// dollarQuoteEnd returns the index following the dollar-quoted string ($$...$$ or $tag$...$tag$)
+// starting at start, or the index following the dollar sign if it does not start one.
+func dollarQuoteEnd(query string, start int) int {
+	tagEnd := start + 1
+	for tagEnd < len(query) && (query[tagEnd] == '_' || isLetterOrDigit(query[tagEnd])) {
+		tagEnd++
+	}
+	if tagEnd >= len(query) || query[tagEnd] != '$' {
+		return start + 1
+	}
+
+	tag := query[start : tagEnd+1]
+	if end := strings.Index(query[tagEnd+1:], tag); end >= 0 {
+		return tagEnd + 1 + end + len(tag)
+	}
+	return len(query)
+}
+
+func isLetterOrDigit(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// quoteValue returns the value converted by database/sql as a SQL literal.
+func quoteValue(value driver.Value) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case int64:
+		// In parentheses so a negative number can not be parsed as a comment with a preceding minus
+		if v < 0 {
+			return "(" + strconv.FormatInt(v, 10) + ")", nil
+		}
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return pq.QuoteLiteral(strconv.FormatFloat(v, 'g', -1, 64)), nil
+		}
+		if v < 0 {
+			return "(" + strconv.FormatFloat(v, 'g', -1, 64) + ")", nil
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case string:
+		return quoteStringValue(v)
+	case []byte:
+		return quoteStringValue(string(v))
+	case time.Time:
+		return pq.QuoteLiteral(v.Format(time.RFC3339Nano)), nil
+	}
+	return "", fmt.Errorf("unsupported type %T", value)
+}
+
+// quoteStringValue quotes a string with pq.QuoteLiteral, which doubles the quotes and escapes
+// the backslashes in an E'...' literal. The NUL character can not be part of a PostgreSQL string.
+func quoteStringValue(value string) (string, error) {
+	if strings.ContainsRune(value, 0) {
+		return "", fmt.Errorf("value contains a NUL character")
+	}
+	return pq.QuoteLiteral(value), nil
+}
+
+func init() {
+	sql.Register(simpleProtocolDriverName, simpleProtocolDriver{proxyDriver{}})
+}
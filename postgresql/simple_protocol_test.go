@@ -0,0 +1,117 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteValue(t *testing.T) {
+	tests := []struct {
+		value    driver.Value
+		expected string
+	}{
+		{nil, "NULL"},
+		{true, "TRUE"},
+		{false, "FALSE"},
+		{int64(42), "42"},
+		{int64(-1), "(-1)"},
+		{1.5, "1.5"},
+		{-0.25, "(-0.25)"},
+		{math.NaN(), "'NaN'"},
+		{"public", "'public'"},
+		{"it's", "'it''s'"},
+		{"''; DROP TABLE t; --", "'''''; DROP TABLE t; --'"},
+		// pq.QuoteLiteral separates the E'' literals from what precedes them with a space
+		{`C:\path`, ` E'C:\\path'`},
+		{`\'`, ` E'\\'''`},
+		{[]byte("{a,b}"), "'{a,b}'"},
+		{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "'2024-01-02T03:04:05Z'"},
+	}
+
+	for _, test := range tests {
+		out, err := quoteValue(test.value)
+		assert.NoError(t, err, "%#v", test.value)
+		assert.Equal(t, test.expected, out, "%#v", test.value)
+	}
+
+	_, err := quoteValue("a\x00b")
+	assert.EqualError(t, err, "value contains a NUL character")
+	_, err = quoteValue([]byte("a\x00"))
+	assert.Error(t, err)
+	_, err = quoteValue(struct{}{})
+	assert.Error(t, err)
+}
+
+func TestInterpolateQuery(t *testing.T) {
+	args := func(values ...driver.Value) []driver.NamedValue {
+		named := make([]driver.NamedValue, len(values))
+		for i, value := range values {
+			named[i] = driver.NamedValue{Ordinal: i + 1, Value: value}
+		}
+		return named
+	}
+
+	tests := []struct {
+		query    string
+		args     []driver.NamedValue
+		expected string
+	}{
+		{
+			// The read query of postgresql_comment
+			query:    "SELECT d.description FROM pg_catalog.pg_class o LEFT JOIN pg_catalog.pg_description d ON d.objoid = o.oid AND d.classoid = $1::regclass AND d.objsubid = 0 WHERE o.relname = $2",
+			args:     args("pg_catalog.pg_class", "it's"),
+			expected: "SELECT d.description FROM pg_catalog.pg_class o LEFT JOIN pg_catalog.pg_description d ON d.objoid = o.oid AND d.classoid = 'pg_catalog.pg_class'::regclass AND d.objsubid = 0 WHERE o.relname = 'it''s'",
+		},
+		{
+			query:    "SELECT $1, $10, $2",
+			args:     args("a", "b", "c", "d", "e", "f", "g", "h", "i", "j"),
+			expected: "SELECT 'a', 'j', 'b'",
+		},
+		{
+			// The parameters in literals, quoted identifiers, comments and dollar quotes are not replaced
+			query:    `SELECT '$1', E'\'$1', "$1", $$ $1 $$, $tag$ $1 $tag$ /* $1 */ FROM t WHERE a = $1 -- $1`,
+			args:     args(int64(-3)),
+			expected: `SELECT '$1', E'\'$1', "$1", $$ $1 $$, $tag$ $1 $tag$ /* $1 */ FROM t WHERE a = (-3) -- $1`,
+		},
+		{
+			query:    `SELECT $1`,
+			args:     args(`back\slash`),
+			expected: `SELECT  E'back\\slash'`,
+		},
+		{
+			query:    "SELECT 1",
+			args:     nil,
+			expected: "SELECT 1",
+		},
+	}
+
+	for _, test := range tests {
+		out, err := interpolateQuery(test.query, test.args)
+		assert.NoError(t, err, test.query)
+		assert.Equal(t, test.expected, out)
+	}
+
+	_, err := interpolateQuery("SELECT $1, $2", args("a"))
+	assert.EqualError(t, err, "no value for parameter $2")
+
+	_, err = interpolateQuery("SELECT $1", args("a\x00"))
+	assert.EqualError(t, err, "could not interpolate parameter $1: value contains a NUL character")
+}
+
+func TestSimpleProtocolConn(t *testing.T) {
+	recorder := &recordingConn{}
+	conn := &simpleProtocolConn{Conn: recorder}
+
+	_, err := conn.ExecContext(context.Background(), "COMMENT ON ROLE r IS $1", []driver.NamedValue{{Ordinal: 1, Value: "o'k"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"COMMENT ON ROLE r IS 'o''k'"}, recorder.statements)
+
+	_, err = conn.ExecContext(context.Background(), "SELECT $1", []driver.NamedValue{{Ordinal: 1, Value: "\x00"}})
+	assert.Error(t, err)
+	assert.Len(t, recorder.statements, 1, "a statement whose parameters can not be quoted must not be executed")
+}
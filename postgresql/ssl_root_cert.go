@@ -0,0 +1,211 @@
+package postgresql
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// sslRootCertPoolParam is the parameter of the connection strings naming a pool of root certificates
+// registered with registerSSLRootCertPool. lib/pq only reads the root certificates from a file, so the
+// provider drivers negotiate SSL themselves with the pool and remove the parameter.
+const sslRootCertPoolParam = "sslrootcert_pool"
+
+var (
+	sslRootCertPools     = map[string]*x509.CertPool{}
+	sslRootCertPoolsLock sync.RWMutex
+)
+
+// registerSSLRootCertPool registers the pool of the PEM encoded root certificates and returns its name,
+// the hash of the content, so the connection strings stay the same for the same certificates.
+func registerSSLRootCertPool(content string) (string, error) {
+	pool, err := parseCertificateBundle(content)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	name := hex.EncodeToString(sum[:])
+
+	sslRootCertPoolsLock.Lock()
+	defer sslRootCertPoolsLock.Unlock()
+	sslRootCertPools[name] = pool
+
+	return name, nil
+}
+
+// sslDialer negotiates SSL as lib/pq does on the connections it dials,
+// verifying the certificates of the servers with a registered pool of root certificates.
+type sslDialer struct {
+	pq.Dialer
+	sslmode    string
+	pool       *x509.CertPool
+	clientCert *tls.Certificate
+}
+
+// sslRootCertPoolDialer returns the dialer and connection string to open dsn with: if it names a pool of root
+// certificates, lib/pq connects without SSL through a dialer negotiating it with the pool.
+func sslRootCertPoolDialer(dialer pq.Dialer, dsn string) (pq.Dialer, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" {
+		return dialer, dsn, nil
+	}
+	query := u.Query()
+	name := query.Get(sslRootCertPoolParam)
+	if name == "" {
+		return dialer, dsn, nil
+	}
+	query.Del(sslRootCertPoolParam)
+
+	sslmode := query.Get("sslmode")
+	if sslmode == "disable" {
+		u.RawQuery = query.Encode()
+		return dialer, u.String(), nil
+	}
+
+	sslRootCertPoolsLock.RLock()
+	pool, found := sslRootCertPools[name]
+	sslRootCertPoolsLock.RUnlock()
+	if !found {
+		return nil, "", fmt.Errorf("unknown pool of root certificates %s", name)
+	}
+
+	ssl := &sslDialer{Dialer: dialer, sslmode: sslmode, pool: pool}
+	if certPath := query.Get("sslcert"); certPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, query.Get("sslkey"))
+		if err != nil {
+			return nil, "", fmt.Errorf("could not load the client certificate: %w", err)
+		}
+		ssl.clientCert = &cert
+	}
+
+	for _, param := range []string{"sslcert", "sslkey", "sslrootcert"} {
+		query.Del(param)
+	}
+	query.Set("sslmode", "disable")
+	u.RawQuery = query.Encode()
+
+	return ssl, u.String(), nil
+}
+
+func (d *sslDialer) Dial(network, address string) (net.Conn, error) {
+	conn, err := d.Dialer.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return d.negotiateSSL(conn, address)
+}
+
+func (d *sslDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	conn, err := d.Dialer.DialTimeout(network, address, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// The timeout covers the SSL negotiation too, lib/pq sets its own deadline for the startup
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	sslConn, err := d.negotiateSSL(conn, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := sslConn.SetDeadline(time.Time{}); err != nil {
+		sslConn.Close()
+		return nil, err
+	}
+	return sslConn, nil
+}
+
+// negotiateSSL sends the SSLRequest message of the PostgreSQL protocol on conn
+// and returns the TLS connection once the server has accepted it.
+func (d *sslDialer) negotiateSSL(conn net.Conn, address string) (net.Conn, error) {
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], 80877103)
+	if _, err := conn.Write(request); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	response := make([]byte, 1)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if response[0] != 'S' {
+		conn.Close()
+		return nil, pq.ErrSSLNotSupported
+	}
+
+	sslConn := tls.Client(conn, d.tlsConfig(address))
+	if err := sslConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return sslConn, nil
+}
+
+// tlsConfig returns the TLS configuration lib/pq would use with a root certificate file:
+// verify-full checks the host name, the other modes the certificate authority only.
+func (d *sslDialer) tlsConfig(address string) *tls.Config {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+
+	config := &tls.Config{
+		RootCAs:       d.pool,
+		ServerName:    host,
+		Renegotiation: tls.RenegotiateFreelyAsClient,
+	}
+	if d.clientCert != nil {
+		config.Certificates = []tls.Certificate{*d.clientCert}
+	}
+
+	if d.sslmode != "verify-full" {
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyCertificateAuthority(rawCerts, d.pool)
+		}
+	}
+	return config
+}
+
+// verifyCertificateAuthority verifies the certificate chain of the server against the pool, without its host name.
+func verifyCertificateAuthority(rawCerts [][]byte, pool *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("the server sent no certificate")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
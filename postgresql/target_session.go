@@ -0,0 +1,125 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// targetSessionAttrs are the values of the target_session_attrs connection parameter, as in libpq:
+// the kind of server to connect to when several hosts are given (e.g.: the primary of a cluster).
+var targetSessionAttrs = []string{"any", "read-write", "read-only", "primary", "standby", "prefer-standby"}
+
+// sessionHost is one of the hosts of a multi-host connection string.
+type sessionHost struct {
+	// address is the host:port of the server
+	address string
+	// dsn is the connection string to the host alone, as lib/pq does not support multi-host connection strings
+	dsn string
+}
+
+// splitHosts splits a connection string with several hosts (e.g.: postgres://user@host1:5432,host2:5432/db)
+// in one connection string per host, without the target_session_attrs parameter which is returned.
+func splitHosts(dsn string) ([]sessionHost, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" {
+		// Not an URL, lib/pq reports the errors of the connection strings
+		return []sessionHost{{dsn: dsn}}, "any", nil
+	}
+
+	query := u.Query()
+	attrs := query.Get("target_session_attrs")
+	if attrs == "" {
+		attrs = "any"
+	}
+	if !sliceContainsStr(targetSessionAttrs, attrs) {
+		return nil, "", fmt.Errorf("invalid target_session_attrs %q, expected one of: %s", attrs, strings.Join(targetSessionAttrs, ", "))
+	}
+	query.Del("target_session_attrs")
+
+	var hosts []sessionHost
+	for _, address := range strings.Split(u.Host, ",") {
+		hostURL := *u
+		hostURL.Host = address
+		hostURL.RawQuery = query.Encode()
+		hosts = append(hosts, sessionHost{address: address, dsn: hostURL.String()})
+	}
+
+	return hosts, attrs, nil
+}
+
+// openTargetSession connects to the hosts in order and returns the connection to the first one
+// matching attrs. With prefer-standby, any server is accepted if none of them is a standby.
+func openTargetSession(open func(string) (driver.Conn, error), hosts []sessionHost, attrs string) (driver.Conn, error) {
+	passes := []string{attrs}
+	if attrs == "prefer-standby" {
+		passes = []string{"standby", "any"}
+	}
+
+	var errs []string
+	for _, want := range passes {
+		for _, host := range hosts {
+			conn, err := open(host.dsn)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", host.address, err))
+				continue
+			}
+
+			matches, err := sessionMatches(conn, want)
+			if err == nil && matches {
+				return conn, nil
+			}
+			conn.Close()
+
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: could not check the session: %v", host.address, err))
+			} else {
+				errs = append(errs, fmt.Sprintf("%s: not %s", host.address, want))
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("could not connect to a server with target_session_attrs=%s: %s", attrs, strings.Join(errs, "; "))
+}
+
+// sessionMatches checks the server of conn is the kind of server wanted.
+func sessionMatches(conn driver.Conn, want string) (bool, error) {
+	if want == "any" {
+		return true, nil
+	}
+
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return false, fmt.Errorf("the connection does not support queries")
+	}
+	rows, err := queryer.QueryContext(
+		context.Background(),
+		"SELECT pg_catalog.pg_is_in_recovery(), pg_catalog.current_setting('transaction_read_only') = 'on'",
+		nil,
+	)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 2)
+	if err := rows.Next(values); err != nil {
+		return false, err
+	}
+	inRecovery, _ := values[0].(bool)
+	readOnly, _ := values[1].(bool)
+
+	switch want {
+	case "read-write":
+		return !readOnly, nil
+	case "read-only":
+		return readOnly, nil
+	case "primary":
+		return !inRecovery, nil
+	case "standby":
+		return inRecovery, nil
+	}
+	return false, fmt.Errorf("unknown target_session_attrs %q", want)
+}
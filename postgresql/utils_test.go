@@ -6,10 +6,13 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/blang/semver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 const (
@@ -18,16 +21,69 @@ const (
 	testRolePassword = "testpwd"
 )
 
-// Can be used in a PreCheck function to disable test based on feature.
-func testCheckCompatibleVersion(t *testing.T, feature featureName) {
+// testServer is the server the acceptance tests run against. Its features are queried once,
+// the version guards of the tests following the server of each run of the version matrix
+// (see tests/testacc_matrix.sh).
+type testServer struct {
+	version  semver.Version
+	flavor   serverFlavor
+	features map[featureName]bool
+}
+
+var (
+	testServersLock sync.Mutex
+	testServers     = make(map[string]*testServer)
+)
+
+// getTestServer returns the server the provider of the acceptance tests is connected to.
+func getTestServer(t *testing.T) *testServer {
 	client := testAccProvider.Meta().(*Client)
+	dsn := client.config.connStr(client.databaseName)
+
+	testServersLock.Lock()
+	defer testServersLock.Unlock()
+
+	if server, ok := testServers[dsn]; ok {
+		return server
+	}
+
 	db, err := client.Connect()
 	if err != nil {
 		t.Fatalf("could connect to database: %v", err)
 	}
-	if !db.featureSupported(feature) {
-		t.Skip(fmt.Sprintf("Skip extension tests for Postgres %s", db.version))
+
+	server := &testServer{
+		version:  db.version,
+		flavor:   db.serverFlavor,
+		features: make(map[featureName]bool, len(featureNames)),
+	}
+	for feature := range featureNames {
+		server.features[feature] = db.featureSupported(feature)
 	}
+	testServers[dsn] = server
+
+	return server
+}
+
+// testCheckFeatures can be used in a PreCheck function to skip a test if the server
+// does not support all the features it needs.
+func testCheckFeatures(t *testing.T, features ...featureName) {
+	server := getTestServer(t)
+
+	var unsupported []string
+	for _, feature := range features {
+		if !server.features[feature] {
+			unsupported = append(unsupported, featureNames[feature])
+		}
+	}
+	if len(unsupported) > 0 {
+		t.Skipf("Skip test: %s %s does not support %s", flavorNames[server.flavor], server.version, strings.Join(unsupported, ", "))
+	}
+}
+
+// Can be used in a PreCheck function to disable test based on feature.
+func testCheckCompatibleVersion(t *testing.T, feature featureName) {
+	testCheckFeatures(t, feature)
 }
 
 // Some tests have to be run as a real superuser (not RDS like)
@@ -38,6 +94,59 @@ func testSuperuserPreCheck(t *testing.T) {
 	}
 }
 
+// existsQuery returns the database of the object managed by a resource and a query returning
+// whether the object still exists (no row meaning it does not).
+type existsQuery func(rs *terraform.ResourceState) (database, query string, args []interface{})
+
+// testAccCheckResourcesDestroyed can be used as CheckDestroy to check the objects managed by the
+// resources of resourceType no longer exist. The objects of a dropped database do not exist.
+func testAccCheckResourcesDestroyed(resourceType string, exists existsQuery) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			database, query, args := exists(rs)
+			if database == "" {
+				database = client.databaseName
+			}
+
+			db, err := client.Connect()
+			if err != nil {
+				return err
+			}
+			found, err := dbExists(db, database)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+
+			txn, err := startTransaction(client, database)
+			if err != nil {
+				return err
+			}
+
+			var stillExists bool
+			err = txn.QueryRow(query, args...).Scan(&stillExists)
+			deferredRollback(txn)
+			switch {
+			case err == sql.ErrNoRows:
+			case err != nil:
+				return fmt.Errorf("could not check if %s %s still exists: %w", resourceType, rs.Primary.ID, err)
+			case stillExists:
+				return fmt.Errorf("%s %s still exists after destroy", resourceType, rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
+
 func getTestConfig(t *testing.T) Config {
 	getEnv := func(key, fallback string) string {
 		value := os.Getenv(key)
@@ -0,0 +1,83 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// maxIdentifierLength is the number of bytes PostgreSQL keeps from an identifier (NAMEDATALEN - 1),
+// the longer identifiers being silently truncated.
+const maxIdentifierLength = 63
+
+// validateIdentifier checks that the value is a non empty identifier PostgreSQL will not truncate,
+// as the truncated name would not match the configured one when the object is read.
+func validateIdentifier(v interface{}, key string) (warnings []string, errors []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", key)}
+	}
+
+	if value == "" {
+		return nil, []error{fmt.Errorf("expected %s not to be an empty string", key)}
+	}
+	if len(value) > maxIdentifierLength {
+		errors = append(errors, fmt.Errorf(
+			"%s %q is %d bytes long but PostgreSQL truncates identifiers to %d bytes",
+			key, value, len(value), maxIdentifierLength,
+		))
+	}
+	return
+}
+
+// validateOptionalIdentifier is validateIdentifier for the attributes where an empty string
+// means the default value (e.g.: the database of the provider).
+func validateOptionalIdentifier(v interface{}, key string) ([]string, []error) {
+	if value, ok := v.(string); ok && value == "" {
+		return nil, nil
+	}
+	return validateIdentifier(v, key)
+}
+
+// checkPrivileges checks that the privileges are allowed for this object type.
+func checkPrivileges(objectType string, privileges []interface{}) error {
+	allowed, ok := allowedPrivileges[objectType]
+	if !ok {
+		return fmt.Errorf("unknown object type %s", objectType)
+	}
+
+	for _, priv := range privileges {
+		if !sliceContainsStr(allowed, priv.(string)) {
+			return fmt.Errorf("%s is not an allowed privilege for object type %s", priv, objectType)
+		}
+	}
+	return nil
+}
+
+// customizeDiffPrivileges checks at plan time that the privileges are allowed for the object type,
+// when both are known.
+func customizeDiffPrivileges(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("object_type") || !diff.NewValueKnown("privileges") {
+		return nil
+	}
+	return checkPrivileges(diff.Get("object_type").(string), diff.Get("privileges").(*schema.Set).List())
+}
+
+// planConnection returns a connection to check at plan time the features supported by the server,
+// whose version is expected_version if it is set or else the version detected when connecting.
+// It returns nil if the server can not be reached yet, the features being checked again when applying.
+func planConnection(meta interface{}) *DBConnection {
+	client, ok := meta.(*Client)
+	if !ok {
+		return nil
+	}
+
+	db, err := client.Connect()
+	if err != nil {
+		log.Printf("[DEBUG] Skipping the plan time checks of the Postgres version: %v", err)
+		return nil
+	}
+	return db
+}
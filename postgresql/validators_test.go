@@ -0,0 +1,65 @@
+package postgresql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIdentifier(t *testing.T) {
+	cases := map[string]struct {
+		value       interface{}
+		expectError bool
+	}{
+		"simple name":          {"my_table", false},
+		"63 bytes":             {strings.Repeat("a", 63), false},
+		"64 bytes":             {strings.Repeat("a", 64), true},
+		"32 runes of 2 bytes":  {strings.Repeat("é", 32), true},
+		"31 runes of 2 bytes":  {strings.Repeat("é", 31), false},
+		"empty":                {"", true},
+		"not a string":         {42, true},
+		"uppercase and spaces": {"My Table", false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, errs := validateIdentifier(c.value, "name")
+			assert.Equal(t, c.expectError, len(errs) > 0, "%v", errs)
+		})
+	}
+}
+
+func TestValidateOptionalIdentifier(t *testing.T) {
+	_, errs := validateOptionalIdentifier("", "schema")
+	assert.Empty(t, errs)
+
+	_, errs = validateOptionalIdentifier(strings.Repeat("a", 64), "schema")
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "truncates identifiers to 63 bytes")
+}
+
+func TestCheckPrivileges(t *testing.T) {
+	assert.NoError(t, checkPrivileges("table", []interface{}{"SELECT", "TRUNCATE"}))
+	assert.NoError(t, checkPrivileges("schema", []interface{}{"ALL"}))
+
+	err := checkPrivileges("sequence", []interface{}{"USAGE", "TRUNCATE"})
+	if assert.Error(t, err) {
+		assert.Equal(t, "TRUNCATE is not an allowed privilege for object type sequence", err.Error())
+	}
+
+	assert.Error(t, checkPrivileges("unknown", []interface{}{"ALL"}))
+}
+
+func TestValidateFeatureSupport(t *testing.T) {
+	db := &DBConnection{version: semver.MustParse("10.0.0")}
+
+	assert.NoError(t, validateFeatureSupport(db, "function"))
+	assert.Error(t, validateFeatureSupport(db, "procedure"))
+	assert.Error(t, validateFeatureSupport(db, "routine"))
+
+	db.version = semver.MustParse("11.0.0")
+	assert.NoError(t, validateFeatureSupport(db, "procedure"))
+	assert.NoError(t, validateFeatureSupport(db, "routine"))
+}
@@ -25,7 +25,7 @@ EOS
 
 export TF_ACC=true
 export PGHOST=localhost
-export PGPORT=25432
+export PGPORT=${PGPORT:-25432}
 export PGUSER=rds
 export PGPASSWORD=rds
 export PGSSLMODE=disable
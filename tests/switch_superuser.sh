@@ -2,7 +2,7 @@
 
 export TF_ACC=true
 export PGHOST=localhost
-export PGPORT=25432
+export PGPORT=${PGPORT:-25432}
 export PGUSER=postgres
 export PGPASSWORD=postgres
 export PGSSLMODE=disable
@@ -0,0 +1,18 @@
+#!/bin/bash
+set -e
+
+# Runs the full acceptance test suite against several Postgres major versions.
+# Each version runs in its own docker compose project listening on its own port,
+# so a failed run can be inspected without conflicting with the next one.
+# The versions can be set with PGVERSIONS, e.g.: PGVERSIONS="13 17" make testacc_matrix
+
+for version in ${PGVERSIONS:-12 13 14 15 16 17}; do
+    echo "####################"
+    echo "## ->  Postgres $version"
+    echo "####################"
+
+    PGVERSION=$version \
+    PGPORT=$((25400 + version)) \
+    COMPOSE_PROJECT_NAME="tf-postgresql-$version" \
+        "$(pwd)"/tests/testacc_full.sh
+done